@@ -56,6 +56,9 @@ type Config struct {
 
 	// LoopbackClientConfig is a config for a privileged loopback connection
 	LoopbackClientConfig *restclient.Config
+
+	// CacheCheckpoint is used to checkpoint and restore the binder cache.
+	CacheCheckpoint *cmdutil.CacheCheckpointOptions
 }
 
 type completedConfig struct {
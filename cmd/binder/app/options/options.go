@@ -67,6 +67,8 @@ type Options struct {
 	CombinedInsecureServing *CombinedInsecureServingOptions
 
 	VolumeBindingTimeoutSeconds int64
+
+	CacheCheckpoint *cmdutil.CacheCheckpointOptions
 }
 
 // NewOptions returns default binder app options.
@@ -89,7 +91,8 @@ func NewOptions() (*Options, error) {
 			BindPort:    hport,
 			BindAddress: hhost,
 		},
-		BinderConfig: *cfg,
+		BinderConfig:    *cfg,
+		CacheCheckpoint: cmdutil.NewCacheCheckpointOptions(),
 	}
 	return o, nil
 }
@@ -126,9 +129,11 @@ func (o *Options) Flags() (nfs cliflag.NamedFlagSets) {
 	fs.StringVar(o.BinderConfig.SchedulerName, "scheduler-name", *o.BinderConfig.SchedulerName, "components will deal with pods that pod.Spec.SchedulerName is equal to scheduler-name / is default-scheduler or empty.")
 	fs.Int64Var(&o.BinderConfig.VolumeBindingTimeoutSeconds, "volume-binding-timeout-seconds", o.BinderConfig.VolumeBindingTimeoutSeconds, "timeout for binding pod volumes")
 	fs.Int64Var(&o.BinderConfig.ReservationTimeOutSeconds, "reservation-ttl", o.BinderConfig.ReservationTimeOutSeconds, "how long resources will be reserved (for resource reservation).")
+	fs.Int32Var(o.BinderConfig.PluginMetricsSamplePercent, "plugin-metrics-sample-percent", *o.BinderConfig.PluginMetricsSamplePercent, "percentage of binding cycles in which plugin metrics are recorded, in the range [0-100].")
 
 	o.CombinedInsecureServing.AddFlags(nfs.FlagSet("insecure serving"))
 	o.BinderConfig.Tracer.AddFlags(nfs.FlagSet("tracer"))
+	o.CacheCheckpoint.AddFlags(nfs.FlagSet("cache checkpoint"))
 
 	BindFlags(&o.BinderConfig.LeaderElection, nfs.FlagSet("leader election"))
 	utilfeature.DefaultMutableFeatureGate.AddFlag(nfs.FlagSet("feature gate"))
@@ -215,6 +220,9 @@ func (o *Options) ApplyTo(c *binderappconfig.Config) error {
 			if o.BinderConfig.ReservationTimeOutSeconds != binderconfig.DefaultReservationTimeOutSeconds {
 				toUse.ReservationTimeOutSeconds = o.BinderConfig.ReservationTimeOutSeconds
 			}
+			if *o.BinderConfig.PluginMetricsSamplePercent != binderconfig.DefaultPluginMetricsSamplePercent {
+				toUse.PluginMetricsSamplePercent = o.BinderConfig.PluginMetricsSamplePercent
+			}
 		}
 		// 5. Godel Profiles (Default)
 		// nothing to overwrite in this version.
@@ -280,6 +288,7 @@ func (o *Options) Config() (*binderappconfig.Config, error) {
 	c.KatalystCrdInformerFactory = katalystinformers.NewSharedInformerFactory(c.KatalystCrdClient, 0)
 
 	c.LeaderElection = leaderElectionConfig
+	c.CacheCheckpoint = o.CacheCheckpoint
 
 	return c, nil
 }
@@ -171,6 +171,7 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 		cc.BinderConfig.VolumeBindingTimeoutSeconds,
 		time.Duration(cc.BinderConfig.ReservationTimeOutSeconds)*time.Second,
 		binder.WithPluginsAndConfigs(cc.BinderConfig.Profile),
+		binder.WithPluginMetricsSamplePercent(*cc.BinderConfig.PluginMetricsSamplePercent),
 	)
 	if err != nil {
 		return err
@@ -203,6 +204,9 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 	// Start podGroup Controllers
 	pgInformer := cc.GodelCrdInformerFactory.Scheduling().V1alpha1().PodGroups()
 
+	// Restore the binder cache before the informers deliver any event.
+	restored := cc.CacheCheckpoint.Enabled() && binder.RestoreCacheFromCheckpoint(cc.CacheCheckpoint.Path, cc.CacheCheckpoint.MaxAge)
+
 	// Start all informers.
 	cc.GodelCrdInformerFactory.Start(ctx.Done())
 	cc.InformerFactory.Start(ctx.Done())
@@ -210,10 +214,24 @@ func Run(ctx context.Context, cc binderappconfig.CompletedConfig) error {
 
 	// Wait for all caches to sync before scheduling.
 	// should wait for cache sync first.
-	cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
-	cc.InformerFactory.WaitForCacheSync(ctx.Done())
-	cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
-	cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced)
+	waitForCacheSync := func() {
+		cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
+		cc.InformerFactory.WaitForCacheSync(ctx.Done())
+		cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
+		cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced)
+	}
+	if restored {
+		// The restored cache is reconciled in background, units are queued and bound after that.
+		go func() {
+			waitForCacheSync()
+			binder.FinishCacheReconciling()
+		}()
+	} else {
+		waitForCacheSync()
+	}
+	if cc.CacheCheckpoint.Enabled() {
+		binder.RunCacheCheckpointer(cc.CacheCheckpoint.Path, cc.CacheCheckpoint.Interval, ctx.Done())
+	}
 
 	run := func(ctx context.Context) {
 		// Register the tracer when the leader is elected.
@@ -66,6 +66,8 @@ import (
 	routeutil "github.com/kubewharf/godel-scheduler/pkg/util/route"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 	"github.com/kubewharf/godel-scheduler/pkg/version/verflag"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
 )
 
 const (
@@ -75,7 +77,11 @@ const (
 	ConfigzName           = "godel-controller-manager-config"
 )
 
-var ControllersDisabledByDefault = sets.NewString()
+// ControllersDisabledByDefault holds the controllers which have to be enabled explicitly.
+var ControllersDisabledByDefault = sets.NewString(
+	"rescheduler",
+	"podgroup",
+)
 
 func NewGodelControllerCmd() *cobra.Command {
 	opts, err := options.NewGodelControllerManagerOptions()
@@ -197,6 +203,12 @@ func Run(ctx context.Context, cc *controllerappconfig.CompletedConfig) error {
 
 	clientBuilder, rootClientBuilder, godelClientBuilder := createClientBuilders(cc)
 
+	if unsecuredMux != nil {
+		if err := installWebhooks(ctx, cc, clientBuilder, unsecuredMux); err != nil {
+			return err
+		}
+	}
+
 	run := func(ctx context.Context, initializersFunc ControllerInitializersFunc) {
 		controllerContext, err := CreateControllerContext(cc, rootClientBuilder, clientBuilder, godelClientBuilder, ctx.Done())
 		if err != nil {
@@ -211,6 +223,9 @@ func Run(ctx context.Context, cc *controllerappconfig.CompletedConfig) error {
 
 		controllerContext.InformerFactory.Start(ctx.Done())
 		controllerContext.GodelInformerFactory.Start(ctx.Done())
+		if controllerContext.KatalystInformerFactory != nil {
+			controllerContext.KatalystInformerFactory.Start(ctx.Done())
+		}
 		close(controllerContext.InformersStarted)
 
 		closer := tracing.NewTracer(
@@ -324,6 +339,10 @@ type ControllerContext struct {
 	// godel Informer factory
 	GodelInformerFactory crdinformers.SharedInformerFactory
 
+	// katalyst Informer factory, gives access to CustomNodeResources.
+	// It is nil unless the rescheduler controller is enabled.
+	KatalystInformerFactory katalystinformers.SharedInformerFactory
+
 	// TODO: ObjectOrMetadataInformerFactory gives access to informers for typed resources
 	// and dynamic resources by their metadata. All generic controllers currently use
 	// object metadata - if a future controller needs access to the full object this
@@ -438,6 +457,8 @@ func NewControllerInitializers() map[string]InitFunc {
 	}
 
 	register("reservation", startReservationController)
+	register("rescheduler", startReschedulerController)
+	register("podgroup", startPodGroupController)
 
 	return controllers
 }
@@ -500,6 +521,16 @@ func CreateControllerContext(
 	sharedInformers := informers.NewSharedInformerFactory(versionedClient, ResyncPeriod(s)())
 	godelInformers := crdinformers.NewSharedInformerFactory(godelClient, ResyncPeriod(s)())
 
+	// CustomNodeResources are only consumed by the rescheduler, so avoid watching them otherwise.
+	var katalystInformers katalystinformers.SharedInformerFactory
+	if isControllerEnabled("rescheduler", ControllersDisabledByDefault, s.ComponentConfig.Generic.Controllers) {
+		katalystClient, err := katalystclient.NewForConfig(restclient.AddUserAgent(restclient.CopyConfig(s.CrdKubeconfig), "shared-informers"))
+		if err != nil {
+			return ControllerContext{}, err
+		}
+		katalystInformers = katalystinformers.NewSharedInformerFactory(katalystClient, ResyncPeriod(s)())
+	}
+
 	// If apiserver is not running we should wait for some time and fail only then. This is particularly
 	// important when we start apiserver and controller manager at the same time.
 	if err := waitForAPIServer(versionedClient, 10*time.Second); err != nil {
@@ -512,14 +543,15 @@ func CreateControllerContext(
 	}
 
 	ctx := ControllerContext{
-		ClientBuilder:        clientBuilder,
-		GodelClientBuilder:   godelClientBuilder,
-		InformerFactory:      sharedInformers,
-		GodelInformerFactory: godelInformers,
-		ComponentConfig:      s.ComponentConfig,
-		AvailableResources:   availableResources,
-		InformersStarted:     make(chan struct{}),
-		ResyncPeriod:         ResyncPeriod(s),
+		ClientBuilder:           clientBuilder,
+		GodelClientBuilder:      godelClientBuilder,
+		InformerFactory:         sharedInformers,
+		GodelInformerFactory:    godelInformers,
+		KatalystInformerFactory: katalystInformers,
+		ComponentConfig:         s.ComponentConfig,
+		AvailableResources:      availableResources,
+		InformersStarted:        make(chan struct{}),
+		ResyncPeriod:            ResyncPeriod(s),
 
 		ControllerManagerMetrics: controllersmetrics.NewControllerManagerMetrics(ComponentName),
 	}
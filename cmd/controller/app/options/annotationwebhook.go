@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+type AnnotationWebhookOptions struct {
+	*config.AnnotationWebhookConfiguration
+}
+
+func (opt *AnnotationWebhookOptions) AddFlags(fs *pflag.FlagSet) {
+	if opt == nil {
+		return
+	}
+	fs.BoolVar(&opt.Enabled, "annotation-webhook-enabled", opt.Enabled, "whether to serve the webhooks validating and defaulting the annotations of pods and PodGroups.")
+	fs.StringSliceVar(&opt.SchedulerNames, "annotation-webhook-scheduler-names", opt.SchedulerNames, "the scheduler names whose pods are validated and defaulted by the annotation webhooks.")
+	fs.StringVar(&opt.DefaultResourceType, "annotation-webhook-default-resource-type", opt.DefaultResourceType, "the resource type set on pods without resource type and qos level, guaranteed or best-effort.")
+	fs.StringVar(&opt.DefaultLauncher, "annotation-webhook-default-launcher", opt.DefaultLauncher, "the launcher set on pods without launcher, kubelet or node-manager.")
+	fs.StringSliceVar(&opt.ExtraPlugins, "annotation-webhook-extra-plugins", opt.ExtraPlugins, "the out-of-tree plugins that could be referenced in constraint annotations.")
+	fs.StringSliceVar(&opt.IgnoredNamespace, "annotation-webhook-ignored-namespace-list", opt.IgnoredNamespace, "The list of namespace whose objects will never be validated or defaulted by the annotation webhooks.")
+}
+
+func (opt *AnnotationWebhookOptions) ApplyTo(cfg *config.AnnotationWebhookConfiguration) error {
+	if opt == nil {
+		return nil
+	}
+	opt.AnnotationWebhookConfiguration.DeepCopyInto(cfg)
+	return nil
+}
+
+func (opt *AnnotationWebhookOptions) Validate() error {
+	if opt == nil {
+		return nil
+	}
+	switch podutil.PodResourceType(opt.DefaultResourceType) {
+	case podutil.GuaranteedPod, podutil.BestEffortPod:
+	default:
+		return fmt.Errorf("invalid annotation webhook default resource type %q", opt.DefaultResourceType)
+	}
+	switch podutil.PodLauncher(opt.DefaultLauncher) {
+	case podutil.Kubelet, podutil.NodeManager:
+	default:
+		return fmt.Errorf("invalid annotation webhook default launcher %q", opt.DefaultLauncher)
+	}
+	return nil
+}
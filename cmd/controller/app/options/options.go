@@ -49,6 +49,9 @@ const DefaultLeaderElectionConfig = "godel-controller-manager"
 type GodelControllerManagerOptions struct {
 	Generic               *GenericControllerManagerConfigurationOptions
 	ReservationController *ReservationControllerOptions
+	ReschedulerController *ReschedulerControllerOptions
+	PodGroupController    *PodGroupControllerOptions
+	AnnotationWebhook     *AnnotationWebhookOptions
 	Tracer                *TracerOptions
 
 	SecureServing           *apiserveroptions.SecureServingOptionsWithLoopback
@@ -92,6 +95,15 @@ func NewGodelControllerManagerOptions() (*GodelControllerManagerOptions, error)
 		ReservationController: &ReservationControllerOptions{
 			componentConfig.ReservationController,
 		},
+		ReschedulerController: &ReschedulerControllerOptions{
+			componentConfig.ReschedulerController,
+		},
+		PodGroupController: &PodGroupControllerOptions{
+			componentConfig.PodGroupController,
+		},
+		AnnotationWebhook: &AnnotationWebhookOptions{
+			componentConfig.AnnotationWebhook,
+		},
 		Tracer: &TracerOptions{
 			componentConfig.Tracer,
 		},
@@ -136,6 +148,9 @@ func (opt *GodelControllerManagerOptions) Flags(allControllers []string, disable
 
 	opt.Tracer.AddFlags(fss.FlagSet("tracer"))
 	opt.ReservationController.AddFlags(fss.FlagSet("reservation Controller"))
+	opt.ReschedulerController.AddFlags(fss.FlagSet("rescheduler Controller"))
+	opt.PodGroupController.AddFlags(fss.FlagSet("podgroup Controller"))
+	opt.AnnotationWebhook.AddFlags(fss.FlagSet("annotation webhook"))
 
 	fs := fss.FlagSet("misc")
 	fs.StringVar(&opt.Master, "master", opt.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig).")
@@ -153,6 +168,18 @@ func (opt *GodelControllerManagerOptions) ApplyTo(c *cmdconfig.Config) error {
 		return err
 	}
 
+	if err := opt.ReschedulerController.ApplyTo(c.ComponentConfig.ReschedulerController); err != nil {
+		return err
+	}
+
+	if err := opt.PodGroupController.ApplyTo(c.ComponentConfig.PodGroupController); err != nil {
+		return err
+	}
+
+	if err := opt.AnnotationWebhook.ApplyTo(c.ComponentConfig.AnnotationWebhook); err != nil {
+		return err
+	}
+
 	opt.Tracer.ApplyTo(c.ComponentConfig.Tracer)
 
 	if err := opt.SecureServing.ApplyTo(&c.SecureServing, &c.LoopbackClientConfig); err != nil {
@@ -183,6 +210,9 @@ func (opt *GodelControllerManagerOptions) Validate(allControllers []string, disa
 	errs = append(errs, opt.Authentication.Validate()...)
 	errs = append(errs, opt.Authorization.Validate()...)
 	errs = append(errs, opt.Tracer.Validate())
+	errs = append(errs, opt.ReschedulerController.Validate())
+	errs = append(errs, opt.PodGroupController.Validate())
+	errs = append(errs, opt.AnnotationWebhook.Validate())
 
 	return utilerrors.NewAggregate(errs)
 }
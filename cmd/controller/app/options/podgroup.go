@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+)
+
+type PodGroupControllerOptions struct {
+	*config.PodGroupControllerConfiguration
+}
+
+func (opt *PodGroupControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if opt == nil {
+		return
+	}
+	fs.StringSliceVar(&opt.OwnerKinds, "podgroup-owner-kinds", opt.OwnerKinds, "the owner kinds whose PodGroups are created automatically, available kinds: Job, JobSet, PyTorchJob, MPIJob, RayCluster.")
+	fs.Int32Var(&opt.DefaultScheduleTimeoutSeconds, "podgroup-default-schedule-timeout-seconds", opt.DefaultScheduleTimeoutSeconds, "the scheduleTimeoutSeconds of created PodGroups when the owner doesn't specify one, 0 means unset.")
+	fs.StringSliceVar(&opt.IgnoredNamespace, "podgroup-ignored-namespace-list", opt.IgnoredNamespace, "The list of namespace whose pods will never be mutated by the podgroup webhook.")
+}
+
+func (opt *PodGroupControllerOptions) ApplyTo(cfg *config.PodGroupControllerConfiguration) error {
+	if opt == nil {
+		return nil
+	}
+	opt.PodGroupControllerConfiguration.DeepCopyInto(cfg)
+	return nil
+}
+
+func (opt *PodGroupControllerOptions) Validate() error {
+	if opt == nil {
+		return nil
+	}
+	available := sets.NewString(config.AvailableOwnerKinds...)
+	for _, kind := range opt.OwnerKinds {
+		if !available.Has(kind) {
+			return fmt.Errorf("unknown podgroup owner kind %q, available kinds: %v", kind, config.AvailableOwnerKinds)
+		}
+	}
+	if opt.DefaultScheduleTimeoutSeconds < 0 {
+		return fmt.Errorf("podgroup default schedule timeout seconds must not be negative")
+	}
+	return nil
+}
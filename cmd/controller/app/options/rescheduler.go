@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+)
+
+type ReschedulerControllerOptions struct {
+	*config.ReschedulerControllerConfiguration
+}
+
+func (opt *ReschedulerControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	if opt == nil {
+		return
+	}
+	fs.Int64Var(&opt.CheckPeriod, "rescheduler-check-period", opt.CheckPeriod, "the interval (in seconds) between two rounds of rescheduling.")
+	fs.Int64Var(&opt.MovementTTL, "rescheduler-movement-ttl", opt.MovementTTL, "how long (in seconds) movements created by rescheduler will be kept before being recycled.")
+	fs.Int64Var(&opt.MaxPodsPerMovement, "rescheduler-max-pods-per-movement", opt.MaxPodsPerMovement, "the maximum number of pods that could be moved by a single movement.")
+	fs.StringSliceVar(&opt.Algorithms, "rescheduler-algorithms", opt.Algorithms, "the list of rescheduling algorithms to run in order, available algorithms: GPUDefragmentation, LoadBalancing, NodeDraining.")
+	fs.StringSliceVar(&opt.IgnoredNamespace, "rescheduler-ignored-namespace-list", opt.IgnoredNamespace, "The list of namespace whose pods will never be moved by rescheduler.")
+	fs.Int64Var(&opt.LoadHighThreshold, "rescheduler-load-high-threshold", opt.LoadHighThreshold, "the cpu usage percentage above which a node is regarded as overloaded.")
+	fs.Int64Var(&opt.LoadLowThreshold, "rescheduler-load-low-threshold", opt.LoadLowThreshold, "the cpu usage percentage below which a node is regarded as underloaded.")
+	fs.Int64Var(&opt.NodeMetricExpirationSeconds, "rescheduler-node-metric-expiration-seconds", opt.NodeMetricExpirationSeconds, "how long the node metrics reported in CNR are considered valid.")
+	fs.StringVar(&opt.DrainNodeLabel, "rescheduler-drain-node-label", opt.DrainNodeLabel, "the label key that marks an unschedulable node to be drained.")
+}
+
+func (opt *ReschedulerControllerOptions) ApplyTo(cfg *config.ReschedulerControllerConfiguration) error {
+	if opt == nil {
+		return nil
+	}
+	opt.ReschedulerControllerConfiguration.DeepCopyInto(cfg)
+	return nil
+}
+
+func (opt *ReschedulerControllerOptions) Validate() error {
+	if opt == nil {
+		return nil
+	}
+	if opt.CheckPeriod <= 0 || opt.MovementTTL <= 0 || opt.MaxPodsPerMovement <= 0 {
+		return fmt.Errorf("rescheduler check period, movement ttl and max pods per movement must be positive")
+	}
+	if opt.LoadLowThreshold < 0 || opt.LoadHighThreshold > 100 || opt.LoadLowThreshold > opt.LoadHighThreshold {
+		return fmt.Errorf("invalid rescheduler load thresholds, expected 0 <= low(%d) <= high(%d) <= 100", opt.LoadLowThreshold, opt.LoadHighThreshold)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller"
+	clientbuilder "github.com/kubewharf/godel-scheduler/pkg/controller/clientbuilder"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup"
+	podgroupconfig "github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+)
+
+func startPodGroupController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	godelClient := controllerContext.GodelClientBuilder.ClientOrDie("podgroup-controller")
+
+	resolver, err := newPodGroupResolver(ctx, controllerContext.ClientBuilder, "podgroup-controller",
+		controllerContext.InformerFactory, controllerContext.ComponentConfig.PodGroupController)
+	if err != nil {
+		return nil, true, err
+	}
+
+	go podgroup.NewPodGroupController(
+		godelClient,
+		controllerContext.InformerFactory.Core().V1().Pods(),
+		controllerContext.GodelInformerFactory.Scheduling().V1alpha1().PodGroups(),
+		resolver,
+		controllerContext.ComponentConfig.PodGroupController,
+	).Run(ctx, controllerContext.ControllerManagerMetrics)
+	return nil, true, nil
+}
+
+// newPodGroupResolver returns the resolver reading the owners from informers. The informers of custom workloads
+// are started here, while the ones of in-tree workloads are registered to the informerFactory started by the caller.
+func newPodGroupResolver(ctx context.Context, clientBuilder clientbuilder.ControllerClientBuilder, name string,
+	informerFactory informers.SharedInformerFactory, cfg *podgroupconfig.PodGroupControllerConfiguration,
+) (*podgroup.Resolver, error) {
+	dynamicClient, err := dynamic.NewForConfig(clientBuilder.ConfigOrDie(name))
+	if err != nil {
+		return nil, err
+	}
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	adapters, err := podgroup.NewInTreeOwnerAdapters(informerFactory, dynamicInformerFactory, cfg.OwnerKinds)
+	if err != nil {
+		return nil, err
+	}
+	dynamicInformerFactory.Start(ctx.Done())
+	return podgroup.NewResolver(adapters...), nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	cnrinformer "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions/node/v1alpha1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler"
+)
+
+func startReschedulerController(ctx context.Context, controllerContext ControllerContext) (controller.Interface, bool, error) {
+	godelClient := controllerContext.GodelClientBuilder.ClientOrDie("rescheduler-controller")
+	kubeClient := controllerContext.ClientBuilder.ClientOrDie("rescheduler-controller")
+
+	var cnrInformer cnrinformer.CustomNodeResourceInformer
+	if controllerContext.KatalystInformerFactory != nil {
+		cnrInformer = controllerContext.KatalystInformerFactory.Node().V1alpha1().CustomNodeResources()
+	}
+
+	rc, err := rescheduler.NewReschedulerController(
+		godelClient,
+		kubeClient,
+		controllerContext.InformerFactory.Core().V1().Pods(),
+		controllerContext.InformerFactory.Core().V1().Nodes(),
+		controllerContext.InformerFactory.Policy().V1().PodDisruptionBudgets(),
+		controllerContext.InformerFactory.Scheduling().V1().PriorityClasses(),
+		controllerContext.GodelInformerFactory.Scheduling().V1alpha1().Movements(),
+		cnrInformer,
+		controllerContext.ComponentConfig.ReschedulerController,
+		rescheduler.NewInTreeRegistry(),
+	)
+	if err != nil {
+		return nil, true, err
+	}
+
+	go rc.Run(ctx, controllerContext.ControllerManagerMetrics)
+	return nil, true, nil
+}
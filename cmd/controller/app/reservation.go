@@ -42,14 +42,19 @@ func startReservationController(ctx context.Context, controllerContext Controlle
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		utils.WithIgnoredNamespaceList(controllerContext.ComponentConfig.ReservationController.IgnoredNamespace))
 
-	deployInformer := controllerContext.InformerFactory.Apps().V1().Deployments()
+	ownerResolvers := reservation.NewInTreeOwnerResolvers(
+		controllerContext.InformerFactory.Apps().V1().Deployments().Lister(),
+		controllerContext.InformerFactory.Apps().V1().StatefulSets().Lister(),
+		controllerContext.InformerFactory.Batch().V1().Jobs().Lister(),
+		controllerContext.GodelInformerFactory.Scheduling().V1alpha1().PodGroups().Lister())
 	reservationInformer := controllerContext.GodelInformerFactory.Scheduling().V1alpha1().Reservations()
 	reservationCheckPeriod := controllerContext.ComponentConfig.ReservationController.ReservationCheckPeriod
 	reservationTTL := controllerContext.ComponentConfig.ReservationController.ReservationTTL
 	matchedRequestCleanUpTTL := controllerContext.ComponentConfig.ReservationController.MatchedRequestExtraTTL
+	placeholderImage := controllerContext.ComponentConfig.ReservationController.PlaceholderImage
 
 	go podInformer.Informer().Run(ctx.Done())
-	go reservation.NewReservationController(ctx, godelClient, podInformer, deployInformer, reservationInformer,
-		reservationCheckPeriod, reservationTTL, matchedRequestCleanUpTTL).Run(ctx, controllerContext.ControllerManagerMetrics)
+	go reservation.NewReservationController(ctx, godelClient, kubeClient, podInformer, reservationInformer,
+		reservationCheckPeriod, reservationTTL, matchedRequestCleanUpTTL, placeholderImage, ownerResolvers).Run(ctx, controllerContext.ControllerManagerMetrics)
 	return nil, true, nil
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+
+	controllerappconfig "github.com/kubewharf/godel-scheduler/cmd/controller/app/config"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook"
+	clientbuilder "github.com/kubewharf/godel-scheduler/pkg/controller/clientbuilder"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup"
+)
+
+// installWebhooks mounts the enabled admission webhooks onto the secure mux.
+// Unlike controllers, webhooks are served by all the replicas regardless of leader election.
+func installWebhooks(ctx context.Context, cc *controllerappconfig.CompletedConfig, clientBuilder clientbuilder.ControllerClientBuilder, secureMux *mux.PathRecorderMux) error {
+	controllers := cc.ComponentConfig.Generic.Controllers
+
+	if isControllerEnabled("podgroup", ControllersDisabledByDefault, controllers) {
+		// The webhook is served by all the replicas, so it can't share the informers of the controllers.
+		informerFactory := informers.NewSharedInformerFactory(clientBuilder.ClientOrDie("podgroup-webhook"), 0)
+		resolver, err := newPodGroupResolver(ctx, clientBuilder, "podgroup-webhook", informerFactory, cc.ComponentConfig.PodGroupController)
+		if err != nil {
+			return err
+		}
+		informerFactory.Start(ctx.Done())
+		secureMux.Handle(podgroup.WebhookPath, podgroup.NewPodMutatingWebhook(resolver, cc.ComponentConfig.PodGroupController.IgnoredNamespace))
+		klog.InfoS("Installed webhook", "controller", "podgroup", "path", podgroup.WebhookPath)
+	}
+
+	if cc.ComponentConfig.AnnotationWebhook.Enabled {
+		webhook := annotationwebhook.NewAnnotationWebhook(cc.ComponentConfig.AnnotationWebhook)
+		secureMux.Handle(annotationwebhook.ValidatingWebhookPath, webhook.ValidatingHandler())
+		secureMux.Handle(annotationwebhook.MutatingWebhookPath, webhook.MutatingHandler())
+		klog.InfoS("Installed webhook", "webhook", "annotation", "paths",
+			[]string{annotationwebhook.ValidatingWebhookPath, annotationwebhook.MutatingWebhookPath})
+	}
+
+	return nil
+}
@@ -19,6 +19,8 @@ package config
 import (
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
 	apiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
@@ -26,6 +28,7 @@ import (
 	"k8s.io/client-go/tools/leaderelection"
 
 	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/shard"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
 )
 
@@ -37,6 +40,10 @@ type Config struct {
 	GodelCrdClient          godelclient.Interface
 	GodelCrdInformerFactory crdinformers.SharedInformerFactory
 
+	// katalyst crd client & informer
+	KatalystCrdClient          katalystclient.Interface
+	KatalystCrdInformerFactory katalystinformers.SharedInformerFactory
+
 	DispatcherConfig dispatcherconfig.GodelDispatcherConfiguration
 
 	// EventBroadcaster is wrapper for event broadcaster, compatible with core.v1.Event and events.v1beta1.Event, used for Events.
@@ -46,6 +53,9 @@ type Config struct {
 
 	LeaderElection *leaderelection.LeaderElectionConfig
 
+	// ShardManager coordinates the shards among the active dispatchers, nil if sharding is disabled.
+	ShardManager *shard.Manager
+
 	InsecureServing        *apiserver.DeprecatedInsecureServingInfo // nil will disable serving on an insecure port
 	InsecureMetricsServing *apiserver.DeprecatedInsecureServingInfo // non-nil if metrics should be served independentl
 
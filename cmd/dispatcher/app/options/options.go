@@ -26,6 +26,8 @@ import (
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
 	godelclientscheme "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/scheme"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
@@ -45,6 +47,7 @@ import (
 	dispatcherappconfig "github.com/kubewharf/godel-scheduler/cmd/dispatcher/app/config"
 	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config/validation"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/shard"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
 )
 
@@ -127,6 +130,7 @@ func (o *Options) Flags() (nfs cliflag.NamedFlagSets) {
 	o.DispatcherConfig.Tracer.AddFlags(nfs.FlagSet("tracer"))
 
 	BindFlags(&o.DispatcherConfig.LeaderElection, nfs.FlagSet("leader election"))
+	BindShardingFlags(o.DispatcherConfig.Sharding, nfs.FlagSet("sharding"))
 	utilfeature.DefaultMutableFeatureGate.AddFlag(nfs.FlagSet("generic"))
 	return nfs
 }
@@ -157,15 +161,23 @@ func (o *Options) Config() (*dispatcherappconfig.Config, error) {
 	}
 
 	// Prepare kube clients.
-	client, leaderElectionClient, eventClient, godelCrdClient, err := createClients(c.DispatcherConfig.ClientConnection, o.Master, c.DispatcherConfig.LeaderElection.RenewDeadline.Duration)
+	client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, err := createClients(c.DispatcherConfig.ClientConnection, o.Master, c.DispatcherConfig.LeaderElection.RenewDeadline.Duration)
 	if err != nil {
 		return nil, err
 	}
 
 	c.EventBroadcaster = cmdutil.NewEventBroadcasterAdapter(eventClient)
 
-	// Set up leader election if enabled.
+	// Set up leader election if enabled. Sharded dispatchers are all active, and coordinate the shards
+	// through the shard manager, while the leader election still decides the one running the cluster-wide workers.
 	var leaderElectionConfig *leaderelection.LeaderElectionConfig
+	if c.DispatcherConfig.Sharding.Enabled {
+		id, err := makeIdentity()
+		if err != nil {
+			return nil, err
+		}
+		c.ShardManager = shard.NewManager(leaderElectionClient, id, *c.DispatcherConfig.Sharding)
+	}
 	if *c.DispatcherConfig.LeaderElection.LeaderElect {
 		// Use the scheduler name in the first profile to record leader election.
 		coreRecorder := c.EventBroadcaster.DeprecatedNewLegacyRecorder(DefaultLeaderElectionName)
@@ -180,6 +192,8 @@ func (o *Options) Config() (*dispatcherappconfig.Config, error) {
 	c.GodelCrdClient = godelCrdClient
 
 	c.GodelCrdInformerFactory = crdinformers.NewSharedInformerFactory(c.GodelCrdClient, 0)
+	c.KatalystCrdClient = katalystCrdClient
+	c.KatalystCrdInformerFactory = katalystinformers.NewSharedInformerFactory(c.KatalystCrdClient, 0)
 	// TODO:(godel) delete if useless.
 	// c.EventClient = eventClient.EventsV1beta1()
 	// c.CoreEventClient = eventClient.CoreV1()
@@ -191,12 +205,10 @@ func (o *Options) Config() (*dispatcherappconfig.Config, error) {
 // makeLeaderElectionConfig builds a leader election configuration. It will
 // create a new resource lock associated with the configuration.
 func makeLeaderElectionConfig(config componentbaseconfig.LeaderElectionConfiguration, client clientset.Interface, recorder record.EventRecorder) (*leaderelection.LeaderElectionConfig, error) {
-	hostname, err := os.Hostname()
+	id, err := makeIdentity()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get hostname: %v", err)
+		return nil, err
 	}
-	// add a uniquifier so that two processes on the same host don't accidentally both become active
-	id := hostname + "_" + string(uuid.NewUUID())
 
 	rl, err := resourcelock.New(config.ResourceLock,
 		config.ResourceNamespace,
@@ -221,7 +233,17 @@ func makeLeaderElectionConfig(config componentbaseconfig.LeaderElectionConfigura
 	}, nil
 }
 
-func createClients(config componentbaseconfig.ClientConnectionConfiguration, masterOverride string, timeout time.Duration) (clientset.Interface, clientset.Interface, clientset.Interface, godelclient.Interface, error) {
+// makeIdentity returns the identity of the dispatcher process in leader election and sharding.
+func makeIdentity() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to get hostname: %v", err)
+	}
+	// add a uniquifier so that two processes on the same host don't accidentally both become active
+	return hostname + "_" + string(uuid.NewUUID()), nil
+}
+
+func createClients(config componentbaseconfig.ClientConnectionConfiguration, masterOverride string, timeout time.Duration) (clientset.Interface, clientset.Interface, clientset.Interface, godelclient.Interface, katalystclient.Interface, error) {
 	if len(config.Kubeconfig) == 0 && len(masterOverride) == 0 {
 		klog.InfoS("WARN: Neither --kubeconfig nor --master was specified. Using default API client. This might not work")
 	}
@@ -232,7 +254,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Kubeconfig},
 		&clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: masterOverride}}).ClientConfig()
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	kubeConfig.DisableCompression = true
@@ -243,7 +265,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 
 	client, err := clientset.NewForConfig(restclient.AddUserAgent(kubeConfig, "dispatcher"))
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	// shallow copy, do not modify the kubeConfig.Timeout.
@@ -251,13 +273,13 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 	restConfig.Timeout = timeout
 	leaderElectionClient, err := clientset.NewForConfig(restclient.AddUserAgent(&restConfig, "leader-election"))
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	utilruntime.Must(godelclientscheme.AddToScheme(clientsetscheme.Scheme))
 	eventClient, err := clientset.NewForConfig(kubeConfig)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	// This creates a client, first loading any specified kubeconfig
@@ -266,7 +288,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Kubeconfig},
 		&clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: masterOverride}}).ClientConfig()
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	crdKubeConfig.DisableCompression = true
@@ -276,8 +298,13 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 
 	godelCrdClient, err := godelclient.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "dispatcher"))
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
+	}
+
+	katalystCrdClient, err := katalystclient.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "dispatcher"))
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
 	}
 
-	return client, leaderElectionClient, eventClient, godelCrdClient, nil
+	return client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, nil
 }
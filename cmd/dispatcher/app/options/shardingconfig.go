@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	dispatcherconfig "github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+)
+
+// BindShardingFlags binds the ShardingConfiguration struct fields to a flagset
+func BindShardingFlags(s *dispatcherconfig.ShardingConfiguration, fs *pflag.FlagSet) {
+	fs.BoolVar(&s.Enabled, "sharding-enabled", s.Enabled, ""+
+		"Run the dispatcher as one of multiple active instances, each of which dispatches "+
+		"the pods of the shards it owns. Leader election is skipped if sharding is enabled.")
+	fs.StringVar((*string)(&s.ShardBy), "sharding-shard-by", string(s.ShardBy), ""+
+		"The way pods are hashed into shards. Supported options are 'Namespace' and 'PodGroup'.")
+	fs.Int32Var(&s.ShardCount, "sharding-shard-count", s.ShardCount, ""+
+		"The number of shards distributed among the dispatchers. It must be the same for all "+
+		"the dispatchers.")
+	fs.StringVar(&s.LeaseNamespace, "sharding-lease-namespace", s.LeaseNamespace, ""+
+		"The namespace of the leases coordinating the shard ownership.")
+	fs.StringVar(&s.LeaseName, "sharding-lease-name", s.LeaseName, ""+
+		"The prefix of the names of the leases coordinating the shard ownership.")
+	fs.DurationVar(&s.LeaseDuration.Duration, "sharding-lease-duration", s.LeaseDuration.Duration, ""+
+		"The duration after which the shards of a dispatcher that stops renewing its "+
+		"membership are taken over by the other dispatchers.")
+	fs.DurationVar(&s.RenewPeriod.Duration, "sharding-renew-period", s.RenewPeriod.Duration, ""+
+		"The interval between renewing the membership and rebalancing the shards. This must "+
+		"be less than the lease duration.")
+}
@@ -143,8 +143,11 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		cc.GodelCrdInformerFactory.Node().V1alpha1().NMNodes(),
 		cc.GodelCrdInformerFactory.Scheduling().V1alpha1().PodGroups(),
 		cc.InformerFactory.Scheduling().V1().PriorityClasses(),
+		cc.KatalystCrdClient,
+		cc.KatalystCrdInformerFactory.Node().V1alpha1().CustomNodeResources(),
 		*cc.DispatcherConfig.SchedulerName,
 		getEventRecorder(&cc),
+		cc.ShardManager,
 	)
 
 	// Prepare the event broadcaster.
@@ -152,7 +155,7 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 
 	// Setup healthz checks.
 	var checks []healthz.HealthChecker
-	if *cc.DispatcherConfig.LeaderElection.LeaderElect {
+	if cc.LeaderElection != nil {
 		checks = append(checks, cc.LeaderElection.WatchDog)
 	}
 
@@ -175,6 +178,8 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 	cc.InformerFactory.WaitForCacheSync(ctx.Done())
 	cc.GodelCrdInformerFactory.Start(ctx.Done())
 	cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
+	cc.KatalystCrdInformerFactory.Start(ctx.Done())
+	cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
 
 	// Prepare a reusable runCommand function.
 	run := func(ctx context.Context) {
@@ -185,25 +190,29 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		defer closer.Close()
 
 		dispatcher.Run(ctx)
+		if cc.ShardManager == nil {
+			dispatcher.RunSingletons(ctx)
+		}
 		<-ctx.Done()
 	}
+	onStoppedLeading := func() {
+		select {
+		case <-ctx.Done():
+			// We were asked to terminate. Exit 0.
+			klog.InfoS("Requested to terminate. Exiting")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		default:
+			// We lost the lock.
+			klog.ErrorS(nil, "Lost leader election")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
 
 	// If leader election is enabled, runCommand via LeaderElector until done and exit.
-	if cc.LeaderElection != nil {
+	if cc.LeaderElection != nil && cc.ShardManager == nil {
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
 			OnStartedLeading: run,
-			OnStoppedLeading: func() {
-				select {
-				case <-ctx.Done():
-					// We were asked to terminate. Exit 0.
-					klog.InfoS("Requested to terminate. Exiting")
-					klog.FlushAndExit(klog.ExitFlushTimeout, 0)
-				default:
-					// We lost the lock.
-					klog.ErrorS(nil, "Lost leader election")
-					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
-				}
-			},
+			OnStoppedLeading: onStoppedLeading,
 		}
 		leaderElector, err := leaderelection.NewLeaderElector(*cc.LeaderElection)
 		if err != nil {
@@ -215,6 +224,34 @@ func Run(ctx context.Context, cc config.CompletedConfig) error {
 		return fmt.Errorf("lost lease")
 	}
 
+	// If sharding is enabled, all the dispatchers are active and dispatch the pods of their own shards, while the
+	// workers writing cluster-wide objects are only run by the leader.
+	if cc.ShardManager != nil {
+		if cc.LeaderElection != nil {
+			cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
+				OnStartedLeading: dispatcher.RunSingletons,
+				OnStoppedLeading: onStoppedLeading,
+			}
+			leaderElector, err := leaderelection.NewLeaderElector(*cc.LeaderElection)
+			if err != nil {
+				return fmt.Errorf("couldn't create leader elector: %v", err)
+			}
+			go leaderElector.Run(ctx)
+		} else {
+			dispatcher.RunSingletons(ctx)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			cc.ShardManager.Run(ctx)
+		}()
+		run(ctx)
+		// Wait for the shards to be released, so that the other dispatchers take them over at once.
+		<-done
+		return fmt.Errorf("finished dispatching the shards")
+	}
+
 	run(ctx)
 	return fmt.Errorf("finished without leader elect")
 }
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+type debugOptions struct {
+	address string
+	file    string
+	timeout time.Duration
+	// token is the bearer token sent to the secure port, e.g. for /dryrun.
+	token              string
+	insecureSkipVerify bool
+}
+
+func newDebugCmd(o *Options, out io.Writer) *cobra.Command {
+	d := &debugOptions{timeout: 30 * time.Second}
+	cmd := &cobra.Command{
+		Use:   "debug SCHEDULER PATH",
+		Short: "Query the debug endpoints of a scheduler, e.g. /healthz, /metrics, /configz or /dryrun",
+		Long: `Query the debug endpoints of a scheduler. The request is sent to --address if it's set, otherwise to the
+current host recorded in the Scheduler CRD on the secure port. The content of --file is POSTed if it's set, e.g. for /dryrun.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address := d.address
+			if len(address) == 0 {
+				clients, err := o.getClients()
+				if err != nil {
+					return err
+				}
+				if address, err = getSchedulerAddress(cmd.Context(), clients, args[0]); err != nil {
+					return err
+				}
+			}
+			return runDebug(cmd.Context(), out, d, address, args[1])
+		},
+	}
+	cmd.Flags().StringVar(&d.address, "address", d.address, "The address of the scheduler, e.g. https://127.0.0.1:10259.")
+	cmd.Flags().StringVarP(&d.file, "file", "f", d.file, "The file whose content is POSTed as the request body, - for stdin.")
+	cmd.Flags().DurationVar(&d.timeout, "timeout", d.timeout, "The timeout of the request.")
+	cmd.Flags().StringVar(&d.token, "token", d.token, "The bearer token used to authenticate to the secure port of the scheduler.")
+	cmd.Flags().BoolVar(&d.insecureSkipVerify, "insecure-skip-tls-verify", d.insecureSkipVerify, "Skip verifying the serving certificate of the scheduler.")
+	return cmd
+}
+
+// getSchedulerAddress returns the secure address of the current host of the scheduler, since some endpoints,
+// e.g. /dryrun, are only served on the secure port.
+func getSchedulerAddress(ctx context.Context, clients *Clients, schedulerName string) (string, error) {
+	scheduler, err := clients.CrdClient.SchedulingV1alpha1().Schedulers().Get(ctx, schedulerName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if len(scheduler.Status.CurrentHost) == 0 {
+		return "", fmt.Errorf("current host of scheduler %s is unknown, please specify --address", schedulerName)
+	}
+	return "https://" + net.JoinHostPort(scheduler.Status.CurrentHost, strconv.Itoa(schedulerconfig.DefaultGodelSchedulerPort)), nil
+}
+
+func runDebug(ctx context.Context, out io.Writer, d *debugOptions, address, path string) error {
+	url := strings.TrimSuffix(address, "/") + "/" + strings.TrimPrefix(path, "/")
+	method, contentType := http.MethodGet, ""
+	var body io.Reader
+	if len(d.file) > 0 {
+		var data []byte
+		var err error
+		if d.file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(d.file)
+		}
+		if err != nil {
+			return err
+		}
+		method, contentType, body = http.MethodPost, "application/json", bytes.NewReader(data)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(d.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	client := http.DefaultClient
+	if d.insecureSkipVerify {
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
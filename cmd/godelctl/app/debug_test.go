@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSchedulerAddress(t *testing.T) {
+	clients := &Clients{CrdClient: godelclientfake.NewSimpleClientset(
+		&schedulingv1a1.Scheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: "s1"},
+			Status:     schedulingv1a1.SchedulerStatus{CurrentHost: "10.0.0.1"},
+		},
+		&schedulingv1a1.Scheduler{ObjectMeta: metav1.ObjectMeta{Name: "s2"}},
+	)}
+
+	address, err := getSchedulerAddress(context.Background(), clients, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://10.0.0.1:10259"; address != want {
+		t.Errorf("expected address %s, got %s", want, address)
+	}
+	if _, err := getSchedulerAddress(context.Background(), clients, "s2"); err == nil {
+		t.Errorf("expected error for scheduler without current host")
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Clients are the clients used by godelctl commands.
+type Clients struct {
+	Client    kubernetes.Interface
+	CrdClient godelclient.Interface
+}
+
+// Options are the global options of godelctl.
+type Options struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+
+	// clients is built lazily from the options, tests could set it directly.
+	clients *Clients
+}
+
+func (o *Options) getNamespace() (string, error) {
+	if len(o.Namespace) > 0 {
+		return o.Namespace, nil
+	}
+	namespace, _, err := o.clientConfig().Namespace()
+	return namespace, err
+}
+
+func (o *Options) clientConfig() clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.Context})
+}
+
+func (o *Options) getClients() (*Clients, error) {
+	if o.clients != nil {
+		return o.clients, nil
+	}
+	restConfig, err := o.clientConfig().ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	crdClient, err := godelclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	o.clients = &Clients{Client: client, CrdClient: crdClient}
+	return o.clients, nil
+}
+
+// NewGodelctlCmd creates the godelctl command. It could also be used as a kubectl plugin
+// by naming the binary `kubectl-godel`.
+func NewGodelctlCmd(out io.Writer) *cobra.Command {
+	o := &Options{}
+	cmd := &cobra.Command{
+		Use:           "godelctl",
+		Short:         "godelctl inspects and operates Gödel scheduling system",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.SetOut(out)
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file, KUBECONFIG and ~/.kube/config are used if not set.")
+	flags.StringVar(&o.Context, "context", o.Context, "The name of the kubeconfig context to use.")
+	flags.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "The namespace of pods and pod groups, the namespace of the kubeconfig context is used if not set.")
+
+	cmd.AddCommand(
+		newJourneyCmd(o, out),
+		newSchedulersCmd(o, out),
+		newRebalanceCmd(o, out),
+		newReservationsCmd(o, out),
+		newMovementsCmd(o, out),
+		newDebugCmd(o, out),
+	)
+	return cmd
+}
+
+func age(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(t))
+}
+
+func valueOrNone(value string) string {
+	if len(value) == 0 {
+		return "<none>"
+	}
+	return value
+}
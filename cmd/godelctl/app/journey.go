@@ -0,0 +1,293 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	frameworkutils "github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// PodStage is the component a pod is waiting for in the pipeline of dispatcher, scheduler and binder.
+type PodStage string
+
+const (
+	StageDispatcher PodStage = "Dispatcher"
+	StageScheduler  PodStage = "Scheduler"
+	StageBinder     PodStage = "Binder"
+	StageBound      PodStage = "Bound"
+	StageAbnormal   PodStage = "Abnormal"
+)
+
+// GetPodStage returns the stage of the pod according to its state annotations.
+func GetPodStage(pod *v1.Pod) PodStage {
+	switch {
+	case podutil.BoundPod(pod):
+		return StageBound
+	case podutil.AssumedPod(pod):
+		return StageBinder
+	case podutil.DispatchedPod(pod):
+		return StageScheduler
+	case podutil.PendingPod(pod):
+		return StageDispatcher
+	}
+	return StageAbnormal
+}
+
+func newJourneyCmd(o *Options, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "journey",
+		Short: "Show the journey of a pod or a pod group through dispatcher, scheduler and binder",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "pod NAME",
+		Short: "Show the journey of a pod",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPodJourney(cmd.Context(), o, out, args[0])
+		},
+	}, &cobra.Command{
+		Use:     "podgroup NAME",
+		Aliases: []string{"pg"},
+		Short:   "Show the journey of a pod group and its pods",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPodGroupJourney(cmd.Context(), o, out, args[0])
+		},
+	})
+	return cmd
+}
+
+func runPodJourney(ctx context.Context, o *Options, out io.Writer, name string) error {
+	clients, err := o.getClients()
+	if err != nil {
+		return err
+	}
+	namespace, err := o.getNamespace()
+	if err != nil {
+		return err
+	}
+	pod, err := clients.Client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	events, err := listEvents(ctx, clients, namespace, pod.UID)
+	if err != nil {
+		return err
+	}
+	printPodJourney(out, pod, events, time.Now())
+	return nil
+}
+
+func runPodGroupJourney(ctx context.Context, o *Options, out io.Writer, name string) error {
+	clients, err := o.getClients()
+	if err != nil {
+		return err
+	}
+	namespace, err := o.getNamespace()
+	if err != nil {
+		return err
+	}
+	pg, err := clients.CrdClient.SchedulingV1alpha1().PodGroups(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	podList, err := clients.Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var pods []*v1.Pod
+	for i := range podList.Items {
+		if podutil.GetPodGroupName(&podList.Items[i]) == pg.Name {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+	events, err := listEvents(ctx, clients, namespace, pg.UID)
+	if err != nil {
+		return err
+	}
+	printPodGroupJourney(out, pg, pods, events, time.Now())
+	return nil
+}
+
+// listEvents lists the events of the object. Events are filtered by the client, because field selectors
+// on involvedObject.uid are not supported by all the API servers.
+func listEvents(ctx context.Context, clients *Clients, namespace string, uid types.UID) ([]v1.Event, error) {
+	eventList, err := clients.Client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var events []v1.Event
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.UID == uid {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+	return events, nil
+}
+
+func eventTime(event *v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func printPodJourney(out io.Writer, pod *v1.Pod, events []v1.Event, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	stage := GetPodStage(pod)
+	fmt.Fprintf(w, "Pod:\t%s\n", podutil.GetPodKey(pod))
+	fmt.Fprintf(w, "Created:\t%s ago\n", age(pod.CreationTimestamp.Time, now))
+	if pgName := podutil.GetPodGroupName(pod); len(pgName) > 0 {
+		fmt.Fprintf(w, "PodGroup:\t%s\n", pgName)
+	}
+	fmt.Fprintf(w, "Stage:\t%s\n", stage)
+	fmt.Fprintf(w, "State:\t%s\n", valueOrNone(string(podutil.GetPodState(pod.Annotations))))
+
+	// Dispatcher
+	if schedulerName := podutil.GetSchedulerNameForPod(pod); len(schedulerName) > 0 {
+		fmt.Fprintf(w, "Dispatcher:\tdispatched to scheduler %s\n", schedulerName)
+	} else if stage != StageBound {
+		fmt.Fprintf(w, "Dispatcher:\twaiting to be dispatched\n")
+	}
+	if count := podutil.GetRedispatchCount(pod); count > 0 {
+		fmt.Fprintf(w, "Redispatched:\t%d times\n", count)
+	}
+
+	// Scheduler
+	if failedSchedulers := podutil.GetFailedSchedulersNames(pod); failedSchedulers.Len() > 0 {
+		fmt.Fprintf(w, "Failed schedulers:\t%s\n", strings.Join(failedSchedulers.List(), ","))
+	}
+	switch stage {
+	case StageScheduler:
+		fmt.Fprintf(w, "Scheduler:\tbeing scheduled by %s\n", podutil.GetSchedulerNameForPod(pod))
+	case StageBinder:
+		if assumedNode := pod.Annotations[podutil.AssumedNodeAnnotationKey]; len(assumedNode) > 0 {
+			fmt.Fprintf(w, "Scheduler:\tassumed on node %s\n", assumedNode)
+		} else if nominatedNode, err := frameworkutils.GetPodNominatedNode(pod); err == nil {
+			victims := make([]string, 0, len(nominatedNode.VictimPods))
+			for _, victim := range nominatedNode.VictimPods {
+				victims = append(victims, victim.Namespace+"/"+victim.Name)
+			}
+			fmt.Fprintf(w, "Scheduler:\tnominated node %s, victims: %s\n", nominatedNode.NodeName, strings.Join(victims, ","))
+		} else {
+			fmt.Fprintf(w, "Scheduler:\tnominated node %s\n", pod.Annotations[podutil.NominatedNodeAnnotationKey])
+		}
+	}
+
+	// Binder
+	switch stage {
+	case StageBinder:
+		fmt.Fprintf(w, "Binder:\twaiting to be bound\n")
+	case StageBound:
+		fmt.Fprintf(w, "Binder:\tbound to node %s\n", pod.Spec.NodeName)
+	}
+	if _, condition := podutil.GetPodCondition(&pod.Status, v1.PodScheduled); condition != nil && condition.Status != v1.ConditionTrue {
+		fmt.Fprintf(w, "Unschedulable:\t%s: %s\n", condition.Reason, condition.Message)
+	}
+	fmt.Fprintf(w, "Phase:\t%s\n", pod.Status.Phase)
+	w.Flush()
+
+	printEvents(out, events, now)
+}
+
+func printPodGroupJourney(out io.Writer, pg *schedulingv1a1.PodGroup, pods []*v1.Pod, events []v1.Event, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "PodGroup:\t%s/%s\n", pg.Namespace, pg.Name)
+	fmt.Fprintf(w, "Created:\t%s ago\n", age(pg.CreationTimestamp.Time, now))
+	fmt.Fprintf(w, "Phase:\t%s\n", valueOrNone(string(pg.Status.Phase)))
+	fmt.Fprintf(w, "MinMember:\t%d\n", pg.Spec.MinMember)
+
+	stages := make(map[PodStage]int)
+	for _, pod := range pods {
+		stages[GetPodStage(pod)]++
+	}
+	var summary []string
+	for _, stage := range []PodStage{StageDispatcher, StageScheduler, StageBinder, StageBound, StageAbnormal} {
+		if stages[stage] > 0 {
+			summary = append(summary, fmt.Sprintf("%s=%d", stage, stages[stage]))
+		}
+	}
+	fmt.Fprintf(w, "Pods:\t%d (%s)\n", len(pods), strings.Join(summary, ", "))
+
+	if len(pg.Status.Conditions) > 0 {
+		fmt.Fprintf(w, "\nConditions:\n")
+		fmt.Fprintf(w, "  AGE\tPHASE\tSTATUS\tREASON\tMESSAGE\n")
+		for _, condition := range pg.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", age(condition.LastTransitionTime.Time, now), condition.Phase, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	if len(pods) > 0 {
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		fmt.Fprintf(w, "\nPods:\n")
+		fmt.Fprintf(w, "  NAME\tSTAGE\tSCHEDULER\tNODE\tFAILED-SCHEDULERS\n")
+		for _, pod := range pods {
+			node := pod.Spec.NodeName
+			if len(node) == 0 {
+				node = pod.Annotations[podutil.AssumedNodeAnnotationKey]
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", pod.Name, GetPodStage(pod), valueOrNone(podutil.GetSchedulerNameForPod(pod)),
+				valueOrNone(node), valueOrNone(strings.Join(podutil.GetFailedSchedulersNames(pod).List(), ",")))
+		}
+	}
+	w.Flush()
+
+	printEvents(out, events, now)
+}
+
+func printEvents(out io.Writer, events []v1.Event, now time.Time) {
+	if len(events) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "\nEvents:\n")
+	fmt.Fprintf(w, "  AGE\tTYPE\tREASON\tFROM\tMESSAGE\n")
+	for i := range events {
+		event := &events[i]
+		from := event.ReportingController
+		if len(from) == 0 {
+			from = event.Source.Component
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", age(eventTime(event), now), event.Type, event.Reason, valueOrNone(from), strings.TrimSpace(event.Message))
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeJourneyTestPod(annotations map[string]string, nodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "p", Annotations: annotations},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestPodJourney(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		wantStage    PodStage
+		wantMessages []string
+	}{
+		{
+			name:         "pending in dispatcher",
+			pod:          makeJourneyTestPod(map[string]string{podutil.PodStateAnnotationKey: string(podutil.PodPending)}, ""),
+			wantStage:    StageDispatcher,
+			wantMessages: []string{"waiting to be dispatched"},
+		},
+		{
+			name: "dispatched to scheduler",
+			pod: makeJourneyTestPod(map[string]string{
+				podutil.PodStateAnnotationKey:         string(podutil.PodDispatched),
+				podutil.SchedulerAnnotationKey:        "s2",
+				podutil.FailedSchedulersAnnotationKey: "s1",
+			}, ""),
+			wantStage:    StageScheduler,
+			wantMessages: []string{"dispatched to scheduler s2", "being scheduled by s2", "Failed schedulers:"},
+		},
+		{
+			name: "assumed by scheduler",
+			pod: makeJourneyTestPod(map[string]string{
+				podutil.PodStateAnnotationKey:    string(podutil.PodAssumed),
+				podutil.SchedulerAnnotationKey:   "s1",
+				podutil.AssumedNodeAnnotationKey: "n1",
+			}, ""),
+			wantStage:    StageBinder,
+			wantMessages: []string{"assumed on node n1", "waiting to be bound"},
+		},
+		{
+			name:         "bound",
+			pod:          makeJourneyTestPod(map[string]string{podutil.SchedulerAnnotationKey: "s1"}, "n1"),
+			wantStage:    StageBound,
+			wantMessages: []string{"bound to node n1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if stage := GetPodStage(tt.pod); stage != tt.wantStage {
+				t.Errorf("expected stage %s, got %s", tt.wantStage, stage)
+			}
+			out := &bytes.Buffer{}
+			printPodJourney(out, tt.pod, nil, time.Now())
+			for _, message := range tt.wantMessages {
+				if !strings.Contains(out.String(), message) {
+					t.Errorf("expected %q in output:\n%s", message, out.String())
+				}
+			}
+		})
+	}
+}
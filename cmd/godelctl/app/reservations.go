@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func newReservationsCmd(o *Options, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "reservations [NAME]",
+		Aliases: []string{"reservation"},
+		Short:   "List reservations in the namespace, or show the details of a reservation",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := o.getClients()
+			if err != nil {
+				return err
+			}
+			namespace, err := o.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runReservations(cmd.Context(), clients, out, namespace, args)
+		},
+	}
+}
+
+func runReservations(ctx context.Context, clients *Clients, out io.Writer, namespace string, args []string) error {
+	now := time.Now()
+	if len(args) == 1 {
+		reservation, err := clients.CrdClient.SchedulingV1alpha1().Reservations(namespace).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		printReservation(out, reservation, now)
+		return nil
+	}
+
+	reservationList, err := clients.CrdClient.SchedulingV1alpha1().Reservations(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	reservations := reservationList.Items
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].Name < reservations[j].Name })
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "NAME\tPHASE\tNODE\tOWNER\tTTL\tAGE\n")
+	for i := range reservations {
+		reservation := &reservations[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", reservation.Name, valueOrNone(string(reservation.Status.Phase)),
+			valueOrNone(reservation.Spec.NodeName), valueOrNone(reservationOwner(reservation)), reservationTTL(reservation),
+			age(reservation.CreationTimestamp.Time, now))
+	}
+	return nil
+}
+
+func reservationOwner(reservation *schedulingv1a1.Reservation) string {
+	owner := reservation.Status.CurrentOwners
+	if len(owner.Name) == 0 {
+		return ""
+	}
+	return owner.Namespace + "/" + owner.Name
+}
+
+func reservationTTL(reservation *schedulingv1a1.Reservation) string {
+	if reservation.Spec.TimeToLive == nil {
+		return "<none>"
+	}
+	return (time.Duration(*reservation.Spec.TimeToLive) * time.Second).String()
+}
+
+func printReservation(out io.Writer, reservation *schedulingv1a1.Reservation, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Reservation:\t%s\n", podutil.GetReservationKey(reservation))
+	fmt.Fprintf(w, "Created:\t%s ago\n", age(reservation.CreationTimestamp.Time, now))
+	fmt.Fprintf(w, "Phase:\t%s\n", valueOrNone(string(reservation.Status.Phase)))
+	fmt.Fprintf(w, "Node:\t%s\n", valueOrNone(reservation.Spec.NodeName))
+	fmt.Fprintf(w, "TTL:\t%s\n", reservationTTL(reservation))
+	fmt.Fprintf(w, "Owner:\t%s\n", valueOrNone(reservationOwner(reservation)))
+	fmt.Fprintf(w, "Scheduler:\t%s\n", valueOrNone(reservation.Spec.Template.Spec.SchedulerName))
+	if len(reservation.Status.Conditions) > 0 {
+		fmt.Fprintf(w, "\nConditions:\n")
+		fmt.Fprintf(w, "  AGE\tPHASE\tSTATUS\tREASON\tMESSAGE\n")
+		for _, condition := range reservation.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", age(condition.LastTransitionTime.Time, now), condition.Phase, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+}
+
+func newMovementsCmd(o *Options, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "movements [NAME]",
+		Aliases: []string{"movement"},
+		Short:   "List movements, or show the recommended nodes of a movement",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := o.getClients()
+			if err != nil {
+				return err
+			}
+			return runMovements(cmd.Context(), clients, out, args)
+		},
+	}
+}
+
+func runMovements(ctx context.Context, clients *Clients, out io.Writer, args []string) error {
+	now := time.Now()
+	if len(args) == 1 {
+		movement, err := clients.CrdClient.SchedulingV1alpha1().Movements().Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		printMovement(out, movement, now)
+		return nil
+	}
+
+	movementList, err := clients.CrdClient.SchedulingV1alpha1().Movements().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	movements := movementList.Items
+	sort.Slice(movements, func(i, j int) bool { return movements[i].Name < movements[j].Name })
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "NAME\tCREATOR\tGENERATION\tDELETED-TASKS\tOWNERS\tNOTIFIED-SCHEDULERS\tAGE\n")
+	for i := range movements {
+		movement := &movements[i]
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\t%s\n", movement.Name, valueOrNone(movement.Spec.Creator), movement.Spec.Generation,
+			len(movement.Spec.DeletedTasks), len(movement.Status.Owners), valueOrNone(strings.Join(movement.Status.NotifiedSchedulers, ",")),
+			age(movement.CreationTimestamp.Time, now))
+	}
+	return nil
+}
+
+func printMovement(out io.Writer, movement *schedulingv1a1.Movement, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Movement:\t%s\n", movement.Name)
+	fmt.Fprintf(w, "Created:\t%s ago\n", age(movement.CreationTimestamp.Time, now))
+	fmt.Fprintf(w, "Creator:\t%s\n", valueOrNone(movement.Spec.Creator))
+	fmt.Fprintf(w, "Generation:\t%d\n", movement.Spec.Generation)
+	fmt.Fprintf(w, "Notified schedulers:\t%s\n", valueOrNone(strings.Join(movement.Status.NotifiedSchedulers, ",")))
+
+	if len(movement.Spec.DeletedTasks) > 0 {
+		fmt.Fprintf(w, "\nDeleted tasks:\n")
+		fmt.Fprintf(w, "  POD\tNODE\n")
+		for _, task := range movement.Spec.DeletedTasks {
+			fmt.Fprintf(w, "  %s/%s\t%s\n", task.Namespace, task.Name, valueOrNone(task.Node))
+		}
+	}
+
+	for _, owner := range movement.Status.Owners {
+		if owner.Owner == nil {
+			continue
+		}
+		fmt.Fprintf(w, "\nOwner %s %s/%s:\n", owner.Owner.Type, owner.Owner.Namespace, owner.Owner.Name)
+		fmt.Fprintf(w, "  NODE\tDESIRED\tACTUAL\n")
+		for _, recommended := range owner.RecommendedNodes {
+			fmt.Fprintf(w, "  %s\t%d\t%d\n", recommended.Node, recommended.DesiredPodCount, len(recommended.ActualPods))
+		}
+		if len(owner.MismatchedTasks) > 0 {
+			mismatched := make([]string, 0, len(owner.MismatchedTasks))
+			for _, task := range owner.MismatchedTasks {
+				mismatched = append(mismatched, fmt.Sprintf("%s/%s(%s)", task.Namespace, task.Name, task.Node))
+			}
+			fmt.Fprintf(w, "  Mismatched tasks:\t%s\n", strings.Join(mismatched, ","))
+		}
+	}
+}
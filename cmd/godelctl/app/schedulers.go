@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	schemaintainer "github.com/kubewharf/godel-scheduler/pkg/dispatcher/scheduler-maintainer"
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// partitionInfo is the node partition and the pending workloads of a scheduler.
+type partitionInfo struct {
+	nodes   sets.String
+	nmNodes sets.String
+	// pendingPods are pods dispatched to the scheduler but not scheduled yet.
+	pendingPods int
+	// pendingUnits are the distinct units (pod groups or single pods) of pending pods.
+	pendingUnits sets.String
+}
+
+// clusterPartitions is the snapshot of node partitions and pending workloads in the cluster.
+type clusterPartitions struct {
+	schedulers []schedulingv1a1.Scheduler
+	partitions map[string]*partitionInfo
+	// unassignedNodes are nodes without scheduler annotation.
+	unassignedNodes int
+	// undispatchedPods are pods waiting for dispatcher.
+	undispatchedPods int
+}
+
+func (c *clusterPartitions) getPartition(schedulerName string) *partitionInfo {
+	partition, ok := c.partitions[schedulerName]
+	if !ok {
+		partition = &partitionInfo{nodes: sets.NewString(), nmNodes: sets.NewString(), pendingUnits: sets.NewString()}
+		c.partitions[schedulerName] = partition
+	}
+	return partition
+}
+
+// allNodes returns the names of nodes and NMNodes in the partition.
+func (p *partitionInfo) allNodes() sets.String {
+	return p.nodes.Union(p.nmNodes)
+}
+
+func getClusterPartitions(ctx context.Context, clients *Clients) (*clusterPartitions, error) {
+	schedulerList, err := clients.CrdClient.SchedulingV1alpha1().Schedulers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	c := &clusterPartitions{schedulers: schedulerList.Items, partitions: make(map[string]*partitionInfo)}
+	sort.Slice(c.schedulers, func(i, j int) bool { return c.schedulers[i].Name < c.schedulers[j].Name })
+
+	nodeList, err := clients.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodeList.Items {
+		if schedulerName := node.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]; len(schedulerName) > 0 {
+			c.getPartition(schedulerName).nodes.Insert(node.Name)
+		} else {
+			c.unassignedNodes++
+		}
+	}
+	nmNodeList, err := clients.CrdClient.NodeV1alpha1().NMNodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, nmNode := range nmNodeList.Items {
+		if schedulerName := nmNode.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]; len(schedulerName) > 0 {
+			c.getPartition(schedulerName).nmNodes.Insert(nmNode.Name)
+		}
+	}
+
+	podList, err := clients.Client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		switch {
+		case podutil.DispatchedPod(pod):
+			partition := c.getPartition(podutil.GetSchedulerNameForPod(pod))
+			partition.pendingPods++
+			if pgName := podutil.GetPodGroupName(pod); len(pgName) > 0 {
+				partition.pendingUnits.Insert(pod.Namespace + "/" + pgName)
+			} else {
+				partition.pendingUnits.Insert(podutil.GetPodKey(pod))
+			}
+		case podutil.PendingPod(pod):
+			c.undispatchedPods++
+		}
+	}
+	return c, nil
+}
+
+func newSchedulersCmd(o *Options, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedulers",
+		Short: "List schedulers with their node partitions and pending units",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := o.getClients()
+			if err != nil {
+				return err
+			}
+			c, err := getClusterPartitions(cmd.Context(), clients)
+			if err != nil {
+				return err
+			}
+			printSchedulers(out, c, time.Now())
+			return nil
+		},
+	}
+}
+
+func printSchedulers(out io.Writer, c *clusterPartitions, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "NAME\tACTIVE\tPARTITION\tNODES\tNMNODES\tPENDING-PODS\tPENDING-UNITS\tLAST-UPDATE\n")
+	for i := range c.schedulers {
+		scheduler := &c.schedulers[i]
+		partition := c.getPartition(scheduler.Name)
+		lastUpdate := "<unknown>"
+		if scheduler.Status.LastUpdateTime != nil {
+			lastUpdate = age(scheduler.Status.LastUpdateTime.Time, now) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%v\t%s\t%d\t%d\t%d\t%d\t%s\n", scheduler.Name, schemaintainer.IsSchedulerActive(scheduler),
+			valueOrNone(scheduler.Status.SchedulerNodePartitionName), partition.nodes.Len(), partition.nmNodes.Len(),
+			partition.pendingPods, partition.pendingUnits.Len(), lastUpdate)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nUnassigned nodes: %d\nPods waiting for dispatcher: %d\n", c.unassignedNodes, c.undispatchedPods)
+}
+
+// nodeMove moves a node from a scheduler partition to another.
+type nodeMove struct {
+	node string
+	from string
+	to   string
+}
+
+// planRebalance returns the moves which make the numbers of nodes in partitions differ by at most one, while
+// moving as few nodes as possible. Nodes with the largest names are moved first to make the plan stable.
+func planRebalance(partitions map[string]sets.String) []nodeMove {
+	if len(partitions) < 2 {
+		return nil
+	}
+	schedulers := make([]string, 0, len(partitions))
+	total := 0
+	for schedulerName, nodes := range partitions {
+		schedulers = append(schedulers, schedulerName)
+		total += nodes.Len()
+	}
+	// Schedulers with more nodes keep the remainder.
+	sort.Slice(schedulers, func(i, j int) bool {
+		a, b := partitions[schedulers[i]].Len(), partitions[schedulers[j]].Len()
+		if a != b {
+			return a > b
+		}
+		return schedulers[i] < schedulers[j]
+	})
+	targets := make(map[string]int, len(schedulers))
+	for i, schedulerName := range schedulers {
+		targets[schedulerName] = total / len(schedulers)
+		if i < total%len(schedulers) {
+			targets[schedulerName]++
+		}
+	}
+
+	var surplus []nodeMove
+	for _, schedulerName := range schedulers {
+		nodes := partitions[schedulerName].List()
+		for i := len(nodes) - 1; i >= targets[schedulerName]; i-- {
+			surplus = append(surplus, nodeMove{node: nodes[i], from: schedulerName})
+		}
+	}
+	var moves []nodeMove
+	for i := len(schedulers) - 1; i >= 0 && len(surplus) > 0; i-- {
+		schedulerName := schedulers[i]
+		for deficit := targets[schedulerName] - partitions[schedulerName].Len(); deficit > 0 && len(surplus) > 0; deficit-- {
+			move := surplus[0]
+			surplus = surplus[1:]
+			move.to = schedulerName
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+func newRebalanceCmd(o *Options, out io.Writer) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "rebalance",
+		Short: "Rebalance nodes among the partitions of active schedulers",
+		Long: `Rebalance nodes among the partitions of active schedulers, so that the numbers of nodes differ by at most one.
+Nodes are moved by updating the scheduler name annotation of Node and NMNode, in the same way as dispatcher does.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := o.getClients()
+			if err != nil {
+				return err
+			}
+			return runRebalance(cmd.Context(), clients, out, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "Only print the nodes to be moved.")
+	return cmd
+}
+
+func runRebalance(ctx context.Context, clients *Clients, out io.Writer, dryRun bool) error {
+	c, err := getClusterPartitions(ctx, clients)
+	if err != nil {
+		return err
+	}
+	partitions := make(map[string]sets.String)
+	for i := range c.schedulers {
+		if scheduler := &c.schedulers[i]; schemaintainer.IsSchedulerActive(scheduler) {
+			partitions[scheduler.Name] = c.getPartition(scheduler.Name).allNodes()
+		}
+	}
+
+	moves := planRebalance(partitions)
+	if len(moves) == 0 {
+		fmt.Fprintf(out, "Partitions of %d active schedulers are balanced\n", len(partitions))
+		return nil
+	}
+	for _, move := range moves {
+		if dryRun {
+			fmt.Fprintf(out, "node %s would be moved from %s to %s\n", move.node, move.from, move.to)
+			continue
+		}
+		if err := moveNode(ctx, clients, c.getPartition(move.from), move); err != nil {
+			return fmt.Errorf("failed to move node %s from %s to %s: %v", move.node, move.from, move.to, err)
+		}
+		fmt.Fprintf(out, "node %s moved from %s to %s\n", move.node, move.from, move.to)
+	}
+	return nil
+}
+
+func moveNode(ctx context.Context, clients *Clients, from *partitionInfo, move nodeMove) error {
+	if from.nodes.Has(move.node) {
+		node, err := clients.Client.CoreV1().Nodes().Get(ctx, move.node, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] = move.to
+		if _, err := clients.Client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	if from.nmNodes.Has(move.node) {
+		nmNode, err := clients.CrdClient.NodeV1alpha1().NMNodes().Get(ctx, move.node, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if nmNode.Annotations == nil {
+			nmNode.Annotations = make(map[string]string)
+		}
+		nmNode.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] = move.to
+		if _, err := clients.CrdClient.NodeV1alpha1().NMNodes().Update(ctx, nmNode, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	tests := []struct {
+		name       string
+		partitions map[string]sets.String
+		wantMoves  int
+		wantSizes  map[string]int
+	}{
+		{
+			name:       "single scheduler",
+			partitions: map[string]sets.String{"s1": sets.NewString("n1", "n2")},
+			wantMoves:  0,
+			wantSizes:  map[string]int{"s1": 2},
+		},
+		{
+			name:       "balanced schedulers",
+			partitions: map[string]sets.String{"s1": sets.NewString("n1", "n2"), "s2": sets.NewString("n3")},
+			wantMoves:  0,
+			wantSizes:  map[string]int{"s1": 2, "s2": 1},
+		},
+		{
+			name: "unbalanced schedulers",
+			partitions: map[string]sets.String{
+				"s1": sets.NewString("n1", "n2", "n3", "n4", "n5", "n6"),
+				"s2": sets.NewString("n7"),
+				"s3": sets.NewString(),
+			},
+			wantMoves: 3,
+			wantSizes: map[string]int{"s1": 3, "s2": 2, "s3": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moves := planRebalance(tt.partitions)
+			if len(moves) != tt.wantMoves {
+				t.Errorf("expected %d moves, got %v", tt.wantMoves, moves)
+			}
+			sizes := make(map[string]int)
+			for name, nodes := range tt.partitions {
+				sizes[name] = nodes.Len()
+			}
+			for _, move := range moves {
+				if !tt.partitions[move.from].Has(move.node) {
+					t.Errorf("node %s is not in partition %s", move.node, move.from)
+				}
+				sizes[move.from]--
+				sizes[move.to]++
+			}
+			for name, size := range tt.wantSizes {
+				if sizes[name] != size {
+					t.Errorf("expected %d nodes in %s, got %d", size, name, sizes[name])
+				}
+			}
+		})
+	}
+}
+
+func TestRunRebalance(t *testing.T) {
+	now := metav1.Now()
+	expired := metav1.NewTime(now.Add(-time.Hour))
+	var crdObjects []runtime.Object
+	for name, lastUpdate := range map[string]*metav1.Time{"s1": &now, "s2": &now, "inactive": &expired} {
+		crdObjects = append(crdObjects, &schedulingv1a1.Scheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     schedulingv1a1.SchedulerStatus{LastUpdateTime: lastUpdate},
+		})
+	}
+	var objects []runtime.Object
+	for i := 0; i < 4; i++ {
+		objects = append(objects, &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("n%d", i),
+			Annotations: map[string]string{nodeutil.GodelSchedulerNodeAnnotationKey: "s1"},
+		}})
+	}
+	objects = append(objects, &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "n-inactive",
+		Annotations: map[string]string{nodeutil.GodelSchedulerNodeAnnotationKey: "inactive"},
+	}})
+	clients := &Clients{Client: clientsetfake.NewSimpleClientset(objects...), CrdClient: godelclientfake.NewSimpleClientset(crdObjects...)}
+
+	out := &bytes.Buffer{}
+	if err := runRebalance(context.Background(), clients, out, true); err != nil {
+		t.Fatal(err)
+	}
+	c, err := getClusterPartitions(context.Background(), clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.getPartition("s1").nodes.Len(); got != 4 {
+		t.Errorf("nodes should not be moved in dry run, got %d nodes in s1, output: %s", got, out.String())
+	}
+
+	if err := runRebalance(context.Background(), clients, out, false); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = getClusterPartitions(context.Background(), clients); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]int{"s1": 2, "s2": 2, "inactive": 1} {
+		if got := c.getPartition(name).nodes.Len(); got != want {
+			t.Errorf("expected %d nodes in %s, got %d, output: %s", want, name, got, out.String())
+		}
+	}
+}
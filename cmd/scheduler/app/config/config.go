@@ -24,9 +24,11 @@ import (
 	apiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection"
 
+	quotaclient "github.com/kubewharf/godel-scheduler/pkg/client/clientset/versioned"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
 )
@@ -45,6 +47,11 @@ type Config struct {
 	KatalystCrdClient          katalystclient.Interface
 	KatalystCrdInformerFactory katalystinformers.SharedInformerFactory
 
+	// QuotaClient is used to watch ElasticQuotas.
+	QuotaClient quotaclient.Interface
+	// MetadataClient is used to watch the metadata of nodes out of the partition of scheduler.
+	MetadataClient metadata.Interface
+
 	// LoopbackClientConfig is a config for a privileged loopback connection
 	LoopbackClientConfig *restclient.Config
 
@@ -61,6 +68,9 @@ type Config struct {
 	// It will be removed once the migration for events from core API to events API is done.
 	// More details can be found at https://github.com/kubernetes/enhancements/blob/master/keps/sig-instrumentation/383-new-event-api-ga-graduation/README.md
 	EventBroadcaster cmdutil.EventBroadcasterAdapter
+
+	// CacheCheckpoint is used to checkpoint and restore the scheduler cache.
+	CacheCheckpoint *cmdutil.CacheCheckpointOptions
 }
 
 type completedConfig struct {
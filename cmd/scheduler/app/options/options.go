@@ -36,6 +36,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	clientset "k8s.io/client-go/kubernetes"
 	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/metadata"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -49,6 +50,8 @@ import (
 	schedulerappconfig "github.com/kubewharf/godel-scheduler/cmd/scheduler/app/config"
 	"github.com/kubewharf/godel-scheduler/cmd/scheduler/app/util/ports"
 	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
+	quotaclient "github.com/kubewharf/godel-scheduler/pkg/client/clientset/versioned"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	godelschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	godelschedulerscheme "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config/scheme"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config/validation"
@@ -77,6 +80,8 @@ type Options struct {
 	// scheduler renew period in seconds
 	SchedulerRenewIntervalSeconds int64
 
+	CacheCheckpoint *cmdutil.CacheCheckpointOptions
+
 	// TODO: The following fields are reserved for backward compatibility only.
 	// We need to remove this logic in the near future.
 	UnitMaxBackoffSeconds         int64
@@ -113,6 +118,7 @@ func NewOptions() (*Options, error) {
 		Authentication:                apiserveroptions.NewDelegatingAuthenticationOptions(),
 		Authorization:                 apiserveroptions.NewDelegatingAuthorizationOptions(),
 		SchedulerRenewIntervalSeconds: godelschedulerconfig.DefaultRenewIntervalInSeconds,
+		CacheCheckpoint:               cmdutil.NewCacheCheckpointOptions(),
 	}
 
 	o.Authentication.TolerateInClusterLookupFailure = true
@@ -175,6 +181,7 @@ func (o *Options) Flags() (nfs cliflag.NamedFlagSets) {
 	o.Authentication.AddFlags(nfs.FlagSet("authentication"))
 	o.Authorization.AddFlags(nfs.FlagSet("authorization"))
 	o.ComponentConfig.Tracer.AddFlags(nfs.FlagSet("tracer"))
+	o.CacheCheckpoint.AddFlags(nfs.FlagSet("cache checkpoint"))
 
 	BindFlags(&o.ComponentConfig.LeaderElection, nfs.FlagSet("leader election"))
 	utilfeature.DefaultMutableFeatureGate.AddFlag(nfs.FlagSet("feature gate"))
@@ -198,6 +205,7 @@ func (o *Options) addSchedulerConfigFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.ComponentConfig.GodelSchedulerName, "godel-scheduler-name", o.ComponentConfig.GodelSchedulerName, "godel scheduler name, to register scheduler crd.")
 	fs.StringVar(o.ComponentConfig.SchedulerName, "scheduler-name", *o.ComponentConfig.SchedulerName, "components will deal with pods that pod.Spec.SchedulerName is equal to scheduler-name / is default-scheduler or empty. This parameter overrides the value defined in config file, which is specified in --config.")
 	fs.StringVar(o.ComponentConfig.SubClusterKey, "sub-cluster-key", *o.ComponentConfig.SubClusterKey, "the key to determine a sub cluster. This parameter overrides the value defined in config file, which is specified in --config.")
+	fs.Int32Var(o.ComponentConfig.PluginMetricsSamplePercent, "plugin-metrics-sample-percent", *o.ComponentConfig.PluginMetricsSamplePercent, "percentage of scheduling cycles in which plugin metrics are recorded, in the range [0-100]. This parameter overrides the value defined in config file, which is specified in --config.")
 }
 
 // ApplyTo applies the scheduler options to the given scheduler app configuration.
@@ -288,6 +296,9 @@ func (o *Options) ApplyTo(c *schedulerappconfig.Config) error {
 			if *o.ComponentConfig.SubClusterKey != godelschedulerconfig.DefaultSubClusterKey {
 				toUse.SubClusterKey = o.ComponentConfig.SubClusterKey
 			}
+			if *o.ComponentConfig.PluginMetricsSamplePercent != godelschedulerconfig.DefaultPluginMetricsSamplePercent {
+				toUse.PluginMetricsSamplePercent = o.ComponentConfig.PluginMetricsSamplePercent
+			}
 		}
 		// 5. Godel Profiles (Default)
 		{
@@ -429,7 +440,7 @@ func (o *Options) Config() (*schedulerappconfig.Config, error) {
 
 	// Prepare kube clients.
 	// client, leaderElectionClient, eventClient, err := createClients(c.ComponentConfig.ClientConnection, o.Master, c.ComponentConfig.LeaderElection.RenewDeadline.Duration)
-	client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, err := createClients(c.ComponentConfig.ClientConnection, o.Master, c.ComponentConfig.LeaderElection.RenewDeadline.Duration)
+	client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, quotaClient, metadataClient, err := createClients(c.ComponentConfig.ClientConnection, o.Master, c.ComponentConfig.LeaderElection.RenewDeadline.Duration)
 	if err != nil {
 		return nil, err
 	}
@@ -448,14 +459,24 @@ func (o *Options) Config() (*schedulerappconfig.Config, error) {
 	}
 
 	c.Client = client
-	c.InformerFactory = cmdutil.NewInformerFactory(client, 0)
 	c.GodelCrdClient = godelCrdClient
-	c.GodelCrdInformerFactory = crdinformers.NewSharedInformerFactory(c.GodelCrdClient, 0)
-
 	c.KatalystCrdClient = katalystCrdClient
-	c.KatalystCrdInformerFactory = katalystinformers.NewSharedInformerFactory(c.KatalystCrdClient, 0)
+	if utilfeature.DefaultFeatureGate.Enabled(features.PartitionScopedInformers) {
+		schedulerName := c.ComponentConfig.GodelSchedulerName
+		c.InformerFactory = cmdutil.NewPartitionScopedInformerFactory(client, schedulerName, 0)
+		c.GodelCrdInformerFactory = cmdutil.NewPartitionScopedCrdInformerFactory(c.GodelCrdClient, schedulerName, 0)
+		c.KatalystCrdInformerFactory = cmdutil.NewPartitionScopedKatalystCrdInformerFactory(c.KatalystCrdClient, schedulerName, 0)
+	} else {
+		c.InformerFactory = cmdutil.NewInformerFactory(client, 0)
+		c.GodelCrdInformerFactory = crdinformers.NewSharedInformerFactory(c.GodelCrdClient, 0)
+		c.KatalystCrdInformerFactory = katalystinformers.NewSharedInformerFactory(c.KatalystCrdClient, 0)
+	}
+
+	c.QuotaClient = quotaClient
+	c.MetadataClient = metadataClient
 
 	c.LeaderElection = leaderElectionConfig
+	c.CacheCheckpoint = o.CacheCheckpoint
 
 	return c, nil
 }
@@ -495,7 +516,7 @@ func makeLeaderElectionConfig(config componentbaseconfig.LeaderElectionConfigura
 
 // createClients creates a kube client and an event client from the given config and masterOverride.
 // TODO remove masterOverride when CLI flags are removed.
-func createClients(config componentbaseconfig.ClientConnectionConfiguration, masterOverride string, timeout time.Duration) (clientset.Interface, clientset.Interface, clientset.Interface, godelclient.Interface, katalystclient.Interface, error) {
+func createClients(config componentbaseconfig.ClientConnectionConfiguration, masterOverride string, timeout time.Duration) (clientset.Interface, clientset.Interface, clientset.Interface, godelclient.Interface, katalystclient.Interface, quotaclient.Interface, metadata.Interface, error) {
 	if len(config.Kubeconfig) == 0 && len(masterOverride) == 0 {
 		klog.InfoS("WARN: Neither --kubeconfig nor --master was specified. Using default API client. This might not work")
 	}
@@ -506,7 +527,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Kubeconfig},
 		&clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: masterOverride}}).ClientConfig()
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	kubeConfig.DisableCompression = true
@@ -517,7 +538,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 
 	client, err := clientset.NewForConfig(restclient.AddUserAgent(kubeConfig, "scheduler"))
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	// shallow copy, do not modify the kubeConfig.Timeout.
@@ -525,13 +546,13 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 	restConfig.Timeout = timeout
 	leaderElectionClient, err := clientset.NewForConfig(restclient.AddUserAgent(&restConfig, "leader-election"))
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	utilruntime.Must(godelclientscheme.AddToScheme(clientsetscheme.Scheme))
 	eventClient, err := clientset.NewForConfig(kubeConfig)
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	// This creates a client, first loading any specified kubeconfig
@@ -540,7 +561,7 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Kubeconfig},
 		&clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: masterOverride}}).ClientConfig()
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	crdKubeConfig.DisableCompression = true
@@ -549,9 +570,21 @@ func createClients(config componentbaseconfig.ClientConnectionConfiguration, mas
 
 	godelCrdClient, err := godelclient.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "scheduler"))
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, nil, err
 	}
 
 	katalystCrdClient, err := katalystclient.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "scheduler"))
-	return client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, nil
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+
+	quotaClient, err := quotaclient.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "scheduler"))
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	metadataClient, err := metadata.NewForConfig(restclient.AddUserAgent(crdKubeConfig, "scheduler"))
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	return client, leaderElectionClient, eventClient, godelCrdClient, katalystCrdClient, quotaClient, metadataClient, nil
 }
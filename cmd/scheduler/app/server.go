@@ -30,6 +30,8 @@ import (
 	schedulerserverconfig "github.com/kubewharf/godel-scheduler/cmd/scheduler/app/config"
 	"github.com/kubewharf/godel-scheduler/cmd/scheduler/app/options"
 	"github.com/kubewharf/godel-scheduler/cmd/scheduler/app/util/configz"
+	quotainformers "github.com/kubewharf/godel-scheduler/pkg/client/informers/externalversions/quota/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	godelscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler"
 	godelschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
@@ -46,6 +48,9 @@ import (
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/mux"
 	"k8s.io/apiserver/pkg/server/routes"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/tools/leaderelection"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -160,6 +165,27 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 
 	eventRecorder := getEventRecorder(&cc)
 
+	schedulerOptions := []godelscheduler.Option{
+		godelscheduler.WithDefaultProfile(cc.ComponentConfig.DefaultProfile),
+		godelscheduler.WithSubClusterProfiles(cc.ComponentConfig.SubClusterProfiles),
+		godelscheduler.WithRenewInterval(cc.ComponentConfig.SchedulerRenewIntervalSeconds),
+		godelscheduler.WithSubClusterKey(*cc.ComponentConfig.SubClusterKey),
+		godelscheduler.WithPluginMetricsSamplePercent(*cc.ComponentConfig.PluginMetricsSamplePercent),
+	}
+	var elasticQuotaInformer cache.SharedIndexInformer
+	if utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuota) {
+		elasticQuotaInformer = quotainformers.NewElasticQuotaInformer(cc.QuotaClient, 0, cache.Indexers{})
+		schedulerOptions = append(schedulerOptions, godelscheduler.WithElasticQuotaInformer(elasticQuotaInformer))
+	}
+	var nodeMetadataInformer, podMetadataInformer informers.GenericInformer
+	if utilfeature.DefaultFeatureGate.Enabled(features.PartitionScopedInformers) {
+		nodeMetadataInformer = cmdutil.NewNodeMetadataInformer(cc.MetadataClient, 0)
+		podMetadataInformer = cmdutil.NewPodMetadataInformer(cc.MetadataClient, cc.ComponentConfig.GodelSchedulerName, 0)
+		schedulerOptions = append(schedulerOptions,
+			godelscheduler.WithNodeMetadataInformer(nodeMetadataInformer),
+			godelscheduler.WithPodMetadataInformer(podMetadataInformer))
+	}
+
 	// Create the scheduler.
 	sched, err := godelscheduler.New(
 		cc.ComponentConfig.GodelSchedulerName,
@@ -172,10 +198,7 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 		ctx.Done(),
 		eventRecorder,
 		time.Duration(cc.ComponentConfig.ReservationTimeOutSeconds)*time.Second,
-		godelscheduler.WithDefaultProfile(cc.ComponentConfig.DefaultProfile),
-		godelscheduler.WithSubClusterProfiles(cc.ComponentConfig.SubClusterProfiles),
-		godelscheduler.WithRenewInterval(cc.ComponentConfig.SchedulerRenewIntervalSeconds),
-		godelscheduler.WithSubClusterKey(*cc.ComponentConfig.SubClusterKey),
+		schedulerOptions...,
 	)
 	if err != nil {
 		return err
@@ -193,7 +216,8 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 	// Start up the healthz server.
 	if cc.InsecureServing != nil {
 		separateMetrics := cc.InsecureMetricsServing != nil
-		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, separateMetrics, checks...), nil, nil)
+		// The insecure server has no authentication, never serve dry runs on it.
+		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, nil, separateMetrics, checks...), nil, nil)
 		if err := cc.InsecureServing.Serve(handler, 0, ctx.Done()); err != nil {
 			return fmt.Errorf("failed to start healthz server: %v", err)
 		}
@@ -205,7 +229,7 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 		}
 	}
 	if cc.SecureServing != nil {
-		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, false, checks...), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
+		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, sched, false, checks...), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
 		// TODO: handle stoppedCh returned by c.SecureServing.Serve
 		if _, _, err := cc.SecureServing.Serve(handler, 0, ctx.Done()); err != nil {
 			// fail early for secure handlers, removing the old error loop from above
@@ -213,15 +237,45 @@ func Run(ctx context.Context, cc schedulerserverconfig.CompletedConfig) error {
 		}
 	}
 
+	// Restore the scheduler cache before the informers deliver any event.
+	restored := cc.CacheCheckpoint.Enabled() && sched.RestoreCacheFromCheckpoint(cc.CacheCheckpoint.Path, cc.CacheCheckpoint.MaxAge)
+
 	// Start all informers.
+	if nodeMetadataInformer != nil {
+		go nodeMetadataInformer.Informer().Run(ctx.Done())
+		go podMetadataInformer.Informer().Run(ctx.Done())
+	}
 	cc.InformerFactory.Start(ctx.Done())
 	cc.GodelCrdInformerFactory.Start(ctx.Done())
 	cc.KatalystCrdInformerFactory.Start(ctx.Done())
+	if elasticQuotaInformer != nil {
+		go elasticQuotaInformer.Run(ctx.Done())
+	}
 
 	// Wait for all caches to sync before scheduling.
-	cc.InformerFactory.WaitForCacheSync(ctx.Done())
-	cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
-	cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
+	waitForCacheSync := func() {
+		cc.InformerFactory.WaitForCacheSync(ctx.Done())
+		cc.GodelCrdInformerFactory.WaitForCacheSync(ctx.Done())
+		cc.KatalystCrdInformerFactory.WaitForCacheSync(ctx.Done())
+		if elasticQuotaInformer != nil {
+			cache.WaitForCacheSync(ctx.Done(), elasticQuotaInformer.HasSynced)
+		}
+		if nodeMetadataInformer != nil {
+			cache.WaitForCacheSync(ctx.Done(), nodeMetadataInformer.Informer().HasSynced, podMetadataInformer.Informer().HasSynced)
+		}
+	}
+	if restored {
+		// The restored cache is reconciled in background, scheduling goes on in conservative mode meanwhile.
+		go func() {
+			waitForCacheSync()
+			sched.FinishCacheReconciling()
+		}()
+	} else {
+		waitForCacheSync()
+	}
+	if cc.CacheCheckpoint.Enabled() {
+		sched.RunCacheCheckpointer(cc.CacheCheckpoint.Path, cc.CacheCheckpoint.Interval, ctx.Done())
+	}
 
 	run := func(ctx context.Context) {
 		// Register the tracer when we become the leader.
@@ -311,13 +365,17 @@ func newMetricsHandler(config *godelschedulerconfig.GodelSchedulerConfiguration)
 
 // newHealthzHandler creates a healthz server from the config, and will also
 // embed the metrics handler if the healthz and metrics address configurations
-// are the same.
-func newHealthzHandler(config *godelschedulerconfig.GodelSchedulerConfiguration, separateMetrics bool, checks ...healthz.HealthChecker) http.Handler {
+// are the same. The dry-run endpoint is served as well if it's enabled and sched
+// is given, which should only be done for the secure server.
+func newHealthzHandler(config *godelschedulerconfig.GodelSchedulerConfiguration, sched *godelscheduler.Scheduler, separateMetrics bool, checks ...healthz.HealthChecker) http.Handler {
 	pathRecorderMux := mux.NewPathRecorderMux(ComponentName)
 	healthz.InstallHandler(pathRecorderMux, checks...)
 	if !separateMetrics {
 		installMetricHandler(pathRecorderMux)
 	}
+	if sched != nil && utilfeature.DefaultFeatureGate.Enabled(features.SchedulerDryRun) {
+		pathRecorderMux.Handle(godelscheduler.DryRunPath, sched.DryRunHandler())
+	}
 	if *config.EnableProfiling {
 		routes.Profiling{}.Install(pathRecorderMux)
 		if *config.EnableContentionProfiling {
@@ -4,6 +4,9 @@ set -e
 set -o pipefail
 
 GO111MODULE=on go install -mod=vendor k8s.io/code-generator/cmd/{deepcopy-gen,conversion-gen,defaulter-gen}
+# The client generators are not vendored, install them with the same version of code-generator.
+CODE_GENERATOR_VERSION=$(go list -mod=vendor -m -f '{{.Version}}' k8s.io/code-generator)
+GO111MODULE=on go install k8s.io/code-generator/cmd/{client-gen,lister-gen,informer-gen}@"${CODE_GENERATOR_VERSION}"
 
 GOPATH=$(go env GOPATH)
 
@@ -81,6 +84,39 @@ echo "Generating controller conversions"
  -h "$PWD"/hack/boilerplate.go.txt
 }
 
+function generateQuotaAPI() {
+echo "Generating quota api deepcopy funcs"
+"${GOPATH}"/bin/deepcopy-gen --input-dirs \
+ github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1 \
+ -O zz_generated.deepcopy \
+ --output-base=./ \
+ -h "$PWD"/hack/boilerplate.go.txt
+
+echo "Generating quota api clientset"
+"${GOPATH}"/bin/client-gen --input-base="" \
+ --input github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1 \
+ --clientset-name versioned \
+ --output-package github.com/kubewharf/godel-scheduler/pkg/client/clientset \
+ --output-base=./ \
+ -h "$PWD"/hack/boilerplate.go.txt
+
+echo "Generating quota api listers"
+"${GOPATH}"/bin/lister-gen --input-dirs \
+ github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1 \
+ --output-package github.com/kubewharf/godel-scheduler/pkg/client/listers \
+ --output-base=./ \
+ -h "$PWD"/hack/boilerplate.go.txt
+
+echo "Generating quota api informers"
+"${GOPATH}"/bin/informer-gen --input-dirs \
+ github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1 \
+ --versioned-clientset-package github.com/kubewharf/godel-scheduler/pkg/client/clientset/versioned \
+ --listers-package github.com/kubewharf/godel-scheduler/pkg/client/listers \
+ --output-package github.com/kubewharf/godel-scheduler/pkg/client/informers \
+ --output-base=./ \
+ -h "$PWD"/hack/boilerplate.go.txt
+}
+
 if [[ "$1" == "scheduler"  || "$1" == "" ]] ; then
     generateSchedulerConfig
 fi
@@ -93,10 +129,15 @@ if [[ "$1" == "controller"  || "$1" == "" ]] ; then
     generateControllerConfig
 fi
 
+if [[ "$1" == "quota"  || "$1" == "" ]] ; then
+    generateQuotaAPI
+fi
+
 echo "
 !!!Attention!!!
 Code generation finished, you need to copy the generated files to the
-pkg/godel-scheduler/apis/config directory. There are some manual changes
+pkg/godel-scheduler/apis/config directory, and the quota api files to
+pkg/apis/quota and pkg/client. There are some manual changes
 in the generated conversion code, be careful to merge the changes.
 Don't forget to delete the directory github.com/kubewharf/godel-scheduler before committing.
 "
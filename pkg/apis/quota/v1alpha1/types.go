@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,shortName=eq
+
+// ElasticQuota bounds the resources requested by pods of a group of namespaces. The group is
+// guaranteed its min resources, and may borrow the idle min resources of other groups up to its max.
+// Borrowed resources are reclaimed by preemption when their owners need them.
+type ElasticQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the quota.
+	Spec ElasticQuotaSpec `json:"spec,omitempty"`
+}
+
+// ElasticQuotaSpec defines the namespaces sharing the quota and the limits of each resource type.
+type ElasticQuotaSpec struct {
+	// Namespaces are the namespaces sharing the quota. A namespace should belong to one quota at most.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Guaranteed limits the resources requested by guaranteed pods.
+	// +optional
+	Guaranteed QuotaLimits `json:"guaranteed,omitempty"`
+
+	// BestEffort limits the resources requested by best-effort pods.
+	// +optional
+	BestEffort QuotaLimits `json:"bestEffort,omitempty"`
+}
+
+// QuotaLimits is the min and max of resources.
+type QuotaLimits struct {
+	// Min is the guaranteed resources, resources absent are not guaranteed.
+	// +optional
+	Min v1.ResourceList `json:"min,omitempty"`
+
+	// Max is the upper bound of resources, resources absent are not limited.
+	// +optional
+	Max v1.ResourceList `json:"max,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ElasticQuotaList is a collection of ElasticQuota.
+type ElasticQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ElasticQuota.
+	Items []ElasticQuota `json:"items"`
+}
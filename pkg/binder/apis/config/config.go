@@ -64,6 +64,10 @@ type GodelBinderConfiguration struct {
 	// reserved resources will be released after a period of time.
 	ReservationTimeOutSeconds int64
 
+	// PluginMetricsSamplePercent is the percentage of binding cycles in which the duration and
+	// status of every plugin is recorded. 0 disables plugin metrics, 100 records every cycle.
+	PluginMetricsSamplePercent *int32
+
 	Profile *GodelBinderProfile `json:"profile"`
 }
 
@@ -37,6 +37,9 @@ const (
 
 	BinderDefaultLockObjectName      = "binder"
 	DefaultReservationTimeOutSeconds = 60
+	// DefaultPluginMetricsSamplePercent is the default percentage of binding cycles
+	// in which per-plugin metrics are recorded.
+	DefaultPluginMetricsSamplePercent = 100
 
 	// DefaultGodelBinderAddress is the default address for the scheduler status server.
 	// May be overridden by a flag at startup.
@@ -143,4 +146,8 @@ func SetDefaults_GodelBinderConfiguration(cfg *GodelBinderConfiguration) {
 	if cfg.ReservationTimeOutSeconds == 0 {
 		cfg.ReservationTimeOutSeconds = DefaultReservationTimeOutSeconds
 	}
+	if cfg.PluginMetricsSamplePercent == nil {
+		defaultValue := int32(DefaultPluginMetricsSamplePercent)
+		cfg.PluginMetricsSamplePercent = &defaultValue
+	}
 }
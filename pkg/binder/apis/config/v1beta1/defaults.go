@@ -37,6 +37,8 @@ const (
 
 	DefaultReservationTimeOutSeconds = 60
 
+	DefaultPluginMetricsSamplePercent = 100
+
 	BinderDefaultLockObjectName = "godel-binder"
 )
 
@@ -131,4 +133,9 @@ func SetDefaults_GodelBinderConfiguration(cfg *GodelBinderConfiguration) {
 	}
 
 	cfg.VolumeBindingTimeoutSeconds = VolumeBindingTimeoutSeconds
+
+	if cfg.PluginMetricsSamplePercent == nil {
+		defaultValue := int32(DefaultPluginMetricsSamplePercent)
+		cfg.PluginMetricsSamplePercent = &defaultValue
+	}
 }
@@ -65,6 +65,10 @@ type GodelBinderConfiguration struct {
 	// reserved resources will be released after a period of time.
 	ReservationTimeOutSeconds int64 `json:"reservationTimeOutSeconds,omitempty"`
 
+	// PluginMetricsSamplePercent is the percentage of binding cycles in which the duration and
+	// status of every plugin is recorded. 0 disables plugin metrics, 100 records every cycle.
+	PluginMetricsSamplePercent *int32 `json:"pluginMetricsSamplePercent,omitempty"`
+
 	Profile *GodelBinderProfile `json:"profile"`
 }
 
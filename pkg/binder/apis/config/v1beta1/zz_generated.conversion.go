@@ -120,6 +120,7 @@ func autoConvert_v1beta1_GodelBinderConfiguration_To_config_GodelBinderConfigura
 	out.VolumeBindingTimeoutSeconds = in.VolumeBindingTimeoutSeconds
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
 	out.ReservationTimeOutSeconds = in.ReservationTimeOutSeconds
+	out.PluginMetricsSamplePercent = (*int32)(unsafe.Pointer(in.PluginMetricsSamplePercent))
 	out.Profile = (*config.GodelBinderProfile)(unsafe.Pointer(in.Profile))
 	return nil
 }
@@ -139,6 +140,7 @@ func autoConvert_config_GodelBinderConfiguration_To_v1beta1_GodelBinderConfigura
 	out.VolumeBindingTimeoutSeconds = in.VolumeBindingTimeoutSeconds
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
 	out.ReservationTimeOutSeconds = in.ReservationTimeOutSeconds
+	out.PluginMetricsSamplePercent = (*int32)(unsafe.Pointer(in.PluginMetricsSamplePercent))
 	out.Profile = (*GodelBinderProfile)(unsafe.Pointer(in.Profile))
 	return nil
 }
@@ -41,6 +41,11 @@ func (in *GodelBinderConfiguration) DeepCopyInto(out *GodelBinderConfiguration)
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
 	}
+	if in.PluginMetricsSamplePercent != nil {
+		in, out := &in.PluginMetricsSamplePercent, &out.PluginMetricsSamplePercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Profile != nil {
 		in, out := &in.Profile, &out.Profile
 		*out = new(GodelBinderProfile)
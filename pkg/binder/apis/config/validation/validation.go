@@ -46,5 +46,10 @@ func ValidateGodelBinderConfiguration(cc *config.GodelBinderConfiguration) field
 			cc.VolumeBindingTimeoutSeconds, "must be greater than 0"))
 	}
 
+	if cc.PluginMetricsSamplePercent != nil && (*cc.PluginMetricsSamplePercent < 0 || *cc.PluginMetricsSamplePercent > 100) {
+		errs = append(errs, field.Invalid(field.NewPath("pluginMetricsSamplePercent"),
+			*cc.PluginMetricsSamplePercent, "not in valid range [0-100]"))
+	}
+
 	return errs
 }
@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	"github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores"
 	deletedmarkerstore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/deleted_marker_store"
@@ -33,6 +34,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	unitstatus "github.com/kubewharf/godel-scheduler/pkg/util/unitstatus"
 )
@@ -53,6 +55,11 @@ type binderCache struct {
 
 	handler commoncache.CacheHandler
 	mu      *sync.RWMutex
+
+	// reconciler is set when the cache is restored from checkpoint, until it's reconciled with informers.
+	reconciler *commoncache.CheckpointReconciler
+	// objects is set when CacheCheckpoint is enabled, to record the objects not kept by stores as they are.
+	objects *commoncache.CheckpointObjects
 }
 
 func newBinderCache(handler commoncache.CacheHandler) *binderCache {
@@ -62,6 +69,9 @@ func newBinderCache(handler commoncache.CacheHandler) *binderCache {
 		handler: handler,
 		mu:      handler.Mutex(),
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.CacheCheckpoint) {
+		bc.objects = commoncache.NewCheckpointObjects()
+	}
 
 	// NodeStore and PodStore are mandatory, so we don't care if they are nil.
 	nodeStore, podStore := bc.CommonStoresSwitch.Find(nodestore.Name), bc.CommonStoresSwitch.Find(podstore.Name)
@@ -19,6 +19,7 @@ package cache
 import (
 	"fmt"
 
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -75,6 +76,7 @@ func (cache *binderCache) DeletePod(pod *v1.Pod) error {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 
+	cache.confirmRestored(commoncache.CheckpointKindPod, pod)
 	// ATTENTION: In order to handle the case of event merging between update and delete, the pod stored in the cache
 	// should be used if the corresponding pod exists in the cache.
 	{
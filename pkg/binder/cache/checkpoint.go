@@ -0,0 +1,342 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+
+	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	nodestore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/node_store"
+	podstore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/pod_store"
+	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/unit_status_store"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+)
+
+var _ commoncache.Checkpointer = &binderCache{}
+
+func (cache *binderCache) Checkpoint() *commoncache.Checkpoint {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	checkpoint := &commoncache.Checkpoint{
+		Version:       commoncache.CheckpointVersion,
+		ComponentName: cache.handler.ComponentName(),
+		Timestamp:     metav1.Now(),
+	}
+
+	nodeStore := cache.CommonStoresSwitch.Find(nodestore.Name).(*nodestore.NodeStore)
+	nodeStore.Store.Range(func(_ string, obj generationstore.StoredObj) {
+		nodeInfo := obj.(framework.NodeInfo)
+		if node := nodeInfo.GetNode(); node != nil {
+			checkpoint.Nodes = append(checkpoint.Nodes, node)
+		}
+		if nmNode := nodeInfo.GetNMNode(); nmNode != nil {
+			checkpoint.NMNodes = append(checkpoint.NMNodes, nmNode)
+		}
+		if cnr := nodeInfo.GetCNR(); cnr != nil {
+			checkpoint.CNRs = append(checkpoint.CNRs, cnr)
+		}
+	})
+	podStore := cache.CommonStoresSwitch.Find(podstore.Name).(*podstore.PodStore)
+	for key, ps := range podStore.PodStates {
+		// The assumptions in flight are not recorded.
+		if podStore.AssumedPods[key] {
+			continue
+		}
+		checkpoint.Pods = append(checkpoint.Pods, ps.Pod)
+	}
+	checkpoint.Units = cache.CommonStoresSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore).Store.GetUnitSchedulingStatuses()
+	cache.objects.Fill(checkpoint)
+
+	return checkpoint
+}
+
+func (cache *binderCache) Restore(checkpoint *commoncache.Checkpoint) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.reconciler != nil {
+		return fmt.Errorf("cache has been restored")
+	}
+	reconciler := commoncache.NewCheckpointReconciler()
+	restore := func(kind string, obj metav1.Object, f func(cs commonstore.Store) error) {
+		if err := cache.CommonStoresSwitch.Range(f); err != nil {
+			klog.InfoS("Failed to restore object from checkpoint", "kind", kind, "object", klog.KObj(obj), "err", err)
+			return
+		}
+		reconciler.Track(kind, obj)
+	}
+	for _, node := range checkpoint.Nodes {
+		restore(commoncache.CheckpointKindNode, node, func(cs commonstore.Store) error { return cs.AddNode(node) })
+	}
+	for _, nmNode := range checkpoint.NMNodes {
+		restore(commoncache.CheckpointKindNMNode, nmNode, func(cs commonstore.Store) error { return cs.AddNMNode(nmNode) })
+	}
+	for _, cnr := range checkpoint.CNRs {
+		restore(commoncache.CheckpointKindCNR, cnr, func(cs commonstore.Store) error { return cs.AddCNR(cnr) })
+	}
+	for _, pod := range checkpoint.Pods {
+		restore(commoncache.CheckpointKindPod, pod, func(cs commonstore.Store) error { return cs.AddPod(pod) })
+	}
+	// The objects below are recorded again, since they are not kept as they are by the stores.
+	for _, pdb := range checkpoint.PDBs {
+		cache.objects.Set(commoncache.CheckpointKindPDB, pdb)
+		restore(commoncache.CheckpointKindPDB, pdb, func(cs commonstore.Store) error { return cs.AddPDB(pdb) })
+	}
+	for _, reservation := range checkpoint.Reservations {
+		cache.objects.Set(commoncache.CheckpointKindReservation, reservation)
+		restore(commoncache.CheckpointKindReservation, reservation, func(cs commonstore.Store) error { return cs.AddReservation(reservation) })
+	}
+	unitStatusStore := cache.CommonStoresSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore)
+	for unitKey, status := range checkpoint.Units {
+		unitStatusStore.SetUnitSchedulingStatus(unitKey, status)
+	}
+	cache.reconciler = reconciler
+
+	klog.InfoS("Restored binder cache from checkpoint", "timestamp", checkpoint.Timestamp,
+		"numNodes", len(checkpoint.Nodes), "numNMNodes", len(checkpoint.NMNodes), "numCNRs", len(checkpoint.CNRs),
+		"numPods", len(checkpoint.Pods), "numUnits", len(checkpoint.Units),
+		"numPDBs", len(checkpoint.PDBs), "numReservations", len(checkpoint.Reservations))
+	return nil
+}
+
+func (cache *binderCache) FinishReconciling() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.reconciler == nil {
+		return 0
+	}
+	count := 0
+	cache.reconciler.Range(func(kind, key string, obj interface{}) {
+		count++
+		klog.V(4).InfoS("Removing stale object restored from checkpoint", "kind", kind, "key", key)
+		var err error
+		switch kind {
+		case commoncache.CheckpointKindNode:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNode(obj.(*v1.Node)) })
+		case commoncache.CheckpointKindNMNode:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNMNode(obj.(*nodev1alpha1.NMNode)) })
+		case commoncache.CheckpointKindCNR:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.DeleteCNR(obj.(*katalystv1alpha1.CustomNodeResource))
+			})
+		case commoncache.CheckpointKindPod:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePod(obj.(*v1.Pod)) })
+		case commoncache.CheckpointKindPDB:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePDB(obj.(*policy.PodDisruptionBudget)) })
+		case commoncache.CheckpointKindReservation:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.DeleteReservation(obj.(*schedulingv1a1.Reservation))
+			})
+		}
+		if err != nil {
+			klog.InfoS("Failed to remove stale object restored from checkpoint", "kind", kind, "key", key, "err", err)
+		}
+	})
+	cache.reconciler = nil
+	return count
+}
+
+func (cache *binderCache) Reconciling() bool {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.reconciler != nil
+}
+
+// confirmRestored returns the restored object if the cache is reconciling and the object is restored from checkpoint.
+func (cache *binderCache) confirmRestored(kind string, obj metav1.Object) (metav1.Object, bool) {
+	if cache.reconciler == nil {
+		return nil, false
+	}
+	return cache.reconciler.Confirm(kind, obj)
+}
+
+// The Add events of informers are converted to Update events for the objects restored from checkpoint,
+// and skipped if the objects are not changed since the checkpoint was taken.
+// DeletePod is defined along with the other pod event handlers.
+
+func (cache *binderCache) AddPod(pod *v1.Pod) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindPod, pod); ok {
+		if restored.GetResourceVersion() == pod.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdatePod(restored.(*v1.Pod), pod) })
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddPod(pod) })
+}
+
+func (cache *binderCache) AddNode(node *v1.Node) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindNode, node); ok {
+		if restored.GetResourceVersion() == node.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdateNode(restored.(*v1.Node), node) })
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddNode(node) })
+}
+
+func (cache *binderCache) DeleteNode(node *v1.Node) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindNode, node)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNode(node) })
+}
+
+func (cache *binderCache) AddNMNode(nmNode *nodev1alpha1.NMNode) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindNMNode, nmNode); ok {
+		if restored.GetResourceVersion() == nmNode.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+			return cs.UpdateNMNode(restored.(*nodev1alpha1.NMNode), nmNode)
+		})
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddNMNode(nmNode) })
+}
+
+func (cache *binderCache) DeleteNMNode(nmNode *nodev1alpha1.NMNode) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindNMNode, nmNode)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNMNode(nmNode) })
+}
+
+func (cache *binderCache) AddCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindCNR, cnr); ok {
+		if restored.GetResourceVersion() == cnr.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+			return cs.UpdateCNR(restored.(*katalystv1alpha1.CustomNodeResource), cnr)
+		})
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddCNR(cnr) })
+}
+
+func (cache *binderCache) DeleteCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindCNR, cnr)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteCNR(cnr) })
+}
+
+// The objects below are recorded as they are received, since their stores don't keep them for checkpoint.
+
+// addRestorable records obj and adds it to the stores, the Add event of an object restored from checkpoint is
+// converted to an Update event as the ones above.
+func (cache *binderCache) addRestorable(kind string, obj metav1.Object,
+	add func(cs commonstore.Store) error, update func(cs commonstore.Store, restored metav1.Object) error,
+) error {
+	cache.objects.Set(kind, obj)
+	if restored, ok := cache.confirmRestored(kind, obj); ok {
+		if restored.GetResourceVersion() == obj.GetResourceVersion() {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return update(cs, restored) })
+	}
+	return cache.CommonStoresSwitch.Range(add)
+}
+
+// deleteRestorable forgets obj and deletes it from the stores.
+func (cache *binderCache) deleteRestorable(kind string, obj metav1.Object, del func(cs commonstore.Store) error) error {
+	cache.objects.Delete(kind, obj)
+	cache.confirmRestored(kind, obj)
+	return cache.CommonStoresSwitch.Range(del)
+}
+
+func (cache *binderCache) AddPDB(pdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindPDB, pdb,
+		func(cs commonstore.Store) error { return cs.AddPDB(pdb) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdatePDB(restored.(*policy.PodDisruptionBudget), pdb)
+		})
+}
+
+func (cache *binderCache) UpdatePDB(oldPdb, newPdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindPDB, newPdb)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdatePDB(oldPdb, newPdb) })
+}
+
+func (cache *binderCache) DeletePDB(pdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindPDB, pdb, func(cs commonstore.Store) error { return cs.DeletePDB(pdb) })
+}
+
+func (cache *binderCache) AddReservation(request *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindReservation, request,
+		func(cs commonstore.Store) error { return cs.AddReservation(request) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdateReservation(restored.(*schedulingv1a1.Reservation), request)
+		})
+}
+
+func (cache *binderCache) UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindReservation, newRequest)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+		return cs.UpdateReservation(oldRequest, newRequest)
+	})
+}
+
+func (cache *binderCache) DeleteReservation(request *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindReservation, request,
+		func(cs commonstore.Store) error { return cs.DeleteReservation(request) })
+}
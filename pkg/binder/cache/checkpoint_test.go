@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	nodestore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/node_store"
+	pdbstore "github.com/kubewharf/godel-scheduler/pkg/binder/cache/commonstores/pdb_store"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	unitstatus "github.com/kubewharf/godel-scheduler/pkg/util/unitstatus"
+)
+
+func newCheckpointTestCache() *binderCache {
+	cacheHandler := commoncache.MakeCacheHandlerWrapper().
+		Period(10 * time.Second).PodAssumedTTL(time.Second).StopCh(make(<-chan struct{})).
+		ComponentName("godel-binder").Obj()
+	return newBinderCache(cacheHandler)
+}
+
+func makeCheckpointTestNode(name, resourceVersion string) *v1.Node {
+	node := testing_helper.MakeNode().Name(name).Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj()
+	node.ResourceVersion = resourceVersion
+	return node
+}
+
+func makeCheckpointTestPDB(name, resourceVersion, label string) *policy.PodDisruptionBudget {
+	pdb := testing_helper.MakePdb().Namespace("default").Name(name).Label("app", label).Obj()
+	pdb.ResourceVersion = resourceVersion
+	return pdb
+}
+
+func TestCheckpointRestoreAndReconcile(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CacheCheckpoint, true)()
+
+	origin := newCheckpointTestCache()
+	origin.AddNode(makeCheckpointTestNode("n1", "1"))
+	origin.AddPod(testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").ResourceVersion("1").Obj())
+	origin.AddPod(testing_helper.MakePod().Namespace("default").Name("p2").UID("p2").Node("n1").ResourceVersion("1").Obj())
+	origin.AddPDB(makeCheckpointTestPDB("pdb1", "1", "a"))
+	origin.AddPDB(makeCheckpointTestPDB("pdb2", "1", "a"))
+	origin.SetUnitSchedulingStatus("default/unit", unitstatus.ScheduledStatus)
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	if err := commoncache.SaveCheckpoint(path, origin.Checkpoint()); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	checkpoint, err := commoncache.LoadCheckpoint(path, "godel-binder", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if len(checkpoint.Nodes) != 1 || len(checkpoint.Pods) != 2 || len(checkpoint.PDBs) != 2 {
+		t.Fatalf("Expected 1 node, 2 pods and 2 pdbs in checkpoint, got %d nodes, %d pods and %d pdbs",
+			len(checkpoint.Nodes), len(checkpoint.Pods), len(checkpoint.PDBs))
+	}
+
+	cache := newCheckpointTestCache()
+	if err := cache.Restore(checkpoint); err != nil {
+		t.Fatalf("Failed to restore checkpoint: %v", err)
+	}
+	if !cache.Reconciling() {
+		t.Errorf("Expected cache to be reconciling after restored")
+	}
+	if got := cache.GetUnitSchedulingStatus("default/unit"); got != unitstatus.ScheduledStatus {
+		t.Errorf("Expected unit status to be restored, got %v", got)
+	}
+
+	// p1 and pdb1 are confirmed by informers, pdb1 is changed since the checkpoint was taken.
+	cache.AddNode(makeCheckpointTestNode("n1", "1"))
+	cache.AddPod(testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").ResourceVersion("1").Obj())
+	cache.AddPDB(makeCheckpointTestPDB("pdb1", "2", "b"))
+
+	if got := cache.FinishReconciling(); got != 2 {
+		t.Errorf("Expected 2 stale objects, got %d", got)
+	}
+	if cache.Reconciling() {
+		t.Errorf("Expected cache not to be reconciling after finished")
+	}
+	nodeStore := cache.CommonStoresSwitch.Find(nodestore.Name).(*nodestore.NodeStore)
+	if got := nodeStore.GetNodeInfo("n1").NumPods(); got != 1 {
+		t.Errorf("Expected 1 pod on n1, got %d", got)
+	}
+	pdbStore := cache.CommonStoresSwitch.Find(pdbstore.Name).(*pdbstore.PdbStore)
+	if pdbStore.Pdbs.Get(util.GetPDBKey(makeCheckpointTestPDB("pdb2", "1", "a"))) != nil {
+		t.Errorf("Expected stale pdb2 to be removed")
+	}
+	if got := cache.Checkpoint().PDBs; len(got) != 1 || got[0].ResourceVersion != "2" {
+		t.Errorf("Expected only the updated pdb1 in the next checkpoint, got %v", got)
+	}
+}
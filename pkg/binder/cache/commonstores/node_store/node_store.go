@@ -342,6 +342,12 @@ func (s *NodeStore) AssumePod(podInfo *framework.CachePodInfo) error {
 		}
 		podInfo.Pod.Annotations[podutil.MicroTopologyKey] = topo
 	}
+	if device := nonnativeresource.AssignGPUDevice(nodeInfo, podInfo.Pod); device != "" {
+		if podInfo.Pod.Annotations == nil {
+			podInfo.Pod.Annotations = map[string]string{}
+		}
+		podInfo.Pod.Annotations[podutil.GPUMicroTopologyKey] = device
+	}
 
 	nodeInfo.AddPod(podInfo.Pod)
 
@@ -420,6 +420,11 @@ func (s *ReservationStore) UpdateReservation(oldRes, newRes *schedulingv1a1.Rese
 }
 
 func (s *ReservationStore) DeleteReservation(res *schedulingv1a1.Reservation) error {
+	if podutil.IsProactiveReservation(res) {
+		// proactive reservation holds no resources by itself, see its placeholder pods.
+		return nil
+	}
+
 	fakePod := podutil.ConvertReservationToPod(res)
 	if err := s.removeFakePod(fakePod); err != nil {
 		return fmt.Errorf("failed to remove fake pod, %v", err)
@@ -622,5 +627,9 @@ func (s *ReservationStore) GetAvailablePlaceholderPod(pod *v1.Pod) (*v1.Pod, err
 	if !reservationInfo.IsAvailable() {
 		return nil, fmt.Errorf("reservation placeholder is already matched")
 	}
+	if !podutil.MatchReservationIdentity(pod, reservationInfo.PlaceholderPod) {
+		return nil, fmt.Errorf("reservation placeholder is reserved for identity %s, but got %s",
+			podutil.GetReservationIdentity(reservationInfo.PlaceholderPod), podutil.GetReservationIdentity(pod))
+	}
 	return reservationInfo.PlaceholderPod, nil
 }
@@ -21,14 +21,13 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
-	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 
 	godelcache "github.com/kubewharf/godel-scheduler/pkg/binder/cache"
 	godelqueue "github.com/kubewharf/godel-scheduler/pkg/binder/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	schedutil "github.com/kubewharf/godel-scheduler/pkg/util"
-	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 type CacheDumper struct {
@@ -131,24 +130,9 @@ type resourceToValueMap map[v1.ResourceName]int64
 // PodOverhead feature is enabled, the Overhead is added to the result.
 // podResourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead
 func calculatePodResourceRequest(pod *v1.Pod, resource v1.ResourceName) int64 {
-	var podRequest int64
-	for i := range pod.Spec.Containers {
-		container := &pod.Spec.Containers[i]
-		value := schedutil.GetNonzeroRequestForResource(resource, &container.Resources.Requests)
-		podRequest += value
+	requests := podutil.PodNonZeroRequests(pod)
+	if _, ok := requests[resource]; !ok {
+		return 0
 	}
-	for i := range pod.Spec.InitContainers {
-		initContainer := &pod.Spec.InitContainers[i]
-		value := schedutil.GetNonzeroRequestForResource(resource, &initContainer.Resources.Requests)
-		if podRequest < value {
-			podRequest = value
-		}
-	}
-	// If Overhead is being utilized, add to the total requests for the pod
-	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
-		if quantity, found := pod.Spec.Overhead[resource]; found {
-			podRequest += quantity.Value()
-		}
-	}
-	return podRequest
+	return schedutil.GetNonzeroRequestForResource(resource, &requests)
 }
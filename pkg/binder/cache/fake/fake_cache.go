@@ -24,6 +24,7 @@ import (
 
 	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
@@ -240,6 +241,18 @@ func (c *Cache) DeleteReservation(request *schedulingv1a1.Reservation) error {
 	return nil
 }
 
+func (c *Cache) AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	return nil
+}
+
+func (c *Cache) UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error {
+	return nil
+}
+
+func (c *Cache) DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	return nil
+}
+
 func (c *Cache) GetAvailablePlaceholderPod(
 	pod *v1.Pod,
 ) (*v1.Pod, error) {
@@ -248,3 +261,19 @@ func (c *Cache) GetAvailablePlaceholderPod(
 	}
 	return nil, fmt.Errorf("empty store")
 }
+
+func (c *Cache) Checkpoint() *commoncache.Checkpoint {
+	return &commoncache.Checkpoint{}
+}
+
+func (c *Cache) Restore(checkpoint *commoncache.Checkpoint) error {
+	return nil
+}
+
+func (c *Cache) FinishReconciling() int {
+	return 0
+}
+
+func (c *Cache) Reconciling() bool {
+	return false
+}
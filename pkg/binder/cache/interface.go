@@ -61,6 +61,7 @@ import (
 //     a pod might have changed its state (e.g. added and deleted) without delivering notification to the cache.
 type BinderCache interface {
 	commoncache.ClusterEventsHandler
+	commoncache.Checkpointer
 
 	// Dump takes a snapshot of the current cache. This is used for debugging
 	// purposes only and shouldn't be confused with UpdateSnapshot function.
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binder
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	cachedebugger "github.com/kubewharf/godel-scheduler/pkg/binder/cache/debugger"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+)
+
+// RestoreCacheFromCheckpoint restores the binder cache from the checkpoint at path, it should be called
+// before informers are started. Returns true if the cache is restored, the binder holds the units in queue
+// until FinishCacheReconciling is called.
+func (binder *Binder) RestoreCacheFromCheckpoint(path string, maxAge time.Duration) bool {
+	checkpoint, err := commoncache.LoadCheckpoint(path, ComponentName, maxAge)
+	if err != nil {
+		klog.InfoS("Skipped restoring binder cache from checkpoint", "path", path, "err", err)
+		return false
+	}
+	if err := binder.BinderCache.Restore(checkpoint); err != nil {
+		klog.InfoS("Failed to restore binder cache from checkpoint", "path", path, "err", err)
+		return false
+	}
+	return true
+}
+
+// FinishCacheReconciling removes the stale objects restored from checkpoint and reports the drift between
+// the binder cache and informers. It should be called after informers are synced.
+func (binder *Binder) FinishCacheReconciling() {
+	staleObjects := binder.BinderCache.FinishReconciling()
+
+	comparer := cachedebugger.CacheComparer{
+		NodeLister: binder.nodeLister,
+		PodLister:  binder.podLister,
+		Cache:      binder.BinderCache,
+	}
+	nodes, err := comparer.NodeLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list nodes for comparing with binder cache", "err", err)
+		return
+	}
+	pods, err := comparer.PodLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods for comparing with binder cache", "err", err)
+		return
+	}
+	assignedPods := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) > 0 {
+			assignedPods = append(assignedPods, pod)
+		}
+	}
+	dump := binder.BinderCache.Dump()
+	missedNodes, redundantNodes := comparer.CompareNodes(nodes, dump.Nodes)
+	missedPods, redundantPods := comparer.ComparePods(assignedPods, nil, dump.Nodes)
+
+	klog.InfoS("Finished reconciling binder cache restored from checkpoint", "numStaleObjects", staleObjects,
+		"numMissedNodes", len(missedNodes), "numRedundantNodes", len(redundantNodes),
+		"numMissedPods", len(missedPods), "numRedundantPods", len(redundantPods))
+	klog.V(4).InfoS("Drift of binder cache restored from checkpoint", "missedNodes", missedNodes, "redundantNodes", redundantNodes,
+		"missedPods", missedPods, "redundantPods", redundantPods)
+}
+
+// RunCacheCheckpointer checkpoints the binder cache to path periodically until stopCh is closed.
+func (binder *Binder) RunCacheCheckpointer(path string, period time.Duration, stopCh <-chan struct{}) {
+	commoncache.RunCheckpointer(binder.BinderCache, path, period, stopCh)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -64,6 +65,8 @@ const (
 	DeletePodEventFmt = "DeletePodEvent(%v)"
 
 	EventReason = "PGController"
+
+	GangRestartEventReason = "GangRestart"
 )
 
 // PodGroupController is a controller that process pod groups using provided Handler interface
@@ -75,6 +78,7 @@ type PodGroupController struct {
 	pgListerSynced  cache.InformerSynced
 	podListerSynced cache.InformerSynced
 	pgClient        pgclientset.Interface
+	client          kubernetes.Interface
 }
 
 // SetupPodGroupController returns a new *PodGroupController
@@ -113,6 +117,7 @@ func SetupPodGroupController(
 	ctrl.pgListerSynced = pgInformer.Informer().HasSynced
 	ctrl.podListerSynced = podInformer.Informer().HasSynced
 	ctrl.pgClient = pgClient
+	ctrl.client = client
 
 	go podInformer.Informer().Run(ctx.Done())
 	go ctrl.Run(PodGroupWorkers, ctx.Done())
@@ -138,7 +143,7 @@ func (ctrl *PodGroupController) Run(workers int, stopCh <-chan struct{}) {
 }
 
 func (ctrl *PodGroupController) pgAdded(pg *schedv1alpha1.PodGroup, event string) {
-	if unitutil.PodGroupFinalState(pg.Status.Phase) {
+	if unitutil.PodGroupTerminalState(pg.Status.Phase) && !unitutil.PodGroupRestartable(pg) {
 		return
 	}
 	if key := unitutil.GetPodGroupKey(pg); len(key) > 0 {
@@ -237,8 +242,11 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 		return true
 	}
 	// Quick check.
-	if unitutil.PodGroupFinalState(pg.Status.Phase) {
-		klog.V(4).InfoS("PodGroup has already ever reached the final state, shouldn't change any more", "podGroupKey", key, "phase", pg.Status.Phase)
+	if unitutil.PodGroupRestartable(pg) {
+		return ctrl.restartGang(pg)
+	}
+	if unitutil.PodGroupTerminalState(pg.Status.Phase) {
+		klog.V(4).InfoS("PodGroup has already ever reached the terminal state, shouldn't change any more", "podGroupKey", key, "phase", pg.Status.Phase)
 		return false
 	}
 
@@ -253,6 +261,8 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 			klog.InfoS("Failed to list pods for podGroup", "podGroupKey", key, "err", err)
 			return true
 		}
+		// Terminating pods are not counted, they are going to be recreated by their owners.
+		pods = filterTerminatingPods(pods)
 
 		if len(pods) > 0 {
 			fillOccupiedObj(pgCopy, pods[0])
@@ -265,6 +275,7 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 		var created, scheduled int32
 		var overWriteScheduled bool
 		var uninitialized, pending, dispatched, assumed int32
+		var running, succeeded, failed int32
 		created = int32(len(pods))
 		for _, pod := range pods {
 			{
@@ -276,6 +287,16 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 					overWriteScheduled = true
 				}
 			}
+			{
+				switch pod.Status.Phase {
+				case v1.PodRunning:
+					running++
+				case v1.PodSucceeded:
+					succeeded++
+				case v1.PodFailed:
+					failed++
+				}
+			}
 			{
 				switch podutil.GetPodState(pod.Annotations) {
 				case podutil.PodNotInit:
@@ -303,8 +324,10 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 		if overWriteScheduled {
 			msg = ScheduledSatisfied
 		}
+		pgCopy.Status.Running, pgCopy.Status.Succeeded, pgCopy.Status.Failed = running, succeeded, failed
 
-		eventMsg = fmt.Sprintf("overWriteScheduled=%v;created=%v,scheduled=%v;uninitialized=%v,pending=%v,dispatched=%v,assumed=%v", overWriteScheduled, created, scheduled, uninitialized, pending, dispatched, assumed)
+		eventMsg = fmt.Sprintf("overWriteScheduled=%v;created=%v,scheduled=%v;uninitialized=%v,pending=%v,dispatched=%v,assumed=%v;running=%v,succeeded=%v,failed=%v",
+			overWriteScheduled, created, scheduled, uninitialized, pending, dispatched, assumed, running, succeeded, failed)
 
 		// TODO: Interpretability enhancement
 		klog.V(4).InfoS("Analyzed pod states for PodGroup", "podGroupKey", key, "minMember", minMember, "eventMsg", eventMsg)
@@ -329,15 +352,6 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 			curPhase = schedv1alpha1.PodGroupPending
 			updatePodGroupCondition(pgCopy, curPhase, "")
 		}
-		{
-			// TODO: remove this compatible adaptation logic
-			if curPhase == schedv1alpha1.PodGroupRunning || curPhase == schedv1alpha1.PodGroupFinished || curPhase == schedv1alpha1.PodGroupFailed {
-				// Treat it as scheduled because there is at least one pod that is bound to the node.
-				phaseBefore := curPhase
-				curPhase = schedv1alpha1.PodGroupScheduled
-				updatePodGroupCondition(pgCopy, curPhase, fmt.Sprintf("Change %v to scheduled for compatibility", phaseBefore))
-			}
-		}
 
 		switch curPhase {
 		case schedv1alpha1.PodGroupPending:
@@ -351,7 +365,7 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupPreScheduling, fmt.Sprintf("More than %v pods has been created but not fully scheduled", pgCopy.Spec.MinMember))
 				return schedv1alpha1.PodGroupPreScheduling
 			case ScheduledSatisfied:
-				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupScheduled, fmt.Sprintf("More than %v pods has been scheduled", pgCopy.Spec.MinMember))
+				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupScheduled, setScheduledTopologyLevel(pgCopy, pods))
 				return schedv1alpha1.PodGroupScheduled
 			}
 		case schedv1alpha1.PodGroupPreScheduling:
@@ -360,10 +374,11 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupPending, fmt.Sprintf("Less than %v pods has been created", pgCopy.Spec.MinMember))
 				return schedv1alpha1.PodGroupPending
 			case ScheduledSatisfied:
-				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupScheduled, fmt.Sprintf("More than %v pods has been scheduled", pgCopy.Spec.MinMember))
+				updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupScheduled, setScheduledTopologyLevel(pgCopy, pods))
 				return schedv1alpha1.PodGroupScheduled
 			}
-		case schedv1alpha1.PodGroupScheduled, schedv1alpha1.PodGroupTimeout:
+		case schedv1alpha1.PodGroupScheduled, schedv1alpha1.PodGroupRunning,
+			schedv1alpha1.PodGroupFinished, schedv1alpha1.PodGroupFailed, schedv1alpha1.PodGroupTimeout:
 		default:
 			klog.InfoS("Got unexpected phase for PodGroup", "phase", curPhase, "podGroupKey", key)
 		}
@@ -371,6 +386,15 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 	}
 
 	nextPhase := Step(pgCopy, msg)
+	// After being scheduled, the PodGroup moves on according to the phases of its members.
+	if nextPhase == schedv1alpha1.PodGroupScheduled || nextPhase == schedv1alpha1.PodGroupRunning {
+		nextPhase = stepLifecycle(pgCopy, nextPhase, int32(len(pods)))
+	}
+	if nextPhase == schedv1alpha1.PodGroupFailed && pg.Status.Phase != nextPhase &&
+		unitutil.GetPodGroupFailurePolicy(pgCopy) == unitutil.PodGroupFailurePolicyRestartGang &&
+		unitutil.GetPodGroupRestartCount(pgCopy) >= unitutil.GetPodGroupMaxRestarts(pgCopy) {
+		klog.InfoS("PodGroup failed and reached the restart limit, won't be restarted", "podGroupKey", key, "restartCount", unitutil.GetPodGroupRestartCount(pgCopy))
+	}
 	// PopulatePodGroupFinalOp must be called at the end.
 	if !unitutil.PodGroupFinalState(nextPhase) && podGroupTimeout(pgCopy) && unitutil.PopulatePodGroupFinalOp(pgCopy, "pg-controller") {
 		updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupTimeout, fmt.Sprintf("Can't schedule pods for %s before timeout", key))
@@ -380,6 +404,18 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 	pgCopy.Status.Phase = nextPhase
 	updated, err := ctrl.updatePodGroup(pg, pgCopy, eventMsg)
 	if updated && err == nil {
+		var pod *v1.Pod
+		if len(pods) > 0 {
+			pod = pods[0]
+		}
+		fromPhase := pg.Status.Phase
+		if fromPhase == "" {
+			fromPhase = schedv1alpha1.PodGroupPending
+		}
+		if fromPhase != nextPhase {
+			metrics.PodGroupPhaseTransitionInc(api.ExtractPodProperty(pod).ConvertToMetricsLabels(), string(fromPhase), string(nextPhase))
+		}
+
 		if len(pods) > 0 && nextPhase == schedv1alpha1.PodGroupScheduled {
 			scheduleStartTime := GetPodGroupScheduleStartTime(pg)
 			if !scheduleStartTime.IsZero() {
@@ -389,7 +425,90 @@ func (ctrl *PodGroupController) syncHandler(key string) bool {
 		}
 	}
 
-	return !(err == nil && unitutil.PodGroupFinalState(nextPhase))
+	// The failed PodGroup is restarted by the next sync, after the Failed phase has been persisted.
+	return !(err == nil && unitutil.PodGroupFinalState(nextPhase)) || unitutil.PodGroupRestartable(pgCopy)
+}
+
+// stepLifecycle moves the scheduled PodGroup to Running, Finished or Failed according to the phases of its members.
+func stepLifecycle(pgCopy *schedv1alpha1.PodGroup, curPhase schedv1alpha1.PodGroupPhase, created int32) schedv1alpha1.PodGroupPhase {
+	minMember := pgCopy.Spec.MinMember
+	if minMember < 1 {
+		minMember = 1
+	}
+	status := &pgCopy.Status
+	switch {
+	case status.Succeeded >= minMember:
+		updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupFinished, fmt.Sprintf("%v pods have succeeded, not less than %v", status.Succeeded, minMember))
+		return schedv1alpha1.PodGroupFinished
+	case status.Failed > 0 && created-status.Failed < minMember:
+		updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupFailed, fmt.Sprintf("%v pods have failed, only %v pods remain healthy, less than %v", status.Failed, created-status.Failed, minMember))
+		return schedv1alpha1.PodGroupFailed
+	case curPhase == schedv1alpha1.PodGroupScheduled && status.Running+status.Succeeded >= minMember:
+		updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupRunning, fmt.Sprintf("%v pods are running or have succeeded, not less than %v", status.Running+status.Succeeded, minMember))
+		return schedv1alpha1.PodGroupRunning
+	}
+	return curPhase
+}
+
+// resetPodGroupForRestart moves the failed PodGroup back to Pending, the Failed condition is kept to record the restart.
+func resetPodGroupForRestart(pgCopy *schedv1alpha1.PodGroup) {
+	restartCount := unitutil.GetPodGroupRestartCount(pgCopy) + 1
+	updatePodGroupCondition(pgCopy, schedv1alpha1.PodGroupPending, fmt.Sprintf("Restart the gang after failure, restart count %v", restartCount))
+	pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
+	pgCopy.Status.ScheduleStartTime = nil
+	pgCopy.Status.Running, pgCopy.Status.Succeeded, pgCopy.Status.Failed = 0, 0, 0
+	// Unlock the final operation so that the gang could be scheduled or timeout again.
+	delete(pgCopy.Annotations, unitutil.PodGroupFinalOpLock)
+	delete(pgCopy.Annotations, podutil.TopologyLevelStatusAnnotationKey)
+	if pgCopy.Annotations == nil {
+		pgCopy.Annotations = make(map[string]string)
+	}
+	pgCopy.Annotations[unitutil.PodGroupRestartCountAnnotationKey] = strconv.Itoa(restartCount)
+}
+
+// restartGang deletes all members of the failed PodGroup and then moves it back to Pending, so that their owners
+// recreate the whole gang. The PodGroup is kept Failed until all the members are deleted, otherwise the remaining
+// members could be scheduled as a part of the new gang. It returns whether the PodGroup should be re-enqueued.
+func (ctrl *PodGroupController) restartGang(pg *schedv1alpha1.PodGroup) bool {
+	key := unitutil.GetPodGroupKey(pg)
+	pods, err := GetAllPods(ctrl.podLister, pg.Namespace, pg.Name)
+	if err != nil {
+		klog.InfoS("Failed to list pods for podGroup", "podGroupKey", key, "err", err)
+		return true
+	}
+	// The local store may lag behind the restart written by the last sync, pods created after the failure
+	// belong to the new gang and must not be deleted.
+	failedExist, failedCondition := getPodGroupConditionByPhase(pg, schedv1alpha1.PodGroupFailed)
+	var deleted int
+	for _, pod := range filterTerminatingPods(pods) {
+		if failedExist && pod.CreationTimestamp.After(failedCondition.LastTransitionTime.Time) {
+			continue
+		}
+		err := ctrl.client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			klog.InfoS("Failed to delete pod to restart the gang of PodGroup", "podGroupKey", key, "pod", podutil.GetPodKey(pod), "err", err)
+			return true
+		}
+		deleted++
+	}
+
+	pgCopy := pg.DeepCopy()
+	resetPodGroupForRestart(pgCopy)
+	eventMsg := fmt.Sprintf("deleted %v pods to restart the gang", deleted)
+	if _, err := ctrl.updatePodGroup(pg, pgCopy, eventMsg); err != nil {
+		klog.InfoS("Failed to restart gang of PodGroup", "podGroupKey", key, "err", err)
+		return true
+	}
+	var pod *v1.Pod
+	if len(pods) > 0 {
+		pod = pods[0]
+	}
+	metrics.PodGroupPhaseTransitionInc(api.ExtractPodProperty(pod).ConvertToMetricsLabels(), string(schedv1alpha1.PodGroupFailed), string(schedv1alpha1.PodGroupPending))
+	if ctrl.eventRecorder != nil {
+		ctrl.eventRecorder.Eventf(pg, v1.EventTypeWarning, GangRestartEventReason, "Deleted %v pods to restart the gang, restart count %v", deleted, unitutil.GetPodGroupRestartCount(pgCopy))
+	}
+	klog.V(4).InfoS("Restarted gang of PodGroup", "podGroupKey", key, "numPods", deleted)
+	return true
 }
 
 func podGroupTimeout(pgCopy *schedv1alpha1.PodGroup) bool {
@@ -400,7 +519,12 @@ func podGroupTimeout(pgCopy *schedv1alpha1.PodGroup) bool {
 		timeoutDuration = frameworkruntime.DefaultGangTimeout
 	}
 
-	if time.Since(pgCopy.CreationTimestamp.Time) > timeoutDuration {
+	// The gang restarted after failure is timed from the restart.
+	startTime := pgCopy.CreationTimestamp
+	if exist, condition := getPodGroupConditionByPhase(pgCopy, schedv1alpha1.PodGroupFailed); exist && condition.LastTransitionTime.After(startTime.Time) {
+		startTime = condition.LastTransitionTime
+	}
+	if time.Since(startTime.Time) > timeoutDuration {
 		klog.V(5).InfoS("Pod group timeout", "podGroupKey", unitutil.GetPodGroupKey(pgCopy), "timeout period", timeoutDuration)
 		return true
 	}
@@ -448,11 +572,19 @@ func (ctrl *PodGroupController) updatePodGroup(old, new *schedv1alpha1.PodGroup,
 		}
 
 		if old.Status.Phase == new.Status.Phase {
-			klog.V(4).InfoS("Skipped update podGroup to new status cause phase unchanged",
-				"podGroupKey", unitutil.GetPodGroupKey(new),
-				"status", old.Status.Phase,
-				"eventMsg", eventMsg,
-			)
+			if old.Status.Running == new.Status.Running && old.Status.Succeeded == new.Status.Succeeded && old.Status.Failed == new.Status.Failed {
+				klog.V(4).InfoS("Skipped update podGroup to new status cause phase unchanged",
+					"podGroupKey", unitutil.GetPodGroupKey(new),
+					"status", old.Status.Phase,
+					"eventMsg", eventMsg,
+				)
+				return false, nil
+			}
+			// Only the numbers of pods in each phase are changed, no need to record an event.
+			if _, err := ctrl.pgClient.SchedulingV1alpha1().PodGroups(new.Namespace).UpdateStatus(context.TODO(), new, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Failed to update pod group status", "podGroupKey", unitutil.GetPodGroupKey(new), "eventMsg", eventMsg)
+				return false, err
+			}
 			return false, nil
 		}
 		_, err := ctrl.pgClient.SchedulingV1alpha1().PodGroups(new.Namespace).UpdateStatus(context.TODO(), new, metav1.UpdateOptions{})
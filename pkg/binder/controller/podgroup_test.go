@@ -34,6 +34,7 @@ import (
 	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/controller"
 	podAnnotations "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
 )
 
 const (
@@ -48,6 +49,7 @@ func Test_PodGroupRun(t *testing.T) {
 	createTimeForDefault := metav1.Time{Time: time.Now().Add(-6 * time.Minute)}     // default is 5 min
 	durationInSeconds := int32(300)
 	noConditionChange := 0
+	twoConditionChanges := 2
 	occupiedObjName := makeOccupiedObj(jobOwnerKind, "default", jobOwnerName)
 
 	nodeName := "n"
@@ -68,6 +70,10 @@ func Test_PodGroupRun(t *testing.T) {
 		scheduleTimeoutSeconds *int32
 		desiredConditionDelta  *int
 		desiredOccupiedObjName *string
+		failurePolicy          unitutil.PodGroupFailurePolicy
+		restartCount           string
+		desiredPodsDeleted     bool
+		desiredRestartCount    string
 	}{
 		{
 			name:              "Group status empty and not enough pods created",
@@ -229,40 +235,154 @@ func Test_PodGroupRun(t *testing.T) {
 			desiredGroupPhase:     v1alpha1.PodGroupPreScheduling,
 			desiredConditionDelta: &noConditionChange,
 		},
-		// Compatible Adaptation Testing
+		// Lifecycle Testing
 		{
-			name:              "Group group running, should be scheduled directly anyway",
+			name:              "Group running cause min-member pods running",
 			pgName:            "pg20",
 			minMember:         2,
 			podNames:          []string{"pod1", "pod2"},
-			podNodeName:       &noNodeName,
-			previousPhase:     v1alpha1.PodGroupRunning,
-			desiredGroupPhase: v1alpha1.PodGroupScheduled,
+			podNodeName:       &nodeName,
+			podPhase:          v1.PodRunning,
+			previousPhase:     v1alpha1.PodGroupScheduled,
+			desiredGroupPhase: v1alpha1.PodGroupRunning,
 		},
 		{
-			name:              "Group group finished, should be scheduled directly",
+			name:              "Group running directly cause min-member pods bound and running",
 			pgName:            "pg21",
 			minMember:         2,
 			podNames:          []string{"pod1", "pod2"},
-			podNodeName:       &noNodeName,
-			previousPhase:     v1alpha1.PodGroupFinished,
-			desiredGroupPhase: v1alpha1.PodGroupScheduled,
+			podNodeName:       &nodeName,
+			podPhase:          v1.PodRunning,
+			previousPhase:     v1alpha1.PodGroupPreScheduling,
+			desiredGroupPhase: v1alpha1.PodGroupRunning,
+			// Both Scheduled and Running conditions are added.
+			desiredConditionDelta: &twoConditionChanges,
 		},
 		{
-			name:              "Group group failed, should be scheduled directly",
-			pgName:            "pg22",
+			name:                  "Group running remains running though pods are not running",
+			pgName:                "pg22",
+			minMember:             2,
+			podNames:              []string{"pod1", "pod2"},
+			podNodeName:           &nodeName,
+			podPhase:              v1.PodPending,
+			previousPhase:         v1alpha1.PodGroupRunning,
+			desiredGroupPhase:     v1alpha1.PodGroupRunning,
+			desiredConditionDelta: &noConditionChange,
+		},
+		{
+			name:              "Group finished cause min-member pods succeeded",
+			pgName:            "pg23",
 			minMember:         2,
 			podNames:          []string{"pod1", "pod2"},
-			podNodeName:       &noNodeName,
-			previousPhase:     v1alpha1.PodGroupFailed,
-			desiredGroupPhase: v1alpha1.PodGroupScheduled,
+			podNodeName:       &nodeName,
+			podPhase:          v1.PodSucceeded,
+			previousPhase:     v1alpha1.PodGroupRunning,
+			desiredGroupPhase: v1alpha1.PodGroupFinished,
+		},
+		{
+			name:              "Group failed cause less than min-member pods healthy",
+			pgName:            "pg24",
+			minMember:         2,
+			podNames:          []string{"pod1", "pod2"},
+			podNodeName:       &nodeName,
+			podPhase:          v1.PodRunning,
+			podPhaseOverride:  v1.PodFailed,
+			previousPhase:     v1alpha1.PodGroupRunning,
+			desiredGroupPhase: v1alpha1.PodGroupFailed,
+		},
+		{
+			name:                  "Group running though one pod failed, cause min-member pods healthy",
+			pgName:                "pg25",
+			minMember:             1,
+			podNames:              []string{"pod1", "pod2"},
+			podNodeName:           &nodeName,
+			podPhase:              v1.PodRunning,
+			podPhaseOverride:      v1.PodFailed,
+			previousPhase:         v1alpha1.PodGroupRunning,
+			desiredGroupPhase:     v1alpha1.PodGroupRunning,
+			desiredConditionDelta: &noConditionChange,
+		},
+		{
+			name:              "Group failed and restarted cause gang restart policy",
+			pgName:            "pg26",
+			minMember:         2,
+			podNames:          []string{"pod1", "pod2"},
+			podNodeName:       &nodeName,
+			podPhase:          v1.PodRunning,
+			podPhaseOverride:  v1.PodFailed,
+			previousPhase:     v1alpha1.PodGroupRunning,
+			desiredGroupPhase: v1alpha1.PodGroupPending,
+			failurePolicy:     unitutil.PodGroupFailurePolicyRestartGang,
+			// Both Failed and Pending conditions are added.
+			desiredConditionDelta: &twoConditionChanges,
+			desiredPodsDeleted:    true,
+			desiredRestartCount:   "1",
+		},
+		{
+			name:                  "Group finished should not change any more",
+			pgName:                "pg27",
+			minMember:             2,
+			podNames:              []string{"pod1", "pod2"},
+			podNodeName:           &nodeName,
+			podPhase:              v1.PodRunning,
+			previousPhase:         v1alpha1.PodGroupFinished,
+			desiredGroupPhase:     v1alpha1.PodGroupFinished,
+			desiredConditionDelta: &noConditionChange,
+		},
+		{
+			name:                  "Group failed should not change any more",
+			pgName:                "pg28",
+			minMember:             2,
+			podNames:              []string{"pod1", "pod2"},
+			podNodeName:           &nodeName,
+			podPhase:              v1.PodRunning,
+			previousPhase:         v1alpha1.PodGroupFailed,
+			desiredGroupPhase:     v1alpha1.PodGroupFailed,
+			desiredConditionDelta: &noConditionChange,
+		},
+		{
+			name:                "Failed group restarted cause restarts left",
+			pgName:              "pg29",
+			minMember:           2,
+			podNames:            []string{"pod1", "pod2"},
+			podNodeName:         &nodeName,
+			podPhase:            v1.PodFailed,
+			previousPhase:       v1alpha1.PodGroupFailed,
+			desiredGroupPhase:   v1alpha1.PodGroupPending,
+			failurePolicy:       unitutil.PodGroupFailurePolicyRestartGang,
+			restartCount:        "1",
+			desiredPodsDeleted:  true,
+			desiredRestartCount: "2",
+		},
+		{
+			name:                "Group failed and not restarted cause restart limit reached",
+			pgName:              "pg30",
+			minMember:           2,
+			podNames:            []string{"pod1", "pod2"},
+			podNodeName:         &nodeName,
+			podPhase:            v1.PodRunning,
+			podPhaseOverride:    v1.PodFailed,
+			previousPhase:       v1alpha1.PodGroupRunning,
+			desiredGroupPhase:   v1alpha1.PodGroupFailed,
+			failurePolicy:       unitutil.PodGroupFailurePolicyRestartGang,
+			restartCount:        fmt.Sprint(unitutil.DefaultPodGroupMaxRestarts),
+			desiredRestartCount: fmt.Sprint(unitutil.DefaultPodGroupMaxRestarts),
 		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			ps := makePods(c.podNames, c.pgName, *c.podNodeName)
+			for _, p := range ps {
+				p.Status.Phase = c.podPhase
+			}
 			kubeClient := fake.NewSimpleClientset(ps[0], ps[1])
 			pg := makePG(c.pgName, int32(c.minMember), c.previousPhase, c.scheduleTimeoutSeconds, c.podGroupCreateTime)
+			if len(c.failurePolicy) > 0 {
+				pg.Annotations = map[string]string{unitutil.PodGroupFailurePolicyAnnotationKey: string(c.failurePolicy)}
+			}
+			if len(c.restartCount) > 0 {
+				pg.Annotations[unitutil.PodGroupRestartCountAnnotationKey] = c.restartCount
+			}
 			pgClient := pgfake.NewSimpleClientset(pg)
 
 			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
@@ -311,6 +431,21 @@ func Test_PodGroupRun(t *testing.T) {
 					return false, fmt.Errorf("want %v condition, got %v condition", len(pg.Status.Conditions)+delta, len(newPg.Status.Conditions))
 				}
 
+				if c.desiredPodsDeleted {
+					pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return false, err
+					}
+					if len(pods.Items) != 0 {
+						return false, fmt.Errorf("want all pods deleted, got %v pods", len(pods.Items))
+					}
+				}
+				if len(c.desiredRestartCount) > 0 {
+					if got := newPg.Annotations[unitutil.PodGroupRestartCountAnnotationKey]; got != c.desiredRestartCount {
+						return false, fmt.Errorf("want restart count %v, got %v", c.desiredRestartCount, got)
+					}
+				}
+
 				return true, nil
 			})
 			if err != nil {
@@ -17,6 +17,8 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,6 +70,16 @@ func GetPodGroupConditionTimeStampByPhase(pg *schedulingv1a1.PodGroup, phase sch
 	return metav1.Time{}
 }
 
+func filterTerminatingPods(pods []*v1.Pod) []*v1.Pod {
+	ret := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			ret = append(ret, pod)
+		}
+	}
+	return ret
+}
+
 func GetAllPods(podLister corelister.PodLister, pgNamespace, pgName string) ([]*v1.Pod, error) {
 	selector := labels.Set(map[string]string{
 		podutil.PodGroupNameAnnotationKey: pgName,
@@ -76,3 +88,49 @@ func GetAllPods(podLister corelister.PodLister, pgNamespace, pgName string) ([]*
 
 	return pods, err
 }
+
+// getAchievedTopologyLevel returns the loosest topology level achieved by the bound pods of the PodGroup,
+// the level is empty if some of them are not placed within any topology level.
+func getAchievedTopologyLevel(pg *schedulingv1a1.PodGroup, pods []*v1.Pod) podutil.TopologyLevelStatus {
+	none := podutil.TopologyLevelStatus{Index: -1}
+	levels := podutil.GetTopologyLevels(pg.Annotations)
+	achieved := -1
+	for _, pod := range pods {
+		if !podutil.BoundPod(pod) {
+			continue
+		}
+		index := -1
+		for i, level := range levels {
+			if level == pod.Annotations[podutil.AchievedTopologyLevelAnnotationKey] {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return none
+		}
+		if index > achieved {
+			achieved = index
+		}
+	}
+	if achieved < 0 {
+		return none
+	}
+	return podutil.TopologyLevelStatus{Level: levels[achieved], Index: achieved}
+}
+
+// setScheduledTopologyLevel records the topology level achieved by the bound pods on the PodGroup
+// which is moving to Scheduled, and returns the message of the Scheduled condition.
+func setScheduledTopologyLevel(pgCopy *schedulingv1a1.PodGroup, pods []*v1.Pod) string {
+	msg := fmt.Sprintf("More than %v pods has been scheduled", pgCopy.Spec.MinMember)
+	if len(podutil.GetTopologyLevels(pgCopy.Annotations)) == 0 {
+		return msg
+	}
+	status := getAchievedTopologyLevel(pgCopy, pods)
+	podutil.SetTopologyLevelStatus(pgCopy.Annotations, status)
+	level := status.Level
+	if len(level) == 0 {
+		level = "none"
+	}
+	return fmt.Sprintf("%s, achieved topology level: %v", msg, level)
+}
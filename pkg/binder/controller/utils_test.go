@@ -74,3 +74,49 @@ func makePod(annotations map[string]string) *v1.Pod {
 	pod.Annotations = annotations
 	return pod
 }
+
+func Test_GetAchievedTopologyLevel(t *testing.T) {
+	pg := testinghelper.MakePodGroup().Namespace("default").Name("pg").Obj()
+	pg.Annotations = map[string]string{podAnnotations.TopologyLevelsAnnotationKey: "leaf,spine,superpod"}
+	makePod := func(name, node, level string) *v1.Pod {
+		pod := testinghelper.MakePod().Namespace("default").Name(name).Node(node)
+		if len(level) > 0 {
+			pod = pod.Annotation(podAnnotations.AchievedTopologyLevelAnnotationKey, level)
+		}
+		return pod.Obj()
+	}
+
+	cases := []struct {
+		name     string
+		pods     []*v1.Pod
+		expected podAnnotations.TopologyLevelStatus
+	}{
+		{
+			name:     "all pods in one leaf",
+			pods:     []*v1.Pod{makePod("p1", "n1", "leaf"), makePod("p2", "n2", "leaf")},
+			expected: podAnnotations.TopologyLevelStatus{Level: "leaf", Index: 0},
+		},
+		{
+			name:     "pods scheduled in different attempts",
+			pods:     []*v1.Pod{makePod("p1", "n1", "leaf"), makePod("p2", "n2", "spine")},
+			expected: podAnnotations.TopologyLevelStatus{Level: "spine", Index: 1},
+		},
+		{
+			name:     "pods not bound are ignored",
+			pods:     []*v1.Pod{makePod("p1", "n1", "leaf"), makePod("p2", "", "")},
+			expected: podAnnotations.TopologyLevelStatus{Level: "leaf", Index: 0},
+		},
+		{
+			name:     "pod placed without topology level",
+			pods:     []*v1.Pod{makePod("p1", "n1", "leaf"), makePod("p2", "n2", "")},
+			expected: podAnnotations.TopologyLevelStatus{Index: -1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := getAchievedTopologyLevel(pg, c.pods); got != c.expected {
+				t.Errorf("expected: %v, got: %v", c.expected, got)
+			}
+		})
+	}
+}
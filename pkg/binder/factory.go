@@ -81,7 +81,7 @@ func NewBasePlugins(victimsCheckingPlugins []*framework.VictimCheckingPluginColl
 		VictimCheckings: victimsCheckingPlugins,
 	}
 	if utilfeature.DefaultFeatureGate.Enabled(features.NonNativeResourceSchedulingSupport) {
-		basicPlugins.CheckConflicts = append(basicPlugins.CheckConflicts, nonnativeresource.Name)
+		basicPlugins.CheckConflicts = append(basicPlugins.CheckConflicts, nonnativeresource.Name, nonnativeresource.GPUShareName)
 	}
 
 	return &basicPlugins
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnativeresource
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/handle"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/nonnativeresource"
+)
+
+const (
+	GPUShareName = "GPUShare"
+)
+
+// GPUShare checks whether pods sharing gpus still fit on a single gpu device of the node.
+type GPUShare struct{}
+
+var _ framework.CheckConflictsPlugin = &GPUShare{}
+
+func NewGPUShare(_ runtime.Object, _ handle.BinderFrameworkHandle) (framework.Plugin, error) {
+	return &GPUShare{}, nil
+}
+
+func (gs *GPUShare) Name() string {
+	return GPUShareName
+}
+
+func (gs *GPUShare) CheckConflicts(_ context.Context, _ *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	return nonnativeresource.FeasibleGPUShare(pod, nodeInfo)
+}
@@ -35,8 +35,38 @@ type VolumeBinding struct {
 
 var _ framework.CheckConflictsPlugin = &VolumeBinding{}
 
-// Name is the name of the plugin used in Registry and configurations.
-const Name = "VolumeBinding"
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "VolumeBinding"
+
+	// placedPodsStateKey is the key in CycleState to the other pods of the same unit placed with the pod.
+	placedPodsStateKey = "PlacedPods" + Name
+)
+
+type placedPodsState []*scheduling.PlacedPod
+
+// Clone the placed pods state.
+func (s placedPodsState) Clone() framework.StateData {
+	return s
+}
+
+// SetPlacedPods writes the other pods of the same unit placed with the pod to CycleState. The storage of
+// their unbound volumes is reserved when checking the storage capacity for the pod.
+func SetPlacedPods(state *framework.CycleState, placedPods []*scheduling.PlacedPod) {
+	state.Write(placedPodsStateKey, placedPodsState(placedPods))
+}
+
+func getPlacedPods(state *framework.CycleState) []*scheduling.PlacedPod {
+	if state == nil {
+		return nil
+	}
+	c, err := state.Read(placedPodsStateKey)
+	if err != nil {
+		return nil
+	}
+	s, _ := c.(placedPodsState)
+	return s
+}
 
 // Name returns name of the plugin. It is used in logs, etc.
 func (pl *VolumeBinding) Name() string {
@@ -45,7 +75,7 @@ func (pl *VolumeBinding) Name() string {
 
 func podHasPVCs(pod *v1.Pod) bool {
 	for _, vol := range pod.Spec.Volumes {
-		if vol.PersistentVolumeClaim != nil {
+		if vol.PersistentVolumeClaim != nil || vol.Ephemeral != nil {
 			return true
 		}
 	}
@@ -62,6 +92,10 @@ func podHasPVCs(pod *v1.Pod) bool {
 // For PVCs that are unbound, it tries to find available PVs that can satisfy the PVC requirements
 // and that the PV node affinity is satisfied by the given node.
 //
+// For PVCs that are unbound and to be provisioned by CSI drivers publishing storage capacity, it checks
+// that they fit into the capacity of a topology segment of the node, together with the claims being
+// provisioned and the unbound claims of the pods placed with the pod.
+//
 // The predicate returns true if all bound PVCs have compatible PVs with the node, and if all unbound
 // PVCs can be matched with an available and node-compatible PV.
 func (pl *VolumeBinding) CheckConflicts(ctx context.Context, cs *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
@@ -73,8 +107,13 @@ func (pl *VolumeBinding) CheckConflicts(ctx context.Context, cs *framework.Cycle
 		return nil
 	}
 
+	pendingClaims, err := pl.binder.GetPendingClaims(pod, getPlacedPods(cs))
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+
 	podLauncher, _ := podutil.GetPodLauncher(pod)
-	reasons, err := pl.binder.FindPodVolumes(pod, nodeInfo.GetNodeName(), nodeInfo.GetNodeLabels(podLauncher))
+	reasons, err := pl.binder.FindPodVolumes(pod, nodeInfo.GetNodeName(), nodeInfo.GetNodeLabels(podLauncher), pendingClaims)
 	if err != nil {
 		return framework.NewStatus(framework.Error, err.Error())
 	}
@@ -57,6 +57,7 @@ func NewInTreeRegistry() Registry {
 		volumebinding.Name:              volumebinding.New,
 		nodeports.Name:                  nodeports.New,
 		nonnativeresource.Name:          nonnativeresource.New,
+		nonnativeresource.GPUShareName:  nonnativeresource.NewGPUShare,
 	}
 }
 
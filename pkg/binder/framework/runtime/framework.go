@@ -424,13 +424,25 @@ func (f *GodelFramework) InitCycleState(pod *v1.Pod) (*framework.CycleState, err
 
 func (f *GodelFramework) RunClusterPrePreemptingPlugins(preemptor *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for _, plugin := range f.clusterPrePreemptingPlugins {
-		if err := plugin.ClusterPrePreempting(preemptor, state, commonState); err != nil {
+		if err := f.runClusterPrePreemptingPlugin(plugin, preemptor, state, commonState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelFramework) runClusterPrePreemptingPlugin(pl framework.ClusterPrePreemptingPlugin, preemptor *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return pl.ClusterPrePreempting(preemptor, state, commonState)
+	}
+	startTime := time.Now()
+	status := pl.ClusterPrePreempting(preemptor, state, commonState)
+
+	podProperty, _ := framework.GetPodProperty(state)
+	metrics.ObserveBindingStageDuration(podProperty, metrics.ClusterPrePreemptingEvaluation, pl.Name(), status.Code().String(), metrics.SinceInSeconds(startTime))
+	return status
+}
+
 func (f *GodelFramework) RunVictimCheckingPlugins(preemptor, pod *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for i, pluginCollection := range f.victimCheckingPlugins {
 		status := f.runVictimCheckingPluginCollection(pluginCollection, preemptor, pod, state, commonState)
@@ -452,7 +464,7 @@ func (f *GodelFramework) RunVictimCheckingPlugins(preemptor, pod *v1.Pod, state,
 
 func (f *GodelFramework) runVictimCheckingPluginCollection(pluginCollection *framework.VictimCheckingPluginCollection, preemptor, pod *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for _, plugin := range pluginCollection.GetVictimCheckingPlugins() {
-		code, msg := plugin.VictimChecking(preemptor, pod, state, commonState)
+		code, msg := f.runVictimCheckingPlugin(plugin, preemptor, pod, state, commonState)
 		switch code {
 		case framework.PreemptionFail:
 			return framework.NewStatus(code, msg)
@@ -467,15 +479,39 @@ func (f *GodelFramework) runVictimCheckingPluginCollection(pluginCollection *fra
 	return framework.NewStatus(framework.PreemptionNotSure)
 }
 
+func (f *GodelFramework) runVictimCheckingPlugin(pl framework.VictimCheckingPlugin, preemptor, pod *v1.Pod, state, commonState *framework.CycleState) (framework.Code, string) {
+	if !state.ShouldRecordPluginMetrics() {
+		return pl.VictimChecking(preemptor, pod, state, commonState)
+	}
+	startTime := time.Now()
+	code, msg := pl.VictimChecking(preemptor, pod, state, commonState)
+
+	podProperty, _ := framework.GetPodProperty(state)
+	metrics.ObserveBindingStageDuration(podProperty, metrics.VictimCheckingEvaluation, pl.Name(), code.String(), metrics.SinceInSeconds(startTime))
+	return code, msg
+}
+
 func (f *GodelFramework) RunPostVictimCheckingPlugins(preemptor, pod *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for _, plugin := range f.postVictimCheckingPlugins {
-		if err := plugin.PostVictimChecking(preemptor, pod, state, commonState); err != nil {
+		if err := f.runPostVictimCheckingPlugin(plugin, preemptor, pod, state, commonState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelFramework) runPostVictimCheckingPlugin(pl framework.PostVictimCheckingPlugin, preemptor, pod *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return pl.PostVictimChecking(preemptor, pod, state, commonState)
+	}
+	startTime := time.Now()
+	status := pl.PostVictimChecking(preemptor, pod, state, commonState)
+
+	podProperty, _ := framework.GetPodProperty(state)
+	metrics.ObserveBindingStageDuration(podProperty, metrics.PostVictimCheckingEvaluation, pl.Name(), status.Code().String(), metrics.SinceInSeconds(startTime))
+	return status
+}
+
 // New creates a new GodelBinderFramework, where pluginRegistry marks which plugins are supported, basePlugins presents which plugins are enabled by default.
 // podConstraintConfigs are used in pod annotation, where hard constraint will be taken as filter plugins and soft constraint will be taken as score plugins.
 // If plugin in podConstraintConfigs not exists in basePlugins, add this plugin to the new Godel Framework.
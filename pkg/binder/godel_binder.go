@@ -25,6 +25,7 @@ import (
 	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
 	"github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
@@ -43,6 +45,7 @@ import (
 	cachedebugger "github.com/kubewharf/godel-scheduler/pkg/binder/cache/debugger"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/controller"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/handle"
+	"github.com/kubewharf/godel-scheduler/pkg/binder/framework/plugins/volumebinding"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/binder/queue"
 	binderutils "github.com/kubewharf/godel-scheduler/pkg/binder/utils"
@@ -52,15 +55,21 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
 	"github.com/kubewharf/godel-scheduler/pkg/plugins/nonnativeresource"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
+	eventutil "github.com/kubewharf/godel-scheduler/pkg/util/event"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util/interpretabity"
+	metricsutil "github.com/kubewharf/godel-scheduler/pkg/util/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/util/parallelize"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 	status "github.com/kubewharf/godel-scheduler/pkg/util/unitstatus"
+	"github.com/kubewharf/godel-scheduler/pkg/volume/scheduling"
 	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
 )
 
 const (
+	ComponentName = "godel-binder"
+
 	MaxPreemptionBackoffPeriodInSeconds = 600
 	MaxRetryAttempts                    = 3 // TODO: 5 will cause a timeout in UT (30s)
 )
@@ -94,6 +103,7 @@ type Binder struct {
 	recorder   events.EventRecorder
 	reconciler *BinderTasksReconciler
 
+	nodeLister        corelisters.NodeLister
 	podLister         corelisters.PodLister
 	pgLister          v1alpha1.PodGroupLister
 	movementLister    v1alpha1.MovementLister
@@ -106,6 +116,9 @@ type Binder struct {
 	// schedulerInfo *SchedulerInfo
 
 	movementController controller.CommonController
+
+	// pluginMetricsSamplePercent is the percentage of pods whose binding cycle records plugin metrics.
+	pluginMetricsSamplePercent int32
 }
 
 // New returns a Binder
@@ -134,7 +147,7 @@ func New(
 
 	cacheHandler := commoncache.MakeCacheHandlerWrapper().
 		Period(10 * time.Second).PodAssumedTTL(5 * time.Minute).ReservationTTL(reservationTTL).StopCh(stopEverything).
-		ComponentName("godel-binder").PodLister(informerFactory.Core().V1().Pods().Lister()).
+		ComponentName(ComponentName).PodLister(informerFactory.Core().V1().Pods().Lister()).
 		Obj()
 
 	binderCache := godelcache.New(cacheHandler)
@@ -164,14 +177,17 @@ func New(
 		recorder:   recorder,
 		reconciler: NewBinderTaskReconciler(client),
 
-		podLister: informerFactory.Core().V1().Pods().Lister(),
-		pgLister:  crdInformerFactory.Scheduling().V1alpha1().PodGroups().Lister(),
+		nodeLister: informerFactory.Core().V1().Nodes().Lister(),
+		podLister:  informerFactory.Core().V1().Pods().Lister(),
+		pgLister:   crdInformerFactory.Scheduling().V1alpha1().PodGroups().Lister(),
+
+		pluginMetricsSamplePercent: options.pluginMetricsSamplePercent,
 	}
 
 	// Setup cache debugger.
 	debugger := cachedebugger.New(
-		informerFactory.Core().V1().Nodes().Lister(),
-		informerFactory.Core().V1().Pods().Lister(),
+		binder.nodeLister,
+		binder.podLister,
 		binderCache,
 		binderQueue,
 	)
@@ -209,6 +225,13 @@ func (binder *Binder) initializeReschedulingModule(
 func (binder *Binder) Run(ctx context.Context) {
 	binder.BinderQueue.Run()
 	binder.reconciler.Run()
+	// The cache restored from checkpoint misses the pods bound after the checkpoint was taken, so conflicts are
+	// only checked against the cache reconciled with informers. Units are queued meanwhile.
+	if err := wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		return !binder.BinderCache.Reconciling(), nil
+	}, ctx.Done()); err != nil {
+		return
+	}
 	resolveConflicts := func(ctx context.Context) {
 		defer func() {
 			if rc := recover(); rc != nil {
@@ -354,6 +377,7 @@ func (binder *Binder) initializeTask(unitInfo *bindingUnitInfo, runningUnitInfo
 		returnErr = fmt.Errorf("fail to init cycle state for pod: %v/%v, error: %v", queuedPod.Pod.Namespace, queuedPod.Pod.Name, err)
 		return
 	}
+	state.SetRecordPluginMetrics(metricsutil.SamplePluginMetrics(binder.pluginMetricsSamplePercent))
 	runningUnitInfo.State = state
 
 	suggestedNode := utils.GetNodeNameFromPod(queuedPod.Pod)
@@ -464,6 +488,13 @@ func (binder *Binder) InitializeUnit(unit *framework.QueuedUnitInfo) *bindingUni
 }
 
 func (binder *Binder) CheckCrossNodeTopologyForUnit(ctx context.Context, unitInfo *bindingUnitInfo) error {
+	// Re-validate the topology level achieved by the scheduler, it may be broken if nodes are relabeled.
+	if err := checkAchievedTopologyLevel(unitInfo.GetNewTasks(), binder.BinderCache.GetNodeInfo); err != nil {
+		err = fmt.Errorf("unit checks fail at CheckCrossNodeTopologyForUnit, err: %v", err)
+		unitInfo.MoveAllTasksToFailedList(err)
+		return err
+	}
+
 	commonState := framework.NewCycleState()
 	// TODO
 	// Step 1: PrepareCommonState
@@ -507,6 +538,37 @@ func (binder *Binder) CheckCrossNodeTopologyForUnit(ctx context.Context, unitInf
 	return nil
 }
 
+// checkAchievedTopologyLevel checks that the tasks placed within a hierarchical topology level by the scheduler
+// still share the same topology value of that level on their suggested nodes.
+func checkAchievedTopologyLevel(tasks []*runningUnitInfo, getNodeInfo func(string) framework.NodeInfo) error {
+	values := make(map[string]string)
+	for _, task := range tasks {
+		pod := task.queuedPodInfo.Pod
+		level := pod.Annotations[podutil.AchievedTopologyLevelAnnotationKey]
+		if len(level) == 0 {
+			continue
+		}
+		nodeInfo := getNodeInfo(task.suggestedNode)
+		if nodeInfo == nil {
+			// missing nodes will be handled by the checks of each task.
+			continue
+		}
+		podLauncher, err := podutil.GetPodLauncher(pod)
+		if err != nil {
+			return err
+		}
+		value, ok := nodeInfo.GetNodeLabels(podLauncher)[level]
+		if !ok {
+			return fmt.Errorf("node %v of pod %v doesn't belong to topology level %v", task.suggestedNode, podutil.GetPodKey(pod), level)
+		}
+		if expected, ok := values[level]; ok && expected != value {
+			return fmt.Errorf("pods are placed across topology level %v, got %v and %v", level, expected, value)
+		}
+		values[level] = value
+	}
+	return nil
+}
+
 func checkCrossNodePreemptionForAssumedTask(podLister corelisters.PodLister, assumedTask *framework.QueuedPodInfo) (complete bool, inProgress bool, returnErr error) {
 	pod := assumedTask.Pod
 	podTrace := tracing.NewSchedulingTrace(pod, assumedTask.GetPodProperty().ConvertToTracingTags(), tracing.WithBinderOption())
@@ -627,6 +689,10 @@ func (binder *Binder) CheckSameNodeConflictsForUnit(ctx context.Context, unitInf
 		return nil
 	}
 
+	// Storage capacity is shared by the nodes in the same topology segment, so the volumes of all the new tasks
+	// are checked together.
+	binder.setPlacedPodsOfNewTasks(unitInfo)
+
 	parallelizeCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -708,6 +774,41 @@ func (binder *Binder) CheckSameNodeConflictsForUnit(ctx context.Context, unitInf
 	return parallelizeCtx.Err()
 }
 
+// setPlacedPodsOfNewTasks writes the new tasks with PVCs to the CycleState of each other, so that VolumeBinding
+// reserves the storage of their unbound volumes when checking the storage capacity.
+func (binder *Binder) setPlacedPodsOfNewTasks(unitInfo *bindingUnitInfo) {
+	var tasks []*runningUnitInfo
+	var placedPods []*scheduling.PlacedPod
+	for _, task := range unitInfo.GetNewTasks() {
+		pod := task.queuedPodInfo.Pod
+		if !hasPVCs(pod) {
+			continue
+		}
+		nodeInfo := binder.BinderCache.GetNodeInfo(task.suggestedNode)
+		if nodeInfo == nil {
+			continue
+		}
+		podLauncher, _ := podutil.GetPodLauncher(pod)
+		tasks = append(tasks, task)
+		placedPods = append(placedPods, &scheduling.PlacedPod{Pod: pod, NodeLabels: nodeInfo.GetNodeLabels(podLauncher)})
+	}
+	if len(placedPods) < 2 {
+		return
+	}
+	for _, task := range tasks {
+		volumebinding.SetPlacedPods(task.State, placedPods)
+	}
+}
+
+func hasPVCs(pod *v1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil || vol.Ephemeral != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func assignMicroTopology(pod *v1.Pod, nodeInfo framework.NodeInfo, state *framework.CycleState) {
 	topo := nonnativeresource.AssignMicroTopology(nodeInfo, pod, state)
 	if topo != "" {
@@ -717,10 +818,18 @@ func assignMicroTopology(pod *v1.Pod, nodeInfo framework.NodeInfo, state *framew
 		pod.Annotations[podutil.MicroTopologyKey] = topo
 		klog.V(4).InfoS("Get assigned micro-topology", "pod", podutil.GetPodKey(pod), "node", nodeInfo.GetNodeName(), "topo", topo)
 	}
+	if device := nonnativeresource.AssignGPUDevice(nodeInfo, pod); device != "" {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[podutil.GPUMicroTopologyKey] = device
+		klog.V(4).InfoS("Get assigned gpu device", "pod", podutil.GetPodKey(pod), "node", nodeInfo.GetNodeName(), "device", device)
+	}
 }
 
 func cleanMicroTopology(pod *v1.Pod) {
 	delete(pod.Annotations, podutil.MicroTopologyKey)
+	delete(pod.Annotations, podutil.GPUMicroTopologyKey)
 }
 
 func (binder *Binder) MarkVictimsAndAssumeTasks(ctx context.Context, unitInfo *bindingUnitInfo) error {
@@ -962,7 +1071,7 @@ func (binder *Binder) RejectFailedTasks(unitInfo *bindingUnitInfo) {
 	// TODO: figure out if we need to print the error message
 	// since we will try to delete all markers and forget all pods,
 	// some of them may have already been reset, so some error message may be misleading
-	for _, cr := range unitInfo.GetFailedTasks() {
+	for i, cr := range unitInfo.GetFailedTasks() {
 		// reset cache
 		if len(cr.runningUnit.victims) > 0 {
 			// delete all pod markers
@@ -986,8 +1095,10 @@ func (binder *Binder) RejectFailedTasks(unitInfo *bindingUnitInfo) {
 				qpi:    cr.runningUnit.queuedPodInfo,
 			})
 		}
-		// TODO: emit event for each pod ? will that increase the API burden and be throttled, um-mm ?
-		binder.recorder.Eventf(cr.runningUnit.queuedPodInfo.Pod, nil, v1.EventTypeWarning, "FailedTasks", "Rejecting", helper.TruncateMessage(cr.err.Error()))
+		// The events of a large gang are aggregated on the PodGroup.
+		if i < eventutil.MaxPodEventsPerUnit {
+			binder.recorder.Eventf(cr.runningUnit.queuedPodInfo.Pod, nil, v1.EventTypeWarning, eventutil.FailedTasks, eventutil.RejectingAction, "%s", helper.TruncateMessage(cr.err.Error()))
+		}
 	}
 
 	if unitInfo.queuedUnitInfo.Type() == framework.PodGroupUnitType {
@@ -1000,15 +1111,44 @@ func (binder *Binder) RejectFailedTasks(unitInfo *bindingUnitInfo) {
 			return
 		}
 
-		errMes := fmt.Errorf("reject failed tasks for unit: %v, detailed failed reasons are attached to tasks(pods); unit checking result is: all member: %v, mim member: %v, ready: %v, waiting: %v, failed tasks: %v",
-			unitInfo.queuedUnitInfo.GetKey(), unitInfo.allMember, unitInfo.minMember, len(unitInfo.GetReadyTasks()), len(unitInfo.GetWaitingTasks()), len(unitInfo.GetFailedTasks()))
-		binder.recorder.Eventf(pg, nil, v1.EventTypeWarning, "FailedTasks", "Rejecting", helper.TruncateMessage(errMes.Error()))
+		details := getBindingUnitDetails(unitInfo)
+		errMes := fmt.Errorf("reject failed tasks for unit: %v, detailed failed reasons are attached to tasks(pods); unit checking result is: all member: %v, mim member: %v, ready: %v, waiting: %v, failed tasks: %v; details: %v",
+			unitInfo.queuedUnitInfo.GetKey(), unitInfo.allMember, unitInfo.minMember, len(unitInfo.GetReadyTasks()), len(unitInfo.GetWaitingTasks()), len(unitInfo.GetFailedTasks()), details.FailureMessage())
+		binder.recorder.Eventf(pg, nil, v1.EventTypeWarning, eventutil.FailedTasks, eventutil.RejectingAction, "%s", helper.TruncateMessage(errMes.Error()))
+
+		cond := schedulingv1a1.PodGroupCondition{
+			Phase:              schedulingv1a1.PodGroupPreScheduling,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "FailedTasks",
+			Message:            details.FailureMessage(),
+		}
+		// The condition is only kept before the PodGroup is scheduled.
+		if err := interpretabity.UpdatePreSchedulingCondition(binder.handle.CRDClientSet(), pg, cond); err != nil {
+			klog.V(4).InfoS("Skipped updating the condition of pod group", "podGroup", klog.KObj(pg), "err", err)
+		}
+		if err := interpretabity.UpdateSchedulingDiagnosis(binder.handle.CRDClientSet(), pg, details.Diagnosis()); err != nil {
+			klog.V(4).InfoS("Skipped updating the scheduling diagnosis of pod group", "podGroup", klog.KObj(pg), "err", err)
+		}
 	}
 }
 
+// getBindingUnitDetails returns the details of the unit failed in binder.
+func getBindingUnitDetails(unitInfo *bindingUnitInfo) *interpretabity.UnitSchedulingDetails {
+	details := interpretabity.NewUnitSchedulingDetails(interpretabity.Binding, unitInfo.allMember)
+	for _, cr := range append(unitInfo.GetReadyTasks(), unitInfo.GetWaitingTasks()...) {
+		details.AddSuccessfulPods(podutil.GetPodKey(cr.runningUnit.queuedPodInfo.Pod))
+	}
+	for _, cr := range unitInfo.GetFailedTasks() {
+		details.AddPodsError(cr.err, podutil.GetPodKey(cr.runningUnit.queuedPodInfo.Pod))
+	}
+	return details
+}
+
 func (binder *Binder) RejectTimeOutUnit(unit *framework.QueuedUnitInfo) {
 	// try to delete pod marker and forget pod
 	// it doesn't matter if these operation fails, binder cache has TTL mechanism to clean up these info from cache
+	pods := make([]*v1.Pod, 0, unit.NumPods())
 	for _, qp := range unit.GetPods() {
 		err := fmt.Errorf("unit timeout")
 		binder.Error(qp, err)
@@ -1019,8 +1159,17 @@ func (binder *Binder) RejectTimeOutUnit(unit *framework.QueuedUnitInfo) {
 				qpi:    qp,
 			})
 		}
-		binder.recorder.Eventf(qp.Pod, nil, v1.EventTypeWarning, "FailedTasks", "Rejecting", err.Error())
+		pods = append(pods, qp.Pod)
 	}
+
+	message := fmt.Sprintf("Unit %s isn't scheduled within its timeout of %ds", unit.GetKey(), unit.GetTimeoutPeriod())
+	if unit.Type() == framework.PodGroupUnitType {
+		if pg, err := binder.pgLister.PodGroups(unit.GetNamespace()).Get(unit.GetName()); err == nil {
+			eventutil.RecordUnitEvent(binder.recorder, pg, pods, v1.EventTypeWarning, eventutil.Timeout, eventutil.RejectingAction, message)
+			return
+		}
+	}
+	eventutil.RecordUnitEvent(binder.recorder, nil, pods, v1.EventTypeWarning, eventutil.Timeout, eventutil.RejectingAction, message)
 }
 
 // ------------------------------ Internal Functions ------------------------------
@@ -1114,7 +1263,7 @@ func (binder *Binder) bindTasks(ctx context.Context, unitInfo *bindingUnitInfo)
 			}
 
 			// emit bind event
-			binder.recorder.Eventf(task.queuedPodInfo.ReservedPod, nil, v1.EventTypeNormal, "Bind", "Binding", "Successfully assigned %v to %v", podutil.GetPodKey(task.queuedPodInfo.ReservedPod), task.suggestedNode)
+			binder.recorder.Eventf(task.queuedPodInfo.ReservedPod, nil, v1.EventTypeNormal, eventutil.Bind, eventutil.BindingAction, "Successfully assigned %v to %v", podutil.GetPodKey(task.queuedPodInfo.ReservedPod), task.suggestedNode)
 
 			if utilfeature.DefaultFeatureGate.Enabled(features.SupportRescheduling) {
 				// update movement status if necessary
@@ -1151,7 +1300,7 @@ func (binder *Binder) bindTasks(ctx context.Context, unitInfo *bindingUnitInfo)
 	return failedTaskToError
 }
 
-func deleteVictimsForTask(cli clientset.Interface, task *runningUnitInfo) (returnErr error) {
+func deleteVictimsForTask(cli clientset.Interface, recorder events.EventRecorder, task *runningUnitInfo) (returnErr error) {
 	podTrace := task.getSchedulingTrace()
 	traceContext := podTrace.NewTraceContext(tracing.RootSpan, tracing.BinderDeleteVictimsSpan)
 	traceContext.WithFields(tracing.WithNominatedNodeField(task.queuedPodInfo.NominatedNode.Marshall()))
@@ -1183,6 +1332,10 @@ func deleteVictimsForTask(cli clientset.Interface, task *runningUnitInfo) (retur
 			returnErr = fmt.Errorf("fail to delete victims for pod: %v/%v, error: %v", task.queuedPodInfo.Pod.Namespace, task.queuedPodInfo.Pod.Name, err)
 			return
 		}
+		if recorder != nil {
+			recorder.Eventf(victim, task.queuedPodInfo.Pod, v1.EventTypeNormal, eventutil.Preempted, eventutil.PreemptingAction,
+				"Preempted by pod %s on node %s", podutil.GetPodKey(task.queuedPodInfo.Pod), task.suggestedNode)
+		}
 	}
 	return nil
 }
@@ -1206,7 +1359,7 @@ func (binder *Binder) deleteVictimsOfNewTasks(ctx context.Context, unitInfo *bin
 	deleteVictims := func(i int) {
 		preemptor := newPreemptors[i]
 		// delete victims
-		err := deleteVictimsForTask(binder.handle.ClientSet(), preemptor)
+		err := deleteVictimsForTask(binder.handle.ClientSet(), binder.recorder, preemptor)
 		if err != nil {
 			failedNodeLock.Lock()
 			failedNodeMap[preemptor.suggestedNode] = err
@@ -76,6 +76,7 @@ func NewFrameworkHandle(
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
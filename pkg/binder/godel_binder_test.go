@@ -1432,6 +1432,7 @@ func TestCoscheduling(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1524,6 +1525,7 @@ func TestCoscheduling_Error_InvalidPodGroup(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1610,6 +1612,7 @@ func TestCoscheduling_Waiting(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -1700,6 +1703,7 @@ func TestCoscheduling_Timeout(t *testing.T) {
 			informerFactory.Core().V1().PersistentVolumeClaims(),
 			informerFactory.Core().V1().PersistentVolumes(),
 			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 			time.Duration(volumeBindingTimeoutSeconds)*time.Second,
 		),
 	}
@@ -2002,3 +2006,59 @@ func TestCheckCrossNodePreemptionForUnit(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckAchievedTopologyLevel(t *testing.T) {
+	nodeInfos := map[string]framework.NodeInfo{}
+	for _, node := range []*v1.Node{
+		testinghelper.MakeNode().Name("n1").Label("leaf", "l1").Label("spine", "s1").Obj(),
+		testinghelper.MakeNode().Name("n2").Label("leaf", "l1").Label("spine", "s1").Obj(),
+		testinghelper.MakeNode().Name("n3").Label("leaf", "l2").Label("spine", "s1").Obj(),
+		testinghelper.MakeNode().Name("n4").Label("spine", "s1").Obj(),
+	} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		nodeInfos[node.Name] = nodeInfo
+	}
+	getNodeInfo := func(name string) framework.NodeInfo { return nodeInfos[name] }
+	makeTask := func(name, node, level string) *runningUnitInfo {
+		pod := testinghelper.MakePod().Namespace("default").Name(name).UID(name).
+			Annotation(podutil.PodLauncherAnnotationKey, string(podutil.Kubelet))
+		if len(level) > 0 {
+			pod = pod.Annotation(podutil.AchievedTopologyLevelAnnotationKey, level)
+		}
+		return &runningUnitInfo{suggestedNode: node, queuedPodInfo: &framework.QueuedPodInfo{Pod: pod.Obj()}}
+	}
+
+	tests := []struct {
+		name      string
+		tasks     []*runningUnitInfo
+		expectErr bool
+	}{
+		{
+			name:  "tasks are in the achieved level",
+			tasks: []*runningUnitInfo{makeTask("p1", "n1", "leaf"), makeTask("p2", "n2", "leaf")},
+		},
+		{
+			name:      "tasks are placed across the achieved level",
+			tasks:     []*runningUnitInfo{makeTask("p1", "n1", "leaf"), makeTask("p2", "n3", "leaf")},
+			expectErr: true,
+		},
+		{
+			name:      "node doesn't belong to the achieved level",
+			tasks:     []*runningUnitInfo{makeTask("p1", "n1", "leaf"), makeTask("p2", "n4", "leaf")},
+			expectErr: true,
+		},
+		{
+			name:  "tasks without achieved level are not checked",
+			tasks: []*runningUnitInfo{makeTask("p1", "n1", ""), makeTask("p2", "n3", "")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAchievedTopologyLevel(tt.tasks, getNodeInfo)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -48,6 +48,15 @@ const (
 	// PostBindEvaluation - operation label value
 	PostBindEvaluation = "postbind_evaluation"
 
+	// ClusterPrePreemptingEvaluation - operation label value
+	ClusterPrePreemptingEvaluation = "cluster_prepreempting_evaluation"
+
+	// VictimCheckingEvaluation - operation label value
+	VictimCheckingEvaluation = "victim_checking_evaluation"
+
+	// PostVictimCheckingEvaluation - operation label value
+	PostVictimCheckingEvaluation = "post_victim_checking_evaluation"
+
 	// SuccessResult - result label value
 	SuccessResult = "success"
 
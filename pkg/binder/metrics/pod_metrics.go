@@ -151,6 +151,14 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.OperationLabel, pkgmetrics.PluginLabel, pkgmetrics.StatusLabel, pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel})
 
+	pluginEvaluationTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "plugin_evaluation_total",
+			Help:           "Number of plugin evaluations at a specific extension point, by the returned status code.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.OperationLabel, pkgmetrics.PluginLabel, pkgmetrics.StatusLabel, pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel})
+
 	// TODO: remove
 	bindingFailures = metrics.NewCounterVec(
 		&metrics.CounterOpts{
@@ -323,7 +331,7 @@ func newBindingStageDurationObserverMetric(labels metrics.Labels) metrics.Observ
 	return bindingStagePluginDuration.With(labels)
 }
 
-// BindingStageDurationObserve Invoke Observe method
+// BindingStageDurationObserve Invoke Observe method, and counts the plugin evaluation by its status.
 // podLabels contains basic object property labels
 func BindingStageDurationObserve(podProperty *api.PodProperty, operation, plugin, status string, duration float64) {
 	podLabels := podProperty.ConvertToMetricsLabels()
@@ -331,6 +339,7 @@ func BindingStageDurationObserve(podProperty *api.PodProperty, operation, plugin
 	podLabels[pkgmetrics.PluginLabel] = plugin
 	podLabels[pkgmetrics.StatusLabel] = status
 	newBindingStageDurationObserverMetric(podLabels).Observe(duration)
+	pluginEvaluationTotal.With(podLabels).Inc()
 }
 
 // newPendingPodsGaugeMetric returns the GaugeMetric for given labels by PendingPods
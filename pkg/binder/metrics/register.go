@@ -39,6 +39,7 @@ var metricsList = []metrics.Registerable{
 	podE2EBinderLatency,
 	e2eBinderLatencyQuantile,
 	bindingStagePluginDuration,
+	pluginEvaluationTotal,
 	preemptVictimPodsCycleLatency,
 	bindingCycleLatency,
 	podOperatingLatency,
@@ -54,6 +55,7 @@ var metricsList = []metrics.Registerable{
 	podE2ELatency,
 	podE2ELatencyQuantile,
 	podGroupE2ELatency,
+	podGroupPhaseTransitions,
 
 	movementUpdateAttempts,
 
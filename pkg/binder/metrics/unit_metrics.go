@@ -49,6 +49,14 @@ var (
 			Buckets:        metrics.ExponentialBuckets(0.001, 2, 20),
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.UnitTypeLabel})
+
+	podGroupPhaseTransitions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      BinderSubsystem,
+			Name:           "podgroup_phase_transitions_total",
+			Help:           "Number of PodGroup phase transitions made by PodGroup controller, by the phases before and after the transition.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.FromPhaseLabel, pkgmetrics.PhaseLabel})
 )
 
 // newPendingUnitsGaugeMetric returns the GaugeMetric for given labels by PendingUnits
@@ -90,3 +98,15 @@ func BinderUnitE2ELatencyObserve(unitProperty api.UnitProperty, duration float64
 	unitLabels := api.MustConvertToMetricsLabels(unitProperty)
 	newBinderUnitE2ELatency(unitLabels).Observe(duration)
 }
+
+func newPodGroupPhaseTransitions(labels metrics.Labels) metrics.CounterMetric {
+	return podGroupPhaseTransitions.With(labels)
+}
+
+// PodGroupPhaseTransitionInc Invoke Inc method
+// basicLabels contains basic object property labels
+func PodGroupPhaseTransitionInc(basicLabels metrics.Labels, from, to string) {
+	basicLabels[pkgmetrics.FromPhaseLabel] = from
+	basicLabels[pkgmetrics.PhaseLabel] = to
+	newPodGroupPhaseTransitions(basicLabels).Inc()
+}
@@ -34,12 +34,16 @@ var defaultBinderOptions = binderOptions{
 	},
 	preemptionPluginConfigs: map[string]*config.PluginConfig{},
 	pluginConfigs:           map[string]*config.PluginConfig{},
+
+	pluginMetricsSamplePercent: config.DefaultPluginMetricsSamplePercent,
 }
 
 type binderOptions struct {
 	victimCheckingPluginSet []*framework.VictimCheckingPluginCollectionSpec
 	preemptionPluginConfigs map[string]*config.PluginConfig
 	pluginConfigs           map[string]*config.PluginConfig
+
+	pluginMetricsSamplePercent int32
 }
 
 // Option configures a Scheduler
@@ -68,6 +72,13 @@ func WithPluginsAndConfigs(profile *config.GodelBinderProfile) Option {
 	}
 }
 
+// WithPluginMetricsSamplePercent sets the percentage of binding cycles recording plugin metrics, the default value is 100
+func WithPluginMetricsSamplePercent(percent int32) Option {
+	return func(o *binderOptions) {
+		o.pluginMetricsSamplePercent = percent
+	}
+}
+
 func renderOptions(opts ...Option) binderOptions {
 	options := defaultBinderOptions
 	for _, opt := range opts {
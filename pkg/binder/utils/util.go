@@ -39,6 +39,7 @@ func CleanupPodAnnotations(client clientset.Interface, pod *v1.Pod) error {
 	delete(podCopy.Annotations, podutil.NominatedNodeAnnotationKey)
 	delete(podCopy.Annotations, podutil.FailedSchedulersAnnotationKey)
 	delete(podCopy.Annotations, podutil.MicroTopologyKey)
+	delete(podCopy.Annotations, podutil.GPUMicroTopologyKey)
 	delete(podCopy.Annotations, podutil.MovementNameKey)
 	delete(podCopy.Annotations, podutil.MatchedReservationPlaceholderKey)
 
@@ -0,0 +1,331 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	unitstatus "github.com/kubewharf/godel-scheduler/pkg/util/unitstatus"
+)
+
+// CheckpointVersion is the version of the checkpoint format, checkpoints of other versions are ignored.
+const CheckpointVersion = "v1"
+
+// Checkpoint is the state of a cache, which is made up of the objects received from informers and
+// the unit statuses set by the component itself. The pods assumed by the scheduler are recorded in
+// AssumedPods, they expire after restart unless informers confirm them.
+type Checkpoint struct {
+	Version       string      `json:"version"`
+	ComponentName string      `json:"componentName"`
+	Timestamp     metav1.Time `json:"timestamp"`
+
+	Nodes     []*v1.Node                             `json:"nodes,omitempty"`
+	NMNodes   []*nodev1alpha1.NMNode                 `json:"nmNodes,omitempty"`
+	CNRs      []*katalystv1alpha1.CustomNodeResource `json:"cnrs,omitempty"`
+	PodGroups []*schedulingv1a1.PodGroup             `json:"podGroups,omitempty"`
+	Pods      []*v1.Pod                              `json:"pods,omitempty"`
+	Units     map[string]unitstatus.SchedulingStatus `json:"units,omitempty"`
+	// AssumedPods are the pods assumed by the scheduler but not received from informers yet.
+	AssumedPods []*v1.Pod `json:"assumedPods,omitempty"`
+
+	PDBs          []*policy.PodDisruptionBudget `json:"pdbs,omitempty"`
+	Owners        []*CheckpointOwner            `json:"owners,omitempty"`
+	Reservations  []*schedulingv1a1.Reservation `json:"reservations,omitempty"`
+	Movements     []*schedulingv1a1.Movement    `json:"movements,omitempty"`
+	ElasticQuotas []*quotav1alpha1.ElasticQuota `json:"elasticQuotas,omitempty"`
+}
+
+// CheckpointOwner is an owner of pods tracked by the PDB store, which is recorded by its labels only.
+type CheckpointOwner struct {
+	Type   string            `json:"type"`
+	Key    string            `json:"key"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Checkpointer is implemented by the caches supporting warm restart.
+type Checkpointer interface {
+	// Checkpoint returns the current state of the cache.
+	Checkpoint() *Checkpoint
+	// Restore replays the checkpoint into an empty cache. The restored objects are reconciled with
+	// the following Add events of informers by resourceVersion.
+	Restore(checkpoint *Checkpoint) error
+	// FinishReconciling removes the restored objects which are not confirmed by informers, and returns
+	// the number of them. It should be called after the informers are synced.
+	FinishReconciling() int
+	// Reconciling returns true if the cache is restored and not reconciled yet.
+	Reconciling() bool
+}
+
+// SaveCheckpoint writes the checkpoint to the path atomically.
+func SaveCheckpoint(path string, checkpoint *Checkpoint) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(w).Encode(checkpoint); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadCheckpoint reads the checkpoint of the component from the path. Checkpoints of other versions,
+// of other components or older than maxAge are rejected.
+func LoadCheckpoint(path, componentName string, maxAge time.Duration) (*Checkpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	checkpoint := &Checkpoint{}
+	if err := json.NewDecoder(r).Decode(checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.Version != CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %q, expected %q", checkpoint.Version, CheckpointVersion)
+	}
+	if checkpoint.ComponentName != componentName {
+		return nil, fmt.Errorf("checkpoint of %q can't be used by %q", checkpoint.ComponentName, componentName)
+	}
+	if age := time.Since(checkpoint.Timestamp.Time); maxAge > 0 && age > maxAge {
+		return nil, fmt.Errorf("checkpoint is too old, age: %v, max age: %v", age, maxAge)
+	}
+	return checkpoint, nil
+}
+
+// RunCheckpointer saves the checkpoint of the cache to the path periodically until stopCh is closed.
+// Nothing is saved while the cache is reconciling, since a part of it may be stale.
+func RunCheckpointer(c Checkpointer, path string, period time.Duration, stopCh <-chan struct{}) {
+	go wait.Until(func() {
+		if c.Reconciling() {
+			return
+		}
+		start := time.Now()
+		if err := SaveCheckpoint(path, c.Checkpoint()); err != nil {
+			klog.ErrorS(err, "Failed to save cache checkpoint", "path", path)
+			return
+		}
+		klog.V(4).InfoS("Saved cache checkpoint", "path", path, "cost", time.Since(start))
+	}, period, stopCh)
+}
+
+// ---------------------------------------------------------------------------------------
+
+// Kinds of the objects tracked by CheckpointReconciler.
+const (
+	CheckpointKindNode         = "Node"
+	CheckpointKindNMNode       = "NMNode"
+	CheckpointKindCNR          = "CNR"
+	CheckpointKindPodGroup     = "PodGroup"
+	CheckpointKindPod          = "Pod"
+	CheckpointKindPDB          = "PDB"
+	CheckpointKindOwner        = "Owner"
+	CheckpointKindReservation  = "Reservation"
+	CheckpointKindMovement     = "Movement"
+	CheckpointKindElasticQuota = "ElasticQuota"
+)
+
+// checkpointKindsInDeletionOrder lists the kinds in the order to remove stale objects, pods are removed
+// before the nodes they are placed on.
+var checkpointKindsInDeletionOrder = []string{
+	CheckpointKindReservation,
+	CheckpointKindMovement,
+	CheckpointKindPod,
+	CheckpointKindPodGroup,
+	CheckpointKindPDB,
+	CheckpointKindOwner,
+	CheckpointKindElasticQuota,
+	CheckpointKindCNR,
+	CheckpointKindNMNode,
+	CheckpointKindNode,
+}
+
+// CheckpointReconciler tracks the objects restored from a checkpoint until they are confirmed by informers.
+// It's not thread safe, callers should hold the lock of cache.
+type CheckpointReconciler struct {
+	pending map[string]map[string]interface{}
+}
+
+func NewCheckpointReconciler() *CheckpointReconciler {
+	return &CheckpointReconciler{pending: make(map[string]map[string]interface{})}
+}
+
+func checkpointObjectKey(obj metav1.Object) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+func checkpointOwnerKey(ownerType, key string) string {
+	return ownerType + "/" + key
+}
+
+func (r *CheckpointReconciler) track(kind, key string, obj interface{}) {
+	if r.pending[kind] == nil {
+		r.pending[kind] = make(map[string]interface{})
+	}
+	r.pending[kind][key] = obj
+}
+
+func (r *CheckpointReconciler) confirm(kind, key string) (interface{}, bool) {
+	restored, ok := r.pending[kind][key]
+	if ok {
+		delete(r.pending[kind], key)
+	}
+	return restored, ok
+}
+
+// Track starts tracking the restored object.
+func (r *CheckpointReconciler) Track(kind string, obj metav1.Object) {
+	r.track(kind, checkpointObjectKey(obj), obj)
+}
+
+// Confirm stops tracking the object, and returns the restored one if it's tracked.
+func (r *CheckpointReconciler) Confirm(kind string, obj metav1.Object) (metav1.Object, bool) {
+	restored, ok := r.confirm(kind, checkpointObjectKey(obj))
+	if !ok {
+		return nil, false
+	}
+	return restored.(metav1.Object), true
+}
+
+// TrackOwner starts tracking the restored owner.
+func (r *CheckpointReconciler) TrackOwner(owner *CheckpointOwner) {
+	r.track(CheckpointKindOwner, checkpointOwnerKey(owner.Type, owner.Key), owner)
+}
+
+// ConfirmOwner stops tracking the owner, and returns the restored one if it's tracked.
+func (r *CheckpointReconciler) ConfirmOwner(ownerType, key string) (*CheckpointOwner, bool) {
+	restored, ok := r.confirm(CheckpointKindOwner, checkpointOwnerKey(ownerType, key))
+	if !ok {
+		return nil, false
+	}
+	return restored.(*CheckpointOwner), true
+}
+
+// Range calls f on the objects not confirmed yet, in the order they should be removed. The objects are
+// metav1.Object, except owners which are *CheckpointOwner.
+func (r *CheckpointReconciler) Range(f func(kind, key string, obj interface{})) {
+	for _, kind := range checkpointKindsInDeletionOrder {
+		for key, obj := range r.pending[kind] {
+			f(kind, key, obj)
+		}
+	}
+}
+
+// CheckpointObjects keeps the latest objects received by a cache for the kinds whose stores don't keep them
+// as they are, so that they can be checkpointed. It's not thread safe, callers should hold the lock of cache.
+// All the methods are no-op on nil, which is used when checkpoint is disabled.
+type CheckpointObjects struct {
+	objects map[string]map[string]interface{}
+}
+
+func NewCheckpointObjects() *CheckpointObjects {
+	return &CheckpointObjects{objects: make(map[string]map[string]interface{})}
+}
+
+func (o *CheckpointObjects) set(kind, key string, obj interface{}) {
+	if o == nil {
+		return
+	}
+	if o.objects[kind] == nil {
+		o.objects[kind] = make(map[string]interface{})
+	}
+	o.objects[kind][key] = obj
+}
+
+func (o *CheckpointObjects) delete(kind, key string) {
+	if o == nil {
+		return
+	}
+	delete(o.objects[kind], key)
+}
+
+// Set records the latest object.
+func (o *CheckpointObjects) Set(kind string, obj metav1.Object) {
+	o.set(kind, checkpointObjectKey(obj), obj)
+}
+
+// Delete removes the object.
+func (o *CheckpointObjects) Delete(kind string, obj metav1.Object) {
+	o.delete(kind, checkpointObjectKey(obj))
+}
+
+// SetOwner records the latest labels of the owner.
+func (o *CheckpointObjects) SetOwner(ownerType, key string, labels map[string]string) {
+	o.set(CheckpointKindOwner, checkpointOwnerKey(ownerType, key), &CheckpointOwner{Type: ownerType, Key: key, Labels: labels})
+}
+
+// DeleteOwner removes the owner.
+func (o *CheckpointObjects) DeleteOwner(ownerType, key string) {
+	o.delete(CheckpointKindOwner, checkpointOwnerKey(ownerType, key))
+}
+
+// Fill appends the recorded objects to the checkpoint.
+func (o *CheckpointObjects) Fill(checkpoint *Checkpoint) {
+	if o == nil {
+		return
+	}
+	for _, obj := range o.objects[CheckpointKindPDB] {
+		checkpoint.PDBs = append(checkpoint.PDBs, obj.(*policy.PodDisruptionBudget))
+	}
+	for _, obj := range o.objects[CheckpointKindOwner] {
+		checkpoint.Owners = append(checkpoint.Owners, obj.(*CheckpointOwner))
+	}
+	for _, obj := range o.objects[CheckpointKindReservation] {
+		checkpoint.Reservations = append(checkpoint.Reservations, obj.(*schedulingv1a1.Reservation))
+	}
+	for _, obj := range o.objects[CheckpointKindMovement] {
+		checkpoint.Movements = append(checkpoint.Movements, obj.(*schedulingv1a1.Movement))
+	}
+	for _, obj := range o.objects[CheckpointKindElasticQuota] {
+		checkpoint.ElasticQuotas = append(checkpoint.ElasticQuotas, obj.(*quotav1alpha1.ElasticQuota))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
 
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 )
 
@@ -118,4 +119,8 @@ type ClusterEventsHandler interface {
 	AddReservation(request *schedulingv1a1.Reservation) error
 	UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reservation) error
 	DeleteReservation(request *schedulingv1a1.Reservation) error
+
+	AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error
+	UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error
+	DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error
 }
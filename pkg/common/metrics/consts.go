@@ -31,6 +31,9 @@ const (
 	// EventLabel - the label used by event
 	EventLabel = "event"
 
+	// ExtensionPointLabel - the label used to identify the framework extension point a plugin runs at
+	ExtensionPointLabel = "extension_point"
+
 	QosLabel             = "qos"
 	PriorityLabel        = "priority"
 	SubClusterLabel      = "sub_cluster"
@@ -55,4 +58,5 @@ const (
 	UpdateResultLabel        = "updateResult"
 	SuggestResultLabel       = "suggestResult"
 	PhaseLabel               = "phase"
+	FromPhaseLabel           = "fromPhase"
 )
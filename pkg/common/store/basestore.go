@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
 
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 )
@@ -86,10 +87,13 @@ func (i *BaseStoreImpl) AddOwner(ownerType, key string, labels map[string]string
 func (i *BaseStoreImpl) UpdateOwner(ownerType, key string, oldLabels, newLabels map[string]string) error {
 	return nil
 }
-func (i *BaseStoreImpl) DeleteOwner(ownerType, key string) error                { return nil }
-func (i *BaseStoreImpl) AddMovement(movement *schedulingv1a1.Movement) error    { return nil }
-func (i *BaseStoreImpl) UpdateMovement(_, _ *schedulingv1a1.Movement) error     { return nil }
-func (i *BaseStoreImpl) DeleteMovement(movement *schedulingv1a1.Movement) error { return nil }
+func (i *BaseStoreImpl) DeleteOwner(ownerType, key string) error                   { return nil }
+func (i *BaseStoreImpl) AddMovement(movement *schedulingv1a1.Movement) error       { return nil }
+func (i *BaseStoreImpl) UpdateMovement(_, _ *schedulingv1a1.Movement) error        { return nil }
+func (i *BaseStoreImpl) DeleteMovement(movement *schedulingv1a1.Movement) error    { return nil }
+func (i *BaseStoreImpl) AddElasticQuota(*quotav1alpha1.ElasticQuota) error         { return nil }
+func (i *BaseStoreImpl) UpdateElasticQuota(_, _ *quotav1alpha1.ElasticQuota) error { return nil }
+func (i *BaseStoreImpl) DeleteElasticQuota(*quotav1alpha1.ElasticQuota) error      { return nil }
 
 func (i *BaseStoreImpl) AssumePod(podInfo *framework.CachePodInfo) error { return nil }
 func (i *BaseStoreImpl) ForgetPod(podInfo *framework.CachePodInfo) error { return nil }
@@ -27,6 +27,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 )
 
@@ -269,3 +270,21 @@ func (s *CommonStoresSwitchImpl) DeleteMovement(movement *schedulingv1a1.Movemen
 	defer s.mu.Unlock()
 	return s.Range(func(s Store) error { return s.DeleteMovement(movement) })
 }
+
+func (s *CommonStoresSwitchImpl) AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Range(func(s Store) error { return s.AddElasticQuota(quota) })
+}
+
+func (s *CommonStoresSwitchImpl) UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Range(func(s Store) error { return s.UpdateElasticQuota(oldQuota, newQuota) })
+}
+
+func (s *CommonStoresSwitchImpl) DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Range(func(s Store) error { return s.DeleteElasticQuota(quota) })
+}
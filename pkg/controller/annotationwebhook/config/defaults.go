@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const DefaultSchedulerName = "godel-scheduler"
+
+func SetDefaultAnnotationWebhook(obj *AnnotationWebhookConfiguration) {
+	if len(obj.SchedulerNames) == 0 {
+		obj.SchedulerNames = []string{DefaultSchedulerName}
+	}
+	if len(obj.DefaultResourceType) == 0 {
+		obj.DefaultResourceType = string(podutil.GuaranteedPod)
+	}
+	if len(obj.DefaultLauncher) == 0 {
+		obj.DefaultLauncher = string(podutil.Kubelet)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+type AnnotationWebhookConfiguration struct {
+	// Enabled indicates whether the annotation webhooks are served by the controller manager.
+	Enabled bool
+	// SchedulerNames is the list of scheduler names whose pods are validated and defaulted.
+	SchedulerNames []string
+	// DefaultResourceType is set on pods that specify neither resource type nor qos level.
+	DefaultResourceType string
+	// DefaultLauncher is set on pods that don't specify the launcher.
+	DefaultLauncher string
+	// ExtraPlugins is the list of out-of-tree plugins that could be referenced in constraint annotations,
+	// in addition to the in-tree ones.
+	ExtraPlugins []string
+	// IgnoredNamespace is the list of namespace whose objects are never validated or defaulted.
+	IgnoredNamespace []string
+}
+
+func NewAnnotationWebhookConfiguration() *AnnotationWebhookConfiguration {
+	return &AnnotationWebhookConfiguration{}
+}
+
+func (c *AnnotationWebhookConfiguration) DeepCopyInto(out *AnnotationWebhookConfiguration) {
+	*out = *c
+	if c.SchedulerNames != nil {
+		out.SchedulerNames = make([]string, len(c.SchedulerNames))
+		copy(out.SchedulerNames, c.SchedulerNames)
+	}
+	if c.ExtraPlugins != nil {
+		out.ExtraPlugins = make([]string, len(c.ExtraPlugins))
+		copy(out.ExtraPlugins, c.ExtraPlugins)
+	}
+	if c.IgnoredNamespace != nil {
+		out.IgnoredNamespace = make([]string, len(c.IgnoredNamespace))
+		copy(out.IgnoredNamespace, c.IgnoredNamespace)
+	}
+}
+
+func (c *AnnotationWebhookConfiguration) DeepCopy() *AnnotationWebhookConfiguration {
+	if c == nil {
+		return nil
+	}
+	out := new(AnnotationWebhookConfiguration)
+	c.DeepCopyInto(out)
+	return out
+}
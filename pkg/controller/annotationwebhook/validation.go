@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotationwebhook
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	frameworkconfig "github.com/kubewharf/godel-scheduler/pkg/framework/api/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/constraints"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+var annotationsPath = field.NewPath("metadata", "annotations")
+
+// ValidatePodAnnotations validates the Gödel-specific annotations of the pod. If oldPod isn't nil, only the
+// annotations changed since oldPod are validated, so that existing pods are never blocked.
+func ValidatePodAnnotations(pod, oldPod *v1.Pod, plugins sets.String) field.ErrorList {
+	changed := func(key string) bool {
+		val, ok := pod.Annotations[key]
+		if !ok {
+			return false
+		}
+		if oldPod == nil {
+			return true
+		}
+		oldVal, oldOk := oldPod.Annotations[key]
+		return !oldOk || oldVal != val
+	}
+
+	var allErrs field.ErrorList
+	if changed(podutil.PodResourceTypeAnnotationKey) {
+		if _, err := podutil.GetPodResourceType(pod); err != nil {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.PodResourceTypeAnnotationKey, err.Error()))
+		}
+	}
+	if changed(podutil.PodLauncherAnnotationKey) {
+		if _, err := podutil.GetPodLauncher(pod); err != nil {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.PodLauncherAnnotationKey, err.Error()))
+		}
+	}
+	if changed(util.PreemptionPolicyKey) {
+		switch v1.PreemptionPolicy(pod.Annotations[util.PreemptionPolicyKey]) {
+		case v1.PreemptNever, v1.PreemptLowerPriority:
+		default:
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, util.PreemptionPolicyKey,
+				fmt.Sprintf("must be %s or %s", v1.PreemptNever, v1.PreemptLowerPriority)))
+		}
+	}
+	if changed(podutil.ProtectionDurationFromPreemptionKey) {
+		if duration, ok := podutil.GetProtectionDuration(podutil.GetPodKey(pod), pod.Annotations); !ok || duration < 0 {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.ProtectionDurationFromPreemptionKey,
+				"must be a non-negative integer of seconds"))
+		}
+	}
+	if changed(podutil.PodRequestTemplateAnnotationKey) {
+		for _, msg := range validation.IsDNS1123Subdomain(pod.Annotations[podutil.PodRequestTemplateAnnotationKey]) {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.PodRequestTemplateAnnotationKey, msg))
+		}
+	}
+	if changed(podutil.ReservationTTLKey) {
+		if podutil.GetPodReservationTimeoutPeriod(pod) <= 0 {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.ReservationTTLKey,
+				"must be a positive integer of seconds"))
+		}
+	}
+	if changed(podutil.IncreasePercentageOfNodesToScoreAnnotationKey) {
+		if _, err := strconv.ParseBool(pod.Annotations[podutil.IncreasePercentageOfNodesToScoreAnnotationKey]); err != nil {
+			allErrs = append(allErrs, invalidAnnotation(pod.Annotations, podutil.IncreasePercentageOfNodesToScoreAnnotationKey,
+				"must be true or false"))
+		}
+	}
+	for _, key := range []string{constraints.HardConstraintsAnnotationKey, constraints.SoftConstraintsAnnotationKey} {
+		if changed(key) {
+			allErrs = append(allErrs, validateConstraints(pod.Annotations, key, plugins)...)
+		}
+	}
+	return allErrs
+}
+
+// ValidatePodGroup validates the spec and the Gödel-specific annotations of the PodGroup.
+func ValidatePodGroup(pg *schedulingv1a1.PodGroup, plugins sets.String) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if pg.Spec.MinMember <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("minMember"), pg.Spec.MinMember, "must be positive"))
+	}
+	if pg.Spec.ScheduleTimeoutSeconds != nil && *pg.Spec.ScheduleTimeoutSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("scheduleTimeoutSeconds"), *pg.Spec.ScheduleTimeoutSeconds, "must be positive"))
+	}
+	if affinity := pg.Spec.Affinity; affinity != nil {
+		allErrs = append(allErrs, validatePodGroupAffinity(affinity, specPath.Child("affinity"))...)
+	}
+
+	if val, ok := pg.Annotations[unit.PodGroupFailurePolicyAnnotationKey]; ok {
+		switch unit.PodGroupFailurePolicy(val) {
+		case unit.PodGroupFailurePolicyNone, unit.PodGroupFailurePolicyRestartGang:
+		default:
+			allErrs = append(allErrs, invalidAnnotation(pg.Annotations, unit.PodGroupFailurePolicyAnnotationKey,
+				fmt.Sprintf("must be %s or %s", unit.PodGroupFailurePolicyNone, unit.PodGroupFailurePolicyRestartGang)))
+		}
+	}
+	if val, ok := pg.Annotations[unit.PodGroupMaxRestartsAnnotationKey]; ok {
+		if n, err := strconv.Atoi(val); err != nil || n < 0 {
+			allErrs = append(allErrs, invalidAnnotation(pg.Annotations, unit.PodGroupMaxRestartsAnnotationKey, "must be a non-negative integer"))
+		}
+	}
+	for _, key := range []string{constraints.HardConstraintsAnnotationKey, constraints.SoftConstraintsAnnotationKey} {
+		if _, ok := pg.Annotations[key]; ok {
+			allErrs = append(allErrs, validateConstraints(pg.Annotations, key, plugins)...)
+		}
+	}
+	return allErrs
+}
+
+func validatePodGroupAffinity(affinity *schedulingv1a1.Affinity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if a := affinity.PodGroupAffinity; a != nil {
+		path := fldPath.Child("podGroupAffinity")
+		allErrs = append(allErrs, validateAffinityTerms(a.Required, path.Child("required"))...)
+		allErrs = append(allErrs, validateAffinityTerms(a.Preferred, path.Child("preferred"))...)
+		for i, r := range a.SortRules {
+			// Empty dimension is allowed for backward compatibility, see PodGroupUnit.GetSortRulesForAffinity.
+			dimension := r.Dimension
+			if len(dimension) == 0 {
+				dimension = schedulingv1a1.Capacity
+			}
+			rule := framework.SortRule{
+				Resource:  framework.SortResource(r.Resource),
+				Dimension: framework.SortDimension(dimension),
+				Order:     framework.SortOrder(r.Order),
+			}
+			if !rule.Valid() {
+				allErrs = append(allErrs, field.Invalid(path.Child("sortRules").Index(i), r,
+					"resource must be GPU, CPU or Memory, dimension must be Capacity or Available, order must be Ascending or Descending"))
+			}
+		}
+		if a.NodeSelector != nil {
+			for i, term := range a.NodeSelector.NodeSelectorTerms {
+				termPath := path.Child("nodeSelector", "nodeSelectorTerms").Index(i)
+				if _, err := helper.NodeSelectorRequirementsAsSelector(term.MatchExpressions); err != nil {
+					allErrs = append(allErrs, field.Invalid(termPath.Child("matchExpressions"), term.MatchExpressions, err.Error()))
+				}
+				if _, err := helper.NodeSelectorRequirementsAsFieldSelector(term.MatchFields); err != nil {
+					allErrs = append(allErrs, field.Invalid(termPath.Child("matchFields"), term.MatchFields, err.Error()))
+				}
+			}
+		}
+	}
+	if a := affinity.PodGroupAntiAffinity; a != nil {
+		path := fldPath.Child("podGroupAntiAffinity")
+		allErrs = append(allErrs, validateAffinityTerms(a.Required, path.Child("required"))...)
+		allErrs = append(allErrs, validateAffinityTerms(a.Preferred, path.Child("preferred"))...)
+	}
+	return allErrs
+}
+
+func validateAffinityTerms(terms []schedulingv1a1.PodGroupAffinityTerm, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, term := range terms {
+		path := fldPath.Index(i).Child("topologyKey")
+		if len(term.TopologyKey) == 0 {
+			allErrs = append(allErrs, field.Required(path, "topologyKey must not be empty"))
+			continue
+		}
+		for _, msg := range validation.IsQualifiedName(term.TopologyKey) {
+			allErrs = append(allErrs, field.Invalid(path, term.TopologyKey, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateConstraints checks the constraint annotation could be parsed and only references known plugins.
+func validateConstraints(annotations map[string]string, key string, plugins sets.String) field.ErrorList {
+	// GetConstraints only looks at the annotations, so PodGroups are validated in the same way as pods.
+	constraintList, err := frameworkconfig.GetConstraints(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}, key)
+	if err != nil {
+		return field.ErrorList{invalidAnnotation(annotations, key, err.Error())}
+	}
+	var allErrs field.ErrorList
+	for _, c := range constraintList {
+		if !plugins.Has(c.PluginName) {
+			allErrs = append(allErrs, invalidAnnotation(annotations, key, fmt.Sprintf("unknown plugin %q", c.PluginName)))
+		}
+	}
+	return allErrs
+}
+
+func invalidAnnotation(annotations map[string]string, key, msg string) *field.Error {
+	return field.Invalid(annotationsPath.Key(key), annotations[key], msg)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotationwebhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	schedulerframework "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/admission"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	// ValidatingWebhookPath is where the validating webhook of pods and PodGroups is served by the controller manager.
+	ValidatingWebhookPath = "/validate-godel-annotations"
+	// MutatingWebhookPath is where the mutating webhook of pods is served by the controller manager.
+	MutatingWebhookPath = "/mutate-godel-annotations"
+)
+
+// AnnotationWebhook validates and defaults the Gödel-specific annotations, so that typos are rejected
+// on admission instead of being silently ignored by the scheduler.
+type AnnotationWebhook struct {
+	schedulerNames      sets.String
+	ignoredNamespaces   sets.String
+	plugins             sets.String
+	defaultResourceType podutil.PodResourceType
+	defaultLauncher     podutil.PodLauncher
+}
+
+// NewAnnotationWebhook returns the webhook, plugins referenced in constraint annotations must be either
+// in-tree scheduler plugins or listed in ExtraPlugins.
+func NewAnnotationWebhook(cfg *config.AnnotationWebhookConfiguration) *AnnotationWebhook {
+	plugins := sets.NewString(cfg.ExtraPlugins...)
+	for name := range schedulerframework.NewInTreeRegistry() {
+		plugins.Insert(name)
+	}
+	return &AnnotationWebhook{
+		schedulerNames:      sets.NewString(cfg.SchedulerNames...),
+		ignoredNamespaces:   sets.NewString(cfg.IgnoredNamespace...),
+		plugins:             plugins,
+		defaultResourceType: podutil.PodResourceType(cfg.DefaultResourceType),
+		defaultLauncher:     podutil.PodLauncher(cfg.DefaultLauncher),
+	}
+}
+
+// ValidatingHandler returns the handler validating pods and PodGroups on creation and update.
+func (w *AnnotationWebhook) ValidatingHandler() http.Handler {
+	return admission.NewHandler("annotation-validating", func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		if (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) || w.ignoredNamespaces.Has(req.Namespace) {
+			return admission.Allowed()
+		}
+		switch req.Kind.Kind {
+		case "Pod":
+			return w.validatePod(req)
+		case podutil.PodGroupKind:
+			return w.validatePodGroup(req)
+		default:
+			return admission.Allowed()
+		}
+	})
+}
+
+// MutatingHandler returns the handler defaulting the resource type and launcher of pods on creation.
+func (w *AnnotationWebhook) MutatingHandler() http.Handler {
+	return admission.NewHandler("annotation-mutating", func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		if req.Operation != admissionv1.Create || req.Kind.Kind != "Pod" || w.ignoredNamespaces.Has(req.Namespace) {
+			return admission.Allowed()
+		}
+		pod := &v1.Pod{}
+		if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !w.schedulerNames.Has(pod.Spec.SchedulerName) {
+			return admission.Allowed()
+		}
+		return admission.Patched(admission.AddAnnotationPatches(pod.Annotations, w.defaultAnnotations(pod)))
+	})
+}
+
+func (w *AnnotationWebhook) defaultAnnotations(pod *v1.Pod) map[string]string {
+	defaults := make(map[string]string)
+	_, hasResourceType := pod.Annotations[podutil.PodResourceTypeAnnotationKey]
+	_, hasQoSLevel := pod.Annotations[util.QoSLevelKey]
+	if !hasResourceType && !hasQoSLevel {
+		defaults[podutil.PodResourceTypeAnnotationKey] = string(w.defaultResourceType)
+	}
+	if _, ok := pod.Annotations[podutil.PodLauncherAnnotationKey]; !ok {
+		defaults[podutil.PodLauncherAnnotationKey] = string(w.defaultLauncher)
+	}
+	return defaults
+}
+
+func (w *AnnotationWebhook) validatePod(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	pod := &v1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !w.schedulerNames.Has(pod.Spec.SchedulerName) {
+		return admission.Allowed()
+	}
+	var oldPod *v1.Pod
+	if req.Operation == admissionv1.Update {
+		oldPod = &v1.Pod{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldPod); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	if errs := ValidatePodAnnotations(pod, oldPod, w.plugins); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed()
+}
+
+func (w *AnnotationWebhook) validatePodGroup(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	pg := &schedulingv1a1.PodGroup{}
+	if err := json.Unmarshal(req.Object.Raw, pg); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	errs := ValidatePodGroup(pg, w.plugins)
+	if len(errs) == 0 {
+		return admission.Allowed()
+	}
+	// Existing PodGroups which were invalid before the webhook is installed are never blocked.
+	if req.Operation == admissionv1.Update {
+		oldPG := &schedulingv1a1.PodGroup{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldPG); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if len(ValidatePodGroup(oldPG, w.plugins)) > 0 {
+			return admission.Allowed()
+		}
+	}
+	return admission.Denied(errs.ToAggregate().Error())
+}
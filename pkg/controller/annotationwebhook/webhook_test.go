@@ -0,0 +1,350 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotationwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/admission"
+	"github.com/kubewharf/godel-scheduler/pkg/util/constraints"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+func newTestWebhook() *AnnotationWebhook {
+	cfg := &config.AnnotationWebhookConfiguration{ExtraPlugins: []string{"OutOfTree"}}
+	config.SetDefaultAnnotationWebhook(cfg)
+	return NewAnnotationWebhook(cfg)
+}
+
+func makePod(annotations map[string]string) *v1.Pod {
+	pod := testinghelper.MakePod().Namespace("default").Name("p").Obj()
+	pod.Spec.SchedulerName = config.DefaultSchedulerName
+	pod.Annotations = annotations
+	return pod
+}
+
+func TestValidatePodAnnotations(t *testing.T) {
+	w := newTestWebhook()
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		old         map[string]string
+		expectedErr string
+	}{
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				podutil.PodResourceTypeAnnotationKey:                  string(podutil.BestEffortPod),
+				podutil.PodLauncherAnnotationKey:                      string(podutil.NodeManager),
+				util.PreemptionPolicyKey:                              string(v1.PreemptNever),
+				podutil.ProtectionDurationFromPreemptionKey:           "60",
+				podutil.PodRequestTemplateAnnotationKey:               "template",
+				podutil.ReservationTTLKey:                             "300",
+				podutil.IncreasePercentageOfNodesToScoreAnnotationKey: "true",
+				constraints.HardConstraintsAnnotationKey:              "NodeAffinity,OutOfTree",
+				constraints.SoftConstraintsAnnotationKey:              "NodeResourcesMostAllocated:2",
+			},
+		},
+		{
+			name:        "invalid resource type",
+			annotations: map[string]string{podutil.PodResourceTypeAnnotationKey: "guarantee"},
+			expectedErr: podutil.PodResourceTypeAnnotationKey,
+		},
+		{
+			name:        "invalid launcher",
+			annotations: map[string]string{podutil.PodLauncherAnnotationKey: "kubelett"},
+			expectedErr: podutil.PodLauncherAnnotationKey,
+		},
+		{
+			name:        "invalid preemption policy",
+			annotations: map[string]string{util.PreemptionPolicyKey: "never"},
+			expectedErr: util.PreemptionPolicyKey,
+		},
+		{
+			name:        "invalid protection duration",
+			annotations: map[string]string{podutil.ProtectionDurationFromPreemptionKey: "1m"},
+			expectedErr: podutil.ProtectionDurationFromPreemptionKey,
+		},
+		{
+			name:        "invalid request template",
+			annotations: map[string]string{podutil.PodRequestTemplateAnnotationKey: "Template_1"},
+			expectedErr: podutil.PodRequestTemplateAnnotationKey,
+		},
+		{
+			name:        "invalid reservation ttl",
+			annotations: map[string]string{podutil.ReservationTTLKey: "0"},
+			expectedErr: podutil.ReservationTTLKey,
+		},
+		{
+			name:        "invalid increase percentage of nodes to score",
+			annotations: map[string]string{podutil.IncreasePercentageOfNodesToScoreAnnotationKey: "yes"},
+			expectedErr: podutil.IncreasePercentageOfNodesToScoreAnnotationKey,
+		},
+		{
+			name:        "malformed constraint",
+			annotations: map[string]string{constraints.HardConstraintsAnnotationKey: "NodeAffinity:0"},
+			expectedErr: constraints.HardConstraintsAnnotationKey,
+		},
+		{
+			name:        "unknown plugin",
+			annotations: map[string]string{constraints.SoftConstraintsAnnotationKey: "NodeAfinity"},
+			expectedErr: `unknown plugin "NodeAfinity"`,
+		},
+		{
+			name:        "unchanged invalid annotation on update",
+			annotations: map[string]string{podutil.PodLauncherAnnotationKey: "kubelett", podutil.PodStateAnnotationKey: "dispatched"},
+			old:         map[string]string{podutil.PodLauncherAnnotationKey: "kubelett"},
+		},
+		{
+			name:        "changed invalid annotation on update",
+			annotations: map[string]string{podutil.PodLauncherAnnotationKey: "kubelett"},
+			old:         map[string]string{podutil.PodLauncherAnnotationKey: string(podutil.Kubelet)},
+			expectedErr: podutil.PodLauncherAnnotationKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldPod *v1.Pod
+			if tt.old != nil {
+				oldPod = makePod(tt.old)
+			}
+			errs := ValidatePodAnnotations(makePod(tt.annotations), oldPod, w.plugins)
+			if len(tt.expectedErr) == 0 {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tt.expectedErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePodGroup(t *testing.T) {
+	w := newTestWebhook()
+	timeout := int32(0)
+	tests := []struct {
+		name        string
+		pg          *schedulingv1a1.PodGroup
+		expectedErr string
+	}{
+		{
+			name: "valid PodGroup",
+			pg: &schedulingv1a1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					unit.PodGroupFailurePolicyAnnotationKey:  string(unit.PodGroupFailurePolicyRestartGang),
+					constraints.HardConstraintsAnnotationKey: "NodeAffinity",
+				}},
+				Spec: schedulingv1a1.PodGroupSpec{
+					MinMember: 2,
+					Affinity: &schedulingv1a1.Affinity{
+						PodGroupAffinity: &schedulingv1a1.PodGroupAffinity{
+							Required: []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: "mainnet"}},
+							SortRules: []schedulingv1a1.SortRule{
+								{Resource: schedulingv1a1.GPUResource, Order: schedulingv1a1.DescendingOrder},
+								{Resource: schedulingv1a1.CPUResource, Dimension: schedulingv1a1.Available, Order: schedulingv1a1.AscendingOrder},
+							},
+							NodeSelector: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+								MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}},
+							}}},
+						},
+						PodGroupAntiAffinity: &schedulingv1a1.PodGroupAntiAffinity{
+							Preferred: []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: "kubernetes.io/hostname"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "zero minMember",
+			pg:          &schedulingv1a1.PodGroup{},
+			expectedErr: "spec.minMember",
+		},
+		{
+			name:        "zero timeout",
+			pg:          &schedulingv1a1.PodGroup{Spec: schedulingv1a1.PodGroupSpec{MinMember: 1, ScheduleTimeoutSeconds: &timeout}},
+			expectedErr: "spec.scheduleTimeoutSeconds",
+		},
+		{
+			name: "invalid sort rule",
+			pg: &schedulingv1a1.PodGroup{Spec: schedulingv1a1.PodGroupSpec{MinMember: 1, Affinity: &schedulingv1a1.Affinity{
+				PodGroupAffinity: &schedulingv1a1.PodGroupAffinity{SortRules: []schedulingv1a1.SortRule{{Resource: "Disk", Order: schedulingv1a1.AscendingOrder}}},
+			}}},
+			expectedErr: "spec.affinity.podGroupAffinity.sortRules[0]",
+		},
+		{
+			name: "empty topology key",
+			pg: &schedulingv1a1.PodGroup{Spec: schedulingv1a1.PodGroupSpec{MinMember: 1, Affinity: &schedulingv1a1.Affinity{
+				PodGroupAntiAffinity: &schedulingv1a1.PodGroupAntiAffinity{Required: []schedulingv1a1.PodGroupAffinityTerm{{}}},
+			}}},
+			expectedErr: "spec.affinity.podGroupAntiAffinity.required[0].topologyKey",
+		},
+		{
+			name: "invalid node selector",
+			pg: &schedulingv1a1.PodGroup{Spec: schedulingv1a1.PodGroupSpec{MinMember: 1, Affinity: &schedulingv1a1.Affinity{
+				PodGroupAffinity: &schedulingv1a1.PodGroupAffinity{NodeSelector: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+					MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn}},
+				}}}},
+			}}},
+			expectedErr: "spec.affinity.podGroupAffinity.nodeSelector.nodeSelectorTerms[0].matchExpressions",
+		},
+		{
+			name: "invalid failure policy",
+			pg: &schedulingv1a1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{unit.PodGroupFailurePolicyAnnotationKey: "Restart"}},
+				Spec:       schedulingv1a1.PodGroupSpec{MinMember: 1},
+			},
+			expectedErr: unit.PodGroupFailurePolicyAnnotationKey,
+		},
+		{
+			name: "invalid max restarts",
+			pg: &schedulingv1a1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{unit.PodGroupMaxRestartsAnnotationKey: "-1"}},
+				Spec:       schedulingv1a1.PodGroupSpec{MinMember: 1},
+			},
+			expectedErr: unit.PodGroupMaxRestartsAnnotationKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidatePodGroup(tt.pg, w.plugins)
+			if len(tt.expectedErr) == 0 {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tt.expectedErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedErr, errs)
+			}
+		})
+	}
+}
+
+func review(t *testing.T, handler http.Handler, operation admissionv1.Operation, kind string, obj, old interface{}) *admissionv1.AdmissionResponse {
+	raw, _ := json.Marshal(obj)
+	req := &admissionv1.AdmissionRequest{
+		UID:       "uid",
+		Kind:      metav1.GroupVersionKind{Kind: kind},
+		Namespace: "default",
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+	if old != nil {
+		oldRaw, _ := json.Marshal(old)
+		req.OldObject = runtime.RawExtension{Raw: oldRaw}
+	}
+	body, _ := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	got := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), got); err != nil || got.Response == nil {
+		t.Fatalf("malformed response %q: %v", recorder.Body.String(), err)
+	}
+	return got.Response
+}
+
+func TestValidatingHandler(t *testing.T) {
+	handler := newTestWebhook().ValidatingHandler()
+
+	invalid := makePod(map[string]string{podutil.PodLauncherAnnotationKey: "kubelett"})
+	if resp := review(t, handler, admissionv1.Create, "Pod", invalid, nil); resp.Allowed {
+		t.Errorf("expected invalid pod to be rejected")
+	}
+	otherScheduler := invalid.DeepCopy()
+	otherScheduler.Spec.SchedulerName = v1.DefaultSchedulerName
+	if resp := review(t, handler, admissionv1.Create, "Pod", otherScheduler, nil); !resp.Allowed {
+		t.Errorf("expected pod of other schedulers to be allowed, got %v", resp.Result)
+	}
+
+	invalidPG := &schedulingv1a1.PodGroup{}
+	if resp := review(t, handler, admissionv1.Create, podutil.PodGroupKind, invalidPG, nil); resp.Allowed {
+		t.Errorf("expected invalid PodGroup to be rejected")
+	}
+	if resp := review(t, handler, admissionv1.Update, podutil.PodGroupKind, invalidPG, invalidPG); !resp.Allowed {
+		t.Errorf("expected existing invalid PodGroup to be allowed, got %v", resp.Result)
+	}
+}
+
+func TestMutatingHandler(t *testing.T) {
+	handler := newTestWebhook().MutatingHandler()
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		expectedPatches []admission.PatchOperation
+	}{
+		{
+			name: "no annotations",
+			expectedPatches: []admission.PatchOperation{{Op: "add", Path: "/metadata/annotations", Value: map[string]interface{}{
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+			}}},
+		},
+		{
+			name:        "qos level set",
+			annotations: map[string]string{util.QoSLevelKey: string(util.ReclaimedCores)},
+			expectedPatches: []admission.PatchOperation{
+				{Op: "add", Path: "/metadata/annotations/godel.bytedance.com~1pod-launcher", Value: string(podutil.Kubelet)},
+			},
+		},
+		{
+			name: "both set",
+			annotations: map[string]string{
+				podutil.PodLauncherAnnotationKey:     string(podutil.NodeManager),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.BestEffortPod),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := review(t, handler, admissionv1.Create, "Pod", makePod(tt.annotations), nil)
+			if !resp.Allowed {
+				t.Fatalf("expected pod to be allowed, got %v", resp.Result)
+			}
+			var patches []admission.PatchOperation
+			if len(resp.Patch) > 0 {
+				if err := json.Unmarshal(resp.Patch, &patches); err != nil {
+					t.Fatalf("malformed patch: %v", err)
+				}
+			}
+			if !reflect.DeepEqual(patches, tt.expectedPatches) {
+				t.Errorf("expected patches %+v, got %+v", tt.expectedPatches, patches)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package config
 
 import (
+	annotationwebhookconfig "github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	podgroupconfig "github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
 	reservationconfig "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/config"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
@@ -25,6 +28,9 @@ func NewEmptyGodelControllerManagerConfiguration() *GodelControllerManagerConfig
 	return &GodelControllerManagerConfiguration{
 		Generic:               &GenericControllerManagerConfiguration{},
 		ReservationController: &reservationconfig.ReservationControllerConfiguration{},
+		ReschedulerController: &reschedulerconfig.ReschedulerControllerConfiguration{},
+		PodGroupController:    &podgroupconfig.PodGroupControllerConfiguration{},
+		AnnotationWebhook:     &annotationwebhookconfig.AnnotationWebhookConfiguration{},
 		Tracer:                &tracing.TracerConfiguration{},
 	}
 }
@@ -20,6 +20,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	componentbaseconfig "k8s.io/component-base/config"
 
+	annotationwebhookconfig "github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	podgroupconfig "github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
 	reservationconfig "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/config"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
@@ -30,6 +33,9 @@ type GodelControllerManagerConfiguration struct {
 
 	Generic               *GenericControllerManagerConfiguration
 	ReservationController *reservationconfig.ReservationControllerConfiguration
+	ReschedulerController *reschedulerconfig.ReschedulerControllerConfiguration
+	PodGroupController    *podgroupconfig.PodGroupControllerConfiguration
+	AnnotationWebhook     *annotationwebhookconfig.AnnotationWebhookConfiguration
 	// HealthzBindAddress is the IP address and port for the health check server to serve on,
 	// defaulting to 0.0.0.0:10251
 	HealthzBindAddress string
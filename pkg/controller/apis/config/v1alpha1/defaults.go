@@ -25,6 +25,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	componentbaseconfig "k8s.io/component-base/config/v1alpha1"
 
+	annotationwebhookconfig "github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
+	podgroupconfig "github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
 	reservationconfig "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/config"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
@@ -119,6 +122,21 @@ func SetDefaults_GodelControllerManagerConfiguration(obj *GodelControllerManager
 	}
 	reservationconfig.SetDefaultReservationController(obj.ReservationController)
 
+	if obj.ReschedulerController == nil {
+		obj.ReschedulerController = reschedulerconfig.NewReschedulerControllerConfiguration()
+	}
+	reschedulerconfig.SetDefaultReschedulerController(obj.ReschedulerController)
+
+	if obj.PodGroupController == nil {
+		obj.PodGroupController = podgroupconfig.NewPodGroupControllerConfiguration()
+	}
+	podgroupconfig.SetDefaultPodGroupController(obj.PodGroupController)
+
+	if obj.AnnotationWebhook == nil {
+		obj.AnnotationWebhook = annotationwebhookconfig.NewAnnotationWebhookConfiguration()
+	}
+	annotationwebhookconfig.SetDefaultAnnotationWebhook(obj.AnnotationWebhook)
+
 	if obj.Tracer == nil {
 		obj.Tracer = tracing.DefaultNoopOptions()
 	}
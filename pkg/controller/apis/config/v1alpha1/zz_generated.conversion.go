@@ -27,7 +27,10 @@ import (
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 
+	annotationwebhookconfig "github.com/kubewharf/godel-scheduler/pkg/controller/annotationwebhook/config"
 	config "github.com/kubewharf/godel-scheduler/pkg/controller/apis/config"
+	podgroupconfig "github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
 	reservationconfig "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/config"
 	tracing "github.com/kubewharf/godel-scheduler/pkg/util/tracing"
 )
@@ -157,6 +160,9 @@ func autoConvert_v1alpha1_GodelControllerManagerConfiguration_To_config_GodelCon
 		out.Generic = nil
 	}
 	out.ReservationController = (*reservationconfig.ReservationControllerConfiguration)(unsafe.Pointer(in.ReservationController))
+	out.ReschedulerController = (*reschedulerconfig.ReschedulerControllerConfiguration)(unsafe.Pointer(in.ReschedulerController))
+	out.PodGroupController = (*podgroupconfig.PodGroupControllerConfiguration)(unsafe.Pointer(in.PodGroupController))
+	out.AnnotationWebhook = (*annotationwebhookconfig.AnnotationWebhookConfiguration)(unsafe.Pointer(in.AnnotationWebhook))
 	out.HealthzBindAddress = in.HealthzBindAddress
 	out.MetricsBindAddress = in.MetricsBindAddress
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
@@ -179,6 +185,9 @@ func autoConvert_config_GodelControllerManagerConfiguration_To_v1alpha1_GodelCon
 		out.Generic = nil
 	}
 	out.ReservationController = (*reservationconfig.ReservationControllerConfiguration)(unsafe.Pointer(in.ReservationController))
+	out.ReschedulerController = (*reschedulerconfig.ReschedulerControllerConfiguration)(unsafe.Pointer(in.ReschedulerController))
+	out.PodGroupController = (*podgroupconfig.PodGroupControllerConfiguration)(unsafe.Pointer(in.PodGroupController))
+	out.AnnotationWebhook = (*annotationwebhookconfig.AnnotationWebhookConfiguration)(unsafe.Pointer(in.AnnotationWebhook))
 	out.HealthzBindAddress = in.HealthzBindAddress
 	out.MetricsBindAddress = in.MetricsBindAddress
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
@@ -80,6 +80,18 @@ func (in *GodelControllerManagerConfiguration) DeepCopyInto(out *GodelController
 		in, out := &in.ReservationController, &out.ReservationController
 		*out = (*in).DeepCopy()
 	}
+	if in.ReschedulerController != nil {
+		in, out := &in.ReschedulerController, &out.ReschedulerController
+		*out = (*in).DeepCopy()
+	}
+	if in.PodGroupController != nil {
+		in, out := &in.PodGroupController, &out.PodGroupController
+		*out = (*in).DeepCopy()
+	}
+	if in.AnnotationWebhook != nil {
+		in, out := &in.AnnotationWebhook, &out.AnnotationWebhook
+		*out = (*in).DeepCopy()
+	}
 	if in.Tracer != nil {
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
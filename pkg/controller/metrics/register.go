@@ -20,6 +20,7 @@ import (
 	k8smetrics "k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
 
+	reschedulermetrics "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/controller/reservation/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/version"
 )
@@ -31,6 +32,7 @@ var metricsList = []k8smetrics.Registerable{
 
 func init() {
 	metrics.Install(&metricsList)
+	reschedulermetrics.Install(&metricsList)
 }
 
 // Register controller manager metrics.
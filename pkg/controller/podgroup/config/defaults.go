@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+const (
+	JobKind        = "Job"
+	JobSetKind     = "JobSet"
+	PyTorchJobKind = "PyTorchJob"
+	MPIJobKind     = "MPIJob"
+	RayClusterKind = "RayCluster"
+)
+
+var (
+	DefaultOwnerKinds   = []string{JobKind}
+	AvailableOwnerKinds = []string{JobKind, JobSetKind, PyTorchJobKind, MPIJobKind, RayClusterKind}
+)
+
+func SetDefaultPodGroupController(obj *PodGroupControllerConfiguration) {
+	if len(obj.OwnerKinds) == 0 {
+		obj.OwnerKinds = append([]string{}, DefaultOwnerKinds...)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+type PodGroupControllerConfiguration struct {
+	// OwnerKinds is the list of owner kinds whose PodGroups are created automatically,
+	// available kinds: Job, JobSet, PyTorchJob, MPIJob, RayCluster.
+	OwnerKinds []string
+	// DefaultScheduleTimeoutSeconds is the scheduleTimeoutSeconds of created PodGroups
+	// when the owner doesn't specify one, 0 means leaving it unset.
+	DefaultScheduleTimeoutSeconds int32
+	// IgnoredNamespace is the list of namespace whose pods are never mutated.
+	IgnoredNamespace []string
+}
+
+func NewPodGroupControllerConfiguration() *PodGroupControllerConfiguration {
+	return &PodGroupControllerConfiguration{}
+}
+
+func (c *PodGroupControllerConfiguration) DeepCopyInto(out *PodGroupControllerConfiguration) {
+	*out = *c
+	if c.OwnerKinds != nil {
+		out.OwnerKinds = make([]string, len(c.OwnerKinds))
+		copy(out.OwnerKinds, c.OwnerKinds)
+	}
+	if c.IgnoredNamespace != nil {
+		out.IgnoredNamespace = make([]string, len(c.IgnoredNamespace))
+		copy(out.IgnoredNamespace, c.IgnoredNamespace)
+	}
+}
+
+func (c *PodGroupControllerConfiguration) DeepCopy() *PodGroupControllerConfiguration {
+	if c == nil {
+		return nil
+	}
+	out := new(PodGroupControllerConfiguration)
+	c.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+// maxOwnerDepth limits how many levels of controller references are walked up from a pod,
+// e.g. Pod -> Job -> JobSet.
+const maxOwnerDepth = 3
+
+// Workload is an owner of pods that should be scheduled as a gang.
+type Workload struct {
+	metav1.Object
+	GroupVersionKind schema.GroupVersionKind
+	// Members is the number of pods the workload runs simultaneously, it's used as the
+	// minMember of the PodGroup unless overridden by the annotation.
+	Members int32
+	// PriorityClassName is the priority class of the pods, it's used as the priority class
+	// of the PodGroup unless overridden by the annotation.
+	PriorityClassName string
+}
+
+// AutoCreate returns true if the workload opts in automatic PodGroup creation.
+func (w *Workload) AutoCreate() bool {
+	return w.GetAnnotations()[unit.PodGroupAutoCreateAnnotationKey] == "true"
+}
+
+// OwnerAdapter gets the workloads of a kind, so that PodGroups could be derived from them.
+// Out-of-tree workloads could be supported by passing more adapters to the controller.
+type OwnerAdapter interface {
+	// GroupKind returns the kind of workloads handled by the adapter.
+	GroupKind() schema.GroupKind
+	// Get returns the workload from the local cache, nil if it doesn't exist.
+	Get(namespace, name string) (*Workload, error)
+	// HasSynced returns true if the local cache of the workloads has been synced.
+	HasSynced() bool
+}
+
+// NewInTreeOwnerAdapters returns the adapters of the given kinds, whose informers are registered to the factories.
+// The factories must be started by the caller.
+func NewInTreeOwnerAdapters(informerFactory informers.SharedInformerFactory, dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory, kinds []string) ([]OwnerAdapter, error) {
+	kindSet := sets.NewString(kinds...)
+	// The pods of JobSets are controlled by Jobs, which have to be walked through to reach the JobSets.
+	if kindSet.Has(config.JobSetKind) && !kindSet.Has(config.JobKind) {
+		kinds = append([]string{config.JobKind}, kinds...)
+	}
+	adapters := make([]OwnerAdapter, 0, len(kinds))
+	for _, kind := range kinds {
+		switch kind {
+		case config.JobKind:
+			adapters = append(adapters, NewJobAdapter(informerFactory.Batch().V1().Jobs()))
+		case config.JobSetKind:
+			adapters = append(adapters, NewUnstructuredAdapter(dynamicInformerFactory,
+				schema.GroupVersionKind{Group: "jobset.x-k8s.io", Version: "v1alpha2", Kind: config.JobSetKind}, "jobsets", jobSetMembers))
+		case config.PyTorchJobKind:
+			adapters = append(adapters, NewUnstructuredAdapter(dynamicInformerFactory,
+				schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: config.PyTorchJobKind}, "pytorchjobs", replicaSpecsMembers("pytorchReplicaSpecs")))
+		case config.MPIJobKind:
+			adapters = append(adapters, NewUnstructuredAdapter(dynamicInformerFactory,
+				schema.GroupVersionKind{Group: "kubeflow.org", Version: "v2beta1", Kind: config.MPIJobKind}, "mpijobs", replicaSpecsMembers("mpiReplicaSpecs")))
+		case config.RayClusterKind:
+			adapters = append(adapters, NewUnstructuredAdapter(dynamicInformerFactory,
+				schema.GroupVersionKind{Group: "ray.io", Version: "v1", Kind: config.RayClusterKind}, "rayclusters", rayClusterMembers))
+		default:
+			return nil, fmt.Errorf("unknown podgroup owner kind %q", kind)
+		}
+	}
+	return adapters, nil
+}
+
+// Resolver finds the workload a pod belongs to by walking up its controller references.
+type Resolver struct {
+	adapters map[schema.GroupKind]OwnerAdapter
+}
+
+func NewResolver(adapters ...OwnerAdapter) *Resolver {
+	r := &Resolver{adapters: make(map[schema.GroupKind]OwnerAdapter, len(adapters))}
+	for _, adapter := range adapters {
+		r.adapters[adapter.GroupKind()] = adapter
+	}
+	return r
+}
+
+// HasSynced returns true if the local caches of all the adapters have been synced.
+func (r *Resolver) HasSynced() bool {
+	for _, adapter := range r.adapters {
+		if !adapter.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve returns the outermost workload opting in automatic PodGroup creation among the
+// controllers of the object, nil if there is no such workload.
+func (r *Resolver) Resolve(namespace string, obj metav1.Object) (*Workload, error) {
+	var found *Workload
+	ref := metav1.GetControllerOf(obj)
+	for depth := 0; ref != nil && depth < maxOwnerDepth; depth++ {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		adapter, ok := r.adapters[schema.GroupKind{Group: gv.Group, Kind: ref.Kind}]
+		if !ok {
+			break
+		}
+		workload, err := adapter.Get(namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if workload == nil || workload.GetUID() != ref.UID {
+			break
+		}
+		if workload.AutoCreate() {
+			found = workload
+		}
+		ref = metav1.GetControllerOf(workload)
+	}
+	return found, nil
+}
+
+// PodGroupName returns the name of the PodGroup derived from the workload.
+func PodGroupName(w *Workload) string {
+	return strings.ToLower(w.GroupVersionKind.Kind) + "-" + w.GetName()
+}
+
+type jobAdapter struct {
+	lister    batchlisters.JobLister
+	hasSynced cache.InformerSynced
+}
+
+func NewJobAdapter(jobInformer batchinformers.JobInformer) OwnerAdapter {
+	return &jobAdapter{
+		lister:    jobInformer.Lister(),
+		hasSynced: jobInformer.Informer().HasSynced,
+	}
+}
+
+func (a *jobAdapter) GroupKind() schema.GroupKind {
+	return batchv1.SchemeGroupVersion.WithKind(config.JobKind).GroupKind()
+}
+
+func (a *jobAdapter) HasSynced() bool {
+	return a.hasSynced()
+}
+
+func (a *jobAdapter) Get(namespace, name string) (*Workload, error) {
+	job, err := a.lister.Jobs(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Workload{
+		Object:            job,
+		GroupVersionKind:  batchv1.SchemeGroupVersion.WithKind(config.JobKind),
+		Members:           jobMembers(job.Spec.Parallelism, job.Spec.Completions),
+		PriorityClassName: job.Spec.Template.Spec.PriorityClassName,
+	}, nil
+}
+
+// jobMembers returns the number of pods running simultaneously, which is the parallelism
+// (1 by default) capped by the completions.
+func jobMembers(parallelism, completions *int32) int32 {
+	members := int32(1)
+	if parallelism != nil {
+		members = *parallelism
+	}
+	if completions != nil && *completions < members {
+		members = *completions
+	}
+	return members
+}
+
+// MembersFunc returns the number of pods running simultaneously and the priority class of pods of a workload.
+type MembersFunc func(obj *unstructured.Unstructured) (int32, string)
+
+type unstructuredAdapter struct {
+	lister    cache.GenericLister
+	hasSynced cache.InformerSynced
+	gvk       schema.GroupVersionKind
+	members   MembersFunc
+}
+
+// NewUnstructuredAdapter returns an adapter of custom workloads, which are watched through the dynamic client
+// so that their APIs aren't necessary to be vendored.
+func NewUnstructuredAdapter(informerFactory dynamicinformer.DynamicSharedInformerFactory, gvk schema.GroupVersionKind, resource string, members MembersFunc) OwnerAdapter {
+	informer := informerFactory.ForResource(gvk.GroupVersion().WithResource(resource))
+	return &unstructuredAdapter{
+		lister:    informer.Lister(),
+		hasSynced: informer.Informer().HasSynced,
+		gvk:       gvk,
+		members:   members,
+	}
+}
+
+func (a *unstructuredAdapter) GroupKind() schema.GroupKind {
+	return a.gvk.GroupKind()
+}
+
+func (a *unstructuredAdapter) HasSynced() bool {
+	return a.hasSynced()
+}
+
+func (a *unstructuredAdapter) Get(namespace, name string) (*Workload, error) {
+	runtimeObj, err := a.lister.ByNamespace(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	obj, ok := runtimeObj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T of %s", runtimeObj, a.gvk.Kind)
+	}
+	members, priorityClassName := a.members(obj)
+	return &Workload{
+		Object:            obj,
+		GroupVersionKind:  a.gvk,
+		Members:           members,
+		PriorityClassName: priorityClassName,
+	}, nil
+}
+
+func nestedInt32(obj map[string]interface{}, def int32, fields ...string) int32 {
+	val, found, err := unstructured.NestedInt64(obj, fields...)
+	if err != nil || !found {
+		return def
+	}
+	return int32(val)
+}
+
+// jobSetMembers sums the members of all the replicated jobs of a JobSet.
+func jobSetMembers(obj *unstructured.Unstructured) (int32, string) {
+	var members int32
+	var priorityClassName string
+	replicatedJobs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "replicatedJobs")
+	for _, item := range replicatedJobs {
+		rjob, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		parallelism := nestedInt32(rjob, 1, "template", "spec", "parallelism")
+		completions := nestedInt32(rjob, parallelism, "template", "spec", "completions")
+		members += nestedInt32(rjob, 1, "replicas") * jobMembers(&parallelism, &completions)
+		if len(priorityClassName) == 0 {
+			priorityClassName, _, _ = unstructured.NestedString(rjob, "template", "spec", "template", "spec", "priorityClassName")
+		}
+	}
+	return members, priorityClassName
+}
+
+// replicaSpecsMembers sums the replicas of all the replica specs of a Kubeflow training job.
+func replicaSpecsMembers(field string) MembersFunc {
+	return func(obj *unstructured.Unstructured) (int32, string) {
+		var members int32
+		priorityClassName, _, _ := unstructured.NestedString(obj.Object, "spec", "runPolicy", "schedulingPolicy", "priorityClass")
+		specs, _, _ := unstructured.NestedMap(obj.Object, "spec", field)
+		for _, item := range specs {
+			spec, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			members += nestedInt32(spec, 1, "replicas")
+		}
+		return members, priorityClassName
+	}
+}
+
+// rayClusterMembers counts the head and the minimum replicas of all the worker groups of a RayCluster.
+func rayClusterMembers(obj *unstructured.Unstructured) (int32, string) {
+	members := int32(1)
+	priorityClassName, _, _ := unstructured.NestedString(obj.Object, "spec", "headGroupSpec", "template", "spec", "priorityClassName")
+	groups, _, _ := unstructured.NestedSlice(obj.Object, "spec", "workerGroupSpecs")
+	for _, item := range groups {
+		group, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		members += nestedInt32(group, 0, "minReplicas")
+	}
+	return members, priorityClassName
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
+	pginformer "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/scheduling/v1alpha1"
+	pglister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	controllersmetrics "github.com/kubewharf/godel-scheduler/pkg/controller/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+const (
+	ControllerName = "podgroup-controller"
+
+	MaxRetryAttempts = 5
+)
+
+// PodGroupController creates PodGroups for the pods annotated by the podgroup webhook. The PodGroups
+// are owned by the workloads, so that they are garbage-collected together with the workloads.
+type PodGroupController struct {
+	godelClient     godelclient.Interface
+	podLister       corelister.PodLister
+	podListerSynced cache.InformerSynced
+	pgLister        pglister.PodGroupLister
+	pgListerSynced  cache.InformerSynced
+	queue           workqueue.RateLimitingInterface
+	resolver        *Resolver
+	config          *config.PodGroupControllerConfiguration
+}
+
+func NewPodGroupController(
+	godelClient godelclient.Interface,
+	podInformer coreinformers.PodInformer,
+	pgInformer pginformer.PodGroupInformer,
+	resolver *Resolver,
+	config *config.PodGroupControllerConfiguration,
+) *PodGroupController {
+	pc := &PodGroupController{
+		godelClient:     godelClient,
+		podLister:       podInformer.Lister(),
+		podListerSynced: podInformer.Informer().HasSynced,
+		pgLister:        pgInformer.Lister(),
+		pgListerSynced:  pgInformer.Informer().HasSynced,
+		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "podgroup"),
+		resolver:        resolver,
+		config:          config,
+	}
+
+	podInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			pod, err := podutil.ConvertToPod(obj)
+			if err != nil {
+				return false
+			}
+			return len(unit.GetPodGroupName(pod)) > 0 && metav1.GetControllerOf(pod) != nil
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: pc.enqueue,
+		},
+	})
+	// Recreate the PodGroup if it's deleted while the workload is still running.
+	pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: pc.enqueuePodsOfPodGroup,
+	})
+
+	return pc
+}
+
+func (pc *PodGroupController) Run(ctx context.Context, controllerManagerMetrics *controllersmetrics.ControllerManagerMetrics) {
+	defer utilruntime.HandleCrash()
+	controllerManagerMetrics.ControllerStarted(ControllerName)
+	defer controllerManagerMetrics.ControllerStopped(ControllerName)
+
+	klog.V(3).InfoS("Starting PodGroup Controller")
+	defer pc.queue.ShutDown()
+	defer klog.V(3).InfoS("Shutting down PodGroup Controller")
+
+	if !cache.WaitForNamedCacheSync("PodGroup", ctx.Done(), pc.podListerSynced, pc.pgListerSynced, pc.resolver.HasSynced) {
+		return
+	}
+
+	go wait.UntilWithContext(ctx, pc.worker, time.Second)
+
+	<-ctx.Done()
+}
+
+func (pc *PodGroupController) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	pc.queue.Add(key)
+}
+
+func (pc *PodGroupController) enqueuePodsOfPodGroup(obj interface{}) {
+	var pg *schedulingv1a1.PodGroup
+	switch t := obj.(type) {
+	case *schedulingv1a1.PodGroup:
+		pg = t
+	case cache.DeletedFinalStateUnknown:
+		pg, _ = t.Obj.(*schedulingv1a1.PodGroup)
+	}
+	if pg == nil || metav1.GetControllerOf(pg) == nil {
+		return
+	}
+	pods, err := pc.podLister.Pods(pg.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, pod := range pods {
+		if unit.GetPodGroupName(pod) == pg.Name && pod.DeletionTimestamp == nil &&
+			pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			pc.enqueue(pod)
+			// A single pod is enough to recreate the PodGroup.
+			return
+		}
+	}
+}
+
+func (pc *PodGroupController) worker(ctx context.Context) {
+	for pc.processNextItem(ctx) {
+	}
+}
+
+func (pc *PodGroupController) processNextItem(ctx context.Context) bool {
+	key, quit := pc.queue.Get()
+	if quit {
+		return false
+	}
+	defer pc.queue.Done(key)
+
+	err := pc.syncPod(ctx, key.(string))
+	if err == nil {
+		pc.queue.Forget(key)
+		return true
+	}
+	if pc.queue.NumRequeues(key) < MaxRetryAttempts {
+		klog.InfoS("Failed to sync PodGroup of pod, will retry", "pod", key, "err", err)
+		pc.queue.AddRateLimited(key)
+		return true
+	}
+	klog.ErrorS(err, "Failed to sync PodGroup of pod, dropped", "pod", key)
+	pc.queue.Forget(key)
+	return true
+}
+
+// syncPod creates the PodGroup referenced by the pod if it doesn't exist yet and is derived from a workload.
+func (pc *PodGroupController) syncPod(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pod, err := pc.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	pgName := unit.GetPodGroupName(pod)
+	if len(pgName) == 0 {
+		return nil
+	}
+	if _, err := pc.pgLister.PodGroups(namespace).Get(pgName); err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	workload, err := pc.resolver.Resolve(namespace, pod)
+	if err != nil {
+		return err
+	}
+	// The PodGroup is expected to be created by users.
+	if workload == nil || PodGroupName(workload) != pgName {
+		return nil
+	}
+
+	pg, err := NewPodGroup(workload, pc.config)
+	if err != nil {
+		klog.InfoS("Failed to derive PodGroup from workload", "kind", workload.GroupVersionKind.Kind,
+			"workload", klog.KObj(workload), "err", err)
+		return nil
+	}
+	if _, err := pc.godelClient.SchedulingV1alpha1().PodGroups(namespace).Create(ctx, pg, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	klog.V(3).InfoS("Created PodGroup for workload", "podGroup", klog.KObj(pg), "kind", workload.GroupVersionKind.Kind,
+		"workload", klog.KObj(workload), "minMember", pg.Spec.MinMember)
+	return nil
+}
+
+// NewPodGroup derives the PodGroup from the workload, the minMember, priority class and timeout set in the
+// workload annotations take precedence over the ones inferred from the workload spec.
+func NewPodGroup(w *Workload, cfg *config.PodGroupControllerConfiguration) (*schedulingv1a1.PodGroup, error) {
+	annotations := w.GetAnnotations()
+	pg := &schedulingv1a1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodGroupName(w),
+			Namespace: w.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(w, w.GroupVersionKind),
+			},
+		},
+		Spec: schedulingv1a1.PodGroupSpec{
+			MinMember:         w.Members,
+			PriorityClassName: w.PriorityClassName,
+		},
+	}
+
+	if val, ok := annotations[unit.PodGroupMinMemberAnnotationKey]; ok {
+		minMember, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", unit.PodGroupMinMemberAnnotationKey, err)
+		}
+		pg.Spec.MinMember = int32(minMember)
+	}
+	if pg.Spec.MinMember <= 0 {
+		return nil, fmt.Errorf("minMember must be positive, got %d", pg.Spec.MinMember)
+	}
+
+	if val, ok := annotations[unit.PodGroupPriorityClassAnnotationKey]; ok {
+		pg.Spec.PriorityClassName = val
+	}
+
+	timeout := cfg.DefaultScheduleTimeoutSeconds
+	if val, ok := annotations[unit.PodGroupScheduleTimeoutAnnotationKey]; ok {
+		t, err := strconv.ParseInt(val, 10, 32)
+		if err != nil || t <= 0 {
+			return nil, fmt.Errorf("invalid %s: %q", unit.PodGroupScheduleTimeoutAnnotationKey, val)
+		}
+		timeout = int32(t)
+	}
+	if timeout > 0 {
+		pg.Spec.ScheduleTimeoutSeconds = &timeout
+	}
+
+	for _, key := range []string{unit.PodGroupFailurePolicyAnnotationKey, unit.PodGroupMaxRestartsAnnotationKey} {
+		if val, ok := annotations[key]; ok {
+			if pg.Annotations == nil {
+				pg.Annotations = make(map[string]string)
+			}
+			pg.Annotations[key] = val
+		}
+	}
+	return pg, nil
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	admissionv1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	bindercontroller "github.com/kubewharf/godel-scheduler/pkg/binder/controller"
+	"github.com/kubewharf/godel-scheduler/pkg/controller/podgroup/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util/admission"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+const testNS = "default"
+
+var jobSetGVK = schema.GroupVersionKind{Group: "jobset.x-k8s.io", Version: "v1alpha2", Kind: config.JobSetKind}
+
+// fakeAdapter serves unstructured workloads from memory.
+type fakeAdapter struct {
+	gvk     schema.GroupVersionKind
+	objs    map[string]*unstructured.Unstructured
+	members MembersFunc
+}
+
+func (a *fakeAdapter) GroupKind() schema.GroupKind {
+	return a.gvk.GroupKind()
+}
+
+func (a *fakeAdapter) HasSynced() bool {
+	return true
+}
+
+func (a *fakeAdapter) Get(namespace, name string) (*Workload, error) {
+	obj, ok := a.objs[namespace+"/"+name]
+	if !ok {
+		return nil, nil
+	}
+	members, priorityClassName := a.members(obj)
+	return &Workload{Object: obj, GroupVersionKind: a.gvk, Members: members, PriorityClassName: priorityClassName}, nil
+}
+
+// newJobAdapter returns the adapter of Jobs with synced informer.
+func newJobAdapter(ctx context.Context, jobs ...runtime.Object) OwnerAdapter {
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(jobs...), 0)
+	adapter := NewJobAdapter(informerFactory.Batch().V1().Jobs())
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	return adapter
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func makeJob(name string, uid types.UID, parallelism, completions *int32, annotations map[string]string, owner *metav1.OwnerReference) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: name, UID: uid, Annotations: annotations},
+		Spec: batchv1.JobSpec{
+			Parallelism: parallelism,
+			Completions: completions,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{PriorityClassName: "job-priority"},
+			},
+		},
+	}
+	if owner != nil {
+		job.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return job
+}
+
+func makeJobSet(name string, uid types.UID, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicatedJobs": []interface{}{
+				map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{"spec": map[string]interface{}{"parallelism": int64(4)}},
+				},
+				map[string]interface{}{
+					"template": map[string]interface{}{"spec": map[string]interface{}{"parallelism": int64(1)}},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(jobSetGVK)
+	obj.SetNamespace(testNS)
+	obj.SetName(name)
+	obj.SetUID(uid)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func makeOwnedPod(name string, owner metav1.Object, gvk schema.GroupVersionKind, annotations map[string]string) *v1.Pod {
+	pod := testinghelper.MakePod().Namespace(testNS).Name(name).Obj()
+	pod.Annotations = annotations
+	pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, gvk)}
+	return pod
+}
+
+var (
+	jobGVK   = batchv1.SchemeGroupVersion.WithKind(config.JobKind)
+	autoOpts = map[string]string{unit.PodGroupAutoCreateAnnotationKey: "true"}
+)
+
+func TestResolve(t *testing.T) {
+	jobSet := makeJobSet("js", "js-uid", autoOpts)
+	jobSetRef := metav1.NewControllerRef(jobSet, jobSetGVK)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobAdapter := newJobAdapter(ctx,
+		makeJob("plain", "plain-uid", int32Ptr(4), nil, nil, nil),
+		makeJob("auto", "auto-uid", int32Ptr(4), int32Ptr(2), autoOpts, nil),
+		makeJob("js-worker", "js-worker-uid", int32Ptr(4), nil, nil, jobSetRef),
+	)
+	resolver := NewResolver(jobAdapter, &fakeAdapter{
+		gvk:     jobSetGVK,
+		objs:    map[string]*unstructured.Unstructured{testNS + "/js": jobSet},
+		members: jobSetMembers,
+	})
+
+	tests := []struct {
+		name          string
+		owner         metav1.Object
+		gvk           schema.GroupVersionKind
+		expectedName  string
+		expectedCount int32
+	}{
+		{
+			name:  "job not opting in",
+			owner: makeJob("plain", "plain-uid", nil, nil, nil, nil),
+			gvk:   jobGVK,
+		},
+		{
+			name:  "job recreated with the same name",
+			owner: makeJob("auto", "stale-uid", nil, nil, nil, nil),
+			gvk:   jobGVK,
+		},
+		{
+			name:          "job opting in",
+			owner:         makeJob("auto", "auto-uid", nil, nil, nil, nil),
+			gvk:           jobGVK,
+			expectedName:  "job-auto",
+			expectedCount: 2,
+		},
+		{
+			name:          "jobset opting in",
+			owner:         makeJob("js-worker", "js-worker-uid", nil, nil, nil, nil),
+			gvk:           jobGVK,
+			expectedName:  "jobset-js",
+			expectedCount: 9,
+		},
+		{
+			name:  "unknown owner",
+			owner: &metav1.ObjectMeta{Name: "rs", UID: "rs-uid"},
+			gvk:   schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeOwnedPod("p", tt.owner, tt.gvk, nil)
+			workload, err := resolver.Resolve(testNS, pod)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tt.expectedName) == 0 {
+				if workload != nil {
+					t.Errorf("expected no workload, got %s", PodGroupName(workload))
+				}
+				return
+			}
+			if workload == nil {
+				t.Fatalf("expected workload %s, got nil", tt.expectedName)
+			}
+			if PodGroupName(workload) != tt.expectedName || workload.Members != tt.expectedCount {
+				t.Errorf("expected %s with %d members, got %s with %d members", tt.expectedName, tt.expectedCount,
+					PodGroupName(workload), workload.Members)
+			}
+		})
+	}
+}
+
+func TestInTreeOwnerAdapters(t *testing.T) {
+	jobSet := makeJobSet("js", "js-uid", autoOpts)
+	job := makeJob("js-worker", "js-worker-uid", int32Ptr(4), nil, nil, metav1.NewControllerRef(jobSet, jobSetGVK))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(job), 0)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{jobSetGVK.GroupVersion().WithResource("jobsets"): "JobSetList"}, jobSet)
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	// Jobs are watched as well to reach the JobSets, though they are not configured.
+	adapters, err := NewInTreeOwnerAdapters(informerFactory, dynamicInformerFactory, []string{config.JobSetKind})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adapters) != 2 || adapters[0].GroupKind() != jobGVK.GroupKind() || adapters[1].GroupKind() != jobSetGVK.GroupKind() {
+		t.Fatalf("expected adapters of Job and JobSet, got %v", adapters)
+	}
+	informerFactory.Start(ctx.Done())
+	dynamicInformerFactory.Start(ctx.Done())
+	resolver := NewResolver(adapters...)
+	if !cache.WaitForCacheSync(ctx.Done(), resolver.HasSynced) {
+		t.Fatal("failed to sync owners")
+	}
+
+	workload, err := resolver.Resolve(testNS, makeOwnedPod("p", job, jobGVK, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if workload == nil || PodGroupName(workload) != "jobset-js" || workload.Members != 9 {
+		t.Errorf("expected jobset-js with 9 members, got %+v", workload)
+	}
+}
+
+func TestNewPodGroup(t *testing.T) {
+	cfg := &config.PodGroupControllerConfiguration{DefaultScheduleTimeoutSeconds: 300}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    schedulingv1a1.PodGroupSpec
+		expectedErr bool
+	}{
+		{
+			name:        "inferred from spec",
+			annotations: autoOpts,
+			expected:    schedulingv1a1.PodGroupSpec{MinMember: 4, PriorityClassName: "job-priority", ScheduleTimeoutSeconds: int32Ptr(300)},
+		},
+		{
+			name: "overridden by annotations",
+			annotations: map[string]string{
+				unit.PodGroupMinMemberAnnotationKey:       "3",
+				unit.PodGroupPriorityClassAnnotationKey:   "high",
+				unit.PodGroupScheduleTimeoutAnnotationKey: "60",
+			},
+			expected: schedulingv1a1.PodGroupSpec{MinMember: 3, PriorityClassName: "high", ScheduleTimeoutSeconds: int32Ptr(60)},
+		},
+		{
+			name:        "malformed minMember",
+			annotations: map[string]string{unit.PodGroupMinMemberAnnotationKey: "three"},
+			expectedErr: true,
+		},
+		{
+			name:        "zero minMember",
+			annotations: map[string]string{unit.PodGroupMinMemberAnnotationKey: "0"},
+			expectedErr: true,
+		},
+		{
+			name:        "malformed timeout",
+			annotations: map[string]string{unit.PodGroupScheduleTimeoutAnnotationKey: "-1"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := makeJob("job", "job-uid", int32Ptr(4), nil, tt.annotations, nil)
+			pg, err := NewPodGroup(&Workload{Object: job, GroupVersionKind: jobGVK, Members: 4, PriorityClassName: "job-priority"}, cfg)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(pg.Spec, tt.expected) {
+				t.Errorf("expected spec %+v, got %+v", tt.expected, pg.Spec)
+			}
+			owner := metav1.GetControllerOf(pg)
+			if owner == nil || owner.UID != job.UID || owner.Kind != config.JobKind {
+				t.Errorf("expected PodGroup to be controlled by the job, got %+v", owner)
+			}
+		})
+	}
+}
+
+func TestSyncPod(t *testing.T) {
+	job := makeJob("auto", "auto-uid", int32Ptr(2), nil, autoOpts, nil)
+	userPG := testinghelper.MakePodGroup().Namespace(testNS).Name("user-pg").MinMember(1).Obj()
+	pods := []*v1.Pod{
+		makeOwnedPod("p1", job, jobGVK, map[string]string{podutil.PodGroupNameAnnotationKey: "job-auto"}),
+		makeOwnedPod("p2", job, jobGVK, map[string]string{podutil.PodGroupNameAnnotationKey: "user-pg"}),
+		makeOwnedPod("p3", job, jobGVK, map[string]string{podutil.PodGroupNameAnnotationKey: "missing-pg"}),
+	}
+
+	objs := []runtime.Object{job}
+	for _, pod := range pods {
+		objs = append(objs, pod)
+	}
+	client := fake.NewSimpleClientset(objs...)
+	godelClient := godelfake.NewSimpleClientset(userPG)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	godelInformerFactory := crdinformers.NewSharedInformerFactory(godelClient, 0)
+	pc := NewPodGroupController(godelClient, informerFactory.Core().V1().Pods(),
+		godelInformerFactory.Scheduling().V1alpha1().PodGroups(), NewResolver(NewJobAdapter(informerFactory.Batch().V1().Jobs())),
+		&config.PodGroupControllerConfiguration{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informerFactory.Start(ctx.Done())
+	godelInformerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	godelInformerFactory.WaitForCacheSync(ctx.Done())
+
+	for _, pod := range pods {
+		if err := pc.syncPod(ctx, pod.Namespace+"/"+pod.Name); err != nil {
+			t.Fatalf("failed to sync pod %s: %v", pod.Name, err)
+		}
+	}
+
+	pgs, err := godelClient.SchedulingV1alpha1().PodGroups(testNS).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(pgs.Items))
+	for _, pg := range pgs.Items {
+		names = append(names, pg.Name)
+		if pg.Name == "job-auto" && pg.Spec.MinMember != 2 {
+			t.Errorf("expected minMember 2, got %d", pg.Spec.MinMember)
+		}
+	}
+	if expected := []string{"job-auto", "user-pg"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected PodGroups %v, got %v", expected, names)
+	}
+}
+
+func TestPodMutatingWebhook(t *testing.T) {
+	job := makeJob("auto", "auto-uid", int32Ptr(2), nil, autoOpts, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := NewPodMutatingWebhook(NewResolver(newJobAdapter(ctx, job)), []string{"ignored"})
+
+	tests := []struct {
+		name            string
+		namespace       string
+		pod             *v1.Pod
+		expectedPatches []admission.PatchOperation
+	}{
+		{
+			name:      "pod of job opting in",
+			namespace: testNS,
+			pod:       makeOwnedPod("p1", job, jobGVK, nil),
+			expectedPatches: []admission.PatchOperation{
+				{Op: "add", Path: "/metadata/annotations", Value: map[string]interface{}{podutil.PodGroupNameAnnotationKey: "job-auto"}},
+				{Op: "add", Path: "/metadata/labels", Value: map[string]interface{}{podutil.PodGroupNameAnnotationKey: "job-auto"}},
+			},
+		},
+		{
+			name:      "pod with annotations and labels",
+			namespace: testNS,
+			pod: func() *v1.Pod {
+				pod := makeOwnedPod("p2", job, jobGVK, map[string]string{"foo": "bar"})
+				pod.Labels = map[string]string{"app": "train"}
+				return pod
+			}(),
+			expectedPatches: []admission.PatchOperation{
+				{Op: "add", Path: "/metadata/annotations/godel.bytedance.com~1pod-group-name", Value: "job-auto"},
+				{Op: "add", Path: "/metadata/labels/godel.bytedance.com~1pod-group-name", Value: "job-auto"},
+			},
+		},
+		{
+			name:      "pod referencing PodGroup",
+			namespace: testNS,
+			pod:       makeOwnedPod("p3", job, jobGVK, map[string]string{podutil.PodGroupNameAnnotationKey: "user-pg"}),
+		},
+		{
+			name:      "pod in ignored namespace",
+			namespace: "ignored",
+			pod:       makeOwnedPod("p4", job, jobGVK, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(tt.pod)
+			review := &admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "uid",
+					Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+					Namespace: tt.namespace,
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+			body, _ := json.Marshal(review)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewReader(body)))
+
+			got := &admissionv1.AdmissionReview{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), got); err != nil {
+				t.Fatalf("malformed response: %v", err)
+			}
+			if got.Response == nil || !got.Response.Allowed || got.Response.UID != "uid" {
+				t.Fatalf("expected pod to be allowed, got %+v", got.Response)
+			}
+			var patches []admission.PatchOperation
+			if len(got.Response.Patch) > 0 {
+				if err := json.Unmarshal(got.Response.Patch, &patches); err != nil {
+					t.Fatalf("malformed patch: %v", err)
+				}
+			}
+			if !reflect.DeepEqual(patches, tt.expectedPatches) {
+				t.Errorf("expected patches %+v, got %+v", tt.expectedPatches, patches)
+			}
+			if len(patches) == 0 {
+				return
+			}
+
+			// The patched pod must be found as a member of the PodGroup by the binder.
+			patch, err := jsonpatch.DecodePatch(got.Response.Patch)
+			if err != nil {
+				t.Fatalf("malformed patch: %v", err)
+			}
+			patchedRaw, err := patch.Apply(raw)
+			if err != nil {
+				t.Fatalf("failed to apply patch: %v", err)
+			}
+			patched := &v1.Pod{}
+			if err := json.Unmarshal(patchedRaw, patched); err != nil {
+				t.Fatalf("malformed patched pod: %v", err)
+			}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			indexer.Add(patched)
+			members, err := bindercontroller.GetAllPods(corelisters.NewPodLister(indexer), testNS, "job-auto")
+			if err != nil || len(members) != 1 || members[0].Name != tt.pod.Name {
+				t.Errorf("expected pod %s to be a member of the PodGroup, got %v, err: %v", tt.pod.Name, members, err)
+			}
+		})
+	}
+}
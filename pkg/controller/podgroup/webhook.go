@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/admission"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+// WebhookPath is where the pod mutating webhook is served by the controller manager.
+const WebhookPath = "/mutate-pods-podgroup"
+
+// NewPodMutatingWebhook returns the handler of the mutating webhook, which sets the pod-group-name annotation and label on
+// the pods of workloads opting in automatic PodGroup creation. Pods already referencing a PodGroup are left untouched.
+// The owners are read from the local caches of the resolver, which must be started by the caller.
+func NewPodMutatingWebhook(resolver *Resolver, ignoredNamespaces []string) http.Handler {
+	ignored := sets.NewString(ignoredNamespaces...)
+	return admission.NewHandler("podgroup", func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		if req.Operation != admissionv1.Create || req.Kind.Kind != "Pod" || ignored.Has(req.Namespace) {
+			return admission.Allowed()
+		}
+		pod := &v1.Pod{}
+		if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if len(unit.GetPodGroupName(pod)) > 0 || metav1.GetControllerOf(pod) == nil {
+			return admission.Allowed()
+		}
+
+		// Reject the pod rather than missing the annotation, the creation is retried by its owner.
+		if !resolver.HasSynced() {
+			return admission.Errored(http.StatusServiceUnavailable, fmt.Errorf("owners of pods are not synced yet"))
+		}
+		workload, err := resolver.Resolve(req.Namespace, pod)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve the owner of pod: %v", err))
+		}
+		if workload == nil {
+			return admission.Allowed()
+		}
+		pgName := PodGroupName(workload)
+		if errs := validation.IsValidLabelValue(pgName); len(errs) > 0 {
+			klog.InfoS("Skipped setting PodGroup of pod, the name isn't a valid label value", "namespace", req.Namespace,
+				"generateName", pod.GenerateName, "name", pod.Name, "podGroup", pgName, "errs", errs)
+			return admission.Allowed()
+		}
+		klog.V(4).InfoS("Set PodGroup of pod", "namespace", req.Namespace, "generateName", pod.GenerateName,
+			"name", pod.Name, "podGroup", pgName)
+		// The members of PodGroups are listed by the label, while the annotation is read by the schedulers.
+		patches := admission.AddAnnotationPatches(pod.Annotations, map[string]string{podutil.PodGroupNameAnnotationKey: pgName})
+		patches = append(patches, admission.AddLabelPatches(pod.Labels, map[string]string{podutil.PodGroupNameAnnotationKey: pgName})...)
+		return admission.Patched(patches)
+	})
+}
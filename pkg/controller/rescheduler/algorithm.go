@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+)
+
+// Decision describes a pod that should be moved away from its current node,
+// and the node recommended for the new pod created by its owner.
+type Decision struct {
+	Pod        *v1.Pod
+	TargetNode string
+}
+
+// Algorithm makes rescheduling decisions based on a snapshot of the cluster.
+// Each algorithm is expected to call Snapshot.Assume for every decision it makes, so that
+// the following decisions (and algorithms) could see the moved pods.
+type Algorithm interface {
+	// Name returns the name of the algorithm, it's also recorded as the creator of Movements.
+	Name() string
+	// Reschedule returns the rescheduling decisions based on the given snapshot.
+	Reschedule(snapshot *Snapshot) []*Decision
+}
+
+// AlgorithmFactory builds an Algorithm from the controller configuration.
+type AlgorithmFactory func(args *reschedulerconfig.ReschedulerControllerConfiguration) Algorithm
+
+// Registry is a collection of all available rescheduling algorithms.
+type Registry map[string]AlgorithmFactory
+
+// NewInTreeRegistry builds the registry with all the in-tree algorithms.
+func NewInTreeRegistry() Registry {
+	return Registry{
+		reschedulerconfig.GPUDefragmentation: NewGPUDefragmentation,
+		reschedulerconfig.LoadBalancing:      NewLoadBalancing,
+		reschedulerconfig.NodeDraining:       NewNodeDraining,
+	}
+}
+
+// Register adds a new algorithm to the registry. An error is returned if the name already exists.
+func (r Registry) Register(name string, factory AlgorithmFactory) error {
+	if _, ok := r[name]; ok {
+		return fmt.Errorf("a rescheduling algorithm named %v already exists", name)
+	}
+	r[name] = factory
+	return nil
+}
+
+// Build instantiates the algorithms in the given order.
+func (r Registry) Build(names []string, args *reschedulerconfig.ReschedulerControllerConfiguration) ([]Algorithm, error) {
+	algorithms := make([]Algorithm, 0, len(names))
+	for _, name := range names {
+		factory, ok := r[name]
+		if !ok {
+			return nil, fmt.Errorf("rescheduling algorithm %v does not exist", name)
+		}
+		algorithms = append(algorithms, factory(args))
+	}
+	return algorithms, nil
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+)
+
+func makeNode(name string, cpu, gpu string) *v1.Node {
+	res := map[v1.ResourceName]string{v1.ResourceCPU: cpu, v1.ResourceMemory: "100Gi"}
+	if len(gpu) > 0 {
+		res[util.ResourceGPU] = gpu
+	}
+	return testinghelper.MakeNode().Name(name).Capacity(res).Obj()
+}
+
+func makeMovablePod(name, node, cpu, gpu string) *v1.Pod {
+	req := map[v1.ResourceName]string{v1.ResourceCPU: cpu}
+	if len(gpu) > 0 {
+		req[util.ResourceGPU] = gpu
+	}
+	return testinghelper.MakePod().Namespace("default").Name(name).UID(name).Node(node).Label("app", "foo").
+		ControllerRef(metav1.OwnerReference{Kind: "ReplicaSet", Name: "rs", UID: "rs"}).Req(req).Obj()
+}
+
+func makeCNR(name, cpuUsage string, updateTime time.Time) *katalystv1alpha1.CustomNodeResource {
+	usage := resource.MustParse(cpuUsage)
+	cnr := testinghelper.MakeNode().Name(name).CNRObj()
+	cnr.Status.NodeMetricStatus = &katalystv1alpha1.NodeMetricStatus{
+		UpdateTime: metav1.NewTime(updateTime),
+		NodeMetric: &katalystv1alpha1.NodeMetricInfo{
+			ResourceUsage: katalystv1alpha1.ResourceUsage{
+				GenericUsage: &katalystv1alpha1.ResourceMetric{CPU: &usage},
+			},
+		},
+	}
+	return cnr
+}
+
+func allMovable(*v1.Pod) bool { return true }
+
+func decisionsToMap(decisions []*Decision) map[string]string {
+	ret := make(map[string]string, len(decisions))
+	for _, d := range decisions {
+		ret[d.Pod.Name] = d.TargetNode
+	}
+	return ret
+}
+
+func TestGPUDefragmentation(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []*v1.Node
+		pods     []*v1.Pod
+		pdbs     []*policy.PodDisruptionBudget
+		limit    int
+		expected map[string]string
+	}{
+		{
+			name:  "pods on the least used node are packed onto the most used node",
+			nodes: []*v1.Node{makeNode("n1", "32", "8"), makeNode("n2", "32", "8"), makeNode("n3", "32", "8")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", "1"),
+				makeMovablePod("p2", "n2", "1", "6"),
+				makeMovablePod("p3", "n3", "1", "4"),
+			},
+			limit:    10,
+			expected: map[string]string{"p1": "n2"},
+		},
+		{
+			name:  "node is skipped if its gpu pods can't be moved together",
+			nodes: []*v1.Node{makeNode("n1", "32", "8"), makeNode("n2", "32", "8")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", "1"),
+				makeMovablePod("p2", "n1", "1", "1"),
+				makeMovablePod("p3", "n2", "1", "7"),
+			},
+			limit:    1,
+			expected: map[string]string{},
+		},
+		{
+			name:  "node is skipped if pdb doesn't allow disruptions",
+			nodes: []*v1.Node{makeNode("n1", "32", "8"), makeNode("n2", "32", "8")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", "1"),
+				makeMovablePod("p2", "n2", "1", "6"),
+			},
+			pdbs: []*policy.PodDisruptionBudget{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb"},
+				Spec:       policy.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}},
+				Status:     policy.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			}},
+			limit:    10,
+			expected: map[string]string{},
+		},
+		{
+			name:  "fully used nodes are not touched",
+			nodes: []*v1.Node{makeNode("n1", "32", "8"), makeNode("n2", "32", "8")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", "8"),
+				makeMovablePod("p2", "n2", "1", "6"),
+			},
+			limit:    10,
+			expected: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewSnapshot(tt.nodes, tt.pods, nil, tt.pdbs, allMovable)
+			snapshot.setLimit(tt.limit)
+			got := decisionsToMap(NewGPUDefragmentation(nil).Reschedule(snapshot))
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadBalancing(t *testing.T) {
+	args := &reschedulerconfig.ReschedulerControllerConfiguration{
+		LoadHighThreshold:           80,
+		LoadLowThreshold:            50,
+		NodeMetricExpirationSeconds: 300,
+	}
+	tests := []struct {
+		name     string
+		nodes    []*v1.Node
+		pods     []*v1.Pod
+		cnrs     []*katalystv1alpha1.CustomNodeResource
+		expected map[string]string
+	}{
+		{
+			name:  "pods are moved from overloaded node until it is below high threshold",
+			nodes: []*v1.Node{makeNode("n1", "10", ""), makeNode("n2", "10", ""), makeNode("n3", "10", "")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", ""),
+				makeMovablePod("p2", "n1", "2", ""),
+				makeMovablePod("p3", "n1", "3", ""),
+			},
+			cnrs: []*katalystv1alpha1.CustomNodeResource{
+				makeCNR("n1", "9", time.Now()),
+				makeCNR("n2", "4", time.Now()),
+				makeCNR("n3", "2", time.Now()),
+			},
+			expected: map[string]string{"p3": "n3"},
+		},
+		{
+			name:  "expired metrics are ignored",
+			nodes: []*v1.Node{makeNode("n1", "10", ""), makeNode("n2", "10", "")},
+			pods:  []*v1.Pod{makeMovablePod("p1", "n1", "3", "")},
+			cnrs: []*katalystv1alpha1.CustomNodeResource{
+				makeCNR("n1", "9", time.Now().Add(-time.Hour)),
+				makeCNR("n2", "1", time.Now()),
+			},
+			expected: map[string]string{},
+		},
+		{
+			name:  "target node should not become overloaded",
+			nodes: []*v1.Node{makeNode("n1", "10", ""), makeNode("n2", "10", "")},
+			pods:  []*v1.Pod{makeMovablePod("p1", "n1", "5", "")},
+			cnrs: []*katalystv1alpha1.CustomNodeResource{
+				makeCNR("n1", "9", time.Now()),
+				makeCNR("n2", "4", time.Now()),
+			},
+			expected: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewSnapshot(tt.nodes, tt.pods, tt.cnrs, nil, allMovable)
+			snapshot.setLimit(10)
+			got := decisionsToMap(NewLoadBalancing(args).Reschedule(snapshot))
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNodeDraining(t *testing.T) {
+	args := &reschedulerconfig.ReschedulerControllerConfiguration{DrainNodeLabel: reschedulerconfig.DefaultDrainNodeLabel}
+
+	draining := makeNode("n1", "10", "")
+	draining.Spec.Unschedulable = true
+	draining.Labels = map[string]string{reschedulerconfig.DefaultDrainNodeLabel: "true"}
+	cordoned := makeNode("n2", "10", "")
+	cordoned.Spec.Unschedulable = true
+
+	tests := []struct {
+		name     string
+		nodes    []*v1.Node
+		pods     []*v1.Pod
+		movable  func(*v1.Pod) bool
+		expected map[string]string
+	}{
+		{
+			name:  "pods on draining node are moved to the node with most free cpu",
+			nodes: []*v1.Node{draining, makeNode("n3", "10", ""), makeNode("n4", "10", "")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "4", ""),
+				makeMovablePod("p2", "n1", "4", ""),
+				makeMovablePod("p3", "n3", "1", ""),
+			},
+			movable:  allMovable,
+			expected: map[string]string{"p1": "n4", "p2": "n3"},
+		},
+		{
+			name:  "pods on cordoned node without drain label are not moved",
+			nodes: []*v1.Node{cordoned, makeNode("n3", "10", "")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n2", "1", ""),
+			},
+			movable:  allMovable,
+			expected: map[string]string{},
+		},
+		{
+			name:  "unmovable pods are left on the node",
+			nodes: []*v1.Node{draining, makeNode("n3", "10", "")},
+			pods: []*v1.Pod{
+				makeMovablePod("p1", "n1", "1", ""),
+				makeMovablePod("p2", "n1", "1", ""),
+			},
+			movable:  func(pod *v1.Pod) bool { return pod.Name != "p1" },
+			expected: map[string]string{"p2": "n3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := NewSnapshot(tt.nodes, tt.pods, nil, nil, tt.movable)
+			snapshot.setLimit(10)
+			got := decisionsToMap(NewNodeDraining(args).Reschedule(snapshot))
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSnapshotForget(t *testing.T) {
+	pod := makeMovablePod("p1", "n1", "4", "")
+	pdb := &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb"},
+		Spec:       policy.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}},
+		Status:     policy.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}
+	snapshot := NewSnapshot([]*v1.Node{makeNode("n1", "8", ""), makeNode("n2", "8", "")}, []*v1.Pod{pod}, nil, []*policy.PodDisruptionBudget{pdb}, allMovable)
+	snapshot.setLimit(1)
+	freeBefore := snapshot.GetNode("n2").Free()
+
+	snapshot.Assume(pod, "n2")
+	if snapshot.CanMove(pod) {
+		t.Fatalf("expected the assumed pod not to be movable again")
+	}
+
+	snapshot.Forget(pod, "n2")
+	if !snapshot.CanMove(pod) {
+		t.Errorf("expected the pod to be movable after forgetting the decision")
+	}
+	if got := snapshot.GetNode("n2").Free(); !reflect.DeepEqual(freeBefore, got) {
+		t.Errorf("expected free resources of n2 to be restored, want %v, got %v", freeBefore, got)
+	}
+	if len(snapshot.GetNode("n1").Pods) != 1 || len(snapshot.GetNode("n2").Pods) != 0 {
+		t.Errorf("expected the pod to be placed back to n1")
+	}
+}
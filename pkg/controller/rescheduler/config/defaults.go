@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+const (
+	DefaultCheckPeriod                 = 60
+	DefaultMovementTTL                 = 600
+	DefaultMaxPodsPerMovement          = 10
+	DefaultLoadHighThreshold           = 80
+	DefaultLoadLowThreshold            = 50
+	DefaultNodeMetricExpirationSeconds = 300
+	DefaultDrainNodeLabel              = "godel.bytedance.com/drain"
+
+	GPUDefragmentation = "GPUDefragmentation"
+	LoadBalancing      = "LoadBalancing"
+	NodeDraining       = "NodeDraining"
+)
+
+var DefaultAlgorithms = []string{NodeDraining, GPUDefragmentation, LoadBalancing}
+
+func SetDefaultReschedulerController(obj *ReschedulerControllerConfiguration) {
+	if obj.CheckPeriod == 0 {
+		obj.CheckPeriod = DefaultCheckPeriod
+	}
+	if obj.MovementTTL == 0 {
+		obj.MovementTTL = DefaultMovementTTL
+	}
+	if obj.MaxPodsPerMovement == 0 {
+		obj.MaxPodsPerMovement = DefaultMaxPodsPerMovement
+	}
+	if len(obj.Algorithms) == 0 {
+		obj.Algorithms = append([]string{}, DefaultAlgorithms...)
+	}
+	if obj.LoadHighThreshold == 0 {
+		obj.LoadHighThreshold = DefaultLoadHighThreshold
+	}
+	if obj.LoadLowThreshold == 0 {
+		obj.LoadLowThreshold = DefaultLoadLowThreshold
+	}
+	if obj.NodeMetricExpirationSeconds == 0 {
+		obj.NodeMetricExpirationSeconds = DefaultNodeMetricExpirationSeconds
+	}
+	if len(obj.DrainNodeLabel) == 0 {
+		obj.DrainNodeLabel = DefaultDrainNodeLabel
+	}
+}
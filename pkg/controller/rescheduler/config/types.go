@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+type ReschedulerControllerConfiguration struct {
+	// CheckPeriod is the interval (in seconds) between two rounds of rescheduling decisions.
+	CheckPeriod int64
+	// MovementTTL is how long (in seconds) a Movement created by the controller is kept
+	// before it will be recycled.
+	MovementTTL int64
+	// MaxPodsPerMovement is the maximum number of pods that could be moved by a single Movement.
+	MaxPodsPerMovement int64
+	// Algorithms is the list of rescheduling algorithms to run, in order.
+	Algorithms []string
+	// IgnoredNamespace is the list of namespace whose pods will never be moved.
+	IgnoredNamespace []string
+
+	// LoadHighThreshold is the cpu usage percentage (based on CNR metrics) above which
+	// a node is regarded as overloaded by the LoadBalancing algorithm.
+	LoadHighThreshold int64
+	// LoadLowThreshold is the cpu usage percentage (based on CNR metrics) below which
+	// a node is regarded as underloaded by the LoadBalancing algorithm.
+	LoadLowThreshold int64
+	// NodeMetricExpirationSeconds indicates how long the CNR metrics are considered valid.
+	NodeMetricExpirationSeconds int64
+
+	// DrainNodeLabel is the label key that marks an unschedulable node to be drained
+	// by the NodeDraining algorithm, the label value must be "true".
+	DrainNodeLabel string
+}
+
+func NewReschedulerControllerConfiguration() *ReschedulerControllerConfiguration {
+	return &ReschedulerControllerConfiguration{}
+}
+
+func (c *ReschedulerControllerConfiguration) DeepCopyInto(out *ReschedulerControllerConfiguration) {
+	*out = *c
+	if c.Algorithms != nil {
+		out.Algorithms = make([]string, len(c.Algorithms))
+		copy(out.Algorithms, c.Algorithms)
+	}
+	if c.IgnoredNamespace != nil {
+		out.IgnoredNamespace = make([]string, len(c.IgnoredNamespace))
+		copy(out.IgnoredNamespace, c.IgnoredNamespace)
+	}
+}
+
+func (c *ReschedulerControllerConfiguration) DeepCopy() *ReschedulerControllerConfiguration {
+	if c == nil {
+		return nil
+	}
+	out := new(ReschedulerControllerConfiguration)
+	c.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+)
+
+// gpuDefragmentation consolidates the gpu pods running on partially used gpu nodes,
+// so that as many gpu nodes as possible could be released entirely for large gpu requests.
+// The least used nodes are emptied first, and their gpu pods are packed onto the most used ones.
+type gpuDefragmentation struct{}
+
+var _ Algorithm = &gpuDefragmentation{}
+
+func NewGPUDefragmentation(_ *reschedulerconfig.ReschedulerControllerConfiguration) Algorithm {
+	return &gpuDefragmentation{}
+}
+
+func (g *gpuDefragmentation) Name() string {
+	return reschedulerconfig.GPUDefragmentation
+}
+
+func (g *gpuDefragmentation) Reschedule(snapshot *Snapshot) []*Decision {
+	var fragmented []*NodeState
+	for _, node := range snapshot.Nodes() {
+		if requested := gpuRequested(node); requested > 0 && requested < gpuAllocatable(node) {
+			fragmented = append(fragmented, node)
+		}
+	}
+	if len(fragmented) < 2 {
+		return nil
+	}
+	sort.SliceStable(fragmented, func(i, j int) bool {
+		return gpuRequested(fragmented[i]) < gpuRequested(fragmented[j])
+	})
+
+	var (
+		decisions []*Decision
+		// drained holds the nodes whose gpu pods will be moved away, they can't be used as targets anymore.
+		drained = sets.NewString()
+		// received holds the nodes which will accept pods, they can't be drained anymore.
+		received = sets.NewString()
+	)
+	for _, source := range fragmented {
+		if received.Has(source.Name()) {
+			continue
+		}
+		var gpuPods []*v1.Pod
+		for _, pod := range source.Pods {
+			if podGPURequest(pod) > 0 {
+				gpuPods = append(gpuPods, pod)
+			}
+		}
+		if len(gpuPods) == 0 || !snapshot.CanMove(gpuPods...) {
+			continue
+		}
+
+		// Targets are sorted by gpu usage descending, so that they will be filled up first.
+		var targets []*NodeState
+		for _, node := range fragmented {
+			if node != source && !drained.Has(node.Name()) {
+				targets = append(targets, node)
+			}
+		}
+		sort.SliceStable(targets, func(i, j int) bool {
+			return gpuRequested(targets[i]) > gpuRequested(targets[j])
+		})
+
+		plan := g.planMoves(snapshot, gpuPods, targets)
+		if plan == nil {
+			continue
+		}
+		for i, pod := range gpuPods {
+			snapshot.Assume(pod, plan[i])
+			decisions = append(decisions, &Decision{Pod: pod, TargetNode: plan[i]})
+			received.Insert(plan[i])
+		}
+		drained.Insert(source.Name())
+	}
+	return decisions
+}
+
+// planMoves finds a target node for each pod, it returns nil if any pod can't be placed.
+func (g *gpuDefragmentation) planMoves(snapshot *Snapshot, pods []*v1.Pod, targets []*NodeState) []string {
+	pending := make(map[string]*framework.Resource, len(targets))
+	plan := make([]string, len(pods))
+	for i, pod := range pods {
+		for _, target := range targets {
+			if pending[target.Name()] == nil {
+				pending[target.Name()] = &framework.Resource{}
+			}
+			if !snapshot.fitsWithPending(pod, target, pending[target.Name()]) {
+				continue
+			}
+			request, _, _ := framework.CalculateResource(pod)
+			pending[target.Name()].AddResource(&request)
+			pending[target.Name()].AllowedPodNumber++
+			plan[i] = target.Name()
+			break
+		}
+		if len(plan[i]) == 0 {
+			return nil
+		}
+	}
+	return plan
+}
+
+func gpuAllocatable(node *NodeState) int64 {
+	return node.Allocatable.ScalarResources[util.ResourceGPU]
+}
+
+func gpuRequested(node *NodeState) int64 {
+	return node.Requested.ScalarResources[util.ResourceGPU]
+}
+
+func podGPURequest(pod *v1.Pod) int64 {
+	request, _, _ := framework.CalculateResource(pod)
+	return request.ScalarResources[util.ResourceGPU]
+}
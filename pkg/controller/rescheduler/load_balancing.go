@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+// loadBalancing moves pods from the overloaded nodes to the underloaded ones, the node load
+// is based on the cpu usage reported in CNR, which is the same data source used by LoadAware.
+// Since the usage of a single pod is unknown, the cpu request is used as its estimated usage.
+type loadBalancing struct {
+	highThreshold int64
+	lowThreshold  int64
+	expiration    time.Duration
+}
+
+var _ Algorithm = &loadBalancing{}
+
+func NewLoadBalancing(args *reschedulerconfig.ReschedulerControllerConfiguration) Algorithm {
+	return &loadBalancing{
+		highThreshold: args.LoadHighThreshold,
+		lowThreshold:  args.LoadLowThreshold,
+		expiration:    time.Duration(args.NodeMetricExpirationSeconds) * time.Second,
+	}
+}
+
+func (l *loadBalancing) Name() string {
+	return reschedulerconfig.LoadBalancing
+}
+
+func (l *loadBalancing) Reschedule(snapshot *Snapshot) []*Decision {
+	// usage is the estimated cpu usage (in milli) of nodes with valid metrics.
+	usage := make(map[string]int64)
+	var overloaded, underloaded []*NodeState
+	for _, node := range snapshot.Nodes() {
+		milliCPU, ok := l.cpuUsage(node)
+		if !ok {
+			continue
+		}
+		usage[node.Name()] = milliCPU
+		if l.percentage(node, milliCPU) > l.highThreshold {
+			overloaded = append(overloaded, node)
+		} else if l.percentage(node, milliCPU) < l.lowThreshold {
+			underloaded = append(underloaded, node)
+		}
+	}
+	if len(overloaded) == 0 || len(underloaded) == 0 {
+		return nil
+	}
+	sort.SliceStable(overloaded, func(i, j int) bool {
+		return l.percentage(overloaded[i], usage[overloaded[i].Name()]) > l.percentage(overloaded[j], usage[overloaded[j].Name()])
+	})
+
+	var decisions []*Decision
+	for _, source := range overloaded {
+		// Move the pods with larger requests first to reduce the number of movements.
+		pods := append([]*v1.Pod{}, source.Pods...)
+		sort.SliceStable(pods, func(i, j int) bool {
+			return podCPURequest(pods[i]) > podCPURequest(pods[j])
+		})
+		for _, pod := range pods {
+			if l.percentage(source, usage[source.Name()]) <= l.highThreshold {
+				break
+			}
+			request := podCPURequest(pod)
+			if request == 0 || !snapshot.CanMove(pod) {
+				continue
+			}
+			var target *NodeState
+			for _, node := range underloaded {
+				if l.percentage(node, usage[node.Name()]+request) >= l.highThreshold || !snapshot.Fits(pod, node) {
+					continue
+				}
+				if target == nil || l.percentage(node, usage[node.Name()]) < l.percentage(target, usage[target.Name()]) {
+					target = node
+				}
+			}
+			if target == nil {
+				continue
+			}
+			snapshot.Assume(pod, target.Name())
+			decisions = append(decisions, &Decision{Pod: pod, TargetNode: target.Name()})
+			usage[source.Name()] -= request
+			usage[target.Name()] += request
+		}
+	}
+	return decisions
+}
+
+// cpuUsage returns the cpu usage of node reported in CNR, it returns false if the metrics are missing or expired.
+func (l *loadBalancing) cpuUsage(node *NodeState) (int64, bool) {
+	if node.CNR == nil || node.CNR.Status.NodeMetricStatus == nil || node.Allocatable.MilliCPU <= 0 {
+		return 0, false
+	}
+	status := node.CNR.Status.NodeMetricStatus
+	if status.NodeMetric == nil || status.NodeMetric.GenericUsage == nil || status.NodeMetric.GenericUsage.CPU == nil {
+		return 0, false
+	}
+	if l.expiration > 0 && time.Since(status.UpdateTime.Time) > l.expiration {
+		return 0, false
+	}
+	return status.NodeMetric.GenericUsage.CPU.MilliValue(), true
+}
+
+func (l *loadBalancing) percentage(node *NodeState, milliCPU int64) int64 {
+	return milliCPU * 100 / node.Allocatable.MilliCPU
+}
+
+func podCPURequest(pod *v1.Pod) int64 {
+	request, _, _ := framework.CalculateResource(pod)
+	return request.MilliCPU
+}
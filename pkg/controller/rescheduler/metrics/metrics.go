@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	k8smetrics "k8s.io/component-base/metrics"
+)
+
+const (
+	SuccessResult = "success"
+	FailureResult = "failure"
+)
+
+var (
+	movementAPICallCounter = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Name:           "rescheduler_movement_api_call_counter",
+			Help:           "the number of movement api calls made by rescheduler. Broken down by algorithm, verb and result.",
+			StabilityLevel: k8smetrics.ALPHA,
+		}, []string{"algorithm", "verb", "result"})
+
+	evictedPodCounter = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Name:           "rescheduler_evicted_pod_counter",
+			Help:           "the number of pods evicted by rescheduler. Broken down by algorithm and result.",
+			StabilityLevel: k8smetrics.ALPHA,
+		}, []string{"algorithm", "result"})
+
+	reschedulingLatency = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Name:           "rescheduler_algorithm_duration_seconds",
+			Help:           "rescheduling algorithm latency in seconds. Broken down by algorithm.",
+			StabilityLevel: k8smetrics.ALPHA,
+			Buckets:        k8smetrics.ExponentialBuckets(0.001, 2, 15),
+		}, []string{"algorithm"})
+)
+
+func IncreaseMovementAPICall(algorithm, verb, result string) {
+	movementAPICallCounter.WithLabelValues(algorithm, verb, result).Inc()
+}
+
+func IncreaseEvictedPod(algorithm, result string) {
+	evictedPodCounter.WithLabelValues(algorithm, result).Inc()
+}
+
+func ObserveReschedulingLatency(algorithm string, latency float64) {
+	reschedulingLatency.WithLabelValues(algorithm).Observe(latency)
+}
+
+func Install(metricList *[]k8smetrics.Registerable) {
+	*metricList = append(*metricList,
+		movementAPICallCounter,
+		evictedPodCounter,
+		reschedulingLatency,
+	)
+}
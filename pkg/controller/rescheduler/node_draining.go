@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+)
+
+// nodeDraining moves pods away from the nodes which are cordoned and labeled to be drained.
+// Pods are recommended to the nodes with the most free cpu, pods which can't be moved
+// (e.g. not preemptible or protected by PDB) are left on the node.
+type nodeDraining struct {
+	drainNodeLabel string
+}
+
+var _ Algorithm = &nodeDraining{}
+
+func NewNodeDraining(args *reschedulerconfig.ReschedulerControllerConfiguration) Algorithm {
+	return &nodeDraining{
+		drainNodeLabel: args.DrainNodeLabel,
+	}
+}
+
+func (d *nodeDraining) Name() string {
+	return reschedulerconfig.NodeDraining
+}
+
+func (d *nodeDraining) Reschedule(snapshot *Snapshot) []*Decision {
+	var decisions []*Decision
+	for _, source := range snapshot.Nodes() {
+		if !d.shouldDrain(source.Node) {
+			continue
+		}
+		pods := append([]*v1.Pod{}, source.Pods...)
+		for _, pod := range pods {
+			if !snapshot.CanMove(pod) {
+				continue
+			}
+			var target *NodeState
+			for _, node := range snapshot.Nodes() {
+				// Draining nodes are unschedulable, so they are already excluded by Fits.
+				if !snapshot.Fits(pod, node) {
+					continue
+				}
+				if target == nil || node.Free().MilliCPU > target.Free().MilliCPU {
+					target = node
+				}
+			}
+			if target == nil {
+				continue
+			}
+			snapshot.Assume(pod, target.Name())
+			decisions = append(decisions, &Decision{Pod: pod, TargetNode: target.Name()})
+		}
+	}
+	return decisions
+}
+
+func (d *nodeDraining) shouldDrain(node *v1.Node) bool {
+	return node.Spec.Unschedulable && node.Labels[d.drainNodeLabel] == "true"
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	policyinformers "k8s.io/client-go/informers/policy/v1"
+	schedulinginformers "k8s.io/client-go/informers/scheduling/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	schedulinglisters "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
+	movementinformer "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/scheduling/v1alpha1"
+	movementlister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	controllersmetrics "github.com/kubewharf/godel-scheduler/pkg/controller/metrics"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+	reschedulermetrics "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/metrics"
+	frameworkutils "github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	cnrinformer "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions/node/v1alpha1"
+	cnrlister "github.com/kubewharf/katalyst-api/pkg/client/listers/node/v1alpha1"
+)
+
+const (
+	ControllerName = "rescheduler-controller"
+
+	// MovementCreatedByLabelKey marks the Movements created by the rescheduler controller,
+	// only these Movements will be recycled by the controller.
+	MovementCreatedByLabelKey = "godel.bytedance.com/created-by"
+)
+
+type ReschedulerController struct {
+	godelClient godelclient.Interface
+	kubeClient  clientset.Interface
+
+	podLister           corelisters.PodLister
+	nodeLister          corelisters.NodeLister
+	pdbLister           policylisters.PodDisruptionBudgetLister
+	priorityClassLister schedulinglisters.PriorityClassLister
+	movementLister      movementlister.MovementLister
+	cnrLister           cnrlister.CustomNodeResourceLister
+	informerSyncedFuncs []cache.InformerSynced
+	ignoredNamespace    sets.String
+	checkPeriod         int64
+	movementTTL         int64
+	maxPodsPerMovement  int64
+	algorithms          []Algorithm
+	movementGenerations map[string]uint
+}
+
+// NewReschedulerController creates the rescheduler controller, cnrInformer is optional
+// and the algorithms depending on node metrics will make no decision without it.
+func NewReschedulerController(
+	godelClient godelclient.Interface,
+	kubeClient clientset.Interface,
+	podInformer coreinformers.PodInformer,
+	nodeInformer coreinformers.NodeInformer,
+	pdbInformer policyinformers.PodDisruptionBudgetInformer,
+	priorityClassInformer schedulinginformers.PriorityClassInformer,
+	movementInformer movementinformer.MovementInformer,
+	cnrInformer cnrinformer.CustomNodeResourceInformer,
+	args *reschedulerconfig.ReschedulerControllerConfiguration,
+	registry Registry,
+) (*ReschedulerController, error) {
+	algorithms, err := registry.Build(args.Algorithms, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &ReschedulerController{
+		godelClient:         godelClient,
+		kubeClient:          kubeClient,
+		podLister:           podInformer.Lister(),
+		nodeLister:          nodeInformer.Lister(),
+		pdbLister:           pdbInformer.Lister(),
+		priorityClassLister: priorityClassInformer.Lister(),
+		movementLister:      movementInformer.Lister(),
+		informerSyncedFuncs: []cache.InformerSynced{
+			podInformer.Informer().HasSynced,
+			nodeInformer.Informer().HasSynced,
+			pdbInformer.Informer().HasSynced,
+			priorityClassInformer.Informer().HasSynced,
+			movementInformer.Informer().HasSynced,
+		},
+		ignoredNamespace:    sets.NewString(args.IgnoredNamespace...),
+		checkPeriod:         args.CheckPeriod,
+		movementTTL:         args.MovementTTL,
+		maxPodsPerMovement:  args.MaxPodsPerMovement,
+		algorithms:          algorithms,
+		movementGenerations: make(map[string]uint),
+	}
+	if cnrInformer != nil {
+		rc.cnrLister = cnrInformer.Lister()
+		rc.informerSyncedFuncs = append(rc.informerSyncedFuncs, cnrInformer.Informer().HasSynced)
+	}
+	return rc, nil
+}
+
+func (rc *ReschedulerController) Run(ctx context.Context, controllerManagerMetrics *controllersmetrics.ControllerManagerMetrics) {
+	defer utilruntime.HandleCrash()
+	controllerManagerMetrics.ControllerStarted(ControllerName)
+	defer controllerManagerMetrics.ControllerStopped(ControllerName)
+
+	klog.V(3).InfoS("Starting Rescheduler Controller")
+	defer klog.V(3).InfoS("Shutting down Rescheduler Controller")
+
+	if !cache.WaitForNamedCacheSync("Rescheduler", ctx.Done(), rc.informerSyncedFuncs...) {
+		return
+	}
+
+	checkPeriod := time.Duration(rc.checkPeriod) * time.Second
+
+	go wait.UntilWithContext(ctx, rc.reschedule, checkPeriod)
+
+	<-ctx.Done()
+}
+
+// reschedule runs a round of rescheduling. All the algorithms share the same snapshot, and an
+// algorithm will be skipped if the Movement it created previously has not been recycled yet.
+func (rc *ReschedulerController) reschedule(ctx context.Context) {
+	inflight := rc.gcMovements(ctx)
+
+	snapshot, err := rc.buildSnapshot()
+	if err != nil {
+		klog.ErrorS(err, "Failed to build snapshot for rescheduling")
+		return
+	}
+
+	for _, algorithm := range rc.algorithms {
+		if inflight.Has(algorithm.Name()) {
+			klog.V(4).InfoS("Skipped rescheduling algorithm since its previous movement is in progress", "algorithm", algorithm.Name())
+			continue
+		}
+
+		start := time.Now()
+		snapshot.setLimit(int(rc.maxPodsPerMovement))
+		decisions := algorithm.Reschedule(snapshot)
+		reschedulermetrics.ObserveReschedulingLatency(algorithm.Name(), helper.SinceInSeconds(start))
+		if len(decisions) == 0 {
+			continue
+		}
+
+		movement, err := rc.createMovement(ctx, algorithm.Name(), decisions)
+		if err != nil {
+			klog.ErrorS(err, "Failed to create movement", "algorithm", algorithm.Name())
+			// No pod will be evicted, so the following algorithms should not see these decisions.
+			for _, decision := range decisions {
+				snapshot.Forget(decision.Pod, decision.TargetNode)
+			}
+			continue
+		}
+		klog.V(3).InfoS("Succeed to create movement", "movement", movement.Name, "algorithm", algorithm.Name(), "podCount", len(decisions))
+
+		// The owners will recreate the evicted pods, and the scheduler will place them
+		// according to the recommended nodes in the Movement.
+		for _, decision := range decisions {
+			rc.evictPod(ctx, algorithm.Name(), movement, decision.Pod)
+		}
+	}
+}
+
+func (rc *ReschedulerController) buildSnapshot() (*Snapshot, error) {
+	nodes, err := rc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pods, err := rc.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pdbs, err := rc.pdbLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var cnrs []*katalystv1alpha1.CustomNodeResource
+	if rc.cnrLister != nil {
+		if cnrs, err = rc.cnrLister.List(labels.Everything()); err != nil {
+			return nil, err
+		}
+	}
+	return NewSnapshot(nodes, pods, cnrs, pdbs, rc.isPodMovable), nil
+}
+
+// isPodMovable checks whether the pod could be evicted and recreated by its owner.
+func (rc *ReschedulerController) isPodMovable(pod *v1.Pod) bool {
+	if rc.ignoredNamespace.Has(pod.Namespace) || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if podutil.PodHasDaemonSetOwnerReference(pod) || podutil.IsReservationPlaceholderPod(pod) {
+		return false
+	}
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	// Pods without owner will not be recreated after eviction.
+	if podutil.GetPodOwnerInfo(pod) == nil {
+		return false
+	}
+
+	switch podutil.CanPodBePreempted(pod) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+	pc, err := rc.priorityClassLister.Get(pod.Spec.PriorityClassName)
+	if err != nil {
+		klog.V(5).InfoS("Failed to get PriorityClass for pod", "pod", klog.KObj(pod), "err", err)
+		return false
+	}
+	return pc.Annotations[util.CanBePreemptedAnnotationKey] == util.CanBePreempted
+}
+
+// gcMovements deletes the expired Movements created by the controller,
+// and returns the creators of the Movements which are still in progress.
+func (rc *ReschedulerController) gcMovements(ctx context.Context) sets.String {
+	inflight := sets.NewString()
+	movements, err := rc.movementLister.List(labels.SelectorFromSet(labels.Set{MovementCreatedByLabelKey: ControllerName}))
+	if err != nil {
+		klog.ErrorS(err, "Error while listing movements")
+		return inflight
+	}
+
+	ttl := time.Duration(rc.movementTTL) * time.Second
+	for _, movement := range movements {
+		creator := movement.Spec.Creator
+		if movement.Spec.Generation > rc.movementGenerations[creator] {
+			rc.movementGenerations[creator] = movement.Spec.Generation
+		}
+		if time.Since(movement.CreationTimestamp.Time) <= ttl {
+			inflight.Insert(creator)
+			continue
+		}
+
+		result := reschedulermetrics.SuccessResult
+		err := rc.godelClient.SchedulingV1alpha1().Movements().Delete(ctx, movement.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete movement", "movement", movement.Name, "algorithm", creator)
+			result = reschedulermetrics.FailureResult
+			inflight.Insert(creator)
+		} else {
+			klog.V(4).InfoS("Succeed to delete movement", "movement", movement.Name, "algorithm", creator)
+		}
+		reschedulermetrics.IncreaseMovementAPICall(creator, "delete", result)
+	}
+	return inflight
+}
+
+func (rc *ReschedulerController) createMovement(ctx context.Context, algorithm string, decisions []*Decision) (*schedulingv1a1.Movement, error) {
+	rc.movementGenerations[algorithm]++
+	movement := newMovement(algorithm, rc.movementGenerations[algorithm], decisions)
+
+	result := reschedulermetrics.SuccessResult
+	created, err := rc.godelClient.SchedulingV1alpha1().Movements().Create(ctx, movement, metav1.CreateOptions{})
+	if err != nil {
+		result = reschedulermetrics.FailureResult
+	}
+	reschedulermetrics.IncreaseMovementAPICall(algorithm, "create", result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status is a subresource, so the recommendations have to be updated separately.
+	created.Status = movement.Status
+	result = reschedulermetrics.SuccessResult
+	updated, err := rc.godelClient.SchedulingV1alpha1().Movements().UpdateStatus(ctx, created, metav1.UpdateOptions{})
+	if err != nil {
+		result = reschedulermetrics.FailureResult
+	}
+	reschedulermetrics.IncreaseMovementAPICall(algorithm, "update_status", result)
+	if err != nil {
+		// Movements without owners are ignored by the schedulers, so the pods will not be evicted.
+		if deleteErr := rc.godelClient.SchedulingV1alpha1().Movements().Delete(ctx, created.Name, metav1.DeleteOptions{}); deleteErr != nil {
+			klog.ErrorS(deleteErr, "Failed to delete movement", "movement", created.Name, "algorithm", algorithm)
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (rc *ReschedulerController) evictPod(ctx context.Context, algorithm string, movement *schedulingv1a1.Movement, pod *v1.Pod) {
+	eviction := &policy.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	result := reschedulermetrics.SuccessResult
+	if err := rc.kubeClient.CoreV1().Pods(pod.Namespace).EvictV1(ctx, eviction); err != nil {
+		klog.ErrorS(err, "Failed to evict pod", "pod", klog.KObj(pod), "movement", movement.Name, "algorithm", algorithm)
+		result = reschedulermetrics.FailureResult
+	} else {
+		klog.V(4).InfoS("Succeed to evict pod", "pod", klog.KObj(pod), "movement", movement.Name, "algorithm", algorithm)
+	}
+	reschedulermetrics.IncreaseEvictedPod(algorithm, result)
+}
+
+// newMovement builds the Movement for the decisions, the recommended nodes are grouped by pod owners.
+func newMovement(algorithm string, generation uint, decisions []*Decision) *schedulingv1a1.Movement {
+	movement := &schedulingv1a1.Movement{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "rescheduler-",
+			Labels:       map[string]string{MovementCreatedByLabelKey: ControllerName},
+		},
+		Spec: schedulingv1a1.MovementSpec{
+			Creator:    algorithm,
+			Generation: generation,
+		},
+	}
+
+	owners := make(map[string]*schedulingv1a1.Owner)
+	recommendations := make(map[string]map[string]*schedulingv1a1.RecommendedNode)
+	for _, decision := range decisions {
+		movement.Spec.DeletedTasks = append(movement.Spec.DeletedTasks, frameworkutils.GetTaskInfoFromPod(decision.Pod))
+
+		ownerInfo := podutil.GetPodOwnerInfo(decision.Pod)
+		if ownerInfo == nil {
+			continue
+		}
+		key := podutil.GetOwnerInfoKey(ownerInfo)
+		if _, ok := owners[key]; !ok {
+			owners[key] = &schedulingv1a1.Owner{Owner: ownerInfo}
+			recommendations[key] = make(map[string]*schedulingv1a1.RecommendedNode)
+		}
+		if _, ok := recommendations[key][decision.TargetNode]; !ok {
+			recommendations[key][decision.TargetNode] = &schedulingv1a1.RecommendedNode{Node: decision.TargetNode}
+			owners[key].RecommendedNodes = append(owners[key].RecommendedNodes, recommendations[key][decision.TargetNode])
+		}
+		recommendations[key][decision.TargetNode].DesiredPodCount++
+	}
+
+	keys := make([]string, 0, len(owners))
+	for key := range owners {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		movement.Status.Owners = append(movement.Status.Owners, owners[key])
+	}
+	return movement
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	reschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/controller/rescheduler/config"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/controller"
+)
+
+func newTestController(t *testing.T, kubeObjects []runtime.Object, movements []*schedulingv1a1.Movement) (*ReschedulerController, *godelfake.Clientset, *fake.Clientset) {
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	godelClient := godelfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	godelInformerFactory := crdinformers.NewSharedInformerFactory(godelClient, controller.NoResyncPeriodFunc())
+
+	args := reschedulerconfig.NewReschedulerControllerConfiguration()
+	reschedulerconfig.SetDefaultReschedulerController(args)
+	args.Algorithms = []string{reschedulerconfig.NodeDraining}
+
+	rc, err := NewReschedulerController(godelClient, kubeClient,
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Policy().V1().PodDisruptionBudgets(),
+		informerFactory.Scheduling().V1().PriorityClasses(),
+		godelInformerFactory.Scheduling().V1alpha1().Movements(),
+		nil, args, NewInTreeRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range kubeObjects {
+		switch o := obj.(type) {
+		case *v1.Pod:
+			informerFactory.Core().V1().Pods().Informer().GetStore().Add(o)
+		case *v1.Node:
+			informerFactory.Core().V1().Nodes().Informer().GetStore().Add(o)
+		case *schedulingv1.PriorityClass:
+			informerFactory.Scheduling().V1().PriorityClasses().Informer().GetStore().Add(o)
+		}
+	}
+	for _, movement := range movements {
+		godelClient.SchedulingV1alpha1().Movements().Create(context.TODO(), movement, metav1.CreateOptions{})
+		godelInformerFactory.Scheduling().V1alpha1().Movements().Informer().GetStore().Add(movement)
+	}
+	return rc, godelClient, kubeClient
+}
+
+func TestIsPodMovable(t *testing.T) {
+	preemptible := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "preemptible",
+			Annotations: map[string]string{util.CanBePreemptedAnnotationKey: util.CanBePreempted},
+		},
+	}
+	nonPreemptible := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "non-preemptible"}}
+	rsRef := metav1.OwnerReference{Kind: "ReplicaSet", Name: "rs", UID: "rs"}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected bool
+	}{
+		{
+			name:     "pod with preemptible priority class",
+			pod:      testinghelper.MakePod().Namespace("default").Name("p").ControllerRef(rsRef).PriorityClassName("preemptible").Obj(),
+			expected: true,
+		},
+		{
+			name:     "pod with non-preemptible priority class",
+			pod:      testinghelper.MakePod().Namespace("default").Name("p").ControllerRef(rsRef).PriorityClassName("non-preemptible").Obj(),
+			expected: false,
+		},
+		{
+			name: "pod annotated as preemptible",
+			pod: testinghelper.MakePod().Namespace("default").Name("p").ControllerRef(rsRef).PriorityClassName("non-preemptible").
+				Annotation(util.CanBePreemptedAnnotationKey, util.CanBePreempted).Obj(),
+			expected: true,
+		},
+		{
+			name: "pod annotated as non-preemptible",
+			pod: testinghelper.MakePod().Namespace("default").Name("p").ControllerRef(rsRef).PriorityClassName("preemptible").
+				Annotation(util.CanBePreemptedAnnotationKey, "false").Obj(),
+			expected: false,
+		},
+		{
+			name:     "pod without owner",
+			pod:      testinghelper.MakePod().Namespace("default").Name("p").PriorityClassName("preemptible").Obj(),
+			expected: false,
+		},
+		{
+			name: "daemonset pod",
+			pod: testinghelper.MakePod().Namespace("default").Name("p").PriorityClassName("preemptible").
+				ControllerRef(metav1.OwnerReference{Kind: "DaemonSet", Name: "ds", UID: "ds"}).Obj(),
+			expected: false,
+		},
+		{
+			name:     "terminating pod",
+			pod:      testinghelper.MakePod().Namespace("default").Name("p").ControllerRef(rsRef).PriorityClassName("preemptible").Terminating().Obj(),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, _, _ := newTestController(t, []runtime.Object{preemptible, nonPreemptible}, nil)
+			if got := rc.isPodMovable(tt.pod); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewMovement(t *testing.T) {
+	p1 := makeMovablePod("p1", "n1", "1", "")
+	p2 := makeMovablePod("p2", "n1", "1", "")
+	p3 := makeMovablePod("p3", "n2", "1", "")
+	decisions := []*Decision{
+		{Pod: p1, TargetNode: "n3"},
+		{Pod: p2, TargetNode: "n3"},
+		{Pod: p3, TargetNode: "n4"},
+	}
+
+	movement := newMovement(reschedulerconfig.NodeDraining, 2, decisions)
+	if movement.Spec.Creator != reschedulerconfig.NodeDraining || movement.Spec.Generation != 2 {
+		t.Errorf("unexpected movement spec: %#v", movement.Spec)
+	}
+	if movement.Labels[MovementCreatedByLabelKey] != ControllerName {
+		t.Errorf("unexpected movement labels: %v", movement.Labels)
+	}
+	if len(movement.Spec.DeletedTasks) != 3 {
+		t.Errorf("expected 3 deleted tasks, got: %d", len(movement.Spec.DeletedTasks))
+	}
+	expectedOwners := []*schedulingv1a1.Owner{
+		{
+			Owner: &schedulingv1a1.OwnerInfo{Type: "ReplicaSet", Name: "rs", Namespace: "default", UID: "rs"},
+			RecommendedNodes: []*schedulingv1a1.RecommendedNode{
+				{Node: "n3", DesiredPodCount: 2},
+				{Node: "n4", DesiredPodCount: 1},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expectedOwners, movement.Status.Owners) {
+		t.Errorf("expected: %v, got: %v", expectedOwners, movement.Status.Owners)
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	draining := makeNode("n1", "10", "")
+	draining.Spec.Unschedulable = true
+	draining.Labels = map[string]string{reschedulerconfig.DefaultDrainNodeLabel: "true"}
+	pod := makeMovablePod("p1", "n1", "1", "")
+	pod.Annotations[util.CanBePreemptedAnnotationKey] = util.CanBePreempted
+	pod.Spec.PriorityClassName = "pc"
+
+	expiredMovement := newMovement(reschedulerconfig.NodeDraining, 1, nil)
+	expiredMovement.Name = "expired"
+	expiredMovement.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	inflightMovement := newMovement(reschedulerconfig.NodeDraining, 1, nil)
+	inflightMovement.Name = "inflight"
+	inflightMovement.CreationTimestamp = metav1.Now()
+
+	tests := []struct {
+		name              string
+		movements         []*schedulingv1a1.Movement
+		expectedMovements int
+		expectedEvicted   bool
+	}{
+		{
+			name:              "movement is created and pod is evicted",
+			expectedMovements: 1,
+			expectedEvicted:   true,
+		},
+		{
+			name:              "expired movement is recycled before rescheduling",
+			movements:         []*schedulingv1a1.Movement{expiredMovement},
+			expectedMovements: 1,
+			expectedEvicted:   true,
+		},
+		{
+			name:              "algorithm is skipped if its movement is in progress",
+			movements:         []*schedulingv1a1.Movement{inflightMovement},
+			expectedMovements: 1,
+			expectedEvicted:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{draining, makeNode("n2", "10", ""), pod.DeepCopy()}
+			rc, godelClient, kubeClient := newTestController(t, objects, tt.movements)
+			rc.reschedule(context.TODO())
+
+			movements, err := godelClient.SchedulingV1alpha1().Movements().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(movements.Items) != tt.expectedMovements {
+				t.Errorf("expected %d movements, got: %d", tt.expectedMovements, len(movements.Items))
+			}
+
+			evicted := false
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "create" && action.GetSubresource() == "eviction" {
+					evicted = true
+				}
+			}
+			if evicted != tt.expectedEvicted {
+				t.Errorf("expected evicted: %v, got: %v", tt.expectedEvicted, evicted)
+			}
+			if tt.expectedEvicted {
+				owners := movements.Items[0].Status.Owners
+				if len(owners) != 1 || len(owners[0].RecommendedNodes) != 1 || owners[0].RecommendedNodes[0].Node != "n2" {
+					t.Errorf("unexpected movement owners: %v", owners)
+				}
+			}
+		})
+	}
+}
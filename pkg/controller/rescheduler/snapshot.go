@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rescheduler
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+)
+
+// NodeState is the view of a node used by rescheduling algorithms.
+type NodeState struct {
+	Node *v1.Node
+	// CNR may be nil if the node does not report any CustomNodeResource.
+	CNR         *katalystv1alpha1.CustomNodeResource
+	Pods        []*v1.Pod
+	Allocatable *framework.Resource
+	Requested   *framework.Resource
+}
+
+func newNodeState(node *v1.Node) *NodeState {
+	return &NodeState{
+		Node:        node,
+		Allocatable: framework.NewResource(node.Status.Allocatable),
+		Requested:   &framework.Resource{},
+	}
+}
+
+func (n *NodeState) Name() string {
+	return n.Node.Name
+}
+
+// Free returns the resources which are not requested by any pod on the node.
+func (n *NodeState) Free() *framework.Resource {
+	free := n.Allocatable.Clone()
+	free.SubResource(n.Requested)
+	return free
+}
+
+func (n *NodeState) addPod(pod *v1.Pod) {
+	n.Pods = append(n.Pods, pod)
+	res, _, _ := framework.CalculateResource(pod)
+	n.Requested.AddResource(&res)
+	n.Requested.AllowedPodNumber++
+}
+
+func (n *NodeState) removePod(pod *v1.Pod) {
+	for i := range n.Pods {
+		if n.Pods[i].UID == pod.UID {
+			n.Pods = append(n.Pods[:i], n.Pods[i+1:]...)
+			res, _, _ := framework.CalculateResource(pod)
+			n.Requested.SubResource(&res)
+			n.Requested.AllowedPodNumber--
+			return
+		}
+	}
+}
+
+// Snapshot is a point-in-time view of the cluster shared by all algorithms in a rescheduling round.
+// Decisions assumed by the former algorithms will be reflected in the snapshot.
+type Snapshot struct {
+	nodes    map[string]*NodeState
+	nodeList []*NodeState
+
+	movable    func(*v1.Pod) bool
+	disruption *disruptionTracker
+	moved      sets.String
+	// limit is the number of pods the running algorithm could still move.
+	limit int
+}
+
+// NewSnapshot builds a snapshot. Only the bound and non-terminated pods are taken into account,
+// and movable decides whether a pod is allowed to be moved by the rescheduler.
+func NewSnapshot(nodes []*v1.Node, pods []*v1.Pod, cnrs []*katalystv1alpha1.CustomNodeResource,
+	pdbs []*policy.PodDisruptionBudget, movable func(*v1.Pod) bool,
+) *Snapshot {
+	s := &Snapshot{
+		nodes:      make(map[string]*NodeState, len(nodes)),
+		nodeList:   make([]*NodeState, 0, len(nodes)),
+		movable:    movable,
+		disruption: newDisruptionTracker(pdbs),
+		moved:      sets.NewString(),
+	}
+	for _, node := range nodes {
+		state := newNodeState(node)
+		s.nodes[node.Name] = state
+		s.nodeList = append(s.nodeList, state)
+	}
+	sort.Slice(s.nodeList, func(i, j int) bool {
+		return s.nodeList[i].Name() < s.nodeList[j].Name()
+	})
+	for _, cnr := range cnrs {
+		if state, ok := s.nodes[cnr.Name]; ok {
+			state.CNR = cnr
+		}
+	}
+	for _, pod := range pods {
+		if !podutil.BoundPod(pod) || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if state, ok := s.nodes[pod.Spec.NodeName]; ok {
+			state.addPod(pod)
+		}
+	}
+	return s
+}
+
+// Nodes returns all the nodes sorted by name.
+func (s *Snapshot) Nodes() []*NodeState {
+	return s.nodeList
+}
+
+func (s *Snapshot) GetNode(nodeName string) *NodeState {
+	return s.nodes[nodeName]
+}
+
+func (s *Snapshot) setLimit(limit int) {
+	s.limit = limit
+}
+
+// CanMove checks whether all the given pods could be moved together, taking
+// the preemptibility, pod disruption budgets and the movement size limit into account.
+func (s *Snapshot) CanMove(pods ...*v1.Pod) bool {
+	if len(pods) > s.limit {
+		return false
+	}
+	for _, pod := range pods {
+		if s.moved.Has(string(pod.UID)) || !s.movable(pod) {
+			return false
+		}
+	}
+	return s.disruption.allows(pods)
+}
+
+// Fits checks whether the pod could be placed on the node. Only the basic predicates
+// (schedulability, taints, node selector, required node affinity and resources) are checked,
+// the scheduler will run the complete filtering before accepting the recommendations.
+func (s *Snapshot) Fits(pod *v1.Pod, node *NodeState) bool {
+	return s.fitsWithPending(pod, node, nil)
+}
+
+// fitsWithPending is the same as Fits, except that the pending resources, which are
+// planned to be placed on the node but not assumed yet, are also taken into account.
+func (s *Snapshot) fitsWithPending(pod *v1.Pod, node *NodeState, pending *framework.Resource) bool {
+	if node.Node.Spec.Unschedulable || pod.Spec.NodeName == node.Name() {
+		return false
+	}
+	if _, untolerated := helper.FindMatchingUntoleratedTaint(node.Node.Spec.Taints, pod.Spec.Tolerations, func(t *v1.Taint) bool {
+		return t.Effect == v1.TaintEffectNoSchedule || t.Effect == v1.TaintEffectNoExecute
+	}); untolerated {
+		return false
+	}
+	if len(pod.Spec.NodeSelector) > 0 && !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Node.Labels)) {
+		return false
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if !helper.MatchNodeSelectorTerms(terms, labels.Set(node.Node.Labels), fields.Set{"metadata.name": node.Name()}) {
+			return false
+		}
+	}
+	request, _, _ := framework.CalculateResource(pod)
+	free := node.Free()
+	free.SubResource(pending)
+	return fitsResource(free, &request)
+}
+
+// Assume records the decision that the pod will be moved to the target node.
+func (s *Snapshot) Assume(pod *v1.Pod, targetNode string) {
+	if source, ok := s.nodes[pod.Spec.NodeName]; ok {
+		source.removePod(pod)
+	}
+	if target, ok := s.nodes[targetNode]; ok {
+		target.addPod(pod)
+	}
+	s.moved.Insert(string(pod.UID))
+	s.disruption.consume(pod)
+	s.limit--
+}
+
+// Forget rolls back a decision assumed before, it is used when the decision could not be carried out.
+func (s *Snapshot) Forget(pod *v1.Pod, targetNode string) {
+	if !s.moved.Has(string(pod.UID)) {
+		return
+	}
+	if target, ok := s.nodes[targetNode]; ok {
+		target.removePod(pod)
+	}
+	if source, ok := s.nodes[pod.Spec.NodeName]; ok {
+		source.addPod(pod)
+	}
+	s.moved.Delete(string(pod.UID))
+	s.disruption.release(pod)
+	s.limit++
+}
+
+// fitsResource checks whether the free resources satisfy the request.
+// Unlike Resource.Satisfy, scalar resources missing from free are regarded as zero.
+func fitsResource(free, request *framework.Resource) bool {
+	if free.AllowedPodNumber < 1 {
+		return false
+	}
+	if request.MilliCPU > free.MilliCPU || request.Memory > free.Memory || request.EphemeralStorage > free.EphemeralStorage {
+		return false
+	}
+	for rName, rQuant := range request.ScalarResources {
+		if rQuant > 0 && rQuant > free.ScalarResources[rName] {
+			return false
+		}
+	}
+	return true
+}
+
+// ----------------------------------- disruptionTracker -----------------------------------
+
+type pdbBudget struct {
+	namespace string
+	selector  labels.Selector
+	allowed   int32
+}
+
+// disruptionTracker makes sure the decisions in a round never exceed the disruptions allowed by PDBs.
+type disruptionTracker struct {
+	budgets []*pdbBudget
+}
+
+func newDisruptionTracker(pdbs []*policy.PodDisruptionBudget) *disruptionTracker {
+	t := &disruptionTracker{}
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			klog.InfoS("Failed to get selector from pdb.Spec", "pdb", klog.KObj(pdb), "err", err)
+			continue
+		}
+		t.budgets = append(t.budgets, &pdbBudget{
+			namespace: pdb.Namespace,
+			selector:  selector,
+			allowed:   pdb.Status.DisruptionsAllowed,
+		})
+	}
+	return t
+}
+
+func (b *pdbBudget) matches(pod *v1.Pod) bool {
+	return b.namespace == pod.Namespace && b.selector.Matches(labels.Set(pod.Labels))
+}
+
+func (t *disruptionTracker) allows(pods []*v1.Pod) bool {
+	for _, budget := range t.budgets {
+		var count int32
+		for _, pod := range pods {
+			if budget.matches(pod) {
+				count++
+			}
+		}
+		if count > budget.allowed {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *disruptionTracker) consume(pod *v1.Pod) {
+	for _, budget := range t.budgets {
+		if budget.matches(pod) {
+			budget.allowed--
+		}
+	}
+}
+
+func (t *disruptionTracker) release(pod *v1.Pod) {
+	for _, budget := range t.budgets {
+		if budget.matches(pod) {
+			budget.allowed++
+		}
+	}
+}
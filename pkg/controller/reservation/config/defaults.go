@@ -20,6 +20,7 @@ const (
 	PodReservationRequestDefaultTimeOutSeconds = 60
 	ReservationDefaultCheckPeriod              = 1
 	DefaultMatchedRequestExtraTTL              = 60
+	DefaultPlaceholderImage                    = "registry.k8s.io/pause:3.9"
 )
 
 var DefaultIgnoredNamespace = []string{"batch"}
@@ -37,6 +38,10 @@ func SetDefaultReservationController(obj *ReservationControllerConfiguration) {
 		obj.MatchedRequestExtraTTL = DefaultMatchedRequestExtraTTL
 	}
 
+	if len(obj.PlaceholderImage) == 0 {
+		obj.PlaceholderImage = DefaultPlaceholderImage
+	}
+
 	if len(obj.IgnoredNamespace) == 0 {
 		obj.IgnoredNamespace = DefaultIgnoredNamespace
 	}
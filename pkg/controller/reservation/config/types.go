@@ -23,6 +23,8 @@ type ReservationControllerConfiguration struct {
 	// IgnoredNamespace is the list of namespace to be ignored when
 	// setup informer.
 	IgnoredNamespace []string
+	// PlaceholderImage is the image used by placeholder pods of proactive reservations.
+	PlaceholderImage string
 }
 
 func NewReservationControllerConfiguration() *ReservationControllerConfiguration {
@@ -33,6 +35,7 @@ func (c *ReservationControllerConfiguration) DeepCopyInto(in *ReservationControl
 	c.ReservationTTL = in.ReservationTTL
 	c.MatchedRequestExtraTTL = in.MatchedRequestExtraTTL
 	c.ReservationCheckPeriod = in.ReservationCheckPeriod
+	c.PlaceholderImage = in.PlaceholderImage
 
 	if in.IgnoredNamespace != nil {
 		c.IgnoredNamespace = make([]string, len(in.IgnoredNamespace))
@@ -45,6 +48,7 @@ func (c *ReservationControllerConfiguration) DeepCopy() (out *ReservationControl
 	out.ReservationTTL = c.ReservationTTL
 	out.MatchedRequestExtraTTL = c.MatchedRequestExtraTTL
 	out.ReservationCheckPeriod = c.ReservationCheckPeriod
+	out.PlaceholderImage = c.PlaceholderImage
 	if c.IgnoredNamespace != nil {
 		out.IgnoredNamespace = make([]string, len(c.IgnoredNamespace))
 		copy(out.IgnoredNamespace, c.IgnoredNamespace)
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appslister "k8s.io/client-go/listers/apps/v1"
+	batchlister "k8s.io/client-go/listers/batch/v1"
+
+	schedulinglister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// OwnerResolver resolves the owner of pods, so that the reservation requirement (and ttl) could be set on the
+// owner instead of each pod. Out-of-tree workloads could be supported by passing more resolvers to the controller.
+type OwnerResolver interface {
+	// Name returns the name of the resolver.
+	Name() string
+	// Resolve returns the owner of the pod, nil if the pod isn't owned by the kind of workloads handled by the resolver.
+	Resolve(pod *v1.Pod) metav1.Object
+}
+
+// NewInTreeOwnerResolvers returns the resolvers of Deployment, StatefulSet, Job and PodGroup owners.
+func NewInTreeOwnerResolvers(
+	deployLister appslister.DeploymentLister,
+	statefulSetLister appslister.StatefulSetLister,
+	jobLister batchlister.JobLister,
+	podGroupLister schedulinglister.PodGroupLister,
+) []OwnerResolver {
+	return []OwnerResolver{
+		NewDeploymentResolver(deployLister),
+		NewStatefulSetResolver(statefulSetLister),
+		NewJobResolver(jobLister),
+		NewPodGroupResolver(podGroupLister),
+	}
+}
+
+// ownerHasReservationRequirement checks the reservation requirement on the owner, and returns the ttl set on the owner.
+func ownerHasReservationRequirement(owner metav1.Object) (bool, int64) {
+	annotations := owner.GetAnnotations()
+	if annotations[podutil.PodResourceReservationAnnotationForGodel] != podutil.PodHasReservationRequirement {
+		return false, 0
+	}
+	ttl, err := strconv.ParseInt(annotations[podutil.ReservationTTLKey], 10, 64)
+	if err != nil {
+		return true, 0
+	}
+	return true, ttl
+}
+
+type deploymentResolver struct {
+	lister appslister.DeploymentLister
+}
+
+func NewDeploymentResolver(lister appslister.DeploymentLister) OwnerResolver {
+	return &deploymentResolver{lister: lister}
+}
+
+func (r *deploymentResolver) Name() string {
+	return "Deployment"
+}
+
+func (r *deploymentResolver) Resolve(pod *v1.Pod) metav1.Object {
+	deployName := util.GetDeployNameFromPod(pod)
+	if len(deployName) == 0 {
+		return nil
+	}
+	deploy, err := r.lister.Deployments(pod.Namespace).Get(deployName)
+	if err != nil {
+		return nil
+	}
+	return deploy
+}
+
+type statefulSetResolver struct {
+	lister appslister.StatefulSetLister
+}
+
+func NewStatefulSetResolver(lister appslister.StatefulSetLister) OwnerResolver {
+	return &statefulSetResolver{lister: lister}
+}
+
+func (r *statefulSetResolver) Name() string {
+	return podutil.StatefulSetKind
+}
+
+func (r *statefulSetResolver) Resolve(pod *v1.Pod) metav1.Object {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != podutil.StatefulSetKind {
+		return nil
+	}
+	sts, err := r.lister.StatefulSets(pod.Namespace).Get(owner.Name)
+	if err != nil || sts.UID != owner.UID {
+		return nil
+	}
+	return sts
+}
+
+type jobResolver struct {
+	lister batchlister.JobLister
+}
+
+func NewJobResolver(lister batchlister.JobLister) OwnerResolver {
+	return &jobResolver{lister: lister}
+}
+
+func (r *jobResolver) Name() string {
+	return podutil.JobKind
+}
+
+func (r *jobResolver) Resolve(pod *v1.Pod) metav1.Object {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != podutil.JobKind {
+		return nil
+	}
+	job, err := r.lister.Jobs(pod.Namespace).Get(owner.Name)
+	if err != nil || job.UID != owner.UID {
+		return nil
+	}
+	return job
+}
+
+type podGroupResolver struct {
+	lister schedulinglister.PodGroupLister
+}
+
+func NewPodGroupResolver(lister schedulinglister.PodGroupLister) OwnerResolver {
+	return &podGroupResolver{lister: lister}
+}
+
+func (r *podGroupResolver) Name() string {
+	return podutil.PodGroupKind
+}
+
+func (r *podGroupResolver) Resolve(pod *v1.Pod) metav1.Object {
+	pgName := podutil.GetPodGroupName(pod)
+	if len(pgName) == 0 {
+		return nil
+	}
+	pg, err := r.lister.PodGroups(pod.Namespace).Get(pgName)
+	if err != nil {
+		return nil
+	}
+	return pg
+}
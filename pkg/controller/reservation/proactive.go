@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	reservationmetrics "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/metrics"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// placeholderDeletionTimeout is how long a deleted placeholder pod is waited for its Reservation
+// before the placeholder is regarded as lost and created again.
+const placeholderDeletionTimeout = time.Minute
+
+// Proactive reservations are created by users before pods exist, from a pod template (`spec.template`) and a
+// replica count (annotation `godel.bytedance.com/reservation-replicas`). For each replica, a placeholder pod is
+// created and scheduled through the normal scheduling framework. Once a placeholder pod is bound, it is deleted
+// and the existing reservation-on-deletion process takes over: the scheduler and binder keep its resources
+// reserved, and a Reservation bound to the node is created and owned by the proactive reservation.
+//
+// Phases of a proactive reservation:
+// - Pending: placeholder pods are being scheduled.
+// - Reserved: resources of all replicas are reserved.
+// - Matched: all reserved resources are consumed or recycled, the reservation will be deleted.
+
+func (rc *ReservationController) syncProactiveReservations(ctx context.Context, reservations []*schedulingv1a1.Reservation) {
+	for key, deletedAt := range rc.deletedPlaceholders {
+		if time.Since(deletedAt) >= placeholderDeletionTimeout {
+			klog.InfoS("Reservation of deleted placeholder pod was not observed in time", "pod", key)
+			delete(rc.deletedPlaceholders, key)
+		}
+	}
+	for _, res := range reservations {
+		if !podutil.IsProactiveReservation(res) || rc.isReservationTimeout(res) {
+			continue
+		}
+		if err := rc.syncProactiveReservation(ctx, res); err != nil {
+			klog.ErrorS(err, "Failed to sync proactive reservation", "reservation", klog.KObj(res))
+		}
+	}
+}
+
+func (rc *ReservationController) syncProactiveReservation(ctx context.Context, res *schedulingv1a1.Reservation) error {
+	replicas := podutil.GetReservationReplicas(res)
+
+	switch res.Status.Phase {
+	case schedulingv1a1.ReservationMatched, schedulingv1a1.ReservationTimeOut:
+		return nil
+	case schedulingv1a1.ResourceReserved:
+		for i := 0; i < replicas; i++ {
+			if rc.getPlacedReservation(res, i) != nil {
+				return nil
+			}
+		}
+		// all reserved resources are consumed or recycled.
+		return rc.updateReservationPhase(ctx, res, schedulingv1a1.ReservationMatched)
+	}
+
+	placed := 0
+	for i := 0; i < replicas; i++ {
+		if rc.getPlacedReservation(res, i) != nil {
+			delete(rc.deletedPlaceholders, placeholderPodKey(res, i))
+			placed++
+			continue
+		}
+		if err := rc.syncPlaceholderPod(ctx, res, i); err != nil {
+			return err
+		}
+	}
+
+	if placed == replicas {
+		return rc.updateReservationPhase(ctx, res, schedulingv1a1.ResourceReserved)
+	}
+	if res.Status.Phase != schedulingv1a1.PendingForReserve {
+		return rc.updateReservationPhase(ctx, res, schedulingv1a1.PendingForReserve)
+	}
+	return nil
+}
+
+// syncPlaceholderPod creates the placeholder pod of the given replica if it doesn't exist,
+// and deletes it once it is bound so that its resources are reserved.
+func (rc *ReservationController) syncPlaceholderPod(ctx context.Context, res *schedulingv1a1.Reservation, index int) error {
+	name, key := placeholderPodName(res, index), placeholderPodKey(res, index)
+	pod, err := rc.podLister.Pods(res.Namespace).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The placeholder was deleted once bound, its Reservation will be created on the deletion event.
+		// Don't place the replica again before the Reservation is observed, otherwise it is reserved twice.
+		if _, ok := rc.deletedPlaceholders[key]; ok {
+			klog.V(4).InfoS("Waiting for the reservation of deleted placeholder pod", "reservation", klog.KObj(res), "pod", key)
+			return nil
+		}
+		pod = newPlaceholderPod(res, index, rc.reservationTTL, rc.placeholderImage)
+		if _, err := rc.kubeClient.CoreV1().Pods(res.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// The pod lister is stale, the existing pod will be handled once it is observed.
+				klog.V(4).InfoS("Placeholder pod already exists", "reservation", klog.KObj(res), "pod", key)
+				return nil
+			}
+			reservationmetrics.IncreaseReservationAPICall("create_placeholder", reservationmetrics.FailureResult)
+			return err
+		}
+		reservationmetrics.IncreaseReservationAPICall("create_placeholder", reservationmetrics.SuccessResult)
+		klog.V(4).InfoS("Succeed to create placeholder pod", "reservation", klog.KObj(res), "pod", klog.KObj(pod))
+		return nil
+	}
+
+	if !metav1.IsControlledBy(pod, res) {
+		return fmt.Errorf("pod %s/%s already exists and is not controlled by reservation", pod.Namespace, pod.Name)
+	}
+	if !podutil.BoundPod(pod) {
+		return nil
+	}
+	if pod.DeletionTimestamp != nil {
+		if _, ok := rc.deletedPlaceholders[key]; !ok {
+			rc.deletedPlaceholders[key] = time.Now()
+		}
+		return nil
+	}
+
+	if err := rc.kubeClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		reservationmetrics.IncreaseReservationAPICall("delete_placeholder", reservationmetrics.FailureResult)
+		return err
+	}
+	rc.deletedPlaceholders[key] = time.Now()
+	reservationmetrics.IncreaseReservationAPICall("delete_placeholder", reservationmetrics.SuccessResult)
+	klog.V(4).InfoS("Succeed to delete bound placeholder pod", "reservation", klog.KObj(res), "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+	return nil
+}
+
+// getPlacedReservation returns the reservation created for the placeholder pod of the given replica.
+func (rc *ReservationController) getPlacedReservation(res *schedulingv1a1.Reservation, index int) *schedulingv1a1.Reservation {
+	placed, err := rc.podReservationLister.Reservations(res.Namespace).Get(placeholderPodName(res, index))
+	if err != nil || !metav1.IsControlledBy(placed, res) {
+		return nil
+	}
+	return placed
+}
+
+// isPlacingPlaceholders checks whether the reservation is created for a placeholder pod of a proactive reservation
+// which is still placing its placeholder pods. Such reservation should be kept even if it is matched, otherwise the
+// placeholder pod will be created again.
+func (rc *ReservationController) isPlacingPlaceholders(res *schedulingv1a1.Reservation) bool {
+	owner := metav1.GetControllerOf(res)
+	if owner == nil || owner.Kind != podutil.ReservationKind {
+		return false
+	}
+	parent, err := rc.podReservationLister.Reservations(res.Namespace).Get(owner.Name)
+	if err != nil || parent.UID != owner.UID {
+		return false
+	}
+	return parent.Status.Phase != schedulingv1a1.ResourceReserved && !rc.isReservationTimeout(parent)
+}
+
+func (rc *ReservationController) updateReservationPhase(ctx context.Context, res *schedulingv1a1.Reservation, phase schedulingv1a1.ReservationPhase) error {
+	resCopy := res.DeepCopy()
+	resCopy.Status.Phase = phase
+	if _, err := rc.godelClient.SchedulingV1alpha1().Reservations(res.Namespace).UpdateStatus(ctx, resCopy, metav1.UpdateOptions{}); err != nil {
+		reservationmetrics.IncreaseReservationAPICall("update_status", reservationmetrics.FailureResult)
+		return err
+	}
+	reservationmetrics.IncreaseReservationAPICall("update_status", reservationmetrics.SuccessResult)
+	klog.V(4).InfoS("Succeed to update proactive reservation phase", "reservation", klog.KObj(res), "phase", phase)
+	return nil
+}
+
+// placeholderPodName returns the deterministic name of the placeholder pod of the given replica.
+func placeholderPodName(res *schedulingv1a1.Reservation, index int) string {
+	return fmt.Sprintf("%s-%d", res.Name, index)
+}
+
+func placeholderPodKey(res *schedulingv1a1.Reservation, index int) string {
+	return res.Namespace + "/" + placeholderPodName(res, index)
+}
+
+// newPlaceholderPod builds the placeholder pod of the given replica according to the reservation template.
+// Containers of the placeholder pod keep the resource requirements only and run the placeholder image.
+func newPlaceholderPod(res *schedulingv1a1.Reservation, index int, defaultTTL int64, image string) *v1.Pod {
+	templateSpec := res.Spec.Template.Spec
+
+	ttl := defaultTTL
+	if res.Spec.TimeToLive != nil {
+		ttl = *res.Spec.TimeToLive
+	}
+	placeholder := podutil.GetPlaceholderFromReservation(res)
+	if len(placeholder) == 0 {
+		placeholder = res.Name
+	}
+
+	annotations := make(map[string]string, len(res.Annotations)+5)
+	for k, v := range res.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, podutil.ReservationReplicasAnno)
+	annotations[podutil.PodResourceReservationAnnotationForGodel] = podutil.PodHasReservationRequirement
+	annotations[podutil.ReservationIndexAnnotation] = placeholder
+	annotations[podutil.ReservationTTLKey] = strconv.FormatInt(ttl, 10)
+	if len(annotations[podutil.PodLauncherAnnotationKey]) == 0 {
+		annotations[podutil.PodLauncherAnnotationKey] = string(podutil.Kubelet)
+	}
+	if len(annotations[podutil.PodResourceTypeAnnotationKey]) == 0 {
+		annotations[podutil.PodResourceTypeAnnotationKey] = string(podutil.GuaranteedPod)
+	}
+
+	var gracePeriod int64
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        placeholderPodName(res, index),
+			Namespace:   res.Namespace,
+			Labels:      res.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(res, schedulingv1a1.SchemeGroupVersion.WithKind(podutil.ReservationKind)),
+			},
+		},
+		Spec: v1.PodSpec{
+			SchedulerName:                 templateSpec.SchedulerName,
+			PriorityClassName:             templateSpec.PriorityClassName,
+			NodeSelector:                  templateSpec.NodeSelector,
+			HostNetwork:                   templateSpec.HostNetwork,
+			Affinity:                      templateSpec.Affinity,
+			TerminationGracePeriodSeconds: &gracePeriod,
+		},
+	}
+	// priority will be resolved from the priority class by admission if the class is specified.
+	if len(templateSpec.PriorityClassName) == 0 && templateSpec.Priority != nil {
+		priority := *templateSpec.Priority
+		pod.Spec.Priority = &priority
+	}
+	for _, t := range templateSpec.Tolerations {
+		if t != nil {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, *t)
+		}
+	}
+	for _, c := range templateSpec.Containers {
+		if c == nil {
+			continue
+		}
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{
+			Name:      c.Name,
+			Image:     image,
+			Resources: *c.Resources.DeepCopy(),
+		})
+	}
+	return pod
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	"github.com/kubewharf/godel-scheduler/pkg/util/controller"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeProactiveReservation(name string, replicas string, phase schedulingv1a1.ReservationPhase) *schedulingv1a1.Reservation {
+	ttl := int64(3600)
+	priority := int32(100)
+	return &schedulingv1a1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         testNS,
+			Name:              name,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.Now(),
+			Annotations: map[string]string{
+				podutil.ReservationReplicasAnno:    replicas,
+				podutil.ReservationIndexAnnotation: "launch",
+			},
+		},
+		Spec: schedulingv1a1.ReservationSpec{
+			Template: schedulingv1a1.Template{
+				Spec: schedulingv1a1.TemplateSpec{
+					SchedulerName: "godel-scheduler",
+					Priority:      &priority,
+					NodeSelector:  map[string]string{"pool": "launch"},
+					Tolerations:   []*v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpExists}},
+					Containers: []*v1.Container{{
+						Name:  "app",
+						Image: "app:v1",
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+						},
+					}},
+				},
+			},
+			TimeToLive: &ttl,
+		},
+		Status: schedulingv1a1.ReservationStatus{Phase: phase},
+	}
+}
+
+func makePlacedReservation(parent *schedulingv1a1.Reservation, index int) *schedulingv1a1.Reservation {
+	pod := newPlaceholderPod(parent, index, ttl, "pause")
+	pod.Spec.NodeName = "n1"
+	res := makeReservationCrd(pod)
+	res.OwnerReferences = pod.OwnerReferences
+	res.CreationTimestamp = metav1.Now()
+	return res
+}
+
+func TestNewPlaceholderPod(t *testing.T) {
+	res := makeProactiveReservation("res", "2", "")
+	pod := newPlaceholderPod(res, 1, ttl, "pause")
+
+	if pod.Name != "res-1" || pod.Namespace != testNS {
+		t.Errorf("unexpected pod key: %s/%s", pod.Namespace, pod.Name)
+	}
+	if !metav1.IsControlledBy(pod, res) || !podutil.IsProactiveReservationPlaceholderPod(pod) {
+		t.Errorf("expected pod to be controlled by reservation, got: %v", pod.OwnerReferences)
+	}
+	expectedAnnotations := map[string]string{
+		podutil.ReservationIndexAnnotation:               "launch",
+		podutil.PodResourceReservationAnnotationForGodel: podutil.PodHasReservationRequirement,
+		podutil.ReservationTTLKey:                        "3600",
+		podutil.PodLauncherAnnotationKey:                 string(podutil.Kubelet),
+		podutil.PodResourceTypeAnnotationKey:             string(podutil.GuaranteedPod),
+	}
+	if !reflect.DeepEqual(expectedAnnotations, pod.Annotations) {
+		t.Errorf("expected annotations: %v, got: %v", expectedAnnotations, pod.Annotations)
+	}
+	if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Image != "pause" ||
+		!pod.Spec.Containers[0].Resources.Requests.Cpu().Equal(resource.MustParse("2")) {
+		t.Errorf("unexpected containers: %v", pod.Spec.Containers)
+	}
+	if pod.Spec.Priority == nil || *pod.Spec.Priority != 100 || pod.Spec.NodeSelector["pool"] != "launch" ||
+		len(pod.Spec.Tolerations) != 1 || pod.Spec.SchedulerName != "godel-scheduler" {
+		t.Errorf("unexpected pod spec: %#v", pod.Spec)
+	}
+}
+
+func TestSyncProactiveReservation(t *testing.T) {
+	res := makeProactiveReservation("res", "2", "")
+
+	boundPod := newPlaceholderPod(res, 0, ttl, "pause")
+	boundPod.Spec.NodeName = "n1"
+	pendingPod := newPlaceholderPod(res, 1, ttl, "pause")
+
+	tests := []struct {
+		name          string
+		reservation   *schedulingv1a1.Reservation
+		pods          []*v1.Pod
+		placed        []*schedulingv1a1.Reservation
+		deleted       []string
+		expectedPods  []string
+		expectedPhase schedulingv1a1.ReservationPhase
+	}{
+		{
+			name:          "placeholder pods are created for new reservation",
+			reservation:   res,
+			expectedPods:  []string{"res-0", "res-1"},
+			expectedPhase: schedulingv1a1.PendingForReserve,
+		},
+		{
+			name:          "bound placeholder pods are deleted to reserve resources",
+			reservation:   res,
+			pods:          []*v1.Pod{boundPod, pendingPod},
+			expectedPods:  []string{"res-1"},
+			expectedPhase: schedulingv1a1.PendingForReserve,
+		},
+		{
+			name:          "deleted placeholder pods are not created again before their reservations are observed",
+			reservation:   res,
+			pods:          []*v1.Pod{pendingPod},
+			deleted:       []string{testNS + "/res-0"},
+			expectedPods:  []string{"res-1"},
+			expectedPhase: schedulingv1a1.PendingForReserve,
+		},
+		{
+			name:          "reservation is reserved once all placeholders are placed",
+			reservation:   res,
+			placed:        []*schedulingv1a1.Reservation{makePlacedReservation(res, 0), makePlacedReservation(res, 1)},
+			expectedPods:  []string{},
+			expectedPhase: schedulingv1a1.ResourceReserved,
+		},
+		{
+			name:          "reserved reservation is matched once all placed reservations are recycled",
+			reservation:   makeProactiveReservation("res", "2", schedulingv1a1.ResourceReserved),
+			expectedPods:  []string{},
+			expectedPhase: schedulingv1a1.ReservationMatched,
+		},
+		{
+			name:          "reserved reservation is kept while placed reservations exist",
+			reservation:   makeProactiveReservation("res", "2", schedulingv1a1.ResourceReserved),
+			placed:        []*schedulingv1a1.Reservation{makePlacedReservation(res, 1)},
+			expectedPods:  []string{},
+			expectedPhase: schedulingv1a1.ResourceReserved,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeObjects := make([]runtime.Object, 0, len(tt.pods))
+			for _, pod := range tt.pods {
+				kubeObjects = append(kubeObjects, pod.DeepCopy())
+			}
+			godelObjects := []runtime.Object{tt.reservation.DeepCopy()}
+			for _, placed := range tt.placed {
+				godelObjects = append(godelObjects, placed)
+			}
+			kubeClient := fake.NewSimpleClientset(kubeObjects...)
+			godelClient := godelfake.NewSimpleClientset(godelObjects...)
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+			godelInformerFactory := crdinformers.NewSharedInformerFactory(godelClient, controller.NoResyncPeriodFunc())
+			podInformer := informerFactory.Core().V1().Pods()
+			reservationInformer := godelInformerFactory.Scheduling().V1alpha1().Reservations()
+
+			rc := NewReservationController(context.TODO(), godelClient, kubeClient, podInformer,
+				reservationInformer, 1, ttl, 60, "pause", nil)
+			for _, obj := range kubeObjects {
+				podInformer.Informer().GetIndexer().Add(obj)
+			}
+			for _, obj := range godelObjects {
+				reservationInformer.Informer().GetIndexer().Add(obj)
+			}
+			for _, key := range tt.deleted {
+				rc.deletedPlaceholders[key] = time.Now()
+			}
+
+			rc.handleReservation(context.TODO())
+
+			pods, err := kubeClient.CoreV1().Pods(testNS).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotPods := make([]string, 0, len(pods.Items))
+			for _, pod := range pods.Items {
+				gotPods = append(gotPods, pod.Name)
+			}
+			sort.Strings(gotPods)
+			if !reflect.DeepEqual(tt.expectedPods, gotPods) {
+				t.Errorf("expected pods: %v, got: %v", tt.expectedPods, gotPods)
+			}
+
+			got, err := godelClient.SchedulingV1alpha1().Reservations(testNS).Get(context.TODO(), tt.reservation.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Phase != tt.expectedPhase {
+				t.Errorf("expected phase: %v, got: %v", tt.expectedPhase, got.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestGCPlacedReservations(t *testing.T) {
+	tests := []struct {
+		name         string
+		parentPhase  schedulingv1a1.ReservationPhase
+		expectedKept bool
+	}{
+		{
+			name:         "matched placed reservation is kept while parent is placing placeholders",
+			parentPhase:  schedulingv1a1.PendingForReserve,
+			expectedKept: true,
+		},
+		{
+			name:         "matched placed reservation is recycled once parent is reserved",
+			parentPhase:  schedulingv1a1.ResourceReserved,
+			expectedKept: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := makeProactiveReservation("res", "2", tt.parentPhase)
+			placed := makePlacedReservation(parent, 0)
+			placed.Status.Phase = schedulingv1a1.ReservationMatched
+			placed.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+
+			kubeClient := fake.NewSimpleClientset()
+			godelClient := godelfake.NewSimpleClientset(placed)
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+			godelInformerFactory := crdinformers.NewSharedInformerFactory(godelClient, controller.NoResyncPeriodFunc())
+			reservationInformer := godelInformerFactory.Scheduling().V1alpha1().Reservations()
+
+			rc := NewReservationController(context.TODO(), godelClient, kubeClient, informerFactory.Core().V1().Pods(),
+				reservationInformer, 1, ttl, 60, "pause", nil)
+			reservationInformer.Informer().GetIndexer().Add(parent)
+			reservationInformer.Informer().GetIndexer().Add(placed)
+
+			rc.gcReservations(context.TODO(), []*schedulingv1a1.Reservation{placed})
+			got, _ := godelClient.SchedulingV1alpha1().Reservations(testNS).Get(context.TODO(), placed.Name, metav1.GetOptions{})
+			if kept := got != nil && got.Name != ""; kept != tt.expectedKept {
+				t.Errorf("expected kept: %v, got: %v", tt.expectedKept, kept)
+			}
+		})
+	}
+}
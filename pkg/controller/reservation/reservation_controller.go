@@ -20,13 +20,14 @@ import (
 	"context"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	appsinformers "k8s.io/client-go/informers/apps/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
-	appslister "k8s.io/client-go/listers/apps/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelister "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -39,7 +40,6 @@ import (
 	reservationmetrics "github.com/kubewharf/godel-scheduler/pkg/controller/reservation/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/controller/reservation/utils"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
-	deployutil "github.com/kubewharf/godel-scheduler/pkg/util/deployment"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
@@ -50,14 +50,24 @@ const (
 
 type ReservationController struct {
 	godelClient godelclient.Interface
+	kubeClient  clientset.Interface
 	// eventRecorder   record.EventRecorder
 	podReservationLister       reservationlister.ReservationLister
 	podReservationListerSynced cache.InformerSynced
 	podReservationQueue        workqueue.DelayingInterface
-	deploymentLister           appslister.DeploymentLister
-	reservationCheckPeriod     int64
-	reservationTTL             int64
-	matchedPodExtraTTL         int64
+	// ownerResolvers resolve the owners of pods, whose reservation requirement and ttl
+	// will be used if the pod itself doesn't require reservation.
+	ownerResolvers         []OwnerResolver
+	podLister              corelister.PodLister
+	podListerSynced        cache.InformerSynced
+	reservationCheckPeriod int64
+	reservationTTL         int64
+	matchedPodExtraTTL     int64
+	placeholderImage       string
+	// deletedPlaceholders records the bound placeholder pods deleted by the controller whose
+	// Reservations have not been observed yet, keyed by namespace/name. It is only accessed
+	// by handleReservation.
+	deletedPlaceholders map[string]time.Time
 	// TODO: deal with fault node, remove reservation CRD on the node.
 }
 
@@ -69,22 +79,29 @@ func init() {
 func NewReservationController(
 	ctx context.Context,
 	godelClient godelclient.Interface,
+	kubeClient clientset.Interface,
 	podInformer coreinformers.PodInformer,
-	deployInformer appsinformers.DeploymentInformer,
 	podReservationInformer reservationinformer.ReservationInformer,
 	reservationCheckPeriod int64,
 	reservationTTL int64,
 	matchedPodExtraTTL int64,
+	placeholderImage string,
+	ownerResolvers []OwnerResolver,
 ) *ReservationController {
 	rc := &ReservationController{
 		godelClient:                godelClient,
+		kubeClient:                 kubeClient,
 		podReservationLister:       podReservationInformer.Lister(),
 		podReservationListerSynced: podReservationInformer.Informer().HasSynced,
 		podReservationQueue:        workqueue.NewNamedDelayingQueue("pod_reservation_request"),
-		deploymentLister:           deployInformer.Lister(),
+		ownerResolvers:             ownerResolvers,
+		podLister:                  podInformer.Lister(),
+		podListerSynced:            podInformer.Informer().HasSynced,
 		reservationCheckPeriod:     reservationCheckPeriod,
 		reservationTTL:             reservationTTL,
 		matchedPodExtraTTL:         matchedPodExtraTTL,
+		placeholderImage:           placeholderImage,
+		deletedPlaceholders:        make(map[string]time.Time),
 	}
 
 	podInformer.Informer().AddEventHandler(
@@ -104,7 +121,7 @@ func (rc *ReservationController) Run(ctx context.Context, controllerManagerMetri
 	defer rc.podReservationQueue.ShutDown()
 	defer klog.V(3).InfoS("Shutting down Reservation Controller")
 
-	if !cache.WaitForNamedCacheSync("Reservation", ctx.Done(), rc.podReservationListerSynced) {
+	if !cache.WaitForNamedCacheSync("Reservation", ctx.Done(), rc.podReservationListerSynced, rc.podListerSynced) {
 		return
 	}
 
@@ -127,17 +144,12 @@ func (rc *ReservationController) createReservationCrdOnPodDeletion(obj interface
 		return
 	}
 	if !podutil.HasReservationRequirement(pod) {
-		deployName := util.GetDeployNameFromPod(pod)
-		if len(deployName) == 0 {
+		required, ownerTTL := rc.ownerHasReservationRequirement(pod)
+		if !required {
 			return
 		}
-
-		deploy, err := rc.deploymentLister.Deployments(pod.Namespace).Get(deployName)
-		if err != nil || !deployutil.DeployHasReservationRequirement(deploy) {
-			return
-		}
-		if t := deployutil.GetReservationTtlFromDeploy(deploy); t != 0 {
-			ttl = t
+		if ownerTTL != 0 {
+			ttl = ownerTTL
 		}
 	}
 
@@ -147,6 +159,10 @@ func (rc *ReservationController) createReservationCrdOnPodDeletion(obj interface
 		klog.ErrorS(err, "Failed to create reservation")
 		return
 	}
+	// reservations of placeholder pods are recycled along with the proactive reservation.
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == podutil.ReservationKind {
+		reservation.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
 
 	// call API to create CRD
 	result := reservationmetrics.SuccessResult
@@ -163,12 +179,29 @@ func (rc *ReservationController) createReservationCrdOnPodDeletion(obj interface
 	klog.V(4).InfoS("Succeed to create reservation", "reservation", klog.KObj(reservation), "index", podutil.GetReservationPlaceholder(pod))
 }
 
+// ownerHasReservationRequirement checks whether any owner of the pod requires reservation,
+// and returns the ttl set on the owner.
+func (rc *ReservationController) ownerHasReservationRequirement(pod *v1.Pod) (bool, int64) {
+	for _, resolver := range rc.ownerResolvers {
+		owner := resolver.Resolve(pod)
+		if owner == nil {
+			continue
+		}
+		if required, ttl := ownerHasReservationRequirement(owner); required {
+			klog.V(5).InfoS("Pod owner requires reservation", "pod", klog.KObj(pod), "ownerKind", resolver.Name(), "owner", owner.GetName())
+			return true, ttl
+		}
+	}
+	return false, 0
+}
+
 func (rc *ReservationController) handleReservation(ctx context.Context) {
 	reservations, err := rc.podReservationLister.List(labels.Everything())
 	if err != nil {
 		klog.ErrorS(err, "Error while listing all pod reservation requests")
 		return
 	}
+	rc.syncProactiveReservations(ctx, reservations)
 	rc.gcReservations(ctx, reservations)
 }
 
@@ -190,7 +223,7 @@ func (rc *ReservationController) gcReservations(
 	)
 
 	for _, prr := range reservations {
-		if rc.isReservationMatched(prr) {
+		if rc.isReservationMatched(prr) && !rc.isPlacingPlaceholders(prr) {
 			waitForGC = append(waitForGC, gcObject{
 				status: reservationmetrics.MatchStatus,
 				obj:    prr,
@@ -221,7 +254,11 @@ func (rc *ReservationController) gcReservations(
 
 func (rc *ReservationController) isReservationTimeout(prr *schedulingv1a1.Reservation) bool {
 	if prr.Status.Phase != schedulingv1a1.ReservationMatched {
-		timeoutDuration := time.Duration(*prr.Spec.TimeToLive) * time.Second
+		ttl := rc.reservationTTL
+		if prr.Spec.TimeToLive != nil {
+			ttl = *prr.Spec.TimeToLive
+		}
+		timeoutDuration := time.Duration(ttl) * time.Second
 		return time.Since(prr.CreationTimestamp.Time) > timeoutDuration
 	}
 	return false
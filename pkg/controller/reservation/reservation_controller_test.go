@@ -22,6 +22,8 @@ import (
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
@@ -97,12 +99,15 @@ func TestCreateReservationCrdOnPodDeletion(t *testing.T) {
 			rc := NewReservationController(
 				context.TODO(),
 				godelClient,
+				kubeClient,
 				podInformer,
-				deployInformer,
 				podReservationInformer,
 				1,
 				60,
 				60,
+				"pause",
+				NewInTreeOwnerResolvers(deployInformer.Lister(), informerFactory.Apps().V1().StatefulSets().Lister(),
+					informerFactory.Batch().V1().Jobs().Lister(), godelInformerFactory.Scheduling().V1alpha1().PodGroups().Lister()),
 			)
 			rc.createReservationCrdOnPodDeletion(tt.Pod)
 			crd, _ := godelClient.SchedulingV1alpha1().Reservations(testNS).Get(context.TODO(), tt.Pod.Name, metav1.GetOptions{})
@@ -149,12 +154,15 @@ func TestHandleReservation(t *testing.T) {
 			rc := NewReservationController(
 				context.TODO(),
 				godelClient,
+				kubeClient,
 				podInformer,
-				deployInformer,
 				podReservationInformer,
 				1,
 				60,
 				60,
+				"pause",
+				NewInTreeOwnerResolvers(deployInformer.Lister(), informerFactory.Apps().V1().StatefulSets().Lister(),
+					informerFactory.Batch().V1().Jobs().Lister(), godelInformerFactory.Scheduling().V1alpha1().PodGroups().Lister()),
 			)
 
 			podReservationInformer.Informer().GetIndexer().Add(tt.Crd)
@@ -172,3 +180,99 @@ func TestHandleReservation(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateReservationCrdForOwners(t *testing.T) {
+	ownerAnnotations := map[string]string{
+		podutil.PodResourceReservationAnnotationForGodel: podutil.PodHasReservationRequirement,
+		podutil.ReservationTTLKey:                        "600",
+	}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sts", UID: "sts", Annotations: ownerAnnotations}}
+	stsWithoutRequirement := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sts", UID: "sts"}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "job", UID: "job", Annotations: ownerAnnotations}}
+	pg := &schedulingv1a1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "pg", Annotations: ownerAnnotations}}
+
+	tests := []struct {
+		name             string
+		pod              *v1.Pod
+		owners           []interface{}
+		expectedIndex    string
+		expectedIdentity string
+	}{
+		{
+			name: "statefulset pod reserves resources for the same ordinal",
+			pod: testinghelper.MakePod().Namespace(testNS).Name("sts-2").UID("sts-2").Node("n1").
+				ControllerRef(*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))).Obj(),
+			owners:           []interface{}{sts},
+			expectedIndex:    "sts",
+			expectedIdentity: "2",
+		},
+		{
+			name: "statefulset without reservation requirement",
+			pod: testinghelper.MakePod().Namespace(testNS).Name("sts-2").UID("sts-2").Node("n1").
+				ControllerRef(*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))).Obj(),
+			owners: []interface{}{stsWithoutRequirement},
+		},
+		{
+			name: "indexed job pod reserves resources for the same completion index",
+			pod: testinghelper.MakePod().Namespace(testNS).Name("job-abcde").UID("job-abcde").Node("n1").
+				Annotation(podutil.JobCompletionIndexAnnotation, "1").
+				ControllerRef(*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))).Obj(),
+			owners:           []interface{}{job},
+			expectedIndex:    "job",
+			expectedIdentity: "1",
+		},
+		{
+			name: "pod group pod reserves resources for the pod group",
+			pod: testinghelper.MakePod().Namespace(testNS).Name("pg-0").UID("pg-0").Node("n1").
+				Annotation(podutil.PodGroupNameAnnotationKey, "pg").Obj(),
+			owners:        []interface{}{pg},
+			expectedIndex: "pg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			godelClient := godelfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+			godelInformerFactory := crdinformers.NewSharedInformerFactory(godelClient, controller.NoResyncPeriodFunc())
+			stsInformer := informerFactory.Apps().V1().StatefulSets()
+			jobInformer := informerFactory.Batch().V1().Jobs()
+			pgInformer := godelInformerFactory.Scheduling().V1alpha1().PodGroups()
+			for _, owner := range tt.owners {
+				switch o := owner.(type) {
+				case *appsv1.StatefulSet:
+					stsInformer.Informer().GetIndexer().Add(o)
+				case *batchv1.Job:
+					jobInformer.Informer().GetIndexer().Add(o)
+				case *schedulingv1a1.PodGroup:
+					pgInformer.Informer().GetIndexer().Add(o)
+				}
+			}
+
+			rc := NewReservationController(context.TODO(), godelClient, kubeClient,
+				informerFactory.Core().V1().Pods(), godelInformerFactory.Scheduling().V1alpha1().Reservations(), 1, 60, 60, "pause",
+				NewInTreeOwnerResolvers(informerFactory.Apps().V1().Deployments().Lister(), stsInformer.Lister(), jobInformer.Lister(), pgInformer.Lister()))
+			rc.createReservationCrdOnPodDeletion(tt.pod)
+
+			crd, err := godelClient.SchedulingV1alpha1().Reservations(testNS).Get(context.TODO(), tt.pod.Name, metav1.GetOptions{})
+			if len(tt.expectedIndex) == 0 {
+				if err == nil {
+					t.Errorf("expected no reservation, got: %#v", crd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := podutil.GetPlaceholderFromReservation(crd); got != tt.expectedIndex {
+				t.Errorf("expected index: %v, got: %v", tt.expectedIndex, got)
+			}
+			if got := crd.Annotations[podutil.ReservationIdentityAnno]; got != tt.expectedIdentity {
+				t.Errorf("expected identity: %v, got: %v", tt.expectedIdentity, got)
+			}
+			if *crd.Spec.TimeToLive != 600 {
+				t.Errorf("expected ttl from owner, got: %v", *crd.Spec.TimeToLive)
+			}
+		})
+	}
+}
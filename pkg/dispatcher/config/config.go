@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	componentbaseconfig "k8s.io/component-base/config/v1alpha1"
 
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
@@ -49,4 +50,44 @@ type GodelDispatcherConfiguration struct {
 
 	// Tracer defines the configuration of tracer
 	Tracer *tracing.TracerConfiguration `json:"tracer,omitempty" yaml:"tracer,omitempty"`
+
+	// Sharding defines the configuration of running multiple active dispatchers, each of which
+	// dispatches the pods of the shards it owns.
+	Sharding *ShardingConfiguration `json:"sharding,omitempty" yaml:"sharding,omitempty"`
+}
+
+// ShardBy is the way pods are hashed into shards.
+type ShardBy string
+
+const (
+	// ShardByNamespace hashes pods by their namespaces.
+	ShardByNamespace ShardBy = "Namespace"
+	// ShardByPodGroup hashes pods by their PodGroups, and pods not belonging to any PodGroup by their owners.
+	ShardByPodGroup ShardBy = "PodGroup"
+)
+
+// ShardingConfiguration configures the active-active dispatchers.
+type ShardingConfiguration struct {
+	// Enabled runs the dispatcher as one of the active instances instead of electing a leader.
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+	// ShardBy is the way pods are hashed into shards, either Namespace or PodGroup.
+	ShardBy ShardBy `json:"shardBy,omitempty" yaml:"shardBy,omitempty"`
+
+	// ShardCount is the number of shards distributed among the dispatchers. It must be the same
+	// for all the dispatchers, and should be much larger than the number of dispatchers.
+	ShardCount int32 `json:"shardCount,omitempty" yaml:"shardCount,omitempty"`
+
+	// LeaseNamespace is the namespace of the Leases coordinating the shard ownership.
+	LeaseNamespace string `json:"leaseNamespace,omitempty" yaml:"leaseNamespace,omitempty"`
+
+	// LeaseName is the prefix of the names of the Leases coordinating the shard ownership.
+	LeaseName string `json:"leaseName,omitempty" yaml:"leaseName,omitempty"`
+
+	// LeaseDuration is the duration after which the shards of a dispatcher that stops renewing
+	// its membership are taken over by the others.
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty" yaml:"leaseDuration,omitempty"`
+
+	// RenewPeriod is the interval between renewing the membership and rebalancing the shards.
+	RenewPeriod metav1.Duration `json:"renewPeriod,omitempty" yaml:"renewPeriod,omitempty"`
 }
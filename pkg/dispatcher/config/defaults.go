@@ -19,6 +19,7 @@ package config
 import (
 	"net"
 	"strconv"
+	"time"
 
 	defaultsconfig "github.com/kubewharf/godel-scheduler/pkg/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
@@ -32,6 +33,10 @@ const (
 	DefaultInsecureBinderPort          = 10351
 
 	DispatcherDefaultLockObjectName = "dispatcher"
+
+	DefaultShardCount         = 64
+	DefaultShardLeaseDuration = 15 * time.Second
+	DefaultShardRenewPeriod   = 2 * time.Second
 )
 
 func SetDefaults(cfg *GodelDispatcherConfiguration) {
@@ -108,6 +113,11 @@ func SetDefaults(cfg *GodelDispatcherConfiguration) {
 		cfg.LeaderElection.ResourceName = DispatcherDefaultLockObjectName
 	}
 
+	if cfg.Sharding == nil {
+		cfg.Sharding = &ShardingConfiguration{}
+	}
+	SetDefaultShardingConfiguration(cfg.Sharding, cfg.LeaderElection.ResourceNamespace)
+
 	// Enable profiling by default in the scheduler
 	if cfg.EnableProfiling == nil {
 		enableProfiling := true
@@ -120,3 +130,26 @@ func SetDefaults(cfg *GodelDispatcherConfiguration) {
 		cfg.EnableContentionProfiling = &enableContentionProfiling
 	}
 }
+
+// SetDefaultShardingConfiguration sets the defaults of the sharding configuration. The Leases are put
+// into the namespace of the leader election lock by default.
+func SetDefaultShardingConfiguration(cfg *ShardingConfiguration, leaseNamespace string) {
+	if len(cfg.ShardBy) == 0 {
+		cfg.ShardBy = ShardByNamespace
+	}
+	if cfg.ShardCount == 0 {
+		cfg.ShardCount = DefaultShardCount
+	}
+	if len(cfg.LeaseNamespace) == 0 {
+		cfg.LeaseNamespace = leaseNamespace
+	}
+	if len(cfg.LeaseName) == 0 {
+		cfg.LeaseName = DispatcherDefaultLockObjectName
+	}
+	if cfg.LeaseDuration.Duration == 0 {
+		cfg.LeaseDuration.Duration = DefaultShardLeaseDuration
+	}
+	if cfg.RenewPeriod.Duration == 0 {
+		cfg.RenewPeriod.Duration = DefaultShardRenewPeriod
+	}
+}
@@ -41,5 +41,37 @@ func ValidateGodelDispatcherConfiguration(cc *config.GodelDispatcherConfiguratio
 		errs = append(errs, field.Invalid(field.NewPath("metricsBindAddress"), cc.MetricsBindAddress, msg))
 	}
 
+	if cc.Sharding != nil {
+		errs = append(errs, ValidateShardingConfiguration(cc.Sharding, field.NewPath("sharding"))...)
+	}
+
+	return errs
+}
+
+// ValidateShardingConfiguration validates the sharding configuration if sharding is enabled.
+func ValidateShardingConfiguration(cc *config.ShardingConfiguration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if !cc.Enabled {
+		return errs
+	}
+	if cc.ShardBy != config.ShardByNamespace && cc.ShardBy != config.ShardByPodGroup {
+		errs = append(errs, field.NotSupported(fldPath.Child("shardBy"), cc.ShardBy,
+			[]string{string(config.ShardByNamespace), string(config.ShardByPodGroup)}))
+	}
+	if cc.ShardCount <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("shardCount"), cc.ShardCount, "must be greater than zero"))
+	}
+	if len(cc.LeaseNamespace) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("leaseNamespace"), ""))
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(cc.LeaseName) {
+		errs = append(errs, field.Invalid(fldPath.Child("leaseName"), cc.LeaseName, msg))
+	}
+	if cc.RenewPeriod.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("renewPeriod"), cc.RenewPeriod, "must be greater than zero"))
+	}
+	if cc.LeaseDuration.Duration <= cc.RenewPeriod.Duration {
+		errs = append(errs, field.Invalid(fldPath.Child("leaseDuration"), cc.LeaseDuration, "must be greater than renewPeriod"))
+	}
 	return errs
 }
@@ -19,6 +19,9 @@ package dispatcher
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	scheduling "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
@@ -27,9 +30,13 @@ import (
 	schedulinginformer "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/scheduling/v1alpha1"
 	nodelister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/node/v1alpha1"
 	schedulinglister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	cnrinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions/node/v1alpha1"
+	cnrlisters "github.com/kubewharf/katalyst-api/pkg/client/listers/node/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -47,8 +54,10 @@ import (
 	nodeshuffler "github.com/kubewharf/godel-scheduler/pkg/dispatcher/node-shuffler"
 	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/reconciler"
 	schemaintainer "github.com/kubewharf/godel-scheduler/pkg/dispatcher/scheduler-maintainer"
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/shard"
 	"github.com/kubewharf/godel-scheduler/pkg/features"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
+	eventutil "github.com/kubewharf/godel-scheduler/pkg/util/event"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
@@ -91,14 +100,25 @@ type Dispatcher struct {
 
 	maintainer *schemaintainer.SchedulerMaintainer
 	shuffler   *nodeshuffler.NodeShuffler
+	// labeler labels pods with their partitions, it's nil unless the informers of schedulers are scoped to partitions.
+	labeler *nodeshuffler.PodPartitionLabeler
 
 	reconciler *reconciler.PodStateReconciler
 
+	// shards is the shard manager of the dispatcher, which is nil if the dispatchers aren't sharded.
+	// Only pods in the shards owned by this dispatcher are dispatched and reconciled.
+	shards *shard.Manager
+
 	// SchedulerName here is the higher level scheduler name, which is used to select pods
 	// that godel schedulers should be responsible for and filter out irrelevant pods.
 	SchedulerName string
 
 	recorder events.EventRecorder
+
+	// redispatchTimers are the timers putting the pods failed by all the schedulers back to the queue
+	// after backoff, keyed by pod key.
+	redispatchTimers     map[string]*time.Timer
+	redispatchTimersLock sync.Mutex
 }
 
 func New(
@@ -111,13 +131,21 @@ func New(
 	nmNodeInformer nodeinformer.NMNodeInformer,
 	podGroupInformer schedulinginformer.PodGroupInformer,
 	priorityClassInformer schedinformers.PriorityClassInformer,
+	katalystCrdClient katalystclient.Interface,
+	cnrInformer cnrinformers.CustomNodeResourceInformer,
 	schedulerName string,
 	recorder events.EventRecorder,
+	shards *shard.Manager,
 ) *Dispatcher {
 	metrics.Register()
 
 	maintainer := schemaintainer.NewSchedulerMaintainer(crdClient, schedulerInformer.Lister())
-	shuffler := nodeshuffler.NewNodeShuffler(client, crdClient, nodeInformer.Lister(), nmNodeInformer.Lister(), schedulerInformer.Lister(), maintainer)
+	var cnrLister cnrlisters.CustomNodeResourceLister
+	if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
+		// CNRs are only watched to keep their scheduler names in line with the nodes.
+		cnrLister = cnrInformer.Lister()
+	}
+	shuffler := nodeshuffler.NewNodeShuffler(client, crdClient, katalystCrdClient, nodeInformer.Lister(), nmNodeInformer.Lister(), cnrLister, schedulerInformer.Lister(), maintainer)
 
 	dispatcher := &Dispatcher{
 		StopEverything:       stopCh,
@@ -134,20 +162,32 @@ func New(
 		shuffler:      shuffler,
 		SchedulerName: schedulerName,
 
+		redispatchTimers: make(map[string]*time.Timer),
+
 		NodeLister:          nodeInformer.Lister(),
 		NMNodeLister:        nmNodeInformer.Lister(),
 		PodGroupLister:      podGroupInformer.Lister(),
 		PriorityClassLister: priorityClassInformer.Lister(),
 
 		recorder: recorder,
+		shards:   shards,
 	}
 
 	reconciler := reconciler.NewPodStateReconciler(client, podInformer.Lister(), nodeInformer.Lister(),
-		schedulerInformer.Lister(), nmNodeInformer.Lister(), schedulerName, dispatcher.DispatchInfo, maintainer)
+		schedulerInformer.Lister(), nmNodeInformer.Lister(), schedulerName, dispatcher.DispatchInfo, maintainer, shards.OwnsPod)
 
 	dispatcher.reconciler = reconciler
+	if utilfeature.DefaultFeatureGate.Enabled(features.PartitionScopedInformers) {
+		dispatcher.labeler = nodeshuffler.NewPodPartitionLabeler(client, podInformer, nodeInformer.Lister(), nmNodeInformer.Lister())
+	}
 
-	AddAllEventHandlers(dispatcher, podInformer, schedulerInformer, nodeInformer, nmNodeInformer, podGroupInformer)
+	AddAllEventHandlers(dispatcher, podInformer, schedulerInformer, nodeInformer, nmNodeInformer, podGroupInformer, cnrInformer)
+	if shards != nil {
+		shards.AddEventHandler(shard.EventHandler{
+			OnAcquired: dispatcher.onShardsAcquired,
+			OnReleased: dispatcher.onShardsReleased,
+		})
+	}
 	go func() {
 		<-dispatcher.StopEverything
 		dispatcher.FIFOPendingPodsQueue.Close()
@@ -171,16 +211,26 @@ func (d *Dispatcher) Run(ctx context.Context) {
 
 	go d.maintainer.Run(d.StopEverything)
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
-		go d.shuffler.Run(d.StopEverything)
-	}
-
 	go wait.UntilWithContext(ctx, d.pendingLoop, 0)
 	go wait.UntilWithContext(ctx, d.pendingUnitPodsLoop, 0)
 
 	go d.reconciler.Run(d.StopEverything)
 }
 
+// RunSingletons runs the workers writing cluster-wide objects, which are the Schedulers, the partitions of nodes and
+// the partition labels of pods, until ctx is done. They must only be run by one dispatcher, even if the dispatchers
+// are sharded.
+func (d *Dispatcher) RunSingletons(ctx context.Context) {
+	go d.maintainer.RunCleanup(ctx.Done())
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
+		go d.shuffler.Run(ctx.Done())
+	}
+	if d.labeler != nil {
+		go d.labeler.Run(ctx.Done())
+	}
+}
+
 // pendingUnitPodsLoop adds pods belonging to dispatchable units to the policy
 // manager or the FIFOPendingPodsQueue.
 func (d *Dispatcher) pendingUnitPodsLoop(ctx context.Context) {
@@ -247,8 +297,9 @@ func (d *Dispatcher) dispatchingPod(ctx context.Context, podInfo *queue.QueuedPo
 
 	pod, err := d.podLister.Pods(namespace).Get(name)
 	if apierrs.IsNotFound(err) || pod.DeletionTimestamp != nil ||
-		!podutil.PendingPodOfGodel(pod, d.SchedulerName) {
-		// podInfo was deleted before or is being deleted, or is not in pending state now
+		!podutil.PendingPodOfGodel(pod, d.SchedulerName) || !d.shards.OwnsPod(pod) {
+		// podInfo was deleted before or is being deleted, or is not in pending state now, or its shard
+		// has been taken over by another dispatcher
 		// return directly without re-enqueuing the podInfo
 		return
 	}
@@ -268,7 +319,19 @@ func (d *Dispatcher) dispatchingPod(ctx context.Context, podInfo *queue.QueuedPo
 		go span.Finish()
 	}()
 
-	schedulerName, err := d.selectScheduler(pod)
+	// Schedulers which have failed the pod are excluded, unless all of them have failed the pod. In that case,
+	// the pod is dispatched to all the schedulers again after backoff.
+	excluded := podutil.GetFailedSchedulersNames(pod)
+	resetFailedSchedulers := excluded.Len() > 0 && !d.DispatchInfo.HasSchedulerExcept(excluded)
+	if resetFailedSchedulers {
+		if d.backoffRedispatching(pod, podInfo, originalQueue) {
+			span.WithTags(tracing.WithResultTag(tracing.ResultFailure))
+			return
+		}
+		excluded = sets.NewString()
+	}
+
+	schedulerName, err := d.selectScheduler(pod, excluded)
 	if err != nil {
 		klog.InfoS("Failed to select the scheduler", "err", err)
 		metrics.PodDispatchingFailure(helper.SinceInSeconds(start))
@@ -281,7 +344,7 @@ func (d *Dispatcher) dispatchingPod(ctx context.Context, podInfo *queue.QueuedPo
 	metrics.PodDispatched(helper.SinceInSeconds(start))
 
 	start = time.Now()
-	if err := d.sendPodToScheduler(pod, podInfo, schedulerName); err != nil {
+	if err := d.sendPodToScheduler(pod, podInfo, schedulerName, resetFailedSchedulers); err != nil {
 		// TODO: need to parse error in order to avoid pod ping-pong because of "resource too old" error
 		klog.InfoS("Failed to send pod to the scheduler", "err", err)
 		metrics.ObservePodUpdatingAttemptAndLatency(podProperty, metrics.FailureResult, helper.SinceInSeconds(start))
@@ -296,11 +359,36 @@ func (d *Dispatcher) dispatchingPod(ctx context.Context, podInfo *queue.QueuedPo
 	}
 
 	metrics.ObservePodUpdatingAttemptAndLatency(podProperty, metrics.SuccessResult, helper.SinceInSeconds(start))
+	if resetFailedSchedulers {
+		metrics.PodRedispatched(metrics.AllSchedulersFailed, podutil.GetRedispatchCount(pod)+1)
+	} else if excluded.Len() > 0 {
+		metrics.PodRedispatched(metrics.FailedSchedulersExcluded, podutil.GetRedispatchCount(pod)+1)
+	}
+	d.recordDispatchedEvent(pod, schedulerName, excluded, resetFailedSchedulers)
 	metrics.DispatchedPodsInc(podProperty, schedulerName)
 	metrics.ObservePodDispatchingLatency(helper.SinceInSeconds(podInfo.InitialAddedTimestamp))
 	span.WithTags(tracing.WithResultTag(tracing.ResultSuccess))
 }
 
+// recordDispatchedEvent emits the Dispatched event on the pod, telling the reassignment if the pod has been
+// failed by other schedulers.
+func (d *Dispatcher) recordDispatchedEvent(pod *v1.Pod, schedulerName string, excluded sets.String, resetFailedSchedulers bool) {
+	if d.recorder == nil {
+		return
+	}
+	switch {
+	case resetFailedSchedulers:
+		d.recorder.Eventf(pod, nil, v1.EventTypeNormal, eventutil.Dispatched, eventutil.DispatchingAction,
+			"Redispatched pod to scheduler %s after all the schedulers (%s) failed it", schedulerName, pod.Annotations[podutil.FailedSchedulersAnnotationKey])
+	case excluded.Len() > 0:
+		d.recorder.Eventf(pod, nil, v1.EventTypeNormal, eventutil.Dispatched, eventutil.DispatchingAction,
+			"Redispatched pod to scheduler %s, excluding the failed schedulers (%s)", schedulerName, strings.Join(excluded.List(), ","))
+	default:
+		d.recorder.Eventf(pod, nil, v1.EventTypeNormal, eventutil.Dispatched, eventutil.DispatchingAction,
+			"Dispatched pod to scheduler %s", schedulerName)
+	}
+}
+
 func (d *Dispatcher) sortedLoop(ctx context.Context) {
 	for {
 		if podInfo, _ := d.SortedPodsQueue.PopPodInfo(); podInfo != nil {
@@ -375,12 +463,14 @@ func (d *Dispatcher) getAssignedScheduler(pg *scheduling.PodGroup) (string, erro
 // selectSchedulerForUnit selects a secheduler for the podgroup, if the
 // dispatcher has already assigned a scheduler to the podgroup, then returns
 // the existing one.
-func (d *Dispatcher) selectSchedulerForUnit(pg *scheduling.PodGroup, pod *v1.Pod, podOwner string) (string, error) {
+// Pods of the podgroup are failed by the scheduler together, so the assigned scheduler is replaced if it's
+// excluded, and the remaining pods will follow the new one.
+func (d *Dispatcher) selectSchedulerForUnit(pg *scheduling.PodGroup, pod *v1.Pod, podOwner string, excluded sets.String) (string, error) {
 	schedName, err := d.getAssignedScheduler(pg)
 	if err != nil {
 		return "", err
 	}
-	if schedName != "" && d.maintainer.SchedulerExist(schedName) && d.maintainer.IsSchedulerInActiveQueue(schedName) {
+	if schedName != "" && !excluded.Has(schedName) && d.maintainer.SchedulerExist(schedName) && d.maintainer.IsSchedulerInActiveQueue(schedName) {
 		d.DispatchInfo.AddPodInAdvance(pod, schedName)
 		if utilfeature.DefaultFeatureGate.Enabled(features.SupportRescheduling) {
 			d.OwnerInfos.AddDispatchedUnboundPod(pod, schedName)
@@ -390,13 +480,13 @@ func (d *Dispatcher) selectSchedulerForUnit(pg *scheduling.PodGroup, pod *v1.Pod
 
 	forceUpdate := false
 	if len(schedName) != 0 {
-		// previous scheduler name for this unit is not empty, but that scheduler is inactive or deleted
-		// we need to reset the scheduler name for this unit forcefully
+		// previous scheduler name for this unit is not empty, but that scheduler is inactive, deleted or
+		// has failed the unit, we need to reset the scheduler name for this unit forcefully
 		forceUpdate = true
 	}
 	klog.V(4).InfoS("Selected a new scheduler for the podGroup", "podGroup", klog.KObj(pg))
 	// select a scheduler for the first dispatchable pod of the PodGroup.
-	schedName, err = d.pickScheduler(pod)
+	schedName, err = d.pickScheduler(pod, excluded)
 	if err != nil {
 		return "", err
 	}
@@ -405,7 +495,8 @@ func (d *Dispatcher) selectSchedulerForUnit(pg *scheduling.PodGroup, pod *v1.Pod
 	return schedName, err
 }
 
-func (d *Dispatcher) selectScheduler(pod *v1.Pod) (string, error) {
+// selectScheduler selects a scheduler for the pod among the schedulers which are not excluded.
+func (d *Dispatcher) selectScheduler(pod *v1.Pod, excluded sets.String) (string, error) {
 	podOwner := podutil.GetPodOwner(pod)
 	pgName := unitutil.GetPodGroupName(pod)
 	// get the scheduler name for pods belonging to an unit
@@ -417,27 +508,36 @@ func (d *Dispatcher) selectScheduler(pod *v1.Pod) (string, error) {
 			return "", err
 		}
 		// get the assigned scheduler, if any.
-		return d.selectSchedulerForUnit(pg, pod, podOwner)
+		return d.selectSchedulerForUnit(pg, pod, podOwner, excluded)
 	}
 	// get the scheduler name for pods not belonging to any unit
-	return d.pickScheduler(pod)
+	return d.pickScheduler(pod, excluded)
 }
 
-func (d *Dispatcher) pickScheduler(pod *v1.Pod) (string, error) {
+func (d *Dispatcher) pickScheduler(pod *v1.Pod, excluded sets.String) (string, error) {
 	if utilfeature.DefaultFeatureGate.Enabled(features.SupportRescheduling) {
-		return d.selectSchedulerBasedOnOwner(pod)
+		return d.selectSchedulerBasedOnOwner(pod, excluded)
 	}
-	return d.loadBalancing(pod)
+	return d.loadBalancing(pod, excluded)
 }
 
-func (d *Dispatcher) selectSchedulerBasedOnOwner(pod *v1.Pod) (string, error) {
+func (d *Dispatcher) selectSchedulerBasedOnOwner(pod *v1.Pod, excluded sets.String) (string, error) {
+	if excluded.Len() > 0 {
+		// the scheduler of the owner may have failed the pod, move the owner to the newly selected scheduler
+		schedulerName, err := d.loadBalancing(pod, excluded)
+		if err == nil {
+			d.OwnerInfos.AddDispatchedUnboundPod(pod, schedulerName)
+		}
+		return schedulerName, err
+	}
+
 	schedulerName := d.OwnerInfos.SelectSchedulerAndSetDispatchedUnboundPod(pod)
 	if schedulerName != "" {
 		d.DispatchInfo.AddPodInAdvance(pod, schedulerName)
 		return schedulerName, nil
 	}
 
-	schedulerName, err := d.loadBalancing(pod)
+	schedulerName, err := d.loadBalancing(pod, excluded)
 	if err == nil && schedulerName != "" {
 		gotSchedulerName := d.OwnerInfos.SetDispatchedUnboundPod(pod, schedulerName)
 		if gotSchedulerName != schedulerName {
@@ -449,21 +549,36 @@ func (d *Dispatcher) selectSchedulerBasedOnOwner(pod *v1.Pod) (string, error) {
 	return schedulerName, err
 }
 
-func (d *Dispatcher) loadBalancing(pod *v1.Pod) (string, error) {
-	if schedulerName := d.DispatchInfo.GetMostIdleSchedulerAndAddPodInAdvance(pod); len(schedulerName) == 0 {
+func (d *Dispatcher) loadBalancing(pod *v1.Pod, excluded sets.String) (string, error) {
+	if schedulerName := d.DispatchInfo.GetMostIdleSchedulerAndAddPodInAdvance(pod, excluded); len(schedulerName) == 0 {
+		if excluded.Len() > 0 {
+			return "", fmt.Errorf("no scheduler registered except the failed ones: %v", excluded.List())
+		}
 		return "", fmt.Errorf("no scheduler registered")
 	} else {
 		return schedulerName, nil
 	}
 }
 
-func (d *Dispatcher) sendPodToScheduler(pod *v1.Pod, podInfo *queue.QueuedPodInfo, schedulerName string) (err error) {
+func (d *Dispatcher) sendPodToScheduler(pod *v1.Pod, podInfo *queue.QueuedPodInfo, schedulerName string, resetFailedSchedulers bool) (err error) {
 	podCopy := pod.DeepCopy()
 	if podCopy.Annotations == nil {
 		podCopy.Annotations = make(map[string]string)
 	}
+	if len(podCopy.Annotations[podutil.FailedSchedulersAnnotationKey]) > 0 {
+		podCopy.Annotations[podutil.RedispatchCountAnnotationKey] = strconv.Itoa(podutil.GetRedispatchCount(pod) + 1)
+	}
+	if resetFailedSchedulers {
+		delete(podCopy.Annotations, podutil.FailedSchedulersAnnotationKey)
+	}
 	podCopy.Annotations[podutil.SchedulerAnnotationKey] = schedulerName
 	podCopy.Annotations[podutil.PodStateAnnotationKey] = string(podutil.PodDispatched)
+	if d.labeler != nil {
+		if podCopy.Labels == nil {
+			podCopy.Labels = make(map[string]string)
+		}
+		podCopy.Labels[podutil.PartitionLabelKey] = schedulerName
+	}
 	if _, ok := podCopy.Annotations[podutil.InitialHandledTimestampAnnotationKey]; !ok {
 		podCopy.Annotations[podutil.InitialHandledTimestampAnnotationKey] = podInfo.InitialAddedTimestamp.Format(helper.TimestampLayout)
 	}
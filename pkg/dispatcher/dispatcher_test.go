@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/features"
 	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -29,12 +30,17 @@ import (
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	katalystclientfake "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned/fake"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
@@ -211,13 +217,15 @@ func TestSelectSchedulerAccordingToOwner(t *testing.T) {
 			nmNodeInformer := crdInformerFactory.Node().V1alpha1().NMNodes()
 			podGroupInformer := crdInformerFactory.Scheduling().V1alpha1().PodGroups()
 			pcInformer := informerFactory.Scheduling().V1().PriorityClasses()
+			katalystClient := katalystclientfake.NewSimpleClientset()
+			cnrInformer := katalystinformers.NewSharedInformerFactory(katalystClient, 0).Node().V1alpha1().CustomNodeResources()
 			schedulerSharedInformer := schedulerInformer.Informer()
 			crdInformerFactory.Start(stopCh)
 			podSharedInformer := podInformer.Informer()
 			informerFactory.Start(stopCh)
 			cache.WaitForCacheSync(stopCh, podSharedInformer.HasSynced, schedulerSharedInformer.HasSynced)
 
-			dispatcher := New(stopCh, client, crdClient, podInformer, nodeInformer, schedulerInformer, nmNodeInformer, podGroupInformer, pcInformer, schedulerName, nil)
+			dispatcher := New(stopCh, client, crdClient, podInformer, nodeInformer, schedulerInformer, nmNodeInformer, podGroupInformer, pcInformer, katalystClient, cnrInformer, schedulerName, nil, nil)
 
 			for _, p := range tt.pods {
 				dispatcher.addPodToPendingOrSortedQueue(p)
@@ -262,3 +270,179 @@ func TestSelectSchedulerAccordingToOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestRedispatchFailedPods(t *testing.T) {
+	timeNow := metav1.NewTime(time.Now())
+	var schedulers []runtime.Object
+	for i := 0; i < 3; i++ {
+		schedulers = append(schedulers, &schedulingv1a1.Scheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("godel-scheduler-%d", i)},
+			Status:     schedulingv1a1.SchedulerStatus{LastUpdateTime: &timeNow},
+		})
+	}
+
+	tests := []struct {
+		name                     string
+		failedSchedulers         string
+		redispatchCount          string
+		expectedSchedulers       sets.String
+		expectedFailedSchedulers string
+		expectedRedispatchCount  string
+		expectedDispatchedAfter  time.Duration
+	}{
+		{
+			name:                     "dispatch to the scheduler which hasn't failed the pod",
+			failedSchedulers:         "godel-scheduler-0,godel-scheduler-2",
+			expectedSchedulers:       sets.NewString("godel-scheduler-1"),
+			expectedFailedSchedulers: "godel-scheduler-0,godel-scheduler-2",
+			expectedRedispatchCount:  "1",
+		},
+		{
+			name:                     "reset failed schedulers after backoff once all the schedulers have failed the pod",
+			failedSchedulers:         "godel-scheduler-0,godel-scheduler-1,godel-scheduler-2",
+			redispatchCount:          "2",
+			expectedSchedulers:       sets.NewString("godel-scheduler-0", "godel-scheduler-1", "godel-scheduler-2"),
+			expectedFailedSchedulers: "",
+			expectedRedispatchCount:  "3",
+			expectedDispatchedAfter:  RedispatchInitialBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			pod := testing_helper.MakePod().Namespace("default").Name("p").UID("p").
+				Annotation(podutil.PodStateAnnotationKey, string(podutil.PodPending)).
+				Annotation(podutil.FailedSchedulersAnnotationKey, tt.failedSchedulers).Obj()
+			if len(tt.redispatchCount) > 0 {
+				pod.Annotations[podutil.RedispatchCountAnnotationKey] = tt.redispatchCount
+			}
+			client := clientsetfake.NewSimpleClientset(pod)
+			informerFactory := informers.NewSharedInformerFactory(client, 0)
+			crdClient := godelclientfake.NewSimpleClientset(schedulers...)
+			crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+			katalystClient := katalystclientfake.NewSimpleClientset()
+			podInformer := informerFactory.Core().V1().Pods()
+			schedulerInformer := crdInformerFactory.Scheduling().V1alpha1().Schedulers()
+			dispatcher := New(stopCh, client, crdClient, podInformer, informerFactory.Core().V1().Nodes(), schedulerInformer,
+				crdInformerFactory.Node().V1alpha1().NMNodes(), crdInformerFactory.Scheduling().V1alpha1().PodGroups(),
+				informerFactory.Scheduling().V1().PriorityClasses(), katalystClient, katalystinformers.NewSharedInformerFactory(katalystClient, 0).Node().V1alpha1().CustomNodeResources(),
+				schedulerName, nil, nil)
+			informerFactory.Start(stopCh)
+			crdInformerFactory.Start(stopCh)
+			cache.WaitForCacheSync(stopCh, podInformer.Informer().HasSynced, schedulerInformer.Informer().HasSynced)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			start := time.Now()
+			dispatcher.Run(ctx)
+
+			var got *v1.Pod
+			if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+				got, _ = client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+				return podutil.DispatchedPod(got), nil
+			}); err != nil {
+				t.Fatalf("pod is not dispatched: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.expectedDispatchedAfter {
+				t.Errorf("expected pod to be dispatched after %v, but got %v", tt.expectedDispatchedAfter, elapsed)
+			}
+			if scheduler := got.Annotations[podutil.SchedulerAnnotationKey]; !tt.expectedSchedulers.Has(scheduler) {
+				t.Errorf("expected pod to be dispatched to one of %v, but got %s", tt.expectedSchedulers.List(), scheduler)
+			}
+			if failedSchedulers := got.Annotations[podutil.FailedSchedulersAnnotationKey]; failedSchedulers != tt.expectedFailedSchedulers {
+				t.Errorf("expected failed schedulers %q, but got %q", tt.expectedFailedSchedulers, failedSchedulers)
+			}
+			if count := got.Annotations[podutil.RedispatchCountAnnotationKey]; count != tt.expectedRedispatchCount {
+				t.Errorf("expected redispatch count %s, but got %s", tt.expectedRedispatchCount, count)
+			}
+		})
+	}
+}
+
+func TestGetRedispatchBackoff(t *testing.T) {
+	tests := []struct {
+		redispatchCount int
+		numSchedulers   int
+		expected        time.Duration
+	}{
+		{redispatchCount: 0, numSchedulers: 1, expected: RedispatchInitialBackoff},
+		{redispatchCount: 2, numSchedulers: 3, expected: RedispatchInitialBackoff},
+		{redispatchCount: 5, numSchedulers: 3, expected: 2 * RedispatchInitialBackoff},
+		{redispatchCount: 8, numSchedulers: 3, expected: 4 * RedispatchInitialBackoff},
+		{redispatchCount: 3, numSchedulers: 0, expected: 8 * RedispatchInitialBackoff},
+		{redispatchCount: 100, numSchedulers: 1, expected: RedispatchMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := getRedispatchBackoff(tt.redispatchCount, tt.numSchedulers); got != tt.expected {
+			t.Errorf("getRedispatchBackoff(%d, %d) = %v, expected %v", tt.redispatchCount, tt.numSchedulers, got, tt.expected)
+		}
+	}
+}
+
+func TestBackoffRedispatchingSkipsStalePods(t *testing.T) {
+	pod := testing_helper.MakePod().Namespace("default").Name("p").UID("p").
+		Annotation(podutil.PodStateAnnotationKey, string(podutil.PodPending)).Obj()
+	recreated := pod.DeepCopy()
+	recreated.UID = "p-new"
+
+	tests := []struct {
+		name     string
+		pods     []*v1.Pod
+		cancel   bool
+		expected bool
+	}{
+		{
+			name:     "pending pod is put back after backoff",
+			pods:     []*v1.Pod{pod},
+			expected: true,
+		},
+		{
+			name:     "deleted pod is not put back",
+			expected: false,
+		},
+		{
+			name:     "recreated pod is not put back",
+			pods:     []*v1.Pod{recreated},
+			expected: false,
+		},
+		{
+			name:     "cancelled backoff doesn't put the pod back",
+			pods:     []*v1.Pod{pod},
+			cancel:   true,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, p := range tt.pods {
+				indexer.Add(p)
+			}
+			d := &Dispatcher{
+				podLister:        listerv1.NewPodLister(indexer),
+				SchedulerName:    schedulerName,
+				redispatchTimers: make(map[string]*time.Timer),
+			}
+			q := queue.NewSortedFIFO(nil)
+			defer q.Close()
+			podInfo, err := queue.NewQueuedPodInfo(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			podInfo.RedispatchBackoffExpiration = time.Now().Add(10 * time.Millisecond)
+
+			if !d.backoffRedispatching(pod, podInfo, q) {
+				t.Fatalf("expected pod to back off")
+			}
+			if tt.cancel {
+				d.cancelRedispatching(podInfo.PodKey)
+			}
+			time.Sleep(100 * time.Millisecond)
+			if got := q.PodInfoExist(podInfo); got != tt.expected {
+				t.Errorf("expected pod in queue: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -21,7 +21,11 @@ import (
 	scheduling "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	nodeinformer "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/node/v1alpha1"
 	schedulinginformer "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/scheduling/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	cnrinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions/node/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -156,6 +160,7 @@ func (d *Dispatcher) deletePodFromPendingOrSortedQueue(obj interface{}) {
 		return
 	}
 	klog.V(3).InfoS("Detected a Delete event for the pending pod", "podResourceType", podInfo.PodResourceType, "pod", klog.KObj(pod))
+	d.cancelRedispatching(podInfo.PodKey)
 
 	// if the pod has been inserted into the Sorted Queue, remove the
 	// pod from it
@@ -232,6 +237,7 @@ func AddAllEventHandlers(
 	nodeInformer coreinformers.NodeInformer,
 	nmNodeInformer nodeinformer.NMNodeInformer,
 	podGroupInformer schedulinginformer.PodGroupInformer,
+	cnrInformer cnrinformers.CustomNodeResourceInformer,
 ) {
 	// pending pods queue
 	podInformer.Informer().AddEventHandler(
@@ -239,10 +245,10 @@ func AddAllEventHandlers(
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
 				case *v1.Pod:
-					return podutil.PendingPodOfGodel(t, dispatcher.SchedulerName)
+					return podutil.PendingPodOfGodel(t, dispatcher.SchedulerName) && dispatcher.shards.OwnsPod(t)
 				case cache.DeletedFinalStateUnknown:
 					if pod, ok := t.Obj.(*v1.Pod); ok {
-						return podutil.PendingPodOfGodel(pod, dispatcher.SchedulerName)
+						return podutil.PendingPodOfGodel(pod, dispatcher.SchedulerName) && dispatcher.shards.OwnsPod(pod)
 					}
 					klog.InfoS("Failed to convert object to *v1.Pod", "object", obj, "component", dispatcher)
 					return false
@@ -342,6 +348,27 @@ func AddAllEventHandlers(
 		},
 	)
 
+	if dispatcher.labeler != nil {
+		podInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    dispatcher.labeler.AddPod,
+				UpdateFunc: dispatcher.labeler.UpdatePod,
+			},
+		)
+		nodeInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    dispatcher.labeler.AddNode,
+				UpdateFunc: dispatcher.labeler.UpdateNode,
+			},
+		)
+		nmNodeInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    dispatcher.labeler.AddNMNode,
+				UpdateFunc: dispatcher.labeler.UpdateNMNode,
+			},
+		)
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(features.DispatcherNodeShuffle) {
 		nodeInformer.Informer().AddEventHandler(
 			cache.ResourceEventHandlerFuncs{
@@ -358,6 +385,13 @@ func AddAllEventHandlers(
 				DeleteFunc: dispatcher.deleteNMNode,
 			},
 		)
+
+		cnrInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    dispatcher.addCNR,
+				UpdateFunc: dispatcher.updateCNR,
+			},
+		)
 	}
 }
 
@@ -593,6 +627,33 @@ func (d *Dispatcher) deleteNMNode(obj interface{}) {
 	d.maintainer.DeleteNMNodeFromGodelScheduler(nmNode)
 }
 
+func (d *Dispatcher) addCNR(obj interface{}) {
+	cnr, ok := obj.(*katalystv1alpha1.CustomNodeResource)
+	if !ok {
+		klog.InfoS("Failed to convert to *katalystv1alpha1.CustomNodeResource", "object", obj)
+		return
+	}
+
+	klog.V(4).InfoS("Started to add cnr", "cnr", cnr.Name)
+	d.shuffler.AddCNR(cnr)
+}
+
+func (d *Dispatcher) updateCNR(oldObj, newObj interface{}) {
+	oldCNR, ok := oldObj.(*katalystv1alpha1.CustomNodeResource)
+	if !ok {
+		klog.InfoS("Failed to convert oldObj to *katalystv1alpha1.CustomNodeResource", "oldObject", oldObj)
+		return
+	}
+	newCNR, ok := newObj.(*katalystv1alpha1.CustomNodeResource)
+	if !ok {
+		klog.InfoS("Failed to convert newObj to *katalystv1alpha1.CustomNodeResource", "newObject", newObj)
+		return
+	}
+
+	klog.V(4).InfoS("Started to update cnr", "cnr", newCNR.Name)
+	d.shuffler.UpdateCNR(oldCNR, newCNR)
+}
+
 func (d *Dispatcher) addPodToAbnormalQueue(obj interface{}) {
 	pod, err := podutil.ConvertToPod(obj)
 	if err != nil {
@@ -600,7 +661,7 @@ func (d *Dispatcher) addPodToAbnormalQueue(obj interface{}) {
 		return
 	}
 
-	if abnormal := podutil.AbnormalPodStateOfGodel(pod, d.SchedulerName); abnormal {
+	if abnormal := podutil.AbnormalPodStateOfGodel(pod, d.SchedulerName); abnormal && d.shards.OwnsPod(pod) {
 		podKey, err := cache.MetaNamespaceKeyFunc(pod)
 		if err == nil {
 			d.reconciler.AbnormalPodsEnqueue(podKey)
@@ -616,7 +677,7 @@ func (d *Dispatcher) updatePodInAbnormalQueue(_, newObj interface{}) {
 		klog.InfoS("Failed to add pod to dispatched", "err", err)
 		return
 	}
-	if abnormal := podutil.AbnormalPodStateOfGodel(newPod, d.SchedulerName); abnormal {
+	if abnormal := podutil.AbnormalPodStateOfGodel(newPod, d.SchedulerName); abnormal && d.shards.OwnsPod(newPod) {
 		podKey, err := cache.MetaNamespaceKeyFunc(newPod)
 		if err == nil {
 			d.reconciler.AbnormalPodsEnqueue(podKey)
@@ -625,3 +686,45 @@ func (d *Dispatcher) updatePodInAbnormalQueue(_, newObj interface{}) {
 		}
 	}
 }
+
+// onShardsAcquired adds the pods of the shards taken over by this dispatcher, since the pod informer won't
+// send events for them until they're updated.
+func (d *Dispatcher) onShardsAcquired(shards sets.Int) {
+	for _, pod := range d.podsOfShards(shards) {
+		switch {
+		case podutil.PendingPodOfGodel(pod, d.SchedulerName):
+			d.addPodToPendingOrSortedQueue(pod)
+		case podutil.AbnormalPodStateOfGodel(pod, d.SchedulerName):
+			d.addPodToAbnormalQueue(pod)
+		case podutil.DispatchedPodOfGodel(pod, d.SchedulerName):
+			// The previous owner may have failed before resetting the pods of the inactive schedulers.
+			if podKey, err := cache.MetaNamespaceKeyFunc(pod); err == nil {
+				d.reconciler.StaleDispatchedPodsEnqueue(podKey)
+			}
+		}
+	}
+}
+
+// onShardsReleased removes the pending pods of the shards released by this dispatcher.
+func (d *Dispatcher) onShardsReleased(shards sets.Int) {
+	for _, pod := range d.podsOfShards(shards) {
+		if podutil.PendingPodOfGodel(pod, d.SchedulerName) {
+			d.deletePodFromPendingOrSortedQueue(pod)
+		}
+	}
+}
+
+func (d *Dispatcher) podsOfShards(shards sets.Int) []*v1.Pod {
+	pods, err := d.podLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods of the shards", "shards", shards.List(), "err", err)
+		return nil
+	}
+	podsOfShards := make([]*v1.Pod, 0)
+	for _, pod := range pods {
+		if shards.Has(d.shards.ShardOfPod(pod)) {
+			podsOfShards = append(podsOfShards, pod)
+		}
+	}
+	return podsOfShards
+}
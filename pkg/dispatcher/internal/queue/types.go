@@ -63,6 +63,9 @@ type QueuedPodInfo struct {
 	// Tracing context used in dispatcher, which should be passed between different queues
 	SpanContext tracing.SpanContext
 
+	// The time after which the pod can be dispatched again, if it has been failed by all the schedulers.
+	RedispatchBackoffExpiration time.Time
+
 	// The property of the pod, which is used to describe the pod's attributes
 	PodProperty *framework.PodProperty
 }
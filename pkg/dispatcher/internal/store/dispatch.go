@@ -99,7 +99,9 @@ type DispatchInfo interface {
 	RemovePodByKey(key string)
 	AddPodInAdvance(pod *v1.Pod, scheduler string)
 	UpdatePodInAdvance(pod *v1.Pod, scheduler string)
-	GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) string
+	GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod, excluded sets.String) string
+	HasSchedulerExcept(excluded sets.String) bool
+	NumSchedulers() int
 	AddScheduler(schedulerName string)
 	DeleteScheduler(schedulerName string)
 	GetPodsOfOneScheduler(schedulerName string) []string
@@ -227,7 +229,9 @@ func (dq *dispatchInfo) UpdatePodInAdvance(pod *v1.Pod, scheduler string) {
 	dq.addPod(pod, scheduler)
 }
 
-func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) string {
+// GetMostIdleSchedulerAndAddPodInAdvance selects the scheduler with the fewest dispatched pods among the schedulers
+// which are not excluded, and returns an empty string if there is no such scheduler.
+func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod, excluded sets.String) string {
 	dq.lock.Lock()
 	defer dq.lock.Unlock()
 
@@ -236,6 +240,9 @@ func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) stri
 	// Ref: https://en.wikipedia.org/wiki/reservoir_sampling for more details about Reservoir Sampling.
 	var randomPoolSize int
 	for schedulerName := range dq.Schedulers {
+		if excluded.Has(schedulerName) {
+			continue
+		}
 		cnt := 0
 		if dq.SchedulerToPods[schedulerName] != nil {
 			cnt = dq.SchedulerToPods[schedulerName].Len()
@@ -258,6 +265,26 @@ func (dq *dispatchInfo) GetMostIdleSchedulerAndAddPodInAdvance(pod *v1.Pod) stri
 	return result
 }
 
+// HasSchedulerExcept returns true if there is any scheduler not in the excluded set.
+func (dq *dispatchInfo) HasSchedulerExcept(excluded sets.String) bool {
+	dq.lock.RLock()
+	defer dq.lock.RUnlock()
+
+	for schedulerName := range dq.Schedulers {
+		if !excluded.Has(schedulerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (dq *dispatchInfo) NumSchedulers() int {
+	dq.lock.RLock()
+	defer dq.lock.RUnlock()
+
+	return len(dq.Schedulers)
+}
+
 type OwnerInfo interface {
 	AddDispatchedUnboundPod(pod *v1.Pod, schedulerName string)
 	SetDispatchedUnboundPod(pod *v1.Pod, schedulerName string) string
@@ -161,6 +161,7 @@ func Test_dispatchInfo_GetMostIdleSchedulerAndAddPodInAdvance(t *testing.T) {
 		name        string
 		pod         *corev1.Pod
 		schedulers  []string
+		excluded    []string
 		existedPods []*corev1.Pod
 		assert      func(result string) bool
 		expected    string
@@ -205,6 +206,31 @@ func Test_dispatchInfo_GetMostIdleSchedulerAndAddPodInAdvance(t *testing.T) {
 			},
 			expected: "test-scheduler-1 or test-scheduler-0",
 		},
+		{
+			name:       "skip excluded scheduler even if it's the most idle one",
+			pod:        newSimplePodWithSchedulerName("test-ns", "pod0", ""),
+			schedulers: []string{"test-scheduler-0", "test-scheduler-1"},
+			excluded:   []string{"test-scheduler-1"},
+			existedPods: []*corev1.Pod{
+				newSimplePodWithSchedulerName("test-ns", "pod0", "test-scheduler-0"),
+				newSimplePodWithSchedulerName("test-ns", "pod1", "test-scheduler-0"),
+				newSimplePodWithSchedulerName("test-ns", "pod2", "test-scheduler-1"),
+			},
+			assert: func(result string) bool {
+				return result == "test-scheduler-0"
+			},
+			expected: "test-scheduler-0",
+		},
+		{
+			name:       "return empty if all schedulers are excluded",
+			pod:        newSimplePodWithSchedulerName("test-ns", "pod0", ""),
+			schedulers: []string{"test-scheduler-0", "test-scheduler-1"},
+			excluded:   []string{"test-scheduler-0", "test-scheduler-1"},
+			assert: func(result string) bool {
+				return result == ""
+			},
+			expected: "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,9 +243,13 @@ func Test_dispatchInfo_GetMostIdleSchedulerAndAddPodInAdvance(t *testing.T) {
 				dq.AddPod(pod)
 			}
 
-			if got := dq.GetMostIdleSchedulerAndAddPodInAdvance(tt.pod); !tt.assert(got) {
+			excluded := sets.NewString(tt.excluded...)
+			if got := dq.GetMostIdleSchedulerAndAddPodInAdvance(tt.pod, excluded); !tt.assert(got) {
 				t.Errorf("GetMostIdleSchedulerAndAddPodInAdvance() = %v, expected %v", got, tt.expected)
 			}
+			if got, want := dq.HasSchedulerExcept(excluded), len(tt.expected) > 0; got != want {
+				t.Errorf("HasSchedulerExcept() = %v, expected %v", got, want)
+			}
 		})
 	}
 }
@@ -29,4 +29,10 @@ const (
 	SuccessResult = "success"
 	// FailureResult - result label value
 	FailureResult = "failure"
+
+	// FailedSchedulersExcluded - reason label value, pods are redispatched to schedulers which haven't failed them
+	FailedSchedulersExcluded = "failed_schedulers_excluded"
+	// AllSchedulersFailed - reason label value, pods are redispatched to all schedulers after backoff
+	// since all of them have failed the pods
+	AllSchedulersFailed = "all_schedulers_failed"
 )
@@ -187,6 +187,23 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.ResultLabel})
 
+	redispatchedPods = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DispatcherSubsystem,
+			Name:           "redispatched_pods_total",
+			Help:           "Number of pods dispatched again after being failed by schedulers, by whether the failed schedulers are excluded or reset.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ReasonLabel})
+
+	podRedispatchCount = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      DispatcherSubsystem,
+			Name:           "pod_redispatch_count",
+			Help:           "Number of times a pod has been redispatched, observed each time the pod is redispatched.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 10),
+			StabilityLevel: metrics.ALPHA,
+		})
+
 	podUpdatingAttempts = metrics.NewCounterVec(
 		&metrics.CounterOpts{
 			Subsystem:      DispatcherSubsystem,
@@ -382,6 +399,12 @@ func DispatchedPodsInc(podProperty *api.PodProperty, scheduler string) {
 	newDispatchedPodsCounterMetric(podLabels).Inc()
 }
 
+// PodRedispatched records a redispatch of the pod, which has been redispatched count times so far
+func PodRedispatched(reason string, count int) {
+	redispatchedPods.With(metrics.Labels{pkgmetrics.ReasonLabel: reason}).Inc()
+	podRedispatchCount.Observe(float64(count))
+}
+
 // newDispatchingAttemptsCounterMetric returns the CounterMetric for given labels by DispatchingAttempts
 func newDispatchingAttemptsCounterMetric(labels metrics.Labels) metrics.CounterMetric {
 	return dispatchingAttempts.With(labels)
@@ -48,6 +48,8 @@ var metricsList = []metrics.Registerable{
 	dispatchedPods,
 	dispatchingAttempts,
 	podUpdatingAttempts,
+	redispatchedPods,
+	podRedispatchCount,
 	podShufflingCount,
 	queueSortingLatency,
 
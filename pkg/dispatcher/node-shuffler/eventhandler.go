@@ -18,6 +18,7 @@ package node_shuffler
 
 import (
 	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 
 	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
@@ -56,3 +57,23 @@ func (ns *NodeShuffler) UpdateNMNode(oldNMNode *nodev1alpha1.NMNode, newNMNode *
 	ns.addNodeToProcessingQueueIfNecessary(newNMNode.Name, newNMNode.Annotations)
 	return nil
 }
+
+func (ns *NodeShuffler) addCNRToProcessingQueueIfNecessary(cnr *katalystv1alpha1.CustomNodeResource) {
+	schedulerName := ns.schedulerNameOfNode(cnr.Name)
+	if len(schedulerName) > 0 && !nodeOfScheduler(&cnr.ObjectMeta, schedulerName) {
+		ns.nodeProcessingQueue.Add(&NodeToBeProcessed{
+			nodeName: cnr.Name,
+			reason:   CNROutOfSync,
+		})
+	}
+}
+
+func (ns *NodeShuffler) AddCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	ns.addCNRToProcessingQueueIfNecessary(cnr)
+	return nil
+}
+
+func (ns *NodeShuffler) UpdateCNR(oldCNR *katalystv1alpha1.CustomNodeResource, newCNR *katalystv1alpha1.CustomNodeResource) error {
+	ns.addCNRToProcessingQueueIfNecessary(newCNR)
+	return nil
+}
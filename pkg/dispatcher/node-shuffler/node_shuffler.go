@@ -25,6 +25,8 @@ import (
 	crdclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
 	nodelister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/node/v1alpha1"
 	schedulerlister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	cnrlister "github.com/kubewharf/katalyst-api/pkg/client/listers/node/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,10 +46,12 @@ import (
 type NodeShuffler struct {
 	schedulerMaintainer *schemaintainer.SchedulerMaintainer
 
-	k8sClient    kubernetes.Interface
-	crdClient    crdclient.Interface
-	nodeLister   corelister.NodeLister
-	nmNodeLister nodelister.NMNodeLister
+	k8sClient      kubernetes.Interface
+	crdClient      crdclient.Interface
+	katalystClient katalystclient.Interface
+	nodeLister     corelister.NodeLister
+	nmNodeLister   nodelister.NMNodeLister
+	cnrLister      cnrlister.CustomNodeResourceLister
 
 	schedulerLister schedulerlister.SchedulerLister
 
@@ -126,18 +130,22 @@ const (
 	InactiveScheduler EnqueueReason = "InactiveScheduler"
 	// too many nodes in this scheduler's partition
 	TooManyNodesInThisPartition EnqueueReason = "TooManyNodesInThisPartition"
+	// scheduler name of cnr is different from the node
+	CNROutOfSync EnqueueReason = "CNROutOfSync"
 )
 
 // NewNodeShuffler creates a new NodeShuffler struct
-func NewNodeShuffler(k8sClient kubernetes.Interface, crdClient crdclient.Interface,
-	nodeLister corelister.NodeLister, nmNodeLister nodelister.NMNodeLister, schedulerLister schedulerlister.SchedulerLister,
-	maintainer *schemaintainer.SchedulerMaintainer,
+func NewNodeShuffler(k8sClient kubernetes.Interface, crdClient crdclient.Interface, katalystClient katalystclient.Interface,
+	nodeLister corelister.NodeLister, nmNodeLister nodelister.NMNodeLister, cnrLister cnrlister.CustomNodeResourceLister,
+	schedulerLister schedulerlister.SchedulerLister, maintainer *schemaintainer.SchedulerMaintainer,
 ) *NodeShuffler {
 	return &NodeShuffler{
 		k8sClient:           k8sClient,
 		crdClient:           crdClient,
+		katalystClient:      katalystClient,
 		nodeLister:          nodeLister,
 		nmNodeLister:        nmNodeLister,
+		cnrLister:           cnrLister,
 		schedulerLister:     schedulerLister,
 		schedulerMaintainer: maintainer,
 		nodeProcessingQueue: NewNodeQueue(),
@@ -150,7 +158,6 @@ func (ns *NodeShuffler) Run(stopCh <-chan struct{}) {
 	go wait.Until(ns.nodeProcessingWorker, time.Second, stopCh)
 	// run re-balancing goroutine every one minute
 	go wait.Until(ns.ReBalanceSchedulerNodes, time.Minute, stopCh)
-	// TODO: sync up node and cnr scheduler name annotation
 
 	<-stopCh
 }
@@ -169,6 +176,13 @@ func (ns *NodeShuffler) nodeProcessingWorker() {
 		}
 		klog.V(5).InfoS("Started to process node", "nodeInfo", nodeInfo)
 
+		if nodeInfo.reason == CNROutOfSync {
+			if err := ns.SyncUpNodeAndCNR(nodeInfo.nodeName); err != nil {
+				klog.InfoS("Failed to sync up the scheduler name of cnr", "node", klog.KRef("", nodeInfo.nodeName), "err", err)
+			}
+			return false
+		}
+
 		node, nodeErr := ns.nodeLister.Get(nodeInfo.nodeName)
 		if nodeErr != nil && !errors.IsNotFound(nodeErr) {
 			klog.InfoS("Failed to get the node from informer", "node", klog.KRef("", nodeInfo.nodeName), "err", nodeErr)
@@ -223,36 +237,36 @@ func (ns *NodeShuffler) updateSchedulerNameForNode(node *v1.Node, nmNode *nodev1
 	// and maybe we can take the previous scheduler name into account too.
 }
 
-// updateNodeSchedulerNameAnnotation updates node annotation
+// updateNodeSchedulerNameAnnotation updates node annotation, and the label used by schedulers to watch their partitions
 func (ns *NodeShuffler) updateNodeSchedulerNameAnnotation(node *v1.Node, nmNode *nodev1alpha1.NMNode, schedulerName string) error {
-	if node != nil && node.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] != schedulerName {
+	if node != nil && !nodeOfScheduler(&node.ObjectMeta, schedulerName) {
 		nodeClone := node.DeepCopy()
-		if nodeClone.Annotations == nil {
-			nodeClone.Annotations = make(map[string]string)
-		}
 		previousScheduler := node.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]
-		nodeClone.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] = schedulerName
+		setNodeScheduler(&nodeClone.ObjectMeta, schedulerName)
 		// update node
 		if _, err := ns.k8sClient.CoreV1().Nodes().Update(context.TODO(), nodeClone, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
-		if previousScheduler != "" {
-			metrics.NodeInPartitionSizeDec(previousScheduler, "node")
+		if previousScheduler != schedulerName {
+			if previousScheduler != "" {
+				metrics.NodeInPartitionSizeDec(previousScheduler, "node")
+			}
+			metrics.NodeInPartitionSizeInc(schedulerName, "node")
 		}
-		metrics.NodeInPartitionSizeInc(schedulerName, "node")
 	}
 
-	if nmNode != nil && nmNode.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] != schedulerName {
+	if nmNode != nil && !nodeOfScheduler(&nmNode.ObjectMeta, schedulerName) {
 		nmNodeClone := nmNode.DeepCopy()
-		if nmNodeClone.Annotations == nil {
-			nmNodeClone.Annotations = make(map[string]string)
-		}
 		previousScheduler := nmNodeClone.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]
-		nmNodeClone.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] = schedulerName
+		setNodeScheduler(&nmNodeClone.ObjectMeta, schedulerName)
 		// update nmNode
 		if _, err := ns.crdClient.NodeV1alpha1().NMNodes().Update(context.TODO(), nmNodeClone, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
+		if previousScheduler == schedulerName {
+			// Only the label is added.
+			return nil
+		}
 		if previousScheduler != "" {
 			metrics.NodeInPartitionSizeDec(previousScheduler, "nmnode")
 			if node != nil {
@@ -265,7 +279,52 @@ func (ns *NodeShuffler) updateNodeSchedulerNameAnnotation(node *v1.Node, nmNode
 		}
 	}
 
-	return nil
+	nodeName := ""
+	if node != nil {
+		nodeName = node.Name
+	} else if nmNode != nil {
+		nodeName = nmNode.Name
+	}
+	return ns.updateCNRSchedulerName(nodeName, schedulerName)
+}
+
+// updateCNRSchedulerName updates the annotation and the label of cnr, which has the same name as the node
+func (ns *NodeShuffler) updateCNRSchedulerName(nodeName, schedulerName string) error {
+	if ns.cnrLister == nil || len(nodeName) == 0 {
+		return nil
+	}
+	cnr, err := ns.cnrLister.Get(nodeName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if nodeOfScheduler(&cnr.ObjectMeta, schedulerName) {
+		return nil
+	}
+	cnrClone := cnr.DeepCopy()
+	setNodeScheduler(&cnrClone.ObjectMeta, schedulerName)
+	_, err = ns.katalystClient.NodeV1alpha1().CustomNodeResources().Update(context.TODO(), cnrClone, metav1.UpdateOptions{})
+	return err
+}
+
+// nodeOfScheduler checks both the annotation and the label, nodes without the label are
+// invisible to the schedulers watching their partitions by label selector.
+func nodeOfScheduler(meta *metav1.ObjectMeta, schedulerName string) bool {
+	return meta.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] == schedulerName &&
+		meta.Labels[nodeutil.GodelSchedulerNodeLabelKey] == schedulerName
+}
+
+func setNodeScheduler(meta *metav1.ObjectMeta, schedulerName string) {
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey] = schedulerName
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	meta.Labels[nodeutil.GodelSchedulerNodeLabelKey] = schedulerName
 }
 
 // ReBalanceSchedulerNodes re-balances the number of nodes among all active schedulers if necessary
@@ -292,5 +351,22 @@ func (ns *NodeShuffler) ReBalanceSchedulerNodes() {
 }
 
 // SyncUpNodeAndCNR makes sure that node and cnr with same name share the same scheduler name annotation
-// TODO: sync up scheduler name in node and cnr
-func (ns *NodeShuffler) SyncUpNodeAndCNR() {}
+func (ns *NodeShuffler) SyncUpNodeAndCNR(nodeName string) error {
+	schedulerName := ns.schedulerNameOfNode(nodeName)
+	if len(schedulerName) == 0 {
+		// The node hasn't been assigned yet, cnr is updated along with it.
+		return nil
+	}
+	return ns.updateCNRSchedulerName(nodeName, schedulerName)
+}
+
+// schedulerNameOfNode returns the scheduler name annotation of node, or nmNode if the node doesn't exist
+func (ns *NodeShuffler) schedulerNameOfNode(nodeName string) string {
+	if node, err := ns.nodeLister.Get(nodeName); err == nil {
+		return node.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]
+	}
+	if nmNode, err := ns.nmNodeLister.Get(nodeName); err == nil {
+		return nmNode.Annotations[nodeutil.GodelSchedulerNodeAnnotationKey]
+	}
+	return ""
+}
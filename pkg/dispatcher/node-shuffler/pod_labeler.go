@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node_shuffler
+
+import (
+	"sync/atomic"
+	"time"
+
+	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	nodelister "github.com/kubewharf/godel-scheduler-api/pkg/client/listers/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const podNodeNameIndex = "nodeName"
+
+// PodPartitionLabeler keeps the partition label of pods consistent with the partitions of their nodes, so that
+// the schedulers watching their partitions by label selector receive the pods on nodes moving across partitions.
+type PodPartitionLabeler struct {
+	client       kubernetes.Interface
+	podLister    corelister.PodLister
+	podIndexer   cache.Indexer
+	nodeLister   corelister.NodeLister
+	nmNodeLister nodelister.NMNodeLister
+
+	queue workqueue.RateLimitingInterface
+	// running is set once Run is called, pods are not enqueued before that so that the queue doesn't grow
+	// in the dispatchers which are not the leader.
+	running atomic.Bool
+}
+
+// NewPodPartitionLabeler creates a new PodPartitionLabeler, it must be called before the pod informer starts.
+func NewPodPartitionLabeler(client kubernetes.Interface, podInformer coreinformers.PodInformer,
+	nodeLister corelister.NodeLister, nmNodeLister nodelister.NMNodeLister,
+) *PodPartitionLabeler {
+	if err := podInformer.Informer().AddIndexers(cache.Indexers{podNodeNameIndex: indexPodByNodeName}); err != nil {
+		klog.ErrorS(err, "Failed to add node name indexer to pod informer")
+	}
+	return &PodPartitionLabeler{
+		client:       client,
+		podLister:    podInformer.Lister(),
+		podIndexer:   podInformer.Informer().GetIndexer(),
+		nodeLister:   nodeLister,
+		nmNodeLister: nmNodeLister,
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pod-partition-labeler"),
+	}
+}
+
+func indexPodByNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || len(pod.Spec.NodeName) == 0 {
+		return []string{}, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// Run runs the workers labeling pods until stopCh is closed
+func (l *PodPartitionLabeler) Run(stopCh <-chan struct{}) {
+	defer l.queue.ShutDown()
+	l.running.Store(true)
+	// Catch up with the pods changed before running.
+	pods, err := l.podLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods", "err", err)
+	}
+	for _, pod := range pods {
+		l.enqueueIfNecessary(pod)
+	}
+	go wait.Until(l.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (l *PodPartitionLabeler) worker() {
+	for l.processNextPod() {
+	}
+}
+
+func (l *PodPartitionLabeler) processNextPod() bool {
+	item, quit := l.queue.Get()
+	if quit {
+		return false
+	}
+	defer l.queue.Done(item)
+
+	key := item.(string)
+	if err := l.syncPod(key); err != nil {
+		klog.InfoS("Failed to label the partition of pod, retrying", "pod", key, "err", err)
+		l.queue.AddRateLimited(key)
+		return true
+	}
+	l.queue.Forget(key)
+	return true
+}
+
+func (l *PodPartitionLabeler) syncPod(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	pod, err := l.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	partition, nodeName := l.desiredPartition(pod)
+	if !partitionOutOfDate(pod, partition, nodeName) {
+		return nil
+	}
+
+	podCopy := pod.DeepCopy()
+	if podCopy.Labels == nil {
+		podCopy.Labels = make(map[string]string)
+	}
+	podCopy.Labels[podutil.PartitionLabelKey] = partition
+	if len(nodeName) > 0 {
+		if podCopy.Annotations == nil {
+			podCopy.Annotations = make(map[string]string)
+		}
+		podCopy.Annotations[podutil.PartitionNodeAnnotationKey] = nodeName
+	}
+	klog.V(4).InfoS("Started to label the partition of pod", "pod", klog.KObj(pod), "partition", partition)
+	return util.PatchPod(l.client, pod, podCopy)
+}
+
+// desiredPartition returns the partition the pod belongs to and the node the pod is bound to. The partition is
+// empty if the pod is neither dispatched nor bound to a node in any partition.
+func (l *PodPartitionLabeler) desiredPartition(pod *v1.Pod) (string, string) {
+	if len(pod.Spec.NodeName) == 0 {
+		return pod.Annotations[podutil.SchedulerAnnotationKey], ""
+	}
+	if node, err := l.nodeLister.Get(pod.Spec.NodeName); err == nil && len(node.Labels[nodeutil.GodelSchedulerNodeLabelKey]) > 0 {
+		return node.Labels[nodeutil.GodelSchedulerNodeLabelKey], pod.Spec.NodeName
+	}
+	if nmNode, err := l.nmNodeLister.Get(pod.Spec.NodeName); err == nil {
+		return nmNode.Labels[nodeutil.GodelSchedulerNodeLabelKey], pod.Spec.NodeName
+	}
+	return "", pod.Spec.NodeName
+}
+
+func partitionOutOfDate(pod *v1.Pod, partition, nodeName string) bool {
+	if len(partition) == 0 {
+		return false
+	}
+	return pod.Labels[podutil.PartitionLabelKey] != partition ||
+		(len(nodeName) > 0 && pod.Annotations[podutil.PartitionNodeAnnotationKey] != nodeName)
+}
+
+func (l *PodPartitionLabeler) enqueueIfNecessary(pod *v1.Pod) {
+	if !l.running.Load() {
+		return
+	}
+	partition, nodeName := l.desiredPartition(pod)
+	if !partitionOutOfDate(pod, partition, nodeName) {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		return
+	}
+	l.queue.Add(key)
+}
+
+// enqueuePodsOnNode enqueues the pods on the node whose partition changes
+func (l *PodPartitionLabeler) enqueuePodsOnNode(nodeName string) {
+	objs, err := l.podIndexer.ByIndex(podNodeNameIndex, nodeName)
+	if err != nil {
+		klog.InfoS("Failed to get pods on node", "node", nodeName, "err", err)
+		return
+	}
+	for _, obj := range objs {
+		if pod, ok := obj.(*v1.Pod); ok {
+			l.enqueueIfNecessary(pod)
+		}
+	}
+}
+
+func (l *PodPartitionLabeler) AddPod(obj interface{}) {
+	if pod, ok := obj.(*v1.Pod); ok {
+		l.enqueueIfNecessary(pod)
+	}
+}
+
+func (l *PodPartitionLabeler) UpdatePod(_, newObj interface{}) {
+	if pod, ok := newObj.(*v1.Pod); ok {
+		l.enqueueIfNecessary(pod)
+	}
+}
+
+func (l *PodPartitionLabeler) AddNode(obj interface{}) {
+	if node, ok := obj.(*v1.Node); ok {
+		l.enqueuePodsOnNode(node.Name)
+	}
+}
+
+func (l *PodPartitionLabeler) UpdateNode(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := newObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	if oldNode.Labels[nodeutil.GodelSchedulerNodeLabelKey] != newNode.Labels[nodeutil.GodelSchedulerNodeLabelKey] {
+		l.enqueuePodsOnNode(newNode.Name)
+	}
+}
+
+func (l *PodPartitionLabeler) AddNMNode(obj interface{}) {
+	if nmNode, ok := obj.(*nodev1alpha1.NMNode); ok {
+		l.enqueuePodsOnNode(nmNode.Name)
+	}
+}
+
+func (l *PodPartitionLabeler) UpdateNMNode(oldObj, newObj interface{}) {
+	oldNMNode, ok := oldObj.(*nodev1alpha1.NMNode)
+	if !ok {
+		return
+	}
+	newNMNode, ok := newObj.(*nodev1alpha1.NMNode)
+	if !ok {
+		return
+	}
+	if oldNMNode.Labels[nodeutil.GodelSchedulerNodeLabelKey] != newNMNode.Labels[nodeutil.GodelSchedulerNodeLabelKey] {
+		l.enqueuePodsOnNode(newNMNode.Name)
+	}
+}
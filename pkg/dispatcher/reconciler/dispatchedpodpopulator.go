@@ -19,6 +19,7 @@ package reconciler
 import (
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	v1 "k8s.io/client-go/listers/core/v1"
@@ -36,17 +37,20 @@ type DispatchedPodsPopulator struct {
 	podLister                v1.PodLister
 	staleDispatchedPodsQueue workqueue.Interface
 	schedulerMaintainer      *schemaintainer.SchedulerMaintainer
+	// ownsPod returns true if the pod belongs to the shards of this dispatcher.
+	ownsPod func(*corev1.Pod) bool
 }
 
 // NewDispatchedPodsPopulator creates a new DispatchedPodsPopulator struct
 func NewDispatchedPodsPopulator(schedulerName string, podLister v1.PodLister, queue workqueue.Interface,
-	maintainer *schemaintainer.SchedulerMaintainer,
+	maintainer *schemaintainer.SchedulerMaintainer, ownsPod func(*corev1.Pod) bool,
 ) *DispatchedPodsPopulator {
 	return &DispatchedPodsPopulator{
 		schedulerName:            schedulerName,
 		podLister:                podLister,
 		staleDispatchedPodsQueue: queue,
 		schedulerMaintainer:      maintainer,
+		ownsPod:                  ownsPod,
 	}
 }
 
@@ -65,7 +69,7 @@ func (dpp *DispatchedPodsPopulator) collectDispatchedPodsInInactiveSchedulers()
 	}
 
 	for _, pod := range pods {
-		if podutil.DispatchedPodOfGodel(pod, dpp.schedulerName) {
+		if podutil.DispatchedPodOfGodel(pod, dpp.schedulerName) && dpp.ownsPod(pod) {
 			schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
 			if dpp.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) || !dpp.schedulerMaintainer.SchedulerExist(schedulerName) {
 				if podKey, err := cache.MetaNamespaceKeyFunc(pod); err != nil {
@@ -52,6 +52,10 @@ type PodStateReconciler struct {
 
 	schedulerMaintainer *schemaintainer.SchedulerMaintainer
 	populator           *DispatchedPodsPopulator
+
+	// ownsPod returns true if the pod belongs to the shards of this dispatcher, only those pods are
+	// reconciled when the dispatchers are sharded.
+	ownsPod func(*corev1.Pod) bool
 }
 
 // NewPodStateReconciler creates a new PodStateReconciler struct
@@ -63,9 +67,10 @@ func NewPodStateReconciler(client kubernetes.Interface,
 	schedulerName string,
 	dispatchedPodsStore store.DispatchInfo,
 	maintainer *schemaintainer.SchedulerMaintainer,
+	ownsPod func(*corev1.Pod) bool,
 ) *PodStateReconciler {
 	staleDispatchedPodsQueue := workqueue.NewNamed("stale-dispatched-pods-queue")
-	populator := NewDispatchedPodsPopulator(schedulerName, podLister, staleDispatchedPodsQueue, maintainer)
+	populator := NewDispatchedPodsPopulator(schedulerName, podLister, staleDispatchedPodsQueue, maintainer, ownsPod)
 
 	return &PodStateReconciler{
 		schedulerName:            schedulerName,
@@ -79,6 +84,7 @@ func NewPodStateReconciler(client kubernetes.Interface,
 		populator:                populator,
 		schedulerMaintainer:      maintainer,
 		dispatchedPodsStore:      dispatchedPodsStore,
+		ownsPod:                  ownsPod,
 	}
 }
 
@@ -146,6 +152,10 @@ func (psr *PodStateReconciler) StaleDispatchedPodsSyncer() {
 }
 
 func (psr *PodStateReconciler) updateStaleDispatchedStatePod(pod *corev1.Pod) error {
+	if !psr.ownsPod(pod) {
+		// the pod is reconciled by the dispatcher owning its shard
+		return nil
+	}
 	if podutil.DispatchedPodOfGodel(pod, psr.schedulerName) {
 		schedulerName := pod.Annotations[podutil.SchedulerAnnotationKey]
 		if psr.schedulerMaintainer.IsSchedulerInInactiveQueue(schedulerName) || !psr.schedulerMaintainer.SchedulerExist(schedulerName) {
@@ -206,6 +216,10 @@ func (psr *PodStateReconciler) AbnormalStatePodsSyncer() {
 
 // updatePodState tries to update pod state if it is abnormal
 func (psr *PodStateReconciler) updateAbnormalStatePod(pod *corev1.Pod) error {
+	if !psr.ownsPod(pod) {
+		// the pod is reconciled by the dispatcher owning its shard
+		return nil
+	}
 	abnormal := podutil.AbnormalPodStateOfGodel(pod, psr.schedulerName)
 	if abnormal {
 		klog.V(3).InfoS("Reset the abnormal pod to Pending state", "pod", klog.KObj(pod))
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/internal/queue"
+	eventutil "github.com/kubewharf/godel-scheduler/pkg/util/event"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	// RedispatchInitialBackoff is the backoff before dispatching a pod again once all the schedulers have failed it.
+	RedispatchInitialBackoff = time.Second
+	// RedispatchMaxBackoff is the max backoff before dispatching a pod again once all the schedulers have failed it.
+	RedispatchMaxBackoff = 5 * time.Minute
+
+	// AllSchedulersFailedReason is the reason of the event recorded when all the schedulers have failed a pod.
+	AllSchedulersFailedReason = "AllSchedulersFailed"
+)
+
+// getRedispatchBackoff returns the backoff of a pod failed by all the schedulers. It's doubled each time the pod
+// has been sent to every scheduler again, which is estimated by the redispatch count and the number of schedulers,
+// so that the backoff survives restarts of the dispatcher.
+func getRedispatchBackoff(redispatchCount, numSchedulers int) time.Duration {
+	if numSchedulers < 1 {
+		numSchedulers = 1
+	}
+	backoff := RedispatchInitialBackoff
+	for rounds := redispatchCount / numSchedulers; rounds > 0; rounds-- {
+		backoff *= 2
+		if backoff > RedispatchMaxBackoff {
+			return RedispatchMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// backoffRedispatching puts the pod, which has been failed by all the schedulers, back to the queue after backoff.
+// It returns false if the backoff has expired and the pod should be dispatched to all the schedulers again.
+func (d *Dispatcher) backoffRedispatching(pod *v1.Pod, podInfo *queue.QueuedPodInfo, originalQueue queue.SortedQueue) bool {
+	if podInfo.RedispatchBackoffExpiration.IsZero() {
+		backoff := getRedispatchBackoff(podutil.GetRedispatchCount(pod), d.DispatchInfo.NumSchedulers())
+		podInfo.RedispatchBackoffExpiration = time.Now().Add(backoff)
+		klog.V(3).InfoS("All the schedulers have failed the pod, will dispatch it again after backoff",
+			"pod", klog.KObj(pod), "failedSchedulers", pod.Annotations[podutil.FailedSchedulersAnnotationKey], "backoff", backoff)
+		if d.recorder != nil {
+			d.recorder.Eventf(pod, nil, v1.EventTypeWarning, AllSchedulersFailedReason, eventutil.DispatchingAction,
+				"All the schedulers (%s) have failed the pod, will dispatch it to all of them again after %v",
+				pod.Annotations[podutil.FailedSchedulersAnnotationKey], backoff)
+		}
+	}
+
+	remaining := time.Until(podInfo.RedispatchBackoffExpiration)
+	if remaining <= 0 {
+		return false
+	}
+
+	d.redispatchTimersLock.Lock()
+	defer d.redispatchTimersLock.Unlock()
+	if timer, ok := d.redispatchTimers[podInfo.PodKey]; ok {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(remaining, func() {
+		d.redispatchTimersLock.Lock()
+		if d.redispatchTimers[podInfo.PodKey] != timer {
+			// cancelled or replaced by a newer backoff.
+			d.redispatchTimersLock.Unlock()
+			return
+		}
+		delete(d.redispatchTimers, podInfo.PodKey)
+		d.redispatchTimersLock.Unlock()
+
+		if !d.stillPendingAfterBackoff(pod, podInfo, originalQueue) {
+			return
+		}
+		originalQueue.AddPodInfo(podInfo)
+	})
+	d.redispatchTimers[podInfo.PodKey] = timer
+	return true
+}
+
+// stillPendingAfterBackoff checks whether the pod backing off should be put back to the queue. Pods which have been
+// deleted, recreated, dispatched, or queued again by the event handlers in the meantime are skipped.
+func (d *Dispatcher) stillPendingAfterBackoff(pod *v1.Pod, podInfo *queue.QueuedPodInfo, originalQueue queue.SortedQueue) bool {
+	current, err := d.podLister.Pods(pod.Namespace).Get(pod.Name)
+	if err != nil || current.UID != pod.UID || current.DeletionTimestamp != nil ||
+		!podutil.PendingPodOfGodel(current, d.SchedulerName) || !d.shards.OwnsPod(current) {
+		klog.V(4).InfoS("Skipped putting the pod back to the queue after redispatching backoff", "pod", podInfo.PodKey)
+		return false
+	}
+	return !originalQueue.PodInfoExist(podInfo)
+}
+
+// cancelRedispatching stops the redispatching backoff of the pod if there is one.
+func (d *Dispatcher) cancelRedispatching(podKey string) {
+	d.redispatchTimersLock.Lock()
+	defer d.redispatchTimersLock.Unlock()
+	if timer, ok := d.redispatchTimers[podKey]; ok {
+		timer.Stop()
+		delete(d.redispatchTimers, podKey)
+	}
+}
@@ -76,6 +76,14 @@ func (maintainer *SchedulerMaintainer) Run(stopCh <-chan struct{}) {
 	<-stopCh
 }
 
+// RunCleanup deletes the inactive schedulers periodically. Unlike Run, it writes the cluster-wide Scheduler objects,
+// so it must only be run by one dispatcher.
+func (maintainer *SchedulerMaintainer) RunCleanup(stopCh <-chan struct{}) {
+	go wait.Until(maintainer.DeleteInactiveSchedulers, 30*time.Second, stopCh)
+
+	<-stopCh
+}
+
 // PopulateSchedulers will populate existing schedulers to active queue
 func (maintainer *SchedulerMaintainer) PopulateSchedulers() {
 	schedulers, err := maintainer.schedulerLister.List(labels.Everything())
@@ -94,23 +102,15 @@ func (maintainer *SchedulerMaintainer) PopulateSchedulers() {
 // we can delete schedulers from one of the queues based on schedulers actual status
 func (maintainer *SchedulerMaintainer) SyncUpSchedulersStatus() {
 	activeSchedulers := maintainer.GetActiveSchedulers()
+	// The schedulers which are still there but not active are deleted by DeleteInactiveSchedulers.
 	for _, schedulerName := range activeSchedulers {
-		scheduler, err := maintainer.schedulerLister.Get(schedulerName)
+		_, err := maintainer.schedulerLister.Get(schedulerName)
 		if err != nil && !errors.IsNotFound(err) {
 			klog.InfoS("Failed to get the schedulers CRD", "schedulerName", schedulerName, "err", err)
 			continue
 		}
 		if errors.IsNotFound(err) {
 			maintainer.DeactivateScheduler(schedulerName)
-			continue
-		}
-		if !IsSchedulerActive(scheduler) {
-			klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", schedulerName)
-			// schedulers is still there and it is not active, delete it.
-			err := maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
-			if err != nil {
-				klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
-			}
 		}
 	}
 
@@ -127,13 +127,25 @@ func (maintainer *SchedulerMaintainer) SyncUpSchedulersStatus() {
 		}
 		if IsSchedulerActive(scheduler) {
 			maintainer.ActivateScheduler(scheduler.Name)
-		} else {
-			klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", schedulerName)
-			// schedulers is still there and it is not active, delete it.
-			err := maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
-			if err != nil {
-				klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
-			}
+		}
+	}
+}
+
+// DeleteInactiveSchedulers deletes the schedulers which are still there but not active.
+func (maintainer *SchedulerMaintainer) DeleteInactiveSchedulers() {
+	schedulers, err := maintainer.schedulerLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list schedulers", "err", err)
+		return
+	}
+	for _, scheduler := range schedulers {
+		if IsSchedulerActive(scheduler) {
+			continue
+		}
+		klog.V(3).InfoS("Started to delete the inactive schedulers", "schedulerName", scheduler.Name)
+		err := maintainer.crdClient.SchedulingV1alpha1().Schedulers().Delete(context.TODO(), scheduler.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			klog.InfoS("Failed to delete the inactive schedulers", "schedulerName", scheduler.Name, "err", err)
 		}
 	}
 }
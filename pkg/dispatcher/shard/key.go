@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+// KeyOfPod returns the key hashed into the shard of the pod. Pods of the same PodGroup always have
+// the same key, so a unit is dispatched by one dispatcher.
+func KeyOfPod(pod *v1.Pod, shardBy config.ShardBy) string {
+	if shardBy != config.ShardByPodGroup {
+		return pod.Namespace
+	}
+	if pgName := unitutil.GetPodGroupName(pod); len(pgName) > 0 {
+		return pod.Namespace + "/" + pgName
+	}
+	// Pods of the same owner are kept together, so the owner is dispatched to one scheduler when
+	// rescheduling is supported.
+	if owner := podutil.GetPodOwner(pod); len(owner) > 0 {
+		return owner
+	}
+	return podutil.GetPodKey(pod)
+}
+
+// ShardOfKey returns the shard of the key among the shardCount shards.
+func ShardOfKey(key string, shardCount int32) int {
+	return int(hash(key) % uint32(shardCount))
+}
@@ -0,0 +1,491 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationinformers "k8s.io/client-go/informers/coordination/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationlisters "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+)
+
+const (
+	// LeaseGroupLabelKey is the label of the Leases coordinating the shards among the dispatchers, whose value
+	// is the lease name in the sharding configuration.
+	LeaseGroupLabelKey = "godel.bytedance.com/dispatcher-lease-group"
+	// LeaseTypeLabelKey is the label telling the member Leases from the shard Leases.
+	LeaseTypeLabelKey = "godel.bytedance.com/dispatcher-lease-type"
+
+	memberLeaseType = "member"
+	shardLeaseType  = "shard"
+)
+
+// EventHandler handles the changes of the shards owned by the dispatcher. The handlers are called
+// after the ownership changes, so OwnsPod already reflects the new ownership.
+type EventHandler struct {
+	OnAcquired func(shards sets.Int)
+	OnReleased func(shards sets.Int)
+}
+
+// Manager coordinates the shards with the other dispatchers through Leases.
+//
+// Every dispatcher renews a member Lease, and the shards are assigned to the live members by
+// consistent hashing. Each shard also has a Lease recording its holder, which guarantees that a
+// shard is dispatched by at most one dispatcher: the holder releases the shard once it's assigned
+// to another member, and the shard of a member whose Lease isn't renewed for the lease duration is
+// taken over by the member it's assigned to. The member Leases of the dead members are garbage
+// collected by the live ones, since the identities aren't reused across restarts.
+type Manager struct {
+	client   kubernetes.Interface
+	identity string
+	cfg      config.ShardingConfiguration
+	clock    clock.Clock
+
+	leaseInformer cache.SharedIndexInformer
+	leaseLister   coordinationlisters.LeaseNamespaceLister
+
+	mu sync.RWMutex
+	// owned are the shards held by this dispatcher.
+	owned sets.Int
+	// shardLeases are the shard Leases written by this dispatcher but not observed by the informer yet.
+	shardLeases map[int]*coordinationv1.Lease
+	// renewTime is the last time the member Lease was renewed.
+	renewTime time.Time
+	// observedMembers records when the renewal of each member was observed locally, so that the liveness
+	// of the members doesn't depend on their clocks.
+	observedMembers map[string]observedRenewal
+
+	handlers []EventHandler
+}
+
+type observedRenewal struct {
+	renewTime  metav1.MicroTime
+	observedAt time.Time
+}
+
+// NewManager returns the shard manager of the dispatcher with the identity.
+func NewManager(client kubernetes.Interface, identity string, cfg config.ShardingConfiguration) *Manager {
+	leaseInformer := coordinationinformers.NewFilteredLeaseInformer(client, cfg.LeaseNamespace, 0, cache.Indexers{}, func(options *metav1.ListOptions) {
+		options.LabelSelector = labels.Set{LeaseGroupLabelKey: cfg.LeaseName}.String()
+	})
+	m := &Manager{
+		client:          client,
+		identity:        identity,
+		cfg:             cfg,
+		clock:           clock.RealClock{},
+		leaseInformer:   leaseInformer,
+		leaseLister:     coordinationlisters.NewLeaseLister(leaseInformer.GetIndexer()).Leases(cfg.LeaseNamespace),
+		owned:           sets.NewInt(),
+		shardLeases:     map[int]*coordinationv1.Lease{},
+		observedMembers: map[string]observedRenewal{},
+	}
+	leaseInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    m.observeLease,
+		UpdateFunc: func(_, newObj interface{}) { m.observeLease(newObj) },
+	})
+	return m
+}
+
+// AddEventHandler adds the handler of the shard ownership changes. It must be called before Run.
+func (m *Manager) AddEventHandler(handler EventHandler) {
+	m.handlers = append(m.handlers, handler)
+}
+
+// Identity returns the identity of the dispatcher.
+func (m *Manager) Identity() string {
+	return m.identity
+}
+
+// Run renews the membership and rebalances the shards until the context is done. The shards are
+// released and the membership is withdrawn on exit, so that the others take over at once.
+func (m *Manager) Run(ctx context.Context) {
+	go m.leaseInformer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), m.leaseInformer.HasSynced) {
+		klog.InfoS("Failed to sync the shard leases")
+		return
+	}
+	klog.InfoS("Started the shard manager", "identity", m.identity, "shardCount", m.cfg.ShardCount, "shardBy", m.cfg.ShardBy)
+
+	wait.Until(m.sync, m.cfg.RenewPeriod.Duration, ctx.Done())
+	m.shutdown()
+}
+
+// OwnsPod returns true if the pod belongs to a shard held by this dispatcher. A nil manager owns all the pods.
+func (m *Manager) OwnsPod(pod *v1.Pod) bool {
+	if m == nil {
+		return true
+	}
+	return m.OwnsShard(m.ShardOfPod(pod))
+}
+
+// ShardOfPod returns the shard of the pod.
+func (m *Manager) ShardOfPod(pod *v1.Pod) int {
+	return ShardOfKey(KeyOfPod(pod, m.cfg.ShardBy), m.cfg.ShardCount)
+}
+
+// OwnsShard returns true if the shard is held by this dispatcher, and the membership of this dispatcher
+// hasn't expired.
+func (m *Manager) OwnsShard(shard int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.owned.Has(shard) && m.clock.Since(m.renewTime) < m.cfg.LeaseDuration.Duration
+}
+
+// OwnedShards returns the shards held by this dispatcher.
+func (m *Manager) OwnedShards() sets.Int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return sets.NewInt(m.owned.UnsortedList()...)
+}
+
+func (m *Manager) sync() {
+	now := m.clock.Now()
+	if err := m.renewMember(now); err != nil {
+		klog.InfoS("Failed to renew the dispatcher membership", "identity", m.identity, "err", err)
+		m.mu.RLock()
+		expired := now.Sub(m.renewTime) >= m.cfg.LeaseDuration.Duration
+		m.mu.RUnlock()
+		if expired {
+			// The others may take over the shards now, so stop dispatching them.
+			m.drop(m.OwnedShards())
+		}
+		return
+	}
+
+	members, err := m.liveMembers(now)
+	if err != nil {
+		klog.InfoS("Failed to list the dispatcher members", "err", err)
+		return
+	}
+	ring := NewRing(members.List())
+
+	acquired, released := sets.NewInt(), sets.NewInt()
+	for shard := 0; shard < int(m.cfg.ShardCount); shard++ {
+		lease, err := m.leaseLister.Get(m.shardLeaseName(shard))
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.InfoS("Failed to get the shard lease", "shard", shard, "err", err)
+			continue
+		}
+		lease = m.latestShardLease(shard, lease)
+
+		holder := holderOf(lease)
+		assignee := ring.Owner(m.shardLeaseName(shard))
+		switch {
+		case holder == m.identity && assignee != m.identity:
+			// Stop dispatching the shard before releasing the lease, otherwise the assignee may dispatch it at the
+			// same time. The assignee takes it over once the lease expires even if the lease isn't released.
+			m.drop(sets.NewInt(shard))
+			if err := m.releaseShard(shard, lease); err != nil {
+				klog.InfoS("Failed to release the shard", "shard", shard, "err", err)
+			}
+			released.Insert(shard)
+		case holder == m.identity:
+			acquired.Insert(shard)
+		case assignee == m.identity && (len(holder) == 0 || !members.Has(holder)):
+			if err := m.acquireShard(shard, lease, now); err != nil {
+				klog.InfoS("Failed to acquire the shard", "shard", shard, "previousHolder", holder, "err", err)
+				continue
+			}
+			acquired.Insert(shard)
+		default:
+			// The shard is held by another member, or is waiting to be released by its holder.
+			released.Insert(shard)
+		}
+	}
+	m.drop(released)
+	m.own(acquired)
+}
+
+// renewMember creates or renews the member Lease of this dispatcher.
+func (m *Manager) renewMember(now time.Time) error {
+	leases := m.client.CoordinationV1().Leases(m.cfg.LeaseNamespace)
+	name := m.memberLeaseName()
+	renewTime := metav1.NewMicroTime(now)
+	durationSeconds := int32(m.cfg.LeaseDuration.Seconds())
+
+	lease, err := m.leaseLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: m.cfg.LeaseNamespace,
+				Labels:    m.leaseLabels(memberLeaseType),
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		if _, err = leases.Create(context.TODO(), lease, metav1.CreateOptions{}); apierrors.IsAlreadyExists(err) {
+			// The informer hasn't observed the lease yet.
+			lease, err = leases.Get(context.TODO(), name, metav1.GetOptions{})
+		} else if err == nil {
+			m.setRenewTime(now)
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = &m.identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &renewTime
+	if _, err := leases.Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	m.setRenewTime(now)
+	return nil
+}
+
+// liveMembers returns the members whose renewals have been observed within the lease duration, including
+// this dispatcher. The Leases of the other members are deleted once they expire.
+func (m *Manager) liveMembers(now time.Time) (sets.String, error) {
+	leases, err := m.leaseLister.List(labels.Set(m.leaseLabels(memberLeaseType)).AsSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	members := sets.NewString(m.identity)
+	observed := make(map[string]observedRenewal, len(leases))
+	for _, lease := range leases {
+		holder := holderOf(lease)
+		if len(holder) == 0 || lease.Spec.RenewTime == nil {
+			continue
+		}
+		renewal, ok := m.observedMembers[holder]
+		if !ok || !renewal.renewTime.Equal(lease.Spec.RenewTime) {
+			renewal = observedRenewal{renewTime: *lease.Spec.RenewTime, observedAt: now}
+		}
+		observed[holder] = renewal
+		if now.Sub(renewal.observedAt) < m.cfg.LeaseDuration.Duration {
+			members.Insert(holder)
+		} else if holder != m.identity {
+			m.deleteMemberLease(lease)
+		}
+	}
+	m.observedMembers = observed
+	return members, nil
+}
+
+// deleteMemberLease deletes the expired Lease of a dead member. The deletion is skipped if the Lease has been
+// renewed in the meantime.
+func (m *Manager) deleteMemberLease(lease *coordinationv1.Lease) {
+	resourceVersion := lease.ResourceVersion
+	err := m.client.CoordinationV1().Leases(m.cfg.LeaseNamespace).Delete(context.TODO(), lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+	})
+	if err == nil {
+		klog.V(2).InfoS("Deleted the expired member lease", "lease", klog.KObj(lease), "holder", holderOf(lease))
+	} else if !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		klog.InfoS("Failed to delete the expired member lease", "lease", klog.KObj(lease), "err", err)
+	}
+}
+
+// acquireShard takes the shard Lease, which is either missing, released or held by a dead member.
+func (m *Manager) acquireShard(shard int, lease *coordinationv1.Lease, now time.Time) error {
+	leases := m.client.CoordinationV1().Leases(m.cfg.LeaseNamespace)
+	acquireTime := metav1.NewMicroTime(now)
+
+	var err error
+	if lease == nil {
+		lease, err = leases.Create(context.TODO(), &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.shardLeaseName(shard),
+				Namespace: m.cfg.LeaseNamespace,
+				Labels:    m.leaseLabels(shardLeaseType),
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: &m.identity,
+				AcquireTime:    &acquireTime,
+			},
+		}, metav1.CreateOptions{})
+	} else {
+		lease = lease.DeepCopy()
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		transitions++
+		lease.Spec.HolderIdentity = &m.identity
+		lease.Spec.AcquireTime = &acquireTime
+		lease.Spec.LeaseTransitions = &transitions
+		// The update fails with a conflict if another member has changed the lease in the meantime.
+		lease, err = leases.Update(context.TODO(), lease, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		m.forgetShardLease(shard)
+		return err
+	}
+	klog.V(2).InfoS("Acquired the shard", "shard", shard, "identity", m.identity)
+	m.mu.Lock()
+	m.shardLeases[shard] = lease
+	m.mu.Unlock()
+	return nil
+}
+
+// releaseShard clears the holder of the shard Lease, so that the assignee can take it over at once.
+func (m *Manager) releaseShard(shard int, lease *coordinationv1.Lease) error {
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	updated, err := m.client.CoordinationV1().Leases(m.cfg.LeaseNamespace).Update(context.TODO(), lease, metav1.UpdateOptions{})
+	if err != nil {
+		m.forgetShardLease(shard)
+		return err
+	}
+	klog.V(2).InfoS("Released the shard", "lease", klog.KObj(lease), "identity", m.identity)
+	m.mu.Lock()
+	m.shardLeases[shard] = updated
+	m.mu.Unlock()
+	return nil
+}
+
+// latestShardLease returns the lease written by this dispatcher until the informer observes it, and the
+// lease in the informer cache otherwise. The resourceVersions are opaque, so they're only compared for
+// equality when the write is observed.
+func (m *Manager) latestShardLease(shard int, cached *coordinationv1.Lease) *coordinationv1.Lease {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if written := m.shardLeases[shard]; written != nil {
+		return written
+	}
+	return cached
+}
+
+// observeLease forgets the shard Lease written by this dispatcher once the informer observes it.
+func (m *Manager) observeLease(obj interface{}) {
+	lease, ok := obj.(*coordinationv1.Lease)
+	if !ok || lease.Labels[LeaseTypeLabelKey] != shardLeaseType {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for shard, written := range m.shardLeases {
+		if written.Name == lease.Name && written.ResourceVersion == lease.ResourceVersion {
+			delete(m.shardLeases, shard)
+		}
+	}
+}
+
+// forgetShardLease forgets the shard Lease written by this dispatcher after a failed write, e.g. a conflict,
+// so that the lease in the informer cache is used from now on.
+func (m *Manager) forgetShardLease(shard int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.shardLeases, shard)
+}
+
+// shutdown releases the shards and withdraws the membership of this dispatcher.
+func (m *Manager) shutdown() {
+	owned := m.OwnedShards()
+	m.drop(owned)
+	for shard := range owned {
+		cached, _ := m.leaseLister.Get(m.shardLeaseName(shard))
+		lease := m.latestShardLease(shard, cached)
+		if lease == nil || holderOf(lease) != m.identity {
+			continue
+		}
+		if err := m.releaseShard(shard, lease); err != nil {
+			klog.InfoS("Failed to release the shard on exit", "shard", shard, "err", err)
+		}
+	}
+	if err := m.client.CoordinationV1().Leases(m.cfg.LeaseNamespace).Delete(context.TODO(), m.memberLeaseName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.InfoS("Failed to delete the member lease on exit", "identity", m.identity, "err", err)
+	}
+}
+
+func (m *Manager) own(shards sets.Int) {
+	m.mu.Lock()
+	newShards := shards.Difference(m.owned)
+	m.owned = m.owned.Union(newShards)
+	m.mu.Unlock()
+	if newShards.Len() == 0 {
+		return
+	}
+	klog.InfoS("Started dispatching the shards", "shards", newShards.List(), "identity", m.identity)
+	for _, handler := range m.handlers {
+		if handler.OnAcquired != nil {
+			handler.OnAcquired(newShards)
+		}
+	}
+}
+
+func (m *Manager) drop(shards sets.Int) {
+	m.mu.Lock()
+	oldShards := shards.Intersection(m.owned)
+	m.owned = m.owned.Difference(oldShards)
+	m.mu.Unlock()
+	if oldShards.Len() == 0 {
+		return
+	}
+	klog.InfoS("Stopped dispatching the shards", "shards", oldShards.List(), "identity", m.identity)
+	for _, handler := range m.handlers {
+		if handler.OnReleased != nil {
+			handler.OnReleased(oldShards)
+		}
+	}
+}
+
+func (m *Manager) setRenewTime(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renewTime = now
+}
+
+func (m *Manager) leaseLabels(leaseType string) map[string]string {
+	return map[string]string{
+		LeaseGroupLabelKey: m.cfg.LeaseName,
+		LeaseTypeLabelKey:  leaseType,
+	}
+}
+
+func (m *Manager) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", m.cfg.LeaseName, shard)
+}
+
+// memberLeaseName returns the name of the member Lease, which is derived from the identity since the identity
+// may not be a valid object name.
+func (m *Manager) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%08x", m.cfg.LeaseName, hash(m.identity))
+}
+
+func holderOf(lease *coordinationv1.Lease) string {
+	if lease == nil || lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
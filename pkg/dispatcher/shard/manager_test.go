@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"github.com/kubewharf/godel-scheduler/pkg/dispatcher/config"
+)
+
+const testShardCount = 16
+
+func newTestManager(t *testing.T, client kubernetes.Interface, identity string, clock *testingclock.FakeClock, stopCh <-chan struct{}) *Manager {
+	cfg := config.ShardingConfiguration{Enabled: true}
+	config.SetDefaultShardingConfiguration(&cfg, "godel-system")
+	cfg.ShardCount = testShardCount
+
+	m := NewManager(client, identity, cfg)
+	m.clock = clock
+	go m.leaseInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, m.leaseInformer.HasSynced) {
+		t.Fatalf("Failed to sync the lease informer of %s", identity)
+	}
+	return m
+}
+
+// syncUntil syncs the managers until the condition holds, giving the informers time to observe the
+// leases written by the others.
+func syncUntil(t *testing.T, condition func() bool, managers ...*Manager) {
+	if err := wait.PollImmediate(20*time.Millisecond, 5*time.Second, func() (bool, error) {
+		for _, m := range managers {
+			m.sync()
+		}
+		return condition(), nil
+	}); err != nil {
+		t.Fatalf("The shards didn't converge: %v", err)
+	}
+}
+
+// partitioned returns true if every shard is owned by exactly one of the managers.
+func partitioned(managers ...*Manager) bool {
+	all := sets.NewInt()
+	for _, m := range managers {
+		owned := m.OwnedShards()
+		if owned.Len() == 0 || all.HasAny(owned.UnsortedList()...) {
+			return false
+		}
+		all.Insert(owned.UnsortedList()...)
+	}
+	return all.Len() == testShardCount
+}
+
+func TestManagerRebalancesShards(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	client := fake.NewSimpleClientset()
+	clock := testingclock.NewFakeClock(time.Now())
+
+	m1 := newTestManager(t, client, "dispatcher-1", clock, stopCh)
+	released, releasedLate := sets.NewInt(), sets.NewInt()
+	m1.AddEventHandler(EventHandler{OnReleased: func(shards sets.Int) {
+		released.Insert(shards.UnsortedList()...)
+		// The shards must be dropped while their leases are still held.
+		for shard := range shards {
+			lease, err := client.CoordinationV1().Leases("godel-system").Get(context.TODO(), m1.shardLeaseName(shard), metav1.GetOptions{})
+			if err != nil || holderOf(lease) != m1.identity {
+				releasedLate.Insert(shard)
+			}
+		}
+	}})
+	syncUntil(t, func() bool { return partitioned(m1) }, m1)
+
+	// A new member takes its shards over from the existing one.
+	m2 := newTestManager(t, client, "dispatcher-2", clock, stopCh)
+	syncUntil(t, func() bool { return partitioned(m1, m2) }, m1, m2)
+	if !released.Equal(m2.OwnedShards()) {
+		t.Errorf("Expected shards %v to be released, got %v", m2.OwnedShards().List(), released.List())
+	}
+	if releasedLate.Len() > 0 {
+		t.Errorf("Expected shards to be dropped before releasing their leases, got %v", releasedLate.List())
+	}
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+	if m1.OwnsPod(pod) == m2.OwnsPod(pod) {
+		t.Errorf("Expected the pod to be owned by exactly one member")
+	}
+	if owner := (*Manager)(nil); !owner.OwnsPod(pod) {
+		t.Errorf("Expected the pod to be owned if the dispatchers aren't sharded")
+	}
+
+	// The shards of a member that stops renewing its membership are taken over after the lease duration.
+	clock.Step(m1.cfg.LeaseDuration.Duration + time.Second)
+	for shard := range m2.OwnedShards() {
+		if m2.OwnsShard(shard) {
+			t.Errorf("Expected the expired member to stop dispatching shard %d", shard)
+		}
+	}
+	syncUntil(t, func() bool { return m1.OwnedShards().Len() == testShardCount }, m1)
+	// The member lease of the expired member is garbage collected.
+	if _, err := client.CoordinationV1().Leases("godel-system").Get(context.TODO(), m2.memberLeaseName(), metav1.GetOptions{}); err == nil {
+		t.Errorf("Expected the expired member lease to be deleted")
+	}
+	if _, err := client.CoordinationV1().Leases("godel-system").Get(context.TODO(), m1.memberLeaseName(), metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the live member lease to be kept, got %v", err)
+	}
+}
+
+func TestManagerReleasesShardsOnExit(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	client := fake.NewSimpleClientset()
+	clock := testingclock.NewFakeClock(time.Now())
+
+	m1 := newTestManager(t, client, "dispatcher-1", clock, stopCh)
+	m2 := newTestManager(t, client, "dispatcher-2", clock, stopCh)
+	syncUntil(t, func() bool { return partitioned(m1, m2) }, m1, m2)
+
+	m2.shutdown()
+	if m2.OwnedShards().Len() != 0 {
+		t.Errorf("Expected no shards to be owned after exit, got %v", m2.OwnedShards().List())
+	}
+	if _, err := client.CoordinationV1().Leases("godel-system").Get(context.TODO(), m2.memberLeaseName(), metav1.GetOptions{}); err == nil {
+		t.Errorf("Expected the member lease to be deleted on exit")
+	}
+	// The shards are taken over without waiting for the lease duration.
+	syncUntil(t, func() bool { return m1.OwnedShards().Len() == testShardCount }, m1)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodesPerMember is the number of points each member has on the ring, which keeps the
+// shards evenly spread when there are only a few members.
+const virtualNodesPerMember = 128
+
+// Ring is a consistent hash ring of the dispatcher members. When a member joins or leaves, only
+// the shards next to its points move, so the other members keep their shards.
+type Ring struct {
+	points []uint32
+	owners map[uint32]string
+}
+
+// NewRing returns the ring of the members.
+func NewRing(members []string) *Ring {
+	r := &Ring{owners: make(map[uint32]string, len(members)*virtualNodesPerMember)}
+	for _, member := range members {
+		for i := 0; i < virtualNodesPerMember; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			// Collisions are resolved deterministically so that all members build the same ring.
+			if owner, ok := r.owners[point]; ok && owner < member {
+				continue
+			} else if !ok {
+				r.points = append(r.points, point)
+			}
+			r.owners[point] = member
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Owner returns the member owning the key, which is the first member clockwise from the key on
+// the ring. It returns an empty string if the ring has no member.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// hash returns the fnv32a hash of the key with the murmur3 finalizer applied, since fnv alone doesn't
+// spread similar keys like the virtual nodes of a member well enough.
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	x := h.Sum32()
+	x ^= x >> 16
+	x *= 0x85ebca6b
+	x ^= x >> 13
+	x *= 0xc2b2ae35
+	x ^= x >> 16
+	return x
+}
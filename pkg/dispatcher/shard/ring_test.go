@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shard
+
+import (
+	"strconv"
+	"testing"
+)
+
+func ownersOf(r *Ring, shardCount int) map[string]string {
+	owners := make(map[string]string, shardCount)
+	for i := 0; i < shardCount; i++ {
+		key := "shard-" + strconv.Itoa(i)
+		owners[key] = r.Owner(key)
+	}
+	return owners
+}
+
+func TestRingOwner(t *testing.T) {
+	if owner := NewRing(nil).Owner("shard-0"); owner != "" {
+		t.Errorf("Expected no owner in an empty ring, got %q", owner)
+	}
+
+	members := []string{"dispatcher-a", "dispatcher-b", "dispatcher-c"}
+	owners := ownersOf(NewRing(members), 64)
+
+	// The ring doesn't depend on the order of the members.
+	if reordered := ownersOf(NewRing([]string{"dispatcher-c", "dispatcher-a", "dispatcher-b"}), 64); len(reordered) != len(owners) {
+		t.Fatalf("Expected %d shards, got %d", len(owners), len(reordered))
+	} else {
+		for key, owner := range owners {
+			if reordered[key] != owner {
+				t.Errorf("Expected %s to be owned by %s regardless of the member order, got %s", key, owner, reordered[key])
+			}
+		}
+	}
+
+	count := map[string]int{}
+	for _, owner := range owners {
+		count[owner]++
+	}
+	for _, member := range members {
+		if count[member] < 8 {
+			t.Errorf("Expected the shards to be spread evenly, got %d shards on %s", count[member], member)
+		}
+	}
+}
+
+func TestRingMemberLeaves(t *testing.T) {
+	owners := ownersOf(NewRing([]string{"dispatcher-a", "dispatcher-b", "dispatcher-c"}), 64)
+	remaining := ownersOf(NewRing([]string{"dispatcher-a", "dispatcher-b"}), 64)
+
+	for key, owner := range owners {
+		if owner != "dispatcher-c" && remaining[key] != owner {
+			t.Errorf("Expected %s to stay on %s when another member leaves, got %s", key, owner, remaining[key])
+		}
+		if remaining[key] == "dispatcher-c" {
+			t.Errorf("Expected %s to leave the removed member", key)
+		}
+	}
+}
@@ -22,6 +22,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 type DRFResource struct {
@@ -180,19 +182,6 @@ func (r *DRFResource) AddFromResourceList(rl v1.ResourceList) {
 
 func GetPodResourceRequest(pod *v1.Pod) *DRFResource {
 	result := &DRFResource{}
-	for _, container := range pod.Spec.Containers {
-		result.AddFromResourceList(container.Resources.Requests)
-	}
-
-	// take max_resource(sum_pod, any_init_container)
-	for _, container := range pod.Spec.InitContainers {
-		result.SetMaxResource(container.Resources.Requests)
-	}
-
-	// If Overhead is being utilized, add to the total requests for the pod
-	if pod.Spec.Overhead != nil {
-		result.AddFromResourceList(pod.Spec.Overhead)
-	}
-
+	result.AddFromResourceList(podutil.PodRequests(pod))
 	return result
 }
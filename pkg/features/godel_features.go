@@ -84,6 +84,34 @@ const (
 	//
 	// Allows to trigger resource reservation in Godel.
 	ResourceReservation featuregate.Feature = "ResourceReservation"
+
+	// alpha: for now
+	//
+	// Serves the dry-run endpoint of scheduler, which tells whether and where a unit could be scheduled right now.
+	SchedulerDryRun featuregate.Feature = "SchedulerDryRun"
+
+	// alpha: for now
+	//
+	// Reserves resources for blocked gangs for a period of time, and only allows lower priority units
+	// to be backfilled into the reserved resources if they are preemptible or finish before the deadline.
+	BackfillScheduling featuregate.Feature = "BackfillScheduling"
+
+	// alpha: for now
+	//
+	// Enforces ElasticQuota on groups of namespaces in scheduler, borrowed resources are reclaimed by preemption.
+	ElasticQuota featuregate.Feature = "ElasticQuota"
+
+	// alpha: for now
+	//
+	// Scopes the Node, NMNode, CNR and Pod informers of scheduler to its partition, nodes and pods of other partitions
+	// are watched by metadata only. The dispatcher labels pods with their partitions.
+	PartitionScopedInformers featuregate.Feature = "PartitionScopedInformers"
+
+	// alpha: for now
+	//
+	// Periodically checkpoints the caches of scheduler and binder, and restores them on restart so that
+	// scheduling can resume before informers are synced.
+	CacheCheckpoint featuregate.Feature = "CacheCheckpoint"
 )
 
 func init() {
@@ -104,4 +132,9 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	EnableColocation:                        {Default: false, PreRelease: featuregate.Alpha},
 	SupportRescheduling:                     {Default: false, PreRelease: featuregate.Alpha},
 	ResourceReservation:                     {Default: false, PreRelease: featuregate.Alpha},
+	SchedulerDryRun:                         {Default: false, PreRelease: featuregate.Alpha},
+	BackfillScheduling:                      {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuota:                            {Default: false, PreRelease: featuregate.Alpha},
+	PartitionScopedInformers:                {Default: false, PreRelease: featuregate.Alpha},
+	CacheCheckpoint:                         {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -98,6 +98,7 @@ func (c *CycleState) Clone() *CycleState {
 		return true
 	})
 	copy.readOnlyStorage = c.readOnlyStorage
+	copy.recordPluginMetrics = c.recordPluginMetrics
 
 	return copy
 }
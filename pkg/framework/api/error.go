@@ -26,9 +26,20 @@ import (
 
 // PreemptionError describe that pod can't preempt any of candidate nodes.
 // Note: we only return PreemptionError when PostPlugins return `Unschedulable` or `UnschedulableAndUnresolvable`.
-type PreemptionError string
+type PreemptionError struct {
+	// Reason doesn't contain the names of pods or nodes, so that the failures of pods can be aggregated by it.
+	Reason string
+	// Message is the detailed message of the failure, Reason is used if it's empty.
+	Message string
+}
+
+// NewPreemptionErrorFromStatus returns the PreemptionError of the failed status, whose reason is the code
+// and the failed plugin of the status.
+func NewPreemptionErrorFromStatus(status *Status) *PreemptionError {
+	return &PreemptionError{Reason: statusReason(status), Message: status.Message()}
+}
 
-func NewPreemptionError(podKey string, candidateNodes int, status *Status) PreemptionError {
+func NewPreemptionError(podKey string, candidateNodes int, status *Status) *PreemptionError {
 	reasons := make(map[string]int)
 	for _, reason := range status.Reasons() {
 		reasons[reason]++
@@ -44,11 +55,22 @@ func NewPreemptionError(podKey string, candidateNodes int, status *Status) Preem
 	}
 
 	errMsg := fmt.Sprintf("0/%v nodes are available to preempt, pod:%v, reason:%v.", candidateNodes, podKey, strings.Join(sortReasonsHistogram(), ", "))
-	return PreemptionError(errMsg)
+	return &PreemptionError{Reason: statusReason(status), Message: errMsg}
 }
 
-func (err PreemptionError) Error() string {
-	return string(err)
+func (err *PreemptionError) Error() string {
+	if len(err.Message) == 0 {
+		return err.Reason
+	}
+	return err.Message
+}
+
+// statusReason returns the code of the status, prefixed by the failed plugin if any.
+func statusReason(status *Status) string {
+	if plugin := status.FailedPlugin(); len(plugin) > 0 {
+		return fmt.Sprintf("[%s] %s", plugin, status.Code())
+	}
+	return status.Code().String()
 }
 
 // FitError describes a fit error of a pod.
@@ -92,7 +92,7 @@ const (
 )
 
 // This list should be exactly the same as the codes iota defined above in the same order.
-var codes = []string{"Success", "Error", "Unschedulable", "UnschedulableAndUnresolvable", "Wait", "Skip", "PreemptionFail", "PreemptionNotSure"}
+var codes = []string{"Success", "Error", "Unschedulable", "UnschedulableAndUnresolvable", "Wait", "Skip", "PreemptionSucceed", "PreemptionFail", "PreemptionNotSure"}
 
 func (c Code) String() string {
 	return codes[c]
@@ -117,6 +117,8 @@ type Status struct {
 	code    Code
 	reasons []string
 	err     error
+	// failedPlugin is the name of the plugin which rejected the pod.
+	failedPlugin string
 }
 
 // Code returns code of the Status.
@@ -143,6 +145,23 @@ func (s *Status) Reasons() []string {
 	return s.reasons
 }
 
+// FailedPlugin returns the name of the plugin which rejected the pod, "" if unknown.
+func (s *Status) FailedPlugin() string {
+	if s == nil {
+		return ""
+	}
+	return s.failedPlugin
+}
+
+// WithFailedPlugin sets the name of the plugin which rejected the pod, and returns the Status itself.
+func (s *Status) WithFailedPlugin(plugin string) *Status {
+	if s == nil {
+		return nil
+	}
+	s.failedPlugin = plugin
+	return s
+}
+
 // AppendReason appends given reason to the Status.
 func (s *Status) AppendReason(reason string) {
 	if s == nil {
@@ -223,6 +242,23 @@ type UnitQueueSortPlugin interface {
 	Less(*QueuedUnitInfo, *QueuedUnitInfo) bool
 }
 
+// UnitQueueSortObserver is an optional interface of UnitQueueSortPlugin. It is implemented by
+// plugins whose order depends on more than the two compared units, e.g. how long units have
+// been waiting or which units were served recently. The scheduling queue keeps them informed.
+type UnitQueueSortObserver interface {
+	// UnitAdded is called before a unit is added to the ready units of the scheduling queue.
+	UnitAdded(*QueuedUnitInfo)
+	// UnitUpdated is called before a unit in the ready units of the scheduling queue is updated.
+	UnitUpdated(oldUnit, newUnit *QueuedUnitInfo)
+	// UnitPopped is called when a unit is popped from the scheduling queue for scheduling.
+	UnitPopped(*QueuedUnitInfo)
+	// UnitDeleted is called when a unit is removed from the scheduling queue without being popped.
+	UnitDeleted(*QueuedUnitInfo)
+	// ReorderInterval returns how often the ready units need to be re-sorted because their order
+	// changes over time. Zero means the order never changes by itself.
+	ReorderInterval() time.Duration
+}
+
 type VictimSearchingPluginCollection struct {
 	forceQuickPass  bool
 	enableQuickPass bool
@@ -367,7 +403,10 @@ func (p PluginToStatus) Merge() *Status {
 
 	finalStatus := NewStatus(Success)
 	var hasUnschedulableAndUnresolvable, hasUnschedulable bool
-	for _, s := range p {
+	for plugin, s := range p {
+		if !s.IsSuccess() && (len(finalStatus.failedPlugin) == 0 || plugin < finalStatus.failedPlugin) {
+			finalStatus.failedPlugin = plugin
+		}
 		if s.Code() == Error {
 			finalStatus.err = s.AsError()
 		} else if s.Code() == UnschedulableAndUnresolvable {
@@ -93,20 +93,24 @@ func assertStatusCode(t *testing.T, code Code, value int) {
 
 func TestPluginToStatusMerge(t *testing.T) {
 	tests := []struct {
-		statusMap PluginToStatus
-		wantCode  Code
+		statusMap        PluginToStatus
+		wantCode         Code
+		wantFailedPlugin string
 	}{
 		{
-			statusMap: PluginToStatus{"p1": NewStatus(Error), "p2": NewStatus(Unschedulable)},
-			wantCode:  Error,
+			statusMap:        PluginToStatus{"p1": NewStatus(Error), "p2": NewStatus(Unschedulable)},
+			wantCode:         Error,
+			wantFailedPlugin: "p1",
 		},
 		{
-			statusMap: PluginToStatus{"p1": NewStatus(Success), "p2": NewStatus(Unschedulable)},
-			wantCode:  Unschedulable,
+			statusMap:        PluginToStatus{"p1": NewStatus(Success), "p2": NewStatus(Unschedulable)},
+			wantCode:         Unschedulable,
+			wantFailedPlugin: "p2",
 		},
 		{
-			statusMap: PluginToStatus{"p1": NewStatus(Success), "p2": NewStatus(UnschedulableAndUnresolvable), "p3": NewStatus(Unschedulable)},
-			wantCode:  UnschedulableAndUnresolvable,
+			statusMap:        PluginToStatus{"p1": NewStatus(Success), "p2": NewStatus(UnschedulableAndUnresolvable), "p3": NewStatus(Unschedulable)},
+			wantCode:         UnschedulableAndUnresolvable,
+			wantFailedPlugin: "p2",
 		},
 		{
 			wantCode: Success,
@@ -118,5 +122,8 @@ func TestPluginToStatusMerge(t *testing.T) {
 		if test.wantCode != gotStatus.Code() {
 			t.Errorf("test #%v, wantCode %v, gotCode %v", i, test.wantCode, gotStatus.Code())
 		}
+		if test.wantFailedPlugin != gotStatus.FailedPlugin() {
+			t.Errorf("test #%v, wantFailedPlugin %v, gotFailedPlugin %v", i, test.wantFailedPlugin, gotStatus.FailedPlugin())
+		}
 	}
 }
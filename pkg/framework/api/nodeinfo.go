@@ -33,7 +33,6 @@ import (
 
 	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	godelutil "github.com/kubewharf/godel-scheduler/pkg/util"
-	"github.com/kubewharf/godel-scheduler/pkg/util/features"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -105,6 +104,7 @@ type NodeInfo interface {
 	GetNumaTopologyStatus() *NumaTopologyStatus
 	GetResourcesAvailableForSharedCoresPods(unavailableNumaList []int) *Resource
 	GetResourcesRequestsOfSharedCoresPods() *Resource
+	GetGPUShareStatus() *GPUShareStatus
 
 	GetPrioritiesForPodsMayBePreempted(resourceType podutil.PodResourceType) []int64
 }
@@ -182,6 +182,9 @@ type NodeInfoImpl struct {
 
 	NumaTopologyStatus *NumaTopologyStatus
 
+	// GPUShareStatus tracks the usage of each gpu device by pods sharing gpus.
+	GPUShareStatus *GPUShareStatus
+
 	mu sync.RWMutex
 }
 
@@ -206,6 +209,7 @@ func NewNodeInfo(pods ...*v1.Pod) NodeInfo {
 	}
 	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.NonNativeResourceSchedulingSupport) {
 		ni.NumaTopologyStatus = newNumaTopologyStatus(NewResource(nil))
+		ni.GPUShareStatus = newGPUShareStatus()
 	}
 	for _, pod := range pods {
 		ni.AddPod(pod)
@@ -538,6 +542,7 @@ func (n *NodeInfoImpl) Clone() NodeInfo {
 		ImageStates:                n.ImageStates,
 		Generation:                 n.Generation,
 		NumaTopologyStatus:         n.NumaTopologyStatus.clone(),
+		GPUShareStatus:             n.GPUShareStatus.clone(),
 	}
 	if len(n.UsedPorts) > 0 {
 		// HostPortInfo is a map-in-map struct
@@ -615,6 +620,7 @@ func (n *NodeInfoImpl) update(podInfo *PodInfo, sign int64, preempt bool) {
 
 	// update non-native resources
 	n.NumaTopologyStatus.updateNonNativeResource(podInfo, sign > 0, preempt)
+	n.GPUShareStatus.updateGPUShare(podInfo, sign > 0, preempt)
 
 	// update reserved resources information.
 	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.ResourceReservation) {
@@ -667,40 +673,13 @@ func (n *NodeInfoImpl) RemovePod(pod *v1.Pod, preempt bool) error {
 	return fmt.Errorf("no corresponding pod %s in pods of node %s", pod.Name, n.getNodeName())
 }
 
+// CalculateResource returns the requests of the pod, as well as the non-zero requests of cpu and memory,
+// see podutil.PodRequests for how they are accounted.
 // resourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead
 func CalculateResource(pod *v1.Pod) (res Resource, non0CPU int64, non0Mem int64) {
-	resPtr := &res
-	for _, c := range pod.Spec.Containers {
-		resPtr.Add(c.Resources.Requests)
-		non0CPUReq, non0MemReq := godelutil.GetNonzeroRequests(&c.Resources.Requests)
-		non0CPU += non0CPUReq
-		non0Mem += non0MemReq
-		// No non-zero resources for GPUs or opaque resources.
-	}
-	for _, ic := range pod.Spec.InitContainers {
-		resPtr.SetMaxResource(ic.Resources.Requests)
-		non0CPUReq, non0MemReq := godelutil.GetNonzeroRequests(&ic.Resources.Requests)
-		if non0CPU < non0CPUReq {
-			non0CPU = non0CPUReq
-		}
-
-		if non0Mem < non0MemReq {
-			non0Mem = non0MemReq
-		}
-	}
-
-	// If Overhead is being utilized, add to the total requests for the pod
-	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
-		resPtr.Add(pod.Spec.Overhead)
-		if _, found := pod.Spec.Overhead[v1.ResourceCPU]; found {
-			non0CPU += pod.Spec.Overhead.Cpu().MilliValue()
-		}
-
-		if _, found := pod.Spec.Overhead[v1.ResourceMemory]; found {
-			non0Mem += pod.Spec.Overhead.Memory().Value()
-		}
-	}
-	return res, non0CPU, non0Mem
+	res.Add(podutil.PodRequests(pod))
+	non0Requests := podutil.PodNonZeroRequests(pod)
+	return res, non0Requests.Cpu().MilliValue(), non0Requests.Memory().Value()
 }
 
 // updateUsedPorts updates the UsedPorts of NodeInfoImpl.
@@ -778,6 +757,7 @@ func (n *NodeInfoImpl) SetCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
 
 	n.CNR = cnr
 	n.NumaTopologyStatus.parseNumaTopologyStatus(cnr, n.PodInfoMaintainer)
+	n.GPUShareStatus.parseGPUShareStatus(cnr, n.PodInfoMaintainer)
 	n.BestEffortAllocatable = NewResourceFromPtr(cnr.Status.Resources.Allocatable)
 	return nil
 }
@@ -790,6 +770,14 @@ func (n *NodeInfoImpl) GetNumaTopologyStatus() *NumaTopologyStatus {
 	return n.NumaTopologyStatus
 }
 
+// Notice that the return value cannot be modified in the place where the function is called
+func (n *NodeInfoImpl) GetGPUShareStatus() *GPUShareStatus {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.GPUShareStatus
+}
+
 func (n *NodeInfoImpl) GetResourcesAvailableForSharedCoresPods(unavailableNumaList []int) *Resource {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
@@ -1481,6 +1469,7 @@ func (n *NodeInfoImpl) RemoveCNR() {
 	n.CNR = nil
 	n.BestEffortAllocatable = &Resource{}
 	n.NumaTopologyStatus.removeCNR()
+	n.GPUShareStatus.removeCNR()
 }
 
 // GetPodKey returns the string key of a pod.
@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sort"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	godelutil "github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// defaultGPUCoreOfDevice is the gpu core of a single device if it's not reported in CNR.
+const defaultGPUCoreOfDevice = 100
+
+func newGPUShareStatus() *GPUShareStatus {
+	return &GPUShareStatus{
+		podAllocations: make(map[string]*GPUAllocation),
+		devices:        make(map[string]*GPUDeviceStatus),
+	}
+}
+
+func (s *GPUShareStatus) clone() *GPUShareStatus {
+	if s == nil {
+		return nil
+	}
+	clone := newGPUShareStatus()
+	for podKey, alloc := range s.podAllocations {
+		allocClone := *alloc
+		clone.podAllocations[podKey] = &allocClone
+	}
+	for id, device := range s.devices {
+		deviceClone := *device
+		deviceClone.users = sets.NewString(device.users.UnsortedList()...)
+		clone.devices[id] = &deviceClone
+	}
+	clone.unassignedMemory, clone.unassignedCore = s.unassignedMemory, s.unassignedCore
+	return clone
+}
+
+func (s *GPUShareStatus) updateGPUShare(podInfo *PodInfo, isAdd, preempt bool) {
+	if s == nil {
+		return
+	}
+	if isAdd {
+		s.addPod(podInfo.Pod)
+	} else {
+		s.removePod(podInfo.Pod, preempt)
+	}
+}
+
+func (s *GPUShareStatus) addPod(pod *v1.Pod) {
+	memory, core := podutil.GetGPUShareRequests(pod)
+	if memory == 0 && core == 0 {
+		return
+	}
+	podKey := podutil.GeneratePodKey(pod)
+	if alloc := s.podAllocations[podKey]; alloc != nil {
+		if alloc.agent {
+			// the allocation reported by agent is preferred.
+			return
+		}
+		s.release(podKey)
+	}
+	s.allocate(podKey, &GPUAllocation{
+		device: pod.GetAnnotations()[podutil.GPUMicroTopologyKey],
+		memory: memory,
+		core:   core,
+	})
+}
+
+// removePod releases the gpu resources used by the pod.
+// if the allocation is reported by agent, it will be kept until agent updates CNR, unless the pod is preempted.
+func (s *GPUShareStatus) removePod(pod *v1.Pod, preempt bool) {
+	podKey := podutil.GeneratePodKey(pod)
+	alloc := s.podAllocations[podKey]
+	if alloc == nil || alloc.agent && !preempt {
+		return
+	}
+	s.release(podKey)
+}
+
+func (s *GPUShareStatus) allocate(podKey string, alloc *GPUAllocation) {
+	s.podAllocations[podKey] = alloc
+	if len(alloc.device) == 0 {
+		s.unassignedMemory += alloc.memory
+		s.unassignedCore += alloc.core
+		return
+	}
+	if device := s.devices[alloc.device]; device != nil {
+		device.requestedMemory += alloc.memory
+		device.requestedCore += alloc.core
+		device.users.Insert(podKey)
+	}
+}
+
+func (s *GPUShareStatus) release(podKey string) {
+	alloc := s.podAllocations[podKey]
+	if alloc == nil {
+		return
+	}
+	delete(s.podAllocations, podKey)
+	if len(alloc.device) == 0 {
+		s.unassignedMemory -= alloc.memory
+		s.unassignedCore -= alloc.core
+		return
+	}
+	if device := s.devices[alloc.device]; device != nil {
+		device.requestedMemory -= alloc.memory
+		device.requestedCore -= alloc.core
+		device.users.Delete(podKey)
+	}
+}
+
+func (s *GPUShareStatus) removeCNR() {
+	if s == nil {
+		return
+	}
+	gpuShareStatus := newGPUShareStatus()
+	for podKey, alloc := range s.podAllocations {
+		if !alloc.agent {
+			gpuShareStatus.allocate(podKey, alloc)
+		}
+	}
+	*s = *gpuShareStatus
+}
+
+// parseGPUShareStatus rebuilds the status from gpu devices in CNR and the pods on the node.
+func (s *GPUShareStatus) parseGPUShareStatus(cnr *katalystv1alpha1.CustomNodeResource, pods *PodInfoMaintainer) {
+	if s == nil {
+		return
+	}
+
+	gpuShareStatus := newGPUShareStatus()
+	var agentAllocations []*katalystv1alpha1.Allocation
+	var agentDevices []string
+	var walk func(zones []*katalystv1alpha1.TopologyZone)
+	walk = func(zones []*katalystv1alpha1.TopologyZone) {
+		for _, zone := range zones {
+			if zone == nil {
+				continue
+			}
+			if zone.Type == katalystv1alpha1.TopologyTypeGPU {
+				gpuShareStatus.devices[zone.Name] = newGPUDeviceStatus(zone.Resources.Allocatable)
+				for _, allocation := range zone.Allocations {
+					if allocation != nil && allocation.Requests != nil {
+						agentAllocations = append(agentAllocations, allocation)
+						agentDevices = append(agentDevices, zone.Name)
+					}
+				}
+			}
+			walk(zone.Children)
+		}
+	}
+	walk(cnr.Status.TopologyZone)
+
+	for i, allocation := range agentAllocations {
+		memory, core := getGPUShareQuantities(*allocation.Requests)
+		if memory == 0 && core == 0 {
+			continue
+		}
+		gpuShareStatus.allocate(allocation.Consumer, &GPUAllocation{
+			agent:  true,
+			device: agentDevices[i],
+			memory: memory,
+			core:   core,
+		})
+	}
+	pods.Range(func(podInfo *PodInfo) {
+		gpuShareStatus.addPod(podInfo.Pod)
+	})
+
+	*s = *gpuShareStatus
+}
+
+func newGPUDeviceStatus(allocatable *v1.ResourceList) *GPUDeviceStatus {
+	device := &GPUDeviceStatus{
+		allocatableCore: defaultGPUCoreOfDevice,
+		users:           sets.NewString(),
+	}
+	if allocatable != nil {
+		memory, core := getGPUShareQuantities(*allocatable)
+		device.allocatableMemory = memory
+		if _, ok := (*allocatable)[godelutil.ResourceGPUCore]; ok {
+			device.allocatableCore = core
+		}
+	}
+	return device
+}
+
+func getGPUShareQuantities(resources v1.ResourceList) (int64, int64) {
+	var memory, core int64
+	if q, ok := resources[godelutil.ResourceGPUMemory]; ok {
+		memory = q.Value()
+	}
+	if q, ok := resources[godelutil.ResourceGPUCore]; ok {
+		core = q.Value()
+	}
+	return memory, core
+}
+
+// GetDeviceIDs returns the sorted ids of gpu devices on the node.
+func (s *GPUShareStatus) GetDeviceIDs() []string {
+	if s == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(s.devices))
+	for id := range s.devices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *GPUShareStatus) GetDevice(id string) *GPUDeviceStatus {
+	if s == nil {
+		return nil
+	}
+	return s.devices[id]
+}
+
+// GetUnassignedRequests returns the gpu memory and gpu core requested by pods which haven't been assigned to any device.
+func (s *GPUShareStatus) GetUnassignedRequests() (int64, int64) {
+	if s == nil {
+		return 0, 0
+	}
+	return s.unassignedMemory, s.unassignedCore
+}
+
+// GetAllocatable returns the gpu memory and gpu core of the device.
+func (d *GPUDeviceStatus) GetAllocatable() (int64, int64) {
+	return d.allocatableMemory, d.allocatableCore
+}
+
+// GetFree returns the gpu memory and gpu core which are not requested by any pod on the device.
+func (d *GPUDeviceStatus) GetFree() (int64, int64) {
+	return d.allocatableMemory - d.requestedMemory, d.allocatableCore - d.requestedCore
+}
+
+// GetUsers returns the number of pods on the device.
+func (d *GPUDeviceStatus) GetUsers() int {
+	return d.users.Len()
+}
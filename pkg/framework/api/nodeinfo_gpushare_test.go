@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	godelutil "github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeGPUSharePod(name, memory, core, device string) *v1.Pod {
+	requests := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	if len(memory) > 0 {
+		requests[godelutil.ResourceGPUMemory] = resource.MustParse(memory)
+	}
+	if len(core) > 0 {
+		requests[godelutil.ResourceGPUCore] = resource.MustParse(core)
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name), Annotations: map[string]string{}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}},
+		},
+	}
+	if len(device) > 0 {
+		pod.Annotations[podutil.GPUMicroTopologyKey] = device
+	}
+	return pod
+}
+
+func makeGPUCNR(devices map[string]string, allocations map[string][]*katalystv1alpha1.Allocation) *katalystv1alpha1.CustomNodeResource {
+	var children []*katalystv1alpha1.TopologyZone
+	for id, memory := range devices {
+		allocatable := v1.ResourceList{godelutil.ResourceGPUMemory: resource.MustParse(memory)}
+		children = append(children, &katalystv1alpha1.TopologyZone{
+			Type:        katalystv1alpha1.TopologyTypeGPU,
+			Name:        id,
+			Resources:   katalystv1alpha1.Resources{Allocatable: &allocatable},
+			Allocations: allocations[id],
+		})
+	}
+	return &katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "n"},
+		Status: katalystv1alpha1.CustomNodeResourceStatus{
+			TopologyZone: []*katalystv1alpha1.TopologyZone{{
+				Type:     katalystv1alpha1.TopologyTypeNuma,
+				Name:     "0",
+				Children: children,
+			}},
+		},
+	}
+}
+
+func checkGPUDevice(t *testing.T, status *GPUShareStatus, id string, expectedFreeMemory, expectedFreeCore int64, expectedUsers int) {
+	device := status.GetDevice(id)
+	if device == nil {
+		t.Fatalf("device %s not found", id)
+	}
+	freeMemory, freeCore := device.GetFree()
+	if freeMemory != expectedFreeMemory || freeCore != expectedFreeCore || device.GetUsers() != expectedUsers {
+		t.Errorf("device %s: expected free (%d, %d) with %d users, got (%d, %d) with %d users",
+			id, expectedFreeMemory, expectedFreeCore, expectedUsers, freeMemory, freeCore, device.GetUsers())
+	}
+}
+
+func TestGPUShareStatus(t *testing.T) {
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.NonNativeResourceSchedulingSupport): true})
+
+	p1 := makeGPUSharePod("p1", "4", "30", "gpu-0")
+	p2 := makeGPUSharePod("p2", "2", "", "")
+	ni := NewNodeInfo(p1, p2, makeGPUSharePod("p3", "", "", ""))
+	if ids := ni.GetGPUShareStatus().GetDeviceIDs(); len(ids) != 0 {
+		t.Errorf("expected no device before CNR is set, got: %v", ids)
+	}
+
+	agentRequests := v1.ResourceList{godelutil.ResourceGPUMemory: resource.MustParse("8")}
+	ni.SetCNR(makeGPUCNR(
+		map[string]string{"gpu-0": "16", "gpu-1": "16"},
+		map[string][]*katalystv1alpha1.Allocation{
+			"gpu-1": {{Consumer: "default/p4/p4", Requests: &agentRequests}},
+		},
+	))
+	status := ni.GetGPUShareStatus()
+	checkGPUDevice(t, status, "gpu-0", 12, 70, 1)
+	checkGPUDevice(t, status, "gpu-1", 8, 100, 1)
+	if memory, core := status.GetUnassignedRequests(); memory != 2 || core != 0 {
+		t.Errorf("expected unassigned requests (2, 0), got (%d, %d)", memory, core)
+	}
+
+	clone := ni.Clone()
+	clone.RemovePod(p1, false)
+	checkGPUDevice(t, clone.GetGPUShareStatus(), "gpu-0", 16, 100, 0)
+	checkGPUDevice(t, ni.GetGPUShareStatus(), "gpu-0", 12, 70, 1)
+
+	// allocations reported by agent are kept until CNR is updated, unless the pod is preempted.
+	p4 := makeGPUSharePod("p4", "8", "", "")
+	ni.AddPod(p4)
+	clone = ni.Clone()
+	clone.RemovePod(p4, true)
+	checkGPUDevice(t, clone.GetGPUShareStatus(), "gpu-1", 16, 100, 0)
+	ni.RemovePod(p4, false)
+	checkGPUDevice(t, ni.GetGPUShareStatus(), "gpu-1", 8, 100, 1)
+
+	ni.RemoveCNR()
+	if ids := ni.GetGPUShareStatus().GetDeviceIDs(); len(ids) != 0 {
+		t.Errorf("expected no device after CNR is removed, got: %v", ids)
+	}
+}
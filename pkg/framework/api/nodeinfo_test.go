@@ -34,9 +34,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
 	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
@@ -50,6 +52,77 @@ func fakeNodeInfo(pods ...*v1.Pod) NodeInfo {
 	return ni
 }
 
+func TestCalculateResource(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("3"),
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+					util.ResourceGPU:  resource.MustParse("2"),
+				}}},
+			},
+			Containers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("1"),
+					v1.ResourceMemory: resource.MustParse("2Gi"),
+					util.ResourceGPU:  resource.MustParse("1"),
+				}}},
+				{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("500m"),
+				}}},
+			},
+			Overhead: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("100m"),
+				v1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+		},
+	}
+
+	tests := []struct {
+		podOverhead     bool
+		expected        v1.ResourceList
+		expectedNon0CPU int64
+		expectedNon0Mem int64
+	}{
+		{
+			podOverhead: true,
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3100m"),
+				v1.ResourceMemory: resource.MustParse("2148Mi"),
+				util.ResourceGPU:  resource.MustParse("2"),
+			},
+			expectedNon0CPU: 3100,
+			expectedNon0Mem: (2048 + 200 + 100) * 1024 * 1024,
+		},
+		{
+			podOverhead: false,
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				util.ResourceGPU:  resource.MustParse("2"),
+			},
+			expectedNon0CPU: 3000,
+			expectedNon0Mem: (2048 + 200) * 1024 * 1024,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("PodOverhead=%v", tt.podOverhead), func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodOverhead, tt.podOverhead)()
+			res, non0CPU, non0Mem := CalculateResource(pod)
+
+			expected := NewResource(tt.expected)
+			if !reflect.DeepEqual(expected, &res) {
+				t.Errorf("expected requests %v, but got %v", expected, res)
+			}
+			if non0CPU != tt.expectedNon0CPU || non0Mem != tt.expectedNon0Mem {
+				t.Errorf("expected non-zero requests %v/%v, but got %v/%v",
+					tt.expectedNon0CPU, tt.expectedNon0Mem, non0CPU, non0Mem)
+			}
+		})
+	}
+}
+
 func TestNewNodeInfo(t *testing.T) {
 	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.NonNativeResourceSchedulingSupport): true})
 
@@ -153,6 +226,7 @@ func TestNewNodeInfo(t *testing.T) {
 			requestsOfSharedCores:                &Resource{},
 			socketToFreeNumasOfConflictResources: map[int]sets.Int{},
 		},
+		GPUShareStatus: newGPUShareStatus(),
 	}
 
 	ni := NewNodeInfo(pods...)
@@ -610,6 +684,7 @@ func TestNodeInfoAddPod(t *testing.T) {
 			availableOfSharedCores:               &Resource{},
 			socketToFreeNumasOfConflictResources: map[int]sets.Int{},
 		},
+		GPUShareStatus: newGPUShareStatus(),
 	}
 
 	ni := fakeNodeInfo()
@@ -765,6 +840,7 @@ func TestNodeInfoRemovePod(t *testing.T) {
 					availableOfSharedCores:               &Resource{},
 					socketToFreeNumasOfConflictResources: map[int]sets.Int{},
 				},
+				GPUShareStatus: newGPUShareStatus(),
 			},
 		},
 		{
@@ -879,6 +955,7 @@ func TestNodeInfoRemovePod(t *testing.T) {
 					availableOfSharedCores:               &Resource{},
 					socketToFreeNumasOfConflictResources: map[int]sets.Int{},
 				},
+				GPUShareStatus: newGPUShareStatus(),
 			},
 		},
 	}
@@ -19,8 +19,11 @@ package api
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 type ReservationPlaceholderMap map[string]*v1.Pod
@@ -42,3 +45,22 @@ func (p ReservationPlaceholdersOfNodes) String() string {
 	}
 	return strings.Join(nodeString, "; ")
 }
+
+// BackfillReservation holds the resources reserved on nodes for a blocked unit until the deadline.
+// Units with lower priority may only be backfilled into the reserved resources if they are preemptible
+// or expected to finish before the deadline.
+type BackfillReservation struct {
+	UnitKey  string
+	Priority int32
+	Deadline time.Time
+	// ResourceType is the resource type of the pods of the unit, the resources are reserved from
+	// the allocatable resources of this type.
+	ResourceType podutil.PodResourceType
+	// Reserved holds the reserved resources of each node.
+	Reserved map[string]*Resource
+}
+
+// Expired returns true if the reservation is no longer valid at the given time.
+func (r *BackfillReservation) Expired(now time.Time) bool {
+	return !now.Before(r.Deadline)
+}
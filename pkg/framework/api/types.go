@@ -326,6 +326,57 @@ type PodAllocation struct {
 	numaAllocations map[int]*v1.ResourceList
 }
 
+// GPUShareStatus tracks the usage of each gpu device by pods sharing gpus.
+type GPUShareStatus struct {
+	// if gpu allocation info exits in both cnr and pod, using cnr
+	// key: podNamespace/podName/podUID
+	podAllocations map[string]*GPUAllocation
+	// key: device id
+	devices map[string]*GPUDeviceStatus
+
+	// requests of pods which haven't been assigned to any device, e.g. pods assumed by scheduler.
+	unassignedMemory int64
+	unassignedCore   int64
+}
+
+func (s *GPUShareStatus) Equal(o *GPUShareStatus) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return cmp.Equal(s.podAllocations, o.podAllocations) && cmp.Equal(s.devices, o.devices) &&
+		s.unassignedMemory == o.unassignedMemory && s.unassignedCore == o.unassignedCore
+}
+
+type GPUAllocation struct {
+	agent  bool
+	device string
+	memory int64
+	core   int64
+}
+
+func (alloc *GPUAllocation) Equal(o *GPUAllocation) bool {
+	if alloc == nil || o == nil {
+		return alloc == o
+	}
+	return *alloc == *o
+}
+
+type GPUDeviceStatus struct {
+	allocatableMemory int64
+	allocatableCore   int64
+	requestedMemory   int64
+	requestedCore     int64
+	users             sets.String
+}
+
+func (d *GPUDeviceStatus) Equal(o *GPUDeviceStatus) bool {
+	if d == nil || o == nil {
+		return d == o
+	}
+	return d.allocatableMemory == o.allocatableMemory && d.allocatableCore == o.allocatableCore &&
+		d.requestedMemory == o.requestedMemory && d.requestedCore == o.requestedCore && d.users.Equal(o.users)
+}
+
 type NumaStatus struct {
 	socketId         int
 	resourceStatuses map[string]*ResourceStatus
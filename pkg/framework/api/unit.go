@@ -52,6 +52,11 @@ var (
 	_ ObservableUnit = &PodGroupUnit{}
 )
 
+// GetPodGroupUnitKey returns the key of the unit of the PodGroup.
+func GetPodGroupUnitKey(podGroup *schedulingv1a1.PodGroup) string {
+	return keyForPodGroupUnit(podGroup)
+}
+
 func keyForPodGroupUnit(podGroup *schedulingv1a1.PodGroup) string {
 	if podGroup == nil {
 		return ""
@@ -249,20 +254,36 @@ func (p *PodGroupUnit) GetRequiredAffinity() ([]UnitAffinityTerm, error) {
 	return terms, nil
 }
 
-// GetPreferredAffinity returns affinity rules specified in PodGroupAffinity.Preferred
+// GetPreferredAffinity returns affinity rules specified in PodGroupAffinity.Preferred, followed by the
+// hierarchical topology levels specified in the PodGroup annotation. Levels are appended from the loosest
+// to the tightest one, so that the tightest level is searched first while grouping nodes, and levels
+// already used by required or preferred affinity are skipped.
 func (p *PodGroupUnit) GetPreferredAffinity() ([]UnitAffinityTerm, error) {
-	if p.podGroup.Spec.Affinity == nil ||
-		p.podGroup.Spec.Affinity.PodGroupAffinity == nil {
-		return nil, nil
-	}
-	affinity := p.podGroup.Spec.Affinity.PodGroupAffinity
 	var terms []UnitAffinityTerm
-	for _, term := range affinity.Preferred {
-		if term.TopologyKey == "" {
+	existing := make(map[string]bool)
+	if p.podGroup.Spec.Affinity != nil && p.podGroup.Spec.Affinity.PodGroupAffinity != nil {
+		affinity := p.podGroup.Spec.Affinity.PodGroupAffinity
+		for _, term := range affinity.Required {
+			existing[term.TopologyKey] = true
+		}
+		for _, term := range affinity.Preferred {
+			if term.TopologyKey == "" {
+				continue
+			}
+			existing[term.TopologyKey] = true
+			terms = append(terms, UnitAffinityTerm{
+				TopologyKey: term.TopologyKey,
+			})
+		}
+	}
+
+	levels := podutil.GetTopologyLevels(p.podGroup.Annotations)
+	for i := len(levels) - 1; i >= 0; i-- {
+		if existing[levels[i]] {
 			continue
 		}
 		terms = append(terms, UnitAffinityTerm{
-			TopologyKey: term.TopologyKey,
+			TopologyKey: levels[i],
 		})
 	}
 	return terms, nil
@@ -352,6 +373,11 @@ var (
 	_ ObservableUnit = &SinglePodUnit{}
 )
 
+// GetSinglePodUnitKey returns the key of the unit of the pod which doesn't belong to a PodGroup.
+func GetSinglePodUnitKey(pod *v1.Pod) string {
+	return keyForSinglePodUnit(pod)
+}
+
 func keyForSinglePodUnit(pod *v1.Pod) string {
 	if pod == nil {
 		return ""
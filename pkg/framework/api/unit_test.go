@@ -18,6 +18,7 @@ package api
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -215,6 +216,54 @@ func TestPodGroupUnit_ReadyToBePopulated(t *testing.T) {
 	}
 }
 
+func TestPodGroupUnit_GetPreferredAffinity(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		affinity *schedulingv1a1.PodGroupAffinity
+		levels   string
+		expected []UnitAffinityTerm
+	}{
+		{
+			desc:     "topology levels are searched from the tightest one",
+			levels:   "leaf,spine,superpod",
+			expected: []UnitAffinityTerm{{TopologyKey: "superpod"}, {TopologyKey: "spine"}, {TopologyKey: "leaf"}},
+		},
+		{
+			desc: "topology levels are nested in affinity terms",
+			affinity: &schedulingv1a1.PodGroupAffinity{
+				Required:  []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: "superpod"}},
+				Preferred: []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: "zone"}},
+			},
+			levels:   "leaf,spine,superpod",
+			expected: []UnitAffinityTerm{{TopologyKey: "zone"}, {TopologyKey: "spine"}, {TopologyKey: "leaf"}},
+		},
+		{
+			desc: "no topology levels",
+			affinity: &schedulingv1a1.PodGroupAffinity{
+				Preferred: []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: "zone"}},
+			},
+			expected: []UnitAffinityTerm{{TopologyKey: "zone"}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			pg := createPodGroup(pgDefaultNamespace, pgDefaultName, pgDefaultMinMember, "")
+			if tt.affinity != nil {
+				pg.Spec.Affinity = &schedulingv1a1.Affinity{PodGroupAffinity: tt.affinity}
+			}
+			if len(tt.levels) > 0 {
+				pg.Annotations = map[string]string{podutil.TopologyLevelsAnnotationKey: tt.levels}
+			}
+			got, err := NewPodGroupUnit(pg, 0).GetPreferredAffinity()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
 func createPodGroup(namespace, name string, minMember int32, priorityClassName string) *schedulingv1a1.PodGroup {
 	pg := &schedulingv1a1.PodGroup{
 		ObjectMeta: metav1.ObjectMeta{
@@ -22,10 +22,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
-	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
-	"github.com/kubewharf/godel-scheduler/pkg/util/features"
 	v1helper "github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
@@ -63,21 +61,11 @@ type PodRequest struct {
 //	    Memory: 1G
 //
 // Result: CPU: 3, Memory: 3G
+//
+// The overhead of the pod is added as well, see podutil.PodRequests.
 func ComputePodResourceRequest(pod *v1.Pod) *PodRequest {
 	result := &PodRequest{}
-	for _, container := range pod.Spec.Containers {
-		result.Add(container.Resources.Requests)
-	}
-
-	// take max_resource(sum_pod, any_init_container)
-	for _, container := range pod.Spec.InitContainers {
-		result.SetMaxResource(container.Resources.Requests)
-	}
-
-	// If Overhead is being utilized, add to the total requests for the pod
-	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
-		result.Add(pod.Spec.Overhead)
-	}
+	result.Add(podutil.PodRequests(pod))
 
 	result.ResourceType, _ = podutil.GetPodResourceType(pod)
 	result.IgnorePodsLimit = podutil.IgnorePodsLimit(pod)
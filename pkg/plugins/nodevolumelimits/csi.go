@@ -77,7 +77,7 @@ func (pl *CSILimits) FitsCSILimits(ctx context.Context, s *framework.CycleState,
 	}
 
 	newVolumes := make(map[string]string)
-	if err := pl.filterAttachableVolumes(csiNode, pod.Spec.Volumes, pod.Namespace, newVolumes); err != nil {
+	if err := pl.filterAttachableVolumes(pod, csiNode, true /* new pod */, newVolumes); err != nil {
 		return framework.NewStatus(framework.Error, err.Error())
 	}
 
@@ -94,7 +94,7 @@ func (pl *CSILimits) FitsCSILimits(ctx context.Context, s *framework.CycleState,
 
 	attachedVolumes := make(map[string]string)
 	for _, existingPod := range nodeInfo.GetPods() {
-		if err := pl.filterAttachableVolumes(csiNode, existingPod.Pod.Spec.Volumes, existingPod.Pod.Namespace, attachedVolumes); err != nil {
+		if err := pl.filterAttachableVolumes(existingPod.Pod, csiNode, false /* existing pod */, attachedVolumes); err != nil {
 			return framework.NewStatus(framework.Error, err.Error())
 		}
 	}
@@ -127,24 +127,38 @@ func (pl *CSILimits) FitsCSILimits(ctx context.Context, s *framework.CycleState,
 }
 
 func (pl *CSILimits) filterAttachableVolumes(
-	csiNode *storagev1.CSINode, volumes []v1.Volume, namespace string, result map[string]string,
+	pod *v1.Pod, csiNode *storagev1.CSINode, newPod bool, result map[string]string,
 ) error {
-	for _, vol := range volumes {
-		// CSI volumes can only be used as persistent volumes
-		if vol.PersistentVolumeClaim == nil {
+	for i := range pod.Spec.Volumes {
+		vol := &pod.Spec.Volumes[i]
+		// CSI volumes can only be used through PVCs, either persistent or generic ephemeral volumes
+		if vol.PersistentVolumeClaim == nil && vol.Ephemeral == nil {
 			continue
 		}
-		pvcName := vol.PersistentVolumeClaim.ClaimName
+		pvcName, isEphemeral := volumeutil.PodVolumeClaimName(pod, vol)
 
 		if pvcName == "" {
 			return fmt.Errorf("PersistentVolumeClaim had no name")
 		}
 
-		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(pvcName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(pvcName)
 		if err != nil {
-			klog.V(5).InfoS("Failed to look up PVC info", "namespace", namespace, "pvcName", pvcName)
+			if newPod && isEphemeral {
+				// The new pod can't run before the ephemeral-volume controller creates its PVC.
+				return fmt.Errorf("looking up PVC %s/%s: %v", pod.Namespace, pvcName, err)
+			}
+			klog.V(5).InfoS("Failed to look up PVC info", "namespace", pod.Namespace, "pvcName", pvcName)
 			continue
 		}
+		if isEphemeral {
+			if err := volumeutil.EphemeralVolumeIsForPod(pod, pvc); err != nil {
+				if newPod {
+					return err
+				}
+				klog.V(5).InfoS("Skipped PVC not owned by the pod", "pod", klog.KObj(pod), "PVC", klog.KObj(pvc))
+				continue
+			}
+		}
 
 		driverName, volumeHandle := pl.getCSIDriverInfo(csiNode, pvc)
 		if driverName == "" || volumeHandle == "" {
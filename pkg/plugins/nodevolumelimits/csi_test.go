@@ -288,6 +288,38 @@ func TestCSILimits(t *testing.T) {
 			},
 		},
 	}
+	ephemeralVolumePod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			UID:  "12345",
+			Annotations: map[string]string{
+				podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+				podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+			},
+		},
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{
+				{
+					Name: "xyz",
+					VolumeSource: v1.VolumeSource{
+						Ephemeral: &v1.EphemeralVolumeSource{},
+					},
+				},
+			},
+		},
+	}
+	controller := true
+	ephemeralClaim := v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ephemeralVolumePod.Name + "-" + ephemeralVolumePod.Spec.Volumes[0].Name,
+			OwnerReferences: []metav1.OwnerReference{
+				{UID: ephemeralVolumePod.UID, Controller: &controller},
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{VolumeName: "csi-ebs.csi.aws.com-3"},
+	}
+	conflictingClaim := *ephemeralClaim.DeepCopy()
+	conflictingClaim.OwnerReferences = nil
 	inTreeNonMigratableOneVolPod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
@@ -317,6 +349,7 @@ func TestCSILimits(t *testing.T) {
 		test             string
 		migrationEnabled bool
 		limitSource      string
+		extraClaims      []v1.PersistentVolumeClaim
 		wantStatus       *framework.Status
 	}{
 		{
@@ -515,6 +548,54 @@ func TestCSILimits(t *testing.T) {
 			limitSource:      "csinode",
 			test:             "should not count in-tree and count csi volumes if migration is disabled (when scheduling in-tree volumes)",
 		},
+		// ephemeral volumes
+		{
+			newPod:      ephemeralVolumePod,
+			filterName:  "csi",
+			driverNames: []string{ebsCSIDriverName},
+			test:        "ephemeral volume missing",
+			wantStatus:  framework.NewStatus(framework.Error, `looking up PVC /test-xyz: persistentvolumeclaim "test-xyz" not found`),
+		},
+		{
+			newPod:      ephemeralVolumePod,
+			filterName:  "csi",
+			extraClaims: []v1.PersistentVolumeClaim{conflictingClaim},
+			driverNames: []string{ebsCSIDriverName},
+			test:        "ephemeral volume not owned",
+			wantStatus:  framework.NewStatus(framework.Error, "PVC /test-xyz was not created for pod /test (pod is not owner)"),
+		},
+		{
+			newPod:       ephemeralVolumePod,
+			existingPods: []*v1.Pod{csiEBSTwoVolPod},
+			filterName:   "csi",
+			extraClaims:  []v1.PersistentVolumeClaim{ephemeralClaim},
+			maxVols:      3,
+			driverNames:  []string{ebsCSIDriverName},
+			limitSource:  "csinode",
+			test:         "ephemeral volume fits",
+		},
+		{
+			newPod:       ephemeralVolumePod,
+			existingPods: []*v1.Pod{csiEBSTwoVolPod},
+			filterName:   "csi",
+			extraClaims:  []v1.PersistentVolumeClaim{ephemeralClaim},
+			maxVols:      2,
+			driverNames:  []string{ebsCSIDriverName},
+			limitSource:  "csinode",
+			test:         "ephemeral volume exceeds limit",
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonMaxVolumeCountExceeded),
+		},
+		{
+			newPod:       csiEBSOneVolPod,
+			existingPods: []*v1.Pod{ephemeralVolumePod, csiEBSTwoVolPod},
+			filterName:   "csi",
+			extraClaims:  []v1.PersistentVolumeClaim{ephemeralClaim},
+			maxVols:      3,
+			driverNames:  []string{ebsCSIDriverName},
+			limitSource:  "csinode",
+			test:         "ephemeral volume of existing pod is counted",
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonMaxVolumeCountExceeded),
+		},
 	}
 
 	// running attachable predicate tests with feature gate and limit present on nodes
@@ -540,7 +621,7 @@ func TestCSILimits(t *testing.T) {
 			p := &CSILimits{
 				csiNodeLister:        getFakeCSINodeLister(csiNode),
 				pvLister:             getFakeCSIPVLister(test.filterName, test.driverNames...),
-				pvcLister:            getFakeCSIPVCLister(test.filterName, "csi-sc", test.driverNames...),
+				pvcLister:            append(getFakeCSIPVCLister(test.filterName, "csi-sc", test.driverNames...), test.extraClaims...),
 				scLister:             getFakeCSIStorageClassLister("csi-sc", test.driverNames[0]),
 				randomVolumeIDPrefix: rand.String(32),
 				translator:           csitrans.New(),
@@ -196,7 +196,7 @@ func (pl *NonCSILimits) FitsNonCSILimits(ctx context.Context, s *framework.Cycle
 	}
 
 	newVolumes := make(map[string]bool)
-	if err := pl.filterVolumes(pod.Spec.Volumes, pod.Namespace, newVolumes); err != nil {
+	if err := pl.filterVolumes(pod, true /* new pod */, newVolumes); err != nil {
 		return framework.NewStatus(framework.Error, err.Error())
 	}
 
@@ -228,7 +228,7 @@ func (pl *NonCSILimits) FitsNonCSILimits(ctx context.Context, s *framework.Cycle
 	// count unique volumes
 	existingVolumes := make(map[string]bool)
 	for _, existingPod := range nodeInfo.GetPods() {
-		if err := pl.filterVolumes(existingPod.Pod.Spec.Volumes, existingPod.Pod.Namespace, existingVolumes); err != nil {
+		if err := pl.filterVolumes(existingPod.Pod, false /* existing pod */, existingVolumes); err != nil {
 			return framework.NewStatus(framework.Error, err.Error())
 		}
 	}
@@ -262,13 +262,14 @@ func (pl *NonCSILimits) FitsNonCSILimits(ctx context.Context, s *framework.Cycle
 	return nil
 }
 
-func (pl *NonCSILimits) filterVolumes(volumes []v1.Volume, namespace string, filteredVolumes map[string]bool) error {
-	for i := range volumes {
-		vol := &volumes[i]
+func (pl *NonCSILimits) filterVolumes(pod *v1.Pod, newPod bool, filteredVolumes map[string]bool) error {
+	namespace := pod.Namespace
+	for i := range pod.Spec.Volumes {
+		vol := &pod.Spec.Volumes[i]
 		if id, ok := pl.filter.FilterVolume(vol); ok {
 			filteredVolumes[id] = true
-		} else if vol.PersistentVolumeClaim != nil {
-			pvcName := vol.PersistentVolumeClaim.ClaimName
+		} else if vol.PersistentVolumeClaim != nil || vol.Ephemeral != nil {
+			pvcName, isEphemeral := volumeutil.PodVolumeClaimName(pod, vol)
 			if pvcName == "" {
 				return fmt.Errorf("PersistentVolumeClaim had no name")
 			}
@@ -280,11 +281,24 @@ func (pl *NonCSILimits) filterVolumes(volumes []v1.Volume, namespace string, fil
 
 			pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(pvcName)
 			if err != nil || pvc == nil {
+				if newPod && isEphemeral {
+					// The new pod can't run before the ephemeral-volume controller creates its PVC.
+					return fmt.Errorf("looking up PVC %s/%s: %v", namespace, pvcName, err)
+				}
 				// If the PVC is invalid, we don't count the volume because
 				// there's no guarantee that it belongs to the running predicate.
 				klog.InfoS("Failed to look up PVC info, assuming PVC doesn't match predicate when counting limits", "namespace", namespace, "PVC", pvcName, "err", err)
 				continue
 			}
+			if isEphemeral {
+				if err := volumeutil.EphemeralVolumeIsForPod(pod, pvc); err != nil {
+					if newPod {
+						return err
+					}
+					klog.V(5).InfoS("Skipped PVC not owned by the pod", "pod", klog.KObj(pod), "PVC", klog.KObj(pvc))
+					continue
+				}
+			}
 
 			pvName := pvc.Spec.VolumeName
 			if pvName == "" {
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnativeresource
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const GPUSharePodFailed = "node gpu devices not satisfy gpu share pod requests"
+
+// FeasibleGPUShare checks whether the pod sharing gpus could be placed on a single gpu device of the node.
+func FeasibleGPUShare(pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	memory, core := podutil.GetGPUShareRequests(pod)
+	if memory == 0 && core == 0 {
+		return nil
+	}
+	gpuShareStatus := nodeInfo.GetGPUShareStatus()
+	if len(SelectGPUDevice(gpuShareStatus, memory, core)) == 0 {
+		return framework.NewStatus(framework.Unschedulable, GPUSharePodFailed)
+	}
+	// pods which haven't been assigned to any device also need to be placed.
+	unassignedMemory, unassignedCore := gpuShareStatus.GetUnassignedRequests()
+	if unassignedMemory == 0 && unassignedCore == 0 {
+		return nil
+	}
+	var freeMemory, freeCore int64
+	for _, id := range gpuShareStatus.GetDeviceIDs() {
+		m, c := gpuShareStatus.GetDevice(id).GetFree()
+		freeMemory, freeCore = freeMemory+m, freeCore+c
+	}
+	if memory+unassignedMemory > freeMemory || core+unassignedCore > freeCore {
+		return framework.NewStatus(framework.Unschedulable, GPUSharePodFailed)
+	}
+	return nil
+}
+
+// SelectGPUDevice returns the device the requests should be placed on, empty if no device fits.
+// Devices are bin-packed, the most requested device which still fits the requests is selected,
+// so that the remaining devices could be kept free for larger requests.
+func SelectGPUDevice(gpuShareStatus *framework.GPUShareStatus, memory, core int64) string {
+	var selected string
+	var selectedFreeMemory, selectedFreeCore int64
+	for _, id := range gpuShareStatus.GetDeviceIDs() {
+		freeMemory, freeCore := gpuShareStatus.GetDevice(id).GetFree()
+		if freeMemory < memory || freeCore < core {
+			continue
+		}
+		if len(selected) == 0 || freeMemory < selectedFreeMemory ||
+			freeMemory == selectedFreeMemory && freeCore < selectedFreeCore {
+			selected, selectedFreeMemory, selectedFreeCore = id, freeMemory, freeCore
+		}
+	}
+	return selected
+}
+
+// ScoreGPUShare favors nodes whose selected device is the most requested after placing the pod.
+func ScoreGPUShare(pod *v1.Pod, nodeInfo framework.NodeInfo) int64 {
+	memory, core := podutil.GetGPUShareRequests(pod)
+	if memory == 0 && core == 0 {
+		return 0
+	}
+	gpuShareStatus := nodeInfo.GetGPUShareStatus()
+	id := SelectGPUDevice(gpuShareStatus, memory, core)
+	if len(id) == 0 {
+		return 0
+	}
+	device := gpuShareStatus.GetDevice(id)
+	allocatableMemory, allocatableCore := device.GetAllocatable()
+	freeMemory, freeCore := device.GetFree()
+
+	var score, count int64
+	if memory > 0 && allocatableMemory > 0 {
+		score += (allocatableMemory - freeMemory + memory) * framework.MaxNodeScore / allocatableMemory
+		count++
+	}
+	if core > 0 && allocatableCore > 0 {
+		score += (allocatableCore - freeCore + core) * framework.MaxNodeScore / allocatableCore
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return score / count
+}
+
+// AssignGPUDevice returns the device selected for the pod sharing gpus, empty if the pod doesn't share gpus.
+func AssignGPUDevice(node framework.NodeInfo, pod *v1.Pod) string {
+	memory, core := podutil.GetGPUShareRequests(pod)
+	if memory == 0 && core == 0 {
+		return ""
+	}
+	return SelectGPUDevice(node.GetGPUShareStatus(), memory, core)
+}
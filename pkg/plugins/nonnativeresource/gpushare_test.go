@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnativeresource
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+)
+
+func makeGPUSharePod(name, memory, device string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name), Annotations: map[string]string{}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{util.ResourceGPUMemory: resource.MustParse(memory)},
+			}}},
+		},
+	}
+	if len(device) > 0 {
+		pod.Annotations[podutil.GPUMicroTopologyKey] = device
+	}
+	return pod
+}
+
+func makeGPUShareNodeInfo(pods ...*v1.Pod) framework.NodeInfo {
+	var zones []*katalystv1alpha1.TopologyZone
+	for _, id := range []string{"gpu-0", "gpu-1"} {
+		allocatable := v1.ResourceList{util.ResourceGPUMemory: resource.MustParse("16")}
+		zones = append(zones, &katalystv1alpha1.TopologyZone{
+			Type:      katalystv1alpha1.TopologyTypeGPU,
+			Name:      id,
+			Resources: katalystv1alpha1.Resources{Allocatable: &allocatable},
+		})
+	}
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetCNR(&katalystv1alpha1.CustomNodeResource{
+		Status: katalystv1alpha1.CustomNodeResourceStatus{TopologyZone: zones},
+	})
+	return nodeInfo
+}
+
+func TestGPUShare(t *testing.T) {
+	utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(godelfeatures.NonNativeResourceSchedulingSupport): true})
+
+	tests := []struct {
+		name           string
+		existingPods   []*v1.Pod
+		pod            *v1.Pod
+		expectedFit    bool
+		expectedDevice string
+		expectedScore  int64
+	}{
+		{
+			name:           "pod not sharing gpus always fits",
+			existingPods:   []*v1.Pod{makeGPUSharePod("p1", "16", "gpu-0"), makeGPUSharePod("p2", "16", "gpu-1")},
+			pod:            &v1.Pod{},
+			expectedFit:    true,
+			expectedDevice: "",
+			expectedScore:  0,
+		},
+		{
+			name:           "most requested device is selected",
+			existingPods:   []*v1.Pod{makeGPUSharePod("p1", "4", "gpu-0"), makeGPUSharePod("p2", "8", "gpu-1")},
+			pod:            makeGPUSharePod("p", "4", ""),
+			expectedFit:    true,
+			expectedDevice: "gpu-1",
+			expectedScore:  75,
+		},
+		{
+			name:           "device without enough free memory is skipped",
+			existingPods:   []*v1.Pod{makeGPUSharePod("p1", "4", "gpu-0"), makeGPUSharePod("p2", "14", "gpu-1")},
+			pod:            makeGPUSharePod("p", "4", ""),
+			expectedFit:    true,
+			expectedDevice: "gpu-0",
+			expectedScore:  50,
+		},
+		{
+			name:         "pod doesn't fit if no single device has enough free memory",
+			existingPods: []*v1.Pod{makeGPUSharePod("p1", "10", "gpu-0"), makeGPUSharePod("p2", "10", "gpu-1")},
+			pod:          makeGPUSharePod("p", "8", ""),
+			expectedFit:  false,
+		},
+		{
+			name:         "pods not assigned to any device are considered",
+			existingPods: []*v1.Pod{makeGPUSharePod("p1", "10", "gpu-0"), makeGPUSharePod("p2", "16", "")},
+			pod:          makeGPUSharePod("p", "8", ""),
+			expectedFit:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := makeGPUShareNodeInfo(tt.existingPods...)
+			if status := FeasibleGPUShare(tt.pod, nodeInfo); status.IsSuccess() != tt.expectedFit {
+				t.Fatalf("expected fit: %v, got: %v", tt.expectedFit, status)
+			}
+			if !tt.expectedFit {
+				return
+			}
+			if device := AssignGPUDevice(nodeInfo, tt.pod); device != tt.expectedDevice {
+				t.Errorf("expected device: %v, got: %v", tt.expectedDevice, device)
+			}
+			if score := ScoreGPUShare(tt.pod, nodeInfo); score != tt.expectedScore {
+				t.Errorf("expected score: %v, got: %v", tt.expectedScore, score)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+)
+
+// AgingPriorityName is the name of the plugin used in the plugin registry and configurations.
+// AgingPriority boosts the priority of units according to how long they have been waiting,
+// so that low priority units will not starve forever.
+const AgingPriorityName = "AgingPriority"
+
+const (
+	DefaultAgingIntervalSeconds     int64 = 60
+	DefaultPriorityBoostPerInterval int64 = 100
+	DefaultMaxPriorityBoost         int64 = 1000
+)
+
+// AgingPriority is a plugin that sorts units by their priority boosted with waiting time.
+type AgingPriority struct {
+	agingInterval    time.Duration
+	boostPerInterval int64
+	maxBoost         int64
+	clock            util.Clock
+}
+
+var (
+	_ framework.UnitQueueSortPlugin   = &AgingPriority{}
+	_ framework.UnitQueueSortObserver = &AgingPriority{}
+)
+
+// Name returns name of the plugin.
+func (p *AgingPriority) Name() string {
+	return AgingPriorityName
+}
+
+// Less is the function used by the readyQ heap algorithm to sort units.
+// It sorts units based on QueuePriorityScore plus the priority boost, which grows by
+// boostPerInterval every agingInterval since the unit was added to the queue for the
+// first time and never exceeds maxBoost.
+func (p *AgingPriority) Less(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	compareResult := ComparePriorityForDebug(uInfo1.GetAnnotations(), uInfo2.GetAnnotations())
+	if compareResult != EQUAL {
+		return compareResult == GREATER
+	}
+	now := p.clock.Now()
+	score1, score2 := p.agedScore(uInfo1, now), p.agedScore(uInfo2, now)
+	if score1 != score2 {
+		return score1 > score2
+	}
+	return lessByRequestsAndTimestamp(uInfo1, uInfo2)
+}
+
+func (p *AgingPriority) agedScore(uInfo *framework.QueuedUnitInfo, now time.Time) float64 {
+	waiting := now.Sub(uInfo.InitialAttemptTimestamp)
+	if waiting <= 0 {
+		return uInfo.QueuePriorityScore
+	}
+	boost := int64(waiting/p.agingInterval) * p.boostPerInterval
+	if boost > p.maxBoost {
+		boost = p.maxBoost
+	}
+	return uInfo.QueuePriorityScore + float64(boost)
+}
+
+// UnitAdded does nothing since the boost only depends on the waiting time.
+func (p *AgingPriority) UnitAdded(*framework.QueuedUnitInfo) {}
+
+// UnitUpdated does nothing since the plugin keeps no state of units.
+func (p *AgingPriority) UnitUpdated(*framework.QueuedUnitInfo, *framework.QueuedUnitInfo) {}
+
+// UnitPopped does nothing since the boost only depends on the waiting time.
+func (p *AgingPriority) UnitPopped(*framework.QueuedUnitInfo) {}
+
+// UnitDeleted does nothing since the boost only depends on the waiting time.
+func (p *AgingPriority) UnitDeleted(*framework.QueuedUnitInfo) {}
+
+// ReorderInterval returns the aging interval, the order of units may change every interval.
+func (p *AgingPriority) ReorderInterval() time.Duration {
+	return p.agingInterval
+}
+
+// NewAgingPriority initializes a new plugin and returns it.
+func NewAgingPriority(obj runtime.Object) (framework.UnitQueueSortPlugin, error) {
+	args, err := getAgingPriorityArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+	p := &AgingPriority{
+		agingInterval:    time.Duration(DefaultAgingIntervalSeconds) * time.Second,
+		boostPerInterval: DefaultPriorityBoostPerInterval,
+		maxBoost:         DefaultMaxPriorityBoost,
+		clock:            util.RealClock{},
+	}
+	if args == nil {
+		return p, nil
+	}
+	if args.AgingIntervalSeconds != nil {
+		if *args.AgingIntervalSeconds <= 0 {
+			return nil, fmt.Errorf("agingIntervalSeconds should be positive, got %d", *args.AgingIntervalSeconds)
+		}
+		p.agingInterval = time.Duration(*args.AgingIntervalSeconds) * time.Second
+	}
+	if args.PriorityBoostPerInterval != nil {
+		if *args.PriorityBoostPerInterval < 0 {
+			return nil, fmt.Errorf("priorityBoostPerInterval should not be negative, got %d", *args.PriorityBoostPerInterval)
+		}
+		p.boostPerInterval = *args.PriorityBoostPerInterval
+	}
+	if args.MaxPriorityBoost != nil {
+		if *args.MaxPriorityBoost < 0 {
+			return nil, fmt.Errorf("maxPriorityBoost should not be negative, got %d", *args.MaxPriorityBoost)
+		}
+		p.maxBoost = *args.MaxPriorityBoost
+	}
+	return p, nil
+}
+
+func getAgingPriorityArgs(obj runtime.Object) (*config.AgingPriorityArgs, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	ptr, ok := obj.(*config.AgingPriorityArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type AgingPriorityArgs, got %T", obj)
+	}
+	return ptr, nil
+}
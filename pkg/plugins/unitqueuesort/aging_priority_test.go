@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"testing"
+	"time"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+var _ util.Clock = &fakeClock{}
+
+func createAgedUnit(enqueueTime time.Time, priority int32) *framework.QueuedUnitInfo {
+	u := createUnit(enqueueTime, "", priority)
+	u.InitialAttemptTimestamp = enqueueTime
+	return u
+}
+
+func TestAgingPriority_Less(t *testing.T) {
+	intervalSeconds, boost, maxBoost := int64(60), int64(10), int64(30)
+	plugin, err := NewAgingPriority(&config.AgingPriorityArgs{
+		AgingIntervalSeconds:     &intervalSeconds,
+		PriorityBoostPerInterval: &boost,
+		MaxPriorityBoost:         &maxBoost,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	plugin.(*AgingPriority).clock = &fakeClock{now: now}
+
+	for _, tt := range []struct {
+		name     string
+		u1       *framework.QueuedUnitInfo
+		u2       *framework.QueuedUnitInfo
+		expected bool
+	}{
+		{
+			name:     "no boost, u1.priority less than u2.priority",
+			u1:       createAgedUnit(now.Add(-30*time.Second), 100),
+			u2:       createAgedUnit(now, 110),
+			expected: false,
+		},
+		{
+			name:     "u1 is boosted ahead of u2 after waiting two intervals",
+			u1:       createAgedUnit(now.Add(-2*time.Minute), 100),
+			u2:       createAgedUnit(now, 110),
+			expected: true,
+		},
+		{
+			name:     "boost is capped by the ceiling",
+			u1:       createAgedUnit(now.Add(-time.Hour), 100),
+			u2:       createAgedUnit(now, 140),
+			expected: false,
+		},
+		{
+			name:     "equal aged score, earlier unit goes first",
+			u1:       createAgedUnit(now.Add(-time.Minute), 100),
+			u2:       createAgedUnit(now.Add(-30*time.Second), 110),
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plugin.Less(tt.u1, tt.u2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := plugin.(framework.UnitQueueSortObserver).ReorderInterval(); got != time.Minute {
+		t.Errorf("expected reorder interval %v, got %v", time.Minute, got)
+	}
+}
+
+func TestNewAgingPriority_InvalidArgs(t *testing.T) {
+	zero := int64(0)
+	if _, err := NewAgingPriority(&config.AgingPriorityArgs{AgingIntervalSeconds: &zero}); err == nil {
+		t.Errorf("expected error for zero agingIntervalSeconds")
+	}
+	if _, err := NewAgingPriority(&config.FairShareArgs{}); err == nil {
+		t.Errorf("expected error for args of wrong type")
+	}
+}
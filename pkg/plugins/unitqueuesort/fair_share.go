@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+// FairShareName is the name of the plugin used in the plugin registry and configurations.
+// FairShare serves the queues (namespaces by default) in round-robin within a priority band,
+// so that one queue can't flood the scheduling queue and starve the others.
+const FairShareName = "FairShare"
+
+// FairShare is a plugin that sorts units by priority, and then in round-robin across queues.
+//
+// Every unit entering the readyQ gets a tag, which is one more than the latest tag of its
+// queue in the same priority band, but never less than the tag of the last popped unit
+// in that band. Units with smaller tags go first, so the n-th unit of every queue is served
+// before the (n+1)-th unit of any queue, and queues that were idle don't get credit for it.
+type FairShare struct {
+	queueAnnotationKey string
+
+	lock sync.Mutex
+	// bands holds the round-robin state for each priority band.
+	bands map[float64]*fairShareBand
+	// tags holds the tags of units waiting in the readyQ.
+	tags map[string]fairShareTag
+}
+
+type fairShareBand struct {
+	// virtualTime is the tag of the last popped unit.
+	virtualTime uint64
+	// lastTags holds the latest tag handed out for each queue.
+	lastTags map[string]uint64
+	// numUnits is the number of tagged units in this band.
+	numUnits int
+}
+
+type fairShareTag struct {
+	band  float64
+	value uint64
+}
+
+var (
+	_ framework.UnitQueueSortPlugin   = &FairShare{}
+	_ framework.UnitQueueSortObserver = &FairShare{}
+)
+
+// Name returns name of the plugin.
+func (p *FairShare) Name() string {
+	return FairShareName
+}
+
+// Less is the function used by the readyQ heap algorithm to sort units.
+// It sorts units based on QueuePriorityScore. When scores are equal, it uses
+// the round-robin tags of units, which are handed out when units are added.
+func (p *FairShare) Less(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	compareResult := ComparePriorityForDebug(uInfo1.GetAnnotations(), uInfo2.GetAnnotations())
+	if compareResult != EQUAL {
+		return compareResult == GREATER
+	}
+	score1, score2 := uInfo1.QueuePriorityScore, uInfo2.QueuePriorityScore
+	if score1 != score2 {
+		return score1 > score2
+	}
+	tag1, tag2 := p.getTag(uInfo1), p.getTag(uInfo2)
+	if tag1 != tag2 {
+		return tag1 < tag2
+	}
+	return lessByRequestsAndTimestamp(uInfo1, uInfo2)
+}
+
+// queueOf returns the queue the unit belongs to.
+func (p *FairShare) queueOf(uInfo *framework.QueuedUnitInfo) string {
+	if len(p.queueAnnotationKey) > 0 {
+		if queue, ok := uInfo.GetAnnotations()[p.queueAnnotationKey]; ok {
+			return queue
+		}
+	}
+	return uInfo.GetNamespace()
+}
+
+// getTag returns the round-robin tag of the unit. Units without tags go after the tagged ones.
+func (p *FairShare) getTag(uInfo *framework.QueuedUnitInfo) uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if tag, ok := p.tags[uInfo.UnitKey]; ok {
+		return tag.value
+	}
+	return math.MaxUint64
+}
+
+// tagLocked hands out a new tag to the unit in the band of its QueuePriorityScore.
+// NOTE: this function assumes lock has been acquired in caller.
+func (p *FairShare) tagLocked(uInfo *framework.QueuedUnitInfo) {
+	band, ok := p.bands[uInfo.QueuePriorityScore]
+	if !ok {
+		band = &fairShareBand{lastTags: map[string]uint64{}}
+		p.bands[uInfo.QueuePriorityScore] = band
+	}
+	queue := p.queueOf(uInfo)
+	value := band.lastTags[queue]
+	if value < band.virtualTime {
+		value = band.virtualTime
+	}
+	value++
+	band.lastTags[queue] = value
+	band.numUnits++
+	p.tags[uInfo.UnitKey] = fairShareTag{band: uInfo.QueuePriorityScore, value: value}
+}
+
+// UnitAdded hands out a tag to the unit if it doesn't have one.
+func (p *FairShare) UnitAdded(uInfo *framework.QueuedUnitInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.tags[uInfo.UnitKey]; !ok {
+		p.tagLocked(uInfo)
+	}
+}
+
+// UnitUpdated keeps the tag of the unit, unless the unit moved to another priority band
+// where it gets a new tag.
+func (p *FairShare) UnitUpdated(_, newUnit *framework.QueuedUnitInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if tag, ok := p.tags[newUnit.UnitKey]; ok {
+		if tag.band == newUnit.QueuePriorityScore {
+			return
+		}
+		p.untagLocked(newUnit.UnitKey, tag)
+	}
+	p.tagLocked(newUnit)
+}
+
+// UnitPopped advances the virtual time of the band to the tag of the popped unit.
+// The unit will get a new tag if it comes back to the readyQ.
+func (p *FairShare) UnitPopped(uInfo *framework.QueuedUnitInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	tag, ok := p.tags[uInfo.UnitKey]
+	if !ok {
+		return
+	}
+	band := p.bands[tag.band]
+	if tag.value > band.virtualTime {
+		band.virtualTime = tag.value
+		// Queues whose latest tag falls behind the virtual time are the same as those without tags.
+		for queue, value := range band.lastTags {
+			if value <= band.virtualTime {
+				delete(band.lastTags, queue)
+			}
+		}
+	}
+	p.untagLocked(uInfo.UnitKey, tag)
+}
+
+// UnitDeleted removes the tag of the deleted unit.
+func (p *FairShare) UnitDeleted(uInfo *framework.QueuedUnitInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if tag, ok := p.tags[uInfo.UnitKey]; ok {
+		p.untagLocked(uInfo.UnitKey, tag)
+	}
+}
+
+// untagLocked removes the tag of the unit, the band is dropped once it has no tagged units.
+// NOTE: this function assumes lock has been acquired in caller.
+func (p *FairShare) untagLocked(unitKey string, tag fairShareTag) {
+	delete(p.tags, unitKey)
+	band := p.bands[tag.band]
+	band.numUnits--
+	if band.numUnits <= 0 {
+		delete(p.bands, tag.band)
+	}
+}
+
+// ReorderInterval returns zero since the tags don't change while units are waiting in the readyQ.
+func (p *FairShare) ReorderInterval() time.Duration {
+	return 0
+}
+
+// NewFairShare initializes a new plugin and returns it.
+func NewFairShare(obj runtime.Object) (framework.UnitQueueSortPlugin, error) {
+	args, err := getFairShareArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+	p := &FairShare{
+		bands: map[float64]*fairShareBand{},
+		tags:  map[string]fairShareTag{},
+	}
+	if args != nil {
+		p.queueAnnotationKey = args.QueueAnnotationKey
+	}
+	return p, nil
+}
+
+func getFairShareArgs(obj runtime.Object) (*config.FairShareArgs, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	ptr, ok := obj.(*config.FairShareArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type FairShareArgs, got %T", obj)
+	}
+	return ptr, nil
+}
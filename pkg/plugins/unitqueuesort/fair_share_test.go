@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+func createNamespacedUnit(namespace, name string, timestamp time.Time, priority int32, annotations map[string]string) *framework.QueuedUnitInfo {
+	pg := v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(timestamp),
+		},
+	}
+	unit := framework.NewPodGroupUnit(&pg, priority)
+	return &framework.QueuedUnitInfo{
+		UnitKey:            unit.GetKey(),
+		ScheduleUnit:       unit,
+		Timestamp:          timestamp,
+		QueuePriorityScore: float64(priority),
+	}
+}
+
+func sortedKeys(plugin framework.UnitQueueSortPlugin, units []*framework.QueuedUnitInfo) []string {
+	sort.SliceStable(units, func(i, j int) bool {
+		return plugin.Less(units[i], units[j])
+	})
+	keys := make([]string, 0, len(units))
+	for _, u := range units {
+		keys = append(keys, u.GetNamespace()+"/"+u.GetName())
+	}
+	return keys
+}
+
+func TestFairShare_RoundRobin(t *testing.T) {
+	plugin, err := NewFairShare(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Now()
+	// Namespace a floods the queue before namespace b comes.
+	units := []*framework.QueuedUnitInfo{
+		createNamespacedUnit("a", "1", t0, 10, nil),
+		createNamespacedUnit("a", "2", t0.Add(time.Second), 10, nil),
+		createNamespacedUnit("a", "3", t0.Add(2*time.Second), 10, nil),
+		createNamespacedUnit("b", "1", t0.Add(3*time.Second), 10, nil),
+		createNamespacedUnit("b", "2", t0.Add(4*time.Second), 10, nil),
+		createNamespacedUnit("c", "1", t0.Add(5*time.Second), 100, nil),
+	}
+	for _, u := range units {
+		plugin.(framework.UnitQueueSortObserver).UnitAdded(u)
+	}
+	got := sortedKeys(plugin, units)
+	expected := []string{"c/1", "a/1", "b/1", "a/2", "b/2", "a/3"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, got)
+		}
+	}
+
+	// units have been sorted in place.
+	// Pop a/1 and b/1, then a new unit of namespace d should not go ahead of a/2 and b/2
+	// which have been waiting, but should not be behind a/3 either.
+	observer := plugin.(framework.UnitQueueSortObserver)
+	observer.UnitPopped(units[1])
+	observer.UnitPopped(units[2])
+	units = append([]*framework.QueuedUnitInfo{units[0]}, units[3:]...)
+	newUnit := createNamespacedUnit("d", "1", t0.Add(6*time.Second), 10, nil)
+	observer.UnitAdded(newUnit)
+	units = append(units, newUnit)
+	got = sortedKeys(plugin, units)
+	expected = []string{"c/1", "a/2", "b/2", "d/1", "a/3"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, got)
+		}
+	}
+
+	// Sorting doesn't hand out tags, and updating a unit within its band keeps its tag.
+	fs := plugin.(*FairShare)
+	if len(fs.tags) != len(units) {
+		t.Errorf("expected %d tags, got %v", len(units), fs.tags)
+	}
+	observer.UnitUpdated(units[1], units[1])
+	got = sortedKeys(plugin, units)
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, got)
+		}
+	}
+
+	for _, u := range units {
+		observer.UnitDeleted(u)
+	}
+	if len(fs.tags) != 0 || len(fs.bands) != 0 {
+		t.Errorf("expected all state to be cleaned up, got tags %v bands %v", fs.tags, fs.bands)
+	}
+}
+
+func TestFairShare_QueueAnnotation(t *testing.T) {
+	queueKey := "godel.bytedance.com/queue"
+	plugin, err := NewFairShare(&config.FairShareArgs{QueueAnnotationKey: queueKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Now()
+	units := []*framework.QueuedUnitInfo{
+		createNamespacedUnit("a", "1", t0, 10, map[string]string{queueKey: "q1"}),
+		createNamespacedUnit("b", "1", t0.Add(time.Second), 10, map[string]string{queueKey: "q1"}),
+		createNamespacedUnit("c", "1", t0.Add(2*time.Second), 10, map[string]string{queueKey: "q2"}),
+	}
+	for _, u := range units {
+		plugin.(framework.UnitQueueSortObserver).UnitAdded(u)
+	}
+	got := sortedKeys(plugin, units)
+	expected := []string{"a/1", "c/1", "b/1"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestFairShare_UnitUpdated(t *testing.T) {
+	plugin, err := NewFairShare(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := plugin.(framework.UnitQueueSortObserver)
+	fs := plugin.(*FairShare)
+	t0 := time.Now()
+	oldUnit := createNamespacedUnit("a", "1", t0, 10, nil)
+	observer.UnitAdded(oldUnit)
+
+	// The unit moves to another priority band and gets a tag there.
+	newUnit := createNamespacedUnit("a", "1", t0, 20, nil)
+	observer.UnitUpdated(oldUnit, newUnit)
+	if tag := fs.tags[newUnit.UnitKey]; tag.band != 20 {
+		t.Errorf("expected unit to be tagged in band 20, got %v", tag)
+	}
+	if _, ok := fs.bands[10]; ok {
+		t.Errorf("expected band 10 to be dropped, got %v", fs.bands)
+	}
+
+	observer.UnitDeleted(newUnit)
+	if len(fs.tags) != 0 || len(fs.bands) != 0 {
+		t.Errorf("expected all state to be cleaned up, got tags %v bands %v", fs.tags, fs.bands)
+	}
+}
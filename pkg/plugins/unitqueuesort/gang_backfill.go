@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
+)
+
+// GangBackfillName is the name of the plugin used in the plugin registry and configurations.
+// GangBackfill detects gang units that keep failing at the head of the queue and lets other
+// units with the same priority be scheduled ahead of them.
+const GangBackfillName = "GangBackfill"
+
+const DefaultHeadOfLineBlockingAttempts = 3
+
+// GangBackfill is a plugin that sorts units by priority, and backfills the units blocked by
+// head-of-line blocking gang units.
+type GangBackfill struct {
+	blockingAttempts int
+	enableBackfill   bool
+}
+
+var (
+	_ framework.UnitQueueSortPlugin   = &GangBackfill{}
+	_ framework.UnitQueueSortObserver = &GangBackfill{}
+)
+
+// Name returns name of the plugin.
+func (p *GangBackfill) Name() string {
+	return GangBackfillName
+}
+
+// Less is the function used by the readyQ heap algorithm to sort units.
+// It sorts units based on QueuePriorityScore. When scores are equal and backfill is enabled,
+// units blocked by head-of-line blocking gang units go ahead of those gang units.
+func (p *GangBackfill) Less(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	compareResult := ComparePriorityForDebug(uInfo1.GetAnnotations(), uInfo2.GetAnnotations())
+	if compareResult != EQUAL {
+		return compareResult == GREATER
+	}
+	score1, score2 := uInfo1.QueuePriorityScore, uInfo2.QueuePriorityScore
+	if score1 != score2 {
+		return score1 > score2
+	}
+	if p.enableBackfill {
+		blocking1, blocking2 := p.isBlocking(uInfo1), p.isBlocking(uInfo2)
+		if blocking1 != blocking2 {
+			return blocking2
+		}
+	}
+	return lessByRequestsAndTimestamp(uInfo1, uInfo2)
+}
+
+// isBlocking returns true if the unit is a gang unit that failed too many times.
+// In order not to starve the gang unit, it is considered blocking in every other
+// round of blockingAttempts attempts, e.g. with blockingAttempts = 3, the unit is
+// considered blocking after attempts 3~5, 9~11 and so on.
+func (p *GangBackfill) isBlocking(uInfo *framework.QueuedUnitInfo) bool {
+	if !p.exceedsBlockingAttempts(uInfo) {
+		return false
+	}
+	return (uInfo.Attempts/p.blockingAttempts)%2 == 1
+}
+
+// exceedsBlockingAttempts returns true if the unit is a gang unit that failed at least blockingAttempts times.
+func (p *GangBackfill) exceedsBlockingAttempts(uInfo *framework.QueuedUnitInfo) bool {
+	return uInfo.Type() == framework.PodGroupUnitType && uInfo.Attempts >= p.blockingAttempts
+}
+
+// UnitAdded does nothing since the plugin keeps no state of units.
+func (p *GangBackfill) UnitAdded(*framework.QueuedUnitInfo) {}
+
+// UnitUpdated does nothing since the plugin keeps no state of units.
+func (p *GangBackfill) UnitUpdated(*framework.QueuedUnitInfo, *framework.QueuedUnitInfo) {}
+
+// UnitPopped reports the gang units that are blocking the head of the queue.
+func (p *GangBackfill) UnitPopped(uInfo *framework.QueuedUnitInfo) {
+	if p.exceedsBlockingAttempts(uInfo) {
+		metrics.HeadOfLineBlockingUnitsInc(uInfo.GetUnitProperty())
+		klog.V(3).InfoS("Detected head-of-line blocking gang unit", "unitKey", uInfo.UnitKey, "attempts", uInfo.Attempts, "numPods", uInfo.NumPods(), "backfill", p.enableBackfill)
+	}
+}
+
+// UnitDeleted does nothing since the plugin keeps no state of units.
+func (p *GangBackfill) UnitDeleted(*framework.QueuedUnitInfo) {}
+
+// ReorderInterval returns zero since the attempts of units don't change while they are waiting in the readyQ.
+func (p *GangBackfill) ReorderInterval() time.Duration {
+	return 0
+}
+
+// NewGangBackfill initializes a new plugin and returns it.
+func NewGangBackfill(obj runtime.Object) (framework.UnitQueueSortPlugin, error) {
+	args, err := getGangBackfillArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+	p := &GangBackfill{
+		blockingAttempts: DefaultHeadOfLineBlockingAttempts,
+		enableBackfill:   true,
+	}
+	if args == nil {
+		return p, nil
+	}
+	if args.HeadOfLineBlockingAttempts != nil {
+		if *args.HeadOfLineBlockingAttempts <= 0 {
+			return nil, fmt.Errorf("headOfLineBlockingAttempts should be positive, got %d", *args.HeadOfLineBlockingAttempts)
+		}
+		p.blockingAttempts = *args.HeadOfLineBlockingAttempts
+	}
+	if args.EnableBackfill != nil {
+		p.enableBackfill = *args.EnableBackfill
+	}
+	return p, nil
+}
+
+func getGangBackfillArgs(obj runtime.Object) (*config.GangBackfillArgs, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	ptr, ok := obj.(*config.GangBackfillArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type GangBackfillArgs, got %T", obj)
+	}
+	return ptr, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitqueuesort
+
+import (
+	"testing"
+	"time"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+)
+
+func createAttemptedUnit(timestamp time.Time, priority int32, attempts int) *framework.QueuedUnitInfo {
+	u := createUnit(timestamp, "", priority)
+	u.Attempts = attempts
+	return u
+}
+
+func TestGangBackfill_Less(t *testing.T) {
+	t1 := time.Now()
+	t2 := t1.Add(time.Second)
+	disabled := false
+	for _, tt := range []struct {
+		name     string
+		args     *config.GangBackfillArgs
+		u1       *framework.QueuedUnitInfo
+		u2       *framework.QueuedUnitInfo
+		expected bool
+	}{
+		{
+			name:     "blocking gang goes behind other unit with the same priority",
+			u1:       createAttemptedUnit(t1, 10, 3),
+			u2:       createAttemptedUnit(t2, 10, 0),
+			expected: false,
+		},
+		{
+			name:     "blocking gang still goes ahead of unit with lower priority",
+			u1:       createAttemptedUnit(t1, 100, 5),
+			u2:       createAttemptedUnit(t2, 10, 0),
+			expected: true,
+		},
+		{
+			name:     "gang is not blocking before reaching the attempts",
+			u1:       createAttemptedUnit(t1, 10, 2),
+			u2:       createAttemptedUnit(t2, 10, 0),
+			expected: true,
+		},
+		{
+			name:     "gang gets another chance at the head after a round of blocking",
+			u1:       createAttemptedUnit(t1, 10, 6),
+			u2:       createAttemptedUnit(t2, 10, 0),
+			expected: true,
+		},
+		{
+			name:     "backfill disabled",
+			args:     &config.GangBackfillArgs{EnableBackfill: &disabled},
+			u1:       createAttemptedUnit(t1, 10, 3),
+			u2:       createAttemptedUnit(t2, 10, 0),
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := NewGangBackfill(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := plugin.Less(tt.u1, tt.u2); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGangBackfill_ExceedsBlockingAttempts(t *testing.T) {
+	plugin, err := NewGangBackfill(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gb := plugin.(*GangBackfill)
+	t1 := time.Now()
+	for _, tt := range []struct {
+		attempts int
+		expected bool
+	}{
+		{attempts: 2, expected: false},
+		{attempts: 3, expected: true},
+		{attempts: 4, expected: true},
+	} {
+		u := createAttemptedUnit(t1, 10, tt.attempts)
+		if got := gb.exceedsBlockingAttempts(u); got != tt.expected {
+			t.Errorf("attempts %d: expected %v, got %v", tt.attempts, tt.expected, got)
+		}
+	}
+}
@@ -18,6 +18,12 @@ package unitqueuesort
 
 import (
 	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 const (
@@ -53,3 +59,26 @@ func ComparePriorityForDebug(annotations1, annotations2 map[string]string) int {
 		return GREATER
 	}
 }
+
+// lessByRequestsAndTimestamp is the tie-breaker shared by unit queue sort plugins once the units are
+// in the same priority band. Single pods requesting more resources go first, then earlier units.
+func lessByRequestsAndTimestamp(uInfo1 *framework.QueuedUnitInfo, uInfo2 *framework.QueuedUnitInfo) bool {
+	if uInfo1.Type() == framework.SinglePodUnitType && uInfo2.Type() == framework.SinglePodUnitType {
+		// We can ensure that `len(unitInfo.GetPods()) > 0` in scheduling queue.
+		pInfo1, pInfo2 := uInfo1.GetPods()[0], uInfo2.GetPods()[0]
+
+		p1CPURequest := podutil.GetPodRequest(pInfo1.Pod, v1.ResourceCPU, resource.DecimalSI)
+		p2CPURequest := podutil.GetPodRequest(pInfo2.Pod, v1.ResourceCPU, resource.DecimalSI)
+		if p1CPURequest.MilliValue() != p2CPURequest.MilliValue() {
+			return p1CPURequest.MilliValue() > p2CPURequest.MilliValue()
+		}
+
+		p1MemoryRequest := podutil.GetPodRequest(pInfo1.Pod, v1.ResourceMemory, resource.BinarySI)
+		p2MemoryRequest := podutil.GetPodRequest(pInfo2.Pod, v1.ResourceMemory, resource.BinarySI)
+		if p1MemoryRequest.Value() != p2MemoryRequest.Value() {
+			return p1MemoryRequest.Value() > p2MemoryRequest.Value()
+		}
+	}
+
+	return uInfo1.Timestamp.Before(uInfo2.Timestamp)
+}
@@ -51,6 +51,9 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NodeResourcesBalancedAllocatedArgs{},
 		&LocalStoragePoolCheckerArgs{},
 		&LoadAwareArgs{},
+		&AgingPriorityArgs{},
+		&FairShareArgs{},
+		&GangBackfillArgs{},
 	)
 	return nil
 }
@@ -128,6 +128,10 @@ const (
 	DefaultMaxWaitingDeletionDuration = 120
 
 	DefaultReservationTimeOutSeconds = 60
+
+	// DefaultPluginMetricsSamplePercent is the default percentage of scheduling cycles
+	// in which per-plugin metrics are recorded.
+	DefaultPluginMetricsSamplePercent = 100
 )
 
 var DefaultBindAddress = net.JoinHostPort(DefaultGodelSchedulerAddress, strconv.Itoa(DefaultInsecureSchedulerPort))
@@ -172,6 +176,9 @@ type GodelSchedulerConfiguration struct {
 	SubClusterKey *string
 	// reserved resources will be released after a period of time.
 	ReservationTimeOutSeconds int64
+	// PluginMetricsSamplePercent is the percentage of scheduling cycles in which the duration and
+	// status of every plugin is recorded. 0 disables plugin metrics, 100 records every cycle.
+	PluginMetricsSamplePercent *int32
 
 	// TODO: update the comment
 	// Profiles are scheduling profiles that kube-scheduler supports. Pods can
@@ -259,3 +259,42 @@ type LoadAwareArgs struct {
 	// Is CPU scaling factor is 80, estimated CPU = 80 / 100 * request.cpu
 	EstimatedScalingFactors map[v1.ResourceName]int64 `json:"estimatedScalingFactors,omitempty"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AgingPriorityArgs holds arguments used to configure the AgingPriority unit queue sort plugin.
+type AgingPriorityArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// AgingIntervalSeconds is the waiting time after which a unit gets one more priority boost.
+	AgingIntervalSeconds *int64 `json:"agingIntervalSeconds,omitempty"`
+	// PriorityBoostPerInterval is the priority added to a unit for every AgingIntervalSeconds it has been waiting.
+	PriorityBoostPerInterval *int64 `json:"priorityBoostPerInterval,omitempty"`
+	// MaxPriorityBoost is the ceiling of the priority boost a unit can get from aging.
+	MaxPriorityBoost *int64 `json:"maxPriorityBoost,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FairShareArgs holds arguments used to configure the FairShare unit queue sort plugin.
+type FairShareArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// QueueAnnotationKey is the annotation of unit whose value identifies the queue the unit belongs to.
+	// Units without the annotation, or all units if it is empty, are grouped by namespace.
+	QueueAnnotationKey string `json:"queueAnnotationKey,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GangBackfillArgs holds arguments used to configure the GangBackfill unit queue sort plugin.
+type GangBackfillArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// HeadOfLineBlockingAttempts is the number of failed attempts after which a gang unit
+	// is considered to block the head of the queue.
+	HeadOfLineBlockingAttempts *int `json:"headOfLineBlockingAttempts,omitempty"`
+	// EnableBackfill indicates whether the other units with the same priority should be
+	// scheduled ahead of a blocking gang unit.
+	EnableBackfill *bool `json:"enableBackfill,omitempty"`
+}
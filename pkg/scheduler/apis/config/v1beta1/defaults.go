@@ -143,6 +143,10 @@ func SetDefaults_GodelSchedulerConfiguration(obj *GodelSchedulerConfiguration) {
 		if obj.ReservationTimeOutSeconds <= 0 {
 			obj.ReservationTimeOutSeconds = config.DefaultReservationTimeOutSeconds
 		}
+		if obj.PluginMetricsSamplePercent == nil {
+			defaultValue := int32(config.DefaultPluginMetricsSamplePercent)
+			obj.PluginMetricsSamplePercent = &defaultValue
+		}
 	}
 	// 5. Godel Profiles
 	{
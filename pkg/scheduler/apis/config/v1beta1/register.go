@@ -53,6 +53,9 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&config.NodeResourcesBalancedAllocatedArgs{},
 		&config.LocalStoragePoolCheckerArgs{},
 		&config.LoadAwareArgs{},
+		&config.AgingPriorityArgs{},
+		&config.FairShareArgs{},
+		&config.GangBackfillArgs{},
 	)
 	return nil
 }
@@ -66,6 +66,9 @@ type GodelSchedulerConfiguration struct {
 	Tracer *tracing.TracerConfiguration
 	// reserved resources will be released after a period of time.
 	ReservationTimeOutSeconds int64 `json:"reservationTimeOutSeconds,omitempty"`
+	// PluginMetricsSamplePercent is the percentage of scheduling cycles in which the duration and
+	// status of every plugin is recorded. 0 disables plugin metrics, 100 records every cycle.
+	PluginMetricsSamplePercent *int32 `json:"pluginMetricsSamplePercent,omitempty"`
 
 	// TODO: update the comment
 	// Profiles are scheduling profiles that kube-scheduler supports. Pods can
@@ -72,6 +72,7 @@ func autoConvert_v1beta1_GodelSchedulerConfiguration_To_config_GodelSchedulerCon
 	out.SubClusterKey = (*string)(unsafe.Pointer(in.SubClusterKey))
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
 	out.ReservationTimeOutSeconds = in.ReservationTimeOutSeconds
+	out.PluginMetricsSamplePercent = (*int32)(unsafe.Pointer(in.PluginMetricsSamplePercent))
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(config.GodelSchedulerProfile)
@@ -112,6 +113,7 @@ func autoConvert_config_GodelSchedulerConfiguration_To_v1beta1_GodelSchedulerCon
 	out.Tracer = (*tracing.TracerConfiguration)(unsafe.Pointer(in.Tracer))
 	out.SubClusterKey = (*string)(unsafe.Pointer(in.SubClusterKey))
 	out.ReservationTimeOutSeconds = in.ReservationTimeOutSeconds
+	out.PluginMetricsSamplePercent = (*int32)(unsafe.Pointer(in.PluginMetricsSamplePercent))
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(GodelSchedulerProfile)
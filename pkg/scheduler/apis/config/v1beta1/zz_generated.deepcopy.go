@@ -47,6 +47,11 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 		in, out := &in.Tracer, &out.Tracer
 		*out = (*in).DeepCopy()
 	}
+	if in.PluginMetricsSamplePercent != nil {
+		in, out := &in.PluginMetricsSamplePercent, &out.PluginMetricsSamplePercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(GodelSchedulerProfile)
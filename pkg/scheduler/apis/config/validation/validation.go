@@ -61,6 +61,9 @@ func ValidateGodelSchedulerConfiguration(cc *config.GodelSchedulerConfiguration)
 		if cc.ReservationTimeOutSeconds <= 0 {
 			errs = append(errs, field.Invalid(field.NewPath("ReservationTimeOutSeconds"), cc.ReservationTimeOutSeconds, "ReservationTimeOutSeconds == 0"))
 		}
+		if cc.PluginMetricsSamplePercent != nil && (*cc.PluginMetricsSamplePercent < 0 || *cc.PluginMetricsSamplePercent > 100) {
+			errs = append(errs, field.Invalid(field.NewPath("pluginMetricsSamplePercent"), *cc.PluginMetricsSamplePercent, "not in valid range [0-100]"))
+		}
 		// TODO: Restore the following logic.
 		// if cc.SubClusterKey == nil || len(*cc.SubClusterKey) == 0 {
 		// 	errs = append(errs, field.Required(field.NewPath("subClusterKey"), ""))
@@ -26,6 +26,106 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgingPriorityArgs) DeepCopyInto(out *AgingPriorityArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.AgingIntervalSeconds != nil {
+		in, out := &in.AgingIntervalSeconds, &out.AgingIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PriorityBoostPerInterval != nil {
+		in, out := &in.PriorityBoostPerInterval, &out.PriorityBoostPerInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPriorityBoost != nil {
+		in, out := &in.MaxPriorityBoost, &out.MaxPriorityBoost
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgingPriorityArgs.
+func (in *AgingPriorityArgs) DeepCopy() *AgingPriorityArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(AgingPriorityArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgingPriorityArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FairShareArgs) DeepCopyInto(out *FairShareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FairShareArgs.
+func (in *FairShareArgs) DeepCopy() *FairShareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(FairShareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FairShareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangBackfillArgs) DeepCopyInto(out *GangBackfillArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.HeadOfLineBlockingAttempts != nil {
+		in, out := &in.HeadOfLineBlockingAttempts, &out.HeadOfLineBlockingAttempts
+		*out = new(int)
+		**out = **in
+	}
+	if in.EnableBackfill != nil {
+		in, out := &in.EnableBackfill, &out.EnableBackfill
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangBackfillArgs.
+func (in *GangBackfillArgs) DeepCopy() *GangBackfillArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(GangBackfillArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GangBackfillArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfiguration) {
 	*out = *in
@@ -47,6 +147,11 @@ func (in *GodelSchedulerConfiguration) DeepCopyInto(out *GodelSchedulerConfigura
 		*out = new(string)
 		**out = **in
 	}
+	if in.PluginMetricsSamplePercent != nil {
+		in, out := &in.PluginMetricsSamplePercent, &out.PluginMetricsSamplePercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.DefaultProfile != nil {
 		in, out := &in.DefaultProfile, &out.DefaultProfile
 		*out = new(GodelSchedulerProfile)
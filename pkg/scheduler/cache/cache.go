@@ -23,14 +23,17 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
 	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
 	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
+	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
 	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/unit_status_store"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
@@ -59,6 +62,11 @@ type schedulerCache struct {
 	mu      *sync.RWMutex
 
 	cacheMetrics *cacheMetrics
+
+	// reconciler is set when the cache is restored from checkpoint, until it's reconciled with informers.
+	reconciler *commoncache.CheckpointReconciler
+	// objects is set when CacheCheckpoint is enabled, to record the objects not kept by stores as they are.
+	objects *commoncache.CheckpointObjects
 }
 
 func newSchedulerCache(handler commoncache.CacheHandler) *schedulerCache {
@@ -72,6 +80,9 @@ func newSchedulerCache(handler commoncache.CacheHandler) *schedulerCache {
 
 		cacheMetrics: cacheMetrics,
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.CacheCheckpoint) {
+		sc.objects = commoncache.NewCheckpointObjects()
+	}
 
 	// NodeStore and PodStore are mandatory, so we don't care if they are nil.
 	nodeStore, podStore := sc.CommonStoresSwitch.Find(nodestore.Name), sc.CommonStoresSwitch.Find(podstore.Name)
@@ -184,6 +195,22 @@ func (cache *schedulerCache) GetUnitSchedulingStatus(unitKey string) unitstatus.
 	return cache.CommonStoresSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore).GetUnitSchedulingStatus(unitKey)
 }
 
+func (cache *schedulerCache) SetBackfillReservation(r *framework.BackfillReservation) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if s := cache.CommonStoresSwitch.Find(reservationstore.Name); s != nil {
+		s.(*reservationstore.ReservationStore).SetBackfillReservation(r)
+	}
+}
+
+func (cache *schedulerCache) RemoveBackfillReservation(unitKey string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if s := cache.CommonStoresSwitch.Find(reservationstore.Name); s != nil {
+		s.(*reservationstore.ReservationStore).RemoveBackfillReservation(unitKey)
+	}
+}
+
 func (cache *schedulerCache) FinishReserving(pod *v1.Pod) error {
 	return cache.finishReserving(pod, time.Now())
 }
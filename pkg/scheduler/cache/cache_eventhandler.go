@@ -21,7 +21,6 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 
-	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
@@ -93,27 +92,3 @@ func (cache *schedulerCache) RemovePod(pod *v1.Pod) error {
 	}
 	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePod(pod) })
 }
-
-func (cache *schedulerCache) AddReservation(request *schedulingv1a1.Reservation) error {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
-		return cs.AddReservation(request)
-	})
-}
-
-func (cache *schedulerCache) UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reservation) error {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
-		return cs.UpdateReservation(oldRequest, newRequest)
-	})
-}
-
-func (cache *schedulerCache) DeleteReservation(request *schedulingv1a1.Reservation) error {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
-		return cs.DeleteReservation(request)
-	})
-}
@@ -0,0 +1,525 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
+	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
+	podgroupstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/podgroup_store"
+	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/unit_status_store"
+	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+)
+
+var _ commoncache.Checkpointer = &schedulerCache{}
+
+func (cache *schedulerCache) Checkpoint() *commoncache.Checkpoint {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	checkpoint := &commoncache.Checkpoint{
+		Version:       commoncache.CheckpointVersion,
+		ComponentName: cache.handler.ComponentName(),
+		Timestamp:     metav1.Now(),
+	}
+
+	nodeStore := cache.CommonStoresSwitch.Find(nodestore.Name).(*nodestore.NodeStore)
+	nodeStore.Store.Range(func(_ string, obj generationstore.StoredObj) {
+		nodeInfo := obj.(framework.NodeInfo)
+		if node := nodeInfo.GetNode(); node != nil {
+			checkpoint.Nodes = append(checkpoint.Nodes, node)
+		}
+		if nmNode := nodeInfo.GetNMNode(); nmNode != nil {
+			checkpoint.NMNodes = append(checkpoint.NMNodes, nmNode)
+		}
+		if cnr := nodeInfo.GetCNR(); cnr != nil {
+			checkpoint.CNRs = append(checkpoint.CNRs, cnr)
+		}
+	})
+	if s := cache.CommonStoresSwitch.Find(podgroupstore.Name); s != nil {
+		checkpoint.PodGroups = s.(*podgroupstore.PodGroupStore).ListPodGroups()
+	}
+	podStore := cache.CommonStoresSwitch.Find(podstore.Name).(*podstore.PodStore)
+	for key, ps := range podStore.PodStates {
+		if podStore.AssumedPods[key] {
+			checkpoint.AssumedPods = append(checkpoint.AssumedPods, ps.Pod)
+			continue
+		}
+		checkpoint.Pods = append(checkpoint.Pods, ps.Pod)
+	}
+	checkpoint.Units = cache.CommonStoresSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore).Store.GetUnitSchedulingStatuses()
+	cache.objects.Fill(checkpoint)
+
+	return checkpoint
+}
+
+func (cache *schedulerCache) Restore(checkpoint *commoncache.Checkpoint) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.reconciler != nil {
+		return fmt.Errorf("cache has been restored")
+	}
+	reconciler := commoncache.NewCheckpointReconciler()
+	restore := func(kind string, obj metav1.Object, f func(cs commonstore.Store) error) {
+		if err := cache.CommonStoresSwitch.Range(f); err != nil {
+			klog.InfoS("Failed to restore object from checkpoint", "kind", kind, "object", klog.KObj(obj), "err", err)
+			return
+		}
+		reconciler.Track(kind, obj)
+	}
+	for _, node := range checkpoint.Nodes {
+		restore(commoncache.CheckpointKindNode, node, func(cs commonstore.Store) error { return cs.AddNode(node) })
+	}
+	for _, nmNode := range checkpoint.NMNodes {
+		restore(commoncache.CheckpointKindNMNode, nmNode, func(cs commonstore.Store) error { return cs.AddNMNode(nmNode) })
+	}
+	for _, cnr := range checkpoint.CNRs {
+		restore(commoncache.CheckpointKindCNR, cnr, func(cs commonstore.Store) error { return cs.AddCNR(cnr) })
+	}
+	for _, podGroup := range checkpoint.PodGroups {
+		restore(commoncache.CheckpointKindPodGroup, podGroup, func(cs commonstore.Store) error { return cs.AddPodGroup(podGroup) })
+	}
+	for _, pod := range checkpoint.Pods {
+		restore(commoncache.CheckpointKindPod, pod, func(cs commonstore.Store) error { return cs.AddPod(pod) })
+	}
+	// The bindings of the assumed pods ended with the previous process, so they expire after the assumed TTL
+	// unless the informers confirm them before.
+	podStore := cache.CommonStoresSwitch.Find(podstore.Name).(*podstore.PodStore)
+	now := time.Now()
+	for _, pod := range checkpoint.AssumedPods {
+		restore(commoncache.CheckpointKindPod, pod, func(cs commonstore.Store) error { return cs.AssumePod(&framework.CachePodInfo{Pod: pod}) })
+		if err := podStore.FinishReserving(pod, now); err != nil {
+			klog.InfoS("Failed to finish reserving assumed pod restored from checkpoint", "pod", klog.KObj(pod), "err", err)
+		}
+	}
+	// The objects below are recorded again, since they are not kept as they are by the stores.
+	for _, pdb := range checkpoint.PDBs {
+		cache.objects.Set(commoncache.CheckpointKindPDB, pdb)
+		restore(commoncache.CheckpointKindPDB, pdb, func(cs commonstore.Store) error { return cs.AddPDB(pdb) })
+	}
+	for _, owner := range checkpoint.Owners {
+		cache.objects.SetOwner(owner.Type, owner.Key, owner.Labels)
+		if err := cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddOwner(owner.Type, owner.Key, owner.Labels) }); err != nil {
+			klog.InfoS("Failed to restore owner from checkpoint", "type", owner.Type, "key", owner.Key, "err", err)
+			continue
+		}
+		reconciler.TrackOwner(owner)
+	}
+	for _, reservation := range checkpoint.Reservations {
+		cache.objects.Set(commoncache.CheckpointKindReservation, reservation)
+		restore(commoncache.CheckpointKindReservation, reservation, func(cs commonstore.Store) error { return cs.AddReservation(reservation) })
+	}
+	for _, movement := range checkpoint.Movements {
+		cache.objects.Set(commoncache.CheckpointKindMovement, movement)
+		restore(commoncache.CheckpointKindMovement, movement, func(cs commonstore.Store) error { return cs.AddMovement(movement) })
+	}
+	for _, quota := range checkpoint.ElasticQuotas {
+		cache.objects.Set(commoncache.CheckpointKindElasticQuota, quota)
+		restore(commoncache.CheckpointKindElasticQuota, quota, func(cs commonstore.Store) error { return cs.AddElasticQuota(quota) })
+	}
+	unitStatusStore := cache.CommonStoresSwitch.Find(unitstatusstore.Name).(*unitstatusstore.UnitStatusStore)
+	for unitKey, status := range checkpoint.Units {
+		unitStatusStore.SetUnitSchedulingStatus(unitKey, status)
+	}
+	cache.reconciler = reconciler
+
+	klog.InfoS("Restored scheduler cache from checkpoint", "timestamp", checkpoint.Timestamp,
+		"numNodes", len(checkpoint.Nodes), "numNMNodes", len(checkpoint.NMNodes), "numCNRs", len(checkpoint.CNRs),
+		"numPodGroups", len(checkpoint.PodGroups), "numPods", len(checkpoint.Pods), "numAssumedPods", len(checkpoint.AssumedPods), "numUnits", len(checkpoint.Units),
+		"numPDBs", len(checkpoint.PDBs), "numOwners", len(checkpoint.Owners), "numReservations", len(checkpoint.Reservations),
+		"numMovements", len(checkpoint.Movements), "numElasticQuotas", len(checkpoint.ElasticQuotas))
+	return nil
+}
+
+func (cache *schedulerCache) FinishReconciling() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.reconciler == nil {
+		return 0
+	}
+	count := 0
+	cache.reconciler.Range(func(kind, key string, obj interface{}) {
+		count++
+		klog.V(4).InfoS("Removing stale object restored from checkpoint", "kind", kind, "key", key)
+		var err error
+		switch kind {
+		case commoncache.CheckpointKindNode:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNode(obj.(*v1.Node)) })
+		case commoncache.CheckpointKindNMNode:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNMNode(obj.(*nodev1alpha1.NMNode)) })
+		case commoncache.CheckpointKindCNR:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.DeleteCNR(obj.(*katalystv1alpha1.CustomNodeResource))
+			})
+		case commoncache.CheckpointKindPodGroup:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePodGroup(obj.(*schedulingv1a1.PodGroup)) })
+		case commoncache.CheckpointKindPod:
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePod(obj.(*v1.Pod)) })
+		case commoncache.CheckpointKindPDB:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePDB(obj.(*policy.PodDisruptionBudget)) })
+		case commoncache.CheckpointKindOwner:
+			owner := obj.(*commoncache.CheckpointOwner)
+			cache.objects.DeleteOwner(owner.Type, owner.Key)
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteOwner(owner.Type, owner.Key) })
+		case commoncache.CheckpointKindReservation:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.DeleteReservation(obj.(*schedulingv1a1.Reservation))
+			})
+		case commoncache.CheckpointKindMovement:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteMovement(obj.(*schedulingv1a1.Movement)) })
+		case commoncache.CheckpointKindElasticQuota:
+			cache.objects.Delete(kind, obj.(metav1.Object))
+			err = cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.DeleteElasticQuota(obj.(*quotav1alpha1.ElasticQuota))
+			})
+		}
+		if err != nil {
+			klog.InfoS("Failed to remove stale object restored from checkpoint", "kind", kind, "key", key, "err", err)
+		}
+	})
+	cache.reconciler = nil
+	return count
+}
+
+func (cache *schedulerCache) Reconciling() bool {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.reconciler != nil
+}
+
+// confirmRestored returns the restored object if the cache is reconciling and the object is restored from checkpoint.
+func (cache *schedulerCache) confirmRestored(kind string, obj metav1.Object) (metav1.Object, bool) {
+	if cache.reconciler == nil {
+		return nil, false
+	}
+	return cache.reconciler.Confirm(kind, obj)
+}
+
+// The Add events of informers are converted to Update events for the objects restored from checkpoint,
+// and skipped if the objects are not changed since the checkpoint was taken.
+
+func (cache *schedulerCache) AddPod(pod *v1.Pod) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindPod, pod); ok {
+		podStore := cache.CommonStoresSwitch.Find(podstore.Name).(*podstore.PodStore)
+		// An assumed pod keeps the resourceVersion of the pod before it's assumed, so it's always updated
+		// to the pod of the apiserver. It's added as usual if it has expired.
+		if cached, _ := podStore.IsCachedPod(restored.(*v1.Pod)); cached {
+			if assumed, _ := podStore.IsAssumedPod(restored.(*v1.Pod)); !assumed && restored.GetResourceVersion() == pod.ResourceVersion {
+				return nil
+			}
+			return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdatePod(restored.(*v1.Pod), pod) })
+		}
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddPod(pod) })
+}
+
+func (cache *schedulerCache) DeletePod(pod *v1.Pod) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindPod, pod)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePod(pod) })
+}
+
+func (cache *schedulerCache) AddNode(node *v1.Node) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindNode, node); ok {
+		if restored.GetResourceVersion() == node.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdateNode(restored.(*v1.Node), node) })
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddNode(node) })
+}
+
+func (cache *schedulerCache) DeleteNode(node *v1.Node) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindNode, node)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNode(node) })
+}
+
+func (cache *schedulerCache) AddNMNode(nmNode *nodev1alpha1.NMNode) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindNMNode, nmNode); ok {
+		if restored.GetResourceVersion() == nmNode.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+			return cs.UpdateNMNode(restored.(*nodev1alpha1.NMNode), nmNode)
+		})
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddNMNode(nmNode) })
+}
+
+func (cache *schedulerCache) DeleteNMNode(nmNode *nodev1alpha1.NMNode) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindNMNode, nmNode)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteNMNode(nmNode) })
+}
+
+func (cache *schedulerCache) AddCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindCNR, cnr); ok {
+		if restored.GetResourceVersion() == cnr.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+			return cs.UpdateCNR(restored.(*katalystv1alpha1.CustomNodeResource), cnr)
+		})
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddCNR(cnr) })
+}
+
+func (cache *schedulerCache) DeleteCNR(cnr *katalystv1alpha1.CustomNodeResource) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindCNR, cnr)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteCNR(cnr) })
+}
+
+func (cache *schedulerCache) AddPodGroup(podGroup *schedulingv1a1.PodGroup) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if restored, ok := cache.confirmRestored(commoncache.CheckpointKindPodGroup, podGroup); ok {
+		if restored.GetResourceVersion() == podGroup.ResourceVersion {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+			return cs.UpdatePodGroup(restored.(*schedulingv1a1.PodGroup), podGroup)
+		})
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddPodGroup(podGroup) })
+}
+
+func (cache *schedulerCache) DeletePodGroup(podGroup *schedulingv1a1.PodGroup) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.confirmRestored(commoncache.CheckpointKindPodGroup, podGroup)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeletePodGroup(podGroup) })
+}
+
+// The objects below are recorded as they are received, since their stores don't keep them for checkpoint.
+
+// addRestorable records obj and adds it to the stores, the Add event of an object restored from checkpoint is
+// converted to an Update event as the ones above.
+func (cache *schedulerCache) addRestorable(kind string, obj metav1.Object,
+	add func(cs commonstore.Store) error, update func(cs commonstore.Store, restored metav1.Object) error,
+) error {
+	cache.objects.Set(kind, obj)
+	if restored, ok := cache.confirmRestored(kind, obj); ok {
+		if restored.GetResourceVersion() == obj.GetResourceVersion() {
+			return nil
+		}
+		return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return update(cs, restored) })
+	}
+	return cache.CommonStoresSwitch.Range(add)
+}
+
+// deleteRestorable forgets obj and deletes it from the stores.
+func (cache *schedulerCache) deleteRestorable(kind string, obj metav1.Object, del func(cs commonstore.Store) error) error {
+	cache.objects.Delete(kind, obj)
+	cache.confirmRestored(kind, obj)
+	return cache.CommonStoresSwitch.Range(del)
+}
+
+func (cache *schedulerCache) AddPDB(pdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindPDB, pdb,
+		func(cs commonstore.Store) error { return cs.AddPDB(pdb) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdatePDB(restored.(*policy.PodDisruptionBudget), pdb)
+		})
+}
+
+func (cache *schedulerCache) UpdatePDB(oldPdb, newPdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindPDB, newPdb)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.UpdatePDB(oldPdb, newPdb) })
+}
+
+func (cache *schedulerCache) DeletePDB(pdb *policy.PodDisruptionBudget) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindPDB, pdb, func(cs commonstore.Store) error { return cs.DeletePDB(pdb) })
+}
+
+func (cache *schedulerCache) AddOwner(ownerType, key string, labels map[string]string) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.SetOwner(ownerType, key, labels)
+	if cache.reconciler != nil {
+		if restored, ok := cache.reconciler.ConfirmOwner(ownerType, key); ok {
+			if reflect.DeepEqual(restored.Labels, labels) {
+				return nil
+			}
+			return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+				return cs.UpdateOwner(ownerType, key, restored.Labels, labels)
+			})
+		}
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.AddOwner(ownerType, key, labels) })
+}
+
+func (cache *schedulerCache) UpdateOwner(ownerType, key string, oldLabels, newLabels map[string]string) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.SetOwner(ownerType, key, newLabels)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+		return cs.UpdateOwner(ownerType, key, oldLabels, newLabels)
+	})
+}
+
+func (cache *schedulerCache) DeleteOwner(ownerType, key string) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.DeleteOwner(ownerType, key)
+	if cache.reconciler != nil {
+		cache.reconciler.ConfirmOwner(ownerType, key)
+	}
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error { return cs.DeleteOwner(ownerType, key) })
+}
+
+func (cache *schedulerCache) AddReservation(request *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindReservation, request,
+		func(cs commonstore.Store) error { return cs.AddReservation(request) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdateReservation(restored.(*schedulingv1a1.Reservation), request)
+		})
+}
+
+func (cache *schedulerCache) UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindReservation, newRequest)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+		return cs.UpdateReservation(oldRequest, newRequest)
+	})
+}
+
+func (cache *schedulerCache) DeleteReservation(request *schedulingv1a1.Reservation) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindReservation, request,
+		func(cs commonstore.Store) error { return cs.DeleteReservation(request) })
+}
+
+func (cache *schedulerCache) AddMovement(movement *schedulingv1a1.Movement) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindMovement, movement,
+		func(cs commonstore.Store) error { return cs.AddMovement(movement) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdateMovement(restored.(*schedulingv1a1.Movement), movement)
+		})
+}
+
+func (cache *schedulerCache) UpdateMovement(oldMovement, newMovement *schedulingv1a1.Movement) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindMovement, newMovement)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+		return cs.UpdateMovement(oldMovement, newMovement)
+	})
+}
+
+func (cache *schedulerCache) DeleteMovement(movement *schedulingv1a1.Movement) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindMovement, movement,
+		func(cs commonstore.Store) error { return cs.DeleteMovement(movement) })
+}
+
+func (cache *schedulerCache) AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.addRestorable(commoncache.CheckpointKindElasticQuota, quota,
+		func(cs commonstore.Store) error { return cs.AddElasticQuota(quota) },
+		func(cs commonstore.Store, restored metav1.Object) error {
+			return cs.UpdateElasticQuota(restored.(*quotav1alpha1.ElasticQuota), quota)
+		})
+}
+
+func (cache *schedulerCache) UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.objects.Set(commoncache.CheckpointKindElasticQuota, newQuota)
+	return cache.CommonStoresSwitch.Range(func(cs commonstore.Store) error {
+		return cs.UpdateElasticQuota(oldQuota, newQuota)
+	})
+}
+
+func (cache *schedulerCache) DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.deleteRestorable(commoncache.CheckpointKindElasticQuota, quota,
+		func(cs commonstore.Store) error { return cs.DeleteElasticQuota(quota) })
+}
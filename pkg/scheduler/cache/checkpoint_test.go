@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	nodestore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/node_store"
+	pdbstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pdb_store"
+	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
+	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+)
+
+func newCheckpointTestCache() *schedulerCache {
+	cacheHandler := commoncache.MakeCacheHandlerWrapper().
+		ComponentName("scheduler").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		PodAssumedTTL(time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore(string(preemptionstore.Name)).Obj()
+	return newSchedulerCache(cacheHandler)
+}
+
+func makeCheckpointTestNode(name, resourceVersion, label string) *v1.Node {
+	node := testing_helper.MakeNode().Name(name).Label("label", label).Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "10"}).Obj()
+	node.ResourceVersion = resourceVersion
+	return node
+}
+
+func TestCheckpointSaveAndLoad(t *testing.T) {
+	cache := newCheckpointTestCache()
+	cache.AddNode(makeCheckpointTestNode("n1", "1", "a"))
+	cache.AddPod(testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").ResourceVersion("1").Obj())
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	if err := commoncache.SaveCheckpoint(path, cache.Checkpoint()); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	checkpoint, err := commoncache.LoadCheckpoint(path, "scheduler", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if len(checkpoint.Nodes) != 1 || len(checkpoint.Pods) != 1 {
+		t.Errorf("Expected 1 node and 1 pod in checkpoint, got %d nodes and %d pods", len(checkpoint.Nodes), len(checkpoint.Pods))
+	}
+	if _, err := commoncache.LoadCheckpoint(path, "binder", time.Minute); err == nil {
+		t.Errorf("Expected checkpoint of other component to be rejected")
+	}
+
+	checkpoint.Version = "v0"
+	if err := commoncache.SaveCheckpoint(path, checkpoint); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	if _, err := commoncache.LoadCheckpoint(path, "scheduler", time.Minute); err == nil {
+		t.Errorf("Expected checkpoint of other version to be rejected")
+	}
+}
+
+func TestCheckpointRestoreAndReconcile(t *testing.T) {
+	origin := newCheckpointTestCache()
+	origin.AddNode(makeCheckpointTestNode("n1", "1", "a"))
+	origin.AddNode(makeCheckpointTestNode("n2", "1", "a"))
+	origin.AddPod(testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").ResourceVersion("1").Obj())
+	origin.AddPod(testing_helper.MakePod().Namespace("default").Name("p2").UID("p2").Node("n2").ResourceVersion("1").Obj())
+
+	cache := newCheckpointTestCache()
+	if err := cache.Restore(origin.Checkpoint()); err != nil {
+		t.Fatalf("Failed to restore checkpoint: %v", err)
+	}
+	if !cache.Reconciling() {
+		t.Errorf("Expected cache to be reconciling after restored")
+	}
+	if err := cache.Restore(origin.Checkpoint()); err == nil {
+		t.Errorf("Expected cache to be restored only once")
+	}
+
+	nodeStore := cache.CommonStoresSwitch.Find(nodestore.Name).(*nodestore.NodeStore)
+	generation := nodeStore.GetNodeInfo("n1").GetGeneration()
+
+	// Unchanged objects are skipped, changed ones are updated.
+	cache.AddNode(makeCheckpointTestNode("n1", "1", "a"))
+	cache.AddPod(testing_helper.MakePod().Namespace("default").Name("p1").UID("p1").Node("n1").ResourceVersion("1").Obj())
+	cache.AddNode(makeCheckpointTestNode("n2", "2", "b"))
+	if got := nodeStore.GetNodeInfo("n1").GetGeneration(); got != generation {
+		t.Errorf("Expected unchanged node to be skipped, generation changed from %d to %d", generation, got)
+	}
+	if got := nodeStore.GetNodeInfo("n2").GetNode().Labels["label"]; got != "b" {
+		t.Errorf("Expected changed node to be updated, got label %q", got)
+	}
+
+	// p2 is not confirmed by informers, so it's removed as stale.
+	if got := cache.FinishReconciling(); got != 1 {
+		t.Errorf("Expected 1 stale object, got %d", got)
+	}
+	if cache.Reconciling() {
+		t.Errorf("Expected cache not to be reconciling after finished")
+	}
+	if got := nodeStore.GetNodeInfo("n1").NumPods(); got != 1 {
+		t.Errorf("Expected 1 pod on n1, got %d", got)
+	}
+	if got := nodeStore.GetNodeInfo("n2").NumPods(); got != 0 {
+		t.Errorf("Expected 0 pod on n2, got %d", got)
+	}
+}
+
+func TestCheckpointRestoreAndReconcileStores(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CacheCheckpoint, true)()
+
+	pdb := testing_helper.MakePdb().Namespace("default").Name("pdb").Label("app", "a").Obj()
+	pdb.ResourceVersion = "1"
+	origin := newCheckpointTestCache()
+	origin.AddPDB(pdb)
+	origin.AddOwner(util.OwnerTypeReplicaSet, "default/rs1", map[string]string{"app": "a"})
+	origin.AddOwner(util.OwnerTypeReplicaSet, "default/rs2", map[string]string{"app": "a"})
+
+	checkpoint := origin.Checkpoint()
+	if len(checkpoint.PDBs) != 1 || len(checkpoint.Owners) != 2 {
+		t.Fatalf("Expected 1 pdb and 2 owners in checkpoint, got %d pdbs and %d owners", len(checkpoint.PDBs), len(checkpoint.Owners))
+	}
+
+	cache := newCheckpointTestCache()
+	if err := cache.Restore(checkpoint); err != nil {
+		t.Fatalf("Failed to restore checkpoint: %v", err)
+	}
+	pdbStore := cache.CommonStoresSwitch.Find(pdbstore.Name).(*pdbstore.PdbStore)
+	if pdbStore.Pdbs.Get(util.GetPDBKey(pdb)) == nil {
+		t.Errorf("Expected pdb to be restored")
+	}
+
+	// rs1 is confirmed with new labels, rs2 is deleted while the component was down.
+	cache.AddPDB(pdb)
+	cache.AddOwner(util.OwnerTypeReplicaSet, "default/rs1", map[string]string{"app": "b"})
+	if got := cache.FinishReconciling(); got != 1 {
+		t.Errorf("Expected 1 stale object, got %d", got)
+	}
+	if pdbStore.ReplicaSets.Get("default/rs2") != nil {
+		t.Errorf("Expected stale owner to be removed")
+	}
+	owners := cache.Checkpoint().Owners
+	if len(owners) != 1 || owners[0].Key != "default/rs1" || owners[0].Labels["app"] != "b" {
+		t.Errorf("Expected only the updated rs1 in the next checkpoint, got %v", owners)
+	}
+}
+
+func TestCheckpointRestoreAssumedPods(t *testing.T) {
+	makePod := func(name, nodeName, resourceVersion string) *v1.Pod {
+		return testing_helper.MakePod().Namespace("default").Name(name).UID(name).Node(nodeName).ResourceVersion(resourceVersion).Obj()
+	}
+	origin := newCheckpointTestCache()
+	origin.AddNode(makeCheckpointTestNode("n1", "1", "a"))
+	for _, name := range []string{"p1", "p2", "p3"} {
+		if err := origin.AssumePod(&framework.CachePodInfo{Pod: makePod(name, "n1", "1")}); err != nil {
+			t.Fatalf("Failed to assume pod: %v", err)
+		}
+	}
+	checkpoint := origin.Checkpoint()
+	if len(checkpoint.AssumedPods) != 3 || len(checkpoint.Pods) != 0 {
+		t.Fatalf("Expected 3 assumed pods in checkpoint, got %d assumed pods and %d pods", len(checkpoint.AssumedPods), len(checkpoint.Pods))
+	}
+
+	cache := newCheckpointTestCache()
+	if err := cache.Restore(checkpoint); err != nil {
+		t.Fatalf("Failed to restore checkpoint: %v", err)
+	}
+	nodeStore := cache.CommonStoresSwitch.Find(nodestore.Name).(*nodestore.NodeStore)
+	podStore := cache.CommonStoresSwitch.Find(podstore.Name).(*podstore.PodStore)
+	if got := nodeStore.GetNodeInfo("n1").NumPods(); got != 3 {
+		t.Errorf("Expected 3 assumed pods on n1, got %d", got)
+	}
+
+	// p1 is bound, and p2 is still pending in the apiserver, so the assumption is dropped.
+	cache.AddNode(makeCheckpointTestNode("n1", "1", "a"))
+	cache.AddPod(makePod("p1", "n1", "2"))
+	cache.AddPod(makePod("p2", "", "1"))
+	if assumed, _ := podStore.IsAssumedPod(makePod("p1", "n1", "2")); assumed {
+		t.Errorf("Expected p1 not to be assumed after it's bound")
+	}
+	if cached, _ := podStore.IsCachedPod(makePod("p2", "", "1")); cached {
+		t.Errorf("Expected p2 to be removed after the pending pod is received")
+	}
+
+	// p3 expires after the assumed TTL, and is added as usual when it's received later.
+	podStore.CleanupExpiredAssumedPods(cache.mu, time.Now().Add(2*time.Second))
+	if got := nodeStore.GetNodeInfo("n1").NumPods(); got != 1 {
+		t.Errorf("Expected 1 pod on n1 after p3 expired, got %d", got)
+	}
+	if err := cache.AddPod(makePod("p3", "n1", "2")); err != nil {
+		t.Errorf("Failed to add p3: %v", err)
+	}
+	if got := cache.FinishReconciling(); got != 0 {
+		t.Errorf("Expected no stale object, got %d", got)
+	}
+	if got := nodeStore.GetNodeInfo("n1").NumPods(); got != 2 {
+		t.Errorf("Expected 2 pods on n1, got %d", got)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroupstore
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+)
+
+var _ generationstore.StoredObj = &placedPods{}
+
+// placedPods are the bound and assumed pods of a pod group, indexed by pod key.
+type placedPods struct {
+	pods       map[string]*v1.Pod
+	generation int64
+}
+
+func newPlacedPods() *placedPods {
+	return &placedPods{pods: map[string]*v1.Pod{}}
+}
+
+func (p *placedPods) GetGeneration() int64 {
+	return p.generation
+}
+
+func (p *placedPods) SetGeneration(generation int64) {
+	p.generation = generation
+}
+
+func (p *placedPods) clone() *placedPods {
+	pods := make(map[string]*v1.Pod, len(p.pods))
+	for key, pod := range p.pods {
+		pods[key] = pod
+	}
+	return &placedPods{pods: pods, generation: p.generation}
+}
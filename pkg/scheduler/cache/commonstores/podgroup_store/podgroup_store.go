@@ -20,12 +20,14 @@ import (
 	"fmt"
 
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
 
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
 	"github.com/kubewharf/godel-scheduler/pkg/util/generationstore"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
 )
 
@@ -51,6 +53,8 @@ type PodGroupStore struct {
 	handler   commoncache.CacheHandler
 
 	store generationstore.Store
+	// placedPods indexes the bound and assumed pods by the key of their pod groups.
+	placedPods generationstore.Store
 }
 
 func NewCache(handler commoncache.CacheHandler) commonstore.Store {
@@ -59,7 +63,8 @@ func NewCache(handler commoncache.CacheHandler) commonstore.Store {
 		storeType: commonstore.Cache,
 		handler:   handler,
 
-		store: generationstore.NewListStore(),
+		store:      generationstore.NewListStore(),
+		placedPods: generationstore.NewListStore(),
 	}
 }
 
@@ -69,10 +74,56 @@ func NewSnapshot(handler commoncache.CacheHandler) commonstore.Store {
 		storeType: commonstore.Snapshot,
 		handler:   handler,
 
-		store: generationstore.NewRawStore(),
+		store:      generationstore.NewRawStore(),
+		placedPods: generationstore.NewRawStore(),
 	}
 }
 
+func (s *PodGroupStore) AddPod(pod *v1.Pod) error {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+		return nil
+	}
+	return s.podOp(pod, true)
+}
+
+func (s *PodGroupStore) UpdatePod(oldPod *v1.Pod, newPod *v1.Pod) error {
+	// Remove the oldPod if existed.
+	{
+		key, err := framework.GetPodKey(oldPod)
+		if err != nil {
+			return err
+		}
+		if ps, _ := s.handler.GetPodState(key); ps != nil {
+			// Use the pod stored in Cache instead of oldPod.
+			if err := s.DeletePod(ps.Pod); err != nil {
+				return err
+			}
+		}
+	}
+	// Add the newPod if needed.
+	{
+		if err := s.AddPod(newPod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PodGroupStore) DeletePod(pod *v1.Pod) error {
+	if !podutil.BoundPod(pod) && !podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType()) {
+		return nil
+	}
+	return s.podOp(pod, false)
+}
+
+func (s *PodGroupStore) AssumePod(podInfo *framework.CachePodInfo) error {
+	return s.podOp(podInfo.Pod, true)
+}
+
+func (s *PodGroupStore) ForgetPod(podInfo *framework.CachePodInfo) error {
+	return s.podOp(podInfo.Pod, false)
+}
+
 func (s *PodGroupStore) AddPodGroup(podGroup *schedulingv1a1.PodGroup) error {
 	s.store.Set(unitutil.GetPodGroupKey(podGroup), framework.NewGenerationPodGroup(podGroup))
 	return nil
@@ -99,6 +150,15 @@ func (s *PodGroupStore) UpdateSnapshot(store commonstore.Store) error {
 		},
 		generationstore.DefaultCleanFunc(cache, snapshot),
 	)
+
+	cache, snapshot = framework.TransferGenerationStore(s.placedPods, store.(*PodGroupStore).placedPods)
+	cache.UpdateRawStore(
+		snapshot,
+		func(key string, obj generationstore.StoredObj) {
+			snapshot.Set(key, obj.(*placedPods).clone())
+		},
+		generationstore.DefaultCleanFunc(cache, snapshot),
+	)
 	return nil
 }
 
@@ -106,6 +166,7 @@ func (s *PodGroupStore) UpdateSnapshot(store commonstore.Store) error {
 
 type StoreHandle interface {
 	GetPodGroupInfo(podGroupName string) (*schedulingv1a1.PodGroup, error)
+	GetPlacedPods(podGroupName string) []*v1.Pod
 }
 
 var _ StoreHandle = &PodGroupStore{}
@@ -121,3 +182,56 @@ func (s *PodGroupStore) GetPodGroupInfo(podGroupName string) (*schedulingv1a1.Po
 	}
 	return pg, nil
 }
+
+// ListPodGroups returns all the pod groups in store.
+func (s *PodGroupStore) ListPodGroups() []*schedulingv1a1.PodGroup {
+	podGroups := make([]*schedulingv1a1.PodGroup, 0, s.store.Len())
+	s.store.Range(func(_ string, obj generationstore.StoredObj) {
+		if pg := obj.(framework.GenerationPodGroup).GetPodGroup(); pg != nil {
+			podGroups = append(podGroups, pg)
+		}
+	})
+	return podGroups
+}
+
+// GetPlacedPods returns the bound and assumed pods of the pod group, including the pods placed
+// in the current scheduling cycle when it's called on Snapshot.
+func (s *PodGroupStore) GetPlacedPods(podGroupName string) []*v1.Pod {
+	obj := s.placedPods.Get(podGroupName)
+	if obj == nil {
+		return nil
+	}
+	pods := make([]*v1.Pod, 0, len(obj.(*placedPods).pods))
+	for _, pod := range obj.(*placedPods).pods {
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func (s *PodGroupStore) podOp(pod *v1.Pod, isAdd bool) error {
+	podGroupName := unitutil.GetPodGroupFullName(pod)
+	if len(podGroupName) == 0 {
+		return nil
+	}
+	podKey := podutil.GeneratePodKey(pod)
+
+	var pp *placedPods
+	if obj := s.placedPods.Get(podGroupName); obj != nil {
+		pp = obj.(*placedPods)
+	} else if isAdd {
+		pp = newPlacedPods()
+	} else {
+		return nil
+	}
+	if isAdd {
+		pp.pods[podKey] = pod
+	} else {
+		delete(pp.pods, podKey)
+	}
+	if len(pp.pods) == 0 {
+		s.placedPods.Delete(podGroupName)
+	} else {
+		s.placedPods.Set(podGroupName, pp)
+	}
+	return nil
+}
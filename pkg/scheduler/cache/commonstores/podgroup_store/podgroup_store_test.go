@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroupstore
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestGetPlacedPods(t *testing.T) {
+	handler := commoncache.MakeCacheHandlerWrapper().Obj()
+	cache := NewCache(handler).(*PodGroupStore)
+	snapshot := NewSnapshot(handler).(*PodGroupStore)
+
+	makePod := func(name, podGroup, node string) *v1.Pod {
+		p := testing_helper.MakePod().Namespace("ns").Name(name).UID(name).Node(node)
+		if len(podGroup) > 0 {
+			p = p.Annotation(podutil.PodGroupNameAnnotationKey, podGroup)
+		}
+		return p.Obj()
+	}
+	verify := func(s *PodGroupStore, podGroupName string, expected ...string) {
+		t.Helper()
+		var got []string
+		for _, p := range s.GetPlacedPods(podGroupName) {
+			got = append(got, p.Name)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("expected placed pods %v of %s, but got %v", expected, podGroupName, got)
+		}
+	}
+
+	for _, p := range []*v1.Pod{
+		makePod("p1", "pg1", "n1"),
+		makePod("p2", "pg1", "n2"),
+		makePod("p3", "pg2", "n1"),
+		makePod("p4", "", "n1"),
+		makePod("p5", "pg1", ""),
+	} {
+		if err := cache.AddPod(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cache.DeletePod(makePod("p2", "pg1", "n2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	verify(cache, "ns/pg1", "p1")
+	verify(snapshot, "ns/pg1", "p1")
+	verify(snapshot, "ns/pg2", "p3")
+
+	// Pods assumed in the snapshot are placed until the next snapshot update.
+	assumedPod := makePod("p6", "pg1", "")
+	assumedPod.Annotations[podutil.AssumedNodeAnnotationKey] = "n3"
+	if err := snapshot.AssumePod(&framework.CachePodInfo{Pod: assumedPod}); err != nil {
+		t.Fatal(err)
+	}
+	verify(snapshot, "ns/pg1", "p1", "p6")
+	verify(cache, "ns/pg1", "p1")
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	verify(snapshot, "ns/pg1", "p1")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotastore
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+// QuotaInfo is the quota of a resource type and the resources used by the pods of that type.
+type QuotaInfo struct {
+	// Name is the name of the ElasticQuota, empty for aggregated quota.
+	Name string
+	// Min is the guaranteed resources, resources absent are not guaranteed.
+	Min v1.ResourceList
+	// Max is the upper bound of resources, resources absent are not limited.
+	Max v1.ResourceList
+	// Used is the resources requested by assumed and bound pods.
+	Used *framework.Resource
+}
+
+// ExceedsMax returns the first resource exceeding max if the request is added, or "" if none.
+func (q *QuotaInfo) ExceedsMax(request *framework.Resource) v1.ResourceName {
+	for name, max := range q.Max {
+		value := ResourceValue(request, name)
+		if value > 0 && ResourceValue(q.Used, name)+value > quantityValue(name, max) {
+			return name
+		}
+	}
+	return ""
+}
+
+// ExceedsMin returns the first requested resource exceeding min if the request is added, or "" if none.
+func (q *QuotaInfo) ExceedsMin(request *framework.Resource) v1.ResourceName {
+	for _, name := range requestedResourceNames(request) {
+		var min int64
+		if quantity, ok := q.Min[name]; ok {
+			min = quantityValue(name, quantity)
+		}
+		if ResourceValue(q.Used, name)+ResourceValue(request, name) > min {
+			return name
+		}
+	}
+	return ""
+}
+
+// OverMin returns true if the used resources exceed min in any dimension, i.e. the quota is borrowing
+// the idle resources of other quotas.
+func (q *QuotaInfo) OverMin() bool {
+	for _, name := range requestedResourceNames(q.Used) {
+		var min int64
+		if quantity, ok := q.Min[name]; ok {
+			min = quantityValue(name, quantity)
+		}
+		if ResourceValue(q.Used, name) > min {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceValue returns the value of the resource in the unit used by framework.Resource, e.g. milli cores for cpu.
+func ResourceValue(r *framework.Resource, name v1.ResourceName) int64 {
+	if r == nil {
+		return 0
+	}
+	switch name {
+	case v1.ResourceCPU:
+		return r.MilliCPU
+	case v1.ResourceMemory:
+		return r.Memory
+	case v1.ResourceEphemeralStorage:
+		return r.EphemeralStorage
+	default:
+		return r.ScalarResources[name]
+	}
+}
+
+func quantityValue(name v1.ResourceName, quantity resource.Quantity) int64 {
+	if name == v1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+// requestedResourceNames returns the names of the resources with positive values.
+func requestedResourceNames(r *framework.Resource) []v1.ResourceName {
+	if r == nil {
+		return nil
+	}
+	var names []v1.ResourceName
+	if r.MilliCPU > 0 {
+		names = append(names, v1.ResourceCPU)
+	}
+	if r.Memory > 0 {
+		names = append(names, v1.ResourceMemory)
+	}
+	if r.EphemeralStorage > 0 {
+		names = append(names, v1.ResourceEphemeralStorage)
+	}
+	for name, value := range r.ScalarResources {
+		if value > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
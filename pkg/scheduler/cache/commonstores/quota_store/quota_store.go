@@ -0,0 +1,380 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotastore
+
+import (
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const Name commonstore.StoreName = "QuotaStore"
+
+func (s *QuotaStore) Name() commonstore.StoreName {
+	return Name
+}
+
+func init() {
+	commonstores.GlobalRegistries.Register(
+		Name,
+		func(h commoncache.CacheHandler) bool {
+			return utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuota)
+		},
+		NewCache,
+		NewSnapshot)
+}
+
+// -------------------------------------- QuotaStore --------------------------------------
+
+// quotaPod is the usage accounted for a pod.
+type quotaPod struct {
+	namespace    string
+	resourceType podutil.PodResourceType
+	request      *framework.Resource
+}
+
+// QuotaStore tracks the ElasticQuotas and the resources used by the namespaces they cover.
+// Usage is accounted per namespace, so that changing the namespaces of a quota doesn't need
+// to walk through the pods again.
+type QuotaStore struct {
+	commonstore.BaseStore
+	storeType commonstore.StoreType
+	handler   commoncache.CacheHandler
+
+	quotas           map[string]*quotav1alpha1.ElasticQuota
+	namespaceToQuota map[string]string
+	used             map[string]map[podutil.PodResourceType]*framework.Resource
+	// pods is only maintained in Cache, to make sure each pod is accounted once.
+	pods map[string]*quotaPod
+	// generation is bumped on every change of Cache, a mismatch triggers the copy to Snapshot.
+	generation int64
+}
+
+var _ commonstore.Store = &QuotaStore{}
+
+func NewCache(handler commoncache.CacheHandler) commonstore.Store {
+	return &QuotaStore{
+		BaseStore: commonstore.NewBaseStore(),
+		storeType: commonstore.Cache,
+		handler:   handler,
+
+		quotas:           make(map[string]*quotav1alpha1.ElasticQuota),
+		namespaceToQuota: make(map[string]string),
+		used:             make(map[string]map[podutil.PodResourceType]*framework.Resource),
+		pods:             make(map[string]*quotaPod),
+		generation:       1,
+	}
+}
+
+func NewSnapshot(handler commoncache.CacheHandler) commonstore.Store {
+	return &QuotaStore{
+		BaseStore: commonstore.NewBaseStore(),
+		storeType: commonstore.Snapshot,
+		handler:   handler,
+
+		quotas:           make(map[string]*quotav1alpha1.ElasticQuota),
+		namespaceToQuota: make(map[string]string),
+		used:             make(map[string]map[podutil.PodResourceType]*framework.Resource),
+	}
+}
+
+func (s *QuotaStore) AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	s.setQuota(quota)
+	return nil
+}
+
+func (s *QuotaStore) UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error {
+	s.removeQuota(oldQuota)
+	s.setQuota(newQuota)
+	return nil
+}
+
+func (s *QuotaStore) DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error {
+	s.removeQuota(quota)
+	return nil
+}
+
+func (s *QuotaStore) setQuota(quota *quotav1alpha1.ElasticQuota) {
+	s.quotas[quota.Name] = quota
+	for _, ns := range quota.Spec.Namespaces {
+		s.assignNamespace(ns)
+	}
+	s.markChanged()
+}
+
+func (s *QuotaStore) removeQuota(quota *quotav1alpha1.ElasticQuota) {
+	old, ok := s.quotas[quota.Name]
+	if !ok {
+		return
+	}
+	delete(s.quotas, quota.Name)
+	// The namespaces claimed by the removed quota move to the other quotas listing them.
+	for _, ns := range old.Spec.Namespaces {
+		if s.namespaceToQuota[ns] == old.Name {
+			s.assignNamespace(ns)
+		}
+	}
+	s.markChanged()
+}
+
+// assignNamespace assigns the namespace to a quota listing it. A namespace is expected to belong
+// to one quota, the oldest one wins otherwise, so that the result doesn't depend on the order
+// in which quotas are received.
+func (s *QuotaStore) assignNamespace(ns string) {
+	var owner *quotav1alpha1.ElasticQuota
+	for _, quota := range s.quotas {
+		if !containsNamespace(quota, ns) {
+			continue
+		}
+		if owner == nil || olderQuota(quota, owner) {
+			owner = quota
+		}
+	}
+	if owner == nil {
+		delete(s.namespaceToQuota, ns)
+		return
+	}
+	s.namespaceToQuota[ns] = owner.Name
+}
+
+func containsNamespace(quota *quotav1alpha1.ElasticQuota, ns string) bool {
+	for _, n := range quota.Spec.Namespaces {
+		if n == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// olderQuota returns true if q1 is created before q2, quotas created at the same time are ordered by name.
+func olderQuota(q1, q2 *quotav1alpha1.ElasticQuota) bool {
+	if !q1.CreationTimestamp.Equal(&q2.CreationTimestamp) {
+		return q1.CreationTimestamp.Before(&q2.CreationTimestamp)
+	}
+	return q1.Name < q2.Name
+}
+
+func (s *QuotaStore) AddPod(pod *v1.Pod) error {
+	if !s.shouldAccount(pod) {
+		return nil
+	}
+	return s.addPod(pod)
+}
+
+func (s *QuotaStore) UpdatePod(oldPod, newPod *v1.Pod) error {
+	// Remove the oldPod if existed.
+	{
+		key, err := framework.GetPodKey(oldPod)
+		if err != nil {
+			return err
+		}
+		if ps, _ := s.handler.GetPodState(key); ps != nil {
+			// Use the pod stored in Cache instead of oldPod.
+			if err := s.DeletePod(ps.Pod); err != nil {
+				return err
+			}
+		}
+	}
+	// Add the newPod if needed.
+	return s.AddPod(newPod)
+}
+
+func (s *QuotaStore) DeletePod(pod *v1.Pod) error {
+	return s.removePod(pod)
+}
+
+func (s *QuotaStore) AssumePod(podInfo *framework.CachePodInfo) error {
+	if err := s.addPod(podInfo.Pod); err != nil {
+		return err
+	}
+	if s.storeType == commonstore.Snapshot && podInfo.Victims != nil {
+		for _, victim := range podInfo.Victims.Pods {
+			if err := s.removePod(victim); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *QuotaStore) ForgetPod(podInfo *framework.CachePodInfo) error {
+	if err := s.removePod(podInfo.Pod); err != nil {
+		return err
+	}
+	if s.storeType == commonstore.Snapshot && podInfo.Victims != nil {
+		for _, victim := range podInfo.Victims.Pods {
+			if err := s.addPod(victim); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// shouldAccount returns true if the pod takes resources of its namespace.
+func (s *QuotaStore) shouldAccount(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+	return podutil.BoundPod(pod) || podutil.AssumedPodOfGodel(pod, s.handler.SchedulerType())
+}
+
+func (s *QuotaStore) addPod(pod *v1.Pod) error {
+	rt, err := podutil.GetPodResourceType(pod)
+	if err != nil {
+		return err
+	}
+	qp := &quotaPod{namespace: pod.Namespace, resourceType: rt, request: framework.NewResource(podutil.PodRequests(pod))}
+	if s.pods != nil {
+		key := podutil.GetPodKey(pod)
+		if _, ok := s.pods[key]; ok {
+			return nil
+		}
+		s.pods[key] = qp
+	}
+	s.getOrCreateUsed(qp.namespace, qp.resourceType).AddResource(qp.request)
+	s.markChanged()
+	return nil
+}
+
+func (s *QuotaStore) removePod(pod *v1.Pod) error {
+	var qp *quotaPod
+	if s.pods != nil {
+		key := podutil.GetPodKey(pod)
+		if qp = s.pods[key]; qp == nil {
+			return nil
+		}
+		delete(s.pods, key)
+	} else {
+		rt, err := podutil.GetPodResourceType(pod)
+		if err != nil {
+			return err
+		}
+		qp = &quotaPod{namespace: pod.Namespace, resourceType: rt, request: framework.NewResource(podutil.PodRequests(pod))}
+	}
+	s.getOrCreateUsed(qp.namespace, qp.resourceType).SubResource(qp.request)
+	s.markChanged()
+	return nil
+}
+
+func (s *QuotaStore) getOrCreateUsed(namespace string, rt podutil.PodResourceType) *framework.Resource {
+	usedOfNamespace, ok := s.used[namespace]
+	if !ok {
+		usedOfNamespace = make(map[podutil.PodResourceType]*framework.Resource)
+		s.used[namespace] = usedOfNamespace
+	}
+	used, ok := usedOfNamespace[rt]
+	if !ok {
+		used = &framework.Resource{}
+		usedOfNamespace[rt] = used
+	}
+	return used
+}
+
+// markChanged bumps the generation of Cache. Snapshot is reset to 0 instead, which never matches Cache,
+// so that the changes made by a scheduling cycle are always overwritten by the next UpdateSnapshot.
+func (s *QuotaStore) markChanged() {
+	if s.storeType == commonstore.Snapshot {
+		s.generation = 0
+		return
+	}
+	s.generation++
+}
+
+func (s *QuotaStore) UpdateSnapshot(store commonstore.Store) error {
+	snapshot := store.(*QuotaStore)
+	if snapshot.generation == s.generation {
+		return nil
+	}
+	snapshot.quotas = make(map[string]*quotav1alpha1.ElasticQuota, len(s.quotas))
+	for name, quota := range s.quotas {
+		snapshot.quotas[name] = quota
+	}
+	snapshot.namespaceToQuota = make(map[string]string, len(s.namespaceToQuota))
+	for ns, name := range s.namespaceToQuota {
+		snapshot.namespaceToQuota[ns] = name
+	}
+	snapshot.used = make(map[string]map[podutil.PodResourceType]*framework.Resource, len(s.used))
+	for ns, usedOfNamespace := range s.used {
+		snapshot.used[ns] = make(map[podutil.PodResourceType]*framework.Resource, len(usedOfNamespace))
+		for rt, used := range usedOfNamespace {
+			snapshot.used[ns][rt] = used.Clone()
+		}
+	}
+	snapshot.generation = s.generation
+	return nil
+}
+
+// -------------------------------- Used in Snapshot --------------------------------
+
+type StoreHandle interface {
+	// GetQuotaInfo returns the quota of the namespace for the given resource type, nil if the namespace isn't limited.
+	GetQuotaInfo(namespace string, rt podutil.PodResourceType) *QuotaInfo
+	// GetAggregatedQuotaInfo returns the sum of min and used of all quotas for the given resource type.
+	// Quotas can only borrow when the aggregated used doesn't exceed the aggregated min.
+	GetAggregatedQuotaInfo(rt podutil.PodResourceType) *QuotaInfo
+}
+
+var _ StoreHandle = &QuotaStore{}
+
+func (s *QuotaStore) GetQuotaInfo(namespace string, rt podutil.PodResourceType) *QuotaInfo {
+	name, ok := s.namespaceToQuota[namespace]
+	if !ok {
+		return nil
+	}
+	quota := s.quotas[name]
+	limits := quotaLimits(quota, rt)
+	info := &QuotaInfo{Name: name, Min: limits.Min, Max: limits.Max, Used: &framework.Resource{}}
+	for _, ns := range quota.Spec.Namespaces {
+		if s.namespaceToQuota[ns] != name {
+			continue
+		}
+		info.Used.AddResource(s.used[ns][rt])
+	}
+	return info
+}
+
+func (s *QuotaStore) GetAggregatedQuotaInfo(rt podutil.PodResourceType) *QuotaInfo {
+	info := &QuotaInfo{Min: v1.ResourceList{}, Used: &framework.Resource{}}
+	for _, quota := range s.quotas {
+		for name, quantity := range quotaLimits(quota, rt).Min {
+			sum := info.Min[name]
+			sum.Add(quantity)
+			info.Min[name] = sum
+		}
+	}
+	for ns, name := range s.namespaceToQuota {
+		if _, ok := s.quotas[name]; ok {
+			info.Used.AddResource(s.used[ns][rt])
+		}
+	}
+	return info
+}
+
+func quotaLimits(quota *quotav1alpha1.ElasticQuota, rt podutil.PodResourceType) quotav1alpha1.QuotaLimits {
+	if rt == podutil.BestEffortPod {
+		return quota.Spec.BestEffort
+	}
+	return quota.Spec.Guaranteed
+}
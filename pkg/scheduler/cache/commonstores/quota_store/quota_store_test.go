@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotastore
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeCacheHandler() commoncache.CacheHandler {
+	cacheHandler := commoncache.MakeCacheHandlerWrapper().
+		ComponentName("godel-scheduler-0").SchedulerType("godel-scheduler").SubCluster(framework.DefaultSubCluster).
+		PodAssumedTTL(15 * time.Minute).Period(10 * time.Second).StopCh(wait.NeverStop).Obj()
+
+	podStore := podstore.NewCache(cacheHandler).(*podstore.PodStore)
+	cacheHandler.SetPodHandler(podStore.GetPodState)
+	cacheHandler.SetPodOpFunc(func(pod *v1.Pod, isAdd bool, skippedStores sets.String) error {
+		if isAdd {
+			return podStore.AddPod(pod)
+		}
+		return podStore.DeletePod(pod)
+	})
+	return cacheHandler
+}
+
+func makeQuota(name string, namespaces []string, min, max string) *quotav1alpha1.ElasticQuota {
+	return &quotav1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: quotav1alpha1.ElasticQuotaSpec{
+			Namespaces: namespaces,
+			Guaranteed: quotav1alpha1.QuotaLimits{
+				Min: v1.ResourceList{v1.ResourceCPU: resource.MustParse(min)},
+				Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse(max)},
+			},
+		},
+	}
+}
+
+func makePod(namespace, name, cpu string) *v1.Pod {
+	return testing_helper.MakePod().Namespace(namespace).Name(name).UID(name).Node("n1").
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu}).Obj()
+}
+
+func TestQuotaStore(t *testing.T) {
+	handler := makeCacheHandler()
+	cache := NewCache(handler).(*QuotaStore)
+	snapshot := NewSnapshot(handler).(*QuotaStore)
+
+	cache.AddElasticQuota(makeQuota("q1", []string{"ns1", "ns2"}, "2", "4"))
+	cache.AddElasticQuota(makeQuota("q2", []string{"ns3"}, "2", "2"))
+
+	p1, p2, p3 := makePod("ns1", "p1", "1"), makePod("ns2", "p2", "2"), makePod("ns3", "p3", "1")
+	for _, p := range []*v1.Pod{p1, p2, p3, p1} {
+		if err := cache.AddPod(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	q1 := snapshot.GetQuotaInfo("ns1", podutil.GuaranteedPod)
+	if q1 == nil || q1.Name != "q1" || q1.Used.MilliCPU != 3000 {
+		t.Fatalf("unexpected quota info %+v", q1)
+	}
+	if !q1.OverMin() {
+		t.Errorf("expected q1 to be over min")
+	}
+	if name := q1.ExceedsMax(&framework.Resource{MilliCPU: 2000}); name != v1.ResourceCPU {
+		t.Errorf("expected cpu to exceed max, got %q", name)
+	}
+	if snapshot.GetQuotaInfo("default", podutil.GuaranteedPod) != nil {
+		t.Errorf("expected no quota for namespace default")
+	}
+	aggregated := snapshot.GetAggregatedQuotaInfo(podutil.GuaranteedPod)
+	if min := aggregated.Min[v1.ResourceCPU]; min.MilliValue() != 4000 || aggregated.Used.MilliCPU != 4000 {
+		t.Errorf("unexpected aggregated quota info %+v", aggregated)
+	}
+
+	// Assuming a pod in Snapshot evicts the victims, forgetting it restores them.
+	podInfo := &framework.CachePodInfo{Pod: makePod("ns3", "p4", "1"), Victims: &framework.Victims{Pods: []*v1.Pod{p2}}}
+	if err := snapshot.AssumePod(podInfo); err != nil {
+		t.Fatal(err)
+	}
+	if used := snapshot.GetQuotaInfo("ns1", podutil.GuaranteedPod).Used.MilliCPU; used != 1000 {
+		t.Errorf("expected used 1000 of q1, got %v", used)
+	}
+	if used := snapshot.GetQuotaInfo("ns3", podutil.GuaranteedPod).Used.MilliCPU; used != 2000 {
+		t.Errorf("expected used 2000 of q2, got %v", used)
+	}
+	if err := snapshot.ForgetPod(podInfo); err != nil {
+		t.Fatal(err)
+	}
+	if used := snapshot.GetQuotaInfo("ns1", podutil.GuaranteedPod).Used.MilliCPU; used != 3000 {
+		t.Errorf("expected used 3000 of q1, got %v", used)
+	}
+
+	// Changes of Snapshot are dropped by the next UpdateSnapshot.
+	snapshot.AssumePod(podInfo)
+	if err := cache.DeletePod(p2); err != nil {
+		t.Fatal(err)
+	}
+	cache.DeleteElasticQuota(makeQuota("q2", nil, "0", "0"))
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if used := snapshot.GetQuotaInfo("ns1", podutil.GuaranteedPod).Used.MilliCPU; used != 1000 {
+		t.Errorf("expected used 1000 of q1, got %v", used)
+	}
+	if snapshot.GetQuotaInfo("ns3", podutil.GuaranteedPod) != nil {
+		t.Errorf("expected no quota for namespace ns3")
+	}
+}
+
+func TestQuotaStore_NamespaceAssignment(t *testing.T) {
+	cache := NewCache(makeCacheHandler()).(*QuotaStore)
+
+	now := metav1.Now()
+	older, newer := makeQuota("q2", []string{"ns1", "ns2"}, "1", "1"), makeQuota("q1", []string{"ns1"}, "1", "1")
+	older.CreationTimestamp = metav1.NewTime(now.Add(-time.Minute))
+	newer.CreationTimestamp = now
+	// The oldest quota wins regardless of the order quotas are received.
+	cache.AddElasticQuota(newer)
+	cache.AddElasticQuota(older)
+	if got := cache.namespaceToQuota["ns1"]; got != "q2" {
+		t.Errorf("expected ns1 to belong to q2, got %q", got)
+	}
+
+	// The namespace moves to the other quota listing it once its quota stops claiming it.
+	updated := older.DeepCopy()
+	updated.Spec.Namespaces = []string{"ns2"}
+	cache.UpdateElasticQuota(older, updated)
+	if got := cache.namespaceToQuota["ns1"]; got != "q1" {
+		t.Errorf("expected ns1 to move to q1, got %q", got)
+	}
+
+	cache.DeleteElasticQuota(newer)
+	if _, ok := cache.namespaceToQuota["ns1"]; ok {
+		t.Errorf("expected ns1 to belong to no quota")
+	}
+	if got := cache.namespaceToQuota["ns2"]; got != "q2" {
+		t.Errorf("expected ns2 to belong to q2, got %q", got)
+	}
+}
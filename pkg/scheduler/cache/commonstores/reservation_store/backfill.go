@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservationstore
+
+import (
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+
+	"k8s.io/klog/v2"
+)
+
+// SetBackfillReservation adds or replaces the backfill reservation of the unit.
+// The deadline given to the unit when it was blocked for the first time is kept, so the
+// reservation isn't extended by the following failures and is dropped once it expires.
+func (s *ReservationStore) SetBackfillReservation(r *framework.BackfillReservation) {
+	if r == nil || len(r.UnitKey) == 0 {
+		return
+	}
+	if deadline, ok := s.backfillDeadlines[r.UnitKey]; ok {
+		copied := *r
+		copied.Deadline = deadline
+		r = &copied
+	} else {
+		s.backfillDeadlines[r.UnitKey] = r.Deadline
+	}
+	if r.Expired(time.Now()) {
+		delete(s.backfillReservations, r.UnitKey)
+		return
+	}
+	s.backfillReservations[r.UnitKey] = r
+	klog.V(4).InfoS("succeed to set backfill reservation", "unit", r.UnitKey, "deadline", r.Deadline, "nodes", len(r.Reserved))
+}
+
+// RemoveBackfillReservation removes the backfill reservation of the unit if existed,
+// and forgets the deadline of the unit.
+func (s *ReservationStore) RemoveBackfillReservation(unitKey string) {
+	delete(s.backfillDeadlines, unitKey)
+	if _, ok := s.backfillReservations[unitKey]; !ok {
+		return
+	}
+	delete(s.backfillReservations, unitKey)
+	klog.V(4).InfoS("succeed to remove backfill reservation", "unit", unitKey)
+}
+
+// DeletePodGroup removes the backfill reservation of the unit of the deleted PodGroup.
+func (s *ReservationStore) DeletePodGroup(podGroup *schedulingv1a1.PodGroup) error {
+	if s.storeType == commonstore.Cache {
+		s.RemoveBackfillReservation(framework.GetPodGroupUnitKey(podGroup))
+	}
+	return nil
+}
+
+// GetBackfillReservationsOnNode returns the unexpired backfill reservations holding resources on the node.
+func (s *ReservationStore) GetBackfillReservationsOnNode(nodeName string) []*framework.BackfillReservation {
+	if len(s.backfillReservations) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var ret []*framework.BackfillReservation
+	for _, r := range s.backfillReservations {
+		if r.Expired(now) {
+			continue
+		}
+		if _, ok := r.Reserved[nodeName]; ok {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// CleanupExpiredBackfillReservations removes the backfill reservations whose deadline has passed.
+// The deadlines of the units are kept until the units are scheduled or deleted.
+func (s *ReservationStore) CleanupExpiredBackfillReservations(now time.Time) {
+	keys := make([]string, 0)
+	for key, r := range s.backfillReservations {
+		if r.Expired(now) {
+			delete(s.backfillReservations, key)
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) > 0 {
+		klog.InfoS("succeed to cleanup expired backfill reservations", "list", keys)
+	}
+}
+
+// updateBackfillSnapshot copies the backfill reservations into snapshot store. Reservations
+// are always replaced as a whole, so a shallow copy is enough.
+func (s *ReservationStore) updateBackfillSnapshot(snapshot *ReservationStore) {
+	snapshot.backfillReservations = make(map[string]*framework.BackfillReservation, len(s.backfillReservations))
+	for key, r := range s.backfillReservations {
+		snapshot.backfillReservations[key] = r
+	}
+}
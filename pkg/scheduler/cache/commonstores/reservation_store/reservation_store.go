@@ -52,7 +52,8 @@ func init() {
 	commonstores.GlobalRegistries.Register(
 		Name,
 		func(h commoncache.CacheHandler) bool {
-			return utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation)
+			return utilfeature.DefaultFeatureGate.Enabled(features.ResourceReservation) ||
+				utilfeature.DefaultFeatureGate.Enabled(features.BackfillScheduling)
 		},
 		NewCache,
 		NewSnapshot)
@@ -70,6 +71,11 @@ type ReservationStore struct {
 	// it is used to look up nodeName list by placeholderKey, only used by snapshot
 	// key: placeholderKey, value: nodeNameList
 	placeholderTable *indexTable
+	// backfillReservations holds the resources reserved for blocked units, key: unitKey
+	backfillReservations map[string]*framework.BackfillReservation
+	// backfillDeadlines holds the deadline given to each unit when it was blocked for the first time,
+	// key: unitKey, only used by cache store
+	backfillDeadlines map[string]time.Time
 	// only used by cache store
 	clr *collector
 }
@@ -85,6 +91,8 @@ func NewCache(handler commoncache.CacheHandler) commonstore.Store {
 		reservations:                     reservation.NewCacheNodeReservationStore(),
 		deployWithReservationRequirement: make(map[string]bool),
 		assumedPlaceholderPods:           make(map[string]*time.Time),
+		backfillReservations:             make(map[string]*framework.BackfillReservation),
+		backfillDeadlines:                make(map[string]time.Time),
 		clr:                              newCollector(),
 	}
 }
@@ -98,6 +106,7 @@ func NewSnapshot(handler commoncache.CacheHandler) commonstore.Store {
 		reservations:                     reservation.NewSnapshotNodeReservationStore(),
 		deployWithReservationRequirement: make(map[string]bool),
 		assumedPlaceholderPods:           make(map[string]*time.Time),
+		backfillReservations:             make(map[string]*framework.BackfillReservation),
 		placeholderTable:                 newPlaceholderTable(),
 	}
 
@@ -272,6 +281,9 @@ func (s *ReservationStore) DeletePod(pod *v1.Pod) error {
 	if err != nil {
 		return err
 	}
+	if s.storeType == commonstore.Cache {
+		s.RemoveBackfillReservation(framework.GetSinglePodUnitKey(pod))
+	}
 
 	state, isAssumed := s.handler.GetPodState(uid)
 	// bound pod was deleted by controller
@@ -460,6 +472,11 @@ func (s *ReservationStore) UpdateReservation(oldRes, newRes *schedulingv1a1.Rese
 }
 
 func (s *ReservationStore) DeleteReservation(res *schedulingv1a1.Reservation) error {
+	if podutil.IsProactiveReservation(res) {
+		// proactive reservation holds no resources by itself, see its placeholder pods.
+		return nil
+	}
+
 	fakePod := podutil.ConvertReservationToPod(res)
 	if err := s.removeFakePod(fakePod); err != nil {
 		return fmt.Errorf("failed to remove fake pod, %v", err)
@@ -478,7 +495,9 @@ func (s *ReservationStore) PeriodWorker(mu *sync.RWMutex) {
 		mu.Lock()
 		defer mu.Unlock()
 
-		s.CleanupExpiredAssumedPodReservation(time.Now())
+		now := time.Now()
+		s.CleanupExpiredAssumedPodReservation(now)
+		s.CleanupExpiredBackfillReservations(now)
 		if s.clr != nil {
 			s.clr.emit()
 		}
@@ -592,6 +611,7 @@ func (s *ReservationStore) UpdateSnapshot(store commonstore.Store) error {
 	}
 
 	cacheStore.UpdateSnapshot(snapshotStore, cloneNodeInfo, cleanNodeInfo)
+	s.updateBackfillSnapshot(snapshot)
 	return nil
 }
 
@@ -713,7 +733,11 @@ func (s *ReservationStore) consumeReservedResources(matchedPod, fakePod *v1.Pod)
 }
 
 type StoreHandle interface {
-	GetAvailableNodesAndPlaceholders(placeholder string) (framework.ReservationPlaceholdersOfNodes, error)
+	// GetAvailableNodesAndPlaceholders returns the available placeholder pods of the placeholder, grouped by nodes.
+	// If pods are given, only placeholder pods whose identity matches any of the pods are returned.
+	GetAvailableNodesAndPlaceholders(placeholder string, pods ...*v1.Pod) (framework.ReservationPlaceholdersOfNodes, error)
+	// GetBackfillReservationsOnNode returns the unexpired backfill reservations holding resources on the node.
+	GetBackfillReservationsOnNode(nodeName string) []*framework.BackfillReservation
 }
 
 func (s *ReservationStore) updateReservedResources(oldPod, newPod *v1.Pod) (err error) {
@@ -730,11 +754,14 @@ func (s *ReservationStore) updateReservedResources(oldPod, newPod *v1.Pod) (err
 
 var _ StoreHandle = &ReservationStore{}
 
-func (s *ReservationStore) GetAvailableNodesAndPlaceholders(placeholder string) (framework.ReservationPlaceholdersOfNodes, error) {
+func (s *ReservationStore) GetAvailableNodesAndPlaceholders(placeholder string, pods ...*v1.Pod) (framework.ReservationPlaceholdersOfNodes, error) {
 	ret, err := s.getAvailablePlaceholderPods(placeholder)
 	if err != nil {
 		return nil, err
 	}
+	if len(pods) > 0 {
+		ret = filterPlaceholdersByIdentity(ret, pods)
+	}
 
 	if len(ret) == 0 {
 		return nil, fmt.Errorf("no available nodes for placeholder %s", placeholder)
@@ -743,6 +770,27 @@ func (s *ReservationStore) GetAvailableNodesAndPlaceholders(placeholder string)
 	return ret, nil
 }
 
+// filterPlaceholdersByIdentity removes the placeholder pods which can't be consumed by any of the pods, e.g. the
+// resources reserved for a StatefulSet pod could only be consumed by the pod with the same ordinal.
+func filterPlaceholdersByIdentity(placeholders framework.ReservationPlaceholdersOfNodes, pods []*v1.Pod) framework.ReservationPlaceholdersOfNodes {
+	ret := make(framework.ReservationPlaceholdersOfNodes, len(placeholders))
+	for node, placeholderMap := range placeholders {
+		matched := make(framework.ReservationPlaceholderMap, len(placeholderMap))
+		for key, placeholderPod := range placeholderMap {
+			for _, pod := range pods {
+				if podutil.MatchReservationIdentity(pod, placeholderPod) {
+					matched[key] = placeholderPod
+					break
+				}
+			}
+		}
+		if len(matched) > 0 {
+			ret[node] = matched
+		}
+	}
+	return ret
+}
+
 // --------------------------- manipulate reservationInfo store ---------------------------
 
 func (s *ReservationStore) getAvailablePlaceholderPods(placeholder string) (framework.ReservationPlaceholdersOfNodes, error) {
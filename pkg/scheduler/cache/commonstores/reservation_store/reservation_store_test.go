@@ -742,3 +742,137 @@ func TestReservationStore_UpdateSnapshot(t *testing.T) {
 		}
 	}
 }
+
+func TestReservationStore_GetAvailableNodesAndPlaceholders(t *testing.T) {
+	isController := true
+	stsRef := metav1.OwnerReference{Kind: podutil.StatefulSetKind, Name: "sts", UID: "sts", Controller: &isController}
+	makeStsPod := func(name, node string) *v1.Pod {
+		return testing_helper.MakePod().Name(name).UID(name).
+			Annotation(podutil.PodResourceReservationAnnotationForGodel, "true").
+			Annotation(podutil.PodStateAnnotationKey, string(podutil.PodAssumed)).
+			ControllerRef(stsRef).Node(node).Obj()
+	}
+
+	fakeHandler = makeCacheHandler()
+	cache := NewCache(fakeHandler).(*ReservationStore)
+	for _, pod := range []*v1.Pod{makeStsPod("sts-0", "node0"), makeStsPod("sts-1", "node1")} {
+		reservation, _ := podutil.ConstructReservationAccordingToPod(pod, int64(reservationTTL))
+		assert.NilError(t, cache.AddReservation(reservation), "failed to add reservation")
+	}
+
+	tests := []struct {
+		name          string
+		pods          []*v1.Pod
+		expectedNodes sets.String
+	}{
+		{
+			name:          "all placeholders are returned without pods",
+			expectedNodes: sets.NewString("node0", "node1"),
+		},
+		{
+			name:          "placeholder of the same ordinal is returned",
+			pods:          []*v1.Pod{makeStsPod("sts-1", "")},
+			expectedNodes: sets.NewString("node1"),
+		},
+		{
+			name:          "pod without identity can consume any placeholder",
+			pods:          []*v1.Pod{testing_helper.MakePod().Name("p").Annotation(podutil.ReservationIndexAnnotation, "sts").Obj()},
+			expectedNodes: sets.NewString("node0", "node1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placeholders, err := cache.GetAvailableNodesAndPlaceholders("sts", tt.pods...)
+			assert.NilError(t, err)
+			nodes := sets.NewString()
+			for node := range placeholders {
+				nodes.Insert(node)
+			}
+			if !nodes.Equal(tt.expectedNodes) {
+				t.Errorf("expected nodes: %v, got: %v", tt.expectedNodes.List(), nodes.List())
+			}
+		})
+	}
+
+	if _, err := cache.GetAvailableNodesAndPlaceholders("sts", makeStsPod("sts-2", "")); err == nil {
+		t.Errorf("expected no available placeholders for new ordinal")
+	}
+}
+
+func TestReservationStore_BackfillReservations(t *testing.T) {
+	cache := NewCache(fakeHandler).(*ReservationStore)
+	snapshot := NewSnapshot(fakeHandler).(*ReservationStore)
+
+	now := time.Now()
+	active := &framework.BackfillReservation{
+		UnitKey:  "PodGroupUnit/default/pg1",
+		Deadline: now.Add(time.Minute),
+		Reserved: map[string]*framework.Resource{"n1": {MilliCPU: 1000}},
+	}
+	expired := &framework.BackfillReservation{
+		UnitKey:  "PodGroupUnit/default/pg2",
+		Deadline: now.Add(-time.Minute),
+		Reserved: map[string]*framework.Resource{"n1": {MilliCPU: 1000}},
+	}
+	cache.SetBackfillReservation(active)
+	cache.SetBackfillReservation(expired)
+
+	assert.DeepEqual(t, []*framework.BackfillReservation{active}, cache.GetBackfillReservationsOnNode("n1"))
+	assert.Equal(t, 0, len(cache.GetBackfillReservationsOnNode("n2")))
+
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, []*framework.BackfillReservation{active}, snapshot.GetBackfillReservationsOnNode("n1"))
+
+	cache.CleanupExpiredBackfillReservations(now)
+	assert.Equal(t, 1, len(cache.backfillReservations))
+
+	cache.RemoveBackfillReservation(active.UnitKey)
+	if err := cache.UpdateSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(snapshot.GetBackfillReservationsOnNode("n1")))
+}
+
+func TestReservationStore_BackfillReservationDeadline(t *testing.T) {
+	cache := NewCache(fakeHandler).(*ReservationStore)
+
+	now := time.Now()
+	first := &framework.BackfillReservation{
+		UnitKey:  "PodGroupUnit/default/pg1",
+		Deadline: now.Add(time.Minute),
+		Reserved: map[string]*framework.Resource{"n1": {MilliCPU: 1000}},
+	}
+	cache.SetBackfillReservation(first)
+
+	// The following failures of the unit don't extend the reservation.
+	cache.SetBackfillReservation(&framework.BackfillReservation{
+		UnitKey:  first.UnitKey,
+		Deadline: now.Add(time.Hour),
+		Reserved: map[string]*framework.Resource{"n2": {MilliCPU: 1000}},
+	})
+	reservations := cache.GetBackfillReservationsOnNode("n2")
+	assert.Equal(t, 1, len(reservations))
+	assert.Equal(t, first.Deadline, reservations[0].Deadline)
+
+	// Once the first deadline has passed, the unit can't reserve resources again.
+	cache.backfillDeadlines[first.UnitKey] = now.Add(-time.Second)
+	cache.CleanupExpiredBackfillReservations(now)
+	cache.SetBackfillReservation(&framework.BackfillReservation{
+		UnitKey:  first.UnitKey,
+		Deadline: now.Add(time.Hour),
+		Reserved: map[string]*framework.Resource{"n1": {MilliCPU: 1000}},
+	})
+	assert.Equal(t, 0, len(cache.backfillReservations))
+
+	// Deleting the PodGroup of the unit releases the reservation and forgets the deadline.
+	cache.backfillDeadlines[first.UnitKey] = now.Add(time.Minute)
+	cache.SetBackfillReservation(first)
+	assert.Equal(t, 1, len(cache.backfillReservations))
+	if err := cache.DeletePodGroup(&schedulingv1alpha1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg1"}}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(cache.backfillReservations))
+	assert.Equal(t, 0, len(cache.backfillDeadlines))
+}
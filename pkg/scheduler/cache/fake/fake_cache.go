@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
 
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
@@ -235,3 +236,16 @@ func (c *Cache) UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reserva
 	return nil
 }
 func (c *Cache) DeleteReservation(request *schedulingv1a1.Reservation) error { return nil }
+func (c *Cache) AddElasticQuota(quota *quotav1alpha1.ElasticQuota) error     { return nil }
+func (c *Cache) UpdateElasticQuota(oldQuota, newQuota *quotav1alpha1.ElasticQuota) error {
+	return nil
+}
+func (c *Cache) DeleteElasticQuota(quota *quotav1alpha1.ElasticQuota) error { return nil }
+
+func (c *Cache) SetBackfillReservation(r *framework.BackfillReservation) {}
+func (c *Cache) RemoveBackfillReservation(unitKey string)                {}
+
+func (c *Cache) Checkpoint() *commoncache.Checkpoint              { return &commoncache.Checkpoint{} }
+func (c *Cache) Restore(checkpoint *commoncache.Checkpoint) error { return nil }
+func (c *Cache) FinishReconciling() int                           { return 0 }
+func (c *Cache) Reconciling() bool                                { return false }
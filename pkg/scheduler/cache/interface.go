@@ -62,6 +62,7 @@ import (
 //     a pod might have changed its state (e.g. added and deleted) without delivering notification to the cache.
 type SchedulerCache interface {
 	commoncache.ClusterEventsHandler
+	commoncache.Checkpointer
 
 	// Dump takes a snapshot of the current cache. This is used for debugging
 	// purposes only and shouldn't be confused with UpdateSnapshot function.
@@ -123,4 +124,9 @@ type SchedulerCache interface {
 	AddReservation(request *schedulingv1a1.Reservation) error
 	UpdateReservation(oldRequest, newRequest *schedulingv1a1.Reservation) error
 	DeleteReservation(request *schedulingv1a1.Reservation) error
+
+	// SetBackfillReservation reserves resources for a blocked unit until the deadline of the reservation.
+	SetBackfillReservation(r *framework.BackfillReservation)
+	// RemoveBackfillReservation releases the resources reserved for the unit.
+	RemoveBackfillReservation(unitKey string)
 }
@@ -25,6 +25,7 @@ import (
 	podstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/pod_store"
 	podgroupstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/podgroup_store"
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
+	quotastore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/quota_store"
 	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
 	unitstatusstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/unit_status_store"
 )
@@ -41,6 +42,7 @@ var orderedStoreNames = []commonstore.StoreName{
 	preemptionstore.Name,
 	unitstatusstore.Name,
 	loadawarestore.Name,
+	quotastore.Name,
 
 	nodestore.Name, // NodeStore be placed second to last.
 	podstore.Name,  // PodStore must be placed at the end.
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	cachedebugger "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/debugger"
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+)
+
+// RestoreCacheFromCheckpoint restores the scheduler cache from the checkpoint at path, it should be called
+// before informers are started. Returns true if the cache is restored, the scheduler works in conservative
+// mode (without preemption) until FinishCacheReconciling is called.
+func (sched *Scheduler) RestoreCacheFromCheckpoint(path string, maxAge time.Duration) bool {
+	checkpoint, err := commoncache.LoadCheckpoint(path, sched.Name, maxAge)
+	if err != nil {
+		klog.InfoS("Skipped restoring scheduler cache from checkpoint", "path", path, "err", err)
+		return false
+	}
+	if err := sched.commonCache.Restore(checkpoint); err != nil {
+		klog.InfoS("Failed to restore scheduler cache from checkpoint", "path", path, "err", err)
+		return false
+	}
+	return true
+}
+
+// FinishCacheReconciling removes the stale objects restored from checkpoint and reports the drift between
+// the scheduler cache and informers. It should be called after informers are synced.
+func (sched *Scheduler) FinishCacheReconciling() {
+	staleObjects := sched.commonCache.FinishReconciling()
+
+	comparer := cachedebugger.CacheComparer{
+		NodeLister: sched.informerFactory.Core().V1().Nodes().Lister(),
+		PodLister:  sched.podLister,
+		Cache:      sched.commonCache,
+	}
+	nodes, err := comparer.NodeLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list nodes for comparing with scheduler cache", "err", err)
+		return
+	}
+	pods, err := comparer.PodLister.List(labels.Everything())
+	if err != nil {
+		klog.InfoS("Failed to list pods for comparing with scheduler cache", "err", err)
+		return
+	}
+	assignedPods := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) > 0 {
+			assignedPods = append(assignedPods, pod)
+		}
+	}
+	dump := sched.commonCache.Dump()
+	missedNodes, redundantNodes := comparer.CompareNodes(nodes, dump.Nodes)
+	missedPods, redundantPods := comparer.ComparePods(assignedPods, nil, dump.Nodes)
+
+	klog.InfoS("Finished reconciling scheduler cache restored from checkpoint", "numStaleObjects", staleObjects,
+		"numMissedNodes", len(missedNodes), "numRedundantNodes", len(redundantNodes),
+		"numMissedPods", len(missedPods), "numRedundantPods", len(redundantPods))
+	klog.V(4).InfoS("Drift of scheduler cache restored from checkpoint", "missedNodes", missedNodes, "redundantNodes", redundantNodes,
+		"missedPods", missedPods, "redundantPods", redundantPods)
+}
+
+// RunCacheCheckpointer checkpoints the scheduler cache to path periodically until stopCh is closed.
+func (sched *Scheduler) RunCacheCheckpointer(path string, period time.Duration, stopCh <-chan struct{}) {
+	commoncache.RunCheckpointer(sched.commonCache, path, period, stopCh)
+}
+
+// listersHasSynced returns the HasSynced of the informers read through listers by the scheduler and its plugins.
+// Listers are not restored from checkpoint, so scheduling waits for them even if the cache is restored. All of
+// the informers are registered by New, so that they are started along with the others.
+func listersHasSynced(informerFactory informers.SharedInformerFactory, crdInformerFactory crdinformers.SharedInformerFactory) []cache.InformerSynced {
+	hasSynced := []cache.InformerSynced{
+		crdInformerFactory.Scheduling().V1alpha1().PodGroups().Informer().HasSynced,
+		informerFactory.Scheduling().V1().PriorityClasses().Informer().HasSynced,
+		informerFactory.Core().V1().PersistentVolumes().Informer().HasSynced,
+		informerFactory.Core().V1().PersistentVolumeClaims().Informer().HasSynced,
+		informerFactory.Storage().V1().StorageClasses().Informer().HasSynced,
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.CSINodeInfo) {
+		hasSynced = append(hasSynced, informerFactory.Storage().V1().CSINodes().Informer().HasSynced)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.CSIStorageCapacity) {
+		hasSynced = append(hasSynced,
+			informerFactory.Storage().V1().CSIStorageCapacities().Informer().HasSynced,
+			informerFactory.Storage().V1().CSIDrivers().Informer().HasSynced)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.SupportRescheduling) {
+		hasSynced = append(hasSynced, crdInformerFactory.Scheduling().V1alpha1().Movements().Informer().HasSynced)
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.ResourceReservation) {
+		hasSynced = append(hasSynced, crdInformerFactory.Scheduling().V1alpha1().Reservations().Informer().HasSynced)
+	}
+	return hasSynced
+}
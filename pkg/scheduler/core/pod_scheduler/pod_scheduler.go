@@ -822,7 +822,7 @@ func (gs *podScheduler) DisablePreemption() bool {
 }
 
 func (gs *podScheduler) GetPreemptionFrameworkForPod(pod *v1.Pod) framework.SchedulerPreemptionFramework {
-	return runtime.NewPreemptionFramework(gs.preemptionPluginRegistry, gs.getBasePluginsForPod(pod))
+	return runtime.NewPreemptionFramework(gs.preemptionPluginRegistry, gs.getBasePluginsForPod(pod), gs.metricsRecorder)
 }
 
 func (gs *podScheduler) SetPreemptionFrameworkForPod(pf framework.SchedulerPreemptionFramework) {
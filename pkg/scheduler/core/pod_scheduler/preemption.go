@@ -18,7 +18,6 @@ package podscheduler
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"reflect"
@@ -78,6 +77,7 @@ var pluginsSkipCheckingDuringPreemption = sets.NewString(
 	nodevolumelimits.GCEPDName,
 	nodevolumelimits.EBSName,
 	nonnativeresource.NonNativeTopologyName,
+	nonnativeresource.GPUShareName,
 	coscheduling.Name,
 )
 
@@ -115,7 +115,7 @@ func (gs *podScheduler) PreemptInPreferredNodes(
 	cachedNominatedNodes *framework.CachedNominatedNodes,
 ) (result core.PodScheduleResult, err error) {
 	if len(nodeGroup.GetPreferredNodes().List()) == 0 {
-		return core.PodScheduleResult{}, fmt.Errorf("can not preempt in empty preferred nodes")
+		return core.PodScheduleResult{}, &framework.PreemptionError{Reason: "can not preempt in empty preferred nodes"}
 	}
 
 	// Prepare information.
@@ -172,7 +172,7 @@ func (gs *podScheduler) PreemptInPreferredNodes(
 	}
 	preemptTraceContext.WithFields(tracing.WithMessageField("Can not preempt in preferred nodes"))
 	klog.InfoS("Can not preempt in preferred nodes", "pod", podutil.GetPodKey(pod))
-	return core.PodScheduleResult{}, fmt.Errorf("can not preempt in non-empty preferred nodes")
+	return core.PodScheduleResult{}, &framework.PreemptionError{Reason: "can not preempt in non-empty preferred nodes"}
 }
 
 func (gs *podScheduler) PreemptInNodeCircles(
@@ -330,7 +330,7 @@ func (gs *podScheduler) prepareNodes(ctx context.Context, preemptNodeCandidates
 
 	if !passed {
 		// no potential nodes, return nil.
-		return nil, errors.New(ReasonUnresolvableByPreemption)
+		return nil, &framework.PreemptionError{Reason: ReasonUnresolvableByPreemption}
 	}
 
 	return potentialCandidates, nil
@@ -348,7 +348,7 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	if err != nil {
 		return "", nil, err
 	} else if !canPreemptOthers {
-		return "", nil, errors.New(ReasonNotEligibleToPreemptOthers)
+		return "", nil, &framework.PreemptionError{Reason: ReasonNotEligibleToPreemptOthers}
 	}
 	completePreparePod := time.Now()
 	klog.InfoS("Complete prepare pod", "pod", podutil.GetPodKey(pod), "duration", completePreparePod.Sub(start))
@@ -371,7 +371,7 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	}
 	if len(candidates) == 0 {
 		metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingFindCandidates, helper.SinceInSeconds(findCandidatesStart))
-		return "", nil, errors.New(ReasonPreemptionCandidatesNotFound)
+		return "", nil, &framework.PreemptionError{Reason: ReasonPreemptionCandidatesNotFound}
 	}
 	metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingFindCandidates, helper.SinceInSeconds(findCandidatesStart))
 
@@ -380,12 +380,12 @@ func (gs *podScheduler) runPreemption(ctx context.Context,
 	bestCandidate := gs.SelectCandidate(ctx, f, pf, state, pod, candidates, nil, cachedNominatedNodes, false)
 	if bestCandidate == nil || len(bestCandidate.Name) == 0 {
 		metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingSelectCandidate, helper.SinceInSeconds(selectCandidateStart))
-		return "", nil, errors.New(ReasonPreemptionBestCandidateNotFound)
+		return "", nil, &framework.PreemptionError{Reason: ReasonPreemptionBestCandidateNotFound}
 	}
 	metrics.PreemptingStageLatencyObserve(podProperty, metrics.PreemptingSelectCandidate, helper.SinceInSeconds(selectCandidateStart))
 
 	if status := pf.RunNodePostPreemptingPlugins(pod, bestCandidate.Victims.Pods, state, commonPreemptionState); !status.IsSuccess() {
-		return "", nil, status.AsError()
+		return "", nil, framework.NewPreemptionErrorFromStatus(status)
 	}
 
 	return bestCandidate.Name, bestCandidate.Victims, nil
@@ -417,11 +417,11 @@ func (gs *podScheduler) FindCandidates(ctx context.Context,
 	cachedNominatedNodes *framework.CachedNominatedNodes,
 ) ([]*framework.Candidate, error) {
 	if status := f.RunPreFilterPlugins(ctx, state, pod); !status.IsSuccess() {
-		return nil, status.AsError()
+		return nil, framework.NewPreemptionErrorFromStatus(status)
 	}
 
 	if status := pf.RunClusterPrePreemptingPlugins(pod, state, commonState); !status.IsSuccess() {
-		return nil, status.AsError()
+		return nil, framework.NewPreemptionErrorFromStatus(status)
 	}
 
 	candidateSelectPolicy := gs.GetCandidateSelectPolicy()
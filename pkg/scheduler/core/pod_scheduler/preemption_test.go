@@ -7185,7 +7185,7 @@ func TestPreemptInSpecificNodeGroup(t *testing.T) {
 			if err != nil {
 				t.Errorf("failed to new plugins registry: %v", err)
 			}
-			pf := frameworkruntime.NewPreemptionFramework(preemptionPluginRegistry, gs.getBasePluginsForPod(preemptor))
+			pf := frameworkruntime.NewPreemptionFramework(preemptionPluginRegistry, gs.getBasePluginsForPod(preemptor), nil)
 			nodeGroup := snapshot.MakeBasicNodeGroup()
 			preferNodes := framework.NewPreferredNodes()
 			for _, node := range nodesInPreferNodes {
@@ -55,6 +55,8 @@ type PodScheduler interface {
 
 type UnitScheduler interface {
 	Schedule(context.Context)
+	// DryRun runs the scheduling workflow for the unit without changing the cache or the API server.
+	DryRun(context.Context, *framework.QueuedUnitInfo) (*DryRunResult, error)
 
 	CanBeRecycle() bool
 	Close()
@@ -157,6 +159,25 @@ func TransferToUnitResult(unitInfo *SchedulingUnitInfo, details *interpretabity.
 	}
 }
 
+// DryRunPlacement is the node a pod would be placed on, along with the victims that would be preempted.
+type DryRunPlacement struct {
+	Pod     string   `json:"pod"`
+	Node    string   `json:"node"`
+	Victims []string `json:"victims,omitempty"`
+}
+
+// DryRunResult describes what would happen if the unit was scheduled now.
+type DryRunResult struct {
+	Unit        string `json:"unit"`
+	Schedulable bool   `json:"schedulable"`
+	// NodeGroup is the node group in which the placements are found.
+	NodeGroup  string            `json:"nodeGroup,omitempty"`
+	Placements []DryRunPlacement `json:"placements,omitempty"`
+	// Message is the failure message if the unit is not schedulable.
+	Message   string                        `json:"message,omitempty"`
+	Diagnosis *interpretabity.UnitDiagnosis `json:"diagnosis,omitempty"`
+}
+
 type RunningUnitInfo struct {
 	QueuedPodInfo *framework.QueuedPodInfo
 	Trace         tracing.SchedulingTrace
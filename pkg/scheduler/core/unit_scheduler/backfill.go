@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"sort"
+	"time"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// computeBackfillReservation returns the resources which should be held for the blocked unit until it times out,
+// or nil if nothing needs to be reserved. Resources are reserved from the nodes with the most free resources first,
+// so that the unit could be placed as soon as the pods running on these nodes finish.
+func computeBackfillReservation(unitInfo *core.SchedulingUnitInfo, request *unitCapacityRequest, nodeGroup framework.NodeGroup, now time.Time) *framework.BackfillReservation {
+	if request == nil || request.request == nil || nodeGroup == nil || isSatisfied(request.request) {
+		return nil
+	}
+
+	type candidate struct {
+		name        string
+		allocatable *framework.Resource
+		free        *framework.Resource
+	}
+	var candidates []candidate
+	for _, nodeInfo := range listNodesOfNodeGroup(nodeGroup) {
+		var allocatable, requested *framework.Resource
+		switch request.resourceType {
+		case podutil.GuaranteedPod:
+			allocatable, requested = nodeInfo.GetGuaranteedAllocatable(), nodeInfo.GetGuaranteedRequested()
+		case podutil.BestEffortPod:
+			allocatable, requested = nodeInfo.GetBestEffortAllocatable(), nodeInfo.GetBestEffortRequested()
+		}
+		if allocatable == nil {
+			continue
+		}
+		free := allocatable
+		if requested != nil {
+			free = freeResource(allocatable, requested)
+		}
+		candidates = append(candidates, candidate{name: nodeInfo.GetNodeName(), allocatable: allocatable, free: free})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].free.MilliCPU != candidates[j].free.MilliCPU {
+			return candidates[i].free.MilliCPU > candidates[j].free.MilliCPU
+		}
+		if candidates[i].free.Memory != candidates[j].free.Memory {
+			return candidates[i].free.Memory > candidates[j].free.Memory
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	remaining := request.request.Clone()
+	reserved := make(map[string]*framework.Resource)
+	for _, c := range candidates {
+		if isSatisfied(remaining) {
+			break
+		}
+		r := &framework.Resource{
+			MilliCPU: nonNegative(minInt64(remaining.MilliCPU, c.allocatable.MilliCPU)),
+			Memory:   nonNegative(minInt64(remaining.Memory, c.allocatable.Memory)),
+		}
+		for name, value := range remaining.ScalarResources {
+			if v := minInt64(value, c.allocatable.ScalarResources[name]); v > 0 {
+				r.SetScalar(name, v)
+			}
+		}
+		if r.IsZero() {
+			continue
+		}
+		remaining.MilliCPU -= r.MilliCPU
+		remaining.Memory -= r.Memory
+		for name, value := range r.ScalarResources {
+			remaining.ScalarResources[name] -= value
+		}
+		reserved[c.name] = r
+	}
+	if len(reserved) == 0 {
+		return nil
+	}
+
+	return &framework.BackfillReservation{
+		UnitKey:      unitInfo.UnitKey,
+		Priority:     unitInfo.QueuedUnitInfo.GetPriority(),
+		Deadline:     now.Add(time.Duration(unitInfo.QueuedUnitInfo.GetTimeoutPeriod()) * time.Second),
+		ResourceType: request.resourceType,
+		Reserved:     reserved,
+	}
+}
+
+// isSatisfied returns true if nothing remains to be reserved in any dimension.
+func isSatisfied(remaining *framework.Resource) bool {
+	if remaining.MilliCPU > 0 || remaining.Memory > 0 {
+		return false
+	}
+	for _, value := range remaining.ScalarResources {
+		if value > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
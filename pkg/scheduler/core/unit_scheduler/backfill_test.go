@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestComputeBackfillReservation(t *testing.T) {
+	nodes := []*v1.Node{
+		testinghelper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj(),
+		testinghelper.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj(),
+		testinghelper.MakeNode().Name("n3").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj(),
+	}
+	existingPods := []*v1.Pod{makeCapacityTestPod("e1", "6", "", "n1"), makeCapacityTestPod("e3", "2", "", "n3")}
+	nodeGroup := makeCapacityTestNodeGroup(existingPods, nodes...)
+
+	podGroup := testinghelper.MakePodGroup().Namespace("default").Name("pg").MinMember(3).Obj()
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		unitInfo     *core.SchedulingUnitInfo
+		wantReserved map[string]int64
+	}{
+		{
+			name: "reserve from nodes with most free resources first",
+			unitInfo: makeCapacityTestUnitInfo(3, false,
+				makeCapacityTestPod("p1", "4", "", ""), makeCapacityTestPod("p2", "4", "", ""), makeCapacityTestPod("p3", "4", "", "")),
+			wantReserved: map[string]int64{"n2": 8000, "n3": 4000},
+		},
+		{
+			name: "request exceeds allocatable of all nodes",
+			unitInfo: makeCapacityTestUnitInfo(3, false,
+				makeCapacityTestPod("p1", "10", "", ""), makeCapacityTestPod("p2", "10", "", ""), makeCapacityTestPod("p3", "10", "", "")),
+			wantReserved: map[string]int64{"n1": 8000, "n2": 8000, "n3": 8000},
+		},
+		{
+			name:     "unit ever scheduled",
+			unitInfo: makeCapacityTestUnitInfo(2, true, makeCapacityTestPod("p1", "4", "", ""), makeCapacityTestPod("p2", "4", "", "")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.unitInfo.UnitKey = "PodGroupUnit/default/pg"
+			tt.unitInfo.QueuedUnitInfo = &framework.QueuedUnitInfo{ScheduleUnit: framework.NewPodGroupUnit(podGroup, 100)}
+
+			got := computeBackfillReservation(tt.unitInfo, computeUnitCapacityRequest(tt.unitInfo), nodeGroup, now)
+			if tt.wantReserved == nil {
+				if got != nil {
+					t.Fatalf("expected no reservation, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("expected reservation, got nil")
+			}
+			if got.UnitKey != tt.unitInfo.UnitKey || got.Priority != 100 || got.ResourceType != podutil.GuaranteedPod {
+				t.Errorf("unexpected reservation %+v", got)
+			}
+			if want := now.Add(300 * time.Second); !got.Deadline.Equal(want) {
+				t.Errorf("expected deadline %v, got %v", want, got.Deadline)
+			}
+			if len(got.Reserved) != len(tt.wantReserved) {
+				t.Fatalf("expected reserved nodes %v, got %v", tt.wantReserved, got.Reserved)
+			}
+			for node, cpu := range tt.wantReserved {
+				if r := got.Reserved[node]; r == nil || r.MilliCPU != cpu {
+					t.Errorf("expected %d milli cpu reserved on node %s, got %+v", cpu, node, r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// unitCapacityRequest is the lower bound of resources needed by `members` pods of a unit.
+// Every resource dimension is computed independently as the sum of the smallest requests, so that a node group
+// which is pruned by the request could never hold `members` pods of the unit.
+type unitCapacityRequest struct {
+	resourceType podutil.PodResourceType
+	members      int
+	request      *framework.Resource
+
+	// gpuShareMembers is the minimum number of pods sharing gpus among `members` pods, and every one of them
+	// requests at least gpuShareMemory and gpuShareCore on a single device.
+	gpuShareMembers int
+	gpuShareMemory  int64
+	gpuShareCore    int64
+}
+
+// computeUnitCapacityRequest returns the capacity request of the unit, or nil if the unit doesn't need the
+// gang-level capacity check, e.g. the unit has been scheduled before or only one pod is required.
+func computeUnitCapacityRequest(unitInfo *core.SchedulingUnitInfo) *unitCapacityRequest {
+	if unitInfo.EverScheduled || unitInfo.MinMember <= 1 || len(unitInfo.DispatchedPods) < unitInfo.MinMember {
+		return nil
+	}
+
+	var (
+		resourceType                 podutil.PodResourceType
+		cpu, memory                  []int64
+		scalars                      = make(map[v1.ResourceName][]int64)
+		gpuShareMemory, gpuShareCore []int64
+		allPods, gpuSharePods        int
+		hasResourceType              bool
+	)
+	for _, runningUnitInfo := range unitInfo.DispatchedPods {
+		pod := runningUnitInfo.ClonedPod
+		if pod == nil {
+			return nil
+		}
+		// Resources reserved for the pod are accounted as requested on nodes, skip the check to avoid false pruning.
+		if podutil.HasReservationRequirement(pod) || podutil.HasMatchedReservationPlaceholder(pod) {
+			return nil
+		}
+		rt, err := podutil.GetPodResourceType(pod)
+		if err != nil || (hasResourceType && rt != resourceType) {
+			return nil
+		}
+		resourceType, hasResourceType = rt, true
+
+		res, _, _ := framework.CalculateResource(pod)
+		cpu = append(cpu, res.MilliCPU)
+		memory = append(memory, res.Memory)
+		for name, value := range res.ScalarResources {
+			scalars[name] = append(scalars[name], value)
+		}
+		if m, c := podutil.GetGPUShareRequests(pod); m > 0 || c > 0 {
+			gpuShareMemory = append(gpuShareMemory, m)
+			gpuShareCore = append(gpuShareCore, c)
+			gpuSharePods++
+		}
+		allPods++
+	}
+
+	members := unitInfo.MinMember
+	request := &framework.Resource{
+		MilliCPU: sumOfSmallest(cpu, allPods, members),
+		Memory:   sumOfSmallest(memory, allPods, members),
+	}
+	for name, values := range scalars {
+		request.SetScalar(name, sumOfSmallest(values, allPods, members))
+	}
+
+	capacityRequest := &unitCapacityRequest{
+		resourceType:    resourceType,
+		members:         members,
+		request:         request,
+		gpuShareMembers: members - (allPods - gpuSharePods),
+	}
+	if capacityRequest.gpuShareMembers > 0 {
+		capacityRequest.gpuShareMemory = minOf(gpuShareMemory)
+		capacityRequest.gpuShareCore = minOf(gpuShareCore)
+		// Pods share gpus in different dimensions, nothing could be inferred for a single device.
+		if capacityRequest.gpuShareMemory == 0 && capacityRequest.gpuShareCore == 0 {
+			capacityRequest.gpuShareMembers = 0
+		}
+	}
+	return capacityRequest
+}
+
+// checkNodeGroupCapacity returns the reason why the node group can't hold the unit, or "" if it may.
+// If preemption is allowed, the allocatable resources are used instead of the free resources, because
+// resources requested by existing pods may be released by preempting them.
+func checkNodeGroupCapacity(request *unitCapacityRequest, nodeGroup framework.NodeGroup, preemption bool) string {
+	if request == nil {
+		return ""
+	}
+
+	available, allocatable := &framework.Resource{}, &framework.Resource{}
+	var gpuShareSlots int64
+	checkGPUShare := request.gpuShareMembers > 0 && utilfeature.DefaultFeatureGate.Enabled(godelfeatures.NonNativeResourceSchedulingSupport)
+
+	for _, nodeInfo := range listNodesOfNodeGroup(nodeGroup) {
+		var nodeAllocatable, nodeRequested *framework.Resource
+		switch request.resourceType {
+		case podutil.GuaranteedPod:
+			nodeAllocatable, nodeRequested = nodeInfo.GetGuaranteedAllocatable(), nodeInfo.GetGuaranteedRequested()
+		case podutil.BestEffortPod:
+			nodeAllocatable, nodeRequested = nodeInfo.GetBestEffortAllocatable(), nodeInfo.GetBestEffortRequested()
+		}
+		if nodeAllocatable == nil {
+			continue
+		}
+		allocatable.AddResource(nodeAllocatable)
+		if preemption || nodeRequested == nil {
+			available.AddResource(nodeAllocatable)
+		} else {
+			available.AddResource(freeResource(nodeAllocatable, nodeRequested))
+		}
+
+		if checkGPUShare {
+			gpuShareSlots += countGPUShareSlots(nodeInfo.GetGPUShareStatus(), request.gpuShareMemory, request.gpuShareCore, preemption)
+		}
+	}
+
+	if request.request.MilliCPU > available.MilliCPU {
+		return insufficientResourceReason(v1.ResourceCPU, request.members, request.request.MilliCPU, available.MilliCPU)
+	}
+	if request.request.Memory > available.Memory {
+		return insufficientResourceReason(v1.ResourceMemory, request.members, request.request.Memory, available.Memory)
+	}
+	names := make([]string, 0, len(request.request.ScalarResources))
+	for name := range request.request.ScalarResources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resourceName := v1.ResourceName(name)
+		// Resources not advertised by any node are left to pod-level filtering.
+		if allocatable.ScalarResources[resourceName] == 0 {
+			continue
+		}
+		if value := request.request.ScalarResources[resourceName]; value > available.ScalarResources[resourceName] {
+			return insufficientResourceReason(resourceName, request.members, value, available.ScalarResources[resourceName])
+		}
+	}
+	if checkGPUShare && int64(request.gpuShareMembers) > gpuShareSlots {
+		return fmt.Sprintf("insufficient gpu devices: %d pods sharing gpus required, at most %d could fit", request.gpuShareMembers, gpuShareSlots)
+	}
+	return ""
+}
+
+// listNodesOfNodeGroup returns the distinct nodes in node circles and preferred nodes of the node group.
+func listNodesOfNodeGroup(nodeGroup framework.NodeGroup) []framework.NodeInfo {
+	var nodes []framework.NodeInfo
+	visited := make(map[string]bool)
+	add := func(nodeInfos []framework.NodeInfo) {
+		for _, nodeInfo := range nodeInfos {
+			if nodeInfo == nil || visited[nodeInfo.GetNodeName()] {
+				continue
+			}
+			visited[nodeInfo.GetNodeName()] = true
+			nodes = append(nodes, nodeInfo)
+		}
+	}
+	for _, nodeCircle := range nodeGroup.GetNodeCircles() {
+		add(nodeCircle.List())
+	}
+	if preferredNodes := nodeGroup.GetPreferredNodes(); preferredNodes != nil {
+		add(preferredNodes.List())
+	}
+	return nodes
+}
+
+// countGPUShareSlots returns the maximum number of pods requesting the given gpu memory and gpu core
+// that could be placed onto the devices of the node.
+func countGPUShareSlots(status *framework.GPUShareStatus, memory, core int64, preemption bool) int64 {
+	var slots int64
+	for _, id := range status.GetDeviceIDs() {
+		device := status.GetDevice(id)
+		freeMemory, freeCore := device.GetFree()
+		if preemption {
+			freeMemory, freeCore = device.GetAllocatable()
+		}
+		deviceSlots := int64(-1)
+		if memory > 0 {
+			deviceSlots = freeMemory / memory
+		}
+		if core > 0 && (deviceSlots < 0 || freeCore/core < deviceSlots) {
+			deviceSlots = freeCore / core
+		}
+		if deviceSlots > 0 {
+			slots += deviceSlots
+		}
+	}
+	return slots
+}
+
+func freeResource(allocatable, requested *framework.Resource) *framework.Resource {
+	free := &framework.Resource{
+		MilliCPU: nonNegative(allocatable.MilliCPU - requested.MilliCPU),
+		Memory:   nonNegative(allocatable.Memory - requested.Memory),
+	}
+	for name, value := range allocatable.ScalarResources {
+		free.SetScalar(name, nonNegative(value-requested.ScalarResources[name]))
+	}
+	return free
+}
+
+func insufficientResourceReason(name v1.ResourceName, members int, requested, available int64) string {
+	return fmt.Sprintf("insufficient %s: %d pods require at least %d, only %d available", name, members, requested, available)
+}
+
+// sumOfSmallest returns the sum of the smallest `members` values among `allPods` pods, where pods
+// absent from values request nothing.
+func sumOfSmallest(values []int64, allPods, members int) int64 {
+	count := members - (allPods - len(values))
+	if count <= 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	var sum int64
+	for _, value := range values[:count] {
+		sum += value
+	}
+	return sum
+}
+
+func minOf(values []int64) int64 {
+	var result int64
+	for i, value := range values {
+		if i == 0 || value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+func nonNegative(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
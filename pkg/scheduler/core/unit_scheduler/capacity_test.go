@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeCapacityTestNodeGroup(existingPods []*v1.Pod, nodes ...*v1.Node) framework.NodeGroup {
+	lister := framework.NewClusterNodeInfoLister().(*framework.NodeInfoListerImpl)
+	for _, node := range nodes {
+		var pods []*v1.Pod
+		for _, pod := range existingPods {
+			if pod.Spec.NodeName == node.Name {
+				pods = append(pods, pod)
+			}
+		}
+		nodeInfo := framework.NewNodeInfo(pods...)
+		nodeInfo.SetNode(node)
+		lister.AddNodeInfo(nodeInfo)
+	}
+	return framework.NewNodeGroup("test", nil, []framework.NodeCircle{framework.NewNodeCircle("test", lister)})
+}
+
+func makeCapacityTestUnitInfo(minMember int, everScheduled bool, pods ...*v1.Pod) *core.SchedulingUnitInfo {
+	unitInfo := &core.SchedulingUnitInfo{
+		MinMember:      minMember,
+		AllMember:      len(pods),
+		EverScheduled:  everScheduled,
+		DispatchedPods: make(map[string]*core.RunningUnitInfo),
+	}
+	for _, pod := range pods {
+		unitInfo.DispatchedPods[podutil.GetPodKey(pod)] = &core.RunningUnitInfo{ClonedPod: pod}
+	}
+	return unitInfo
+}
+
+func makeCapacityTestPod(name, cpu, gpu, nodeName string) *v1.Pod {
+	req := map[v1.ResourceName]string{v1.ResourceCPU: cpu}
+	if len(gpu) > 0 {
+		req[util.ResourceGPU] = gpu
+	}
+	return testinghelper.MakePod().Namespace("default").Name(name).UID(name).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Req(req).Node(nodeName).Obj()
+}
+
+func TestCheckNodeGroupCapacity(t *testing.T) {
+	nodes := []*v1.Node{
+		testinghelper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi", util.ResourceGPU: "4"}).Obj(),
+		testinghelper.MakeNode().Name("n2").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi", util.ResourceGPU: "4"}).Obj(),
+	}
+	existingPods := []*v1.Pod{makeCapacityTestPod("e1", "6", "", "n1"), makeCapacityTestPod("e2", "6", "", "n2")}
+
+	tests := []struct {
+		name         string
+		unitInfo     *core.SchedulingUnitInfo
+		preemption   bool
+		expectPruned bool
+	}{
+		{
+			name: "min member pods fit into free resources",
+			unitInfo: makeCapacityTestUnitInfo(2, false,
+				makeCapacityTestPod("p1", "2", "", ""), makeCapacityTestPod("p2", "2", "", ""), makeCapacityTestPod("p3", "2", "", "")),
+			expectPruned: false,
+		},
+		{
+			name: "min member pods exceed free cpu",
+			unitInfo: makeCapacityTestUnitInfo(3, false,
+				makeCapacityTestPod("p1", "2", "", ""), makeCapacityTestPod("p2", "2", "", ""), makeCapacityTestPod("p3", "2", "", "")),
+			expectPruned: true,
+		},
+		{
+			name: "smallest requests are used for min member",
+			unitInfo: makeCapacityTestUnitInfo(2, false,
+				makeCapacityTestPod("p1", "2", "", ""), makeCapacityTestPod("p2", "2", "", ""), makeCapacityTestPod("p3", "8", "", "")),
+			expectPruned: false,
+		},
+		{
+			name: "allocatable resources are used if preemption is allowed",
+			unitInfo: makeCapacityTestUnitInfo(3, false,
+				makeCapacityTestPod("p1", "2", "", ""), makeCapacityTestPod("p2", "2", "", ""), makeCapacityTestPod("p3", "2", "", "")),
+			preemption:   true,
+			expectPruned: false,
+		},
+		{
+			name: "min member pods exceed allocatable gpu",
+			unitInfo: makeCapacityTestUnitInfo(2, false,
+				makeCapacityTestPod("p1", "1", "8", ""), makeCapacityTestPod("p2", "1", "8", "")),
+			preemption:   true,
+			expectPruned: true,
+		},
+		{
+			name: "unit ever scheduled is never pruned",
+			unitInfo: makeCapacityTestUnitInfo(3, true,
+				makeCapacityTestPod("p1", "2", "", ""), makeCapacityTestPod("p2", "2", "", ""), makeCapacityTestPod("p3", "2", "", "")),
+			expectPruned: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeGroup := makeCapacityTestNodeGroup(existingPods, nodes...)
+			reason := checkNodeGroupCapacity(computeUnitCapacityRequest(tt.unitInfo), nodeGroup, tt.preemption)
+			if pruned := len(reason) > 0; pruned != tt.expectPruned {
+				t.Errorf("expected pruned: %v, got reason: %q", tt.expectPruned, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	unitruntime "github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/unit_runtime"
+	"github.com/kubewharf/godel-scheduler/pkg/util/interpretabity"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// DryRun runs Locating, Grouping, Scheduling and Preempting for the unit in the same way as Schedule does, but
+// the results are only assumed in the snapshot of unitScheduler and forgotten before returning. The cache is
+// never updated and nothing is persisted or re-enqueued, so the unitScheduler should own a snapshot dedicated
+// to dry runs, which could be reused by the following dry runs.
+func (gs *unitScheduler) DryRun(ctx context.Context, queuedUnitInfo *framework.QueuedUnitInfo) (*core.DryRunResult, error) {
+	snapshot, switchType, subCluster := gs.Snapshot, gs.switchType, gs.subCluster
+	if inValidUnit(queuedUnitInfo) {
+		return nil, fmt.Errorf("empty unit or invalid queued pod info")
+	}
+	klog.V(4).InfoS("Attempting to dry run unit", "switchType", switchType, "subCluster", subCluster, "unitKey", queuedUnitInfo.UnitKey)
+
+	unitInfo, err := gs.constructSchedulingUnitInfo(ctx, queuedUnitInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct scheduling unit info: %v", err)
+	}
+	if err = gs.Cache.UpdateSnapshot(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to update snapshot: %v", err)
+	}
+
+	result := &core.DryRunResult{Unit: unitInfo.UnitKey}
+	unitFramework := unitruntime.NewUnitFramework(gs, gs, gs.PluginRegistry, gs.PluginOrder, unitInfo.QueuedUnitInfo)
+
+	nodeGroup, status := unitFramework.RunLocatingPlugins(ctx, unitInfo.QueuedUnitInfo, unitInfo.UnitCycleState, snapshot.MakeBasicNodeGroup())
+	if !status.IsSuccess() {
+		result.Message = fmt.Sprintf("failed to run locating plugins: %v", status.AsError())
+		return result, nil
+	}
+	nodeGroups, status := unitFramework.RunGroupingPlugin(ctx, unitInfo.QueuedUnitInfo, unitInfo.UnitCycleState, nodeGroup)
+	if !status.IsSuccess() {
+		result.Message = fmt.Sprintf("failed to run grouping plugin: %v", status.AsError())
+		return result, nil
+	}
+
+	var (
+		finalUnitResult  = core.NewUnitResult(false, unitInfo.AllMember)
+		capacityRequest  = computeUnitCapacityRequest(unitInfo)
+		prunedNodeGroups = make(map[string]string)
+		attempts         = make(map[string]*interpretabity.UnitSchedulingDetails)
+	)
+	for _, nodeGroup := range nodeGroups {
+		nodeGroupName := nodeGroup.GetKey()
+		if reason := checkNodeGroupCapacity(capacityRequest, nodeGroup, !gs.disablePreemption); len(reason) > 0 {
+			prunedNodeGroups[nodeGroupName] = reason
+			continue
+		}
+
+		unitResult := gs.scheduleUnitInNodeGroup(ctx, unitInfo, unitFramework, nodeGroup)
+		unitResult.Successfully = (unitInfo.EverScheduled && len(unitResult.SuccessfulPods) > 0) || len(unitResult.SuccessfulPods) >= unitInfo.MinMember
+		attempts[nodeGroupName] = unitResult.Details
+		// keep the scheduling result with most successful Pods.
+		if len(unitResult.SuccessfulPods) >= len(finalUnitResult.SuccessfulPods) {
+			finalUnitResult = unitResult
+			result.NodeGroup = nodeGroupName
+			result.Placements = dryRunPlacements(unitInfo, unitResult)
+		}
+		// the assumed pods are always forgotten, so that the snapshot could be reused.
+		gs.resetRunningUnitInfo(ctx, unitInfo, unitResult, nodeGroupName)
+		if unitResult.Successfully {
+			break
+		}
+	}
+
+	for nodeGroupName, reason := range prunedNodeGroups {
+		finalUnitResult.Details.AddPrunedNodeGroup(nodeGroupName, reason)
+	}
+	for nodeGroupName, details := range attempts {
+		finalUnitResult.Details.AddNodeGroupAttempt(nodeGroupName, details)
+	}
+	result.Schedulable = finalUnitResult.Successfully
+	if !result.Schedulable {
+		result.Message = finalUnitResult.Details.FailureMessage()
+	}
+	result.Diagnosis = finalUnitResult.Details.Diagnosis()
+	return result, nil
+}
+
+func dryRunPlacements(unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) []core.DryRunPlacement {
+	placements := make([]core.DryRunPlacement, 0, len(result.SuccessfulPods))
+	for _, podKey := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[podKey]
+		if runningUnitInfo == nil {
+			continue
+		}
+		placement := core.DryRunPlacement{Pod: podKey, Node: runningUnitInfo.NodeToPlace}
+		if runningUnitInfo.Victims != nil {
+			for _, victim := range runningUnitInfo.Victims.Pods {
+				placement.Victims = append(placement.Victims, podutil.GetPodKey(victim))
+			}
+		}
+		placements = append(placements, placement)
+	}
+	sort.Slice(placements, func(i, j int) bool { return placements[i].Pod < placements[j].Pod })
+	return placements
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unitscheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func TestDryRun(t *testing.T) {
+	testNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", UID: types.UID("machine1")},
+		Status:     v1.NodeStatus{Capacity: makeResources(10, 20, 32, 20).Capacity, Allocatable: makeAllocatableResources(10, 20, 32, 20)},
+	}
+
+	minMembers := 3
+	namespace := "test"
+	podGroupName := "testPodGroup"
+	testPodGroup := &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: podGroupName, Namespace: namespace, UID: types.UID(podGroupName)},
+		Spec:       v1alpha1.PodGroupSpec{MinMember: int32(minMembers)},
+	}
+
+	var podInfos []*framework.QueuedPodInfo
+	for i := 0; i < minMembers; i++ {
+		podName := fmt.Sprintf("testpod-%d", i)
+		resource := framework.Resource{MilliCPU: 1, Memory: 1}
+		testPod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: namespace,
+				UID:       types.UID(podName),
+				Annotations: map[string]string{
+					podutil.PodLauncherAnnotationKey:     string(podutil.Kubelet),
+					podutil.PodResourceTypeAnnotationKey: string(podutil.GuaranteedPod),
+					podutil.PodGroupNameAnnotationKey:    podGroupName,
+				},
+			},
+			Spec: v1.PodSpec{
+				SchedulerName: testSchedulerName,
+				Containers: []v1.Container{{
+					Resources: v1.ResourceRequirements{Requests: resource.ResourceList()},
+				}},
+			},
+		}
+		podInfos = append(podInfos, &framework.QueuedPodInfo{
+			Pod:                     testPod,
+			Timestamp:               time.Now(),
+			InitialAttemptTimestamp: time.Now(),
+		})
+	}
+
+	client := clientsetfake.NewSimpleClientset(testNode)
+	client.PrependReactor("*", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() != "get" && action.GetVerb() != "list" && action.GetVerb() != "watch" {
+			t.Errorf("unexpected request in dry run: %v", action)
+		}
+		return false, nil, nil
+	})
+
+	sCache := godelcache.New(commoncache.MakeCacheHandlerWrapper().
+		ComponentName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+		PodAssumedTTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+		EnableStore("PreemptionStore").
+		Obj())
+	snapshot := godelcache.NewEmptySnapshot(commoncache.MakeCacheHandlerWrapper().
+		SubCluster(framework.DefaultSubCluster).SwitchType(framework.DefaultSubClusterSwitchType).
+		EnableStore("PreemptionStore").
+		Obj())
+	sCache.AddNode(testNode)
+
+	pgu := framework.NewPodGroupUnit(testPodGroup, 100)
+	pgu.AddPods(podInfos)
+
+	s := &unitScheduler{
+		schedulerName:     testSchedulerName,
+		switchType:        framework.SwitchType(1),
+		subCluster:        "",
+		disablePreemption: false,
+
+		client:    client,
+		crdClient: godelclientfake.NewSimpleClientset(),
+		pgLister:  testing_helper.NewFakePodGroupLister(nil),
+
+		Cache:     sCache,
+		Snapshot:  snapshot,
+		Scheduler: mockScheduler{result: core.PodScheduleResult{SuggestedHost: testNode.Name, NumberOfEvaluatedNodes: 1, NumberOfFeasibleNodes: 1}},
+
+		Recorder: &events.FakeRecorder{},
+		Clock:    clock.RealClock{},
+	}
+
+	result, err := s.DryRun(context.Background(), framework.NewQueuedUnitInfo(pgu.GetKey(), pgu, clock.RealClock{}))
+	assert.NoError(t, err)
+	assert.True(t, result.Schedulable)
+	assert.Equal(t, pgu.GetKey(), result.Unit)
+	assert.Len(t, result.Placements, minMembers)
+	for i, placement := range result.Placements {
+		assert.Equal(t, podutil.GetPodKey(podInfos[i].Pod), placement.Pod)
+		assert.Equal(t, testNode.Name, placement.Node)
+	}
+
+	// Nothing should be changed in cache.
+	for _, podInfo := range podInfos {
+		isCached, err := sCache.IsCachedPod(podInfo.Pod)
+		assert.NoError(t, err)
+		assert.False(t, isCached)
+	}
+	// The assumed pods are forgotten, so that the snapshot could be reused by the following dry runs.
+	nodeInfo := snapshot.GetNodeInfo(testNode.Name)
+	assert.NotNil(t, nodeInfo)
+	assert.Equal(t, 0, nodeInfo.NumPods())
+}
@@ -28,11 +28,13 @@ import (
 	"k8s.io/klog/v2"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
+	volumeutil "github.com/kubewharf/godel-scheduler/pkg/util/volume/util"
 )
 
 const (
@@ -79,6 +81,7 @@ func updateFailedSchedulingPod(cs clientset.Interface, schedulerName string,
 		delete(podCopy.Annotations, podutil.SchedulerAnnotationKey)
 	}
 	delete(podCopy.Annotations, podutil.MicroTopologyKey)
+	delete(podCopy.Annotations, podutil.GPUMicroTopologyKey)
 
 	if util.GetPodDebugMode(failedPod) == util.DebugModeOn {
 		delete(podCopy.Annotations, util.DebugModeAnnotationKey)
@@ -147,24 +150,63 @@ func podPassesBasicChecks(pod *v1.Pod, pvcLister corelisters.PersistentVolumeCla
 	manifest := &(pod.Spec)
 	for i := range manifest.Volumes {
 		volume := &manifest.Volumes[i]
-		var pvcName string
-		switch {
-		case volume.PersistentVolumeClaim != nil:
-			pvcName = volume.PersistentVolumeClaim.ClaimName
-		default:
+		pvcName, isEphemeral := volumeutil.PodVolumeClaimName(pod, volume)
+		if pvcName == "" {
 			// Volume is not using a PVC, ignore
 			continue
 		}
 		pvc, err := pvcLister.PersistentVolumeClaims(pod.Namespace).Get(pvcName)
 		if err != nil {
-			// The error has already enough context ("persistentvolumeclaim "myclaim" not found")
+			// The error has already enough context ("persistentvolumeclaim "myclaim" not found").
+			// The PVC of a generic ephemeral volume may not be created yet by the ephemeral-volume
+			// controller, and the pod will be retried once it's added.
 			return err
 		}
 
 		if pvc.DeletionTimestamp != nil {
 			return fmt.Errorf("persistentvolumeclaim %q is being deleted", pvc.Name)
 		}
+
+		if isEphemeral {
+			if err := volumeutil.EphemeralVolumeIsForPod(pod, pvc); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
+
+// setAchievedTopologyLevel records the tightest topology level shared by the nodes of the successful pods on
+// these pods, if hierarchical topology levels are specified for the unit. The binder will re-validate it.
+func setAchievedTopologyLevel(unitInfo *core.SchedulingUnitInfo, result *core.UnitResult, getNodeInfo func(string) framework.NodeInfo) {
+	levels := podutil.GetTopologyLevels(unitInfo.QueuedUnitInfo.GetAnnotations())
+	if len(levels) == 0 || len(result.SuccessfulPods) == 0 {
+		return
+	}
+
+	nodeLabels := make([]map[string]string, 0, len(result.SuccessfulPods))
+	for _, key := range result.SuccessfulPods {
+		runningUnitInfo := unitInfo.DispatchedPods[key]
+		podLauncher, err := podutil.GetPodLauncher(runningUnitInfo.ClonedPod)
+		if err != nil {
+			return
+		}
+		nodeInfo := getNodeInfo(runningUnitInfo.NodeToPlace)
+		if nodeInfo == nil {
+			return
+		}
+		nodeLabels = append(nodeLabels, nodeInfo.GetNodeLabels(podLauncher))
+	}
+
+	level := podutil.GetAchievedTopologyLevel(levels, nodeLabels)
+	for _, key := range result.SuccessfulPods {
+		clonedPod := unitInfo.DispatchedPods[key].ClonedPod
+		if len(level) == 0 {
+			delete(clonedPod.Annotations, podutil.AchievedTopologyLevelAnnotationKey)
+		} else {
+			clonedPod.Annotations[podutil.AchievedTopologyLevelAnnotationKey] = level
+		}
+	}
+	klog.V(4).InfoS("Achieved topology level for unit", "unitKey", unitInfo.UnitKey, "levels", levels, "achievedLevel", level)
+}
@@ -18,6 +18,7 @@ package unitscheduler
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,8 @@ import (
 	clienttesting "k8s.io/client-go/testing"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
@@ -110,3 +113,116 @@ func TestUpdatePodCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAchievedTopologyLevel(t *testing.T) {
+	nodeInfos := map[string]framework.NodeInfo{}
+	for _, node := range []*v1.Node{
+		testinghelper.MakeNode().Name("n1").Label("leaf", "l1").Label("spine", "s1").Obj(),
+		testinghelper.MakeNode().Name("n2").Label("leaf", "l1").Label("spine", "s1").Obj(),
+		testinghelper.MakeNode().Name("n3").Label("leaf", "l2").Label("spine", "s1").Obj(),
+	} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		nodeInfos[node.Name] = nodeInfo
+	}
+	getNodeInfo := func(name string) framework.NodeInfo { return nodeInfos[name] }
+
+	tests := []struct {
+		name     string
+		levels   string
+		nodes    []string
+		expected string
+	}{
+		{
+			name:     "pods in one leaf",
+			levels:   "leaf,spine",
+			nodes:    []string{"n1", "n2"},
+			expected: "leaf",
+		},
+		{
+			name:     "pods across leaves",
+			levels:   "leaf,spine",
+			nodes:    []string{"n1", "n3"},
+			expected: "spine",
+		},
+		{
+			name:     "no topology levels",
+			nodes:    []string{"n1", "n2"},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := testinghelper.MakePodGroup().Namespace("default").Name("pg").MinMember(uint(len(tt.nodes))).Obj()
+			if len(tt.levels) > 0 {
+				pg.Annotations = map[string]string{podutil.TopologyLevelsAnnotationKey: tt.levels}
+			}
+			unitInfo := &core.SchedulingUnitInfo{
+				QueuedUnitInfo: &framework.QueuedUnitInfo{ScheduleUnit: framework.NewPodGroupUnit(pg, 0)},
+				DispatchedPods: map[string]*core.RunningUnitInfo{},
+			}
+			result := &core.UnitResult{}
+			for i, node := range tt.nodes {
+				pod := testinghelper.MakePod().Namespace("default").Name(fmt.Sprintf("p%d", i)).
+					Annotation(podutil.PodLauncherAnnotationKey, string(podutil.Kubelet)).Obj()
+				unitInfo.DispatchedPods[pod.Name] = &core.RunningUnitInfo{ClonedPod: pod, NodeToPlace: node}
+				result.SuccessfulPods = append(result.SuccessfulPods, pod.Name)
+			}
+
+			setAchievedTopologyLevel(unitInfo, result, getNodeInfo)
+			for _, runningUnitInfo := range unitInfo.DispatchedPods {
+				if got := runningUnitInfo.ClonedPod.Annotations[podutil.AchievedTopologyLevelAnnotationKey]; got != tt.expected {
+					t.Errorf("expected achieved level: %v, got: %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPodPassesBasicChecks(t *testing.T) {
+	pod := testinghelper.MakePod().Namespace("default").Name("p").UID("p").Obj()
+	pod.Spec.Volumes = []v1.Volume{{Name: "scratch", VolumeSource: v1.VolumeSource{Ephemeral: &v1.EphemeralVolumeSource{}}}}
+	ownedPVC := v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "p-scratch",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(pod, v1.SchemeGroupVersion.WithKind("Pod"))},
+		},
+	}
+	conflictingPVC := *ownedPVC.DeepCopy()
+	conflictingPVC.OwnerReferences = nil
+	deletingPVC := *ownedPVC.DeepCopy()
+	deletingPVC.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name    string
+		pvcs    testinghelper.PersistentVolumeClaimLister
+		wantErr bool
+	}{
+		{
+			name:    "ephemeral volume claim not created yet",
+			wantErr: true,
+		},
+		{
+			name: "ephemeral volume claim owned by the pod",
+			pvcs: testinghelper.PersistentVolumeClaimLister{ownedPVC},
+		},
+		{
+			name:    "ephemeral volume claim not owned by the pod",
+			pvcs:    testinghelper.PersistentVolumeClaimLister{conflictingPVC},
+			wantErr: true,
+		},
+		{
+			name:    "ephemeral volume claim being deleted",
+			pvcs:    testinghelper.PersistentVolumeClaimLister{deletingPVC},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := podPassesBasicChecks(pod, tt.pvcs); (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -21,12 +21,14 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	clientset "k8s.io/client-go/kubernetes"
 	corelister "k8s.io/client-go/listers/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -37,6 +39,7 @@ import (
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
 	"github.com/kubewharf/godel-scheduler-api/pkg/client/listers/scheduling/v1alpha1"
 	commonstore "github.com/kubewharf/godel-scheduler/pkg/common/store"
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
@@ -49,8 +52,10 @@ import (
 	schedulingqueue "github.com/kubewharf/godel-scheduler/pkg/scheduler/queue"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/reconciler"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
+	eventutil "github.com/kubewharf/godel-scheduler/pkg/util/event"
 	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	"github.com/kubewharf/godel-scheduler/pkg/util/interpretabity"
+	metricsutil "github.com/kubewharf/godel-scheduler/pkg/util/metrics"
 	"github.com/kubewharf/godel-scheduler/pkg/util/parallelize"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 	"github.com/kubewharf/godel-scheduler/pkg/util/tracing"
@@ -94,6 +99,8 @@ type unitScheduler struct {
 
 	// Misc...
 	MaxWaitingDeletionDuration time.Duration
+	// PluginMetricsSamplePercent is the percentage of pods whose scheduling cycle records plugin metrics.
+	PluginMetricsSamplePercent int32
 }
 
 var (
@@ -125,6 +132,7 @@ func NewUnitScheduler(
 	recorder events.EventRecorder,
 	// misc...
 	maxWaitingDeletionDuration time.Duration,
+	pluginMetricsSamplePercent int32,
 ) core.UnitScheduler {
 	gs := &unitScheduler{
 		schedulerName:     schedulerName,
@@ -153,6 +161,7 @@ func NewUnitScheduler(
 		LatestScheduleTimestamp: clock.Now(),
 
 		MaxWaitingDeletionDuration: maxWaitingDeletionDuration,
+		PluginMetricsSamplePercent: pluginMetricsSamplePercent,
 	}
 
 	gs.PluginRegistry = schedulerframework.NewUnitPluginsRegistry(schedulerframework.NewUnitInTreeRegistry(), nil, gs)
@@ -195,7 +204,7 @@ func (gs *unitScheduler) BootstrapSchedulePod(ctx context.Context, pod *v1.Pod,
 		klog.ErrorS(err, "Failed to initialize cycle state", "switchType", switchType, "subCluster", subCluster, "pod", klog.KObj(pod))
 		return "", nil, nil, nil, err
 	}
-	state.SetRecordPluginMetrics(true)
+	state.SetRecordPluginMetrics(metricsutil.SamplePluginMetrics(gs.PluginMetricsSamplePercent))
 
 	if err = framework.SetPodTrace(podTrace, state); err != nil {
 		klog.ErrorS(err, "Fail to set pod tracing context map", "switchType", switchType, "subCluster", subCluster, "pod", podutil.GetPodKey(pod))
@@ -345,6 +354,12 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 
 		// record final scheduling result,
 		finalUnitResult = core.NewUnitResult(false, unitInfo.AllMember)
+
+		// gang-level capacity request, node groups which can't hold min member pods will be pruned before scheduling pods.
+		capacityRequest  = computeUnitCapacityRequest(unitInfo)
+		prunedNodeGroups = make(map[string]string)
+		// scheduling details of every attempted node group.
+		attempts = make(map[string]*interpretabity.UnitSchedulingDetails)
 	)
 
 	// TODO: we will cache some feasible nodes based on pod owners, make sure this (per node group scheduling) will not affect that
@@ -353,11 +368,20 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		nodeGroupName := nodeGroup.GetKey()
 		klog.V(4).InfoS("Attempting to schedule unit in this node group", "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "nodeGroup", nodeGroupName)
 
+		if reason := checkNodeGroupCapacity(capacityRequest, nodeGroup, !gs.disablePreemption); len(reason) > 0 {
+			klog.V(4).InfoS("Pruned node group without enough capacity for unit", "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "nodeGroup", nodeGroupName, "reason", reason)
+			prunedNodeGroups[nodeGroupName] = reason
+			continue
+		}
+
 		unitInfo.StartUnitTraceContext(tracing.RootSpan, tracing.SchedulerScheduleSpan, tracing.WithEverScheduledTag(unitInfo.EverScheduled))
 		unitInfo.SetUnitTraceContextFields(tracing.SchedulerScheduleSpan, tracing.WithNodeGroupField(nodeGroupName))
 
 		unitResult := gs.scheduleUnitInNodeGroup(ctx, unitInfo, unitFramework, nodeGroup)
 		scheduleSucceed := (unitInfo.EverScheduled && len(unitResult.SuccessfulPods) > 0) || len(unitResult.SuccessfulPods) >= unitInfo.MinMember
+		if scheduleSucceed {
+			setAchievedTopologyLevel(unitInfo, unitResult, snapshot.GetNodeInfo)
+		}
 		if scheduleSucceed && gs.applyToCache(ctx, unitInfo, unitResult) {
 			msg := "Schedule unit succeeded both for snapshot and cache"
 			klog.V(4).InfoS(msg, "switchType", switchType, "subCluster", subCluster, "unitKey", unitInfo.UnitKey, "nodeGroup", nodeGroupName)
@@ -406,6 +430,7 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		}
 
 		unitInfo.FinishUnitTraceContext(tracing.SchedulerScheduleSpan)
+		attempts[nodeGroupName] = unitResult.Details
 		// keep the scheduling result with most successful Pods.
 		if len(unitResult.SuccessfulPods) >= len(finalUnitResult.SuccessfulPods) {
 			finalUnitResult = unitResult
@@ -416,6 +441,13 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		}
 	}
 
+	for nodeGroupName, reason := range prunedNodeGroups {
+		finalUnitResult.Details.AddPrunedNodeGroup(nodeGroupName, reason)
+	}
+	for nodeGroupName, details := range attempts {
+		finalUnitResult.Details.AddNodeGroupAttempt(nodeGroupName, details)
+	}
+
 	errMessage := fmt.Sprintf("Failed to schedule unit. unit message:%v; failure message:%v", unitMessage, finalUnitResult.Details.FailureMessage())
 
 	// if scheduling failed, stop the workflow and return
@@ -432,6 +464,13 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 				"message", finalUnitResult.Details.FailureMessage())
 		}
 
+		// hold resources for the blocked gang, so that it won't be starved by smaller units.
+		if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.BackfillScheduling) {
+			if r := computeBackfillReservation(unitInfo, capacityRequest, nodeGroup, gs.Clock.Now()); r != nil {
+				gs.Cache.SetBackfillReservation(r)
+			}
+		}
+
 		// re-enqueue pods based on the `schedulingSuccessfully` value of scheduling result
 		// TODO: add more specific error messages -> attach scheduling errors to scheduling result
 		gs.handleSchedulingUnitFailure(ctx, finalUnitResult, unitInfo, errors.New(errMessage), "SchedulingFailed")
@@ -439,11 +478,15 @@ func (gs *unitScheduler) Schedule(ctx context.Context) {
 		return
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.BackfillScheduling) {
+		gs.Cache.RemoveBackfillReservation(unitInfo.UnitKey)
+	}
+
 	message := fmt.Sprintf("Schedule unit successfully. uint message: %v; successful pods:%d, failed pods:%d",
 		unitMessage, len(finalUnitResult.SuccessfulPods), len(finalUnitResult.FailedPods))
 	klog.V(4).InfoS("Scheduled unit successfully", "unitKey", unitInfo.UnitKey, "numSuccessfulPods", len(finalUnitResult.SuccessfulPods), "numFailedPods", len(finalUnitResult.FailedPods))
 	gs.recordUnitSchedulingResults(queuedUnitInfo, true,
-		"ScheduleUnitSuccessfully", core.ContinueAction, helper.TruncateMessage(message))
+		eventutil.ScheduleUnitSuccessfully, core.ContinueAction, helper.TruncateMessage(message))
 
 	// in case of scheduling partially success
 	gs.handleSchedulingUnitFailure(ctx, finalUnitResult, unitInfo, errors.New(errMessage), "SchedulingFailed")
@@ -536,7 +579,8 @@ func (gs *unitScheduler) scheduleUnitInNodeGroup(ctx context.Context, unitInfo *
 	unitInfo.SetUnitTraceContextFields(tracing.SchedulerScheduleUnitSpan, tracing.WithErrorFields(tracing.TruncateErrors(scheduleResult.Details.GetErrors()))...)
 	unitInfo.FinishUnitTraceContext(tracing.SchedulerScheduleUnitSpan)
 
-	if gs.disablePreemption {
+	// The cache restored from checkpoint may hold stale victims, so preemption is not performed until it's reconciled.
+	if gs.disablePreemption || gs.Cache.Reconciling() {
 		return core.TransferToUnitResult(unitInfo, scheduleResult.Details, scheduleResult.SuccessfulPods, scheduleResult.FailedPods)
 	}
 
@@ -546,6 +590,9 @@ func (gs *unitScheduler) scheduleUnitInNodeGroup(ctx context.Context, unitInfo *
 	unitInfo.SetUnitTraceContextFields(tracing.SchedulerPreemptUnitSpan, tracing.WithMessageField(preemptResult.Marshal()))
 	unitInfo.SetUnitTraceContextFields(tracing.SchedulerPreemptUnitSpan, tracing.WithErrorFields(tracing.TruncateErrors(preemptResult.Details.GetErrors()))...)
 	unitInfo.FinishUnitTraceContext(tracing.SchedulerPreemptUnitSpan)
+
+	// keep the node rejections of scheduling phase to explain why pods need preemption.
+	preemptResult.Details.AddNodeRejectionsFrom(scheduleResult.Details)
 	return core.TransferToUnitResult(unitInfo, preemptResult.Details, append(scheduleResult.SuccessfulPods, preemptResult.SuccessfulPods...), preemptResult.FailedPods)
 }
 
@@ -638,37 +685,39 @@ func (gs *unitScheduler) recordUnitSchedulingResults(unitInfo *framework.QueuedU
 	if unitInfo == nil || unitInfo.ScheduleUnit == nil {
 		return
 	}
-	// TODO: send warning event to unit object, e.g. PodGroup
-	// send events to each pod
 	var eventType string
 	if successful {
 		eventType = v1.EventTypeNormal
 	} else {
 		eventType = v1.EventTypeWarning
 	}
+	// send events to each pod, the pods of a scheduled unit get their own PersistPodSuccessfully events once they're assumed
+	var pods []*v1.Pod
 	for _, podInfo := range unitInfo.GetPods() {
 		if podInfo == nil {
 			klog.ErrorS(nil, "DEBUG: got a nil PodInfo, which shouldn't happen", "unitKey", unitInfo.UnitKey, "unitPodInfos", unitInfo.ScheduleUnit)
 			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 		}
-		if podInfo.Pod == nil {
+		if podInfo.Pod == nil || successful {
 			continue
 		}
-		gs.Recorder.Eventf(podInfo.Pod, nil, eventType, reason, action, message)
+		pods = append(pods, podInfo.Pod)
 	}
 
-	if unitInfo.Type() == framework.PodGroupUnitType {
-		// record event for PodGroup.
-		pg, err := gs.pgLister.PodGroups(unitInfo.GetNamespace()).Get(unitInfo.GetName())
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				klog.InfoS("Failed to get PodGroup", "unitNamespace", unitInfo.GetNamespace(), "unitName", unitInfo.GetName(), "err", err)
-			}
-			// do nothing, if PodGroup is not found.
-			return
+	if unitInfo.Type() != framework.PodGroupUnitType {
+		eventutil.RecordUnitEvent(gs.Recorder, nil, pods, eventType, reason, action, message)
+		return
+	}
+	// record event for PodGroup, which aggregates the events of the pods.
+	pg, err := gs.pgLister.PodGroups(unitInfo.GetNamespace()).Get(unitInfo.GetName())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.InfoS("Failed to get PodGroup", "unitNamespace", unitInfo.GetNamespace(), "unitName", unitInfo.GetName(), "err", err)
 		}
-		gs.Recorder.Eventf(pg, nil, eventType, reason, action, helper.TruncateMessage(message))
+		eventutil.RecordUnitEvent(gs.Recorder, nil, pods, eventType, reason, action, message)
+		return
 	}
+	eventutil.RecordUnitEvent(gs.Recorder, pg, pods, eventType, reason, action, message)
 }
 
 // updateFailedScheduleUnit reports the failure details for PodGroupUnit, by updating the condition.
@@ -691,7 +740,10 @@ func (gs *unitScheduler) updateFailedScheduleUnit(scheduleUnit framework.Schedul
 		Message:            failureDetails.FailureMessage(),
 	}
 
-	return interpretabity.UpdatePreSchedulingCondition(gs.crdClient, pg, cond)
+	if err := interpretabity.UpdatePreSchedulingCondition(gs.crdClient, pg, cond); err != nil {
+		return err
+	}
+	return interpretabity.UpdateSchedulingDiagnosis(gs.crdClient, pg, failureDetails.Diagnosis())
 }
 
 func (gs *unitScheduler) applyToCache(ctx context.Context, unitInfo *core.SchedulingUnitInfo, result *core.UnitResult) bool {
@@ -769,6 +821,8 @@ func (gs *unitScheduler) PersistSuccessfulPods(ctx context.Context,
 ) {
 	var failedPods []string
 	var fpMutex sync.Mutex
+	// the events of a large unit are aggregated on the PodGroup, so only the first pods get their own events
+	var persistedPods int32
 
 	cache, switchType, subCluster := gs.Cache, gs.switchType, gs.subCluster
 	unitProperty := unitInfo.QueuedUnitInfo.GetUnitProperty()
@@ -804,8 +858,10 @@ func (gs *unitScheduler) PersistSuccessfulPods(ctx context.Context,
 				"switchType", switchType, "subCluster", subCluster,
 				"pod", klog.KObj(runningUnitInfo.ClonedPod),
 				"unitKey", unitInfo.UnitKey)
-			gs.Recorder.Eventf(runningUnitInfo.ClonedPod, nil, v1.EventTypeNormal,
-				"PersistPodSuccessfully", core.ContinueAction, "Reserve pod successfully, and pod now is in assumed state")
+			if atomic.AddInt32(&persistedPods, 1) <= eventutil.MaxPodEventsPerUnit {
+				gs.Recorder.Eventf(runningUnitInfo.ClonedPod, nil, v1.EventTypeNormal,
+					eventutil.PersistPodSuccessfully, core.ContinueAction, "Assumed pod on node %s", runningUnitInfo.NodeToPlace)
+			}
 		} else {
 			updatingTraceContext.WithTags(tracing.WithResultTag(tracing.ResultFailure))
 			updatingTraceContext.WithFields(tracing.WithErrorField(err))
@@ -168,7 +168,7 @@ func (ms mockScheduler) DisablePreemption() bool {
 
 func (ms mockScheduler) GetPreemptionFrameworkForPod(_ *v1.Pod) framework.SchedulerPreemptionFramework {
 	registry := framework.PluginMap{}
-	return fwkruntime.NewPreemptionFramework(registry, ms.basePlugins)
+	return fwkruntime.NewPreemptionFramework(registry, ms.basePlugins, nil)
 }
 
 func (ms mockScheduler) PreemptInSpecificNodeGroup(_ context.Context, _ framework.SchedulerFramework, _ framework.SchedulerPreemptionFramework, _, _, _ *framework.CycleState, pod *v1.Pod, _ framework.NodeGroup, _ framework.NodeToStatusMap, _ *framework.CachedNominatedNodes) (core.PodScheduleResult, error) {
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
+	unitscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler/core/unit_scheduler"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// DryRunPath is the path of the dry-run endpoint.
+const DryRunPath = "/dryrun"
+
+// maxConcurrentDryRuns is the maximum number of dry runs served at the same time, since all the dry runs of
+// a sub cluster share the same snapshot. Requests beyond the limit are rejected with 429.
+const maxConcurrentDryRuns = 1
+
+// DryRunRequest asks whether and where the pods could be scheduled right now. Pods belong to the PodGroup
+// if it's specified, otherwise exactly one pod is expected.
+type DryRunRequest struct {
+	PodGroup *schedulingv1a1.PodGroup `json:"podGroup,omitempty"`
+	Pods     []*v1.Pod                `json:"pods"`
+}
+
+// DryRun schedules the unit described by the request on a snapshot of the scheduler cache dedicated to dry runs.
+// Neither the cache nor the API server is changed, and no event is recorded. The caller must hold a dry run slot.
+func (sched *Scheduler) DryRun(ctx context.Context, request *DryRunRequest) (*core.DryRunResult, error) {
+	unit, err := sched.newDryRunUnit(request)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := request.Pods[0]
+	subCluster := framework.DefaultSubCluster
+	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerSubClusterConcurrentScheduling) {
+		subCluster = pod.Spec.NodeSelector[framework.GetGlobalSubClusterKey()]
+	}
+	switchType := ParseSwitchTypeForPod(pod)
+	if switchType == framework.DisableScheduleSwitch {
+		return nil, fmt.Errorf("no scheduling workflow for sub cluster %q", subCluster)
+	}
+
+	return sched.getDryRunScheduler(subCluster, switchType).DryRun(ctx, framework.NewQueuedUnitInfo(unit.GetKey(), unit, sched.clock))
+}
+
+// getDryRunScheduler returns the unit scheduler dedicated to the dry runs of the sub cluster and switch type,
+// it's created at the first dry run and kept for the following ones.
+func (sched *Scheduler) getDryRunScheduler(subCluster string, switchType framework.SwitchType) core.UnitScheduler {
+	key := fmt.Sprintf("%s/%d", subCluster, switchType)
+	if unitScheduler, ok := sched.dryRunSchedulers[key]; ok {
+		return unitScheduler
+	}
+
+	subClusterConfig := sched.getSubClusterConfig(subCluster)
+	snapshot, podScheduler := sched.newSnapshotAndPodScheduler(subCluster, switchType, subClusterConfig)
+	unitScheduler := unitscheduler.NewUnitScheduler(
+		sched.Name,
+		switchType,
+		subCluster,
+		subClusterConfig.DisablePreemption,
+		sched.client,
+		sched.crdClient,
+		sched.podLister,
+		sched.pvcLister,
+		sched.pgLister,
+		sched.commonCache,
+		snapshot,
+		nil,
+		nil,
+		podScheduler,
+		sched.clock,
+		&events.FakeRecorder{},
+		time.Duration(subClusterConfig.MaxWaitingDeletionDuration)*time.Second,
+		// Dry runs don't affect real scheduling, keep them out of the plugin metrics.
+		0,
+	)
+	sched.dryRunSchedulers[key] = unitScheduler
+	return unitScheduler
+}
+
+// closeDryRunSchedulers closes the unit schedulers dedicated to dry runs.
+func (sched *Scheduler) closeDryRunSchedulers() {
+	sched.dryRunSlots <- struct{}{}
+	defer func() { <-sched.dryRunSlots }()
+	for key, unitScheduler := range sched.dryRunSchedulers {
+		unitScheduler.Close()
+		delete(sched.dryRunSchedulers, key)
+	}
+}
+
+func (sched *Scheduler) newDryRunUnit(request *DryRunRequest) (framework.ScheduleUnit, error) {
+	if request == nil || len(request.Pods) == 0 {
+		return nil, fmt.Errorf("no pod specified")
+	}
+	pg := request.PodGroup
+	if pg == nil && len(request.Pods) > 1 {
+		return nil, fmt.Errorf("pod group must be specified for %d pods", len(request.Pods))
+	}
+
+	now := sched.clock.Now()
+	podInfos := make([]*framework.QueuedPodInfo, 0, len(request.Pods))
+	for _, pod := range request.Pods {
+		if pod == nil {
+			return nil, fmt.Errorf("nil pod specified")
+		}
+		pod = pod.DeepCopy()
+		if len(pod.Namespace) == 0 {
+			pod.Namespace = metav1.NamespaceDefault
+			if pg != nil && len(pg.Namespace) > 0 {
+				pod.Namespace = pg.Namespace
+			}
+		}
+		if len(pod.UID) == 0 {
+			pod.UID = uuid.NewUUID()
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		if pg != nil {
+			if len(pg.Namespace) == 0 {
+				pg = pg.DeepCopy()
+				pg.Namespace = pod.Namespace
+			}
+			if pod.Namespace != pg.Namespace {
+				return nil, fmt.Errorf("pod %s/%s is not in the namespace of pod group %s", pod.Namespace, pod.Name, pg.Name)
+			}
+			pod.Annotations[podutil.PodGroupNameAnnotationKey] = pg.Name
+		}
+		podInfos = append(podInfos, &framework.QueuedPodInfo{
+			Pod:                     pod,
+			Timestamp:               now,
+			InitialAttemptTimestamp: now,
+			OwnerReferenceKey:       podutil.GetPodTemplateKey(pod),
+		})
+	}
+
+	if pg == nil {
+		return framework.NewSinglePodUnit(podInfos[0]), nil
+	}
+	priority := podutil.GetDefaultPriorityForGodelPod(podInfos[0].Pod)
+	if len(pg.Spec.PriorityClassName) > 0 {
+		pc, err := sched.informerFactory.Scheduling().V1().PriorityClasses().Lister().Get(pg.Spec.PriorityClassName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get priority class %s: %v", pg.Spec.PriorityClassName, err)
+		}
+		priority = pc.Value
+	}
+	unit := framework.NewPodGroupUnit(pg, priority)
+	if err := unit.AddPods(podInfos); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+// DryRunHandler serves DryRunRequest in the body of POST requests, and responds with the DryRunResult in JSON.
+func (sched *Scheduler) DryRunHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		select {
+		case sched.dryRunSlots <- struct{}{}:
+			defer func() { <-sched.dryRunSlots }()
+		default:
+			http.Error(w, "too many dry runs in progress", http.StatusTooManyRequests)
+			return
+		}
+
+		request := &DryRunRequest{}
+		if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+			return
+		}
+		result, err := sched.DryRun(req.Context(), request)
+		if err != nil {
+			klog.V(4).InfoS("Failed to dry run", "err", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			klog.InfoS("Failed to encode dry run result", "err", err)
+		}
+	})
+}
@@ -33,6 +33,7 @@ import (
 	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
 	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
 	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/util"
@@ -167,6 +168,26 @@ func (sched *Scheduler) onCSINodeUpdate(oldObj, newObj interface{}) {
 	)
 }
 
+func (sched *Scheduler) onCSIStorageCapacityAdd(obj interface{}) {
+	sched.ScheduleSwitch.Process(
+		// TODO: Parse SwitchType for CSI
+		framework.SwitchTypeAll,
+		func(dataSet ScheduleDataSet) {
+			dataSet.SchedulingQueue().MoveAllToActiveOrBackoffQueue(util.CSIStorageCapacityAdd)
+		},
+	)
+}
+
+func (sched *Scheduler) onCSIStorageCapacityUpdate(oldObj, newObj interface{}) {
+	sched.ScheduleSwitch.Process(
+		// TODO: Parse SwitchType for CSI
+		framework.SwitchTypeAll,
+		func(dataSet ScheduleDataSet) {
+			dataSet.SchedulingQueue().MoveAllToActiveOrBackoffQueue(util.CSIStorageCapacityUpdate)
+		},
+	)
+}
+
 // update nodes within scheduler api
 func (sched *Scheduler) onSchedulerUpdate(_, _ interface{}) {
 	sched.ScheduleSwitch.Process(
@@ -898,6 +919,72 @@ func (sched *Scheduler) deleteReservation(obj interface{}) {
 	}
 }
 
+func (sched *Scheduler) addElasticQuota(obj interface{}) {
+	quota, ok := obj.(*quotav1alpha1.ElasticQuota)
+	if !ok {
+		klog.InfoS("Failed to convert to *quota.ElasticQuota", "object", obj)
+		return
+	}
+	klog.V(4).InfoS("Detected an add event for elastic quota", "elasticQuota", klog.KObj(quota))
+	if err := sched.commonCache.AddElasticQuota(quota); err != nil {
+		klog.ErrorS(err, "Failed to add elastic quota to cache", "elasticQuota", klog.KObj(quota))
+		return
+	}
+	sched.onElasticQuotaChange()
+}
+
+func (sched *Scheduler) updateElasticQuota(oldObj, newObj interface{}) {
+	oldQuota, ok := oldObj.(*quotav1alpha1.ElasticQuota)
+	if !ok {
+		klog.InfoS("Failed to convert to *quota.ElasticQuota", "oldObject", oldObj)
+		return
+	}
+	newQuota, ok := newObj.(*quotav1alpha1.ElasticQuota)
+	if !ok {
+		klog.InfoS("Failed to convert to *quota.ElasticQuota", "newObject", newObj)
+		return
+	}
+	klog.V(4).InfoS("Detected an update event for elastic quota", "elasticQuota", klog.KObj(newQuota))
+	if err := sched.commonCache.UpdateElasticQuota(oldQuota, newQuota); err != nil {
+		klog.ErrorS(err, "Failed to update elastic quota in cache", "elasticQuota", klog.KObj(newQuota))
+		return
+	}
+	sched.onElasticQuotaChange()
+}
+
+func (sched *Scheduler) deleteElasticQuota(obj interface{}) {
+	var quota *quotav1alpha1.ElasticQuota
+	switch t := obj.(type) {
+	case *quotav1alpha1.ElasticQuota:
+		quota = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if quota, ok = t.Obj.(*quotav1alpha1.ElasticQuota); !ok {
+			klog.InfoS("Failed to convert to *quota.ElasticQuota", "object", t.Obj)
+			return
+		}
+	default:
+		klog.InfoS("Failed to convert to *quota.ElasticQuota", "object", obj)
+		return
+	}
+	klog.V(4).InfoS("Detected a delete event for elastic quota", "elasticQuota", klog.KObj(quota))
+	if err := sched.commonCache.DeleteElasticQuota(quota); err != nil {
+		klog.ErrorS(err, "Failed to delete elastic quota from cache", "elasticQuota", klog.KObj(quota))
+		return
+	}
+	sched.onElasticQuotaChange()
+}
+
+// onElasticQuotaChange retries the pods rejected by quotas, since the limits may have been relaxed.
+func (sched *Scheduler) onElasticQuotaChange() {
+	sched.ScheduleSwitch.Process(
+		framework.SwitchTypeAll,
+		func(dataSet ScheduleDataSet) {
+			dataSet.SchedulingQueue().MoveAllToActiveOrBackoffQueue(util.ElasticQuotaChange)
+		},
+	)
+}
+
 // addAllEventHandlers is a helper function used in tests and in Scheduler
 // to add event handlers for various informers.
 func addAllEventHandlers(
@@ -906,12 +993,25 @@ func addAllEventHandlers(
 	crdInformerFactory crdinformers.SharedInformerFactory,
 	katalystCrdInformerFactory katalystinformers.SharedInformerFactory,
 ) {
-	podInformer := informerFactory.Core().V1().Pods().Informer()
-	podInformer.AddEventHandler(
+	partitionScoped := utilfeature.DefaultFeatureGate.Enabled(godelfeatures.PartitionScopedInformers) &&
+		sched.options.nodeMetadataInformer != nil && sched.options.podMetadataInformer != nil
+
+	addPodFunc, deletePodFunc := sched.addPod, sched.deletePod
+	if partitionScoped {
+		addPodFunc, deletePodFunc = sched.addPodOfPartition, sched.deletePodOfPartition
+		sched.options.podMetadataInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.addPodMetadata,
+				UpdateFunc: sched.updatePodMetadata,
+				DeleteFunc: sched.deletePodMetadata,
+			},
+		)
+	}
+	informerFactory.Core().V1().Pods().Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
-			AddFunc:    sched.addPod,
+			AddFunc:    addPodFunc,
 			UpdateFunc: sched.updatePod,
-			DeleteFunc: sched.deletePod,
+			DeleteFunc: deletePodFunc,
 		},
 	)
 
@@ -931,11 +1031,22 @@ func addAllEventHandlers(
 		},
 	)
 
+	deleteNodeFunc := sched.deleteNodeFromCache
+	if partitionScoped {
+		deleteNodeFunc = sched.deleteNodeOfPartition
+		sched.options.nodeMetadataInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.addNodeMetadata,
+				UpdateFunc: sched.updateNodeMetadata,
+				DeleteFunc: sched.deleteNodeMetadata,
+			},
+		)
+	}
 	informerFactory.Core().V1().Nodes().Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    sched.addNodeToCache,
 			UpdateFunc: sched.updateNodeInCache,
-			DeleteFunc: sched.deleteNodeFromCache,
+			DeleteFunc: deleteNodeFunc,
 		},
 	)
 
@@ -972,6 +1083,18 @@ func addAllEventHandlers(
 		)
 	}
 
+	// More storage capacity may make pods failed for lack of storage schedulable.
+	if utilfeature.DefaultFeatureGate.Enabled(features.CSIStorageCapacity) {
+		informerFactory.Storage().V1().CSIStorageCapacities().Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.onCSIStorageCapacityAdd,
+				UpdateFunc: sched.onCSIStorageCapacityUpdate,
+			},
+		)
+		// CSIDrivers are only listed by the VolumeBinding plugin.
+		informerFactory.Storage().V1().CSIDrivers().Informer()
+	}
+
 	// On add and delete of PVs, it will affect equivalence cache items
 	// related to persistent volume
 	informerFactory.Core().V1().PersistentVolumes().Informer().AddEventHandler(
@@ -993,13 +1116,16 @@ func addAllEventHandlers(
 	// This is for ServiceAffinity: affected by the selector of the service is updated.
 	// Also, if new service is added, equivalence cache will also become invalid since
 	// existing pods may be "captured" by this service and change this predicate result.
-	informerFactory.Core().V1().Services().Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc:    sched.onServiceAdd,
-			UpdateFunc: sched.onServiceUpdate,
-			DeleteFunc: sched.onServiceDelete,
-		},
-	)
+	// Services are not listed by any plugin, so they are not watched when the informers are scoped to the partition.
+	if !partitionScoped {
+		informerFactory.Core().V1().Services().Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.onServiceAdd,
+				UpdateFunc: sched.onServiceUpdate,
+				DeleteFunc: sched.onServiceDelete,
+			},
+		)
+	}
 
 	if sched.mayHasPreemption {
 		informerFactory.Policy().V1().PodDisruptionBudgets().Informer().AddEventHandler(
@@ -1060,4 +1186,14 @@ func addAllEventHandlers(
 			},
 		)
 	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(godelfeatures.ElasticQuota) && sched.options.elasticQuotaInformer != nil {
+		sched.options.elasticQuotaInformer.AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    sched.addElasticQuota,
+				UpdateFunc: sched.updateElasticQuota,
+				DeleteFunc: sched.deleteElasticQuota,
+			},
+		)
+	}
 }
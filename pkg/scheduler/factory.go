@@ -29,7 +29,9 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/plugins/unitqueuesort"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/backfillreservation"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/coscheduling"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/elasticquota"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeaffinity"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeports"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/noderesources"
@@ -37,6 +39,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/podlauncher"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/tainttoleration"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/volumebinding"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/elasticquotachecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/newlystartedprotectionchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/pdbchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/podlauncherchecker"
@@ -53,8 +56,10 @@ func basePluginsForKubelet() *framework.PluginCollection {
 		Filters: []*framework.PluginSpec{
 			framework.NewPluginSpec(podlauncher.Name),
 			framework.NewPluginSpec(coscheduling.Name),
+			framework.NewPluginSpec(elasticquota.Name),
 			framework.NewPluginSpec(nodeunschedulable.Name),
 			framework.NewPluginSpec(noderesources.FitName),
+			framework.NewPluginSpec(backfillreservation.Name),
 			framework.NewPluginSpec(nodeports.Name),
 			framework.NewPluginSpec(volumebinding.Name),
 			framework.NewPluginSpec(nodeaffinity.Name),
@@ -87,6 +92,7 @@ func basePluginsForKubelet() *framework.PluginCollection {
 			),
 			framework.NewVictimSearchingPluginCollectionSpec(
 				[]config.Plugin{
+					{Name: elasticquotachecker.ElasticQuotaCheckerName},
 					{Name: priorityvaluechecker.PriorityValueCheckerName},
 				},
 				false,
@@ -117,8 +123,10 @@ func basePluginsForNodeManager() *framework.PluginCollection {
 		Filters: []*framework.PluginSpec{
 			framework.NewPluginSpec(podlauncher.Name),
 			framework.NewPluginSpec(coscheduling.Name),
+			framework.NewPluginSpec(elasticquota.Name),
 			framework.NewPluginSpec(nodeunschedulable.Name),
 			framework.NewPluginSpec(noderesources.FitName),
+			framework.NewPluginSpec(backfillreservation.Name),
 			framework.NewPluginSpec(nodeports.Name),
 			framework.NewPluginSpec(volumebinding.Name),
 			framework.NewPluginSpec(nodeaffinity.Name),
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfillreservation
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	reservationstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/reservation_store"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "BackfillReservation"
+
+	// ErrReasonReserved is the reason when the free resources of the node are reserved for blocked units.
+	ErrReasonReserved = "node(s) had resources reserved for blocked units"
+)
+
+// BackfillReservation is a plugin that keeps pods away from the resources reserved for blocked units,
+// unless the pods could be preempted or will finish before the reservations expire.
+type BackfillReservation struct {
+	pluginHandle reservationstore.StoreHandle
+}
+
+var _ framework.FilterPlugin = &BackfillReservation{}
+
+// New initializes and returns a new BackfillReservation plugin.
+func New(_ runtime.Object, handle handle.PodFrameworkHandle) (framework.Plugin, error) {
+	var pluginHandle reservationstore.StoreHandle
+	if ins := handle.FindStore(reservationstore.Name); ins != nil {
+		pluginHandle = ins.(reservationstore.StoreHandle)
+	}
+
+	return &BackfillReservation{
+		pluginHandle: pluginHandle,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *BackfillReservation) Name() string {
+	return Name
+}
+
+// Filter rejects the node if the pod could only be placed into resources reserved for other units.
+func (pl *BackfillReservation) Filter(ctx context.Context, _ *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.BackfillScheduling) || pl.pluginHandle == nil {
+		return nil
+	}
+
+	reservations := pl.pluginHandle.GetBackfillReservationsOnNode(nodeInfo.GetNodeName())
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	resourceType, err := podutil.GetPodResourceType(pod)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+
+	reserved := &framework.Resource{}
+	now := time.Now()
+	for _, r := range reservations {
+		if r.ResourceType != resourceType || !reservationApplies(r, pod, now) {
+			continue
+		}
+		reserved.AddResource(r.Reserved[nodeInfo.GetNodeName()])
+	}
+	if reserved.IsZero() {
+		return nil
+	}
+
+	var allocatable, requested *framework.Resource
+	switch resourceType {
+	case podutil.GuaranteedPod:
+		allocatable, requested = nodeInfo.GetGuaranteedAllocatable(), nodeInfo.GetGuaranteedRequested()
+	case podutil.BestEffortPod:
+		allocatable, requested = nodeInfo.GetBestEffortAllocatable(), nodeInfo.GetBestEffortRequested()
+	}
+	if allocatable == nil {
+		return nil
+	}
+	if requested == nil {
+		requested = &framework.Resource{}
+	}
+
+	podRequest, _, _ := framework.CalculateResource(pod)
+	if exceeds(&podRequest, requested, reserved, allocatable) {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonReserved)
+	}
+	return nil
+}
+
+// reservationApplies returns true if the pod must keep away from the resources of the reservation.
+func reservationApplies(r *framework.BackfillReservation, pod *v1.Pod, now time.Time) bool {
+	if r.Expired(now) || r.UnitKey == utils.GetUnitIdentifier(pod) {
+		return false
+	}
+	if podutil.GetPodPriority(pod) >= r.Priority {
+		return false
+	}
+	if podutil.CanPodBePreempted(pod) == 1 {
+		return false
+	}
+	if d, ok := podutil.GetExpectedDuration(pod); ok && !now.Add(d).After(r.Deadline) {
+		return false
+	}
+	return true
+}
+
+// exceeds returns true if any resource reserved on the node could not hold the pod.
+func exceeds(podRequest, requested, reserved, allocatable *framework.Resource) bool {
+	if podRequest.MilliCPU > 0 && reserved.MilliCPU > 0 &&
+		podRequest.MilliCPU+requested.MilliCPU+reserved.MilliCPU > allocatable.MilliCPU {
+		return true
+	}
+	if podRequest.Memory > 0 && reserved.Memory > 0 &&
+		podRequest.Memory+requested.Memory+reserved.Memory > allocatable.Memory {
+		return true
+	}
+	for name, value := range podRequest.ScalarResources {
+		if value > 0 && reserved.ScalarResources[name] > 0 &&
+			value+requested.ScalarResources[name]+reserved.ScalarResources[name] > allocatable.ScalarResources[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backfillreservation
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+type fakeStoreHandle struct {
+	reservations []*framework.BackfillReservation
+}
+
+func (h *fakeStoreHandle) GetAvailableNodesAndPlaceholders(string, ...*v1.Pod) (framework.ReservationPlaceholdersOfNodes, error) {
+	return nil, nil
+}
+
+func (h *fakeStoreHandle) GetBackfillReservationsOnNode(nodeName string) []*framework.BackfillReservation {
+	var ret []*framework.BackfillReservation
+	for _, r := range h.reservations {
+		if _, ok := r.Reserved[nodeName]; ok {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+func makePod(name, cpu string, priority int32) *testinghelper.PodWrapper {
+	return testinghelper.MakePod().Namespace("default").Name(name).UID(name).Priority(priority).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu})
+}
+
+func TestBackfillReservationFilter(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.BackfillScheduling, true)()
+
+	node := testinghelper.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8", v1.ResourceMemory: "16Gi"}).Obj()
+	nodeInfo := framework.NewNodeInfo(makePod("running", "2", 0).Node("n1").Obj())
+	nodeInfo.SetNode(node)
+
+	now := time.Now()
+	reservation := &framework.BackfillReservation{
+		UnitKey:      "PodGroupUnit/default/pg",
+		Priority:     100,
+		Deadline:     now.Add(10 * time.Minute),
+		ResourceType: podutil.GuaranteedPod,
+		Reserved:     map[string]*framework.Resource{"n1": {MilliCPU: 4000}},
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		reservations []*framework.BackfillReservation
+		wantCode     framework.Code
+	}{
+		{
+			name:     "pod fits into unreserved resources",
+			pod:      makePod("p", "2", 0).Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "pod requires reserved resources",
+			pod:      makePod("p", "4", 0).Obj(),
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name:     "pod of the reserved unit",
+			pod:      makePod("p", "4", 0).Annotation(podutil.PodGroupNameAnnotationKey, "pg").Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "pod with higher priority",
+			pod:      makePod("p", "4", 100).Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name: "preemptible pod",
+			pod: makePod("p", "4", 0).PriorityClassName("low").
+				Annotation(util.CanBePreemptedAnnotationKey, util.CanBePreempted).Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name: "pod finishes before deadline",
+			pod: makePod("p", "4", 0).
+				Annotation(podutil.ExpectedDurationAnnotationKey, strconv.Itoa(300)).Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name: "pod finishes after deadline",
+			pod: makePod("p", "4", 0).
+				Annotation(podutil.ExpectedDurationAnnotationKey, strconv.Itoa(3600)).Obj(),
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name: "expired reservation",
+			pod:  makePod("p", "4", 0).Obj(),
+			reservations: []*framework.BackfillReservation{{
+				UnitKey:      "PodGroupUnit/default/pg",
+				Priority:     100,
+				Deadline:     now.Add(-time.Minute),
+				ResourceType: podutil.GuaranteedPod,
+				Reserved:     map[string]*framework.Resource{"n1": {MilliCPU: 4000}},
+			}},
+			wantCode: framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reservations := tt.reservations
+			if reservations == nil {
+				reservations = []*framework.BackfillReservation{reservation}
+			}
+			pl := &BackfillReservation{pluginHandle: &fakeStoreHandle{reservations: reservations}}
+
+			status := pl.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+			if got := status.Code(); got != tt.wantCode {
+				t.Errorf("expected code %v, got %v: %v", tt.wantCode, got, status.Message())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	quotastore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/quota_store"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// Name is the name of the plugin used in Registry and configurations.
+const Name = "ElasticQuota"
+
+// ElasticQuota is a plugin that rejects pods exceeding the max of their quota, and pods exceeding
+// the min of their quota when there are no idle resources of other quotas to borrow.
+type ElasticQuota struct {
+	pluginHandle quotastore.StoreHandle
+}
+
+var (
+	_ framework.PreFilterPlugin = &ElasticQuota{}
+	_ framework.FilterPlugin    = &ElasticQuota{}
+)
+
+// New initializes and returns a new ElasticQuota plugin.
+func New(_ runtime.Object, handle handle.PodFrameworkHandle) (framework.Plugin, error) {
+	var pluginHandle quotastore.StoreHandle
+	if ins := handle.FindStore(quotastore.Name); ins != nil {
+		pluginHandle = ins.(quotastore.StoreHandle)
+	}
+
+	return &ElasticQuota{
+		pluginHandle: pluginHandle,
+	}, nil
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *ElasticQuota) Name() string {
+	return Name
+}
+
+// PreFilter checks the request of the pod against the quota of its namespace.
+func (pl *ElasticQuota) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuota) || pl.pluginHandle == nil {
+		return nil
+	}
+
+	resourceType, err := podutil.GetPodResourceType(pod)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	quota := pl.pluginHandle.GetQuotaInfo(pod.Namespace, resourceType)
+	if quota == nil {
+		return nil
+	}
+
+	request := framework.NewResource(podutil.PodRequests(pod))
+	if name := quota.ExceedsMax(request); name != "" {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("exceeded max %v of ElasticQuota %v", name, quota.Name))
+	}
+	if quota.ExceedsMin(request) == "" {
+		return nil
+	}
+	// Borrowing is only allowed when the quotas don't use more than their min in total,
+	// so that the borrowed resources can always be reclaimed for the owners.
+	if name := pl.pluginHandle.GetAggregatedQuotaInfo(resourceType).ExceedsMin(request); name != "" {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("exceeded min %v of ElasticQuota %v and no idle quota to borrow", name, quota.Name))
+	}
+	return nil
+}
+
+func (pl *ElasticQuota) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter does nothing, quota is checked in PreFilter once for all nodes.
+func (pl *ElasticQuota) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	return nil
+}
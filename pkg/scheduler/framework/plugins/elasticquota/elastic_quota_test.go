@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	quotastore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/quota_store"
+	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeQuota(name, namespace, min, max string) *quotav1alpha1.ElasticQuota {
+	return &quotav1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: quotav1alpha1.ElasticQuotaSpec{
+			Namespaces: []string{namespace},
+			Guaranteed: quotav1alpha1.QuotaLimits{
+				Min: v1.ResourceList{v1.ResourceCPU: resource.MustParse(min)},
+				Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse(max)},
+			},
+		},
+	}
+}
+
+func makePod(namespace, name, cpu string) *v1.Pod {
+	return testinghelper.MakePod().Namespace(namespace).Name(name).UID(name).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu}).Obj()
+}
+
+func TestElasticQuotaPreFilter(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ElasticQuota, true)()
+
+	tests := []struct {
+		name     string
+		running  []*v1.Pod
+		pod      *v1.Pod
+		wantCode framework.Code
+	}{
+		{
+			name:     "namespace without quota",
+			pod:      makePod("default", "p", "100"),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "within min",
+			running:  []*v1.Pod{makePod("ns1", "r1", "1")},
+			pod:      makePod("ns1", "p", "1"),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "exceeds max",
+			running:  []*v1.Pod{makePod("ns1", "r1", "3")},
+			pod:      makePod("ns1", "p", "2"),
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name:     "borrows idle quota",
+			running:  []*v1.Pod{makePod("ns1", "r1", "2")},
+			pod:      makePod("ns1", "p", "1"),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "no idle quota to borrow",
+			running:  []*v1.Pod{makePod("ns1", "r1", "2"), makePod("ns2", "r2", "2")},
+			pod:      makePod("ns1", "p", "1"),
+			wantCode: framework.Unschedulable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := quotastore.NewSnapshot(nil).(*quotastore.QuotaStore)
+			store.AddElasticQuota(makeQuota("q1", "ns1", "2", "4"))
+			store.AddElasticQuota(makeQuota("q2", "ns2", "2", "4"))
+			for _, p := range tt.running {
+				if err := store.AssumePod(&framework.CachePodInfo{Pod: p}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pl := &ElasticQuota{pluginHandle: store}
+			status := pl.PreFilter(context.Background(), framework.NewCycleState(), tt.pod)
+			if got := status.Code(); got != tt.wantCode {
+				t.Errorf("expected code %v, got %v: %v", tt.wantCode, got, status.Message())
+			}
+		})
+	}
+}
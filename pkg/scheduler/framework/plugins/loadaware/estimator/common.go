@@ -21,6 +21,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
 // addResourceList adds the resources in newList to list
@@ -55,22 +56,18 @@ func maxResourceList(list, new corev1.ResourceList) {
 // total container resource requests and to the total container limits which have a
 // non-zero quantity.
 func PodRequestsAndLimits(pod *corev1.Pod) (reqs, limits corev1.ResourceList) {
-	reqs, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	reqs, limits = podutil.PodRequests(pod), corev1.ResourceList{}
 	for _, container := range pod.Spec.Containers {
-		addResourceList(reqs, container.Resources.Requests)
 		addResourceList(limits, container.Resources.Limits)
 	}
 	// init containers define the minimum of any resource
 	for _, container := range pod.Spec.InitContainers {
-		maxResourceList(reqs, container.Resources.Requests)
 		maxResourceList(limits, container.Resources.Limits)
 	}
 
 	// if PodOverhead feature is supported, add overhead for running a pod
-	// to the sum of reqeuests and to non-zero limits:
+	// to non-zero limits, requests have included the overhead:
 	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
-		addResourceList(reqs, pod.Spec.Overhead)
-
 		for name, quantity := range pod.Spec.Overhead {
 			if value, ok := limits[name]; ok && !value.IsZero() {
 				value.Add(quantity)
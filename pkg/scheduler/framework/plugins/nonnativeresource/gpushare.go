@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnativeresource
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	godelfeatures "github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/plugins/nonnativeresource"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
+)
+
+const GPUShareName = "GPUShare"
+
+// GPUShare places pods sharing gpus (requesting gpu memory or gpu core) onto individual gpu devices.
+type GPUShare struct {
+	handle handle.PodFrameworkHandle
+}
+
+var (
+	_ framework.FilterPlugin = &GPUShare{}
+	_ framework.ScorePlugin  = &GPUShare{}
+)
+
+func NewGPUShare(_ runtime.Object, handle handle.PodFrameworkHandle) (framework.Plugin, error) {
+	return &GPUShare{handle: handle}, nil
+}
+
+func (gs *GPUShare) Name() string {
+	return GPUShareName
+}
+
+func (gs *GPUShare) Filter(_ context.Context, _ *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
+	if !utilfeature.DefaultFeatureGate.Enabled(godelfeatures.NonNativeResourceSchedulingSupport) {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("featuregate %s is disabled", godelfeatures.NonNativeResourceSchedulingSupport))
+	}
+	return nonnativeresource.FeasibleGPUShare(pod, nodeInfo)
+}
+
+func (gs *GPUShare) Score(_ context.Context, _ *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := gs.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	return nonnativeresource.ScoreGPUShare(pod, nodeInfo), nil
+}
+
+func (gs *GPUShare) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
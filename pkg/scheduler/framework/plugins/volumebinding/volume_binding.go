@@ -18,25 +18,61 @@ package volumebinding
 
 import (
 	"context"
+	"fmt"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/framework/utils"
+	podgroupstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/podgroup_store"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
 	"github.com/kubewharf/godel-scheduler/pkg/volume/scheduling"
 )
 
 // VolumeBinding is a plugin that binds pod volumes in scheduling.
 type VolumeBinding struct {
-	binder scheduling.BaseVolumeBinder
+	binder       scheduling.BaseVolumeBinder
+	handle       handle.PodFrameworkHandle
+	pluginHandle podgroupstore.StoreHandle
+	// volumeInformers are the informers of PVs, PVCs and StorageClasses, which may be started on demand.
+	volumeInformers []cache.SharedIndexInformer
 }
 
-var _ framework.FilterPlugin = &VolumeBinding{}
+var (
+	_ framework.PreFilterPlugin = &VolumeBinding{}
+	_ framework.FilterPlugin    = &VolumeBinding{}
+	_ framework.ScorePlugin     = &VolumeBinding{}
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "VolumeBinding"
+
+	// preFilterStateKey is the key in CycleState to VolumeBinding pre-computed data.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonVolumeInformersNotSynced is used when the informers of volumes haven't synced.
+	ErrReasonVolumeInformersNotSynced = "volume informers not synced"
+)
+
+// activator is implemented by the informers which are not started until a pod with claims shows up.
+type activator interface {
+	Activate()
+}
 
-// Name is the name of the plugin used in Registry and configurations.
-const Name = "VolumeBinding"
+type preFilterState struct {
+	pendingClaims *scheduling.PendingClaims
+}
+
+// Clone the prefilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	// The state is not impacted by adding/removing existing pods, hence we don't need to make a deep copy.
+	return s
+}
 
 // Name returns name of the plugin. It is used in logs, etc.
 func (pl *VolumeBinding) Name() string {
@@ -45,13 +81,87 @@ func (pl *VolumeBinding) Name() string {
 
 func podHasPVCs(pod *v1.Pod) bool {
 	for _, vol := range pod.Spec.Volumes {
-		if vol.PersistentVolumeClaim != nil {
+		if vol.PersistentVolumeClaim != nil || vol.Ephemeral != nil {
 			return true
 		}
 	}
 	return false
 }
 
+// PreFilter invoked at the prefilter extension point. It collects the claims waiting to be provisioned,
+// including the unbound claims of the other members of the same gang placed in this scheduling cycle,
+// whose storage is reserved from the capacity of their topology segments.
+func (pl *VolumeBinding) PreFilter(ctx context.Context, cs *framework.CycleState, pod *v1.Pod) *framework.Status {
+	if !podHasPVCs(pod) {
+		return nil
+	}
+	if !pl.volumeInformersSynced() {
+		// The pod is moved back by the add events of PVs, PVCs and StorageClasses listed by the informers.
+		return framework.NewStatus(framework.Unschedulable, ErrReasonVolumeInformersNotSynced)
+	}
+	pendingClaims, err := pl.binder.GetPendingClaims(pod, pl.getPlacedGangMembers(pod))
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	cs.Write(preFilterStateKey, &preFilterState{pendingClaims: pendingClaims})
+	return nil
+}
+
+// volumeInformersSynced activates the volume informers started on demand, and checks whether all of them
+// have synced, so that the pods with claims are not scheduled against empty listers.
+func (pl *VolumeBinding) volumeInformersSynced() bool {
+	synced := true
+	for _, informer := range pl.volumeInformers {
+		if a, ok := informer.(activator); ok {
+			a.Activate()
+		}
+		if !informer.HasSynced() {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// PreFilterExtensions do not exist for this plugin.
+func (pl *VolumeBinding) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// getPlacedGangMembers returns the other members of the pod group of the pod, which have been placed
+// in the snapshot but whose volumes are not bound yet.
+func (pl *VolumeBinding) getPlacedGangMembers(pod *v1.Pod) []*scheduling.PlacedPod {
+	pgName := unitutil.GetPodGroupFullName(pod)
+	if len(pgName) == 0 || pl.pluginHandle == nil || pl.handle.SnapshotSharedLister() == nil {
+		return nil
+	}
+	var placedPods []*scheduling.PlacedPod
+	for _, p := range pl.pluginHandle.GetPlacedPods(pgName) {
+		if p.UID == pod.UID || !podHasPVCs(p) {
+			continue
+		}
+		nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(utils.GetNodeNameFromPod(p))
+		if err != nil || nodeInfo == nil {
+			continue
+		}
+		podLauncher, _ := podutil.GetPodLauncher(p)
+		placedPods = append(placedPods, &scheduling.PlacedPod{Pod: p, NodeLabels: nodeInfo.GetNodeLabels(podLauncher)})
+	}
+	return placedPods
+}
+
+func getPendingClaims(cs *framework.CycleState) (*scheduling.PendingClaims, error) {
+	c, err := cs.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v  convert to volumebinding.preFilterState error", c)
+	}
+	return s.pendingClaims, nil
+}
+
 // Filter invoked at the filter extension point.
 // It evaluates if a pod can fit due to the volumes it requests,
 // for both bound and unbound PVCs.
@@ -62,6 +172,9 @@ func podHasPVCs(pod *v1.Pod) bool {
 // For PVCs that are unbound, it tries to find available PVs that can satisfy the PVC requirements
 // and that the PV node affinity is satisfied by the given node.
 //
+// For PVCs that are unbound and to be provisioned by CSI drivers publishing storage capacity, it checks
+// that they fit into the capacity of a topology segment of the node, together with the pending claims.
+//
 // The predicate returns true if all bound PVCs have compatible PVs with the node, and if all unbound
 // PVCs can be matched with an available and node-compatible PV.
 func (pl *VolumeBinding) Filter(ctx context.Context, cs *framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
@@ -73,8 +186,13 @@ func (pl *VolumeBinding) Filter(ctx context.Context, cs *framework.CycleState, p
 		return nil
 	}
 
+	pendingClaims, err := getPendingClaims(cs)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
 	podLauncher, _ := podutil.GetPodLauncher(pod)
-	reasons, err := pl.binder.FindPodVolumes(pod, nodeInfo.GetNodeName(), nodeInfo.GetNodeLabels(podLauncher))
+	reasons, err := pl.binder.FindPodVolumes(pod, nodeInfo.GetNodeName(), nodeInfo.GetNodeLabels(podLauncher), pendingClaims)
 	if err != nil {
 		return framework.NewStatus(framework.Error, err.Error())
 	}
@@ -89,14 +207,56 @@ func (pl *VolumeBinding) Filter(ctx context.Context, cs *framework.CycleState, p
 	return nil
 }
 
+// Score invoked at the score extension point. Nodes whose topology segments have more storage capacity
+// left after provisioning the unbound PVCs of the pod get higher scores.
+func (pl *VolumeBinding) Score(ctx context.Context, cs *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	if !podHasPVCs(pod) {
+		return 0, nil
+	}
+	pendingClaims, err := getPendingClaims(cs)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+
+	podLauncher, _ := podutil.GetPodLauncher(pod)
+	score, err := pl.binder.ScorePodVolumes(pod, nodeName, nodeInfo.GetNodeLabels(podLauncher), pendingClaims)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	return int64(score * float64(framework.MaxNodeScore)), nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *VolumeBinding) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
 // New initializes a new plugin with volume binder and returns it.
 func New(_ runtime.Object, fh handle.PodFrameworkHandle) (framework.Plugin, error) {
+	informerFactory := fh.SharedInformerFactory()
+	var pluginHandle podgroupstore.StoreHandle
+	if ins := fh.FindStore(podgroupstore.Name); ins != nil {
+		pluginHandle = ins.(podgroupstore.StoreHandle)
+	}
 	return &VolumeBinding{
 		binder: scheduling.NewBaseVolumeBinder(
-			fh.SharedInformerFactory().Storage().V1().CSINodes(),
-			fh.SharedInformerFactory().Core().V1().PersistentVolumeClaims(),
-			fh.SharedInformerFactory().Core().V1().PersistentVolumes(),
-			fh.SharedInformerFactory().Storage().V1().StorageClasses(),
+			informerFactory.Core().V1().Nodes(),
+			informerFactory.Storage().V1().CSINodes(),
+			informerFactory.Core().V1().PersistentVolumeClaims(),
+			informerFactory.Core().V1().PersistentVolumes(),
+			informerFactory.Storage().V1().StorageClasses(),
+			scheduling.NewCapacityCheck(informerFactory),
 		),
+		handle:       fh,
+		pluginHandle: pluginHandle,
+		volumeInformers: []cache.SharedIndexInformer{
+			informerFactory.Core().V1().PersistentVolumes().Informer(),
+			informerFactory.Core().V1().PersistentVolumeClaims().Informer(),
+			informerFactory.Storage().V1().StorageClasses().Informer(),
+		},
 	}, nil
 }
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/volume/scheduling"
@@ -105,10 +106,57 @@ func TestVolumeBinding(t *testing.T) {
 			p := &VolumeBinding{
 				binder: fakeVolumeBinder,
 			}
-			gotStatus := p.Filter(context.Background(), nil, item.pod, nodeInfo)
+			state := framework.NewCycleState()
+			if status := p.PreFilter(context.Background(), state, item.pod); !status.IsSuccess() {
+				t.Fatalf("prefilter failed: %v", status)
+			}
+			gotStatus := p.Filter(context.Background(), state, item.pod, nodeInfo)
 			if !reflect.DeepEqual(gotStatus, item.wantStatus) {
 				t.Errorf("status does not match: %v, want: %v", gotStatus, item.wantStatus)
 			}
 		})
 	}
 }
+
+type fakeLazyInformer struct {
+	cache.SharedIndexInformer
+	activated bool
+	synced    bool
+}
+
+func (i *fakeLazyInformer) Activate() {
+	i.activated = true
+}
+
+func (i *fakeLazyInformer) HasSynced() bool {
+	return i.synced
+}
+
+func TestPreFilterWaitsForVolumeInformers(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{Volumes: []v1.Volume{{VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{}}}}}}
+	informer := &fakeLazyInformer{}
+	p := &VolumeBinding{
+		binder:          scheduling.NewFakeVolumeBinder(&scheduling.FakeVolumeBinderConfig{AllBound: true}),
+		volumeInformers: []cache.SharedIndexInformer{informer},
+	}
+
+	if status := p.PreFilter(context.Background(), framework.NewCycleState(), &v1.Pod{}); !status.IsSuccess() {
+		t.Fatalf("expected pod without claims to pass, got %v", status)
+	}
+	if informer.activated {
+		t.Fatalf("expected informer not to be activated by pod without claims")
+	}
+
+	status := p.PreFilter(context.Background(), framework.NewCycleState(), pod)
+	if want := framework.NewStatus(framework.Unschedulable, ErrReasonVolumeInformersNotSynced); !reflect.DeepEqual(status, want) {
+		t.Errorf("status does not match: %v, want: %v", status, want)
+	}
+	if !informer.activated {
+		t.Errorf("expected informer to be activated by pod with claims")
+	}
+
+	informer.synced = true
+	if status := p.PreFilter(context.Background(), framework.NewCycleState(), pod); !status.IsSuccess() {
+		t.Errorf("expected pod to pass once informers synced, got %v", status)
+	}
+}
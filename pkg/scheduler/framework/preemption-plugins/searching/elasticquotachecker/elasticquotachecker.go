@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquotachecker
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	quotastore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/quota_store"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+const (
+	ElasticQuotaCheckerName        = "ElasticQuotaChecker"
+	clusterPrePreemptingQuotaKey   = "ClusterPrePreempting-" + ElasticQuotaCheckerName
+	errReasonProtectedByQuotaMin   = "pod within the min of its ElasticQuota could not be preempted"
+	errReasonBorrowingAcrossQuotas = "pod borrowing idle quota could not preempt pods of other ElasticQuotas"
+)
+
+// ElasticQuotaChecker reclaims the resources borrowed by quotas using more than their min. A preemptor
+// within the min of its quota preempts pods of over-min quotas regardless of their priorities, and pods
+// of quotas within their min are never preempted by pods of other quotas. Pods of the same quota and
+// pods without quota are left to the following checkers.
+type ElasticQuotaChecker struct {
+	pluginHandle quotastore.StoreHandle
+}
+
+var (
+	_ framework.ClusterPrePreemptingPlugin = &ElasticQuotaChecker{}
+	_ framework.VictimSearchingPlugin      = &ElasticQuotaChecker{}
+)
+
+// NewElasticQuotaChecker initializes a new plugin and returns it.
+func NewElasticQuotaChecker(_ runtime.Object, handle handle.PodFrameworkHandle) (framework.Plugin, error) {
+	var pluginHandle quotastore.StoreHandle
+	if ins := handle.FindStore(quotastore.Name); ins != nil {
+		pluginHandle = ins.(quotastore.StoreHandle)
+	}
+	return &ElasticQuotaChecker{pluginHandle: pluginHandle}, nil
+}
+
+func (eqc *ElasticQuotaChecker) Name() string {
+	return ElasticQuotaCheckerName
+}
+
+func (eqc *ElasticQuotaChecker) ClusterPrePreempting(preemptor *v1.Pod, state, _ *framework.CycleState) *framework.Status {
+	s := &quotaState{}
+	if utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuota) && eqc.pluginHandle != nil {
+		if resourceType, err := podutil.GetPodResourceType(preemptor); err == nil {
+			if quota := eqc.pluginHandle.GetQuotaInfo(preemptor.Namespace, resourceType); quota != nil {
+				s.quotaName = quota.Name
+				s.borrowing = quota.ExceedsMin(framework.NewResource(podutil.PodRequests(preemptor))) != ""
+			}
+		}
+	}
+	state.Write(clusterPrePreemptingQuotaKey, s)
+	return nil
+}
+
+func (eqc *ElasticQuotaChecker) VictimSearching(preemptor *v1.Pod, podInfo *framework.PodInfo, state, _ *framework.CycleState, _ *framework.VictimState) (framework.Code, string) {
+	s, err := getQuotaState(state)
+	if err != nil {
+		return framework.Error, err.Error()
+	}
+	if len(s.quotaName) == 0 {
+		return framework.PreemptionNotSure, ""
+	}
+
+	resourceType, err := podutil.GetPodResourceType(podInfo.Pod)
+	if err != nil {
+		return framework.PreemptionNotSure, ""
+	}
+	quota := eqc.pluginHandle.GetQuotaInfo(podInfo.Pod.Namespace, resourceType)
+	if quota == nil || quota.Name == s.quotaName {
+		return framework.PreemptionNotSure, ""
+	}
+	if s.borrowing {
+		return framework.PreemptionFail, errReasonBorrowingAcrossQuotas
+	}
+	if !quota.OverMin() {
+		return framework.PreemptionFail, errReasonProtectedByQuotaMin
+	}
+	return framework.PreemptionSucceed, ""
+}
+
+type quotaState struct {
+	// quotaName is the ElasticQuota of the preemptor, empty if there's none.
+	quotaName string
+	// borrowing is true if the preemptor exceeds the min of its quota.
+	borrowing bool
+}
+
+func (state *quotaState) Clone() framework.StateData {
+	return state
+}
+
+func getQuotaState(state *framework.CycleState) (*quotaState, error) {
+	c, err := state.Read(clusterPrePreemptingQuotaKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q from cycleState: %v", clusterPrePreemptingQuotaKey, err)
+	}
+
+	s, ok := c.(*quotaState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to ElasticQuotaChecker.quotaState error", c)
+	}
+	return s, nil
+}
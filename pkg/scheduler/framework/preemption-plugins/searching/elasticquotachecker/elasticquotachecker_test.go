@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquotachecker
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	quotav1alpha1 "github.com/kubewharf/godel-scheduler/pkg/apis/quota/v1alpha1"
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	quotastore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/quota_store"
+	testing_helper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+func makeQuota(name, namespace string) *quotav1alpha1.ElasticQuota {
+	return &quotav1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: quotav1alpha1.ElasticQuotaSpec{
+			Namespaces: []string{namespace},
+			Guaranteed: quotav1alpha1.QuotaLimits{
+				Min: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			},
+		},
+	}
+}
+
+func makePod(namespace, name, cpu string) *v1.Pod {
+	return testing_helper.MakePod().Namespace(namespace).Name(name).UID(name).
+		Annotation(podutil.PodResourceTypeAnnotationKey, string(podutil.GuaranteedPod)).
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu}).Obj()
+}
+
+func TestElasticQuotaChecker(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ElasticQuota, true)()
+
+	tests := []struct {
+		name         string
+		running      []*v1.Pod
+		preemptor    *v1.Pod
+		victim       *v1.Pod
+		expectedCode framework.Code
+	}{
+		{
+			name:         "preemptor without quota",
+			preemptor:    makePod("default", "p", "1"),
+			victim:       makePod("ns2", "v", "1"),
+			expectedCode: framework.PreemptionNotSure,
+		},
+		{
+			name:         "victim in the same quota",
+			preemptor:    makePod("ns1", "p", "1"),
+			victim:       makePod("ns1", "v", "1"),
+			expectedCode: framework.PreemptionNotSure,
+		},
+		{
+			name:         "victim without quota",
+			preemptor:    makePod("ns1", "p", "1"),
+			victim:       makePod("default", "v", "1"),
+			expectedCode: framework.PreemptionNotSure,
+		},
+		{
+			name:         "reclaim from quota over min",
+			running:      []*v1.Pod{makePod("ns2", "r", "3")},
+			preemptor:    makePod("ns1", "p", "1"),
+			victim:       makePod("ns2", "v", "1"),
+			expectedCode: framework.PreemptionSucceed,
+		},
+		{
+			name:         "victim protected by min",
+			running:      []*v1.Pod{makePod("ns2", "r", "2")},
+			preemptor:    makePod("ns1", "p", "1"),
+			victim:       makePod("ns2", "v", "1"),
+			expectedCode: framework.PreemptionFail,
+		},
+		{
+			name:         "borrowing preemptor",
+			running:      []*v1.Pod{makePod("ns1", "r1", "2"), makePod("ns2", "r2", "3")},
+			preemptor:    makePod("ns1", "p", "1"),
+			victim:       makePod("ns2", "v", "1"),
+			expectedCode: framework.PreemptionFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := quotastore.NewSnapshot(nil).(*quotastore.QuotaStore)
+			store.AddElasticQuota(makeQuota("q1", "ns1"))
+			store.AddElasticQuota(makeQuota("q2", "ns2"))
+			for _, p := range tt.running {
+				if err := store.AssumePod(&framework.CachePodInfo{Pod: p}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			checker := &ElasticQuotaChecker{pluginHandle: store}
+			state := framework.NewCycleState()
+			if gotStatus := checker.ClusterPrePreempting(tt.preemptor, state, nil); gotStatus != nil {
+				t.Fatalf("failed to run prepare preemption plugin: %v", gotStatus)
+			}
+			gotCode, gotMsg := checker.VictimSearching(tt.preemptor, framework.NewPodInfo(tt.victim), state, nil, nil)
+			if gotCode != tt.expectedCode {
+				t.Errorf("expected code %v, got %v: %v", tt.expectedCode, gotCode, gotMsg)
+			}
+		})
+	}
+}
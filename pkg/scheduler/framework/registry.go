@@ -24,7 +24,9 @@ import (
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	schedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/handle"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/backfillreservation"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/coscheduling"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/elasticquota"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/imagelocality"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/loadaware"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/nodeaffinity"
@@ -38,6 +40,7 @@ import (
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/podlauncher"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/tainttoleration"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/plugins/volumebinding"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/elasticquotachecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/newlystartedprotectionchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/pdbchecker"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/framework/preemption-plugins/searching/podlauncherchecker"
@@ -76,15 +79,18 @@ func NewOrderedPluginRegistry() framework.PluginList {
 			// only Unschedulable
 			nodeports.Name,
 			noderesources.FitName,
+			backfillreservation.Name,
 			nodevolumelimits.CSIName,
 			nodevolumelimits.CinderName,
 			nodevolumelimits.AzureDiskName,
 			nodevolumelimits.GCEPDName,
 			nodevolumelimits.EBSName,
 			nonnativeresource.NonNativeTopologyName,
+			nonnativeresource.GPUShareName,
 
 			// always Success
 			coscheduling.Name,
+			elasticquota.Name,
 		},
 	}
 }
@@ -106,6 +112,7 @@ func NewInTreeRegistry() Registry {
 		podlauncher.Name:                        podlauncher.New,
 		volumebinding.Name:                      volumebinding.New,
 		nonnativeresource.NonNativeTopologyName: nonnativeresource.NewNonNativeTopology,
+		nonnativeresource.GPUShareName:          nonnativeresource.NewGPUShare,
 		// TODO: remove it, use NonNativeResourceSelector & NonNativeTopology instead  @songxinyi.echo
 
 		nodevolumelimits.CSIName:       nodevolumelimits.NewCSI,
@@ -122,6 +129,9 @@ func NewInTreeRegistry() Registry {
 		noderesources.NodeResourcesAffinityName: noderesources.NewNodeResourcesAffinity,
 
 		loadaware.Name: loadaware.NewLoadAware,
+
+		backfillreservation.Name: backfillreservation.New,
+		elasticquota.Name:        elasticquota.New,
 	}
 }
 
@@ -132,6 +142,7 @@ func NewInTreePreemptionRegistry() Registry {
 		preemptibilitychecker.PreemptibilityCheckerName:                 preemptibilitychecker.NewPreemptibilityChecker,
 		pdbchecker.PDBCheckerName:                                       pdbchecker.NewPDBChecker,
 		priorityvaluechecker.PriorityValueCheckerName:                   priorityvaluechecker.NewPriorityValueChecker,
+		elasticquotachecker.ElasticQuotaCheckerName:                     elasticquotachecker.NewElasticQuotaChecker,
 		newlystartedprotectionchecker.NewlyStartedProtectionCheckerName: newlystartedprotectionchecker.NewNewlyStartedProtectionChecker,
 		// sorting plugins
 		priority.MinHighestPriorityName:       priority.NewMinHighestPriority,
@@ -93,7 +93,7 @@ func (f *GodelSchedulerFramework) RunPreFilterPlugins(ctx context.Context, state
 			// Haven't return even if not in debug mode
 			if finalStatus == nil {
 				if status.IsUnschedulable() {
-					finalStatus = status
+					finalStatus = status.WithFailedPlugin(pl.Name())
 				} else {
 					msg := fmt.Sprintf("Failed to run PreFilter plugin %q for pod %q: %v", pl.Name(), pod.Name, status.Message())
 					klog.ErrorS(nil, "Failed to run PreFilter plugin", "pluginName", pl.Name(), "pod", klog.KObj(pod), "statusMessage", status.Message())
@@ -21,6 +21,7 @@ import (
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
+	metricsutil "github.com/kubewharf/godel-scheduler/pkg/util/metrics"
 )
 
 // frameworkMetric is the data structure passed in the buffer channel between the main framework thread
@@ -43,6 +44,10 @@ type MetricsRecorder struct {
 	// how often the recorder runs to flush the metrics.
 	interval time.Duration
 
+	// qos and subCluster identify the switch type of the scheduling workflow the recorder belongs to.
+	qos        string
+	subCluster string
+
 	// stopCh is used to stop the goroutine which periodically flushes metrics. It's currently only
 	// used in tests.
 	stopCh chan struct{}
@@ -56,6 +61,8 @@ func NewMetricsRecorder(bufferSize int, interval time.Duration, switchType frame
 		bufferCh:    make(chan *frameworkMetric, bufferSize),
 		bufferSize:  bufferSize,
 		interval:    interval,
+		qos:         metricsutil.SwitchTypeToQos(switchType),
+		subCluster:  subCluster,
 		stopCh:      make(chan struct{}),
 		isStoppedCh: make(chan struct{}),
 	}
@@ -66,7 +73,7 @@ func NewMetricsRecorder(bufferSize int, interval time.Duration, switchType frame
 // observePluginDurationAsync observes the plugin_execution_duration_seconds metric.
 // The metric will be flushed to Prometheus asynchronously.
 func (r *MetricsRecorder) observePluginDurationAsync(podProperty *framework.PodProperty, extensionPoint, pluginName string, status *framework.Status, nodeGroupKey string, value float64) {
-	if podProperty == nil {
+	if r == nil || podProperty == nil {
 		return
 	}
 
@@ -104,6 +111,7 @@ func (r *MetricsRecorder) flushMetrics() {
 		select {
 		case m := <-r.bufferCh:
 			metrics.PodSchedulingStageDurationObserve(m.podProperty, m.operation, m.plugin, m.status, m.nodegroup, m.duration)
+			metrics.PluginExecutionObserve(m.operation, m.plugin, m.status, r.qos, r.subCluster, m.duration)
 		default:
 			return
 		}
@@ -19,12 +19,15 @@ package runtime
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
 
@@ -37,12 +40,16 @@ type GodelSchedulerPreemptionFramework struct {
 	postVictimSearchingPlugins  []framework.PostVictimSearchingPlugin
 	nodePostPreemptingPlugins   []framework.NodePostPreemptingPlugin
 	candidatesSortingPlugins    []framework.CandidatesSortingPlugin
+
+	metricsRecorder *MetricsRecorder
 }
 
 func NewPreemptionFramework(preemptionPluginRegistry framework.PluginMap,
 	basePlugins *framework.PluginCollection,
+	metricsRecorder *MetricsRecorder,
 ) *GodelSchedulerPreemptionFramework {
 	f := &GodelSchedulerPreemptionFramework{
+		metricsRecorder:             metricsRecorder,
 		victimSearchingPlugins:      make([]*framework.VictimSearchingPluginCollection, 0),
 		clusterPrePreemptingPlugins: make([]framework.ClusterPrePreemptingPlugin, 0),
 		nodePrePreemptingPlugins:    make([]framework.NodePrePreemptingPlugin, 0),
@@ -163,22 +170,42 @@ func (f *GodelSchedulerPreemptionFramework) RunVictimSearchingPlugins(preemptor
 
 func (f *GodelSchedulerPreemptionFramework) RunClusterPrePreemptingPlugins(preemptor *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for _, pl := range f.clusterPrePreemptingPlugins {
-		if err := pl.ClusterPrePreempting(preemptor, state, commonState); err != nil {
+		if err := f.runClusterPrePreemptingPlugin(pl, preemptor, state, commonState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelSchedulerPreemptionFramework) runClusterPrePreemptingPlugin(pl framework.ClusterPrePreemptingPlugin, preemptor *v1.Pod, state, commonState *framework.CycleState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return pl.ClusterPrePreempting(preemptor, state, commonState)
+	}
+	startTime := time.Now()
+	status := pl.ClusterPrePreempting(preemptor, state, commonState)
+	f.observePluginDuration(state, metrics.ClusterPrePreemptingEvaluation, pl.Name(), status, startTime)
+	return status
+}
+
 func (f *GodelSchedulerPreemptionFramework) RunNodePrePreemptingPlugins(preemptor *v1.Pod, nodeInfo framework.NodeInfo, state, preemptionState *framework.CycleState) *framework.Status {
 	for _, pl := range f.nodePrePreemptingPlugins {
-		if err := pl.NodePrePreempting(preemptor, nodeInfo, state, preemptionState); err != nil {
+		if err := f.runNodePrePreemptingPlugin(pl, preemptor, nodeInfo, state, preemptionState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelSchedulerPreemptionFramework) runNodePrePreemptingPlugin(pl framework.NodePrePreemptingPlugin, preemptor *v1.Pod, nodeInfo framework.NodeInfo, state, preemptionState *framework.CycleState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return pl.NodePrePreempting(preemptor, nodeInfo, state, preemptionState)
+	}
+	startTime := time.Now()
+	status := pl.NodePrePreempting(preemptor, nodeInfo, state, preemptionState)
+	f.observePluginDuration(state, metrics.NodePrePreemptingEvaluation, pl.Name(), status, startTime)
+	return status
+}
+
 func (f *GodelSchedulerPreemptionFramework) runVictimSearchingPluginCollection(
 	pluginCollection *framework.VictimSearchingPluginCollection,
 	preemptor *v1.Pod, podInfo *framework.PodInfo,
@@ -186,7 +213,7 @@ func (f *GodelSchedulerPreemptionFramework) runVictimSearchingPluginCollection(
 	victimState *framework.VictimState,
 ) (framework.Code, string) {
 	for _, plugin := range pluginCollection.GetVictimSearchingPlugins() {
-		code, msg := plugin.VictimSearching(preemptor, podInfo, state, preemptionState, victimState)
+		code, msg := f.runVictimSearchingPlugin(plugin, preemptor, podInfo, state, preemptionState, victimState)
 		switch code {
 		case framework.PreemptionFail:
 			return code, msg
@@ -203,24 +230,67 @@ func (f *GodelSchedulerPreemptionFramework) runVictimSearchingPluginCollection(
 	return framework.PreemptionNotSure, ""
 }
 
+func (f *GodelSchedulerPreemptionFramework) runVictimSearchingPlugin(
+	plugin framework.VictimSearchingPlugin,
+	preemptor *v1.Pod, podInfo *framework.PodInfo,
+	state, preemptionState *framework.CycleState,
+	victimState *framework.VictimState,
+) (framework.Code, string) {
+	if !state.ShouldRecordPluginMetrics() {
+		return plugin.VictimSearching(preemptor, podInfo, state, preemptionState, victimState)
+	}
+	startTime := time.Now()
+	code, msg := plugin.VictimSearching(preemptor, podInfo, state, preemptionState, victimState)
+	f.observePluginDuration(state, metrics.VictimSearchingEvaluation, plugin.Name(), framework.NewStatus(code), startTime)
+	return code, msg
+}
+
 func (f *GodelSchedulerPreemptionFramework) RunPostVictimSearchingPlugins(preemptor *v1.Pod, podInfo *framework.PodInfo, state, preemptionState *framework.CycleState, victimState *framework.VictimState) *framework.Status {
 	for _, plugin := range f.postVictimSearchingPlugins {
-		if err := plugin.PostVictimSearching(preemptor, podInfo, state, preemptionState, victimState); err != nil {
+		if err := f.runPostVictimSearchingPlugin(plugin, preemptor, podInfo, state, preemptionState, victimState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelSchedulerPreemptionFramework) runPostVictimSearchingPlugin(plugin framework.PostVictimSearchingPlugin, preemptor *v1.Pod, podInfo *framework.PodInfo, state, preemptionState *framework.CycleState, victimState *framework.VictimState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return plugin.PostVictimSearching(preemptor, podInfo, state, preemptionState, victimState)
+	}
+	startTime := time.Now()
+	status := plugin.PostVictimSearching(preemptor, podInfo, state, preemptionState, victimState)
+	f.observePluginDuration(state, metrics.PostVictimSearchingEvaluation, plugin.Name(), status, startTime)
+	return status
+}
+
 func (f *GodelSchedulerPreemptionFramework) RunNodePostPreemptingPlugins(preemptor *v1.Pod, victims []*v1.Pod, state, commonState *framework.CycleState) *framework.Status {
 	for _, plugin := range f.nodePostPreemptingPlugins {
-		if err := plugin.NodePostPreempting(preemptor, victims, state, commonState); err != nil {
+		if err := f.runNodePostPreemptingPlugin(plugin, preemptor, victims, state, commonState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (f *GodelSchedulerPreemptionFramework) runNodePostPreemptingPlugin(plugin framework.NodePostPreemptingPlugin, preemptor *v1.Pod, victims []*v1.Pod, state, commonState *framework.CycleState) *framework.Status {
+	if !state.ShouldRecordPluginMetrics() {
+		return plugin.NodePostPreempting(preemptor, victims, state, commonState)
+	}
+	startTime := time.Now()
+	status := plugin.NodePostPreempting(preemptor, victims, state, commonState)
+	f.observePluginDuration(state, metrics.NodePostPreemptingEvaluation, plugin.Name(), status, startTime)
+	return status
+}
+
+// observePluginDuration hands the duration and status of a preemption plugin over to the metrics recorder.
+func (f *GodelSchedulerPreemptionFramework) observePluginDuration(state *framework.CycleState, extensionPoint, pluginName string, status *framework.Status, startTime time.Time) {
+	// The node group key may be missing if preemption is triggered outside of a node group, leave it empty then.
+	nodeGroupKey, _ := framework.GetNodeGroupKey(state)
+	podProperty, _ := framework.GetPodProperty(state)
+	f.metricsRecorder.observePluginDurationAsync(podProperty, extensionPoint, pluginName, status, nodeGroupKey, helper.SinceInSeconds(startTime))
+}
+
 func (f *GodelSchedulerPreemptionFramework) RunCandidatesSortingPlugins(
 	candidates []*framework.Candidate,
 	candidate *framework.Candidate,
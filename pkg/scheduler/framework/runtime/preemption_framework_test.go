@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
 	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
 )
 
@@ -259,3 +260,49 @@ func TestRunVictimSearchingPluginCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestRunVictimSearchingPluginsRecordsSampledMetrics(t *testing.T) {
+	tests := []struct {
+		name                string
+		recordPluginMetrics bool
+		expectedMetrics     []frameworkMetric
+	}{
+		{
+			name:                "sampled cycle",
+			recordPluginMetrics: true,
+			expectedMetrics: []frameworkMetric{
+				{operation: metrics.VictimSearchingEvaluation, plugin: (&VictimSearchingPluginNotSure{}).Name(), status: framework.PreemptionNotSure.String()},
+				{operation: metrics.VictimSearchingEvaluation, plugin: (&VictimSearchingPluginFail{}).Name(), status: framework.PreemptionFail.String()},
+			},
+		},
+		{
+			name:                "not sampled cycle",
+			recordPluginMetrics: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &MetricsRecorder{bufferCh: make(chan *frameworkMetric, 10), bufferSize: 10}
+			f := &GodelSchedulerPreemptionFramework{metricsRecorder: recorder}
+			pluginCollection := framework.NewVictimSearchingPluginCollection([]framework.VictimSearchingPlugin{
+				&VictimSearchingPluginNotSure{},
+				&VictimSearchingPluginFail{},
+			}, true, false, false)
+
+			state := framework.NewCycleState()
+			state.SetRecordPluginMetrics(tt.recordPluginMetrics)
+			framework.SetPodProperty(&framework.PodProperty{}, state)
+			f.runVictimSearchingPluginCollection(pluginCollection, nil, nil, state, nil, nil)
+
+			close(recorder.bufferCh)
+			var gotMetrics []frameworkMetric
+			for m := range recorder.bufferCh {
+				gotMetrics = append(gotMetrics, frameworkMetric{operation: m.operation, plugin: m.plugin, status: m.status})
+			}
+			if !reflect.DeepEqual(tt.expectedMetrics, gotMetrics) {
+				t.Errorf("expected metrics: %v, but got: %v", tt.expectedMetrics, gotMetrics)
+			}
+		})
+	}
+}
@@ -63,28 +63,31 @@ func (i *Reservation) Locating(ctx context.Context, unit framework.ScheduleUnit,
 		return nodeGroup, nil
 	}
 	// TODO: More precise inspection rules.
-	if unit.Type() == framework.PodGroupUnitType {
-		// We don't support gang reservation for now.
-		return nodeGroup, nil
-	}
-
+	// Pods in the same unit (e.g. pod group) are expected to share the same placeholder.
 	placeholder := ""
+	pods := make([]*v1.Pod, 0, len(unit.GetPods()))
 	for _, podInfo := range unit.GetPods() {
 		if podInfo == nil || podInfo.Pod == nil {
 			continue
 		}
+		// placeholder pods of proactive reservations are reserving resources, they should never consume reserved resources.
+		if podutil.IsProactiveReservationPlaceholderPod(podInfo.Pod) {
+			continue
+		}
 
-		placeholder = podutil.GetReservationPlaceholder(podInfo.Pod)
-		if len(placeholder) > 0 {
-			break
+		ph := podutil.GetReservationPlaceholder(podInfo.Pod)
+		if len(ph) == 0 || (len(placeholder) > 0 && ph != placeholder) {
+			continue
 		}
+		placeholder = ph
+		pods = append(pods, podInfo.Pod)
 	}
 
 	if len(placeholder) == 0 {
 		return nodeGroup, nil
 	}
 
-	availablePlaceholders, err := i.pluginHandle.GetAvailableNodesAndPlaceholders(placeholder)
+	availablePlaceholders, err := i.pluginHandle.GetAvailableNodesAndPlaceholders(placeholder, pods...)
 	if err != nil {
 		// metrics.IncReservationAttempt(podProperty, metrics.FailureResult, "no_available_placeholders")
 		klog.InfoS("Failed to locate reservation placeholder", "unit", unit.GetKey(), "placeholder", placeholder, "err", err)
@@ -101,7 +104,8 @@ func (i *Reservation) Locating(ctx context.Context, unit framework.ScheduleUnit,
 		nodeGroup.GetPreferredNodes().Add(nodeInfo, i)
 	}
 
-	// TODO: Distribute all placeholders to different nodegroups when podgroup supports reservation.
+	// All placeholders are preferred in the same node group, pods of a pod group consume different
+	// placeholders since a placeholder is removed from the unit state once it is matched.
 	unitCycleState.Write(unitStateKey,
 		&unitState{
 			index:                   availablePlaceholders,
@@ -134,22 +138,25 @@ func (i *Reservation) PrePreferNode(ctx context.Context, unitCycleState, podCycl
 		return nodeInfo, podCycleState, nil
 	}
 
-	// get a placeholder pod
+	// get a placeholder pod, prefer the one reserved for the pod with the same identity.
 	// TODO: sort reserved pod inorder to reduce failure rate
 	var fakePod *v1.Pod = nil
+	identity := podutil.GetReservationIdentity(pod)
 	for _, p := range fakePods {
-		if p != nil {
+		if p == nil || !podutil.MatchReservationIdentity(pod, p) {
+			continue
+		}
+		if fakePod == nil || (len(identity) > 0 && podutil.GetReservationIdentity(p) == identity) {
 			fakePod = p
-			// set matched placeholder pod
-			pod.Annotations[podutil.MatchedReservationPlaceholderKey] = podutil.GetPodKey(p)
-			delete(fakePods, podutil.GetPodKey(p)) // remove used fakePod
-			break
 		}
 	}
 
 	if fakePod == nil {
 		return nodeInfo, podCycleState, nil
 	}
+	// set matched placeholder pod
+	pod.Annotations[podutil.MatchedReservationPlaceholderKey] = podutil.GetPodKey(fakePod)
+	delete(fakePods, podutil.GetPodKey(fakePod)) // remove used fakePod
 
 	// deep copy & remove fake pod from nodeInfo.
 	nodeCopy := nodeInfo.Clone()
@@ -18,6 +18,7 @@ package unitruntime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -281,7 +282,7 @@ func (f *UnitFramework) Preempting(ctx context.Context, unitInfo *core.Schedulin
 	result := &core.UnitPreemptionResult{
 		SuccessfulPods: []string{},
 		FailedPods:     []string{},
-		Details:        interpretabity.NewUnitSchedulingDetails(interpretabity.Scheduling, needPreempt),
+		Details:        interpretabity.NewUnitSchedulingDetails(interpretabity.Preempting, needPreempt),
 	}
 
 	commonPreemptionState := framework.NewCycleState()
@@ -300,6 +301,7 @@ func (f *UnitFramework) Preempting(ctx context.Context, unitInfo *core.Schedulin
 				unitInfo.QueuedUnitInfo.QueuePriorityScore, unitInfo.UnitCycleState, commonPreemptionState,
 				nodeGroup, unitInfo.NodeToStatusMapByTemplate[tmplKey], templateToNominatedNodes[runningUnitInfo.QueuedPodInfo.OwnerReferenceKey])
 			defer tracing.AsyncFinishTraceContext(preemptTraceContext, time.Now())
+			result.Details.AddPreemptionAttempt(err)
 
 			if scheduled {
 				klog.V(4).InfoS("Pod was able to preempt in this attempt",
@@ -542,7 +544,14 @@ func (f *UnitFramework) preemptOneUnitInstance(ctx context.Context, scheduledInd
 		preemptionTraceContext.WithFields(tracing.WithReasonField(fmt.Sprintf("Failed to run preemption")), tracing.WithErrorField(err))
 		errMessage := fmt.Sprintf("Fail to preempt for this pod in node group: %v, err: %v", nodeGroup.GetKey(), err)
 		f.schedulerHooks.EventRecorder().Eventf(clonedPod, nil, v1.EventTypeWarning, "FailToPreempt", core.ReturnAction, helper.TruncateMessage(errMessage))
-		return false, err
+		if err == nil {
+			return false, &framework.PreemptionError{Reason: "no nominated node found"}
+		}
+		var preemptionErr *framework.PreemptionError
+		if errors.As(err, &preemptionErr) {
+			return false, preemptionErr
+		}
+		return false, &framework.PreemptionError{Reason: framework.Error.String(), Message: err.Error()}
 	}
 
 	klog.V(4).InfoS("Pod can be placed by evicting some other pods",
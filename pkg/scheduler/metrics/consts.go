@@ -45,6 +45,21 @@ const (
 	// ScoreNormalizeEvaluation - Score normalize evaluation operation label value
 	ScoreNormalizeEvaluation = "score_normalize_evaluation"
 
+	// ClusterPrePreemptingEvaluation - ClusterPrePreempting evaluation operation label value
+	ClusterPrePreemptingEvaluation = "cluster_prepreempting_evaluation"
+
+	// NodePrePreemptingEvaluation - NodePrePreempting evaluation operation label value
+	NodePrePreemptingEvaluation = "node_prepreempting_evaluation"
+
+	// VictimSearchingEvaluation - VictimSearching evaluation operation label value
+	VictimSearchingEvaluation = "victim_searching_evaluation"
+
+	// PostVictimSearchingEvaluation - PostVictimSearching evaluation operation label value
+	PostVictimSearchingEvaluation = "post_victim_searching_evaluation"
+
+	// NodePostPreemptingEvaluation - NodePostPreempting evaluation operation label value
+	NodePostPreemptingEvaluation = "node_postpreempting_evaluation"
+
 	// SuccessResult - result label value
 	SuccessResult = "success"
 
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	pkgmetrics "github.com/kubewharf/godel-scheduler/pkg/common/metrics"
+)
+
+// Plugin metrics are only recorded for the sampled scheduling cycles, see PluginMetricsSamplePercent.
+
+var (
+	pluginExecutionDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "plugin_execution_duration_seconds",
+			Help:           "Duration for running a plugin at a specific extension point, in seconds.",
+			Buckets:        metrics.ExponentialBuckets(0.00001, 1.5, 20),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ExtensionPointLabel, pkgmetrics.PluginLabel, pkgmetrics.StatusLabel, pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.SchedulerLabel})
+
+	pluginEvaluationTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "plugin_evaluation_total",
+			Help:           "Number of plugin evaluations at a specific extension point, by the returned status code.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.ExtensionPointLabel, pkgmetrics.PluginLabel, pkgmetrics.StatusLabel, pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.SchedulerLabel})
+)
+
+// PluginExecutionObserve records the duration and the status code of one plugin execution.
+// qos and subCluster identify the switch type of the scheduling workflow running the plugin.
+func PluginExecutionObserve(extensionPoint, plugin, status, qos, subCluster string, duration float64) {
+	labels := metrics.Labels{
+		pkgmetrics.ExtensionPointLabel: extensionPoint,
+		pkgmetrics.PluginLabel:         plugin,
+		pkgmetrics.StatusLabel:         status,
+		pkgmetrics.QosLabel:            qos,
+		pkgmetrics.SubClusterLabel:     subCluster,
+	}
+	if len(subCluster) == 0 {
+		labels[pkgmetrics.SubClusterLabel] = pkgmetrics.UndefinedLabelValue
+	}
+	setScheduler(labels)
+	pluginExecutionDuration.With(labels).Observe(duration)
+	pluginEvaluationTotal.With(labels).Inc()
+}
@@ -36,6 +36,8 @@ var metricsList = []metrics.Registerable{
 	podE2ESchedulingLatencyQuantile,
 	queueSortingLatency,
 	podSchedulingStageDuration,
+	pluginExecutionDuration,
+	pluginEvaluationTotal,
 	schedulingUpdateSnapshotDuration,
 	schedulingAlgorithmDuration,
 	preemptingEvaluationDuration,
@@ -65,6 +67,7 @@ var metricsList = []metrics.Registerable{
 
 	schedulerUnitE2ELatency,
 	unitScheduleResult,
+	headOfLineBlockingUnits,
 }
 
 // SchedulerName name of scheduler to produce metrics
@@ -67,6 +67,14 @@ var (
 			Buckets:        []float64{1, 10, 50, 100, 500, 1000, 5000, 10000},
 			StabilityLevel: metrics.ALPHA,
 		}, []string{pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.UnitTypeLabel, pkgmetrics.SchedulerLabel, pkgmetrics.ResultLabel})
+
+	headOfLineBlockingUnits = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "head_of_line_blocking_units_total",
+			Help:           "Number of times a gang unit blocking the head of the queue is popped for scheduling.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{pkgmetrics.QosLabel, pkgmetrics.SubClusterLabel, pkgmetrics.UnitTypeLabel, pkgmetrics.SchedulerLabel})
 )
 
 // newSchedulerQueueIncomingUnitsCounterMetric returns the CounterMetric for given labels by SchedulerQueueIncomingUnits
@@ -138,3 +146,13 @@ func UnitScheduleResultObserve(unitProperty api.UnitProperty, result string, min
 	unitLabels[pkgmetrics.ResultLabel] = result
 	newUnitScheduleResultObserverMetric(unitLabels).Observe(minMember)
 }
+
+func newHeadOfLineBlockingUnitsCounterMetric(labels metrics.Labels) metrics.CounterMetric {
+	setScheduler(labels)
+	return headOfLineBlockingUnits.With(labels)
+}
+
+// HeadOfLineBlockingUnitsInc Invoke Inc method
+func HeadOfLineBlockingUnitsInc(unitProperty api.UnitProperty) {
+	newHeadOfLineBlockingUnitsCounterMetric(api.MustConvertToMetricsLabels(unitProperty)).Inc()
+}
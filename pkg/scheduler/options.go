@@ -21,6 +21,9 @@ import (
 	"reflect"
 	"strings"
 
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
@@ -32,6 +35,12 @@ type schedulerOptions struct {
 
 	renewInterval int64
 	subClusterKey string
+
+	pluginMetricsSamplePercent int32
+
+	elasticQuotaInformer cache.SharedIndexInformer
+	nodeMetadataInformer informers.GenericInformer
+	podMetadataInformer  informers.GenericInformer
 }
 
 // Option configures a Scheduler
@@ -66,9 +75,41 @@ func WithSubClusterKey(key string) Option {
 	}
 }
 
+// WithPluginMetricsSamplePercent sets the percentage of scheduling cycles recording plugin metrics, the default value is 100
+func WithPluginMetricsSamplePercent(percent int32) Option {
+	return func(o *schedulerOptions) {
+		o.pluginMetricsSamplePercent = percent
+	}
+}
+
+// WithElasticQuotaInformer sets the informer of ElasticQuotas, quotas are not watched if it's not set
+func WithElasticQuotaInformer(informer cache.SharedIndexInformer) Option {
+	return func(o *schedulerOptions) {
+		o.elasticQuotaInformer = informer
+	}
+}
+
+// WithNodeMetadataInformer sets the metadata informer of all nodes, which is required when the informers
+// of scheduler are scoped to its partition
+func WithNodeMetadataInformer(informer informers.GenericInformer) Option {
+	return func(o *schedulerOptions) {
+		o.nodeMetadataInformer = informer
+	}
+}
+
+// WithPodMetadataInformer sets the metadata informer of pods out of the partition of scheduler, which is required
+// when the informers of scheduler are scoped to its partition
+func WithPodMetadataInformer(informer informers.GenericInformer) Option {
+	return func(o *schedulerOptions) {
+		o.podMetadataInformer = informer
+	}
+}
+
 var defaultSchedulerOptions = schedulerOptions{
 	renewInterval: config.DefaultRenewIntervalInSeconds,
 	subClusterKey: config.DefaultSubClusterKey,
+
+	pluginMetricsSamplePercent: config.DefaultPluginMetricsSamplePercent,
 }
 
 func renderOptions(opts ...Option) schedulerOptions {
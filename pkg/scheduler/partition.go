@@ -0,0 +1,265 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// Handlers used when the informers are scoped to the partition of the scheduler. Nodes and pods of the partition
+// come from the label-selected informers, nodes and pods of other partitions come from the metadata informers
+// and are stored in cache without spec and status, so that the topology of the cluster is still complete.
+
+// nodeMetadataInThisPartition returns true if the node is selected by the partition label of the scheduler.
+func (sched *Scheduler) nodeMetadataInThisPartition(meta metav1.Object) bool {
+	return meta.GetLabels()[nodeutil.GodelSchedulerNodeLabelKey] == sched.Name
+}
+
+// nodeFromMetadata returns a node without spec and status, which is enough to locate the topology of pods.
+func nodeFromMetadata(meta *metav1.PartialObjectMetadata) *v1.Node {
+	node := &v1.Node{ObjectMeta: meta.ObjectMeta}
+	node.ManagedFields = nil
+	return node
+}
+
+func (sched *Scheduler) addNodeMetadata(obj interface{}) {
+	meta, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "object", obj)
+		return
+	}
+	if sched.nodeMetadataInThisPartition(meta) {
+		// Nodes of this partition come from the node informer.
+		return
+	}
+	if err := sched.commonCache.AddNode(nodeFromMetadata(meta)); err != nil {
+		klog.InfoS("Failed to add node metadata to scheduler cache", "node", meta.Name, "err", err)
+	}
+}
+
+func (sched *Scheduler) updateNodeMetadata(oldObj, newObj interface{}) {
+	oldMeta, ok := oldObj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		klog.InfoS("Failed to convert oldObj to *metav1.PartialObjectMetadata", "oldObject", oldObj)
+		return
+	}
+	newMeta, ok := newObj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		klog.InfoS("Failed to convert newObj to *metav1.PartialObjectMetadata", "newObject", newObj)
+		return
+	}
+
+	oldInPartition, newInPartition := sched.nodeMetadataInThisPartition(oldMeta), sched.nodeMetadataInThisPartition(newMeta)
+	if newInPartition {
+		// The node moves into this partition, the node itself comes from the node informer, and the pods on it
+		// come from the pod informer once they are labeled by the dispatcher.
+		return
+	}
+	// Most of the updates are caused by the heartbeats of nodes, skip them.
+	if !oldInPartition && reflect.DeepEqual(oldMeta.Labels, newMeta.Labels) && reflect.DeepEqual(oldMeta.Annotations, newMeta.Annotations) {
+		return
+	}
+	if err := sched.commonCache.UpdateNode(nodeFromMetadata(oldMeta), nodeFromMetadata(newMeta)); err != nil {
+		klog.InfoS("Failed to update node metadata in scheduler cache", "node", newMeta.Name, "err", err)
+	}
+}
+
+func (sched *Scheduler) deleteNodeMetadata(obj interface{}) {
+	var meta *metav1.PartialObjectMetadata
+	switch t := obj.(type) {
+	case *metav1.PartialObjectMetadata:
+		meta = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		meta, ok = t.Obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "object", t.Obj)
+			return
+		}
+	default:
+		klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "type", t)
+		return
+	}
+	if sched.nodeMetadataInThisPartition(meta) {
+		return
+	}
+	if err := sched.commonCache.DeleteNode(nodeFromMetadata(meta)); err != nil {
+		klog.InfoS("Failed to remove node metadata from scheduler cache", "node", meta.Name, "err", err)
+	}
+}
+
+// deleteNodeOfPartition handles the delete events of the label-selected node informer. The node may still
+// exist and just move out of this partition, in which case it's replaced by its metadata.
+func (sched *Scheduler) deleteNodeOfPartition(obj interface{}) {
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+			node, ok = tombstone.Obj.(*v1.Node)
+		}
+	}
+	if ok {
+		if item, err := sched.options.nodeMetadataInformer.Lister().Get(node.Name); err == nil {
+			if meta, isMeta := item.(*metav1.PartialObjectMetadata); isMeta && !sched.nodeMetadataInThisPartition(meta) {
+				klog.V(3).InfoS("Detected node moving out of the partition", "node", node.Name)
+				if err := sched.commonCache.UpdateNode(node, nodeFromMetadata(meta)); err != nil {
+					klog.InfoS("Failed to update node metadata in scheduler cache", "node", node.Name, "err", err)
+				}
+				return
+			}
+		}
+	}
+	sched.deleteNodeFromCache(obj)
+}
+
+// podFromMetadata returns a pod with the metadata only, which is located by the node annotation written by the
+// dispatcher, or nil if the pod is not bound or assumed yet. It's enough for the cross-partition checks counting
+// pods by labels and topology.
+func podFromMetadata(meta *metav1.PartialObjectMetadata) *v1.Pod {
+	nodeName := meta.Annotations[podutil.PartitionNodeAnnotationKey]
+	if len(nodeName) == 0 {
+		nodeName = meta.Annotations[podutil.AssumedNodeAnnotationKey]
+	}
+	if len(nodeName) == 0 {
+		return nil
+	}
+	pod := &v1.Pod{
+		ObjectMeta: meta.ObjectMeta,
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	pod.ManagedFields = nil
+	return pod
+}
+
+// metadataOnlyPod returns true if the pod is built from metadata, pods from apiserver have at least one container.
+func metadataOnlyPod(pod *v1.Pod) bool {
+	return len(pod.Spec.Containers) == 0
+}
+
+// addPodOfPartition handles the add events of the label-selected pod informer. The pod may move into this
+// partition while its metadata is still in cache, which is replaced by the complete pod.
+func (sched *Scheduler) addPodOfPartition(obj interface{}) {
+	sched.partitionPodsLock.Lock()
+	defer sched.partitionPodsLock.Unlock()
+
+	if pod, ok := obj.(*v1.Pod); ok {
+		if cachedPod, err := sched.commonCache.GetPod(pod); err == nil && metadataOnlyPod(cachedPod) {
+			if err := sched.commonCache.DeletePod(cachedPod); err != nil {
+				klog.InfoS("Failed to remove pod metadata from scheduler cache", "pod", klog.KObj(pod), "err", err)
+			}
+		}
+	}
+	sched.addPod(obj)
+}
+
+// deletePodOfPartition handles the delete events of the label-selected pod informer. The pod may still exist
+// and just move out of this partition, in which case it's replaced by its metadata.
+func (sched *Scheduler) deletePodOfPartition(obj interface{}) {
+	sched.partitionPodsLock.Lock()
+	defer sched.partitionPodsLock.Unlock()
+
+	sched.deletePod(obj)
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	if item, exists, err := sched.options.podMetadataInformer.Informer().GetIndexer().GetByKey(key); err == nil && exists {
+		if meta, ok := item.(*metav1.PartialObjectMetadata); ok {
+			klog.V(4).InfoS("Detected pod moving out of the partition", "pod", key)
+			sched.setPodMetadataLocked(meta)
+		}
+	}
+}
+
+func (sched *Scheduler) addPodMetadata(obj interface{}) {
+	meta, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "object", obj)
+		return
+	}
+	sched.partitionPodsLock.Lock()
+	defer sched.partitionPodsLock.Unlock()
+	sched.setPodMetadataLocked(meta)
+}
+
+func (sched *Scheduler) updatePodMetadata(_, newObj interface{}) {
+	newMeta, ok := newObj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		klog.InfoS("Failed to convert newObj to *metav1.PartialObjectMetadata", "newObject", newObj)
+		return
+	}
+	sched.partitionPodsLock.Lock()
+	defer sched.partitionPodsLock.Unlock()
+	sched.setPodMetadataLocked(newMeta)
+}
+
+func (sched *Scheduler) deletePodMetadata(obj interface{}) {
+	var meta *metav1.PartialObjectMetadata
+	switch t := obj.(type) {
+	case *metav1.PartialObjectMetadata:
+		meta = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		meta, ok = t.Obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "object", t.Obj)
+			return
+		}
+	default:
+		klog.InfoS("Failed to convert to *metav1.PartialObjectMetadata", "type", t)
+		return
+	}
+	sched.partitionPodsLock.Lock()
+	defer sched.partitionPodsLock.Unlock()
+
+	pod := &v1.Pod{ObjectMeta: meta.ObjectMeta}
+	// The complete pod is kept if the pod moves into this partition.
+	if cachedPod, err := sched.commonCache.GetPod(pod); err == nil && metadataOnlyPod(cachedPod) {
+		if err := sched.commonCache.DeletePod(cachedPod); err != nil {
+			klog.InfoS("Failed to remove pod metadata from scheduler cache", "pod", klog.KObj(pod), "err", err)
+		}
+	}
+}
+
+// setPodMetadataLocked adds or updates the pod built from metadata in cache, unless the complete pod is there.
+func (sched *Scheduler) setPodMetadataLocked(meta *metav1.PartialObjectMetadata) {
+	pod := podFromMetadata(meta)
+	if pod == nil {
+		return
+	}
+	cachedPod, err := sched.commonCache.GetPod(pod)
+	switch {
+	case err != nil:
+		err = sched.commonCache.AddPod(pod)
+	case metadataOnlyPod(cachedPod):
+		err = sched.commonCache.UpdatePod(cachedPod, pod)
+	default:
+		// The pod moves out of this partition, it's replaced once removed from the label-selected pod informer.
+		return
+	}
+	if err != nil {
+		klog.InfoS("Failed to set pod metadata in scheduler cache", "pod", klog.KObj(pod), "err", err)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	commoncache "github.com/kubewharf/godel-scheduler/pkg/common/cache"
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	godelcache "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache"
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+type fakeNodeMetadataInformer struct {
+	lister cache.GenericLister
+}
+
+func (f *fakeNodeMetadataInformer) Informer() cache.SharedIndexInformer { return nil }
+
+func (f *fakeNodeMetadataInformer) Lister() cache.GenericLister { return f.lister }
+
+func newFakeNodeMetadataInformer(metas ...*metav1.PartialObjectMetadata) *fakeNodeMetadataInformer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, meta := range metas {
+		indexer.Add(meta)
+	}
+	return &fakeNodeMetadataInformer{lister: cache.NewGenericLister(indexer, schema.GroupResource{Resource: "nodes"})}
+}
+
+func nodeMetadata(name, schedulerName string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{nodeutil.GodelSchedulerNodeLabelKey: schedulerName},
+		},
+	}
+}
+
+func podMetadata(name string, annotations map[string]string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(name),
+			Labels:          map[string]string{"app": "a", podutil.PartitionLabelKey: "scheduler-1"},
+			Annotations:     annotations,
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+			ResourceVersion: "1",
+		},
+	}
+}
+
+func TestPodFromMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		expectedNode string
+	}{
+		{
+			name: "pending pod",
+		},
+		{
+			name:         "bound pod",
+			annotations:  map[string]string{podutil.PartitionNodeAnnotationKey: "node-1"},
+			expectedNode: "node-1",
+		},
+		{
+			name:         "assumed pod",
+			annotations:  map[string]string{podutil.AssumedNodeAnnotationKey: "node-2"},
+			expectedNode: "node-2",
+		},
+		{
+			name:         "bound pod assumed before",
+			annotations:  map[string]string{podutil.PartitionNodeAnnotationKey: "node-1", podutil.AssumedNodeAnnotationKey: "node-2"},
+			expectedNode: "node-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := podFromMetadata(podMetadata("pod", tt.annotations))
+			if len(tt.expectedNode) == 0 {
+				if pod != nil {
+					t.Errorf("expected no pod, got %v", pod)
+				}
+				return
+			}
+			if pod.Name != "pod" || pod.Spec.NodeName != tt.expectedNode || pod.Labels["app"] != "a" {
+				t.Errorf("expected identity, labels and node %v to be kept, got %v", tt.expectedNode, pod)
+			}
+			if pod.ManagedFields != nil {
+				t.Errorf("expected managed fields to be stripped, got %v", pod.ManagedFields)
+			}
+			if !metadataOnlyPod(pod) {
+				t.Errorf("expected pod built from metadata")
+			}
+		})
+	}
+}
+
+func TestPodMetadataHandlers(t *testing.T) {
+	newSched := func() *Scheduler {
+		return &Scheduler{
+			Name: "scheduler-0",
+			commonCache: godelcache.New(commoncache.MakeCacheHandlerWrapper().
+				ComponentName("").SchedulerType("").SubCluster(framework.DefaultSubCluster).
+				PodAssumedTTL(30 * time.Second).Period(10 * time.Second).StopCh(make(<-chan struct{})).
+				Obj()),
+		}
+	}
+	bound := map[string]string{podutil.PartitionNodeAnnotationKey: "node-1"}
+
+	t.Run("add, update and delete pod metadata", func(t *testing.T) {
+		sched := newSched()
+		sched.addPodMetadata(podMetadata("pod", bound))
+		cachedPod, err := sched.commonCache.GetPod(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "pod"}})
+		if err != nil || cachedPod.Spec.NodeName != "node-1" {
+			t.Fatalf("expected pod on node-1 in cache, got %v, %v", cachedPod, err)
+		}
+
+		moved := podMetadata("pod", map[string]string{podutil.PartitionNodeAnnotationKey: "node-2"})
+		moved.ResourceVersion = "2"
+		sched.updatePodMetadata(podMetadata("pod", bound), moved)
+		if cachedPod, err = sched.commonCache.GetPod(cachedPod); err != nil || cachedPod.Spec.NodeName != "node-2" {
+			t.Fatalf("expected pod on node-2 in cache, got %v, %v", cachedPod, err)
+		}
+
+		sched.deletePodMetadata(cache.DeletedFinalStateUnknown{Key: "default/pod", Obj: moved})
+		if _, err = sched.commonCache.GetPod(cachedPod); err == nil {
+			t.Errorf("expected pod to be removed from cache")
+		}
+	})
+
+	t.Run("complete pod is kept", func(t *testing.T) {
+		sched := newSched()
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "pod", ResourceVersion: "1"},
+			Spec:       v1.PodSpec{NodeName: "node-0", Containers: []v1.Container{{Name: "c"}}},
+		}
+		if err := sched.commonCache.AddPod(pod); err != nil {
+			t.Fatal(err)
+		}
+		sched.addPodMetadata(podMetadata("pod", bound))
+		sched.deletePodMetadata(podMetadata("pod", bound))
+		if cachedPod, err := sched.commonCache.GetPod(pod); err != nil || metadataOnlyPod(cachedPod) || cachedPod.Spec.NodeName != "node-0" {
+			t.Errorf("expected complete pod to be kept in cache, got %v, %v", cachedPod, err)
+		}
+	})
+}
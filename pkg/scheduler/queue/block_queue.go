@@ -79,6 +79,9 @@ type BlockQueue struct {
 	// cycle will be put back to activeQueue if we were trying to schedule them
 	// when we received move request.
 	moveRequestCycle int64
+
+	// sortObserver is informed when units leave the queue, it may be nil.
+	sortObserver framework.UnitQueueSortObserver
 }
 
 // Making sure that BlockQueue implements SchedulingQueue.
@@ -119,10 +122,11 @@ func NewBlockQueue(
 		metricsRecorder: newMetricsRecorder(),
 
 		waitingPodsList: newWaitingPodsList(qos, options.subCluster, options.owner, options.clock),
-		readyQ:          heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")),
+		readyQ:          newObservedQueue(heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")), options.sortObserver),
 		waitingQ:        heap.NewWithRecorder("waiting", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("waiting")),
 
 		moveRequestCycle: -1,
+		sortObserver:     options.sortObserver,
 	}
 	pq.cond.L = &pq.lock
 	pq.latestOperationTimestamp = pq.clock.Now()
@@ -136,6 +140,18 @@ func (p *BlockQueue) Run() {
 	// TODO: discuss remove flushScheduledUnitsInWaitingQ & flushWaitingPodsList
 	go wait.Until(p.flushScheduledUnitsInWaitingQ, 2.0*time.Second, p.stop)
 	go wait.Until(p.flushWaitingPodsList, 20.0*time.Second, p.stop)
+	if p.sortObserver != nil && p.sortObserver.ReorderInterval() > 0 {
+		go wait.Until(p.reorderReadyQ, p.sortObserver.ReorderInterval(), p.stop)
+	}
+}
+
+// reorderReadyQ re-sorts readyQ since the order of units changes over time.
+func (p *BlockQueue) reorderReadyQ() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if q, ok := p.readyQ.(reorderableQueue); ok {
+		q.Reorder()
+	}
 }
 
 // triggerBroadcastIfNeeded
@@ -440,6 +456,9 @@ func (p *BlockQueue) Delete(pod *v1.Pod) error {
 	// The unit has been removed from SubQueue in deletePodFromUnitInfo.
 	if unitInfo.NumPods() == 0 {
 		klog.V(4).InfoS("SchedulingQueue Delete, delete from oldQueue and won't add back", "subCluster", p.subCluster, "qos", p.qos, "unitKey", unitInfo.UnitKey, "oldQueue", queue)
+		if p.sortObserver != nil {
+			p.sortObserver.UnitDeleted(unitInfo)
+		}
 	} else {
 		if queue != p.waitingQ && !p.readyToBeScheduled(unitInfo) {
 			klog.V(4).InfoS("SchedulingQueue Delete, move the unit", "subCluster", p.subCluster, "qos", p.qos, "unitKey", unitInfo.UnitKey, "oldQueue", queue, "newQueue", p.waitingQ)
@@ -511,6 +530,9 @@ func (p *BlockQueue) Pop() (*framework.QueuedUnitInfo, error) {
 	unitInfo := obj.(*framework.QueuedUnitInfo)
 	unitInfo.Attempts++
 	p.schedulingCycle++
+	if p.sortObserver != nil {
+		p.sortObserver.UnitPopped(unitInfo)
+	}
 	// TODO: improve metrics
 	p.metricsRecorder.recordPending(unitInfo, time.Since(unitInfo.Timestamp))
 	return unitInfo, nil
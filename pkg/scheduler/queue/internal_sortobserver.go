@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
+)
+
+// observedQueue is a SubQueue which informs the UnitQueueSortObserver of the units added to it.
+type observedQueue struct {
+	SubQueue
+	observer framework.UnitQueueSortObserver
+}
+
+// newObservedQueue wraps the queue with the observer, the queue is returned as is if observer is nil.
+func newObservedQueue(queue SubQueue, observer framework.UnitQueueSortObserver) SubQueue {
+	if observer == nil {
+		return queue
+	}
+	return &observedQueue{SubQueue: queue, observer: observer}
+}
+
+func (q *observedQueue) Add(obj interface{}) error {
+	q.observer.UnitAdded(obj.(*framework.QueuedUnitInfo))
+	return q.SubQueue.Add(obj)
+}
+
+func (q *observedQueue) Update(oldObj, newObj interface{}) error {
+	q.observer.UnitUpdated(oldObj.(*framework.QueuedUnitInfo), newObj.(*framework.QueuedUnitInfo))
+	return q.SubQueue.Update(oldObj, newObj)
+}
+
+func (q *observedQueue) Reorder() {
+	if queue, ok := q.SubQueue.(reorderableQueue); ok {
+		queue.Reorder()
+	}
+}
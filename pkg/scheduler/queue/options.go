@@ -32,6 +32,7 @@ type schedulingQueueOptions struct {
 	unitMaxBackoffDuration        time.Duration
 	owner                         string
 	attemptImpactFactorOnPriority float64
+	sortObserver                  framework.UnitQueueSortObserver
 }
 
 // Option configures a PriorityQueue
@@ -86,6 +87,14 @@ func WithAttemptImpactFactorOnPriority(attemptImpactFactorOnPriority float64) Op
 	}
 }
 
+// WithUnitQueueSortObserver sets the observer to be informed of the activity of PriorityQueue.
+// It is usually the unit queue sort plugin, if the plugin implements framework.UnitQueueSortObserver.
+func WithUnitQueueSortObserver(observer framework.UnitQueueSortObserver) Option {
+	return func(o *schedulingQueueOptions) {
+		o.sortObserver = observer
+	}
+}
+
 var defaultPriorityQueueOptions = schedulingQueueOptions{
 	clock:                         util.RealClock{},
 	unitInitialBackoffDuration:    config.DefaultUnitInitialBackoffInSeconds * time.Second,
@@ -107,6 +107,9 @@ type PriorityQueue struct {
 	attemptImpactFactorOnPriority float64
 
 	priorityHeap SubQueue
+
+	// sortObserver is informed when units leave the queue, it may be nil.
+	sortObserver framework.UnitQueueSortObserver
 }
 
 // Making sure that PriorityQueue implements SchedulingQueue.
@@ -147,7 +150,7 @@ func NewPriorityQueue(
 		metricsRecorder: newMetricsRecorder(),
 
 		waitingPodsList: newWaitingPodsList(qos, options.subCluster, options.owner, options.clock),
-		readyQ:          heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")),
+		readyQ:          newObservedQueue(heap.NewWithRecorder("ready", unitInfoKeyFunc, comp, metrics.NewPendingUnitsRecorder("ready")), options.sortObserver),
 		backoffQ:        heap.NewWithRecorder("backoff", unitInfoKeyFunc, boHandler.unitsCompareBackoffCompleted, metrics.NewPendingUnitsRecorder("backoff")),
 		waitingQ:        heap.NewWithRecorder("waiting", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("waiting")),
 		unschedulableQ:  heap.NewWithRecorder("unschedulable", unitInfoKeyFunc, alwaysFalse, metrics.NewPendingUnitsRecorder("unschedulable")),
@@ -159,6 +162,7 @@ func NewPriorityQueue(
 			u2 := unitInfo2.(*framework.QueuedUnitInfo)
 			return u1.GetPriority() < u2.GetPriority()
 		}),
+		sortObserver: options.sortObserver,
 	}
 	pq.cond.L = &pq.lock
 	pq.latestOperationTimestamp = pq.clock.Now()
@@ -173,6 +177,18 @@ func (p *PriorityQueue) Run() {
 	// TODO: discuss remove flushScheduledUnitsInWaitingQ & flushWaitingPodsList
 	go wait.Until(p.flushScheduledUnitsInWaitingQ, 2.0*time.Second, p.stop)
 	go wait.Until(p.flushWaitingPodsList, 20.0*time.Second, p.stop)
+	if p.sortObserver != nil && p.sortObserver.ReorderInterval() > 0 {
+		go wait.Until(p.reorderReadyQ, p.sortObserver.ReorderInterval(), p.stop)
+	}
+}
+
+// reorderReadyQ re-sorts readyQ since the order of units changes over time.
+func (p *PriorityQueue) reorderReadyQ() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if q, ok := p.readyQ.(reorderableQueue); ok {
+		q.Reorder()
+	}
 }
 
 // flushBackoffQCompleted Moves all units from backoffQ which have completed backoff in to readyQ
@@ -555,6 +571,9 @@ func (p *PriorityQueue) Delete(pod *v1.Pod) error {
 		if err != nil {
 			klog.V(4).InfoS("Error occurred when deleting unit in SchedulingQueue.Delete", "subCluster", p.subCluster, "qos", p.qos, "queue", p.priorityHeap, "err", err)
 		}
+		if p.sortObserver != nil {
+			p.sortObserver.UnitDeleted(unitInfo)
+		}
 	} else {
 		if queue != p.waitingQ && !p.readyToBeScheduled(unitInfo) {
 			klog.V(4).InfoS("SchedulingQueue Delete, move the unit", "subCluster", p.subCluster, "qos", p.qos, "unitKey", unitInfo.UnitKey, "oldQueue", queue, "newQueue", p.waitingQ)
@@ -706,6 +725,9 @@ func (p *PriorityQueue) Pop() (*framework.QueuedUnitInfo, error) {
 	unitInfo.Attempts++
 	p.setQueuedPriorityScore(unitInfo)
 	p.schedulingCycle++
+	if p.sortObserver != nil {
+		p.sortObserver.UnitPopped(unitInfo)
+	}
 	// TODO: improve metrics
 	p.metricsRecorder.recordPending(unitInfo, time.Since(unitInfo.Timestamp))
 	p.priorityHeap.Delete(unitInfo)
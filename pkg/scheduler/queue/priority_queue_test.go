@@ -261,6 +261,37 @@ func TestPriorityQueue_Pop(t *testing.T) {
 	wg.Wait()
 }
 
+func TestPriorityQueue_PopWithFairShareSort(t *testing.T) {
+	plugin, err := unitqueuesort.NewFairShare(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewPriorityQueue(nil, nil, nil, plugin.Less, WithUnitQueueSortObserver(plugin.(framework.UnitQueueSortObserver)))
+	newPod := func(namespace, name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(namespace + name)},
+			Spec:       v1.PodSpec{Priority: &midPriority},
+		}
+	}
+	for _, pod := range []*v1.Pod{
+		newPod("ns1", "p1"), newPod("ns1", "p2"), newPod("ns1", "p3"), newPod("ns2", "p1"), newPod("ns2", "p2"),
+	} {
+		if err := q.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []string{"ns1/p1", "ns2/p1", "ns1/p2", "ns2/p2", "ns1/p3"}
+	for _, e := range expected {
+		u, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := getOnePodInfo(u).Pod.Namespace + "/" + getOnePodInfo(u).Pod.Name; got != e {
+			t.Errorf("Expected: %v after Pop, but got: %v", e, got)
+		}
+	}
+}
+
 func TestPriorityQueue_Update(t *testing.T) {
 	// TODO: remove UnitDequeueStatus
 	q := NewPriorityQueue(nil, nil, nil, newDefaultUnitQueueSort())
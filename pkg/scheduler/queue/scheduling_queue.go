@@ -94,6 +94,11 @@ type SubQueue interface {
 	List() []interface{}
 }
 
+// reorderableQueue is implemented by the SubQueues whose ordering can be re-established.
+type reorderableQueue interface {
+	Reorder()
+}
+
 // NewSchedulingQueue initializes a priority queue as a new scheduling queue.
 func NewSchedulingQueue(
 	cache cache.SchedulerCache,
@@ -121,6 +121,9 @@ func InitUnitQueueSortPlugin(spec *framework.PluginSpec, pluginArgs map[string]*
 type SortPluginFactory = func(runtime.Object) (framework.UnitQueueSortPlugin, error)
 
 var UnitSortPluginRegistry = map[string]SortPluginFactory{
-	unitqueuesort.FCFSName: unitqueuesort.NewFCFS,
-	unitqueuesort.Name:     unitqueuesort.New,
+	unitqueuesort.FCFSName:          unitqueuesort.NewFCFS,
+	unitqueuesort.Name:              unitqueuesort.New,
+	unitqueuesort.AgingPriorityName: unitqueuesort.NewAgingPriority,
+	unitqueuesort.FairShareName:     unitqueuesort.NewFairShare,
+	unitqueuesort.GangBackfillName:  unitqueuesort.NewGangBackfill,
 }
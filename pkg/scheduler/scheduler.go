@@ -19,6 +19,7 @@ package scheduler
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"time"
 
 	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
@@ -45,6 +46,7 @@ import (
 	preemptionstore "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/commonstores/preemption_store"
 	cachedebugger "github.com/kubewharf/godel-scheduler/pkg/scheduler/cache/debugger"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/controller"
+	"github.com/kubewharf/godel-scheduler/pkg/scheduler/core"
 	podscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler/core/pod_scheduler"
 	unitscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler/core/unit_scheduler"
 	"github.com/kubewharf/godel-scheduler/pkg/scheduler/metrics"
@@ -67,6 +69,8 @@ type Scheduler struct {
 	StopEverything         <-chan struct{}
 	scheduledPodsHasSynced func() bool
 	clock                  clock.Clock
+	// listersHasSynced are waited instead of scheduledPodsHasSynced when the cache is restored from checkpoint.
+	listersHasSynced []cache.InformerSynced
 
 	// client syncs K8S object
 	client clientset.Interface
@@ -91,6 +95,17 @@ type Scheduler struct {
 	metricsRecorder     *godelcache.ClusterCollectable
 
 	movementController controller.CommonController
+
+	// dryRunSlots bounds the number of dry runs served at the same time.
+	dryRunSlots chan struct{}
+	// dryRunSchedulers are the unit schedulers dedicated to dry runs, keyed by sub cluster and switch type.
+	// Their snapshots are updated incrementally from the cache and reused by the following dry runs.
+	// It's only accessed by the dry run holding a slot.
+	dryRunSchedulers map[string]core.UnitScheduler
+
+	// partitionPodsLock serializes the handlers of the pod informer and the pod metadata informer when the
+	// informers are scoped to the partition, since pods move between them.
+	partitionPodsLock sync.Mutex
 }
 
 // New returns a Scheduler
@@ -158,6 +173,9 @@ func New(
 		schedulerMaintainer: NewSchedulerStatusMaintainer(globalClock, crdClient, godelSchedulerName, options.renewInterval),
 		recorder:            recorder,
 		metricsRecorder:     godelcache.NewEmptyClusterCollectable(godelSchedulerName),
+
+		dryRunSlots:      make(chan struct{}, maxConcurrentDryRuns),
+		dryRunSchedulers: make(map[string]core.UnitScheduler),
 	}
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.SupportRescheduling) {
@@ -194,6 +212,7 @@ func New(
 
 	// 4. Add event handlers
 	addAllEventHandlers(sched, informerFactory, crdInformerFactory, katalystCrdInformerFactory)
+	sched.listersHasSynced = listersHasSynced(informerFactory, crdInformerFactory)
 
 	return sched, nil
 }
@@ -202,9 +221,16 @@ func New(
 func (sched *Scheduler) Run(ctx context.Context) {
 	// run scheduler maintainer to maintain scheduler status in CRD
 	go sched.schedulerMaintainer.Run(sched.StopEverything)
-	if !cache.WaitForCacheSync(ctx.Done(), sched.scheduledPodsHasSynced) {
+	// The cache restored from checkpoint already holds the scheduled pods, but the objects read through listers
+	// are not restored, so wait for them instead.
+	hasSynced := []cache.InformerSynced{sched.scheduledPodsHasSynced}
+	if sched.commonCache.Reconciling() {
+		hasSynced = sched.listersHasSynced
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), hasSynced...) {
 		return
 	}
+	defer sched.closeDryRunSchedulers()
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerCacheScrape) {
 		// The metrics agent scrape endpoint every 5s and flush them to the metrics server every 30s. To
@@ -225,30 +251,25 @@ func (sched *Scheduler) Run(ctx context.Context) {
 	sched.ScheduleSwitch.Run(ctx)
 }
 
-func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType framework.SwitchType) ScheduleDataSet {
-	var subClusterConfig *subClusterConfig
+// getSubClusterConfig returns the config of the sub cluster, which falls back to the default config.
+func (sched *Scheduler) getSubClusterConfig(subCluster string) *subClusterConfig {
 	if profile, ok := sched.options.subClusterProfiles[subCluster]; ok {
-		subClusterConfig = newSubClusterConfigFromDefaultConfig(&profile, sched.defaultSubClusterConfig)
-	} else {
-		subClusterConfig = sched.defaultSubClusterConfig
+		return newSubClusterConfigFromDefaultConfig(&profile, sched.defaultSubClusterConfig)
 	}
-	klog.InfoS("CreateSubClusterWorkflow DataSet", "subCluster", subCluster, "clusterIndex", idx, "subClusterConfig", subClusterConfig)
+	return sched.defaultSubClusterConfig
+}
 
+func pluginConfigsToArgs(pluginConfigs []config.PluginConfig) map[string]*config.PluginConfig {
 	pluginArgs := make(map[string]*config.PluginConfig)
-	for index := range subClusterConfig.PluginConfigs {
-		pluginArg := subClusterConfig.PluginConfigs[index]
+	for index := range pluginConfigs {
+		pluginArg := pluginConfigs[index]
 		pluginArgs[pluginArg.Name] = &pluginArg
 	}
-	unitQueueSortPlugin, err := godelqueue.InitUnitQueueSortPlugin(subClusterConfig.UnitQueueSortPlugin, pluginArgs)
-	if err != nil {
-		panic(err)
-	}
-	preemptionPluginArgs := make(map[string]*config.PluginConfig)
-	for index := range subClusterConfig.PreemptionPluginConfigs {
-		pluginArgs := subClusterConfig.PreemptionPluginConfigs[index]
-		preemptionPluginArgs[pluginArgs.Name] = &pluginArgs
-	}
+	return pluginArgs
+}
 
+// newSnapshotAndPodScheduler creates an empty snapshot of the sub cluster and the pod scheduler working on it.
+func (sched *Scheduler) newSnapshotAndPodScheduler(subCluster string, switchType framework.SwitchType, subClusterConfig *subClusterConfig) (*godelcache.Snapshot, core.PodScheduler) {
 	handler := commoncache.MakeCacheHandlerWrapper().
 		SubCluster(subCluster).SwitchType(switchType).
 		EnableStore(schedulerutil.FilterTrueKeys(subClusterConfig.EnableStore)...).
@@ -272,21 +293,39 @@ func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType fra
 		subClusterConfig.PercentageOfNodesToScore,
 		subClusterConfig.IncreasedPercentageOfNodesToScore,
 		subClusterConfig.BasePlugins,
-		pluginArgs,
-		preemptionPluginArgs,
+		pluginConfigsToArgs(subClusterConfig.PluginConfigs),
+		pluginConfigsToArgs(subClusterConfig.PreemptionPluginConfigs),
 	)
+	return snapshot, podScheduler
+}
+
+func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType framework.SwitchType) ScheduleDataSet {
+	subClusterConfig := sched.getSubClusterConfig(subCluster)
+	klog.InfoS("CreateSubClusterWorkflow DataSet", "subCluster", subCluster, "clusterIndex", idx, "subClusterConfig", subClusterConfig)
+
+	unitQueueSortPlugin, err := godelqueue.InitUnitQueueSortPlugin(subClusterConfig.UnitQueueSortPlugin, pluginConfigsToArgs(subClusterConfig.PluginConfigs))
+	if err != nil {
+		panic(err)
+	}
+	snapshot, podScheduler := sched.newSnapshotAndPodScheduler(subCluster, switchType, subClusterConfig)
+	queueOpts := []godelqueue.Option{
+		godelqueue.WithUnitInitialBackoffDuration(time.Duration(subClusterConfig.UnitInitialBackoffSeconds) * time.Second),
+		godelqueue.WithPodMaxBackoffDuration(time.Duration(subClusterConfig.UnitMaxBackoffSeconds) * time.Second),
+		godelqueue.WithOwner(sched.Name),
+		godelqueue.WithSwitchType(switchType),
+		godelqueue.WithSubCluster(subCluster),
+		godelqueue.WithClock(sched.clock),
+	}
+	if observer, ok := unitQueueSortPlugin.(framework.UnitQueueSortObserver); ok {
+		queueOpts = append(queueOpts, godelqueue.WithUnitQueueSortObserver(observer))
+	}
 	schedulingQueue := godelqueue.NewSchedulingQueue(
 		sched.commonCache,
 		sched.informerFactory.Scheduling().V1().PriorityClasses().Lister(),
 		sched.crdInformerFactory.Scheduling().V1alpha1().PodGroups().Lister(),
 		unitQueueSortPlugin.Less,
 		subClusterConfig.UseBlockQueue,
-		godelqueue.WithUnitInitialBackoffDuration(time.Duration(subClusterConfig.UnitInitialBackoffSeconds)*time.Second),
-		godelqueue.WithPodMaxBackoffDuration(time.Duration(subClusterConfig.UnitMaxBackoffSeconds)*time.Second),
-		godelqueue.WithOwner(sched.Name),
-		godelqueue.WithSwitchType(switchType),
-		godelqueue.WithSubCluster(subCluster),
-		godelqueue.WithClock(sched.clock),
+		queueOpts...,
 	)
 	reconciler := reconciler.NewFailedTaskReconciler(sched.client, sched.informerFactory.Core().V1().Pods().Lister(), sched.commonCache, *sched.SchedulerName)
 	unitScheduler := unitscheduler.NewUnitScheduler(
@@ -307,6 +346,7 @@ func (sched *Scheduler) createDataSet(idx int, subCluster string, switchType fra
 		sched.clock,
 		sched.recorder,
 		time.Duration(subClusterConfig.MaxWaitingDeletionDuration)*time.Second,
+		sched.options.pluginMetricsSamplePercent,
 	)
 	debugger := cachedebugger.New(
 		sched.informerFactory.Core().V1().Nodes().Lister(),
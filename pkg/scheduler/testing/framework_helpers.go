@@ -136,7 +136,7 @@ func NewSchedulerPodFramework(pluginRegistry framework.PluginMap, orderedPluginR
 }
 
 func (mfh *MockPodFrameworkHandle) GetPreemptionFrameworkForPod(_ *v1.Pod) framework.SchedulerPreemptionFramework {
-	return schedulerruntime.NewPreemptionFramework(mfh.preemptionPluginRegistry, mfh.basePlugins)
+	return schedulerruntime.NewPreemptionFramework(mfh.preemptionPluginRegistry, mfh.basePlugins, nil)
 }
 
 func (mfh *MockPodFrameworkHandle) GetPreemptionPolicy(deployName string) string {
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// maxRequestBytes is the limit of AdmissionReview bodies, it's the same as the one of kube-apiserver.
+const maxRequestBytes = 3 * 1024 * 1024
+
+// HandleFunc admits a single AdmissionRequest, the returned response must not be nil.
+type HandleFunc func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// NewHandler wraps the HandleFunc as a http.Handler serving admission.k8s.io/v1 AdmissionReview.
+func NewHandler(name string, fn HandleFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+			http.Error(w, fmt.Sprintf("malformed AdmissionReview: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		resp := fn(review.Request)
+		resp.UID = review.Request.UID
+		if !resp.Allowed {
+			klog.V(4).InfoS("Rejected admission request", "webhook", name, "kind", review.Request.Kind.Kind,
+				"namespace", review.Request.Namespace, "name", review.Request.Name, "reason", resp.Result.Message)
+		}
+
+		out, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: review.TypeMeta,
+			Response: resp,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(out)
+	})
+}
+
+// Allowed returns a response admitting the request without any change.
+func Allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// Denied returns a response rejecting the request with the reason.
+func Denied(reason string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: reason,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
+
+// Errored returns a response rejecting the request because of an internal error.
+func Errored(code int32, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Code:    code,
+		},
+	}
+}
+
+// PatchOperation is a single JSON patch (RFC 6902) operation.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patched returns a response admitting the request with the JSON patches, it's the same as Allowed if there is no patch.
+func Patched(patches []PatchOperation) *admissionv1.AdmissionResponse {
+	if len(patches) == 0 {
+		return Allowed()
+	}
+	raw, err := json.Marshal(patches)
+	if err != nil {
+		return Errored(http.StatusInternalServerError, err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     raw,
+		PatchType: &patchType,
+	}
+}
+
+// AddAnnotationPatches returns the patches setting the annotations on an object whose current annotations are `existing`.
+func AddAnnotationPatches(existing, annotations map[string]string) []PatchOperation {
+	return addMapPatches("/metadata/annotations", existing, annotations)
+}
+
+// AddLabelPatches returns the patches setting the labels on an object whose current labels are `existing`.
+func AddLabelPatches(existing, labels map[string]string) []PatchOperation {
+	return addMapPatches("/metadata/labels", existing, labels)
+}
+
+func addMapPatches(path string, existing, values map[string]string) []PatchOperation {
+	if len(values) == 0 {
+		return nil
+	}
+	if existing == nil {
+		return []PatchOperation{{Op: "add", Path: path, Value: values}}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	patches := make([]PatchOperation, 0, len(keys))
+	for _, key := range keys {
+		op := "add"
+		if _, ok := existing[key]; ok {
+			op = "replace"
+		}
+		patches = append(patches, PatchOperation{Op: op, Path: path + "/" + escapeJSONPointer(key), Value: values[key]})
+	}
+	return patches
+}
+
+// escapeJSONPointer escapes the reference token according to RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
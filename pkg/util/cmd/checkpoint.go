@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/features"
+)
+
+const (
+	DefaultCacheCheckpointInterval = time.Minute
+	DefaultCacheCheckpointMaxAge   = 10 * time.Minute
+)
+
+// CacheCheckpointOptions specifies where and how often the cache is checkpointed.
+type CacheCheckpointOptions struct {
+	// Path is the file the checkpoint is written to and restored from.
+	Path string
+	// Interval is the period of taking checkpoints.
+	Interval time.Duration
+	// MaxAge is the max age of a checkpoint that could be restored.
+	MaxAge time.Duration
+}
+
+func NewCacheCheckpointOptions() *CacheCheckpointOptions {
+	return &CacheCheckpointOptions{
+		Interval: DefaultCacheCheckpointInterval,
+		MaxAge:   DefaultCacheCheckpointMaxAge,
+	}
+}
+
+func (o *CacheCheckpointOptions) AddFlags(fs *pflag.FlagSet) {
+	if o == nil {
+		return
+	}
+
+	fs.StringVar(&o.Path, "cache-checkpoint-path", o.Path, "the file to checkpoint the cache to, only works when feature gate CacheCheckpoint is enabled.")
+	fs.DurationVar(&o.Interval, "cache-checkpoint-interval", o.Interval, "interval period to checkpoint the cache.")
+	fs.DurationVar(&o.MaxAge, "cache-checkpoint-max-age", o.MaxAge, "checkpoints older than this will not be restored.")
+}
+
+// Enabled returns true if the feature gate is enabled and the checkpoint path is specified.
+func (o *CacheCheckpointOptions) Enabled() bool {
+	return o != nil && len(o.Path) > 0 && o.Interval > 0 && utilfeature.DefaultFeatureGate.Enabled(features.CacheCheckpoint)
+}
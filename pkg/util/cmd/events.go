@@ -26,6 +26,8 @@ import (
 	v1 "k8s.io/client-go/kubernetes/typed/events/v1"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/tools/record"
+
+	eventutil "github.com/kubewharf/godel-scheduler/pkg/util/event"
 )
 
 // EventRecorder knows how to record events on behalf of an EventSource.
@@ -51,7 +53,7 @@ type EventBroadcasterAdapter interface {
 	// StartRecordingToSink starts sending events received from the specified eventBroadcaster.
 	StartRecordingToSink(stopCh <-chan struct{})
 
-	// NewRecorder creates a new Event Recorder with specified name.
+	// NewRecorder creates a new Event Recorder with specified name, which limits the rate of the events of each reason.
 	NewRecorder(name string) EventRecorder
 
 	// DeprecatedNewLegacyRecorder creates a legacy Event Recorder with specific name.
@@ -98,10 +100,13 @@ func (e *eventBroadcasterAdapterImpl) StartRecordingToSink(stopCh <-chan struct{
 }
 
 func (e *eventBroadcasterAdapterImpl) NewRecorder(name string) EventRecorder {
+	var recorder events.EventRecorder
 	if e.broadcaster != nil && e.eventClient != nil {
-		return e.broadcaster.NewRecorder(scheme.Scheme, name)
+		recorder = e.broadcaster.NewRecorder(scheme.Scheme, name)
+	} else {
+		recorder = record.NewEventRecorderAdapter(e.DeprecatedNewLegacyRecorder(name))
 	}
-	return record.NewEventRecorderAdapter(e.DeprecatedNewLegacyRecorder(name))
+	return eventutil.NewRateLimitedRecorder(recorder, eventutil.DefaultQPS, eventutil.DefaultBurst)
 }
 
 func (e *eventBroadcasterAdapterImpl) DeprecatedNewLegacyRecorder(name string) record.EventRecorder {
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	nodev1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/node/v1alpha1"
+	godelclient "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	nmnodeinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions/node/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	katalystclient "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
+	cnrinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+
+	nodeutil "github.com/kubewharf/godel-scheduler/pkg/util/node"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+)
+
+// NewPartitionScopedInformerFactory is the same as NewInformerFactory, except that only the nodes and pods
+// in the partition of the scheduler are watched. PersistentVolumes, PersistentVolumeClaims and StorageClasses
+// are not watched until a pod of the partition uses a claim.
+func NewPartitionScopedInformerFactory(cs clientset.Interface, schedulerName string, resyncPeriod time.Duration) informers.SharedInformerFactory {
+	informerFactory := informers.NewSharedInformerFactory(cs, resyncPeriod)
+	tweakListOptions := partitionTweakListOptions(schedulerName)
+	informerFactory.InformerFor(&v1.Node{}, func(k clientset.Interface, duration time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewFilteredNodeInformer(k, duration, cache.Indexers{}, tweakListOptions)
+	})
+	podSelector := podutil.PartitionLabelKey + "=" + schedulerName
+	informerFactory.InformerFor(&v1.Pod{}, func(k clientset.Interface, duration time.Duration) cache.SharedIndexInformer {
+		return newFilteredPodInformer(k, metav1.NamespaceAll, duration, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+			func(options *metav1.ListOptions) {
+				options.FieldSelector = nonTerminalPodSelector
+				options.LabelSelector = podSelector
+			})
+	})
+
+	volumeInformers := []*lazyInformer{
+		newLazyInformer(coreinformers.NewPersistentVolumeInformer(cs, resyncPeriod, cache.Indexers{})),
+		newLazyInformer(coreinformers.NewPersistentVolumeClaimInformer(cs, metav1.NamespaceAll, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		newLazyInformer(storageinformers.NewStorageClassInformer(cs, resyncPeriod, cache.Indexers{})),
+	}
+	for obj, informer := range map[runtime.Object]*lazyInformer{
+		&v1.PersistentVolume{}:      volumeInformers[0],
+		&v1.PersistentVolumeClaim{}: volumeInformers[1],
+		&storagev1.StorageClass{}:   volumeInformers[2],
+	} {
+		informer := informer
+		informerFactory.InformerFor(obj, func(clientset.Interface, time.Duration) cache.SharedIndexInformer {
+			return informer
+		})
+	}
+	informerFactory.Core().V1().Pods().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: podWithClaims,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				for _, informer := range volumeInformers {
+					informer.Activate()
+				}
+			},
+		},
+	})
+	return informerFactory
+}
+
+func podWithClaims(obj interface{}) bool {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return false
+	}
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].PersistentVolumeClaim != nil || pod.Spec.Volumes[i].Ephemeral != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lazyInformer doesn't run until it's activated, and is regarded as synced before that, so that the objects only
+// used by a few pods are not watched in the partitions without such pods. Once activated, it reports the synced
+// state of the underlying informer, and the VolumeBinding plugin holds the pods with claims until it has synced.
+// The plugin also activates it, since the pod may be scheduled before the handler above is notified.
+type lazyInformer struct {
+	cache.SharedIndexInformer
+	activated chan struct{}
+	once      sync.Once
+}
+
+func newLazyInformer(informer cache.SharedIndexInformer) *lazyInformer {
+	return &lazyInformer{SharedIndexInformer: informer, activated: make(chan struct{})}
+}
+
+func (i *lazyInformer) Activate() {
+	i.once.Do(func() {
+		close(i.activated)
+	})
+}
+
+func (i *lazyInformer) Run(stopCh <-chan struct{}) {
+	select {
+	case <-i.activated:
+		i.SharedIndexInformer.Run(stopCh)
+	case <-stopCh:
+	}
+}
+
+func (i *lazyInformer) HasSynced() bool {
+	select {
+	case <-i.activated:
+		return i.SharedIndexInformer.HasSynced()
+	default:
+		return true
+	}
+}
+
+// NewPartitionScopedCrdInformerFactory creates a SharedInformerFactory of godel CRDs, in which only
+// the NMNodes in the partition of the scheduler are watched.
+func NewPartitionScopedCrdInformerFactory(client godelclient.Interface, schedulerName string, resyncPeriod time.Duration) crdinformers.SharedInformerFactory {
+	informerFactory := crdinformers.NewSharedInformerFactory(client, resyncPeriod)
+	tweakListOptions := partitionTweakListOptions(schedulerName)
+	informerFactory.InformerFor(&nodev1alpha1.NMNode{}, func(c godelclient.Interface, duration time.Duration) cache.SharedIndexInformer {
+		return nmnodeinformers.NewFilteredNMNodeInformer(c, duration, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, tweakListOptions)
+	})
+	return informerFactory
+}
+
+// NewPartitionScopedKatalystCrdInformerFactory creates a SharedInformerFactory of katalyst CRDs, in which only
+// the CNRs in the partition of the scheduler are watched.
+func NewPartitionScopedKatalystCrdInformerFactory(client katalystclient.Interface, schedulerName string, resyncPeriod time.Duration) katalystinformers.SharedInformerFactory {
+	informerFactory := katalystinformers.NewSharedInformerFactory(client, resyncPeriod)
+	tweakListOptions := partitionTweakListOptions(schedulerName)
+	informerFactory.InformerFor(&katalystv1alpha1.CustomNodeResource{}, func(c katalystclient.Interface, duration time.Duration) cache.SharedIndexInformer {
+		return cnrinformers.NewFilteredCustomNodeResourceInformer(c, duration, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, tweakListOptions)
+	})
+	return informerFactory
+}
+
+// NewPodMetadataInformer creates an informer watching the metadata of the non-terminal pods out of the partition
+// of the scheduler, which are located by the node annotation written by the dispatcher.
+func NewPodMetadataInformer(client metadata.Interface, schedulerName string, resyncPeriod time.Duration) informers.GenericInformer {
+	selector := fmt.Sprintf("%s!=%s", podutil.PartitionLabelKey, schedulerName)
+	return metadatainformer.NewFilteredMetadataInformer(client, v1.SchemeGroupVersion.WithResource("pods"), metav1.NamespaceAll, resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(options *metav1.ListOptions) {
+			options.FieldSelector = nonTerminalPodSelector
+			options.LabelSelector = selector
+		})
+}
+
+// NewNodeMetadataInformer creates an informer watching the metadata of all nodes, which is much lighter
+// than the nodes themselves and is enough for the topology of nodes in other partitions.
+func NewNodeMetadataInformer(client metadata.Interface, resyncPeriod time.Duration) informers.GenericInformer {
+	return metadatainformer.NewFilteredMetadataInformer(client, v1.SchemeGroupVersion.WithResource("nodes"), metav1.NamespaceAll, resyncPeriod, cache.Indexers{}, nil)
+}
+
+func partitionTweakListOptions(schedulerName string) func(options *metav1.ListOptions) {
+	selector := nodeutil.PartitionLabelSelector(schedulerName)
+	return func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+	}
+}
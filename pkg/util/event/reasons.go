@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+// Reasons of the events emitted on Pods and PodGroups for the scheduling decisions made by the dispatcher,
+// the scheduler and the binder. They're documented in docs/features/events.md and are keyed off by alerting,
+// so they must not be changed.
+const (
+	// Dispatched is emitted on a pod when the dispatcher sends it to a scheduler, including the
+	// reassignments after the pod fails in another scheduler.
+	Dispatched = "Dispatched"
+	// ScheduleUnitSuccessfully is emitted on a PodGroup when the scheduler places its unit.
+	ScheduleUnitSuccessfully = "ScheduleUnitSuccessfully"
+	// PersistPodSuccessfully is emitted on a pod when the scheduler assumes it on a node.
+	PersistPodSuccessfully = "PersistPodSuccessfully"
+	// Preempted is emitted on a victim when the binder deletes it for a preemptor.
+	Preempted = "Preempted"
+	// FailedTasks is emitted on a pod and its PodGroup when the binder rejects the pod and sends it
+	// back for scheduling.
+	FailedTasks = "FailedTasks"
+	// Timeout is emitted on the pods and the PodGroup of a unit when the binder rejects it because
+	// it isn't scheduled within its timeout.
+	Timeout = "Timeout"
+	// Bind is emitted on a pod when the binder binds it to a node.
+	Bind = "Bind"
+)
+
+// Actions of the events emitted for the scheduling decisions.
+const (
+	DispatchingAction = "Dispatching"
+	PreemptingAction  = "Preempting"
+	RejectingAction   = "Rejecting"
+	BindingAction     = "Binding"
+)
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+)
+
+const (
+	// DefaultQPS is the rate of the events of each reason emitted by a component.
+	DefaultQPS = 50
+	// DefaultBurst is the burst of the events of each reason emitted by a component, which allows
+	// the events of a large gang to be emitted at once.
+	DefaultBurst = 500
+
+	// MaxPodEventsPerUnit is the max number of pods of a unit an event is emitted on. The event on the
+	// PodGroup counts all the pods.
+	MaxPodEventsPerUnit = 10
+)
+
+// unlimitedReasons are the reasons of the events which are never dropped, since they record the actions
+// on the pods themselves instead of the attempts to schedule them.
+var unlimitedReasons = map[string]bool{
+	Preempted: true,
+	Bind:      true,
+}
+
+// rateLimitedRecorder drops the events of a reason exceeding the rate limit of the reason, so that the
+// events of large gangs don't starve the other events or overload the API server. The dropped events
+// are counted by the event_dropped_total metric.
+type rateLimitedRecorder struct {
+	recorder events.EventRecorder
+	qps      float32
+	burst    int
+
+	mu       sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+var _ events.EventRecorder = &rateLimitedRecorder{}
+
+// NewRateLimitedRecorder returns the recorder limiting the rate of the events of each reason. It returns
+// nil if the recorder is nil.
+func NewRateLimitedRecorder(recorder events.EventRecorder, qps float32, burst int) events.EventRecorder {
+	if recorder == nil {
+		return nil
+	}
+	RegisterMetrics()
+	return &rateLimitedRecorder{
+		recorder: recorder,
+		qps:      qps,
+		burst:    burst,
+		limiters: map[string]flowcontrol.RateLimiter{},
+	}
+}
+
+func (r *rateLimitedRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	if !unlimitedReasons[reason] && !r.limiter(reason).TryAccept() {
+		droppedEvents.WithLabelValues(reason).Inc()
+		klog.V(4).InfoS("Dropped the event exceeding the rate limit", "reason", reason, "action", action)
+		return
+	}
+	r.recorder.Eventf(regarding, related, eventtype, reason, action, note, args...)
+}
+
+func (r *rateLimitedRecorder) limiter(reason string) flowcontrol.RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiter, ok := r.limiters[reason]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(r.qps, r.burst)
+		r.limiters[reason] = limiter
+	}
+	return limiter
+}
+
+// RecordUnitEvent emits the event on the pods of a unit and on its PodGroup. The event is aggregated on
+// the PodGroup, and only the first MaxPodEventsPerUnit pods get their own events, so a large gang doesn't
+// flood the API server. The PodGroup can be nil if the unit isn't a PodGroup or it has been deleted.
+func RecordUnitEvent(recorder events.EventRecorder, podGroup runtime.Object, pods []*v1.Pod, eventtype, reason, action, note string) {
+	if recorder == nil {
+		return
+	}
+	for i, pod := range pods {
+		if podGroup != nil && i >= MaxPodEventsPerUnit {
+			break
+		}
+		recorder.Eventf(pod, nil, eventtype, reason, action, "%s", helper.TruncateMessage(note))
+	}
+	if podGroup == nil {
+		return
+	}
+	if len(pods) > MaxPodEventsPerUnit {
+		note = fmt.Sprintf("[%d pods, events on the first %d pods] %s", len(pods), MaxPodEventsPerUnit, note)
+	} else if len(pods) > 0 {
+		note = fmt.Sprintf("[%d pods] %s", len(pods), note)
+	}
+	recorder.Eventf(podGroup, nil, eventtype, reason, action, "%s", helper.TruncateMessage(note))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"fmt"
+	"testing"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func drain(recorder *events.FakeRecorder) []string {
+	var got []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			got = append(got, e)
+		default:
+			return got
+		}
+	}
+}
+
+func TestRateLimitedRecorder(t *testing.T) {
+	fakeRecorder := events.NewFakeRecorder(100)
+	recorder := NewRateLimitedRecorder(fakeRecorder, 0.001, 2)
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+
+	dropped, _ := testutil.GetCounterMetricValue(droppedEvents.WithLabelValues(Dispatched))
+	for i := 0; i < 3; i++ {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, Dispatched, DispatchingAction, "dispatched %d", i)
+	}
+	recorder.Eventf(pod, nil, v1.EventTypeWarning, FailedTasks, RejectingAction, "rejected")
+	// Bind and Preempted events are never dropped.
+	for i := 0; i < 3; i++ {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, Bind, BindingAction, "bound %d", i)
+	}
+
+	expected := []string{
+		"Normal Dispatched dispatched 0", "Normal Dispatched dispatched 1", "Warning FailedTasks rejected",
+		"Normal Bind bound 0", "Normal Bind bound 1", "Normal Bind bound 2",
+	}
+	got := drain(fakeRecorder)
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+	if value, _ := testutil.GetCounterMetricValue(droppedEvents.WithLabelValues(Dispatched)); value != dropped+1 {
+		t.Errorf("Expected 1 dropped event to be counted, got %v", value-dropped)
+	}
+
+	if NewRateLimitedRecorder(nil, DefaultQPS, DefaultBurst) != nil {
+		t.Errorf("Expected a nil recorder to stay nil")
+	}
+}
+
+func TestRecordUnitEvent(t *testing.T) {
+	pg := &schedulingv1a1.PodGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pg"}}
+	makePods := func(n int) []*v1.Pod {
+		pods := make([]*v1.Pod, n)
+		for i := range pods {
+			pods[i] = &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("pod-%d", i)}}
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name              string
+		podGroup          runtime.Object
+		pods              []*v1.Pod
+		expectedPodEvents int
+		expectedPGEvent   string
+	}{
+		{
+			name:              "small gang",
+			podGroup:          pg,
+			pods:              makePods(3),
+			expectedPodEvents: 3,
+			expectedPGEvent:   "Warning Timeout [3 pods] unit timeout",
+		},
+		{
+			name:              "large gang is aggregated",
+			podGroup:          pg,
+			pods:              makePods(25),
+			expectedPodEvents: MaxPodEventsPerUnit,
+			expectedPGEvent:   "Warning Timeout [25 pods, events on the first 10 pods] unit timeout",
+		},
+		{
+			name:              "no PodGroup",
+			pods:              makePods(25),
+			expectedPodEvents: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeRecorder := events.NewFakeRecorder(100)
+			RecordUnitEvent(fakeRecorder, tt.podGroup, tt.pods, v1.EventTypeWarning, Timeout, RejectingAction, "unit timeout")
+
+			got := drain(fakeRecorder)
+			podEvents := got
+			if tt.podGroup != nil {
+				if len(got) == 0 || got[len(got)-1] != tt.expectedPGEvent {
+					t.Fatalf("Expected the PodGroup event %q, got %v", tt.expectedPGEvent, got)
+				}
+				podEvents = got[:len(got)-1]
+			}
+			if len(podEvents) != tt.expectedPodEvents {
+				t.Errorf("Expected %d pod events, got %d", tt.expectedPodEvents, len(podEvents))
+			}
+		})
+	}
+
+	// A nil recorder is ignored.
+	RecordUnitEvent(nil, pg, makePods(1), v1.EventTypeWarning, Timeout, RejectingAction, "unit timeout")
+}
@@ -367,6 +367,15 @@ const (
 	// Enables the in-tree storage to CSI Plugin migration feature.
 	CSIMigration featuregate.Feature = "CSIMigration"
 
+	// owner: @pohly
+	// alpha: v1.19
+	// beta: v1.21
+	//
+	// Enables tracking of available storage capacity that CSI drivers provide.
+	// It's off by default here, because the informers of storage.k8s.io/v1
+	// CSIStorageCapacity can't sync against API servers older than v1.24.
+	CSIStorageCapacity featuregate.Feature = "CSIStorageCapacity"
+
 	// owner: @davidz627
 	// alpha: v1.14
 	// beta: v1.17
@@ -632,6 +641,7 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	ServiceAccountIssuerDiscovery:  {Default: false, PreRelease: featuregate.Alpha},
 	CRIContainerLogRotation:        {Default: true, PreRelease: featuregate.Beta},
 	CSIMigration:                   {Default: true, PreRelease: featuregate.Beta},
+	CSIStorageCapacity:             {Default: false, PreRelease: featuregate.Beta},
 	CSIMigrationGCE:                {Default: false, PreRelease: featuregate.Beta}, // Off by default (requires GCE PD CSI Driver)
 	CSIMigrationGCEComplete:        {Default: false, PreRelease: featuregate.Alpha},
 	CSIMigrationAWS:                {Default: false, PreRelease: featuregate.Beta}, // Off by default (requires AWS EBS CSI driver)
@@ -258,6 +258,12 @@ func (h *Heap) Len() int {
 	return len(h.data.queue)
 }
 
+// Reorder re-establishes the heap ordering. It should be called when the result of
+// lessFunc changes for items already in the heap.
+func (h *Heap) Reorder() {
+	heap.Init(h.data)
+}
+
 func (h *Heap) String() string {
 	return h.name
 }
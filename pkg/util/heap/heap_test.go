@@ -269,3 +269,26 @@ func TestHeap_List(t *testing.T) {
 		}
 	}
 }
+
+// TestHeap_Reorder tests that Heap.Reorder restores the heap invariant after the order changes.
+func TestHeap_Reorder(t *testing.T) {
+	reversed := false
+	h := New("", testHeapObjectKeyFunc, func(val1 interface{}, val2 interface{}) bool {
+		if reversed {
+			return compareInts(val2, val1)
+		}
+		return compareInts(val1, val2)
+	})
+	h.Add(mkHeapObj("foo", 10))
+	h.Add(mkHeapObj("bar", 1))
+	h.Add(mkHeapObj("baz", 11))
+
+	reversed = true
+	h.Reorder()
+	for _, e := range []int{11, 10, 1} {
+		item, err := h.Pop()
+		if a := item.(testHeapObject).val; err != nil || a != e {
+			t.Fatalf("expected %d, got %d", e, a)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpretabity
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxNodeRejectionsInMessage is the maximum number of node rejections rendered in the failure message.
+	MaxNodeRejectionsInMessage = 10
+	// MaxNodeGroupsInMessage is the maximum number of node groups rendered in the failure message.
+	MaxNodeGroupsInMessage = 10
+	// MaxPreemptionFailureReasons is the maximum number of reasons of preemption failures recorded for a unit,
+	// the failures with other reasons are counted as OtherPreemptionFailures.
+	MaxPreemptionFailureReasons = 10
+	// OtherPreemptionFailures is the reason of the preemption failures beyond MaxPreemptionFailureReasons.
+	OtherPreemptionFailures = "other reasons"
+)
+
+// NodeRejection is the number of nodes rejected by a plugin with the reason.
+type NodeRejection struct {
+	Plugin string `json:"plugin,omitempty"`
+	Reason string `json:"reason"`
+	Nodes  int    `json:"nodes"`
+}
+
+// TopologyShortfall explains why a topology domain (node group) can't hold the unit.
+type TopologyShortfall struct {
+	NodeGroup string `json:"nodeGroup"`
+	Reason    string `json:"reason"`
+}
+
+// NodeGroupDiagnosis is the result of scheduling the unit in a node group (topology domain).
+type NodeGroupDiagnosis struct {
+	NodeGroup      string          `json:"nodeGroup"`
+	SuccessfulPods int             `json:"successfulPods"`
+	FailedPods     int             `json:"failedPods"`
+	NodeRejections []NodeRejection `json:"nodeRejections,omitempty"`
+}
+
+// PreemptionFailure is the number of pods failed to preempt with the reason.
+type PreemptionFailure struct {
+	Reason string `json:"reason"`
+	Pods   int    `json:"pods"`
+}
+
+// PreemptionDiagnosis describes the preemption attempts of the unit.
+type PreemptionDiagnosis struct {
+	Attempts int                 `json:"attempts"`
+	Failures []PreemptionFailure `json:"failures,omitempty"`
+}
+
+// UnitDiagnosis is the structured explanation of why a unit is pending.
+type UnitDiagnosis struct {
+	Stage              SchedulingStage                   `json:"stage"`
+	AllPods            int                               `json:"allPods"`
+	UnHandledPods      int                               `json:"unHandledPods"`
+	SuccessfulPods     int                               `json:"successfulPods"`
+	FailedPods         int                               `json:"failedPods"`
+	Categories         map[SchedulingFailureCategory]int `json:"categories,omitempty"`
+	NodeRejections     []NodeRejection                   `json:"nodeRejections,omitempty"`
+	NodeGroups         []NodeGroupDiagnosis              `json:"nodeGroups,omitempty"`
+	TopologyShortfalls []TopologyShortfall               `json:"topologyShortfalls,omitempty"`
+	Preemption         *PreemptionDiagnosis              `json:"preemption,omitempty"`
+}
+
+// Diagnosis returns the structured explanation of the scheduling details.
+func (details *UnitSchedulingDetails) Diagnosis() *UnitDiagnosis {
+	if details == nil {
+		return nil
+	}
+
+	successfulPods := len(details.successfulPods)
+	failedPods := len(details.podError)
+	diagnosis := &UnitDiagnosis{
+		Stage:          details.Stage(),
+		AllPods:        details.allPods,
+		UnHandledPods:  details.allPods - successfulPods - failedPods,
+		SuccessfulPods: successfulPods,
+		FailedPods:     failedPods,
+	}
+	if failedPods > 0 {
+		diagnosis.Categories = details.aggregatePodErrors()
+	}
+
+	diagnosis.NodeRejections = details.sortedNodeRejections()
+
+	for _, nodeGroup := range details.nodeGroups {
+		diagnosis.NodeGroups = append(diagnosis.NodeGroups, nodeGroup)
+	}
+	sort.Slice(diagnosis.NodeGroups, func(i, j int) bool {
+		return diagnosis.NodeGroups[i].NodeGroup < diagnosis.NodeGroups[j].NodeGroup
+	})
+
+	for nodeGroup, reason := range details.prunedNodeGroups {
+		diagnosis.TopologyShortfalls = append(diagnosis.TopologyShortfalls, TopologyShortfall{NodeGroup: nodeGroup, Reason: reason})
+	}
+	sort.Slice(diagnosis.TopologyShortfalls, func(i, j int) bool {
+		return diagnosis.TopologyShortfalls[i].NodeGroup < diagnosis.TopologyShortfalls[j].NodeGroup
+	})
+
+	if details.preemptionAttempts > 0 {
+		preemption := &PreemptionDiagnosis{Attempts: details.preemptionAttempts}
+		for reason, pods := range details.preemptionFailures {
+			preemption.Failures = append(preemption.Failures, PreemptionFailure{Reason: reason, Pods: pods})
+		}
+		sort.Slice(preemption.Failures, func(i, j int) bool {
+			a, b := preemption.Failures[i], preemption.Failures[j]
+			if a.Pods != b.Pods {
+				return a.Pods > b.Pods
+			}
+			return a.Reason < b.Reason
+		})
+		diagnosis.Preemption = preemption
+	}
+	return diagnosis
+}
+
+// sortedNodeRejections returns the node rejections sorted by the number of nodes in descending order.
+func (details *UnitSchedulingDetails) sortedNodeRejections() []NodeRejection {
+	return sortNodeRejections(details.nodeRejections)
+}
+
+func sortNodeRejections(nodeRejections map[nodeRejectionKey]int) []NodeRejection {
+	var rejections []NodeRejection
+	for key, nodes := range nodeRejections {
+		rejections = append(rejections, NodeRejection{Plugin: key.plugin, Reason: key.reason, Nodes: nodes})
+	}
+	sort.Slice(rejections, func(i, j int) bool {
+		a, b := rejections[i], rejections[j]
+		if a.Nodes != b.Nodes {
+			return a.Nodes > b.Nodes
+		}
+		if a.Plugin != b.Plugin {
+			return a.Plugin < b.Plugin
+		}
+		return a.Reason < b.Reason
+	})
+	return rejections
+}
+
+// Marshal returns the diagnosis in JSON format, which is recorded in the PodGroup annotation.
+// Node rejections and node groups are truncated in the same way as String to bound the size.
+func (d *UnitDiagnosis) Marshal() string {
+	if d == nil {
+		return ""
+	}
+	compact := *d
+	if len(compact.NodeRejections) > MaxNodeRejectionsInMessage {
+		compact.NodeRejections = compact.NodeRejections[:MaxNodeRejectionsInMessage]
+	}
+	nodeGroups := d.NodeGroups
+	if len(nodeGroups) > MaxNodeGroupsInMessage {
+		nodeGroups = nodeGroups[:MaxNodeGroupsInMessage]
+	}
+	compact.NodeGroups = make([]NodeGroupDiagnosis, 0, len(nodeGroups))
+	for _, nodeGroup := range nodeGroups {
+		if len(nodeGroup.NodeRejections) > MaxNodeRejectionsInMessage {
+			nodeGroup.NodeRejections = nodeGroup.NodeRejections[:MaxNodeRejectionsInMessage]
+		}
+		compact.NodeGroups = append(compact.NodeGroups, nodeGroup)
+	}
+	data, err := json.Marshal(&compact)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// String renders the diagnosis into a single line message, which is used in PodGroup conditions and events.
+func (d *UnitDiagnosis) String() string {
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "allPods=%d, unHandledPods=%d, successfulPods=%d, failedPods=%d",
+		d.AllPods, d.UnHandledPods, d.SuccessfulPods, d.FailedPods)
+	if d.Stage != SchedulerStage {
+		fmt.Fprintf(&b, ", stage=%s", d.Stage)
+	}
+
+	if len(d.Categories) > 0 {
+		categories := make([]string, 0, len(d.Categories))
+		for category, pods := range d.Categories {
+			categories = append(categories, fmt.Sprintf("%s=%d", category, pods))
+		}
+		sort.Strings(categories)
+		fmt.Fprintf(&b, "; categories: %s", strings.Join(categories, ", "))
+	}
+
+	if len(d.NodeRejections) > 0 {
+		rejections := make([]string, 0, len(d.NodeRejections))
+		for i, rejection := range d.NodeRejections {
+			if i == MaxNodeRejectionsInMessage {
+				rejections = append(rejections, fmt.Sprintf("and %d more", len(d.NodeRejections)-i))
+				break
+			}
+			if len(rejection.Plugin) > 0 {
+				rejections = append(rejections, fmt.Sprintf("%d nodes: [%s] %s", rejection.Nodes, rejection.Plugin, rejection.Reason))
+			} else {
+				rejections = append(rejections, fmt.Sprintf("%d nodes: %s", rejection.Nodes, rejection.Reason))
+			}
+		}
+		fmt.Fprintf(&b, "; nodeRejections: %s", strings.Join(rejections, ", "))
+	}
+
+	if len(d.NodeGroups) > 0 {
+		nodeGroups := make([]string, 0, len(d.NodeGroups))
+		for i, nodeGroup := range d.NodeGroups {
+			if i == MaxNodeGroupsInMessage {
+				nodeGroups = append(nodeGroups, fmt.Sprintf("and %d more", len(d.NodeGroups)-i))
+				break
+			}
+			nodeGroups = append(nodeGroups, fmt.Sprintf("%s: successfulPods=%d, failedPods=%d", nodeGroup.NodeGroup, nodeGroup.SuccessfulPods, nodeGroup.FailedPods))
+		}
+		fmt.Fprintf(&b, "; nodeGroups: [%s]", strings.Join(nodeGroups, "; "))
+	}
+
+	if d.Preemption != nil {
+		fmt.Fprintf(&b, "; preemption: attempts=%d", d.Preemption.Attempts)
+		if len(d.Preemption.Failures) > 0 {
+			failures := make([]string, 0, len(d.Preemption.Failures))
+			for _, failure := range d.Preemption.Failures {
+				failures = append(failures, fmt.Sprintf("%d pods: %s", failure.Pods, failure.Reason))
+			}
+			fmt.Fprintf(&b, ", failures: %s", strings.Join(failures, ", "))
+		}
+	}
+
+	if len(d.TopologyShortfalls) > 0 {
+		shortfalls := make([]string, 0, len(d.TopologyShortfalls))
+		for _, shortfall := range d.TopologyShortfalls {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: %s", shortfall.NodeGroup, shortfall.Reason))
+		}
+		fmt.Fprintf(&b, "; prunedNodeGroups=%d [%s]", len(shortfalls), strings.Join(shortfalls, "; "))
+	}
+	return b.String()
+}
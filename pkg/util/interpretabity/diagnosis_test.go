@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interpretabity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	schedv1alpha1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	pgfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/framework/api"
+	unitutil "github.com/kubewharf/godel-scheduler/pkg/util/unit"
+)
+
+func makeFitError(gpuNodes, taintNodes int) *api.FitError {
+	statuses := make(api.NodeToStatusMap)
+	for i := 0; i < gpuNodes; i++ {
+		statuses[string(rune('a'+i))] = api.NewStatus(api.Unschedulable, "Insufficient nvidia.com/gpu").WithFailedPlugin("NodeResourcesFit")
+	}
+	for i := 0; i < taintNodes; i++ {
+		statuses[string(rune('A'+i))] = api.NewStatus(api.UnschedulableAndUnresolvable, "node(s) had taint x").WithFailedPlugin("TaintToleration")
+	}
+	return &api.FitError{NumAllNodes: gpuNodes + taintNodes, FilteredNodesStatuses: statuses}
+}
+
+func TestUnitDiagnosis(t *testing.T) {
+	scheduling := NewUnitSchedulingDetails(Scheduling, 3)
+	scheduling.AddPodsError(makeFitError(3, 1), "p1")
+	scheduling.AddPodsError(makeFitError(2, 2), "p2", "p3")
+
+	details := NewUnitSchedulingDetails(Preempting, 3)
+	details.AddNodeRejectionsFrom(scheduling)
+	details.AddPreemptionAttempt(nil)
+	details.AddSuccessfulPods("p1")
+	details.AddPreemptionAttempt(&api.PreemptionError{Reason: "no preemption candidates found", Message: "no preemption candidates found for pod default/p2"})
+	details.AddPodsError(&api.PreemptionError{Reason: "no preemption candidates found"}, "p2", "p3")
+	details.AddPrunedNodeGroup("ng1", "insufficient cpu")
+
+	expected := &UnitDiagnosis{
+		Stage:          SchedulerStage,
+		AllPods:        3,
+		SuccessfulPods: 1,
+		FailedPods:     2,
+		Categories:     map[SchedulingFailureCategory]int{InsufficientResourcesError: 2},
+		NodeRejections: []NodeRejection{
+			{Plugin: "NodeResourcesFit", Reason: "Insufficient nvidia.com/gpu", Nodes: 3},
+			{Plugin: "TaintToleration", Reason: "node(s) had taint x", Nodes: 2},
+		},
+		TopologyShortfalls: []TopologyShortfall{{NodeGroup: "ng1", Reason: "insufficient cpu"}},
+		Preemption: &PreemptionDiagnosis{
+			Attempts: 2,
+			Failures: []PreemptionFailure{{Reason: "no preemption candidates found", Pods: 1}},
+		},
+	}
+	if got := details.Diagnosis(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected diagnosis. got:%+v, want:%+v", got, expected)
+	}
+
+	want := "allPods=3, unHandledPods=0, successfulPods=1, failedPods=2; categories: InsufficientResources=2" +
+		"; nodeRejections: 3 nodes: [NodeResourcesFit] Insufficient nvidia.com/gpu, 2 nodes: [TaintToleration] node(s) had taint x" +
+		"; preemption: attempts=2, failures: 1 pods: no preemption candidates found" +
+		"; prunedNodeGroups=1 [ng1: insufficient cpu]"
+	if got := details.FailureMessage(); got != want {
+		t.Errorf("wrong failure message. got:%q, want:%q", got, want)
+	}
+
+	binding := NewUnitSchedulingDetails(Binding, 2)
+	binding.AddSuccessfulPods("p1")
+	binding.AddPodsError(errors.New("conflicts"), "p2")
+	want = "allPods=2, unHandledPods=0, successfulPods=1, failedPods=1, stage=Binder; categories: UnexpectedError=1"
+	if got := binding.FailureMessage(); got != want {
+		t.Errorf("wrong failure message. got:%q, want:%q", got, want)
+	}
+}
+
+func TestUnitDiagnosisOfNodeGroups(t *testing.T) {
+	ng1 := NewUnitSchedulingDetails(Scheduling, 2)
+	ng1.AddSuccessfulPods("p1")
+	ng1.AddPodsError(makeFitError(2, 0), "p2")
+	ng2 := NewUnitSchedulingDetails(Scheduling, 2)
+	ng2.AddPodsError(makeFitError(0, 3), "p1", "p2")
+
+	details := ng1
+	details.AddNodeGroupAttempt("ng2", ng2)
+	details.AddNodeGroupAttempt("ng1", ng1)
+
+	expected := []NodeGroupDiagnosis{
+		{
+			NodeGroup: "ng1", SuccessfulPods: 1, FailedPods: 1,
+			NodeRejections: []NodeRejection{{Plugin: "NodeResourcesFit", Reason: "Insufficient nvidia.com/gpu", Nodes: 2}},
+		},
+		{
+			NodeGroup: "ng2", SuccessfulPods: 0, FailedPods: 2,
+			NodeRejections: []NodeRejection{{Plugin: "TaintToleration", Reason: "node(s) had taint x", Nodes: 3}},
+		},
+	}
+	diagnosis := details.Diagnosis()
+	if !reflect.DeepEqual(diagnosis.NodeGroups, expected) {
+		t.Errorf("unexpected node groups. got:%+v, want:%+v", diagnosis.NodeGroups, expected)
+	}
+
+	want := "allPods=2, unHandledPods=0, successfulPods=1, failedPods=1; categories: InsufficientResources=1" +
+		"; nodeRejections: 2 nodes: [NodeResourcesFit] Insufficient nvidia.com/gpu" +
+		"; nodeGroups: [ng1: successfulPods=1, failedPods=1; ng2: successfulPods=0, failedPods=2]"
+	if got := details.FailureMessage(); got != want {
+		t.Errorf("wrong failure message. got:%q, want:%q", got, want)
+	}
+
+	pg := &schedv1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pg"},
+		Status:     schedv1alpha1.PodGroupStatus{Phase: schedv1alpha1.PodGroupPreScheduling},
+	}
+	client := pgfake.NewSimpleClientset(pg)
+	if err := UpdateSchedulingDiagnosis(client, pg, diagnosis); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.SchedulingV1alpha1().PodGroups("default").Get(context.TODO(), "pg", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := got.Annotations[unitutil.PodGroupSchedulingDiagnosisAnnotationKey]; value != diagnosis.Marshal() {
+		t.Errorf("unexpected diagnosis annotation: %v", value)
+	}
+}
+
+func TestPreemptionFailures(t *testing.T) {
+	details := NewUnitSchedulingDetails(Preempting, 30)
+	for i := 0; i < 3; i++ {
+		status := api.NewStatus(api.Unschedulable, fmt.Sprintf("pod default/p%d exceeds the quota", i)).WithFailedPlugin("ElasticQuota")
+		details.AddPreemptionAttempt(api.NewPreemptionError(fmt.Sprintf("default/p%d", i), 10, status))
+	}
+	details.AddPreemptionAttempt(makeFitError(2, 1))
+	details.AddPreemptionAttempt(fmt.Errorf("skip scheduling this pod, key:default/p4"))
+	details.AddPreemptionAttempt(fmt.Errorf("skip scheduling this pod, key:default/p5"))
+	for i := 0; i < MaxPreemptionFailureReasons; i++ {
+		details.AddPreemptionAttempt(&api.PreemptionError{Reason: fmt.Sprintf("reason-%02d", i)})
+	}
+
+	failures := details.Diagnosis().Preemption.Failures
+	if len(failures) != MaxPreemptionFailureReasons+1 {
+		t.Fatalf("expected %d reasons, but got %+v", MaxPreemptionFailureReasons+1, failures)
+	}
+	expected := []PreemptionFailure{
+		{Reason: "[ElasticQuota] Unschedulable", Pods: 3},
+		{Reason: OtherPreemptionFailures, Pods: 3},
+		{Reason: string(UnexpectedError), Pods: 2},
+		{Reason: "[NodeResourcesFit] Insufficient nvidia.com/gpu", Pods: 1},
+	}
+	if !reflect.DeepEqual(failures[:len(expected)], expected) {
+		t.Errorf("unexpected preemption failures. got:%+v, want:%+v", failures[:len(expected)], expected)
+	}
+}
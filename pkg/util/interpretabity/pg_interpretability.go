@@ -17,6 +17,7 @@ limitations under the License.
 package interpretabity
 
 import (
+	"errors"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -39,8 +40,24 @@ type SchedulingPhase string
 
 const (
 	Scheduling SchedulingPhase = "Scheduling"
+	Preempting SchedulingPhase = "Preempting"
+	Binding    SchedulingPhase = "Binding"
 )
 
+// SchedulingStage describe the component where the unit failed.
+type SchedulingStage string
+
+const (
+	SchedulerStage SchedulingStage = "Scheduler"
+	BinderStage    SchedulingStage = "Binder"
+)
+
+// nodeRejectionKey identifies the plugin and the reason rejecting nodes.
+type nodeRejectionKey struct {
+	plugin string
+	reason string
+}
+
 // UnitSchedulingDetails interpret the scheduling category for podgroup.
 type UnitSchedulingDetails struct {
 	phase          SchedulingPhase
@@ -48,18 +65,72 @@ type UnitSchedulingDetails struct {
 	successfulPods sets.String
 	// podError records the failed error of every failed Pod
 	podError map[string]error
+	// prunedNodeGroups records the reason of every node group pruned before scheduling pods
+	prunedNodeGroups map[string]string
+	// nodeRejections records the maximum number of nodes rejected by plugin and reason among all failed Pods
+	nodeRejections map[nodeRejectionKey]int
+	// preemptionAttempts is the number of Pods which tried to preempt others
+	preemptionAttempts int
+	// preemptionFailures records the number of Pods failed to preempt by reason, see preemptionFailureReason
+	preemptionFailures map[string]int
+	// nodeGroups records the results of scheduling the unit in each node group
+	nodeGroups map[string]NodeGroupDiagnosis
 }
 
 // NewUnitSchedulingDetails returns a interpreter instance.
 func NewUnitSchedulingDetails(phase SchedulingPhase, allPods int) *UnitSchedulingDetails {
 	return &UnitSchedulingDetails{
-		phase:          phase,
-		allPods:        allPods,
-		successfulPods: sets.NewString(),
-		podError:       make(map[string]error),
+		phase:              phase,
+		allPods:            allPods,
+		successfulPods:     sets.NewString(),
+		podError:           make(map[string]error),
+		nodeRejections:     make(map[nodeRejectionKey]int),
+		preemptionFailures: make(map[string]int),
+	}
+}
+
+// Stage returns the component where the scheduling details come from.
+func (details *UnitSchedulingDetails) Stage() SchedulingStage {
+	if details != nil && details.phase == Binding {
+		return BinderStage
+	}
+	return SchedulerStage
+}
+
+// AddPrunedNodeGroup records the node group which is pruned by gang-level capacity check.
+func (details *UnitSchedulingDetails) AddPrunedNodeGroup(nodeGroup, reason string) {
+	if details == nil {
+		return
+	}
+	if details.prunedNodeGroups == nil {
+		details.prunedNodeGroups = make(map[string]string)
+	}
+	details.prunedNodeGroups[nodeGroup] = reason
+}
+
+// AddNodeGroupAttempt records the result of scheduling the unit in the node group.
+func (details *UnitSchedulingDetails) AddNodeGroupAttempt(nodeGroup string, attempt *UnitSchedulingDetails) {
+	if details == nil || attempt == nil {
+		return
+	}
+	if details.nodeGroups == nil {
+		details.nodeGroups = make(map[string]NodeGroupDiagnosis)
+	}
+	details.nodeGroups[nodeGroup] = NodeGroupDiagnosis{
+		NodeGroup:      nodeGroup,
+		SuccessfulPods: len(attempt.successfulPods),
+		FailedPods:     len(attempt.podError),
+		NodeRejections: attempt.sortedNodeRejections(),
 	}
 }
 
+func (details *UnitSchedulingDetails) GetPrunedNodeGroups() map[string]string {
+	if details == nil {
+		return nil
+	}
+	return details.prunedNodeGroups
+}
+
 func (details *UnitSchedulingDetails) AddSuccessfulPods(podKey ...string) {
 	if details == nil {
 		return
@@ -80,6 +151,80 @@ func (details *UnitSchedulingDetails) AddPodsError(err error, podKey ...string)
 	}
 
 	details.successfulPods.Delete(podKey...)
+
+	var fitErr *api.FitError
+	if errors.As(err, &fitErr) {
+		details.addNodeRejections(fitErr)
+	}
+}
+
+// fitErrorRejections returns the number of nodes rejected by plugin and reason in the FitError.
+func fitErrorRejections(fitErr *api.FitError) map[nodeRejectionKey]int {
+	rejections := make(map[nodeRejectionKey]int)
+	for _, status := range fitErr.FilteredNodesStatuses {
+		for _, reason := range status.Reasons() {
+			rejections[nodeRejectionKey{plugin: status.FailedPlugin(), reason: reason}]++
+		}
+	}
+	return rejections
+}
+
+func (details *UnitSchedulingDetails) addNodeRejections(fitErr *api.FitError) {
+	for key, nodes := range fitErrorRejections(fitErr) {
+		if nodes > details.nodeRejections[key] {
+			details.nodeRejections[key] = nodes
+		}
+	}
+}
+
+// AddNodeRejectionsFrom records the node rejections of other details, e.g. the rejections happened
+// in scheduling phase are kept in the details of preempting phase.
+func (details *UnitSchedulingDetails) AddNodeRejectionsFrom(other *UnitSchedulingDetails) {
+	if details == nil || other == nil {
+		return
+	}
+	for key, nodes := range other.nodeRejections {
+		if nodes > details.nodeRejections[key] {
+			details.nodeRejections[key] = nodes
+		}
+	}
+}
+
+// AddPreemptionAttempt records an attempt of preemption, err is nil if the preemption succeeded.
+// At most MaxPreemptionFailureReasons reasons are recorded, the others are counted as OtherPreemptionFailures.
+func (details *UnitSchedulingDetails) AddPreemptionAttempt(err error) {
+	if details == nil {
+		return
+	}
+	details.preemptionAttempts++
+	if err == nil {
+		return
+	}
+	reason := preemptionFailureReason(err)
+	if _, ok := details.preemptionFailures[reason]; !ok && len(details.preemptionFailures) >= MaxPreemptionFailureReasons {
+		reason = OtherPreemptionFailures
+	}
+	details.preemptionFailures[reason]++
+}
+
+// preemptionFailureReason returns the reason of the preemption failure, which doesn't contain the names of
+// pods or nodes, so that the failures of all pods in the unit can be aggregated by it.
+func preemptionFailureReason(err error) string {
+	var preemptionErr *api.PreemptionError
+	if errors.As(err, &preemptionErr) {
+		return preemptionErr.Reason
+	}
+	var fitErr *api.FitError
+	if errors.As(err, &fitErr) {
+		// The reason rejecting the most nodes.
+		if rejections := sortNodeRejections(fitErrorRejections(fitErr)); len(rejections) > 0 {
+			if len(rejections[0].Plugin) > 0 {
+				return fmt.Sprintf("[%s] %s", rejections[0].Plugin, rejections[0].Reason)
+			}
+			return rejections[0].Reason
+		}
+	}
+	return string(errorToFailureCategory(err))
 }
 
 func (details *UnitSchedulingDetails) GetPodError(podKey string) error {
@@ -101,7 +246,7 @@ func errorToFailureCategory(err error) SchedulingFailureCategory {
 	}
 
 	switch err.(type) {
-	case api.PreemptionError:
+	case *api.PreemptionError:
 		return InsufficientResourcesError
 	case *api.FitError:
 		return InsufficientResourcesError
@@ -123,18 +268,11 @@ func (details *UnitSchedulingDetails) aggregatePodErrors() map[SchedulingFailure
 }
 
 // FailureMessage returns the detailed failure message about the scheduling.
-// If all Pods are successful, returns "".
 func (details *UnitSchedulingDetails) FailureMessage() string {
 	if details == nil {
 		return ""
 	}
-
-	successfulPods := len(details.successfulPods)
-	failedPods := len(details.podError)
-	unHandledPods := details.allPods - successfulPods - failedPods
-
-	return fmt.Sprintf("allPods=%d, unHandledPods=%d, successfulPods=%d, failedPods=%d",
-		details.allPods, unHandledPods, successfulPods, failedPods)
+	return details.Diagnosis().String()
 }
 
 func (details *UnitSchedulingDetails) GetErrors() []error {
@@ -142,5 +280,8 @@ func (details *UnitSchedulingDetails) GetErrors() []error {
 	for podKey, err := range details.podError {
 		errs = append(errs, fmt.Errorf("pod: %v, err: %v", podKey, err))
 	}
+	for nodeGroup, reason := range details.prunedNodeGroups {
+		errs = append(errs, fmt.Errorf("node group: %v, pruned: %v", nodeGroup, reason))
+	}
 	return errs
 }
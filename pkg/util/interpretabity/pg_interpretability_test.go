@@ -126,3 +126,20 @@ func TestSchedulingFailureInterpreter(t *testing.T) {
 		})
 	}
 }
+
+func TestPrunedNodeGroupsFailureMessage(t *testing.T) {
+	details := NewUnitSchedulingDetails(Scheduling, 2)
+	if got, want := details.FailureMessage(), "allPods=2, unHandledPods=2, successfulPods=0, failedPods=0"; got != want {
+		t.Errorf("wrong failure message. got:%q, want:%q", got, want)
+	}
+
+	details.AddPrunedNodeGroup("ng2", "insufficient memory")
+	details.AddPrunedNodeGroup("ng1", "insufficient cpu")
+	want := "allPods=2, unHandledPods=2, successfulPods=0, failedPods=0; prunedNodeGroups=2 [ng1: insufficient cpu; ng2: insufficient memory]"
+	if got := details.FailureMessage(); got != want {
+		t.Errorf("wrong failure message. got:%q, want:%q", got, want)
+	}
+	if got := len(details.GetErrors()); got != 2 {
+		t.Errorf("wrong number of errors. got:%d, want:2", got)
+	}
+}
@@ -57,6 +57,33 @@ func UpdatePreSchedulingCondition(pgcli pgclientset.Interface, pg *schedv1alpha1
 	return nil
 }
 
+// UpdateSchedulingDiagnosis records the structured diagnosis on the PodGroup which is PreScheduling,
+// the annotation is not patched if the diagnosis is unchanged.
+func UpdateSchedulingDiagnosis(pgcli pgclientset.Interface, pg *schedv1alpha1.PodGroup, diagnosis *UnitDiagnosis) error {
+	if pgcli == nil {
+		return fmt.Errorf("client is nil")
+	}
+	if pg.Status.Phase != schedv1alpha1.PodGroupPreScheduling {
+		return fmt.Errorf("PodGroup:%v isn't in phase:%v", unitutil.GetPodGroupKey(pg), schedv1alpha1.PodGroupPreScheduling)
+	}
+	value := diagnosis.Marshal()
+	if len(value) == 0 || pg.Annotations[unitutil.PodGroupSchedulingDiagnosisAnnotationKey] == value {
+		return nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{unitutil.PodGroupSchedulingDiagnosisAnnotationKey: value},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = pgcli.SchedulingV1alpha1().PodGroups(pg.Namespace).Patch(context.TODO(), pg.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
 // PatchPodGroupCondition calculates the delta bytes change from <old> to <new>,
 // and then submit a request to API server to patch the podgroup changes.
 func PatchPodGroupCondition(crdCli pgclientset.Interface, old, new *schedv1alpha1.PodGroup) (err error) {
@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"math/rand"
+
 	framework "github.com/kubewharf/godel-scheduler/pkg/framework/api"
 	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
 )
@@ -35,3 +37,15 @@ func SwitchTypeToQos(switchType framework.SwitchType) string {
 		return string(podutil.UndefinedPod)
 	}
 }
+
+// SamplePluginMetrics decides whether the per-plugin metrics of a scheduling or binding cycle
+// should be recorded, given the percentage of cycles to sample.
+func SamplePluginMetrics(percent int32) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	return rand.Int31n(100) < percent
+}
@@ -22,6 +22,10 @@ const (
 	// GodelSchedulerNodeAnnotationKey is the annotation key in both Node and CNR api objects,
 	// value is the godel scheduler whose node partition contains this node
 	GodelSchedulerNodeAnnotationKey = "godel.bytedance.com/scheduler-name"
+
+	// GodelSchedulerNodeLabelKey is the label key in both Node and NMNode api objects, written along with
+	// GodelSchedulerNodeAnnotationKey, so that schedulers are able to watch their partitions by label selector
+	GodelSchedulerNodeLabelKey = "godel.bytedance.com/scheduler-name"
 )
 
 func NodeOfThisScheduler(annotations map[string]string, schedulerName string) bool {
@@ -32,6 +36,11 @@ func NodeOfThisScheduler(annotations map[string]string, schedulerName string) bo
 	return false
 }
 
+// PartitionLabelSelector returns the label selector of the nodes in the partition of the scheduler
+func PartitionLabelSelector(schedulerName string) string {
+	return GodelSchedulerNodeLabelKey + "=" + schedulerName
+}
+
 // Scheduable checks if the node is schedulable
 // a node is schedulable only if its condition NodeReady == True
 func Scheduable(node *v1.Node) bool {
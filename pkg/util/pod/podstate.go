@@ -37,6 +37,19 @@ const (
 	// this is used only when Node Partition is physical
 	FailedSchedulersAnnotationKey = "godel.bytedance.com/failed-schedulers"
 
+	// PartitionLabelKey is a pod label key, value is the scheduler whose partition the pod belongs to, which is the
+	// selected scheduler of dispatched pods and the scheduler of the node of bound pods. It's written by the dispatcher
+	// so that schedulers are able to watch the pods of their partitions by label selector
+	PartitionLabelKey = "godel.bytedance.com/scheduler-name"
+
+	// PartitionNodeAnnotationKey is a pod annotation key written along with PartitionLabelKey on bound pods, value is
+	// the node name, so that schedulers of other partitions are able to locate the pod by its metadata
+	PartitionNodeAnnotationKey = "godel.bytedance.com/partition-node"
+
+	// RedispatchCountAnnotationKey is a pod annotation key, value is the number of times the dispatcher has sent the pod
+	// to a scheduler again after it failed in other schedulers
+	RedispatchCountAnnotationKey = "godel.bytedance.com/redispatch-count"
+
 	// TraceContext represents the span context for the pod
 	TraceContext = "trace-context"
 
@@ -79,6 +92,14 @@ const (
 	// MicroTopologyKey is an annotation key for pod micro topology assigned by scheduler&binder
 	MicroTopologyKey = "godel.bytedance.com/micro-topology"
 
+	// GPUMicroTopologyKey is an annotation key for the gpu device assigned by binder to the pod sharing gpus
+	GPUMicroTopologyKey = "godel.bytedance.com/gpu-micro-topology"
+
+	// GPUMemoryAnnotationKey and GPUCoreAnnotationKey are pod annotation keys for requesting shared gpus,
+	// they are used only if the pod doesn't request gpu memory or gpu core through extended resources.
+	GPUMemoryAnnotationKey = "godel.bytedance.com/gpu-memory"
+	GPUCoreAnnotationKey   = "godel.bytedance.com/gpu-core"
+
 	IgnorePodsLimitAnnotationKey = "godel.bytedance.com/ignore-pods-limit"
 
 	ProtectionDurationFromPreemptionKey = "godel.bytedance.com/protection-duration-from-preemption"
@@ -99,6 +120,21 @@ const (
 	// reservation related
 	MatchedReservationPlaceholderKey = "godel.bytedance.com/matched-reservation-placeholder"
 	ReservationTTLKey                = "godel.bytedance.com/reservation-ttl"
+	// ExpectedDurationAnnotationKey is a pod annotation key, value is the number of seconds the pod is expected to run.
+	// Pods finishing before the deadline of a backfill reservation may be placed into the reserved resources.
+	ExpectedDurationAnnotationKey = "godel.bytedance.com/expected-duration-seconds"
+
+	// TopologyLevelsAnnotationKey is a PodGroup annotation key, value is the topology keys of a hierarchical
+	// network topology, ordered from the tightest level to the loosest level, e.g. "leaf-switch,spine-switch,superpod".
+	TopologyLevelsAnnotationKey = "godel.bytedance.com/topology-levels"
+
+	// AchievedTopologyLevelAnnotationKey is a pod annotation key, value is the tightest topology level (one of the keys
+	// in TopologyLevelsAnnotationKey) shared by all the pods of the unit placed in the same scheduling attempt.
+	AchievedTopologyLevelAnnotationKey = "godel.bytedance.com/achieved-topology-level"
+
+	// TopologyLevelStatusAnnotationKey is a PodGroup annotation key, value is the TopologyLevelStatus in JSON format,
+	// which records the loosest topology level achieved by the scheduled pods. Use GetTopologyLevelStatus to parse it.
+	TopologyLevelStatusAnnotationKey = "godel.bytedance.com/topology-level-status"
 )
 
 type PodState string
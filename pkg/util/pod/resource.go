@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+)
+
+// PodRequests returns the resources reserved for the pod on its node. It's the accounting model shared by the
+// scheduler (node resources fit, NUMA topology and cache totals) and the binder (conflict check), so that they
+// always agree on the usage of nodes:
+//
+//	requests = max(sum(containers), max(initContainers)) + overhead
+//
+// Init containers run one by one before the containers start, so only the largest one is reserved. The overhead
+// of the runtime class is added if the PodOverhead feature is enabled.
+//
+// NOTE: restartable init containers (sidecars) and the resources allocated by in-place resizing are not accounted,
+// because the core/v1 API vendored by this repo has neither `restartPolicy` of init containers nor `status.resize`
+// and the allocated resources of containers. They should be added here once the API is upgraded.
+func PodRequests(pod *v1.Pod) v1.ResourceList {
+	return podRequests(pod, func(requests v1.ResourceList) v1.ResourceList {
+		return requests
+	})
+}
+
+// PodNonZeroRequests is the same as PodRequests, except that cpu and memory not requested by a container are
+// counted as the defaults. It's used to score nodes, so that pods without requests are not considered free.
+func PodNonZeroRequests(pod *v1.Pod) v1.ResourceList {
+	return podRequests(pod, nonZeroRequests)
+}
+
+func podRequests(pod *v1.Pod, containerRequests func(v1.ResourceList) v1.ResourceList) v1.ResourceList {
+	reqs := v1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResourceList(reqs, containerRequests(pod.Spec.Containers[i].Resources.Requests))
+	}
+	// take max_resource(sum_pod, any_init_container)
+	for i := range pod.Spec.InitContainers {
+		maxResourceList(reqs, containerRequests(pod.Spec.InitContainers[i].Resources.Requests))
+	}
+	// If Overhead is being utilized, add to the total requests for the pod
+	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
+		addResourceList(reqs, pod.Spec.Overhead)
+	}
+	return reqs
+}
+
+func nonZeroRequests(requests v1.ResourceList) v1.ResourceList {
+	_, hasCPU := requests[v1.ResourceCPU]
+	_, hasMemory := requests[v1.ResourceMemory]
+	if hasCPU && hasMemory {
+		return requests
+	}
+	result := make(v1.ResourceList, len(requests)+2)
+	for name, quantity := range requests {
+		result[name] = quantity
+	}
+	// Override if un-set, but not if explicitly set to zero
+	if !hasCPU {
+		result[v1.ResourceCPU] = *resource.NewMilliQuantity(util.DefaultMilliCPURequest, resource.DecimalSI)
+	}
+	if !hasMemory {
+		result[v1.ResourceMemory] = *resource.NewQuantity(util.DefaultMemoryRequest, resource.BinarySI)
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+)
+
+func makeResourceList(cpu, memory string) v1.ResourceList {
+	rl := v1.ResourceList{}
+	if len(cpu) > 0 {
+		rl[v1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if len(memory) > 0 {
+		rl[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return rl
+}
+
+func TestPodRequests(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("2", "1Gi")}},
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("2", "3Gi")}},
+			},
+			Containers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("2", "1Gi")}},
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("1", "")}},
+			},
+			Overhead: makeResourceList("500m", "100Mi"),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		podOverhead     bool
+		expected        v1.ResourceList
+		expectedNonZero v1.ResourceList
+	}{
+		{
+			name:            "overhead is added",
+			podOverhead:     true,
+			expected:        makeResourceList("3500m", "3172Mi"),
+			expectedNonZero: makeResourceList("3500m", "3172Mi"),
+		},
+		{
+			name:            "overhead is ignored if PodOverhead is disabled",
+			podOverhead:     false,
+			expected:        makeResourceList("3", "3Gi"),
+			expectedNonZero: makeResourceList("3", "3Gi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodOverhead, tt.podOverhead)()
+			assertResourceList(t, "PodRequests", PodRequests(pod), tt.expected)
+			assertResourceList(t, "PodNonZeroRequests", PodNonZeroRequests(pod), tt.expectedNonZero)
+		})
+	}
+}
+
+func TestPodNonZeroRequests(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("1", "")}},
+				{Resources: v1.ResourceRequirements{Requests: makeResourceList("", "0")}},
+			},
+		},
+	}
+	// cpu of the second container and memory of the first one are defaulted, explicit zero is kept.
+	assertResourceList(t, "PodRequests", PodRequests(pod), makeResourceList("1", "0"))
+	assertResourceList(t, "PodNonZeroRequests", PodNonZeroRequests(pod), makeResourceList("1100m", "200Mi"))
+	if _, ok := pod.Spec.Containers[0].Resources.Requests[v1.ResourceMemory]; ok {
+		t.Errorf("requests of containers shouldn't be changed")
+	}
+}
+
+func assertResourceList(t *testing.T, name string, got, expected v1.ResourceList) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Errorf("%s: expected %v, but got %v", name, expected, got)
+		return
+	}
+	for resourceName, quantity := range expected {
+		if gotQuantity, ok := got[resourceName]; !ok || gotQuantity.Cmp(quantity) != 0 {
+			t.Errorf("%s: expected %s %s, but got %s", name, resourceName, quantity.String(), gotQuantity.String())
+		}
+	}
+}
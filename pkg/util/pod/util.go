@@ -17,6 +17,7 @@ limitations under the License.
 package pod
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -502,6 +503,15 @@ func GetFailedSchedulersNames(pod *v1.Pod) sets.String {
 	return sets.NewString()
 }
 
+// GetRedispatchCount returns the number of times the pod has been redispatched, from annotation redispatchCount
+func GetRedispatchCount(pod *v1.Pod) int {
+	count, err := strconv.Atoi(pod.Annotations[RedispatchCountAnnotationKey])
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
 // IsPodEligibleForPreemption returns false if a pod never preempts; true otherwise.
 func IsPodEligibleForPreemption(pod *v1.Pod) bool {
 	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
@@ -519,40 +529,19 @@ func GetBestEffortPodAppName(pod *v1.Pod) string {
 	return ""
 }
 
+// GetPodRequest returns the request of the resource by the pod, see PodRequests.
 func GetPodRequest(pod *v1.Pod, resourceType v1.ResourceName, format resource.Format) *resource.Quantity {
 	result := resource.NewQuantity(0, format)
-	for _, container := range pod.Spec.Containers {
-		for key, value := range container.Resources.Requests {
-			if key == resourceType {
-				result.Add(value)
-			}
-		}
-	}
-
-	for _, container := range pod.Spec.InitContainers {
-		for key, value := range container.Resources.Requests {
-			if key == resourceType {
-				if result.Cmp(value) < 0 {
-					result.SetMilli(value.MilliValue())
-				}
-			}
-		}
+	if quantity, ok := PodRequests(pod)[resourceType]; ok {
+		result.Add(quantity)
 	}
-
 	return result
 }
 
+// GetPodRequests returns the requests of the pod keyed by resource names, see PodRequests.
 func GetPodRequests(pod *v1.Pod) map[string]*resource.Quantity {
-	reqs := v1.ResourceList{}
-	for _, container := range pod.Spec.Containers {
-		addResourceList(reqs, container.Resources.Requests)
-	}
-	// init containers define the minimum of any resource
-	for _, container := range pod.Spec.InitContainers {
-		maxResourceList(reqs, container.Resources.Requests)
-	}
-
-	result := make(map[string]*resource.Quantity)
+	reqs := PodRequests(pod)
+	result := make(map[string]*resource.Quantity, len(reqs))
 	for key, quantity := range reqs {
 		copy := quantity.DeepCopy()
 		result[key.String()] = &copy
@@ -560,6 +549,42 @@ func GetPodRequests(pod *v1.Pod) map[string]*resource.Quantity {
 	return result
 }
 
+// GetGPUShareRequests returns the gpu memory (in bytes) and gpu core (in percentage of a single device)
+// requested by the pod sharing gpus. Requests of extended resources take precedence over annotations.
+// Pods requesting whole gpus are not considered as sharing gpus.
+func GetGPUShareRequests(pod *v1.Pod) (int64, int64) {
+	reqs := GetPodRequests(pod)
+	if gpu := reqs[util.ResourceGPU.String()]; gpu != nil && !gpu.IsZero() {
+		return 0, 0
+	}
+	memory, core := reqs[util.ResourceGPUMemory.String()], reqs[util.ResourceGPUCore.String()]
+	if memory == nil && core == nil {
+		memory = parseQuantityAnnotation(pod, GPUMemoryAnnotationKey)
+		core = parseQuantityAnnotation(pod, GPUCoreAnnotationKey)
+	}
+	var memoryVal, coreVal int64
+	if memory != nil {
+		memoryVal = memory.Value()
+	}
+	if core != nil {
+		coreVal = core.Value()
+	}
+	return memoryVal, coreVal
+}
+
+func parseQuantityAnnotation(pod *v1.Pod, key string) *resource.Quantity {
+	val, ok := pod.GetAnnotations()[key]
+	if !ok {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(val)
+	if err != nil {
+		klog.InfoS("Failed to parse quantity from pod annotation", "pod", klog.KObj(pod), "key", key, "value", val, "err", err)
+		return nil
+	}
+	return &quantity
+}
+
 // addResourceList adds the resources in newList to list
 func addResourceList(list, newList v1.ResourceList) {
 	for name, quantity := range newList {
@@ -808,6 +833,7 @@ const (
 	PodGroupKind         = "PodGroup"
 	DaemonSetKind        = "DaemonSet"
 	RequestTemplateKind  = "RequestTemplate"
+	JobKind              = "Job"
 
 	KeySeperator string = "/"
 )
@@ -889,6 +915,7 @@ func CleanupPodAnnotations(client clientset.Interface, pod *v1.Pod) error {
 	delete(podCopy.Annotations, NominatedNodeAnnotationKey)
 	delete(podCopy.Annotations, FailedSchedulersAnnotationKey)
 	delete(podCopy.Annotations, MicroTopologyKey)
+	delete(podCopy.Annotations, GPUMicroTopologyKey)
 
 	// reset pod state to dispatched
 	podCopy.Annotations[PodStateAnnotationKey] = string(PodDispatched)
@@ -910,6 +937,15 @@ const (
 	ReservationOwnerTypeAnno            = "godel.bytedance.com/reservation-owner-type"
 	ReservationOwnerNameAnno            = "godel.bytedance.com/reservation-owner"
 	ReservationOriginalPodNameAnno      = "godel.bytedance.com/reservation-original-pod"
+	// ReservationReplicasAnno is set on the Reservation created by users ahead of time, the value is
+	// the number of placeholder pods to be scheduled according to the reservation template.
+	ReservationReplicasAnno = "godel.bytedance.com/reservation-replicas"
+	// ReservationKind is the kind of the owner reference set on placeholder pods of proactive reservations.
+	ReservationKind = "Reservation"
+	// ReservationIdentityAnno records the stable identity of the original pod on reservations and placeholder pods.
+	ReservationIdentityAnno = "godel.bytedance.com/reservation-identity"
+	// JobCompletionIndexAnnotation is the completion index of pods created by Indexed batch Jobs.
+	JobCompletionIndexAnnotation = "batch.kubernetes.io/job-completion-index"
 )
 
 func GetReservationIndex(pod *v1.Pod) string {
@@ -921,6 +957,12 @@ func GetReservationIndex(pod *v1.Pod) string {
 		return pod.Annotations[ReservationIndexAnnotation]
 	}
 
+	// pods can also claim the reserved resources by label, so that the index could be set in the pod template
+	// of workloads and used by label selectors at the same time.
+	if pod.Labels != nil && len(pod.Labels[ReservationIndexAnnotation]) != 0 {
+		return pod.Labels[ReservationIndexAnnotation]
+	}
+
 	return ""
 }
 
@@ -933,16 +975,58 @@ func GetReservationPlaceholder(pod *v1.Pod) string {
 	// for different ownerreference, choose different placeholder.
 	var ph string
 	owner := metav1.GetControllerOf(pod)
-	// statefulset extension.
-	if owner != nil && owner.Kind == StatefulSetExtension {
+	// statefulset, statefulset extension and job.
+	if owner != nil && (owner.Kind == StatefulSetExtension || owner.Kind == StatefulSetKind || owner.Kind == JobKind) {
 		// use owner name as placeholder.
 		ph = owner.Name
 		return ph
 	}
 
-	// deployment
-	ph = util.GetDeployNameFromPod(pod)
-	return ph
+	// deployment, it takes precedence over pod group to keep the placeholders of existing deployments.
+	if ph = util.GetDeployNameFromPod(pod); len(ph) > 0 {
+		return ph
+	}
+
+	// pod group
+	return GetPodGroupName(pod)
+}
+
+// GetReservationIdentity returns the stable identity of the pod among pods with the same placeholder,
+// e.g. the ordinal of StatefulSet pods or the completion index of Indexed Job pods. Reserved resources
+// with identity could only be consumed by pods with the same identity (or pods without identity).
+func GetReservationIdentity(pod *v1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	if identity := pod.Annotations[ReservationIdentityAnno]; len(identity) > 0 {
+		return identity
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+	switch owner.Kind {
+	case StatefulSetKind, StatefulSetExtension:
+		// pods of statefulset are named as <statefulset name>-<ordinal>.
+		if !strings.HasPrefix(pod.Name, owner.Name+"-") {
+			return ""
+		}
+		ordinal := strings.TrimPrefix(pod.Name, owner.Name+"-")
+		if _, err := strconv.Atoi(ordinal); err != nil {
+			return ""
+		}
+		return ordinal
+	case JobKind:
+		return pod.Annotations[JobCompletionIndexAnnotation]
+	}
+	return ""
+}
+
+// MatchReservationIdentity checks whether the pod could consume the resources reserved by the placeholder pod.
+func MatchReservationIdentity(pod, placeholderPod *v1.Pod) bool {
+	identity, reserved := GetReservationIdentity(pod), GetReservationIdentity(placeholderPod)
+	return len(identity) == 0 || len(reserved) == 0 || identity == reserved
 }
 
 func IsPvcVolumeLocalPV(pvcLister corelisters.PersistentVolumeClaimLister, pvc string, pod *v1.Pod) (bool, string) {
@@ -992,6 +1076,22 @@ func HasMatchedReservationPlaceholder(pod *v1.Pod) bool {
 	return false
 }
 
+// GetExpectedDuration returns how long the pod is expected to run, and false if it's not specified or invalid.
+func GetExpectedDuration(pod *v1.Pod) (time.Duration, bool) {
+	if pod == nil || pod.Annotations == nil {
+		return 0, false
+	}
+	val, ok := pod.Annotations[ExpectedDurationAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
 func IsReservationPlaceholderPod(pod *v1.Pod) bool {
 	if pod == nil || pod.Annotations == nil {
 		return false
@@ -1037,6 +1137,13 @@ func CreateReservationFakePod(pod *v1.Pod) *v1.Pod {
 	}
 
 	fakePod := pod.DeepCopy()
+	if identity := GetReservationIdentity(pod); len(identity) > 0 {
+		// identity may depend on the pod name, record it before the name is changed.
+		if fakePod.Annotations == nil {
+			fakePod.Annotations = map[string]string{}
+		}
+		fakePod.Annotations[ReservationIdentityAnno] = identity
+	}
 	removeTopologyInfoOnPlaceholder(fakePod)
 	injectPlaceholderInfo(fakePod, ReservationPlaceholderPostFix)
 	return fakePod
@@ -1088,6 +1195,9 @@ func ConstructReservationAccordingToPod(pod *v1.Pod, defaultTTL int64) (res *sch
 		PlaceholderPodUIDAnno:          string(pod.UID),
 		ReservationOriginalPodNameAnno: pod.Name,
 	}
+	if identity := GetReservationIdentity(pod); len(identity) > 0 {
+		res.Annotations[ReservationIdentityAnno] = identity
+	}
 	// for model reservation
 	owner := metav1.GetControllerOf(pod)
 	if owner != nil {
@@ -1110,7 +1220,10 @@ func ConvertReservationToPod(res *schedulingv1alpha1.Reservation) *v1.Pod {
 	}
 
 	// 1.set spec info
-	pc := *res.Spec.Template.Spec.Priority
+	var pc int32
+	if res.Spec.Template.Spec.Priority != nil {
+		pc = *res.Spec.Template.Spec.Priority
+	}
 	templateSpec := res.Spec.Template.Spec
 	pod := &v1.Pod{
 		Spec: v1.PodSpec{
@@ -1230,6 +1343,34 @@ func IsPlaceholderPod(p *v1.Pod) bool {
 	return p.Annotations[ReservationPlaceHolderPodAnnotation] == "true"
 }
 
+// GetReservationReplicas returns the number of placeholder pods requested by a proactive reservation.
+func GetReservationReplicas(res *schedulingv1alpha1.Reservation) int {
+	if res == nil || res.Annotations == nil {
+		return 0
+	}
+	replicas, err := strconv.Atoi(res.Annotations[ReservationReplicasAnno])
+	if err != nil || replicas < 0 {
+		return 0
+	}
+	return replicas
+}
+
+// IsProactiveReservation checks whether the reservation is created by users before pods exist. Such reservation
+// doesn't occupy resources by itself, the resources are reserved by the placeholder pods scheduled for it.
+func IsProactiveReservation(res *schedulingv1alpha1.Reservation) bool {
+	return res != nil && res.Spec.NodeName == "" && GetReservationReplicas(res) > 0
+}
+
+// IsProactiveReservationPlaceholderPod checks whether the pod is a placeholder pod created for a proactive reservation.
+// These pods reserve resources for others and should never consume the reserved resources.
+func IsProactiveReservationPlaceholderPod(pod *v1.Pod) bool {
+	if pod == nil {
+		return false
+	}
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == ReservationKind
+}
+
 func GetPlaceholderFromReservation(res *schedulingv1alpha1.Reservation) string {
 	if res == nil || res.Annotations == nil {
 		return ""
@@ -1237,3 +1378,78 @@ func GetPlaceholderFromReservation(res *schedulingv1alpha1.Reservation) string {
 
 	return res.Annotations[ReservationIndexAnnotation]
 }
+
+// GetTopologyLevels returns the topology keys of the hierarchical network topology set in the annotations,
+// ordered from the tightest level to the loosest level. Empty and duplicated keys are ignored.
+func GetTopologyLevels(annotations map[string]string) []string {
+	value := annotations[TopologyLevelsAnnotationKey]
+	if len(value) == 0 {
+		return nil
+	}
+	var levels []string
+	seen := sets.NewString()
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if len(key) == 0 || seen.Has(key) {
+			continue
+		}
+		seen.Insert(key)
+		levels = append(levels, key)
+	}
+	return levels
+}
+
+// TopologyLevelStatus is the value of TopologyLevelStatusAnnotationKey on scheduled PodGroups, in JSON format,
+// e.g. `{"level":"spine-switch","index":1}`.
+type TopologyLevelStatus struct {
+	// Level is the loosest topology key achieved by the scheduled pods of the PodGroup,
+	// empty if some of them are not placed within any of the topology levels.
+	Level string `json:"level"`
+	// Index is the index of Level in TopologyLevelsAnnotationKey, -1 if Level is empty.
+	Index int `json:"index"`
+}
+
+// GetTopologyLevelStatus parses the topology level status set in the PodGroup annotations,
+// nil is returned if the status is not set.
+func GetTopologyLevelStatus(annotations map[string]string) (*TopologyLevelStatus, error) {
+	value, ok := annotations[TopologyLevelStatusAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	status := &TopologyLevelStatus{}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, fmt.Errorf("failed to parse %v %q: %v", TopologyLevelStatusAnnotationKey, value, err)
+	}
+	return status, nil
+}
+
+// SetTopologyLevelStatus sets the topology level status in the PodGroup annotations.
+func SetTopologyLevelStatus(annotations map[string]string, status TopologyLevelStatus) {
+	value, _ := json.Marshal(status)
+	annotations[TopologyLevelStatusAnnotationKey] = string(value)
+}
+
+// GetAchievedTopologyLevel returns the tightest level in levels whose topology value is the same for all the given
+// node labels, empty string if there is no such level.
+func GetAchievedTopologyLevel(levels []string, nodeLabels []map[string]string) string {
+	if len(nodeLabels) == 0 {
+		return ""
+	}
+	for _, key := range levels {
+		value, ok := nodeLabels[0][key]
+		if !ok {
+			continue
+		}
+		shared := true
+		for _, labels := range nodeLabels[1:] {
+			if v, ok := labels[key]; !ok || v != value {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			return key
+		}
+	}
+	return ""
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package pod
 
 import (
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -59,3 +60,226 @@ func TestGetOwnerInfo(t *testing.T) {
 		t.Errorf("got owner error for p2")
 	}
 }
+
+func TestGetReservationIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{
+			name:     "index in annotation",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ReservationIndexAnnotation: "anno"}}},
+			expected: "anno",
+		},
+		{
+			name:     "index in label",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{ReservationIndexAnnotation: "label"}}},
+			expected: "label",
+		},
+		{
+			name: "annotation takes precedence over label",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{ReservationIndexAnnotation: "anno"},
+				Labels:      map[string]string{ReservationIndexAnnotation: "label"},
+			}},
+			expected: "anno",
+		},
+		{
+			name:     "no index",
+			pod:      &v1.Pod{},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetReservationIndex(tt.pod); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetReservationIdentity(t *testing.T) {
+	isController := true
+	stsRef := metav1.OwnerReference{Kind: StatefulSetKind, Name: "sts", Controller: &isController}
+	jobRef := metav1.OwnerReference{Kind: JobKind, Name: "job", Controller: &isController}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{
+			name:     "ordinal of statefulset pod",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-3", OwnerReferences: []metav1.OwnerReference{stsRef}}},
+			expected: "3",
+		},
+		{
+			name:     "statefulset pod with unexpected name",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-abc", OwnerReferences: []metav1.OwnerReference{stsRef}}},
+			expected: "",
+		},
+		{
+			name: "completion index of job pod",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "job-abcde",
+				Annotations:     map[string]string{JobCompletionIndexAnnotation: "2"},
+				OwnerReferences: []metav1.OwnerReference{jobRef},
+			}},
+			expected: "2",
+		},
+		{
+			name: "identity recorded on placeholder pod",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        "sts-3-placeholder",
+				Annotations: map[string]string{ReservationIdentityAnno: "3"},
+			}},
+			expected: "3",
+		},
+		{
+			name:     "pod without identity",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetReservationIdentity(tt.pod); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+
+	// identity of the original pod is kept on its placeholder pod.
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-3", Annotations: map[string]string{}, OwnerReferences: []metav1.OwnerReference{stsRef}}}
+	placeholder := CreateReservationFakePod(pod)
+	if !MatchReservationIdentity(pod, placeholder) {
+		t.Errorf("expected pod to match its placeholder")
+	}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sts-1", OwnerReferences: []metav1.OwnerReference{stsRef}}}
+	if MatchReservationIdentity(other, placeholder) {
+		t.Errorf("expected pod with different ordinal not to match the placeholder")
+	}
+	if !MatchReservationIdentity(&v1.Pod{}, placeholder) {
+		t.Errorf("expected pod without identity to match the placeholder")
+	}
+}
+
+func TestGetAchievedTopologyLevel(t *testing.T) {
+	levels := GetTopologyLevels(map[string]string{TopologyLevelsAnnotationKey: "leaf, spine,,superpod,leaf"})
+	if !reflect.DeepEqual([]string{"leaf", "spine", "superpod"}, levels) {
+		t.Fatalf("unexpected topology levels: %v", levels)
+	}
+
+	tests := []struct {
+		name       string
+		nodeLabels []map[string]string
+		expected   string
+	}{
+		{
+			name: "all nodes in one leaf",
+			nodeLabels: []map[string]string{
+				{"leaf": "l1", "spine": "s1", "superpod": "p1"},
+				{"leaf": "l1", "spine": "s1", "superpod": "p1"},
+			},
+			expected: "leaf",
+		},
+		{
+			name: "nodes across leaves in one spine",
+			nodeLabels: []map[string]string{
+				{"leaf": "l1", "spine": "s1", "superpod": "p1"},
+				{"leaf": "l2", "spine": "s1", "superpod": "p1"},
+			},
+			expected: "spine",
+		},
+		{
+			name: "node without leaf label",
+			nodeLabels: []map[string]string{
+				{"spine": "s1", "superpod": "p1"},
+				{"leaf": "l1", "spine": "s2", "superpod": "p1"},
+			},
+			expected: "superpod",
+		},
+		{
+			name: "nodes across superpods",
+			nodeLabels: []map[string]string{
+				{"leaf": "l1", "spine": "s1", "superpod": "p1"},
+				{"leaf": "l2", "spine": "s2", "superpod": "p2"},
+			},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetAchievedTopologyLevel(levels, tt.nodeLabels); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetReservationPlaceholder(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{
+			name: "index takes precedence over owner",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Annotations:     map[string]string{ReservationIndexAnnotation: "anno"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: StatefulSetKind, Name: "sts", Controller: &[]bool{true}[0]}},
+			}},
+			expected: "anno",
+		},
+		{
+			name: "statefulset owner",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{Kind: StatefulSetKind, Name: "sts", Controller: &[]bool{true}[0]}},
+			}},
+			expected: "sts",
+		},
+		{
+			name: "deployment takes precedence over pod group",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"name": "deploy"},
+				Annotations: map[string]string{PodGroupNameAnnotationKey: "pg"},
+			}},
+			expected: "deploy",
+		},
+		{
+			name:     "pod group",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PodGroupNameAnnotationKey: "pg"}}},
+			expected: "pg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetReservationPlaceholder(tt.pod); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTopologyLevelStatus(t *testing.T) {
+	annotations := map[string]string{}
+	if status, err := GetTopologyLevelStatus(annotations); status != nil || err != nil {
+		t.Fatalf("expected no status, got %v, %v", status, err)
+	}
+
+	SetTopologyLevelStatus(annotations, TopologyLevelStatus{Level: "spine", Index: 1})
+	if annotations[TopologyLevelStatusAnnotationKey] != `{"level":"spine","index":1}` {
+		t.Errorf("unexpected annotation value: %v", annotations[TopologyLevelStatusAnnotationKey])
+	}
+	status, err := GetTopologyLevelStatus(annotations)
+	if err != nil || !reflect.DeepEqual(&TopologyLevelStatus{Level: "spine", Index: 1}, status) {
+		t.Errorf("unexpected status: %v, %v", status, err)
+	}
+
+	annotations[TopologyLevelStatusAnnotationKey] = "spine"
+	if _, err := GetTopologyLevelStatus(annotations); err == nil {
+		t.Errorf("expected error for malformed status")
+	}
+}
@@ -22,4 +22,9 @@ const (
 	ResourceSriov v1.ResourceName = "bytedance.com/sriov.nic"
 	ResourceGPU   v1.ResourceName = "nvidia.com/gpu"
 	ResourceNuma  v1.ResourceName = "numa"
+
+	// ResourceGPUMemory and ResourceGPUCore are requested by pods sharing gpu devices.
+	// GPU memory is measured in bytes, and GPU core is measured in percentage of a single device (100 per device).
+	ResourceGPUMemory v1.ResourceName = "godel.bytedance.com/gpu-memory"
+	ResourceGPUCore   v1.ResourceName = "godel.bytedance.com/gpu-core"
 )
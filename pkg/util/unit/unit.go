@@ -17,6 +17,7 @@ limitations under the License.
 package unit
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
@@ -28,6 +29,44 @@ import (
 
 const (
 	PodGroupFinalOpLock = "godel.bytedance.com/podgroup-final-op-lock"
+
+	// PodGroupFailurePolicyAnnotationKey specifies what to do when the PodGroup fails after being scheduled.
+	PodGroupFailurePolicyAnnotationKey = "godel.bytedance.com/podgroup-failure-policy"
+	// PodGroupMaxRestartsAnnotationKey limits how many times the gang is restarted by the RestartGang failure policy,
+	// the value must be a non-negative integer. DefaultPodGroupMaxRestarts is used if it's not set.
+	PodGroupMaxRestartsAnnotationKey = "godel.bytedance.com/podgroup-max-restarts"
+	// PodGroupRestartCountAnnotationKey records how many times the gang has been restarted, it's maintained by the
+	// podgroup controller of the binder.
+	PodGroupRestartCountAnnotationKey = "godel.bytedance.com/podgroup-restart-count"
+
+	// PodGroupSchedulingDiagnosisAnnotationKey records why the PodGroup is pending, the value is the
+	// interpretabity.UnitDiagnosis in JSON format. It is only updated while the PodGroup is PreScheduling.
+	PodGroupSchedulingDiagnosisAnnotationKey = "godel.bytedance.com/podgroup-scheduling-diagnosis"
+
+	// The following annotations are set on the owners of pods (Job, JobSet, PyTorchJob...) to have their
+	// PodGroups created automatically by the podgroup controller.
+
+	// PodGroupAutoCreateAnnotationKey opts the owner in automatic PodGroup creation, the value must be "true".
+	PodGroupAutoCreateAnnotationKey = "godel.bytedance.com/podgroup-auto-create"
+	// PodGroupMinMemberAnnotationKey overrides the minMember inferred from the owner spec.
+	PodGroupMinMemberAnnotationKey = "godel.bytedance.com/podgroup-min-member"
+	// PodGroupPriorityClassAnnotationKey overrides the priority class inferred from the owner spec.
+	PodGroupPriorityClassAnnotationKey = "godel.bytedance.com/podgroup-priority-class"
+	// PodGroupScheduleTimeoutAnnotationKey specifies the scheduleTimeoutSeconds of the PodGroup.
+	PodGroupScheduleTimeoutAnnotationKey = "godel.bytedance.com/podgroup-schedule-timeout-seconds"
+)
+
+type PodGroupFailurePolicy string
+
+const (
+	// PodGroupFailurePolicyNone keeps the failed PodGroup as it is, it's the default policy.
+	PodGroupFailurePolicyNone PodGroupFailurePolicy = "None"
+	// PodGroupFailurePolicyRestartGang deletes all members of the failed PodGroup and moves it back to
+	// Pending, so that the owners recreate and reschedule the whole gang.
+	PodGroupFailurePolicyRestartGang PodGroupFailurePolicy = "RestartGang"
+
+	// DefaultPodGroupMaxRestarts is the number of restarts allowed by the RestartGang failure policy by default.
+	DefaultPodGroupMaxRestarts = 3
 )
 
 // TODO: move to util package
@@ -100,6 +139,54 @@ func PodGroupFinalOp(pg *v1alpha1.PodGroup) string {
 	return ""
 }
 
+// PodGroupFinalState returns true if the scheduling of the PodGroup has reached a final result.
 func PodGroupFinalState(p v1alpha1.PodGroupPhase) bool {
-	return p == v1alpha1.PodGroupScheduled || p == v1alpha1.PodGroupTimeout
+	switch p {
+	case v1alpha1.PodGroupScheduled, v1alpha1.PodGroupRunning, v1alpha1.PodGroupFinished, v1alpha1.PodGroupFailed, v1alpha1.PodGroupTimeout:
+		return true
+	}
+	return false
+}
+
+// PodGroupTerminalState returns true if the PodGroup won't change its phase any more.
+func PodGroupTerminalState(p v1alpha1.PodGroupPhase) bool {
+	return p == v1alpha1.PodGroupFinished || p == v1alpha1.PodGroupFailed || p == v1alpha1.PodGroupTimeout
+}
+
+func GetPodGroupFailurePolicy(pg *v1alpha1.PodGroup) PodGroupFailurePolicy {
+	if pg != nil && pg.Annotations != nil {
+		if policy := PodGroupFailurePolicy(pg.Annotations[PodGroupFailurePolicyAnnotationKey]); policy == PodGroupFailurePolicyRestartGang {
+			return policy
+		}
+	}
+	return PodGroupFailurePolicyNone
+}
+
+// GetPodGroupMaxRestarts returns the number of restarts allowed by the RestartGang failure policy.
+func GetPodGroupMaxRestarts(pg *v1alpha1.PodGroup) int {
+	if pg != nil && pg.Annotations != nil {
+		if val, ok := pg.Annotations[PodGroupMaxRestartsAnnotationKey]; ok {
+			if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+				return n
+			}
+		}
+	}
+	return DefaultPodGroupMaxRestarts
+}
+
+// GetPodGroupRestartCount returns how many times the gang has been restarted.
+func GetPodGroupRestartCount(pg *v1alpha1.PodGroup) int {
+	if pg != nil && pg.Annotations != nil {
+		if n, err := strconv.Atoi(pg.Annotations[PodGroupRestartCountAnnotationKey]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// PodGroupRestartable returns true if the failed PodGroup is going to be restarted by the RestartGang failure policy.
+func PodGroupRestartable(pg *v1alpha1.PodGroup) bool {
+	return pg != nil && pg.Status.Phase == v1alpha1.PodGroupFailed &&
+		GetPodGroupFailurePolicy(pg) == PodGroupFailurePolicyRestartGang &&
+		GetPodGroupRestartCount(pg) < GetPodGroupMaxRestarts(pg)
 }
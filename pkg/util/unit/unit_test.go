@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
 	"github.com/stretchr/testify/assert"
 
 	testinghelper "github.com/kubewharf/godel-scheduler/pkg/testing-helper"
@@ -63,3 +64,36 @@ func TestPodGroupFullName(t *testing.T) {
 	pod.SetAnnotations(annotations)
 	assert.Equal(t, fullName, GetPodGroupFullName(pod))
 }
+
+func TestPodGroupRestartable(t *testing.T) {
+	makePodGroup := func(phase v1alpha1.PodGroupPhase, annotations map[string]string) *v1alpha1.PodGroup {
+		pg := &v1alpha1.PodGroup{}
+		pg.Annotations = annotations
+		pg.Status.Phase = phase
+		return pg
+	}
+	restartGang := string(PodGroupFailurePolicyRestartGang)
+
+	assert.False(t, PodGroupRestartable(nil))
+	assert.False(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupFailed, nil)))
+	assert.False(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupRunning, map[string]string{
+		PodGroupFailurePolicyAnnotationKey: restartGang,
+	})))
+	assert.True(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupFailed, map[string]string{
+		PodGroupFailurePolicyAnnotationKey: restartGang,
+		PodGroupRestartCountAnnotationKey:  fmt.Sprint(DefaultPodGroupMaxRestarts - 1),
+	})))
+	assert.False(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupFailed, map[string]string{
+		PodGroupFailurePolicyAnnotationKey: restartGang,
+		PodGroupRestartCountAnnotationKey:  fmt.Sprint(DefaultPodGroupMaxRestarts),
+	})))
+	assert.False(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupFailed, map[string]string{
+		PodGroupFailurePolicyAnnotationKey: restartGang,
+		PodGroupMaxRestartsAnnotationKey:   "0",
+	})))
+	assert.True(t, PodGroupRestartable(makePodGroup(v1alpha1.PodGroupFailed, map[string]string{
+		PodGroupFailurePolicyAnnotationKey: restartGang,
+		PodGroupMaxRestartsAnnotationKey:   "10",
+		PodGroupRestartCountAnnotationKey:  "5",
+	})))
+}
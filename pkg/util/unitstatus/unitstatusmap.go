@@ -47,6 +47,15 @@ func (u *UnitStatusMap) GetUnitSchedulingStatus(unitKey string) SchedulingStatus
 	return u.schedulingStatus.get(unitKey)
 }
 
+// GetUnitSchedulingStatuses returns a copy of the scheduling statuses of all units.
+func (u *UnitStatusMap) GetUnitSchedulingStatuses() map[string]SchedulingStatus {
+	statuses := make(map[string]SchedulingStatus, len(u.schedulingStatus))
+	for unitKey, status := range u.schedulingStatus {
+		statuses[unitKey] = status
+	}
+	return statuses
+}
+
 func (u *UnitStatusMap) DeleteUnitSchedulingStatus(unitKey string) {
 	u.schedulingStatus.delete(unitKey)
 }
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EphemeralVolumeClaimName returns the name of the PVC that the ephemeral-volume controller creates for
+// a generic ephemeral volume of the pod. The name is deterministic, so it's known before the PVC exists.
+func EphemeralVolumeClaimName(pod *v1.Pod, volume *v1.Volume) string {
+	return pod.Name + "-" + volume.Name
+}
+
+// EphemeralVolumeIsForPod checks that the PVC is the one created for a generic ephemeral volume of the pod.
+// A PVC with the same name that isn't owned by the pod must not be used, since it may hold data of someone else.
+func EphemeralVolumeIsForPod(pod *v1.Pod, pvc *v1.PersistentVolumeClaim) error {
+	if !metav1.IsControlledBy(pvc, pod) {
+		return fmt.Errorf("PVC %s/%s was not created for pod %s/%s (pod is not owner)", pvc.Namespace, pvc.Name, pod.Namespace, pod.Name)
+	}
+	return nil
+}
+
+// PodVolumeClaimName returns the name of the PVC used by the volume of the pod, which is either a
+// PersistentVolumeClaim volume or a generic ephemeral volume. It returns an empty name for the other volumes.
+func PodVolumeClaimName(pod *v1.Pod, volume *v1.Volume) (pvcName string, isEphemeral bool) {
+	switch {
+	case volume.PersistentVolumeClaim != nil:
+		return volume.PersistentVolumeClaim.ClaimName, false
+	case volume.Ephemeral != nil:
+		return EphemeralVolumeClaimName(pod, volume), true
+	default:
+		return "", false
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+
+	pvutil "github.com/kubewharf/godel-scheduler/pkg/volume/persistentvolume/util"
 )
 
 // AssumeCache is a cache on top of the informer that allows for updating
@@ -46,6 +48,9 @@ type AssumeCache interface {
 
 	// List all the objects in the cache
 	List(indexObj interface{}) []interface{}
+
+	// ListIndexed lists the objects having any index value, grouped by the index values
+	ListIndexed() map[string][]interface{}
 }
 
 type errWrongType struct {
@@ -290,6 +295,29 @@ func (c *assumeCache) List(indexObj interface{}) []interface{} {
 	return allObjs
 }
 
+func (c *assumeCache) ListIndexed() map[string][]interface{} {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	indexedObjs := map[string][]interface{}{}
+	for _, indexedValue := range c.store.ListIndexFuncValues(c.indexName) {
+		objs, err := c.store.ByIndex(c.indexName, indexedValue)
+		if err != nil {
+			klog.InfoS("Failed to list index", "err", err)
+			return nil
+		}
+		for _, obj := range objs {
+			objInfo, ok := obj.(*objInfo)
+			if !ok {
+				klog.InfoS("Failed to list object", "err", &errWrongType{"objInfo", obj})
+				continue
+			}
+			indexedObjs[indexedValue] = append(indexedObjs[indexedValue], objInfo.latestObj)
+		}
+	}
+	return indexedObjs
+}
+
 func (c *assumeCache) Assume(obj interface{}) error {
 	name, err := cache.MetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -299,7 +327,7 @@ func (c *assumeCache) Assume(obj interface{}) error {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
 
-	objInfo, err := c.getObjInfo(name)
+	stored, err := c.getObjInfo(name)
 	if err != nil {
 		return err
 	}
@@ -309,7 +337,7 @@ func (c *assumeCache) Assume(obj interface{}) error {
 		return err
 	}
 
-	storedVersion, err := c.getObjVersion(name, objInfo.latestObj)
+	storedVersion, err := c.getObjVersion(name, stored.latestObj)
 	if err != nil {
 		return err
 	}
@@ -318,8 +346,10 @@ func (c *assumeCache) Assume(obj interface{}) error {
 		return fmt.Errorf("%v %q is out of sync (stored: %d, assume: %d)", c.description, name, storedVersion, newVersion)
 	}
 
-	// Only update the cached object
-	objInfo.latestObj = obj
+	// Only update the cached object, a new objInfo is stored so that the object is re-indexed.
+	if err := c.store.Update(&objInfo{name: name, latestObj: obj, apiObj: stored.apiObj}); err != nil {
+		return err
+	}
 	klog.V(4).InfoS("Assumed object", "entryDescription", c.description, "objectName", name, "objectVersion", newVersion)
 	return nil
 }
@@ -328,12 +358,15 @@ func (c *assumeCache) Restore(objName string) {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
 
-	objInfo, err := c.getObjInfo(objName)
+	stored, err := c.getObjInfo(objName)
 	if err != nil {
 		// This could be expected if object got deleted
 		klog.InfoS("Failed to restore object", "entryDescription", c.description, "objectName", objName, "err", err)
 	} else {
-		objInfo.latestObj = objInfo.apiObj
+		if err := c.store.Update(&objInfo{name: objName, latestObj: stored.apiObj, apiObj: stored.apiObj}); err != nil {
+			klog.InfoS("Failed to restore object", "entryDescription", c.description, "objectName", objName, "err", err)
+			return
+		}
 		klog.V(4).InfoS("Restored object", "entryDescription", c.description, "objectName", objName)
 	}
 }
@@ -415,15 +448,29 @@ type PVCAssumeCache interface {
 	// pvcKey is the result of MetaNamespaceKeyFunc on PVC obj
 	GetPVC(pvcKey string) (*v1.PersistentVolumeClaim, error)
 	GetAPIPVC(pvcKey string) (*v1.PersistentVolumeClaim, error)
+	// ListSelectedNodePVCs returns the unbound PVCs with the selected node, grouped by the selected node.
+	ListSelectedNodePVCs() map[string][]*v1.PersistentVolumeClaim
 }
 
 type pvcAssumeCache struct {
 	AssumeCache
 }
 
+// pvcSelectedNodeIndexFunc indexes the unbound PVCs by their selected nodes, other PVCs aren't indexed.
+func pvcSelectedNodeIndexFunc(obj interface{}) ([]string, error) {
+	pvc, ok := obj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return nil, fmt.Errorf("object is not a v1.PersistentVolumeClaim: %v", obj)
+	}
+	if nodeName := pvc.Annotations[pvutil.AnnSelectedNode]; len(nodeName) > 0 && len(pvc.Spec.VolumeName) == 0 {
+		return []string{nodeName}, nil
+	}
+	return nil, nil
+}
+
 // NewPVCAssumeCache creates a PVC assume cache.
 func NewPVCAssumeCache(informer cache.SharedIndexInformer) PVCAssumeCache {
-	return &pvcAssumeCache{NewAssumeCache(informer, "v1.PersistentVolumeClaim", "namespace", cache.MetaNamespaceIndexFunc)}
+	return &pvcAssumeCache{NewAssumeCache(informer, "v1.PersistentVolumeClaim", "selectednode", pvcSelectedNodeIndexFunc)}
 }
 
 func (c *pvcAssumeCache) GetPVC(pvcKey string) (*v1.PersistentVolumeClaim, error) {
@@ -450,3 +497,18 @@ func (c *pvcAssumeCache) GetAPIPVC(pvcKey string) (*v1.PersistentVolumeClaim, er
 	}
 	return pvc, nil
 }
+
+func (c *pvcAssumeCache) ListSelectedNodePVCs() map[string][]*v1.PersistentVolumeClaim {
+	pvcs := map[string][]*v1.PersistentVolumeClaim{}
+	for nodeName, objs := range c.ListIndexed() {
+		for _, obj := range objs {
+			pvc, ok := obj.(*v1.PersistentVolumeClaim)
+			if !ok {
+				klog.InfoS("Failed to list PVCs", "err", &errWrongType{"v1.PersistentVolumeClaim", obj})
+				continue
+			}
+			pvcs[nodeName] = append(pvcs[nodeName], pvc)
+		}
+	}
+	return pvcs
+}
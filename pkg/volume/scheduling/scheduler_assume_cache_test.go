@@ -18,6 +18,7 @@ package scheduling
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -472,3 +473,46 @@ func TestAssumeUpdatePVCCache(t *testing.T) {
 		t.Fatalf("failed to get PVC after old PVC added: %v", err)
 	}
 }
+
+func TestListSelectedNodePVCs(t *testing.T) {
+	cache := NewPVCAssumeCache(nil)
+	internalCache, ok := cache.(*pvcAssumeCache).AssumeCache.(*assumeCache)
+	if !ok {
+		t.Fatalf("Failed to get internal cache")
+	}
+
+	verify := func(expected map[string][]string) {
+		t.Helper()
+		got := map[string][]string{}
+		for nodeName, pvcs := range cache.ListSelectedNodePVCs() {
+			for _, pvc := range pvcs {
+				got[nodeName] = append(got[nodeName], pvc.Name)
+			}
+		}
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("expected selected node PVCs %v, but got %v", expected, got)
+		}
+	}
+
+	selectedPVC := makeClaim("pvc-selected", "1", "ns1")
+	selectedPVC.Annotations[pvutil.AnnSelectedNode] = "node1"
+	boundPVC := makeClaim("pvc-bound", "1", "ns1")
+	boundPVC.Annotations[pvutil.AnnSelectedNode] = "node1"
+	boundPVC.Spec.VolumeName = "pv1"
+	pvc := makeClaim("pvc", "1", "ns1")
+	internalCache.add(selectedPVC)
+	internalCache.add(boundPVC)
+	internalCache.add(pvc)
+	verify(map[string][]string{"node1": {"pvc-selected"}})
+
+	// The assumed PVC is re-indexed by its selected node.
+	assumedPVC := pvc.DeepCopy()
+	assumedPVC.Annotations[pvutil.AnnSelectedNode] = "node2"
+	if err := cache.Assume(assumedPVC); err != nil {
+		t.Fatalf("Assume() returned error %v", err)
+	}
+	verify(map[string][]string{"node1": {"pvc-selected"}, "node2": {"pvc"}})
+
+	cache.Restore(getPVCName(pvc))
+	verify(map[string][]string{"node1": {"pvc-selected"}})
+}
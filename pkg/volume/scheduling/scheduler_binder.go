@@ -65,6 +65,8 @@ const (
 	ErrReasonNodeConflict ConflictReason = "node(s) had volume node affinity conflict"
 	// ErrUnboundImmediatePVC is used when the pod has an unbound PVC in immedate binding mode.
 	ErrUnboundImmediatePVC ConflictReason = "pod has unbound immediate PersistentVolumeClaims"
+	// ErrReasonNotEnoughSpace is used when a pod cannot start on a node because not enough storage space is available.
+	ErrReasonNotEnoughSpace ConflictReason = "node(s) did not have enough free storage"
 )
 
 // InTreeToCSITranslator contains methods required to check migratable status
@@ -100,16 +102,7 @@ type InTreeToCSITranslator interface {
 //  2. Once all the assume operations are done in d), the scheduler processes the next Pod in the scheduler queue
 //     while the actual binding operation occurs in the background.
 type GodelVolumeBinder interface {
-	// FindPodVolumes checks if all of a Pod's PVCs can be satisfied by the node.
-	//
-	// If a PVC is bound, it checks if the PV's NodeAffinity matches the Node.
-	// Otherwise, it tries to find an available PV to bind to the PVC.
-	//
-	// It returns an error when something went wrong or a list of reasons why the node is
-	// (currently) not usable for the pod.
-	//
-	// This function is called by the volume binding scheduler predicate and can be called in parallel
-	FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string) (reasons ConflictReasons, err error)
+	BaseVolumeBinder
 
 	// AssumePodVolumes will:
 	// 1. Take the PV matches for unbound PVCs and update the PV cache assuming
@@ -149,14 +142,24 @@ type BaseVolumeBinder interface {
 	// It returns an error when something went wrong or a list of reasons why the node is
 	// (currently) not usable for the pod.
 	//
+	// Unbound PVCs to provision are also checked against the storage capacity of the node if it's tracked
+	// by their CSI drivers, with the storage requested by pendingClaims reserved.
+	//
 	// This function is called by the volume binding scheduler predicate and can be called in parallel
-	FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string) (reasons ConflictReasons, err error)
+	FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (reasons ConflictReasons, err error)
+
+	// GetPendingClaims returns the PVCs waiting to be provisioned, including the unbound PVCs of placedPods,
+	// whose storage should be reserved while checking the pod.
+	GetPendingClaims(pod *v1.Pod, placedPods []*PlacedPod) (*PendingClaims, error)
+
+	// ScorePodVolumes returns the fraction of the free storage capacity left on the node after the
+	// unbound PVCs of the pod are provisioned there.
+	ScorePodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (float64, error)
 }
 
 type volumeBinder struct {
 	*baseVolumeBinder
-	kubeClient   clientset.Interface
-	nodeInformer coreinformers.NodeInformer
+	kubeClient clientset.Interface
 	// Stores binding decisions that were made in FindPodVolumes for use in AssumePodVolumes.
 	// AssumePodVolumes modifies the bindings again for use in BindPodVolumes.
 	podBindingCache PodBindingCache
@@ -165,39 +168,56 @@ type volumeBinder struct {
 }
 
 type baseVolumeBinder struct {
+	nodeInformer    coreinformers.NodeInformer
 	classLister     storagelisters.StorageClassLister
 	csiNodeInformer storageinformers.CSINodeInformer
 	pvcCache        PVCAssumeCache
 	pvCache         PVAssumeCache
 	translator      InTreeToCSITranslator
+
+	// csiDriverLister and csiStorageCapacityLister are nil if storage capacity isn't checked.
+	csiDriverLister          storagelisters.CSIDriverLister
+	csiStorageCapacityLister storagelisters.CSIStorageCapacityLister
 }
 
-func newBaseVolumeBinder(csiNodeInformer storageinformers.CSINodeInformer,
+func newBaseVolumeBinder(
+	nodeInformer coreinformers.NodeInformer,
+	csiNodeInformer storageinformers.CSINodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 ) *baseVolumeBinder {
 	b := &baseVolumeBinder{
+		nodeInformer:    nodeInformer,
 		classLister:     storageClassInformer.Lister(),
 		csiNodeInformer: csiNodeInformer,
 		pvcCache:        NewPVCAssumeCache(pvcInformer.Informer()),
 		pvCache:         NewPVAssumeCache(pvInformer.Informer()),
 		translator:      csitrans.New(),
 	}
+	if capacityCheck != nil {
+		b.csiDriverLister = capacityCheck.CSIDriverInformer.Lister()
+		b.csiStorageCapacityLister = capacityCheck.CSIStorageCapacityInformer.Lister()
+	}
 	return b
 }
 
+// NewBaseVolumeBinder sets up all the caches needed for the scheduler to check volumes. Storage capacity
+// is only checked if capacityCheck is not nil.
 func NewBaseVolumeBinder(
+	nodeInformer coreinformers.NodeInformer,
 	csiNodeInformer storageinformers.CSINodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 ) BaseVolumeBinder {
-	b := newBaseVolumeBinder(csiNodeInformer, pvcInformer, pvInformer, storageClassInformer)
+	b := newBaseVolumeBinder(nodeInformer, csiNodeInformer, pvcInformer, pvInformer, storageClassInformer, capacityCheck)
 	return b
 }
 
-func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string) (reasons ConflictReasons, err error) {
+func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (reasons ConflictReasons, err error) {
 	// Warning: Below log needs high verbosity as it can be printed several times (#60933).
 	klog.V(5).InfoS("Entered FindPodVolumes in baseVolumeBinder", "pod", klog.KObj(pod), "nodeName", nodeName)
 
@@ -206,6 +226,7 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 	// returns without an error.
 	unboundVolumesSatisfied := true
 	boundVolumesSatisfied := true
+	capacitySatisfied := true
 	defer func() {
 		if err != nil {
 			return
@@ -216,6 +237,9 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 		if !unboundVolumesSatisfied {
 			reasons = append(reasons, ErrReasonBindConflict)
 		}
+		if !capacitySatisfied {
+			reasons = append(reasons, ErrReasonNotEnoughSpace)
+		}
 	}()
 
 	start := time.Now()
@@ -229,14 +253,17 @@ func (b *baseVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 	podVolumes := podVolumesInfo{
 		boundVolumesSatisfied:   boundVolumesSatisfied,
 		unboundVolumesSatisfied: unboundVolumesSatisfied,
+		capacitySatisfied:       capacitySatisfied,
 	}
-	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, podVolumes)
+	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, pendingClaims, podVolumes)
 	boundVolumesSatisfied = podVolumes.boundVolumesSatisfied
 	unboundVolumesSatisfied = podVolumes.unboundVolumesSatisfied
+	capacitySatisfied = podVolumes.capacitySatisfied
 	return
 }
 
 // NewVolumeBinder sets up all the caches needed for the scheduler to make volume binding decisions.
+// Storage capacity is only checked if capacityCheck is not nil.
 func NewVolumeBinder(
 	kubeClient clientset.Interface,
 	nodeInformer coreinformers.NodeInformer,
@@ -244,12 +271,12 @@ func NewVolumeBinder(
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
+	capacityCheck *CapacityCheck,
 	bindTimeout time.Duration,
 ) GodelVolumeBinder {
 	b := &volumeBinder{
-		baseVolumeBinder: newBaseVolumeBinder(csiNodeInformer, pvcInformer, pvInformer, storageClassInformer),
+		baseVolumeBinder: newBaseVolumeBinder(nodeInformer, csiNodeInformer, pvcInformer, pvInformer, storageClassInformer, capacityCheck),
 		kubeClient:       kubeClient,
-		nodeInformer:     nodeInformer,
 		podBindingCache:  NewPodBindingCache(),
 		bindTimeout:      bindTimeout,
 	}
@@ -272,7 +299,7 @@ func (b *volumeBinder) DeletePodBindings(pod *v1.Pod) {
 // FindPodVolumes caches the matching PVs and PVCs to provision per node in podBindingCache.
 // This method intentionally takes in a *v1.Node object instead of using volumebinder.nodeInformer.
 // That's necessary because some operations will need to pass in to the predicate fake node objects.
-func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string) (reasons ConflictReasons, err error) {
+func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (reasons ConflictReasons, err error) {
 	// Warning: Below log needs high verbosity as it can be printed several times (#60933).
 	klog.V(5).InfoS("Entered FindPodVolumes in volumeBinder", "pod", klog.KObj(pod), "nodeName", nodeName)
 
@@ -281,6 +308,7 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 	// returns without an error.
 	unboundVolumesSatisfied := true
 	boundVolumesSatisfied := true
+	capacitySatisfied := true
 	defer func() {
 		if err != nil {
 			return
@@ -291,6 +319,9 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 		if !unboundVolumesSatisfied {
 			reasons = append(reasons, ErrReasonBindConflict)
 		}
+		if !capacitySatisfied {
+			reasons = append(reasons, ErrReasonNotEnoughSpace)
+		}
 	}()
 
 	start := time.Now()
@@ -327,12 +358,14 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels m
 	podVolumes := podVolumesInfo{
 		boundVolumesSatisfied:   boundVolumesSatisfied,
 		unboundVolumesSatisfied: unboundVolumesSatisfied,
+		capacitySatisfied:       capacitySatisfied,
 		matchedBindings:         matchedBindings,
 		provisionedClaims:       provisionedClaims,
 	}
-	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, podVolumes)
+	podVolumes, reasons, err = b.findPodVolumes(pod, nodeName, nodeLabels, pendingClaims, podVolumes)
 	boundVolumesSatisfied = podVolumes.boundVolumesSatisfied
 	unboundVolumesSatisfied = podVolumes.unboundVolumesSatisfied
+	capacitySatisfied = podVolumes.capacitySatisfied
 	matchedBindings = podVolumes.matchedBindings
 	provisionedClaims = podVolumes.provisionedClaims
 	return
@@ -659,13 +692,21 @@ func (b *volumeBinder) checkBindings(pod *v1.Pod, bindings []*bindingInfo, claim
 	return true, nil
 }
 
-func (b *baseVolumeBinder) isVolumeBound(namespace string, vol *v1.Volume) (bool, *v1.PersistentVolumeClaim, error) {
-	if vol.PersistentVolumeClaim == nil {
+func (b *baseVolumeBinder) isVolumeBound(pod *v1.Pod, vol *v1.Volume) (bool, *v1.PersistentVolumeClaim, error) {
+	// Generic ephemeral volumes also use a PVC, just with a computed name.
+	pvcName, isEphemeral := volumeutil.PodVolumeClaimName(pod, vol)
+	if pvcName == "" {
 		return true, nil, nil
 	}
 
-	pvcName := vol.PersistentVolumeClaim.ClaimName
-	return b.isPVCBound(namespace, pvcName)
+	bound, pvc, err := b.isPVCBound(pod.Namespace, pvcName)
+	// The PVC of an ephemeral volume must be owned by the pod.
+	if isEphemeral && err == nil && pvc != nil {
+		if err := volumeutil.EphemeralVolumeIsForPod(pod, pvc); err != nil {
+			return false, nil, err
+		}
+	}
+	return bound, pvc, err
 }
 
 func (b *baseVolumeBinder) isPVCBound(namespace, pvcName string) (bool, *v1.PersistentVolumeClaim, error) {
@@ -701,7 +742,7 @@ func (b *baseVolumeBinder) isPVCFullyBound(pvc *v1.PersistentVolumeClaim) bool {
 // arePodVolumesBound returns true if all volumes are fully bound
 func (b *volumeBinder) arePodVolumesBound(pod *v1.Pod) bool {
 	for _, vol := range pod.Spec.Volumes {
-		if isBound, _, _ := b.isVolumeBound(pod.Namespace, &vol); !isBound {
+		if isBound, _, _ := b.isVolumeBound(pod, &vol); !isBound {
 			// Pod has at least one PVC that needs binding
 			return false
 		}
@@ -717,7 +758,7 @@ func (b *baseVolumeBinder) getPodVolumes(pod *v1.Pod) (boundClaims []*v1.Persist
 	unboundClaimsDelayBinding = []*v1.PersistentVolumeClaim{}
 
 	for _, vol := range pod.Spec.Volumes {
-		volumeBound, pvc, err := b.isVolumeBound(pod.Namespace, &vol)
+		volumeBound, pvc, err := b.isVolumeBound(pod, &vol)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -845,11 +886,7 @@ func (b *baseVolumeBinder) checkVolumeProvisions(pod *v1.Pod, claimsToProvision
 			return false, nil, nil
 		}
 
-		// TODO: Check if capacity of the node domain in the storage class
-		// can satisfy resource requirement of given claim
-
 		provisionedClaims = append(provisionedClaims, claim)
-
 	}
 	klog.V(4).InfoS("Provisioning for claims of pod that has no matching volumes on node", "pod", klog.KObj(pod), "nodeName", nodeName)
 
@@ -969,11 +1006,12 @@ func (b *baseVolumeBinder) tryTranslatePVToCSI(pv *v1.PersistentVolume, csiNode
 type podVolumesInfo struct {
 	boundVolumesSatisfied   bool
 	unboundVolumesSatisfied bool
+	capacitySatisfied       bool
 	matchedBindings         []*bindingInfo
 	provisionedClaims       []*v1.PersistentVolumeClaim
 }
 
-func (b *baseVolumeBinder) findPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, podVolumes podVolumesInfo) (podVolumesInfo, ConflictReasons, error) {
+func (b *baseVolumeBinder) findPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims, podVolumes podVolumesInfo) (podVolumesInfo, ConflictReasons, error) {
 	podName := getPodName(pod)
 	// The pod's volumes need to be processed in one call to avoid the race condition where
 	// volumes can get bound/provisioned in between calls.
@@ -1032,6 +1070,14 @@ func (b *baseVolumeBinder) findPodVolumes(pod *v1.Pod, nodeName string, nodeLabe
 			if err != nil {
 				return podVolumes, nil, err
 			}
+			// Check if capacity of the node domain in the storage class
+			// can satisfy resource requirement of given claims
+			if podVolumes.unboundVolumesSatisfied {
+				podVolumes.capacitySatisfied, err = b.checkVolumeCapacity(pod, podVolumes.provisionedClaims, nodeName, nodeLabels, pendingClaims)
+				if err != nil {
+					return podVolumes, nil, err
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util/features"
+	"github.com/kubewharf/godel-scheduler/pkg/util/helper"
+	volumeutil "github.com/kubewharf/godel-scheduler/pkg/util/volume/util"
+	pvutil "github.com/kubewharf/godel-scheduler/pkg/volume/persistentvolume/util"
+)
+
+// CapacityCheck contains additional parameters for NewVolumeBinder and NewBaseVolumeBinder that
+// are only needed when checking volume sizes against available storage capacity is desired.
+type CapacityCheck struct {
+	CSIDriverInformer          storageinformers.CSIDriverInformer
+	CSIStorageCapacityInformer storageinformers.CSIStorageCapacityInformer
+}
+
+// NewCapacityCheck returns the CapacityCheck with informers from the factory, or nil if the
+// CSIStorageCapacity feature is disabled.
+func NewCapacityCheck(informerFactory informers.SharedInformerFactory) *CapacityCheck {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.CSIStorageCapacity) {
+		return nil
+	}
+	return &CapacityCheck{
+		CSIDriverInformer:          informerFactory.Storage().V1().CSIDrivers(),
+		CSIStorageCapacityInformer: informerFactory.Storage().V1().CSIStorageCapacities(),
+	}
+}
+
+// PlacedPod is a pod that has been placed on a node, but whose volumes haven't been provisioned yet,
+// e.g. another member of the same gang placed in the current scheduling cycle.
+type PlacedPod struct {
+	Pod        *v1.Pod
+	NodeLabels map[string]string
+}
+
+// PendingClaims are the claims waiting to be provisioned in the topology segments of their nodes.
+// CSIStorageCapacity isn't updated until the volumes are provisioned, so the storage they request
+// is reserved from the capacity of those segments.
+type PendingClaims struct {
+	// claims are indexed by claim key.
+	claims map[string]*pendingClaim
+	// capacities are the CSIStorageCapacities indexed by storage class. They're listed once when the
+	// PendingClaims are built, instead of for every node and storage class in Filter and Score.
+	capacities map[string][]*capacitySegment
+}
+
+// capacitySegment is a CSIStorageCapacity with the selector of its node topology.
+type capacitySegment struct {
+	capacity *storagev1.CSIStorageCapacity
+	selector labels.Selector
+}
+
+type pendingClaim struct {
+	className  string
+	size       int64
+	nodeLabels labels.Set
+}
+
+// reserved returns the storage requested by the pending claims in the topology segment of the capacity.
+func (p *PendingClaims) reserved(capacity *storagev1.CSIStorageCapacity, selector labels.Selector, excluded map[string]bool) int64 {
+	if p == nil {
+		return 0
+	}
+	var reserved int64
+	for key, claim := range p.claims {
+		if excluded[key] || claim.className != capacity.StorageClassName {
+			continue
+		}
+		if selector.Matches(claim.nodeLabels) {
+			reserved += claim.size
+		}
+	}
+	return reserved
+}
+
+// GetPendingClaims returns the claims waiting to be provisioned, which are the claims with the selected
+// node but not bound yet, and the unbound claims with delayed binding of the placed pods. The claims of
+// the pod itself are excluded.
+func (b *baseVolumeBinder) GetPendingClaims(pod *v1.Pod, placedPods []*PlacedPod) (*PendingClaims, error) {
+	pending := &PendingClaims{claims: map[string]*pendingClaim{}}
+	if b.csiStorageCapacityLister == nil {
+		return pending, nil
+	}
+	capacities, err := b.listCapacities()
+	if err != nil {
+		return nil, err
+	}
+	pending.capacities = capacities
+
+	for nodeName, pvcs := range b.pvcCache.ListSelectedNodePVCs() {
+		node, err := b.nodeInformer.Lister().Get(nodeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, pvc := range pvcs {
+			pending.add(pvc, node.Labels)
+		}
+	}
+
+	for _, placedPod := range placedPods {
+		if placedPod.Pod.UID == pod.UID {
+			continue
+		}
+		_, claimsToBind, _, err := b.getPodVolumes(placedPod.Pod)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claimsToBind {
+			// Claims with the selected node have been added above.
+			if _, ok := claim.Annotations[pvutil.AnnSelectedNode]; !ok {
+				pending.add(claim, placedPod.NodeLabels)
+			}
+		}
+	}
+
+	for i := range pod.Spec.Volumes {
+		if pvcName, _ := volumeutil.PodVolumeClaimName(pod, &pod.Spec.Volumes[i]); pvcName != "" {
+			delete(pending.claims, pod.Namespace+"/"+pvcName)
+		}
+	}
+	return pending, nil
+}
+
+func (p *PendingClaims) add(claim *v1.PersistentVolumeClaim, nodeLabels map[string]string) {
+	quantity, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok || quantity.Value() == 0 {
+		return
+	}
+	p.claims[getPVCName(claim)] = &pendingClaim{
+		className:  helper.GetPersistentVolumeClaimClass(claim),
+		size:       quantity.Value(),
+		nodeLabels: labels.Set(nodeLabels),
+	}
+}
+
+// classCapacityRequest is the storage requested by the claims of a pod to provision in a storage class.
+type classCapacityRequest struct {
+	class *storagev1.StorageClass
+	// total is the sum of the sizes of the claims, which must fit into the capacity of one topology segment.
+	total int64
+	// max is the size of the largest claim, which must not exceed the maximum volume size.
+	max    int64
+	claims map[string]bool
+}
+
+// getCapacityRequests groups the claims to provision by storage class, skipping the classes whose CSI
+// driver doesn't publish storage capacity.
+func (b *baseVolumeBinder) getCapacityRequests(claimsToProvision []*v1.PersistentVolumeClaim) ([]*classCapacityRequest, error) {
+	if b.csiStorageCapacityLister == nil {
+		return nil, nil
+	}
+
+	var requests []*classCapacityRequest
+	requestOfClass := map[string]*classCapacityRequest{}
+	for _, claim := range claimsToProvision {
+		quantity, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]
+		if !ok || quantity.Value() == 0 {
+			continue
+		}
+		className := helper.GetPersistentVolumeClaimClass(claim)
+		request, ok := requestOfClass[className]
+		if !ok {
+			class, err := b.classLister.Get(className)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find storage class %q", className)
+			}
+			tracked, err := b.isCapacityTracked(class.Provisioner)
+			if err != nil {
+				return nil, err
+			}
+			if !tracked {
+				requestOfClass[className] = nil
+				continue
+			}
+			request = &classCapacityRequest{class: class, claims: map[string]bool{}}
+			requestOfClass[className] = request
+			requests = append(requests, request)
+		}
+		if request == nil {
+			continue
+		}
+		size := quantity.Value()
+		request.total += size
+		if size > request.max {
+			request.max = size
+		}
+		request.claims[getPVCName(claim)] = true
+	}
+	return requests, nil
+}
+
+// isCapacityTracked returns true if the CSI driver of the provisioner publishes storage capacity.
+func (b *baseVolumeBinder) isCapacityTracked(provisioner string) (bool, error) {
+	driver, err := b.csiDriverLister.Get(provisioner)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Either the provisioner is not a CSI driver or the driver does not
+			// opt into storage capacity scheduling. Either way, skip
+			// capacity checking.
+			return false, nil
+		}
+		return false, err
+	}
+	return driver.Spec.StorageCapacity != nil && *driver.Spec.StorageCapacity, nil
+}
+
+// listCapacities returns the CSIStorageCapacities with capacity and node topology, indexed by storage class.
+func (b *baseVolumeBinder) listCapacities() (map[string][]*capacitySegment, error) {
+	capacities, err := b.csiStorageCapacityLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	segments := make(map[string][]*capacitySegment)
+	for _, capacity := range capacities {
+		if capacity.Capacity == nil || capacity.NodeTopology == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(capacity.NodeTopology)
+		if err != nil {
+			klog.InfoS("Failed to parse node topology of CSIStorageCapacity", "capacity", klog.KObj(capacity), "err", err)
+			continue
+		}
+		segments[capacity.StorageClassName] = append(segments[capacity.StorageClassName], &capacitySegment{capacity: capacity, selector: selector})
+	}
+	return segments, nil
+}
+
+// getAvailableCapacity returns the largest storage available for the request in the topology segments
+// of the node, after the pending claims are reserved. It returns -1 if no segment of the node could hold
+// the volumes.
+func (b *baseVolumeBinder) getAvailableCapacity(request *classCapacityRequest, nodeLabels map[string]string, pendingClaims *PendingClaims) (int64, error) {
+	var capacities map[string][]*capacitySegment
+	if pendingClaims != nil {
+		capacities = pendingClaims.capacities
+	} else {
+		var err error
+		if capacities, err = b.listCapacities(); err != nil {
+			return -1, err
+		}
+	}
+
+	var available int64 = -1
+	for _, segment := range capacities[request.class.Name] {
+		capacity := segment.capacity
+		if capacity.MaximumVolumeSize != nil && capacity.MaximumVolumeSize.Value() < request.max {
+			continue
+		}
+		if !segment.selector.Matches(labels.Set(nodeLabels)) {
+			continue
+		}
+		if free := capacity.Capacity.Value() - pendingClaims.reserved(capacity, segment.selector, request.claims); free > available {
+			available = free
+		}
+	}
+	return available, nil
+}
+
+// checkVolumeCapacity checks given claims to provision against the storage capacity of the node. The claims
+// of the same storage class must fit into one topology segment of the node together with the pending claims.
+func (b *baseVolumeBinder) checkVolumeCapacity(pod *v1.Pod, claimsToProvision []*v1.PersistentVolumeClaim, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (bool, error) {
+	requests, err := b.getCapacityRequests(claimsToProvision)
+	if err != nil {
+		return false, err
+	}
+	for _, request := range requests {
+		available, err := b.getAvailableCapacity(request, nodeLabels, pendingClaims)
+		if err != nil {
+			return false, err
+		}
+		if available < request.total {
+			klog.V(4).InfoS("Node has no topology segment with enough storage capacity for claims of pod", "pod", klog.KObj(pod), "nodeName", nodeName, "storageClass", klog.KObj(request.class), "requested", request.total, "available", available)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ScorePodVolumes returns the fraction of the free storage in the topology segment of the node that is
+// left after the claims of the pod to provision are placed there, averaged over the storage classes whose
+// capacity is tracked. It's in [0, 1], and the nodes with more storage left get higher scores so that volumes are
+// spread across segments. It returns 0 if none of the claims of the pod is capacity tracked.
+func (b *baseVolumeBinder) ScorePodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (float64, error) {
+	_, claimsToBind, _, err := b.getPodVolumes(pod)
+	if err != nil || len(claimsToBind) == 0 {
+		return 0, err
+	}
+	requests, err := b.getCapacityRequests(claimsToBind)
+	if err != nil || len(requests) == 0 {
+		return 0, err
+	}
+
+	var score float64
+	for _, request := range requests {
+		available, err := b.getAvailableCapacity(request, nodeLabels, pendingClaims)
+		if err != nil {
+			return 0, err
+		}
+		if available >= request.total && available > 0 {
+			score += float64(available-request.total) / float64(available)
+		}
+	}
+	return score / float64(len(requests)), nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+)
+
+const (
+	capacityDriver   = "capacity.csi.example.com"
+	capacityClass    = "capacityClass"
+	noCapacityDriver = "no-capacity.csi.example.com"
+	noCapacityClass  = "noCapacityClass"
+	zoneLabelKey     = "topology.example.com/zone"
+)
+
+func makeCapacity(name, zone, capacity, maximumVolumeSize string) *storagev1.CSIStorageCapacity {
+	c := &storagev1.CSIStorageCapacity{
+		ObjectMeta:       metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		NodeTopology:     &metav1.LabelSelector{MatchLabels: map[string]string{zoneLabelKey: zone}},
+		StorageClassName: capacityClass,
+	}
+	quantity := resource.MustParse(capacity)
+	c.Capacity = &quantity
+	if maximumVolumeSize != "" {
+		quantity := resource.MustParse(maximumVolumeSize)
+		c.MaximumVolumeSize = &quantity
+	}
+	return c
+}
+
+func newCapacityTestBinder(t *testing.T, stopCh <-chan struct{}, objects ...runtime.Object) *baseVolumeBinder {
+	waitMode := storagev1.VolumeBindingWaitForFirstConsumer
+	trackCapacity, untrackCapacity := true, false
+	objects = append(objects,
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: capacityClass}, Provisioner: capacityDriver, VolumeBindingMode: &waitMode},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: noCapacityClass}, Provisioner: noCapacityDriver, VolumeBindingMode: &waitMode},
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: capacityDriver}, Spec: storagev1.CSIDriverSpec{StorageCapacity: &trackCapacity}},
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: noCapacityDriver}, Spec: storagev1.CSIDriverSpec{StorageCapacity: &untrackCapacity}},
+		makeNode("node-a1", map[string]string{zoneLabelKey: "a"}),
+		makeNode("node-a2", map[string]string{zoneLabelKey: "a"}),
+		makeNode("node-b1", map[string]string{zoneLabelKey: "b"}),
+		makeCapacity("capacity-a", "a", "10Gi", ""),
+		makeCapacity("capacity-b", "b", "20Gi", "4Gi"),
+	)
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(objects...), 0)
+	b := newBaseVolumeBinder(
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Storage().V1().CSINodes(),
+		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Core().V1().PersistentVolumes(),
+		informerFactory.Storage().V1().StorageClasses(),
+		&CapacityCheck{
+			CSIDriverInformer:          informerFactory.Storage().V1().CSIDrivers(),
+			CSIStorageCapacityInformer: informerFactory.Storage().V1().CSIStorageCapacities(),
+		},
+	)
+	// The node informer is shared with the scheduler, which is what registers it outside of tests.
+	informerFactory.Core().V1().Nodes().Informer()
+	informerFactory.Start(stopCh)
+	for v, synced := range informerFactory.WaitForCacheSync(stopCh) {
+		if !synced {
+			t.Fatalf("Failed to sync informer %v", v)
+		}
+	}
+	return b
+}
+
+func TestFindPodVolumesWithCapacity(t *testing.T) {
+	class, otherClass := capacityClass, noCapacityClass
+	claim5Gi := makeTestPVC("claim-5gi", "5Gi", "", pvcUnbound, "", "1", &class)
+	claim3Gi := makeTestPVC("claim-3gi", "3Gi", "", pvcUnbound, "", "1", &class)
+	claim8Gi := makeTestPVC("claim-8gi", "8Gi", "", pvcUnbound, "", "1", &class)
+	untrackedClaim := makeTestPVC("untracked-claim", "100Gi", "", pvcUnbound, "", "1", &otherClass)
+	provisioningClaim := makeTestPVC("provisioning-claim", "6Gi", "node-a2", pvcSelectedNode, "", "1", &class)
+	memberClaim := makeTestPVC("member-claim", "6Gi", "", pvcUnbound, "", "1", &class)
+
+	member := makePod([]*v1.PersistentVolumeClaim{memberClaim})
+	member.Name, member.UID = "member", types.UID("member")
+
+	scenarios := map[string]struct {
+		podPVCs         []*v1.PersistentVolumeClaim
+		provisioning    bool
+		placedPods      []*PlacedPod
+		node            string
+		expectedReasons ConflictReasons
+	}{
+		"fits": {
+			podPVCs: []*v1.PersistentVolumeClaim{claim5Gi},
+			node:    "node-a1",
+		},
+		"claims of the same class are summed": {
+			podPVCs:         []*v1.PersistentVolumeClaim{claim5Gi, claim8Gi},
+			node:            "node-a1",
+			expectedReasons: ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"exceeds maximum volume size": {
+			podPVCs:         []*v1.PersistentVolumeClaim{claim5Gi},
+			node:            "node-b1",
+			expectedReasons: ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"below maximum volume size": {
+			podPVCs: []*v1.PersistentVolumeClaim{claim3Gi},
+			node:    "node-b1",
+		},
+		"capacity isn't tracked by the driver": {
+			podPVCs: []*v1.PersistentVolumeClaim{untrackedClaim},
+			node:    "node-a1",
+		},
+		"claim being provisioned in the same segment is reserved": {
+			podPVCs:         []*v1.PersistentVolumeClaim{claim5Gi},
+			provisioning:    true,
+			node:            "node-a1",
+			expectedReasons: ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"claim being provisioned in another segment isn't reserved": {
+			podPVCs:      []*v1.PersistentVolumeClaim{claim3Gi},
+			provisioning: true,
+			node:         "node-b1",
+		},
+		"gang member placed in the same segment is reserved": {
+			podPVCs:         []*v1.PersistentVolumeClaim{claim5Gi},
+			placedPods:      []*PlacedPod{{Pod: member, NodeLabels: map[string]string{zoneLabelKey: "a"}}},
+			node:            "node-a2",
+			expectedReasons: ConflictReasons{ErrReasonNotEnoughSpace},
+		},
+		"gang member placed in another segment isn't reserved": {
+			podPVCs:    []*v1.PersistentVolumeClaim{claim5Gi},
+			placedPods: []*PlacedPod{{Pod: member, NodeLabels: map[string]string{zoneLabelKey: "b"}}},
+			node:       "node-a2",
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			objects := []runtime.Object{memberClaim}
+			for _, pvc := range scenario.podPVCs {
+				objects = append(objects, pvc)
+			}
+			if scenario.provisioning {
+				objects = append(objects, provisioningClaim)
+			}
+			b := newCapacityTestBinder(t, stopCh, objects...)
+
+			pod := makePod(scenario.podPVCs)
+			pod.UID = types.UID("test-pod")
+			pendingClaims, err := b.GetPendingClaims(pod, scenario.placedPods)
+			if err != nil {
+				t.Fatalf("Failed to get pending claims: %v", err)
+			}
+			node, err := b.nodeInformer.Lister().Get(scenario.node)
+			if err != nil {
+				t.Fatalf("Failed to get node: %v", err)
+			}
+			reasons, err := b.FindPodVolumes(pod, node.Name, node.Labels, pendingClaims)
+			if err != nil {
+				t.Fatalf("Failed to find pod volumes: %v", err)
+			}
+			checkReasons(t, reasons, scenario.expectedReasons)
+		})
+	}
+}
+
+func TestScorePodVolumes(t *testing.T) {
+	class := capacityClass
+	claim := makeTestPVC("claim", "2Gi", "", pvcUnbound, "", "1", &class)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	b := newCapacityTestBinder(t, stopCh, claim)
+	pod := makePod([]*v1.PersistentVolumeClaim{claim})
+
+	for nodeName, expected := range map[string]float64{
+		// 8Gi of 10Gi is left in zone a.
+		"node-a1": 0.8,
+		// 18Gi of 20Gi is left in zone b.
+		"node-b1": 0.9,
+	} {
+		node, err := b.nodeInformer.Lister().Get(nodeName)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		score, err := b.ScorePodVolumes(pod, nodeName, node.Labels, nil)
+		if err != nil {
+			t.Fatalf("Failed to score pod volumes: %v", err)
+		}
+		if score != expected {
+			t.Errorf("Expected score %v on %s, got %v", expected, nodeName, score)
+		}
+	}
+}
+
+type countingCapacityLister struct {
+	storagelisters.CSIStorageCapacityLister
+	lists int
+}
+
+func (l *countingCapacityLister) List(selector labels.Selector) ([]*storagev1.CSIStorageCapacity, error) {
+	l.lists++
+	return l.CSIStorageCapacityLister.List(selector)
+}
+
+func TestCapacitiesListedOncePerPod(t *testing.T) {
+	class := capacityClass
+	claim := makeTestPVC("claim", "2Gi", "", pvcUnbound, "", "1", &class)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	b := newCapacityTestBinder(t, stopCh, claim)
+	lister := &countingCapacityLister{CSIStorageCapacityLister: b.csiStorageCapacityLister}
+	b.csiStorageCapacityLister = lister
+	pod := makePod([]*v1.PersistentVolumeClaim{claim})
+
+	pendingClaims, err := b.GetPendingClaims(pod, nil)
+	if err != nil {
+		t.Fatalf("Failed to get pending claims: %v", err)
+	}
+	for _, nodeName := range []string{"node-a1", "node-a2", "node-b1"} {
+		node, err := b.nodeInformer.Lister().Get(nodeName)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		if _, err := b.FindPodVolumes(pod, nodeName, node.Labels, pendingClaims); err != nil {
+			t.Fatalf("Failed to find pod volumes: %v", err)
+		}
+		if _, err := b.ScorePodVolumes(pod, nodeName, node.Labels, pendingClaims); err != nil {
+			t.Fatalf("Failed to score pod volumes: %v", err)
+		}
+	}
+	if lister.lists != 1 {
+		t.Errorf("Expected CSIStorageCapacities to be listed once, got %d", lister.lists)
+	}
+}
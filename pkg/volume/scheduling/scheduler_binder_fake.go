@@ -45,10 +45,20 @@ type FakeVolumeBinder struct {
 }
 
 // FindPodVolumes implements GodelVolumeBinder.FindPodVolumes.
-func (b *FakeVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string) (reasons ConflictReasons, err error) {
+func (b *FakeVolumeBinder) FindPodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (reasons ConflictReasons, err error) {
 	return b.config.FindReasons, b.config.FindErr
 }
 
+// GetPendingClaims implements GodelVolumeBinder.GetPendingClaims.
+func (b *FakeVolumeBinder) GetPendingClaims(pod *v1.Pod, placedPods []*PlacedPod) (*PendingClaims, error) {
+	return nil, nil
+}
+
+// ScorePodVolumes implements GodelVolumeBinder.ScorePodVolumes.
+func (b *FakeVolumeBinder) ScorePodVolumes(pod *v1.Pod, nodeName string, nodeLabels map[string]string, pendingClaims *PendingClaims) (float64, error) {
+	return 0, nil
+}
+
 // AssumePodVolumes implements GodelVolumeBinder.AssumePodVolumes.
 func (b *FakeVolumeBinder) AssumePodVolumes(assumedPod *v1.Pod, nodeName string) (bool, error) {
 	b.AssumeCalled = true
@@ -161,6 +161,7 @@ func newTestBinder(t *testing.T, stopCh <-chan struct{}) *testEnv {
 		pvcInformer,
 		informerFactory.Core().V1().PersistentVolumes(),
 		classInformer,
+		nil,
 		3*time.Second)
 
 	// Wait for informers cache sync
@@ -711,6 +712,25 @@ func makePod(pvcs []*v1.PersistentVolumeClaim) *v1.Pod {
 	return pod
 }
 
+// makeGenericEphemeralPod returns a pod with a generic ephemeral volume, and its PVC owned by the pod,
+// which is created from the given claim.
+func makeGenericEphemeralPod(claim *v1.PersistentVolumeClaim) (*v1.Pod, *v1.PersistentVolumeClaim) {
+	pod := makePodWithoutPVC()
+	pod.UID = "test-pod-uid"
+	pod.Spec.Volumes = []v1.Volume{
+		{
+			Name: "ephemeral",
+			VolumeSource: v1.VolumeSource{
+				Ephemeral: &v1.EphemeralVolumeSource{},
+			},
+		},
+	}
+	pvc := claim.DeepCopy()
+	pvc.Name = pod.Name + "-ephemeral"
+	pvc.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(pod, v1.SchemeGroupVersion.WithKind("Pod"))}
+	return pod, pvc
+}
+
 func makePodWithoutPVC() *v1.Pod {
 	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -778,6 +798,10 @@ func checkReasons(t *testing.T, actual, expected ConflictReasons) {
 }
 
 func TestFindPodVolumesWithoutProvisioning(t *testing.T) {
+	ephemeralPod, ephemeralPVC := makeGenericEphemeralPod(unboundPVC)
+	conflictingPVC := ephemeralPVC.DeepCopy()
+	conflictingPVC.OwnerReferences = nil
+
 	type scenarioType struct {
 		// Inputs
 		pvs     []*v1.PersistentVolume
@@ -882,6 +906,23 @@ func TestFindPodVolumesWithoutProvisioning(t *testing.T) {
 			podPVCs: []*v1.PersistentVolumeClaim{immediateUnboundPVC, unboundPVC},
 			reasons: ConflictReasons{ErrUnboundImmediatePVC},
 		},
+		"generic-ephemeral,no-pvc": {
+			pod:        ephemeralPod,
+			cachePVCs:  []*v1.PersistentVolumeClaim{},
+			shouldFail: true,
+		},
+		"generic-ephemeral,with-pvc": {
+			pod:              ephemeralPod,
+			cachePVCs:        []*v1.PersistentVolumeClaim{ephemeralPVC},
+			pvs:              []*v1.PersistentVolume{pvNode1a},
+			expectedBindings: []*bindingInfo{makeBinding(ephemeralPVC, pvNode1a)},
+		},
+		"generic-ephemeral,wrong-pvc": {
+			pod:        ephemeralPod,
+			cachePVCs:  []*v1.PersistentVolumeClaim{conflictingPVC},
+			pvs:        []*v1.PersistentVolume{pvNode1a},
+			shouldFail: true,
+		},
 	}
 
 	testNode := &v1.Node{
@@ -913,7 +954,7 @@ func TestFindPodVolumesWithoutProvisioning(t *testing.T) {
 		}
 
 		// Execute
-		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, testNode.GetName(), testNode.GetLabels())
+		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, testNode.GetName(), testNode.GetLabels(), nil)
 
 		// Validate
 		if !scenario.shouldFail && err != nil {
@@ -1018,7 +1059,7 @@ func TestFindPodVolumesWithProvisioning(t *testing.T) {
 		}
 
 		// Execute
-		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, testNode.GetName(), testNode.GetLabels())
+		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, testNode.GetName(), testNode.GetLabels(), nil)
 
 		// Validate
 		if !scenario.shouldFail && err != nil {
@@ -1118,7 +1159,7 @@ func TestFindPodVolumesWithCSIMigration(t *testing.T) {
 		}
 
 		// Execute
-		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, node.GetName(), node.GetLabels())
+		reasons, err := testEnv.binder.FindPodVolumes(scenario.pod, node.GetName(), node.GetLabels(), nil)
 
 		// Validate
 		if !scenario.shouldFail && err != nil {
@@ -1939,7 +1980,7 @@ func TestFindAssumeVolumes(t *testing.T) {
 
 	// Execute
 	// 1. Find matching PVs
-	reasons, err := testEnv.binder.FindPodVolumes(pod, testNode.GetName(), testNode.GetLabels())
+	reasons, err := testEnv.binder.FindPodVolumes(pod, testNode.GetName(), testNode.GetLabels(), nil)
 	if err != nil {
 		t.Errorf("Test failed: FindPodVolumes returned error: %v", err)
 	}
@@ -1965,7 +2006,7 @@ func TestFindAssumeVolumes(t *testing.T) {
 	// This should always return the original chosen pv
 	// Run this many times in case sorting returns different orders for the two PVs.
 	for i := 0; i < 50; i++ {
-		reasons, err := testEnv.binder.FindPodVolumes(pod, testNode.GetName(), testNode.GetLabels())
+		reasons, err := testEnv.binder.FindPodVolumes(pod, testNode.GetName(), testNode.GetLabels(), nil)
 		if err != nil {
 			t.Errorf("Test failed: FindPodVolumes returned error: %v", err)
 		}
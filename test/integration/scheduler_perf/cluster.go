@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	godelclientfake "github.com/kubewharf/godel-scheduler-api/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/kubewharf/godel-scheduler-api/pkg/client/informers/externalversions"
+	katalystclientfake "github.com/kubewharf/katalyst-api/pkg/client/clientset/versioned/fake"
+	katalystinformers "github.com/kubewharf/katalyst-api/pkg/client/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	godelbinder "github.com/kubewharf/godel-scheduler/pkg/binder"
+	binderconfig "github.com/kubewharf/godel-scheduler/pkg/binder/apis/config"
+	bindercontroller "github.com/kubewharf/godel-scheduler/pkg/binder/controller"
+	godeldispatcher "github.com/kubewharf/godel-scheduler/pkg/dispatcher"
+	godelscheduler "github.com/kubewharf/godel-scheduler/pkg/scheduler"
+	godelschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	cmdutil "github.com/kubewharf/godel-scheduler/pkg/util/cmd"
+)
+
+const (
+	// testSchedulerName is the scheduler name of pods, which is shared by dispatcher, scheduler and binder.
+	testSchedulerName = "godel-scheduler"
+	// testGodelSchedulerName is the name of the Scheduler CRD registered by the scheduler.
+	testGodelSchedulerName = "godel-scheduler-0"
+
+	reservationTTL = time.Duration(godelschedulerconfig.DefaultReservationTimeOutSeconds) * time.Second
+)
+
+// The fake clientset buffers DefaultChanSize events for each watcher and panics once the buffer is full,
+// which is easy to hit when thousands of pods are created in a burst.
+const watchChanSize = 1 << 20
+
+// cluster runs dispatcher, scheduler and binder in process against fake clientsets. As in production,
+// each component has its own informers, and they only communicate through the API objects.
+type cluster struct {
+	client         *clientsetfake.Clientset
+	crdClient      *godelclientfake.Clientset
+	katalystClient *katalystclientfake.Clientset
+}
+
+func newCluster() *cluster {
+	watch.DefaultChanSize = watchChanSize
+
+	c := &cluster{
+		client:         clientsetfake.NewSimpleClientset(),
+		crdClient:      godelclientfake.NewSimpleClientset(),
+		katalystClient: katalystclientfake.NewSimpleClientset(),
+	}
+	// Fill in the fields set by API server on creation, which are required by components, e.g. pods
+	// without UID couldn't be cached.
+	for _, fake := range []*clienttesting.Fake{&c.client.Fake, &c.crdClient.Fake, &c.katalystClient.Fake} {
+		fake.PrependReactor("create", "*", setObjectMetaOnCreation)
+	}
+	// The fake clientset stores bindings as updates of pods, so bind pods as API server and kubelet do.
+	c.client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		createAction, ok := action.(clienttesting.CreateAction)
+		if !ok || createAction.GetSubresource() != "binding" {
+			return false, nil, nil
+		}
+		binding := createAction.GetObject().(*v1.Binding)
+		return true, nil, c.bindPod(binding)
+	})
+	return c
+}
+
+func setObjectMetaOnCreation(action clienttesting.Action) (bool, runtime.Object, error) {
+	createAction, ok := action.(clienttesting.CreateAction)
+	if !ok || len(createAction.GetSubresource()) > 0 {
+		return false, nil, nil
+	}
+	if obj, err := meta.Accessor(createAction.GetObject()); err == nil {
+		if len(obj.GetUID()) == 0 {
+			obj.SetUID(uuid.NewUUID())
+		}
+		if ts := obj.GetCreationTimestamp(); ts.IsZero() {
+			obj.SetCreationTimestamp(metav1.Now())
+		}
+	}
+	return false, nil, nil
+}
+
+func (c *cluster) bindPod(binding *v1.Binding) error {
+	obj, err := c.client.Tracker().Get(v1.SchemeGroupVersion.WithResource("pods"), binding.Namespace, binding.Name)
+	if err != nil {
+		return err
+	}
+	pod := obj.(*v1.Pod).DeepCopy()
+	if len(pod.Spec.NodeName) > 0 {
+		return fmt.Errorf("pod %s/%s is already bound to %s", pod.Namespace, pod.Name, pod.Spec.NodeName)
+	}
+	now := metav1.Now()
+	pod.Spec.NodeName = binding.Target.Name
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.StartTime = &now
+	pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{
+		Type:               v1.PodScheduled,
+		Status:             v1.ConditionTrue,
+		LastTransitionTime: now,
+	})
+	return c.client.Tracker().Update(v1.SchemeGroupVersion.WithResource("pods"), pod, pod.Namespace)
+}
+
+// run starts all the components with the scheduler configuration, and blocks until the scheduler
+// is registered so that pods can be dispatched.
+func (c *cluster) run(ctx context.Context, cfg *godelschedulerconfig.GodelSchedulerConfiguration) error {
+	recorder := &events.FakeRecorder{}
+
+	// Dispatcher
+	{
+		informerFactory := cmdutil.NewInformerFactory(c.client, 0)
+		crdInformerFactory := crdinformers.NewSharedInformerFactory(c.crdClient, 0)
+		katalystInformerFactory := katalystinformers.NewSharedInformerFactory(c.katalystClient, 0)
+		dispatcher := godeldispatcher.New(
+			ctx.Done(),
+			c.client,
+			c.crdClient,
+			informerFactory.Core().V1().Pods(),
+			informerFactory.Core().V1().Nodes(),
+			crdInformerFactory.Scheduling().V1alpha1().Schedulers(),
+			crdInformerFactory.Node().V1alpha1().NMNodes(),
+			crdInformerFactory.Scheduling().V1alpha1().PodGroups(),
+			informerFactory.Scheduling().V1().PriorityClasses(),
+			c.katalystClient,
+			katalystInformerFactory.Node().V1alpha1().CustomNodeResources(),
+			testSchedulerName,
+			recorder,
+			nil,
+		)
+		informerFactory.Start(ctx.Done())
+		crdInformerFactory.Start(ctx.Done())
+		katalystInformerFactory.Start(ctx.Done())
+		informerFactory.WaitForCacheSync(ctx.Done())
+		crdInformerFactory.WaitForCacheSync(ctx.Done())
+		katalystInformerFactory.WaitForCacheSync(ctx.Done())
+		go dispatcher.Run(ctx)
+	}
+
+	// Scheduler
+	{
+		informerFactory := cmdutil.NewInformerFactory(c.client, 0)
+		crdInformerFactory := crdinformers.NewSharedInformerFactory(c.crdClient, 0)
+		katalystInformerFactory := katalystinformers.NewSharedInformerFactory(c.katalystClient, 0)
+		var opts []godelscheduler.Option
+		if cfg != nil {
+			opts = append(opts,
+				godelscheduler.WithDefaultProfile(cfg.DefaultProfile),
+				godelscheduler.WithSubClusterProfiles(cfg.SubClusterProfiles),
+			)
+			if cfg.SubClusterKey != nil {
+				opts = append(opts, godelscheduler.WithSubClusterKey(*cfg.SubClusterKey))
+			}
+		}
+		schedulerName := testSchedulerName
+		sched, err := godelscheduler.New(
+			testGodelSchedulerName,
+			&schedulerName,
+			c.client,
+			c.crdClient,
+			informerFactory,
+			crdInformerFactory,
+			katalystInformerFactory,
+			ctx.Done(),
+			recorder,
+			reservationTTL,
+			opts...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create scheduler: %v", err)
+		}
+		informerFactory.Start(ctx.Done())
+		crdInformerFactory.Start(ctx.Done())
+		katalystInformerFactory.Start(ctx.Done())
+		informerFactory.WaitForCacheSync(ctx.Done())
+		crdInformerFactory.WaitForCacheSync(ctx.Done())
+		katalystInformerFactory.WaitForCacheSync(ctx.Done())
+		go sched.Run(ctx)
+	}
+
+	// Binder
+	{
+		informerFactory := cmdutil.NewInformerFactory(c.client, 0)
+		crdInformerFactory := crdinformers.NewSharedInformerFactory(c.crdClient, 0)
+		katalystInformerFactory := katalystinformers.NewSharedInformerFactory(c.katalystClient, 0)
+		schedulerName := testSchedulerName
+		binder, err := godelbinder.New(
+			c.client,
+			c.crdClient,
+			informerFactory,
+			crdInformerFactory,
+			katalystInformerFactory,
+			ctx.Done(),
+			recorder,
+			&schedulerName,
+			binderconfig.VolumeBindingTimeoutSeconds,
+			reservationTTL,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create binder: %v", err)
+		}
+		pgInformer := crdInformerFactory.Scheduling().V1alpha1().PodGroups()
+		informerFactory.Start(ctx.Done())
+		crdInformerFactory.Start(ctx.Done())
+		katalystInformerFactory.Start(ctx.Done())
+		informerFactory.WaitForCacheSync(ctx.Done())
+		crdInformerFactory.WaitForCacheSync(ctx.Done())
+		katalystInformerFactory.WaitForCacheSync(ctx.Done())
+		cache.WaitForCacheSync(ctx.Done(), pgInformer.Informer().HasSynced)
+		bindercontroller.SetupPodGroupController(ctx, c.client, c.crdClient, pgInformer)
+		go binder.Run(ctx)
+	}
+
+	return wait.PollImmediateUntil(100*time.Millisecond, func() (bool, error) {
+		scheduler, err := c.crdClient.SchedulingV1alpha1().Schedulers().Get(ctx, testGodelSchedulerName, metav1.GetOptions{})
+		return err == nil && scheduler.Status.LastUpdateTime != nil, nil
+	}, ctx.Done())
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/featuregate"
+	"sigs.k8s.io/yaml"
+
+	godelschedulerconfig "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config"
+	godelschedulerscheme "github.com/kubewharf/godel-scheduler/pkg/scheduler/apis/config/scheme"
+)
+
+const (
+	// createNodesOpcode creates nodes, and CNRs for nodes with NUMA topology.
+	createNodesOpcode = "createNodes"
+	// createPodsOpcode creates single pods.
+	createPodsOpcode = "createPods"
+	// createPodGroupsOpcode creates pod groups and all of their member pods.
+	createPodGroupsOpcode = "createPodGroups"
+	// barrierOpcode waits until all the pods created so far are bound.
+	barrierOpcode = "barrier"
+)
+
+// testCase defines a set of workloads sharing the same ops, feature gates and scheduler configuration.
+type testCase struct {
+	// Name of the test case.
+	Name string `json:"name"`
+	// FeatureGates are set before starting the components and restored after the test case.
+	FeatureGates map[featuregate.Feature]bool `json:"featureGates,omitempty"`
+	// SchedulerConfigFile is a GodelSchedulerConfiguration file, relative to the config file.
+	// The default configuration is used if it's empty.
+	SchedulerConfigFile string `json:"schedulerConfigFile,omitempty"`
+	// PriorityClasses are created before running the ops.
+	PriorityClasses []priorityClass `json:"priorityClasses,omitempty"`
+	// Ops are executed in order for each workload.
+	Ops []op `json:"workloadTemplate"`
+	// Workloads substitute the parameters of the ops.
+	Workloads []workload `json:"workloads"`
+
+	// schedulerConfig is loaded from SchedulerConfigFile.
+	schedulerConfig *godelschedulerconfig.GodelSchedulerConfiguration
+}
+
+// workload is a named set of parameters for the ops of a test case.
+type workload struct {
+	Name string `json:"name"`
+	// Labels select workloads to run, e.g. performance or integration-test.
+	Labels []string       `json:"labels,omitempty"`
+	Params map[string]int `json:"params,omitempty"`
+}
+
+func (w *workload) hasLabel(label string) bool {
+	for _, l := range w.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+type priorityClass struct {
+	Name  string `json:"name"`
+	Value int32  `json:"value"`
+	// Preemptible marks the pods of the class as preemptible.
+	Preemptible bool `json:"preemptible,omitempty"`
+}
+
+// op is a step of a workload. Fields are used according to the opcode.
+type op struct {
+	Opcode string `json:"opcode"`
+	// Count is the number of nodes, pods or pod groups to create.
+	Count int `json:"count,omitempty"`
+	// CountParam overrides Count with the parameter of the workload, e.g. $initNodes.
+	CountParam string `json:"countParam,omitempty"`
+	// CollectMetrics marks the pods created by the op as measured.
+	CollectMetrics bool `json:"collectMetrics,omitempty"`
+
+	// NodeTemplate is used by createNodes.
+	NodeTemplate *nodeTemplate `json:"nodeTemplate,omitempty"`
+	// PodTemplate is used by createPods and createPodGroups.
+	PodTemplate *podTemplate `json:"podTemplate,omitempty"`
+	// MinMember is the size of each pod group created by createPodGroups.
+	MinMember int `json:"minMember,omitempty"`
+	// AffinityTopologyKey sets the required job level affinity of pod groups.
+	AffinityTopologyKey string `json:"affinityTopologyKey,omitempty"`
+}
+
+type nodeTemplate struct {
+	// Capacity is used as both capacity and allocatable of nodes.
+	Capacity map[v1.ResourceName]string `json:"capacity"`
+	Labels   map[string]string          `json:"labels,omitempty"`
+	// Zones spreads nodes among `zone-<i>` by the topology.kubernetes.io/zone label.
+	Zones int `json:"zones,omitempty"`
+	// NumaZones creates a CNR with the number of NUMA zones for each node, among which
+	// cpu and memory are evenly divided.
+	NumaZones int `json:"numaZones,omitempty"`
+}
+
+type podTemplate struct {
+	Requests          map[v1.ResourceName]string `json:"requests"`
+	Labels            map[string]string          `json:"labels,omitempty"`
+	Annotations       map[string]string          `json:"annotations,omitempty"`
+	PriorityClassName string                     `json:"priorityClassName,omitempty"`
+}
+
+func (o *op) count(w *workload) (int, error) {
+	if len(o.CountParam) == 0 {
+		return o.Count, nil
+	}
+	count, ok := w.Params[strings.TrimPrefix(o.CountParam, "$")]
+	if !ok {
+		return 0, fmt.Errorf("parameter %s is not set in workload %s", o.CountParam, w.Name)
+	}
+	return count, nil
+}
+
+func (o *op) validate() error {
+	switch o.Opcode {
+	case createNodesOpcode:
+		if o.NodeTemplate == nil {
+			return fmt.Errorf("nodeTemplate is required by %s", o.Opcode)
+		}
+	case createPodsOpcode:
+		if o.PodTemplate == nil {
+			return fmt.Errorf("podTemplate is required by %s", o.Opcode)
+		}
+	case createPodGroupsOpcode:
+		if o.PodTemplate == nil {
+			return fmt.Errorf("podTemplate is required by %s", o.Opcode)
+		}
+		if o.MinMember <= 0 {
+			return fmt.Errorf("minMember of %s must be positive", o.Opcode)
+		}
+	case barrierOpcode:
+	default:
+		return fmt.Errorf("unknown opcode %q", o.Opcode)
+	}
+	return nil
+}
+
+// loadTestCases loads test cases from the config file, as well as the scheduler configurations referred by them.
+func loadTestCases(path string) ([]*testCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var testCases []*testCase
+	if err := yaml.UnmarshalStrict(data, &testCases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	for _, tc := range testCases {
+		for i := range tc.Ops {
+			if err := tc.Ops[i].validate(); err != nil {
+				return nil, fmt.Errorf("test case %s: op %d: %v", tc.Name, i, err)
+			}
+		}
+		if len(tc.SchedulerConfigFile) > 0 {
+			if tc.schedulerConfig, err = loadSchedulerConfig(filepath.Join(filepath.Dir(path), tc.SchedulerConfigFile)); err != nil {
+				return nil, fmt.Errorf("test case %s: %v", tc.Name, err)
+			}
+		}
+	}
+	return testCases, nil
+}
+
+func loadSchedulerConfig(path string) (*godelschedulerconfig.GodelSchedulerConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// The UniversalDecoder runs defaulting and returns the internal type by default.
+	obj, gvk, err := godelschedulerscheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cfg, ok := obj.(*godelschedulerconfig.GodelSchedulerConfiguration); ok {
+		return cfg, nil
+	}
+	return nil, fmt.Errorf("couldn't decode %s as GodelSchedulerConfiguration, got %s", path, gvk)
+}
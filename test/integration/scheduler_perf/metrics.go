@@ -0,0 +1,350 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/test/e2e/perftype"
+)
+
+// resultVersion is the version of the result format.
+const resultVersion = "v1"
+
+// stageMetrics are the existing latency histograms of dispatcher, scheduler and binder, whose
+// increments during a workload are reported.
+var stageMetrics = []string{
+	"dispatcher_e2e_dispatching_duration_seconds",
+	"scheduler_e2e_scheduling_duration_seconds",
+	"scheduler_scheduling_updatesnapshot_duration_seconds",
+	"scheduler_scheduling_algorithm_duration_seconds",
+	"scheduler_unit_e2e_duration_seconds",
+	"binder_e2e_duration_seconds",
+	"binder_unit_e2e_duration_seconds",
+	"godel_pod_e2e_duration_seconds",
+}
+
+// Stages of a pod observed through the API objects.
+const (
+	stageDispatching = "Dispatching"
+	stageScheduling  = "Scheduling"
+	stageBinding     = "Binding"
+	stageE2E         = "E2E"
+)
+
+// podTimestamps are the first time that a pod was observed in each state.
+type podTimestamps struct {
+	created    time.Time
+	dispatched time.Time
+	assumed    time.Time
+	bound      time.Time
+}
+
+// podTracker records the state transitions of measured pods.
+type podTracker struct {
+	mu       sync.Mutex
+	measured map[string]*podTimestamps
+	// bound is the number of measured pods which are bound.
+	bound int
+}
+
+func newPodTracker(podInformer cache.SharedIndexInformer) *podTracker {
+	t := &podTracker{measured: make(map[string]*podTimestamps)}
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { t.observe(obj) },
+		UpdateFunc: func(_, obj interface{}) { t.observe(obj) },
+	})
+	return t
+}
+
+// measure marks the pod as measured. It must be called before creating the pod.
+func (t *podTracker) measure(key string, created time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.measured[key] = &podTimestamps{created: created}
+}
+
+func (t *podTracker) observe(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ts, ok := t.measured[podutil.GetPodKey(pod)]
+	if !ok {
+		return
+	}
+	switch {
+	case podutil.BoundPod(pod):
+		if ts.bound.IsZero() {
+			ts.bound = now
+			t.bound++
+		}
+	case podutil.AssumedPod(pod):
+		if ts.assumed.IsZero() {
+			ts.assumed = now
+		}
+	case podutil.DispatchedPod(pod):
+		if ts.dispatched.IsZero() {
+			ts.dispatched = now
+		}
+	}
+}
+
+// progress returns the numbers of bound and measured pods.
+func (t *podTracker) progress() (int, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bound, len(t.measured)
+}
+
+// latencies returns the latencies of stages in milliseconds. A stage is skipped for a pod if
+// either end of it isn't observed, e.g. a pod could be assumed and bound between two events.
+func (t *podTracker) latencies() map[string][]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	latencies := make(map[string][]float64)
+	add := func(stage string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			latencies[stage] = append(latencies[stage], float64(to.Sub(from))/float64(time.Millisecond))
+		}
+	}
+	for _, ts := range t.measured {
+		add(stageDispatching, ts.created, ts.dispatched)
+		add(stageScheduling, ts.dispatched, ts.assumed)
+		add(stageBinding, ts.assumed, ts.bound)
+		add(stageE2E, ts.created, ts.bound)
+	}
+	return latencies
+}
+
+// throughputCollector samples the number of bound measured pods every second.
+type throughputCollector struct {
+	tracker *podTracker
+	// samples are the numbers of pods bound in each second.
+	samples []float64
+	start   time.Time
+}
+
+func (c *throughputCollector) run(stopCh <-chan struct{}) {
+	c.start = time.Now()
+	lastBound, _ := c.tracker.progress()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			bound, _ := c.tracker.progress()
+			c.samples = append(c.samples, float64(bound-lastBound))
+			lastBound = bound
+		}
+	}
+}
+
+// histogram is the sum of the buckets of all the series of a histogram metric.
+type histogram struct {
+	upperBounds []float64
+	// counts are cumulative.
+	counts []float64
+	count  float64
+	sum    float64
+}
+
+// gatherHistograms returns the histograms of stageMetrics in the legacy registry.
+func gatherHistograms() (map[string]*histogram, error) {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(stageMetrics))
+	for _, name := range stageMetrics {
+		wanted[name] = true
+	}
+	histograms := make(map[string]*histogram)
+	for _, family := range families {
+		if !wanted[family.GetName()] || family.GetType() != dto.MetricType_HISTOGRAM {
+			continue
+		}
+		h := &histogram{}
+		for _, metric := range family.GetMetric() {
+			h.add(metric.GetHistogram())
+		}
+		histograms[family.GetName()] = h
+	}
+	return histograms, nil
+}
+
+func (h *histogram) add(m *dto.Histogram) {
+	if h.upperBounds == nil {
+		for _, bucket := range m.GetBucket() {
+			h.upperBounds = append(h.upperBounds, bucket.GetUpperBound())
+		}
+		h.counts = make([]float64, len(h.upperBounds))
+	}
+	for i, bucket := range m.GetBucket() {
+		if i < len(h.counts) {
+			h.counts[i] += float64(bucket.GetCumulativeCount())
+		}
+	}
+	h.count += float64(m.GetSampleCount())
+	h.sum += m.GetSampleSum()
+}
+
+// sub returns the increment of the histogram since before.
+func (h *histogram) sub(before *histogram) *histogram {
+	if before == nil {
+		return h
+	}
+	delta := &histogram{
+		upperBounds: h.upperBounds,
+		counts:      make([]float64, len(h.counts)),
+		count:       h.count - before.count,
+		sum:         h.sum - before.sum,
+	}
+	for i := range h.counts {
+		delta.counts[i] = h.counts[i]
+		if i < len(before.counts) {
+			delta.counts[i] -= before.counts[i]
+		}
+	}
+	return delta
+}
+
+// quantile estimates the q-quantile by linear interpolation within the bucket, the same as
+// histogram_quantile of Prometheus.
+func (h *histogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := q * h.count
+	lowerBound, lowerCount := 0.0, 0.0
+	for i, upperBound := range h.upperBounds {
+		if h.counts[i] >= rank {
+			if h.counts[i] == lowerCount {
+				return upperBound
+			}
+			return lowerBound + (upperBound-lowerBound)*(rank-lowerCount)/(h.counts[i]-lowerCount)
+		}
+		lowerBound, lowerCount = upperBound, h.counts[i]
+	}
+	// The rank falls into the +Inf bucket.
+	return lowerBound
+}
+
+func (h *histogram) dataItem(labels map[string]string) perftype.DataItem {
+	const secondsToMillis = 1000
+	return perftype.DataItem{
+		Data: map[string]float64{
+			"Perc50":  h.quantile(0.5) * secondsToMillis,
+			"Perc90":  h.quantile(0.9) * secondsToMillis,
+			"Perc95":  h.quantile(0.95) * secondsToMillis,
+			"Perc99":  h.quantile(0.99) * secondsToMillis,
+			"Average": h.sum / math.Max(h.count, 1) * secondsToMillis,
+			"Count":   h.count,
+		},
+		Unit:   "ms",
+		Labels: labels,
+	}
+}
+
+// samplesDataItem summarizes samples by average and percentiles.
+func samplesDataItem(samples []float64, unit string, labels map[string]string) perftype.DataItem {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	percentile := func(p float64) float64 {
+		if len(sorted) == 0 {
+			return 0
+		}
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	sum := 0.0
+	for _, sample := range sorted {
+		sum += sample
+	}
+	return perftype.DataItem{
+		Data: map[string]float64{
+			"Perc50":  percentile(0.5),
+			"Perc90":  percentile(0.9),
+			"Perc95":  percentile(0.95),
+			"Perc99":  percentile(0.99),
+			"Average": sum / math.Max(float64(len(sorted)), 1),
+			"Count":   float64(len(sorted)),
+		},
+		Unit:   unit,
+		Labels: labels,
+	}
+}
+
+// collectDataItems summarizes the throughput and the latencies of a workload.
+func collectDataItems(name string, tracker *podTracker, throughput *throughputCollector, duration time.Duration,
+	before, after map[string]*histogram,
+) []perftype.DataItem {
+	labels := func(metric string, extra ...string) map[string]string {
+		l := map[string]string{"Name": name, "Metric": metric}
+		for i := 0; i+1 < len(extra); i += 2 {
+			l[extra[i]] = extra[i+1]
+		}
+		return l
+	}
+
+	bound, _ := tracker.progress()
+	throughputItem := samplesDataItem(throughput.samples, "pods/s", labels("SchedulingThroughput"))
+	// The average is over the whole workload rather than the complete seconds sampled.
+	throughputItem.Data["Average"] = float64(bound) / math.Max(duration.Seconds(), 1e-9)
+	items := []perftype.DataItem{throughputItem}
+
+	latencies := tracker.latencies()
+	for _, stage := range []string{stageDispatching, stageScheduling, stageBinding, stageE2E} {
+		items = append(items, samplesDataItem(latencies[stage], "ms", labels("PodStageLatency", "Stage", stage)))
+	}
+	for _, metric := range stageMetrics {
+		if h, ok := after[metric]; ok {
+			items = append(items, h.sub(before[metric]).dataItem(labels(metric)))
+		}
+	}
+	return items
+}
+
+// writeResults writes the data items into a JSON file in dir, and returns the path of the file.
+func writeResults(dir string, labels map[string]string, items []perftype.DataItem) (string, error) {
+	data, err := json.MarshalIndent(perftype.PerfData{Version: resultVersion, DataItems: items, Labels: labels}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("SchedulerPerf_%s.json", time.Now().Format("2006-01-02T15-04-05")))
+	return path, os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/godel-scheduler/test/e2e/perftype"
+)
+
+var (
+	configFile = flag.String("perf-scheduling-config", "config/performance-config.yaml",
+		"Path to the file of test cases.")
+	labelFilter = flag.String("perf-scheduling-label-filter", "performance",
+		"Only workloads with the label are run by the benchmark.")
+	resultDir = flag.String("perf-scheduling-result-dir", "",
+		"Directory to write the results in JSON. Results are only logged if it's empty.")
+	timeout = flag.Duration("perf-scheduling-timeout", 10*time.Minute,
+		"Timeout of each op and of scheduling the measured pods.")
+)
+
+// integrationTestLabel marks small workloads which are run by unit tests to keep the test cases working.
+const integrationTestLabel = "integration-test"
+
+// BenchmarkPerfScheduling runs the workloads selected by -perf-scheduling-label-filter, e.g.
+//
+//	go test ./test/integration/scheduler_perf -run=^$ -bench=BenchmarkPerfScheduling -benchtime=1x \
+//	  -perf-scheduling-result-dir=/tmp/perf
+//
+// Each workload runs once regardless of b.N.
+func BenchmarkPerfScheduling(b *testing.B) {
+	items := runTestCases(b, *labelFilter)
+	labels := map[string]string{"Benchmark": "PerfScheduling", "Label": *labelFilter}
+	if len(*resultDir) > 0 {
+		path, err := writeResults(*resultDir, labels, items)
+		if err != nil {
+			b.Fatalf("Failed to write results: %v", err)
+		}
+		b.Logf("Results are written to %s", path)
+		return
+	}
+	data, err := json.MarshalIndent(perftype.PerfData{Version: resultVersion, DataItems: items, Labels: labels}, "", "  ")
+	if err != nil {
+		b.Fatalf("Failed to marshal results: %v", err)
+	}
+	b.Logf("%s\n%s\n%s", perftype.PerfResultTag, data, perftype.PerfResultEnd)
+}
+
+// TestPerfScheduling runs the small workloads to make sure the test cases keep working.
+func TestPerfScheduling(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping in short mode")
+	}
+	for _, item := range runTestCases(t, integrationTestLabel) {
+		if item.Labels["Metric"] == "SchedulingThroughput" && item.Data["Average"] <= 0 {
+			t.Errorf("No pod of %s is scheduled", item.Labels["Name"])
+		}
+	}
+}
+
+func runTestCases(tb testing.TB, label string) []perftype.DataItem {
+	testCases, err := loadTestCases(*configFile)
+	if err != nil {
+		tb.Fatalf("Failed to load test cases: %v", err)
+	}
+	var items []perftype.DataItem
+	run := func(tb testing.TB, tc *testCase, w *workload) {
+		for feature, enabled := range tc.FeatureGates {
+			defer featuregatetesting.SetFeatureGateDuringTest(tb, utilfeature.DefaultFeatureGate, feature, enabled)()
+		}
+		workloadItems, err := runWorkload(context.Background(), tc, w, *timeout)
+		if err != nil {
+			tb.Fatalf("Failed to run workload: %v", err)
+		}
+		for _, item := range workloadItems {
+			if item.Labels["Metric"] != "SchedulingThroughput" {
+				continue
+			}
+			klog.InfoS("Finished workload", "name", item.Labels["Name"], "throughput", fmt.Sprintf("%.2f pods/s", item.Data["Average"]))
+			if b, ok := tb.(*testing.B); ok {
+				b.ReportMetric(item.Data["Average"], "pods/s")
+			}
+		}
+		items = append(items, workloadItems...)
+	}
+
+	for _, tc := range testCases {
+		for i := range tc.Workloads {
+			tc, w := tc, &tc.Workloads[i]
+			if !w.hasLabel(label) {
+				continue
+			}
+			name := tc.Name + "/" + w.Name
+			switch tb := tb.(type) {
+			case *testing.B:
+				tb.Run(name, func(b *testing.B) { run(b, tc, w) })
+			case *testing.T:
+				tb.Run(name, func(t *testing.T) { run(t, tc, w) })
+			}
+		}
+	}
+	return items
+}
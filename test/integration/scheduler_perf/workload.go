@@ -0,0 +1,336 @@
+/*
+Copyright 2024 The Godel Scheduler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	schedulingv1a1 "github.com/kubewharf/godel-scheduler-api/pkg/apis/scheduling/v1alpha1"
+	katalystv1alpha1 "github.com/kubewharf/katalyst-api/pkg/apis/node/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+
+	"github.com/kubewharf/godel-scheduler/pkg/util"
+	podutil "github.com/kubewharf/godel-scheduler/pkg/util/pod"
+	"github.com/kubewharf/godel-scheduler/test/e2e/perftype"
+)
+
+// defaultMaxPods is used if the capacity of pods isn't set in the node template.
+const defaultMaxPods = "110"
+
+// workloadRunner runs the ops of a workload against a cluster.
+type workloadRunner struct {
+	tc       *testCase
+	workload *workload
+	cluster  *cluster
+	timeout  time.Duration
+
+	podLister listerv1.PodLister
+	tracker   *podTracker
+
+	// nodes is the number of nodes created so far, which is used to name nodes.
+	nodes int
+	// priorities are the values of priority classes.
+	priorities map[string]int32
+}
+
+// runWorkload starts a cluster, runs the ops of the workload and returns the data items of measured pods.
+// Each op must finish within timeout, and so does the scheduling of measured pods.
+func runWorkload(ctx context.Context, tc *testCase, w *workload, timeout time.Duration) ([]perftype.DataItem, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := &workloadRunner{
+		tc:         tc,
+		workload:   w,
+		cluster:    newCluster(),
+		timeout:    timeout,
+		priorities: make(map[string]int32),
+	}
+	informerFactory := informers.NewSharedInformerFactory(r.cluster.client, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	r.podLister = podInformer.Lister()
+	r.tracker = newPodTracker(podInformer.Informer())
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	if err := r.cluster.run(ctx, tc.schedulerConfig); err != nil {
+		return nil, err
+	}
+	if err := r.createPriorityClasses(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		start      time.Time
+		before     map[string]*histogram
+		throughput = &throughputCollector{tracker: r.tracker}
+		stopCh     = make(chan struct{})
+	)
+	defer func() {
+		if !start.IsZero() {
+			close(stopCh)
+		}
+	}()
+	for i := range tc.Ops {
+		o := &tc.Ops[i]
+		if o.CollectMetrics && start.IsZero() {
+			var err error
+			if before, err = gatherHistograms(); err != nil {
+				return nil, err
+			}
+			start = time.Now()
+			go throughput.run(stopCh)
+		}
+		if err := r.runOp(ctx, i, o); err != nil {
+			return nil, fmt.Errorf("op %d (%s): %v", i, o.Opcode, err)
+		}
+	}
+	if start.IsZero() {
+		return nil, fmt.Errorf("no op collects metrics")
+	}
+
+	if err := r.waitFor(ctx, "measured pods to be bound", func() (bool, error) {
+		bound, measured := r.tracker.progress()
+		return bound == measured, nil
+	}); err != nil {
+		bound, measured := r.tracker.progress()
+		return nil, fmt.Errorf("%v: %d of %d measured pods are bound", err, bound, measured)
+	}
+	duration := time.Since(start)
+	after, err := gatherHistograms()
+	if err != nil {
+		return nil, err
+	}
+	return collectDataItems(tc.Name+"/"+w.Name, r.tracker, throughput, duration, before, after), nil
+}
+
+func (r *workloadRunner) runOp(ctx context.Context, index int, o *op) error {
+	count, err := o.count(r.workload)
+	if err != nil {
+		return err
+	}
+	namespace := fmt.Sprintf("namespace-%d", index)
+	switch o.Opcode {
+	case createNodesOpcode:
+		for i := 0; i < count; i++ {
+			if err := r.createNode(ctx, o.NodeTemplate); err != nil {
+				return err
+			}
+		}
+	case createPodsOpcode:
+		for i := 0; i < count; i++ {
+			pod := r.makePod(fmt.Sprintf("pod-%d", i), namespace, o.PodTemplate)
+			if err := r.createPod(ctx, pod, o.CollectMetrics); err != nil {
+				return err
+			}
+		}
+	case createPodGroupsOpcode:
+		for i := 0; i < count; i++ {
+			if err := r.createPodGroup(ctx, fmt.Sprintf("pg-%d", i), namespace, o); err != nil {
+				return err
+			}
+		}
+	case barrierOpcode:
+		return r.waitFor(ctx, "all pods to be bound", r.allPodsBound)
+	}
+	return nil
+}
+
+func (r *workloadRunner) waitFor(ctx context.Context, what string, condition wait.ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	if err := wait.PollImmediateUntil(100*time.Millisecond, condition, ctx.Done()); err != nil {
+		return fmt.Errorf("timed out waiting for %s", what)
+	}
+	return nil
+}
+
+func (r *workloadRunner) allPodsBound() (bool, error) {
+	pods, err := r.podLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if !podutil.BoundPod(pod) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *workloadRunner) createPriorityClasses(ctx context.Context) error {
+	for _, pc := range r.tc.PriorityClasses {
+		obj := &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{Name: pc.Name},
+			Value:      pc.Value,
+		}
+		if pc.Preemptible {
+			obj.Annotations = map[string]string{util.CanBePreemptedAnnotationKey: util.CanBePreempted}
+		}
+		if _, err := r.cluster.client.SchedulingV1().PriorityClasses().Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		r.priorities[pc.Name] = pc.Value
+	}
+	return nil
+}
+
+func (r *workloadRunner) createNode(ctx context.Context, tmpl *nodeTemplate) error {
+	name := fmt.Sprintf("node-%d", r.nodes)
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{v1.LabelHostname: name},
+		},
+		Status: v1.NodeStatus{
+			Capacity:   v1.ResourceList{v1.ResourcePods: resource.MustParse(defaultMaxPods)},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+	for k, v := range tmpl.Labels {
+		node.Labels[k] = v
+	}
+	if tmpl.Zones > 0 {
+		node.Labels[v1.LabelTopologyZone] = fmt.Sprintf("zone-%d", r.nodes%tmpl.Zones)
+	}
+	for name, quantity := range tmpl.Capacity {
+		node.Status.Capacity[name] = resource.MustParse(quantity)
+	}
+	node.Status.Allocatable = node.Status.Capacity.DeepCopy()
+	r.nodes++
+
+	if tmpl.NumaZones > 0 {
+		if _, err := r.cluster.katalystClient.NodeV1alpha1().CustomNodeResources().Create(ctx, makeCNR(node, tmpl.NumaZones), metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	_, err := r.cluster.client.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+	return err
+}
+
+// makeCNR returns the CNR of the node, whose cpu and memory are evenly divided among NUMA zones of a socket.
+func makeCNR(node *v1.Node, numaZones int) *katalystv1alpha1.CustomNodeResource {
+	cpu, memory := node.Status.Allocatable[v1.ResourceCPU], node.Status.Allocatable[v1.ResourceMemory]
+	socket := &katalystv1alpha1.TopologyZone{Type: katalystv1alpha1.TopologyTypeSocket, Name: "0"}
+	for i := 0; i < numaZones; i++ {
+		resources := v1.ResourceList{
+			v1.ResourceCPU:    *resource.NewMilliQuantity(cpu.MilliValue()/int64(numaZones), resource.DecimalSI),
+			v1.ResourceMemory: *resource.NewQuantity(memory.Value()/int64(numaZones), resource.BinarySI),
+		}
+		allocatable := resources.DeepCopy()
+		socket.Children = append(socket.Children, &katalystv1alpha1.TopologyZone{
+			Type:      katalystv1alpha1.TopologyTypeNuma,
+			Name:      fmt.Sprint(i),
+			Resources: katalystv1alpha1.Resources{Capacity: &resources, Allocatable: &allocatable},
+		})
+	}
+	return &katalystv1alpha1.CustomNodeResource{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Spec: katalystv1alpha1.CustomNodeResourceSpec{
+			NodeResourceProperties: []*katalystv1alpha1.Property{{
+				PropertyName:     util.ResourceNuma.String(),
+				PropertyQuantity: resource.NewQuantity(int64(numaZones), resource.DecimalSI),
+			}},
+		},
+		Status: katalystv1alpha1.CustomNodeResourceStatus{
+			TopologyZone: []*katalystv1alpha1.TopologyZone{socket},
+		},
+	}
+}
+
+func (r *workloadRunner) makePod(name, namespace string, tmpl *podTemplate) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      make(map[string]string),
+			Annotations: make(map[string]string),
+		},
+		Spec: v1.PodSpec{
+			SchedulerName:     testSchedulerName,
+			PriorityClassName: tmpl.PriorityClassName,
+			Containers: []v1.Container{{
+				Name:      "container",
+				Image:     "busybox",
+				Resources: v1.ResourceRequirements{Requests: make(v1.ResourceList), Limits: make(v1.ResourceList)},
+			}},
+		},
+	}
+	for k, v := range tmpl.Labels {
+		pod.Labels[k] = v
+	}
+	for k, v := range tmpl.Annotations {
+		pod.Annotations[k] = v
+	}
+	if len(pod.Annotations[util.QoSLevelKey]) == 0 && len(pod.Annotations[podutil.PodResourceTypeAnnotationKey]) == 0 {
+		pod.Annotations[podutil.PodResourceTypeAnnotationKey] = string(podutil.GuaranteedPod)
+	}
+	for name, quantity := range tmpl.Requests {
+		pod.Spec.Containers[0].Resources.Requests[name] = resource.MustParse(quantity)
+		pod.Spec.Containers[0].Resources.Limits[name] = resource.MustParse(quantity)
+	}
+	// Priority is set by the admission plugin of API server, which isn't there.
+	if priority, ok := r.priorities[tmpl.PriorityClassName]; ok {
+		pod.Spec.Priority = &priority
+	}
+	return pod
+}
+
+func (r *workloadRunner) createPod(ctx context.Context, pod *v1.Pod, measured bool) error {
+	if measured {
+		r.tracker.measure(podutil.GetPodKey(pod), time.Now())
+	}
+	_, err := r.cluster.client.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	return err
+}
+
+func (r *workloadRunner) createPodGroup(ctx context.Context, name, namespace string, o *op) error {
+	pg := &schedulingv1a1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: schedulingv1a1.PodGroupSpec{
+			MinMember:         int32(o.MinMember),
+			PriorityClassName: o.PodTemplate.PriorityClassName,
+		},
+	}
+	if len(o.AffinityTopologyKey) > 0 {
+		pg.Spec.Affinity = &schedulingv1a1.Affinity{
+			PodGroupAffinity: &schedulingv1a1.PodGroupAffinity{
+				Required: []schedulingv1a1.PodGroupAffinityTerm{{TopologyKey: o.AffinityTopologyKey}},
+			},
+		}
+	}
+	if _, err := r.cluster.crdClient.SchedulingV1alpha1().PodGroups(namespace).Create(ctx, pg, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	for i := 0; i < o.MinMember; i++ {
+		pod := r.makePod(fmt.Sprintf("%s-pod-%d", name, i), namespace, o.PodTemplate)
+		pod.Annotations[podutil.PodGroupNameAnnotationKey] = name
+		if err := r.createPod(ctx, pod, o.CollectMetrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}